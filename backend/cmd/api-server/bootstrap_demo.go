@@ -5,11 +5,11 @@ import (
 	"log"
 	"math/rand"
 	"strconv"
-	"strings"
 	"time"
-	"unicode"
 
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	"github.com/ngocan-dev/mangahub/backend/internal/config"
+	"github.com/ngocan-dev/mangahub/backend/internal/demo"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
 )
 
@@ -28,8 +28,31 @@ type demoMangaSeed struct {
 	Language string
 }
 
-func bootstrapDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc *chapterservice.Service) error {
-	seeds := []demoMangaSeed{
+// bootstrapDemoManga seeds the demo dataset unless it is already present.
+// The dataset comes from cfg.SeedFile when set, otherwise from the built-in
+// list with views drawn from an RNG seeded with cfg.RandSeed; cfg.MangaCount
+// caps its size either way.
+func bootstrapDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc *chapterservice.Service, cfg config.DemoConfig) error {
+	var seeds []demo.MangaSeed
+	if cfg.SeedFile != "" {
+		loaded, err := demo.LoadSeedFile(cfg.SeedFile)
+		if err != nil {
+			return err
+		}
+		seeds = loaded
+	} else {
+		seed := cfg.RandSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		seeds = builtinDemoSeeds(rand.New(rand.NewSource(seed)))
+	}
+	return seedDemoManga(ctx, mangaSvc, chapterSvc, demo.Limit(seeds, cfg.MangaCount))
+}
+
+// builtinDemoSeeds expands the built-in demo list into a dataset
+func builtinDemoSeeds(rng *rand.Rand) []demo.MangaSeed {
+	builtin := []demoMangaSeed{
 		{
 			Title:    "Kogarashi no Tsuki",
 			AltTitle: "Moon of Wandering Winds",
@@ -116,6 +139,42 @@ func bootstrapDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc
 		},
 	}
 
+	seeds := make([]demo.MangaSeed, 0, len(builtin))
+	for _, b := range builtin {
+		views := b.ViewsMin
+		if b.ViewsMax > b.ViewsMin {
+			views = b.ViewsMin + rng.Int63n(b.ViewsMax-b.ViewsMin+1)
+		}
+
+		chapters := make([]demo.ChapterSeed, 0, b.Chapters)
+		for ch := 1; ch <= b.Chapters; ch++ {
+			chapters = append(chapters, demo.ChapterSeed{
+				Number:  ch,
+				Title:   buildChapterTitle(ch),
+				Content: "This is the full content of chapter " + strconv.Itoa(ch) + " for " + b.Title + ".\n\nIt is auto-generated for demo purposes.",
+			})
+		}
+
+		seeds = append(seeds, demo.MangaSeed{
+			Title:    b.Title,
+			AltTitle: b.AltTitle,
+			Author:   b.Author,
+			Artist:   b.Artist,
+			Status:   b.Status,
+			Synopsis: b.Synopsis,
+			Genres:   b.Genres,
+			Rating:   b.Rating,
+			Views:    views,
+			Language: b.Language,
+			Chapters: chapters,
+		})
+	}
+	return seeds
+}
+
+// seedDemoManga creates the manga and chapters of a dataset. Nothing is
+// created when any of its titles already exists, so restarts do not reseed.
+func seedDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc *chapterservice.Service, seeds []demo.MangaSeed) error {
 	if len(seeds) == 0 {
 		return nil
 	}
@@ -130,29 +189,34 @@ func bootstrapDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc
 		}
 	}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-
 	for _, seed := range seeds {
-		slug := slugify(seed.Title)
-		views := seed.ViewsMin
-		if seed.ViewsMax > seed.ViewsMin {
-			views = seed.ViewsMin + rng.Int63n(seed.ViewsMax-seed.ViewsMin+1)
+		base := seed.Slug
+		if base == "" {
+			base = manga.Slugify(seed.Title)
+		}
+		slug, err := mangaSvc.UniqueSlug(ctx, base)
+		if err != nil {
+			return err
+		}
+		coverURL := seed.CoverURL
+		if coverURL == "" {
+			coverURL = "https://cdn.mangahub.demo/covers/" + slug + ".jpg"
 		}
 
 		req := manga.CreateMangaRequest{
 			Title:       seed.Title,
 			AltTitle:    seed.AltTitle,
 			Slug:        slug,
-			CoverURL:    "https://cdn.mangahub.demo/covers/" + slug + ".jpg",
+			CoverURL:    coverURL,
 			Author:      seed.Author,
 			Artist:      seed.Artist,
 			Status:      seed.Status,
 			Synopsis:    seed.Synopsis,
 			Genres:      seed.Genres,
 			Rating:      seed.Rating,
-			Views:       views,
+			Views:       seed.Views,
 			Language:    seed.Language,
-			LastChapter: seed.Chapters,
+			LastChapter: seed.LastChapter(),
 		}
 
 		mangaID, err := mangaSvc.CreateManga(ctx, req)
@@ -162,14 +226,12 @@ func bootstrapDemoManga(ctx context.Context, mangaSvc *manga.Service, chapterSvc
 		}
 		log.Printf("demo bootstrap: created manga [%d] %s", mangaID, seed.Title)
 
-		for ch := 1; ch <= seed.Chapters; ch++ {
-			chapterTitle := buildChapterTitle(ch)
-			content := "This is the full content of chapter " + strconv.Itoa(ch) + " for " + seed.Title + ".\n\nIt is auto-generated for demo purposes."
-			if _, err := chapterSvc.CreateChapter(ctx, mangaID, ch, chapterTitle, content, seed.Language); err != nil {
-				log.Printf("demo bootstrap: failed to create chapter %d for %s: %v", ch, seed.Title, err)
+		for _, ch := range seed.Chapters {
+			if _, err := chapterSvc.CreateChapter(ctx, mangaID, ch.Number, ch.Title, ch.Content, seed.Language); err != nil {
+				log.Printf("demo bootstrap: failed to create chapter %d for %s: %v", ch.Number, seed.Title, err)
 				continue
 			}
-			log.Printf("demo bootstrap: created chapter %d for %s", ch, seed.Title)
+			log.Printf("demo bootstrap: created chapter %d for %s", ch.Number, seed.Title)
 		}
 	}
 
@@ -184,23 +246,3 @@ func buildChapterTitle(number int) string {
 	}
 	return "Chapter " + strconv.Itoa(number) + ": " + fragments[idx]
 }
-
-func slugify(s string) string {
-	s = strings.ToLower(s)
-	var b strings.Builder
-	lastDash := false
-	for _, r := range s {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) {
-			b.WriteRune(r)
-			lastDash = false
-			continue
-		}
-		if r == ' ' || r == '_' || r == '-' {
-			if b.Len() > 0 && !lastDash {
-				b.WriteRune('-')
-				lastDash = true
-			}
-		}
-	}
-	return strings.Trim(b.String(), "-")
-}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	"github.com/ngocan-dev/mangahub/backend/internal/config"
+	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
+)
+
+const testSeedFile = `{
+  "manga": [
+    {
+      "title": "Paper Lantern Post",
+      "author": "Mori Aki",
+      "status": "ongoing",
+      "genres": ["Slice of Life"],
+      "rating": 4.1,
+      "views": 1200,
+      "language": "ja",
+      "chapters": [
+        {"number": 1, "title": "First Delivery", "content": "A letter arrives."},
+        {"number": 2, "title": "Rain Route", "content": "The ink runs."}
+      ]
+    },
+    {
+      "title": "Tidewatch",
+      "slug": "tidewatch-custom",
+      "status": "completed",
+      "views": 300,
+      "language": "en",
+      "chapters": [
+        {"number": 1, "title": "Low Tide", "content": "The harbor empties."}
+      ]
+    },
+    {
+      "title": "Left Out By The Count",
+      "chapters": []
+    }
+  ]
+}`
+
+func TestBootstrapDemoMangaFromSeedFile(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        slug TEXT NOT NULL UNIQUE,
+        title TEXT NOT NULL,
+        alt_title TEXT,
+        cover_url TEXT,
+        author TEXT,
+        artist TEXT,
+        status TEXT NOT NULL DEFAULT 'ongoing',
+        synopsis TEXT,
+        language TEXT DEFAULT 'ja',
+        rating_average REAL NOT NULL DEFAULT 0,
+        rating_count INTEGER NOT NULL DEFAULT 0,
+        views INTEGER NOT NULL DEFAULT 0,
+        last_chapter INTEGER,
+        last_chapter_at DATETIME
+    );
+    CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE);
+    CREATE TABLE manga_tags (manga_id INTEGER NOT NULL, tag_id INTEGER NOT NULL, PRIMARY KEY (manga_id, tag_id));
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        language TEXT NOT NULL DEFAULT 'ja',
+        content_text TEXT,
+        updated_at DATETIME,
+        UNIQUE (manga_id, number, language)
+    );
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "seed.json")
+	if err := os.WriteFile(path, []byte(testSeedFile), 0o644); err != nil {
+		t.Fatalf("failed to write seed file: %v", err)
+	}
+
+	mangaSvc := manga.NewService(db)
+	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(db))
+	cfg := config.DemoConfig{MangaCount: 2, SeedFile: path}
+	ctx := context.Background()
+	for run := 0; run < 2; run++ {
+		// The second run must notice the dataset and leave it alone
+		if err := bootstrapDemoManga(ctx, mangaSvc, chapterSvc, cfg); err != nil {
+			t.Fatalf("run %d: bootstrapDemoManga failed: %v", run, err)
+		}
+	}
+
+	rows, err := db.Query(`
+        SELECT m.slug, m.title, m.rating_count, m.last_chapter, c.number, c.title, c.content_text, c.language
+        FROM mangas m
+        JOIN chapters c ON c.manga_id = m.id
+        ORDER BY m.id, c.number`)
+	if err != nil {
+		t.Fatalf("failed to query seeded data: %v", err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		slug, title     string
+		views, last     int
+		number          int
+		chapter, body   string
+		chapterLanguage string
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.slug, &r.title, &r.views, &r.last, &r.number, &r.chapter, &r.body, &r.chapterLanguage); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		got = append(got, r)
+	}
+	want := []row{
+		{"paper-lantern-post", "Paper Lantern Post", 1200, 2, 1, "First Delivery", "A letter arrives.", "ja"},
+		{"paper-lantern-post", "Paper Lantern Post", 1200, 2, 2, "Rain Route", "The ink runs.", "ja"},
+		{"tidewatch-custom", "Tidewatch", 300, 1, 1, "Low Tide", "The harbor empties.", "en"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected seeded data:\n got %+v\nwant %+v", got, want)
+	}
+
+	var mangaCount int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM mangas`).Scan(&mangaCount); err != nil {
+		t.Fatalf("failed to count manga: %v", err)
+	}
+	if mangaCount != 2 {
+		t.Fatalf("expected DEMO_MANGA_COUNT to cap the dataset at 2 manga, got %d", mangaCount)
+	}
+}
+
+func TestBuiltinDemoSeedsAreReproducible(t *testing.T) {
+	first := builtinDemoSeeds(rand.New(rand.NewSource(42)))
+	second := builtinDemoSeeds(rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("expected the same RNG seed to generate the same dataset")
+	}
+}
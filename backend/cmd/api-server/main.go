@@ -16,15 +16,24 @@ import (
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
 	"github.com/ngocan-dev/mangahub/backend/domain/chat"
 	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/domain/notification"
+	"github.com/ngocan-dev/mangahub/backend/domain/room"
 	"github.com/ngocan-dev/mangahub/backend/domain/user"
 	"github.com/ngocan-dev/mangahub/backend/internal/auth"
 	"github.com/ngocan-dev/mangahub/backend/internal/cache"
 	"github.com/ngocan-dev/mangahub/backend/internal/config"
 	"github.com/ngocan-dev/mangahub/backend/internal/http/handlers"
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/metrics"
 	"github.com/ngocan-dev/mangahub/backend/internal/middleware"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	"github.com/ngocan-dev/mangahub/backend/internal/queue"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	"github.com/ngocan-dev/mangahub/backend/internal/security"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
+	libraryservice "github.com/ngocan-dev/mangahub/backend/internal/service/library"
 	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
 	"github.com/ngocan-dev/mangahub/backend/internal/udp"
 	ws "github.com/ngocan-dev/mangahub/backend/internal/websocket"
@@ -40,13 +49,14 @@ func startTCPServerWithRestart(
 	for {
 		log.Printf("Starting TCP server on %s (max clients: %d)", address, maxClients)
 
-		if err := server.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		err := server.Start(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, tcp.ErrServerClosed) {
 			log.Printf("TCP server stopped with error: %v", err)
 		} else {
 			log.Printf("TCP server stopped")
 		}
 
-		if ctx.Err() != nil {
+		if ctx.Err() != nil || errors.Is(err, tcp.ErrServerClosed) {
 			return
 		}
 
@@ -62,10 +72,18 @@ func startTCPServerWithRestart(
 func main() {
 	startTime := time.Now()
 
+	// JSON logs; the standard log package is routed through the same handler
+	logging.Setup()
+
 	// Root context for the whole process (graceful shutdown)
 	rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// The TCP and UDP servers outlive the signal so Shutdown can still reach
+	// their clients; this context only stops them once that is done
+	serversCtx, stopServers := context.WithCancel(context.Background())
+	defer stopServers()
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
@@ -76,9 +94,22 @@ func main() {
 
 	// Auth secret
 	auth.SetSecret(cfg.Auth.JWTSecret)
+	auth.SetAccessTokenTTL(cfg.Auth.AccessTokenTTL)
+	auth.SetIssuer(cfg.Auth.Issuer)
+	auth.SetAudience(cfg.Auth.Audience)
+	auth.SetRefreshTokenRotation(cfg.Auth.RefreshTokenRotation)
+
+	pagination.SetDefaults(pagination.Defaults{Limit: cfg.Pagination.DefaultLimit, MaxLimit: cfg.Pagination.MaxLimit})
+
+	securityCfg := security.DefaultConfig()
+	securityCfg.MaxMessageLength = cfg.Security.MaxMessageLength
+	securityCfg.MaxReviewLength = cfg.Security.MaxReviewLength
+	if err := security.Configure(securityCfg); err != nil {
+		log.Fatalf("configure security: %v", err)
+	}
 
-	// DB
-	db, err := dbpkg.Open(cfg.DB.Driver, cfg.DB.DSN, &dbpkg.PoolConfig{
+	// DB; DB_REPLICA_DSN optionally serves read-heavy queries
+	cluster, err := dbpkg.OpenCluster(cfg.DB.Driver, cfg.DB.DSN, cfg.DB.ReplicaDSN, &dbpkg.PoolConfig{
 		MaxOpenConns:    25,
 		MaxIdleConns:    5,
 		ConnMaxLifetime: 5 * time.Minute,
@@ -87,7 +118,11 @@ func main() {
 	if err != nil {
 		log.Fatalf("cannot open database: %v", err)
 	}
-	defer db.Close()
+	defer cluster.Close()
+	db := cluster.Primary()
+	if cluster.HasReplica() {
+		log.Println("DB read replica enabled")
+	}
 
 	// Apply migrations to keep the schema up to date (idempotent).
 	if err := dbpkg.RunMigrations(db, cfg.DB.MigrationsDir); err != nil {
@@ -96,23 +131,53 @@ func main() {
 	log.Println("database migrations applied")
 
 	// Gin
-	r := gin.Default()
-	r.Use(middleware.CORSMiddleware(cfg.App.AllowedOrigins))
-
-	// Rate limiter
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
+	r := gin.New()
+	r.Use(gin.Recovery())
+	// Tags requests with X-Request-ID and writes one access log line each
+	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.CORSMiddleware(middleware.CORSConfig{
+		AllowedOrigins:   cfg.App.AllowedOrigins,
+		AllowedMethods:   cfg.App.CORSAllowedMethods,
+		AllowedHeaders:   cfg.App.CORSAllowedHeaders,
+		ExposedHeaders:   cfg.App.CORSExposedHeaders,
+		AllowCredentials: cfg.App.CORSAllowCredentials,
+		MaxAge:           cfg.App.CORSMaxAge,
+	}))
+
+	// Prometheus metrics
+	appMetrics := metrics.New()
+	r.Use(appMetrics.Middleware())
+
+	// Rate limiter; credential endpoints get tighter per-route buckets and
+	// trusted roles a larger default budget (RATE_LIMIT_TIERS)
+	rateLimiter := middleware.NewRateLimiter(100, time.Minute).
+		WithRoute(http.MethodPost, "/login", 10, time.Minute).
+		WithRoute(http.MethodPost, "/register", 5, time.Minute).
+		WithRoute(http.MethodPost, "/auth/refresh", 20, time.Minute).
+		WithRoute(http.MethodPost, "/password/reset/request", 5, time.Minute).
+		WithRoute(http.MethodPost, "/password/reset/confirm", 10, time.Minute)
+	rateLimiter.SetIdentityFunc(handlers.RequestUserID)
+	for tier, perMinute := range cfg.App.RateLimitTiers {
+		rateLimiter.WithTier(tier, perMinute, time.Minute)
+	}
+	rateLimiter.SetTierFunc(handlers.RequestRole)
 	r.Use(rateLimiter.RateLimitMiddleware())
 
-	// Request timeout (adjust if too aggressive for your DB queries)
-	r.Use(func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 500*time.Millisecond)
-		defer cancel()
-		c.Request = c.Request.WithContext(ctx)
-		c.Next()
-	})
+	// Request timeout (REQUEST_TIMEOUT); heavy routes get longer budgets
+	// via REQUEST_TIMEOUT_OVERRIDES
+	r.Use(middleware.RequestTimeoutMiddleware(cfg.App.RequestTimeout, cfg.App.RouteTimeouts))
+
+	// Idempotency-Key support for POSTs that clients retry
+	idempotency := middleware.NewIdempotency(db, 24*time.Hour)
+	idempotency.SetIdentityFunc(handlers.RequestUserID)
 
 	// Handlers
 	userHandler := handlers.NewUserHandler(db)
+	userHandler.SetAvatarStore(user.NewAvatarStore(cfg.App.AvatarDir))
+	if cfg.Auth.PasswordResetSender == "log" {
+		log.Println("Warning: password reset links are written to the log; use only in development")
+		userHandler.SetPasswordResetSender(user.NewLogResetSender(cfg.Auth.PasswordResetURL))
+	}
 	authHandler := handlers.NewAuthHandler(db)
 
 	// Optional Redis cache
@@ -124,8 +189,29 @@ func main() {
 		log.Println("Redis cache connected successfully")
 		mangaCache = cache.NewMangaCache(redisClient)
 		defer redisClient.Close()
+		go mangaCache.StartReconnectProbe(rootCtx, 5*time.Second)
+	}
+
+	// Shared rate limits across API instances
+	if cfg.App.RateLimitBackend == "redis" {
+		if redisClient != nil {
+			rateLimiter.SetStore(middleware.NewRedisStore(redisClient))
+			log.Println("Rate limiting backed by Redis")
+		} else {
+			log.Println("Warning: RATE_LIMIT_BACKEND=redis but Redis is unavailable; using in-memory rate limits")
+		}
 	}
 
+	// Token blacklist (logout); Redis is optional and the DB is the fallback
+	var revocationStore *auth.RevocationStore
+	if redisClient != nil {
+		revocationStore = auth.NewRevocationStore(db, redisClient)
+	} else {
+		revocationStore = auth.NewRevocationStore(db, nil)
+	}
+	auth.SetRevocationStore(revocationStore)
+	go revocationStore.StartCleanup(rootCtx, time.Hour)
+
 	// DB health monitor
 	healthMonitor := dbpkg.NewHealthMonitor(db, 10*time.Second, 30*time.Second)
 	healthMonitor.Start()
@@ -133,6 +219,14 @@ func main() {
 
 	// Write queue
 	writeQueue := queue.NewWriteQueue(1000, 3, nil)
+	writeQueue.SetPersistence(cfg.App.WriteQueuePath)
+	// Reload writes left pending by a previous run before anything enqueues;
+	// the processor started below picks them up
+	if restored, err := writeQueue.Restore(); err != nil {
+		log.Printf("Warning: failed to restore write queue: %v", err)
+	} else if restored > 0 {
+		log.Printf("Restored %d queued write operations", restored)
+	}
 
 	// TCP progress sync server
 	tcpAddress := cfg.App.TCPServerAddr
@@ -140,15 +234,29 @@ func main() {
 		tcpAddress = ":9000"
 	}
 	tcpServer := tcp.NewServer(tcpAddress, 200, db)
+	tcpServer.SetMaxConnectionsPerUser(cfg.App.MaxConnectionsPerUser)
+	tcpServer.SetDeadlines(tcp.Deadlines{
+		Auth:  cfg.App.TCPAuthTimeout,
+		Read:  cfg.App.TCPReadTimeout,
+		Write: cfg.App.TCPWriteTimeout,
+	})
 
-	go startTCPServerWithRestart(rootCtx, tcpServer, tcpAddress, 200, 5*time.Second)
+	go startTCPServerWithRestart(serversCtx, tcpServer, tcpAddress, 200, 5*time.Second)
 
 	broadcaster := tcp.NewServerBroadcaster(tcpServer, writeQueue)
 
 	// Manga service + handlers
 	mangaService := handlers.GetMangaService(db, mangaCache)
 	mangaService.SetDBHealth(healthMonitor)
+	mangaService.SetCircuitBreaker(dbpkg.NewCircuitBreaker(5, 10*time.Second))
+	mangaService.SetReadReplica(cluster.Reader())
+	mangaService.SetRatingPrior(float64(cfg.App.RatingPrior))
 	mangaService.SetWriteQueue(writeQueue)
+	mangaService.SetCoverCache(cfg.App.CoverCacheDir)
+	if mangaCache != nil {
+		mangaService.SetViewBuffer(mangaCache)
+	}
+	go mangaService.StartViewFlusher(rootCtx, time.Minute)
 
 	chapterRepo := chapterrepository.NewRepository(db)
 	chapterSvc := chapterservice.NewService(chapterRepo)
@@ -157,7 +265,7 @@ func main() {
 	if cfg.EnableDemoData {
 		log.Println("⚠️ Loading DEMO data from SQLite")
 		bootstrapCtx, cancel := context.WithTimeout(rootCtx, 15*time.Second)
-		if err := bootstrapDemoManga(bootstrapCtx, mangaService, chapterSvc); err != nil {
+		if err := bootstrapDemoManga(bootstrapCtx, mangaService, chapterSvc, cfg.Demo); err != nil {
 			log.Printf("demo bootstrap failed: %v", err)
 		}
 		cancel()
@@ -180,23 +288,52 @@ func main() {
 
 	mangaHandler := handlers.NewMangaHandlerWithService(db, mangaService)
 	mangaHandler.SetBroadcaster(broadcaster)
+	mangaHandler.SetLibraryBroadcaster(broadcaster)
 	mangaHandler.SetDBHealth(healthMonitor)
 	mangaHandler.SetWriteQueue(writeQueue)
+	mangaHandler.SetReadReplica(cluster.Reader())
+
+	// Library collections
+	libraryService := libraryservice.NewService(libraryrepository.NewRepository(db), mangaService, nil)
 
+	// Chapter navigation advances reading progress for logged-in readers
+	chapterProgress := history.NewService(history.NewRepository(db), chapterSvc, libraryService, mangaService)
+	chapterProgress.SetBroadcaster(broadcaster)
+	chapterProgress.SetPopularityNotifier(mangaService)
 	chapterHandler := handlers.NewChapterHandler(db)
+	chapterHandler.SetProgressUpdater(chapterProgress)
+	chapterHandler.SetMangaInvalidator(mangaService)
+	collectionHandler := handlers.NewCollectionHandler(libraryService)
+
+	// Reading goals
+	goalService := history.NewService(history.NewRepository(db), chapterSvc, nil, mangaService)
+
+	// Reading statistics are recomputed in the background so reads hit the cache
+	statsWorker := history.NewStatsWorker(goalService, cfg.App.StatsRecomputeInterval)
+	goalService.SetStatsWorker(statsWorker)
+	chapterProgress.SetStatsWorker(statsWorker)
+	mangaHandler.SetStatsWorker(statsWorker)
+	go statsWorker.Run(rootCtx)
+	goalHandler := handlers.NewGoalHandler(goalService)
+	streakHandler := handlers.NewStreakHandler(goalService)
 
 	// Friend domain wiring
 	userRepo := user.NewRepository(db)
 	friendRepo := friend.NewRepository(db)
-	friendService := friend.NewService(friendRepo, userRepo, nil) // consider a Noop notifier instead of nil
+	// WebSocket chat; its presence connections also carry friend request events
+	chatHub := ws.NewDirectChatHub(db)
+	chatHub.SetAllowedOrigins(cfg.App.AllowedOrigins)
+	chatHandler := handlers.NewChatHandler(chatHub)
+
+	friendService := friend.NewService(friendRepo, userRepo, chatHub)
 	friendHandler := handlers.NewFriendHandler(friendService)
 
 	chatRepo := chat.NewRepository(db)
 	chatService := chat.NewService(chatRepo, friendRepo)
-	// WebSocket chat
-	chatHub := ws.NewDirectChatHub(db)
-	chatHandler := handlers.NewChatHandler(chatHub)
+	friendHandler.SetPresenceCheckers(chatHub, tcpServer)
+	userHandler.SetConnectionClosers(chatHub, tcpServer)
 	chatMessageHandler := handlers.NewChatMessageHandler(chatService)
+	roomHandler := handlers.NewRoomHandler(room.NewService(room.NewRepository(db)))
 
 	// UDP notification server
 	udpAddress := cfg.UDP.ServerAddr
@@ -207,27 +344,35 @@ func main() {
 	udpServerEnabled := !cfg.UDP.Disabled
 	udpMaxClients := cfg.UDP.MaxClients
 
-	var notificationHandler *handlers.NotificationHandler
 	var udpServer *udp.Server
 
+	// Chapter release notifications are always recorded for the notification
+	// history; the live UDP push only happens when the server is enabled
+	notificationService := notification.NewService(notification.NewRepository(db))
+	notificationService.SetMangaChecker(mangaService)
+
 	if udpServerEnabled {
 		udpServer = udp.NewServer(udpAddress, db)
 		udpServer.SetMaxClients(udpMaxClients)
+		udpServer.SetDeadlines(udp.Deadlines{Read: cfg.UDP.ReadTimeout, StaleAfter: cfg.UDP.StaleAfter})
 
 		go func() {
 			log.Printf("Starting UDP notification server on %s", udpAddress)
-			if err := udpServer.Start(rootCtx); err != nil && !errors.Is(err, context.Canceled) {
+			if err := udpServer.Start(serversCtx); err != nil && !errors.Is(err, context.Canceled) {
 				log.Printf("UDP server stopped: %v", err)
 			}
 		}()
-
-		notifier := udp.NewNotifier(udpServer)
-		notificationHandler = handlers.NewNotificationHandler(db, notifier)
 	} else {
-		log.Println("UDP notification server disabled; chapter notifications will be unavailable")
-		notificationHandler = handlers.NewNotificationHandler(db, nil)
+		log.Println("UDP notification server disabled; chapter notifications will only be recorded")
 	}
 
+	notifier := udp.NewNotifier(udpServer)
+	notifier.SetRecorder(notificationService)
+	goalService.SetGoalNotifiers(notificationService, notifier, chatHub)
+	chapterHandler.SetReleaseNotifier(notifier)
+	notificationHandler := handlers.NewNotificationHandler(db, notifier)
+	notificationHandler.SetNotificationService(notificationService)
+
 	wsAddress := cfg.App.WSServerAddr
 	if wsAddress == "" {
 		wsAddress = ":8081"
@@ -244,6 +389,22 @@ func main() {
 	}
 	statusHandler.SetAddresses(apiAddress, grpcAddress, tcpAddress, udpAddress)
 	statusHandler.SetWSAddress(wsAddress)
+	if mangaCache != nil {
+		statusHandler.SetCache(mangaCache)
+	}
+
+	queueHandler := handlers.NewQueueHandler(writeQueue)
+
+	appMetrics.SetTCPServer(tcpServer)
+	if udpServer != nil {
+		appMetrics.SetUDPServer(udpServer)
+	}
+	appMetrics.SetWebSocketHub(chatHub)
+	appMetrics.SetWriteQueue(writeQueue)
+	if mangaCache != nil {
+		appMetrics.SetCache(mangaCache)
+	}
+	appMetrics.SetDBHealth(healthMonitor)
 
 	syncHandler := handlers.NewSyncStatusHandler(db, healthMonitor, tcpServer, cfg.DB.DSN)
 
@@ -255,22 +416,49 @@ func main() {
 	r.POST("/register", userHandler.Register)
 
 	// Status/sync
+	r.GET("/healthz", statusHandler.Healthz)
+	r.GET("/readyz", statusHandler.Readyz)
 	r.GET("/server/status", statusHandler.GetStatus)
+	r.GET("/version", statusHandler.GetVersion)
 	r.GET("/sync/status", syncHandler.GetStatus)
+	r.GET("/sync/devices", authHandler.RequireAuth, syncHandler.ListDevices)
+	r.DELETE("/sync/devices/:id", authHandler.RequireAuth, syncHandler.RevokeDevice)
+	r.GET("/metrics", appMetrics.Handler())
 
 	// Login
 	r.POST("/login", authHandler.Login)
+	r.POST("/auth/refresh", authHandler.Refresh)
+	r.POST("/logout", authHandler.RequireAuth, authHandler.Logout)
 	r.GET("/me", authHandler.RequireAuth, authHandler.Me)
+	r.DELETE("/me", authHandler.RequireAuth, userHandler.DeleteAccount)
+	r.POST("/me/password", authHandler.RequireAuth, userHandler.ChangePassword)
+	r.POST("/me/avatar", authHandler.RequireAuth, userHandler.UploadAvatar)
+	r.GET("/me/privacy", authHandler.RequireAuth, userHandler.GetPrivacy)
+	r.PUT("/me/privacy", authHandler.RequireAuth, userHandler.UpdatePrivacy)
+	r.GET("/avatars/:id", userHandler.GetAvatar)
+	r.POST("/password/reset/request", userHandler.RequestPasswordReset)
+	r.POST("/password/reset/confirm", userHandler.ConfirmPasswordReset)
 
 	// Friend management
 	r.GET("/users/search", authHandler.RequireAuth, friendHandler.Search)
+	r.GET("/notifications", authHandler.RequireAuth, notificationHandler.ListNotifications)
+	r.POST("/notifications/read-all", authHandler.RequireAuth, notificationHandler.MarkAllNotificationsRead)
+	r.POST("/notifications/:id/read", authHandler.RequireAuth, notificationHandler.MarkNotificationRead)
+	r.GET("/subscriptions", authHandler.RequireAuth, notificationHandler.ListSubscriptions)
+
 	r.GET("/friends", authHandler.RequireAuth, friendHandler.ListFriends)
+	r.GET("/friends/online", authHandler.RequireAuth, friendHandler.OnlineFriends)
 	r.GET("/friends/requests", authHandler.RequireAuth, friendHandler.PendingRequests)
 	r.POST("/friends/request", authHandler.RequireAuth, friendHandler.SendRequest)
 	r.POST("/friends/accept", authHandler.RequireAuth, friendHandler.AcceptRequest)
 	r.POST("/friends/reject", authHandler.RequireAuth, friendHandler.RejectRequest)
 	r.POST("/friends/:id/accept", authHandler.RequireAuth, friendHandler.AcceptRequest)
 	r.POST("/friends/:id/reject", authHandler.RequireAuth, friendHandler.RejectRequest)
+	r.DELETE("/friends/:id", authHandler.RequireAuth, friendHandler.RemoveFriend)
+
+	// Blocking
+	r.POST("/users/:id/block", authHandler.RequireAuth, friendHandler.BlockUser)
+	r.DELETE("/users/:id/block", authHandler.RequireAuth, friendHandler.UnblockUser)
 
 	// Legacy paths (optional)
 	r.POST("/friends/requests", authHandler.RequireAuth, friendHandler.SendRequest)
@@ -282,31 +470,90 @@ func main() {
 	r.GET("/chat/conversations", authHandler.RequireAuth, chatMessageHandler.ListConversations)
 	r.GET("/chat/rooms/:roomID/messages", authHandler.RequireAuth, chatMessageHandler.ListMessages)
 	r.POST("/chat/messages", authHandler.RequireAuth, chatMessageHandler.SendMessage)
+	// Chat rooms
+	r.GET("/rooms", authHandler.RequireAuth, roomHandler.List)
+	r.POST("/rooms", authHandler.RequireAuth, roomHandler.Create)
+	r.POST("/rooms/:id/members", authHandler.RequireAuth, roomHandler.AddMember)
+	r.DELETE("/rooms/:id/members/:userId", authHandler.RequireAuth, roomHandler.RemoveMember)
 
 	// Manga
 	r.GET("/manga/popular", mangaHandler.GetPopularManga)
 	r.GET("/mangas/popular", mangaHandler.GetPopularManga)
+	r.GET("/mangas/trending", mangaHandler.GetTrending)
+	r.GET("/mangas/suggest", mangaHandler.Suggest)
+	r.GET("/tags", mangaHandler.ListTags)
+	r.GET("/tags/:name/mangas", mangaHandler.GetMangaByTag)
 
 	r.GET("/mangas/search", mangaHandler.Search)
 	r.GET("/mangas/:id", mangaHandler.GetDetails)
+	r.POST("/mangas/batch", mangaHandler.GetDetailsBatch)
+	r.GET("/mangas/:id/similar", mangaHandler.GetSimilarManga)
+	r.GET("/mangas/:id/feed.xml", mangaHandler.GetFeed)
+	r.GET("/covers/:id", mangaHandler.GetCover)
+	r.GET("/recommendations", authHandler.RequireAuth, mangaHandler.GetRecommendations)
 
 	r.GET("/library", authHandler.RequireAuth, mangaHandler.GetLibrary)
-	r.POST("/mangas/:id/library", authHandler.RequireAuth, mangaHandler.AddToLibrary)
+	r.GET("/library/export", authHandler.RequireAuth, mangaHandler.ExportLibrary)
+	r.POST("/library/import", authHandler.RequireAuth, mangaHandler.ImportLibrary)
+	r.POST("/mangas/:id/library", authHandler.RequireAuth, idempotency.Middleware(), mangaHandler.AddToLibrary)
+	r.DELETE("/mangas/:id/library", authHandler.RequireAuth, mangaHandler.RemoveFromLibrary)
+	r.POST("/mangas/:id/subscribe", authHandler.RequireAuth, notificationHandler.Subscribe)
+	r.DELETE("/mangas/:id/subscribe", authHandler.RequireAuth, notificationHandler.Unsubscribe)
+
+	r.GET("/collections", authHandler.RequireAuth, collectionHandler.List)
+	r.POST("/collections", authHandler.RequireAuth, collectionHandler.Create)
+	r.POST("/collections/:id/items", authHandler.RequireAuth, collectionHandler.AddItem)
+	r.DELETE("/collections/:id/items/:manga_id", authHandler.RequireAuth, collectionHandler.RemoveItem)
 
 	r.GET("/chapters/:id", chapterHandler.GetChapter)
+	r.GET("/chapters/:id/navigation", chapterHandler.GetNavigation)
 
 	r.PUT("/mangas/:id/progress", authHandler.RequireAuth, mangaHandler.UpdateProgress)
+	r.GET("/progress", authHandler.RequireAuth, mangaHandler.GetProgressBatch)
+	r.PUT("/progress/batch", authHandler.RequireAuth, mangaHandler.BatchUpdateProgress)
+	r.POST("/reading/sessions/start", authHandler.RequireAuth, mangaHandler.StartReadingSession)
+	r.POST("/reading/sessions/end", authHandler.RequireAuth, mangaHandler.EndReadingSession)
 
-	r.POST("/mangas/:id/reviews", authHandler.RequireAuth, mangaHandler.CreateReview)
+	r.POST("/mangas/:id/reviews", authHandler.RequireAuth, idempotency.Middleware(), mangaHandler.CreateReview)
 	r.GET("/mangas/:id/reviews", mangaHandler.GetReviews)
+	r.PUT("/reviews/:id", authHandler.RequireAuth, mangaHandler.UpdateReview)
+	r.DELETE("/reviews/:id", authHandler.RequireAuth, mangaHandler.DeleteReview)
+	r.POST("/reviews/:id/flag", authHandler.RequireAuth, mangaHandler.FlagReview)
+	r.POST("/reviews/:id/vote", authHandler.RequireAuth, mangaHandler.VoteReview)
 
-	// r.GET("/friends/activity", authHandler.RequireAuth, mangaHandler.GetFriendsActivityFeed)
+	r.GET("/friends/activity", authHandler.RequireAuth, mangaHandler.GetFriendsActivityFeed)
 
 	r.GET("/statistics/reading", authHandler.RequireAuth, mangaHandler.GetReadingStatistics)
 	r.GET("/analytics/reading", authHandler.RequireAuth, mangaHandler.GetReadingAnalytics)
+	r.GET("/analytics/reading/export", authHandler.RequireAuth, mangaHandler.ExportReadingAnalytics)
+
+	r.POST("/goals", authHandler.RequireAuth, goalHandler.Create)
+	r.GET("/goals", authHandler.RequireAuth, goalHandler.List)
+	r.PUT("/goals/:id", authHandler.RequireAuth, goalHandler.Update)
+	r.DELETE("/goals/:id", authHandler.RequireAuth, goalHandler.Delete)
+	r.POST("/streaks/freeze", authHandler.RequireAuth, streakHandler.Freeze)
+
+	// Admin routes: every /admin/* endpoint requires the admin role
+	admin := r.Group("/admin", authHandler.RequireAuth, middleware.RequireRole(auth.RoleAdmin))
 
 	// Admin notify
-	r.POST("/admin/notify", authHandler.RequireAuth, notificationHandler.NotifyChapterRelease)
+	admin.POST("/notify", notificationHandler.NotifyChapterRelease)
+
+	// Chapter publishing
+	admin.POST("/mangas/:id/chapters", chapterHandler.CreateChapter)
+	admin.PUT("/chapters/:id", chapterHandler.UpdateChapter)
+
+	// Admin write queue
+	admin.GET("/queue/deadletters", queueHandler.ListDeadLetters)
+	admin.POST("/queue/deadletters/:id/retry", queueHandler.RetryDeadLetter)
+
+	// Review moderation
+	admin.POST("/reviews/:id/hide", mangaHandler.HideReview)
+	admin.DELETE("/reviews/:id/hide", mangaHandler.UnhideReview)
+
+	// Manga retirement
+	admin.DELETE("/mangas/:id", mangaHandler.DeleteManga)
+	admin.POST("/mangas/:id/restore", mangaHandler.RestoreManga)
 
 	// --------------------
 	// HTTP server (graceful shutdown)
@@ -335,4 +582,22 @@ func main() {
 	} else {
 		log.Println("HTTP server stopped")
 	}
+
+	if err := mangaService.FlushViews(shutdownCtx); err != nil {
+		log.Printf("View flush error: %v", err)
+	}
+
+	if err := tcpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("TCP shutdown error: %v", err)
+	} else {
+		log.Println("TCP server stopped, clients notified")
+	}
+
+	if udpServer != nil {
+		if err := udpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("UDP shutdown error: %v", err)
+		} else {
+			log.Println("UDP server stopped, clients notified")
+		}
+	}
 }
@@ -16,8 +16,10 @@ import (
 	_ "modernc.org/sqlite"
 
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
 	"github.com/ngocan-dev/mangahub/backend/internal/config"
 	grpcserver "github.com/ngocan-dev/mangahub/backend/internal/grpc"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	"github.com/ngocan-dev/mangahub/backend/internal/queue"
 	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
 	pb "github.com/ngocan-dev/mangahub/backend/proto/manga"
@@ -63,6 +65,10 @@ func main() {
 	}
 	defer db.Close()
 
+	// Reject logged-out, revoked and deleted-account tokens the same way the
+	// API server does; the Revoked_Tokens table is the source of truth
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+
 	// Initialize TCP broadcaster for real-time sync
 	tcpAddress := cfg.App.TCPServerAddr
 	if tcpAddress == "" {
@@ -76,12 +82,21 @@ func main() {
 	writeQueue := queue.NewWriteQueue(1000, 3, nil)
 	broadcaster := tcp.NewServerBroadcaster(tcpServer, writeQueue)
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	// Create gRPC server; calls need a bearer token unless the method is public
+	auth.SetSecret(cfg.Auth.JWTSecret)
+	auth.SetIssuer(cfg.Auth.Issuer)
+	auth.SetAudience(cfg.Auth.Audience)
+	pagination.SetDefaults(pagination.Defaults{Limit: cfg.Pagination.DefaultLimit, MaxLimit: cfg.Pagination.MaxLimit})
+	authInterceptor := grpcserver.NewAuthInterceptor(cfg.GRPC.PublicMethods)
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authInterceptor.Unary()),
+		grpc.StreamInterceptor(authInterceptor.Stream()),
+	)
 
 	// Register manga service
 	mangaServer := grpcserver.NewServer(db)
 	mangaServer.SetBroadcaster(broadcaster)
+	mangaServer.SetProgressSource(tcpServer)
 	pb.RegisterMangaServiceServer(grpcServer, mangaServer)
 
 	// Create listener
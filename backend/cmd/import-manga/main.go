@@ -8,44 +8,32 @@ import (
 	"math/rand"
 	"strings"
 	"time"
-	"unicode"
 
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	"github.com/ngocan-dev/mangahub/backend/internal/cache"
 	"github.com/ngocan-dev/mangahub/backend/internal/config"
+	"github.com/ngocan-dev/mangahub/backend/internal/demo"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
 )
 
-type MangaSeed struct {
-	Title       string
-	AltTitle    string
-	Author      string
-	Artist      string
-	Genres      []string
-	Status      string
-	Description string
-	Rating      float64
-	Views       int64
-	CoverURL    string
-	Slug        string
-}
-
-type ChapterSeed struct {
-	Number      int
-	Title       string
-	ContentText string
-	Language    string
-}
+// defaultImportCount is how many manga are generated without DEMO_MANGA_COUNT
+const defaultImportCount = 45
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
-
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
+	// A fixed DEMO_RAND_SEED reproduces the same generated dataset
+	randSeed := cfg.Demo.RandSeed
+	if randSeed == 0 {
+		randSeed = time.Now().UnixNano()
+	}
+	rand.Seed(randSeed)
+
 	db, err := dbpkg.Open(cfg.DB.Driver, cfg.DB.DSN, nil)
 	if err != nil {
 		log.Fatalf("cannot open database: %v", err)
@@ -57,8 +45,30 @@ func main() {
 	chapterSvc := chapterservice.NewService(chapterRepo)
 	mangaService.SetChapterService(chapterSvc)
 
+	// Imports go through CreateManga, which busts cached search results and
+	// popular lists when the API server's Redis cache is reachable
+	if redisClient, err := cache.NewClient(cfg.App.RedisAddr, cfg.App.RedisPassword, cfg.App.RedisDB); err != nil {
+		log.Printf("Redis cache not available, cached search results will expire on their own: %v", err)
+	} else {
+		defer redisClient.Close()
+		mangaService.SetCache(cache.NewMangaCache(redisClient))
+	}
+
 	ctx := context.Background()
-	seeds := generateMangaSeeds(45)
+	var seeds []demo.MangaSeed
+	if cfg.Demo.SeedFile != "" {
+		seeds, err = demo.LoadSeedFile(cfg.Demo.SeedFile)
+		if err != nil {
+			log.Fatalf("failed to load seed file: %v", err)
+		}
+		seeds = demo.Limit(seeds, cfg.Demo.MangaCount)
+	} else {
+		count := cfg.Demo.MangaCount
+		if count == 0 {
+			count = defaultImportCount
+		}
+		seeds = generateMangaSeeds(count)
+	}
 
 	for _, seed := range seeds {
 		existing, err := mangaService.GetByTitle(ctx, seed.Title)
@@ -71,21 +81,38 @@ func main() {
 			continue
 		}
 
-		chapterSeeds := generateChapters(seed)
+		// Different titles can slugify alike; suffix the slug rather than
+		// fail the unique constraint
+		base := seed.Slug
+		if base == "" {
+			base = manga.Slugify(seed.Title)
+		}
+		slug, err := mangaService.UniqueSlug(ctx, base)
+		if err != nil {
+			log.Printf("skip %s due to slug lookup error: %v", seed.Title, err)
+			continue
+		}
+		if slug != base {
+			log.Printf("slug %s is taken, using %s for %s", base, slug, seed.Title)
+		}
+		coverURL := seed.CoverURL
+		if coverURL == "" {
+			coverURL = fmt.Sprintf("https://cdn.mangahub.fake/covers/%s.jpg", slug)
+		}
 		req := manga.CreateMangaRequest{
 			Title:       seed.Title,
 			AltTitle:    seed.AltTitle,
-			Slug:        seed.Slug,
-			CoverURL:    seed.CoverURL,
+			Slug:        slug,
+			CoverURL:    coverURL,
 			Author:      seed.Author,
 			Artist:      seed.Artist,
 			Status:      seed.Status,
-			Synopsis:    seed.Description,
+			Synopsis:    seed.Synopsis,
 			Genres:      seed.Genres,
 			Rating:      seed.Rating,
 			Views:       seed.Views,
-			Language:    "ja",
-			LastChapter: len(chapterSeeds),
+			Language:    seed.Language,
+			LastChapter: seed.LastChapter(),
 		}
 
 		mangaID, err := mangaService.CreateManga(ctx, req)
@@ -95,8 +122,8 @@ func main() {
 		}
 		log.Printf("created manga [%d]: %s", mangaID, seed.Title)
 
-		for _, ch := range chapterSeeds {
-			if _, err := chapterSvc.CreateChapter(ctx, mangaID, ch.Number, ch.Title, ch.ContentText, ch.Language); err != nil {
+		for _, ch := range seed.Chapters {
+			if _, err := chapterSvc.CreateChapter(ctx, mangaID, ch.Number, ch.Title, ch.Content, seed.Language); err != nil {
 				log.Printf("failed to create chapter %d for %s: %v", ch.Number, seed.Title, err)
 				continue
 			}
@@ -105,9 +132,9 @@ func main() {
 	}
 }
 
-func generateMangaSeeds(count int) []MangaSeed {
+func generateMangaSeeds(count int) []demo.MangaSeed {
 	titles := make(map[string]struct{})
-	seeds := make([]MangaSeed, 0, count)
+	seeds := make([]demo.MangaSeed, 0, count)
 
 	for len(seeds) < count {
 		title := randomTitle()
@@ -116,7 +143,7 @@ func generateMangaSeeds(count int) []MangaSeed {
 		}
 		titles[title] = struct{}{}
 
-		slug := slugify(title)
+		slug := manga.Slugify(title)
 		alt := randomAltTitle(title)
 		genres := randomGenres()
 		status := randomStatus()
@@ -127,34 +154,35 @@ func generateMangaSeeds(count int) []MangaSeed {
 		desc := randomDescription(genres, status)
 		coverURL := fmt.Sprintf("https://cdn.mangahub.fake/covers/%s.jpg", slug)
 
-		seeds = append(seeds, MangaSeed{
-			Title:       title,
-			AltTitle:    alt,
-			Author:      author,
-			Artist:      artist,
-			Genres:      genres,
-			Status:      status,
-			Description: desc,
-			Rating:      rating,
-			Views:       views,
-			CoverURL:    coverURL,
-			Slug:        slug,
+		seeds = append(seeds, demo.MangaSeed{
+			Title:    title,
+			AltTitle: alt,
+			Author:   author,
+			Artist:   artist,
+			Genres:   genres,
+			Status:   status,
+			Synopsis: desc,
+			Rating:   rating,
+			Views:    views,
+			CoverURL: coverURL,
+			Slug:     slug,
+			Language: "ja",
+			Chapters: generateChapters(title),
 		})
 	}
 
 	return seeds
 }
 
-func generateChapters(seed MangaSeed) []ChapterSeed {
+func generateChapters(title string) []demo.ChapterSeed {
 	total := rand.Intn(16) + 5 // 5–20 chapters
-	chapters := make([]ChapterSeed, 0, total)
+	chapters := make([]demo.ChapterSeed, 0, total)
 
 	for i := 1; i <= total; i++ {
-		chapters = append(chapters, ChapterSeed{
-			Number:      i,
-			Title:       fmt.Sprintf("Chapter %d: %s", i, randomChapterTitle()),
-			ContentText: fmt.Sprintf("Chapter %d content for %s.\n\nThis is placeholder demo text generated during import.", i, seed.Title),
-			Language:    "ja",
+		chapters = append(chapters, demo.ChapterSeed{
+			Number:  i,
+			Title:   fmt.Sprintf("Chapter %d: %s", i, randomChapterTitle()),
+			Content: fmt.Sprintf("Chapter %d content for %s.\n\nThis is placeholder demo text generated during import.", i, title),
 		})
 	}
 
@@ -245,23 +273,3 @@ func randomChapterTitle() string {
 	fragments := []string{"Moonlit Market", "Silent Citadel", "Broken Oath", "Hidden Shrine", "Frozen River", "Ember Festival", "Thunder Path", "Emerald Gate", "Glass Library", "Azure Requiem", "Shadow Banquet", "Jade Courtyard"}
 	return fragments[rand.Intn(len(fragments))]
 }
-
-func slugify(s string) string {
-	s = strings.ToLower(s)
-	var b strings.Builder
-	lastDash := false
-	for _, r := range s {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) {
-			b.WriteRune(r)
-			lastDash = false
-			continue
-		}
-		if r == ' ' || r == '_' || r == '-' {
-			if b.Len() > 0 && !lastDash {
-				b.WriteRune('-')
-				lastDash = true
-			}
-		}
-	}
-	return strings.Trim(b.String(), "-")
-}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"flag"
 	"log"
-	"os"
+	"strconv"
 
 	_ "modernc.org/sqlite"
 
@@ -12,8 +13,28 @@ import (
 
 func main() {
 	// Support optional "up" subcommand while remaining runnable as `go run ./cmd/migrate`.
-	if len(os.Args) > 1 && os.Args[1] != "up" {
-		log.Fatalf("unknown command %q (only optional \"up\" is supported)", os.Args[1])
+	// `down [steps]` rolls back the most recent migrations, one step by default.
+	force := flag.Bool("force", false, "accept applied migrations whose files were edited and record their new checksums")
+	flag.Parse()
+	args := flag.Args()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	steps := 1
+	switch command {
+	case "up":
+	case "down":
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("invalid steps %q: must be a positive integer", args[1])
+			}
+			steps = n
+		}
+	default:
+		log.Fatalf("unknown command %q (supported: up, down [steps])", command)
 	}
 
 	// Loading config ensures the migrations directory comes from a single source of truth,
@@ -33,7 +54,15 @@ func main() {
 	}
 	defer db.Close()
 
-	if err := dbpkg.RunMigrations(db, cfg.DB.MigrationsDir); err != nil {
+	if command == "down" {
+		if err := dbpkg.RollbackMigration(db, cfg.DB.MigrationsDir, steps); err != nil {
+			log.Fatalf("rollback migrations: %v", err)
+		}
+		log.Printf("rolled back %d migration(s)", steps)
+		return
+	}
+
+	if err := dbpkg.RunMigrationsWithOptions(db, cfg.DB.MigrationsDir, dbpkg.MigrationOptions{Force: *force}); err != nil {
 		log.Fatalf("run migrations: %v", err)
 	}
 
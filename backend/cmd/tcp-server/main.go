@@ -11,6 +11,8 @@ import (
 	_ "modernc.org/sqlite"
 
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+	"github.com/ngocan-dev/mangahub/backend/internal/config"
 	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
 )
 
@@ -21,6 +23,16 @@ func main() {
 	dbPath := flag.String("db", "file:data/mangahub.db?_foreign_keys=on", "Database connection string")
 	flag.Parse()
 
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	// Tokens are issued by the API server, so validate them the same way
+	auth.SetSecret(cfg.Auth.JWTSecret)
+	auth.SetIssuer(cfg.Auth.Issuer)
+	auth.SetAudience(cfg.Auth.Audience)
+
 	// Open database connection
 	db, err := dbpkg.OpenSQLite(*dbPath, nil)
 	if err != nil {
@@ -28,6 +40,10 @@ func main() {
 	}
 	defer db.Close()
 
+	// Reject logged-out, revoked and deleted-account tokens the same way the
+	// API server does; the Revoked_Tokens table is the source of truth
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+
 	// Create TCP server
 	server := tcp.NewServer(*address, *maxClients, db)
 
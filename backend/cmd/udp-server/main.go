@@ -11,6 +11,7 @@ import (
 	_ "modernc.org/sqlite"
 
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
 	"github.com/ngocan-dev/mangahub/backend/internal/config"
 	"github.com/ngocan-dev/mangahub/backend/internal/udp"
 )
@@ -27,6 +28,11 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
+	// Tokens are issued by the API server, so validate them the same way
+	auth.SetSecret(cfg.Auth.JWTSecret)
+	auth.SetIssuer(cfg.Auth.Issuer)
+	auth.SetAudience(cfg.Auth.Audience)
+
 	// Open database connection
 	db, err := dbpkg.OpenSQLite(*dbPath, nil)
 	if err != nil {
@@ -34,6 +40,10 @@ func main() {
 	}
 	defer db.Close()
 
+	// Reject logged-out, revoked and deleted-account tokens the same way the
+	// API server does; the Revoked_Tokens table is the source of truth
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+
 	// Create UDP server
 	server := udp.NewServer(*address, db)
 	server.SetMaxClients(*maxClients)
@@ -41,6 +51,7 @@ func main() {
 	if cfg.UDP.MaxClientsFromEnv {
 		server.SetMaxClients(cfg.UDP.MaxClients)
 	}
+	server.SetDeadlines(udp.Deadlines{Read: cfg.UDP.ReadTimeout, StaleAfter: cfg.UDP.StaleAfter})
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
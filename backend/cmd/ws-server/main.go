@@ -2,24 +2,47 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/gin-gonic/gin"
 	_ "modernc.org/sqlite"
 
 	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+	"github.com/ngocan-dev/mangahub/backend/internal/config"
+	"github.com/ngocan-dev/mangahub/backend/internal/http/handlers"
 	"github.com/ngocan-dev/mangahub/backend/internal/websocket"
 )
 
 func main() {
-	// Parse command line flags
-	address := flag.String("address", ":8081", "WebSocket server address")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	// Tokens are issued by the API server, so validate them the same way
+	auth.SetSecret(cfg.Auth.JWTSecret)
+	auth.SetIssuer(cfg.Auth.Issuer)
+	auth.SetAudience(cfg.Auth.Audience)
+
+	defaultAddress := cfg.App.WSServerAddr
+	if defaultAddress == "" {
+		defaultAddress = ":8081"
+	}
+
+	// Parse command line flags; defaults come from the shared configuration
+	address := flag.String("address", defaultAddress, "WebSocket server address")
 	dbPath := flag.String("db", "file:data/mangahub.db?_foreign_keys=on", "Database connection string")
+	allowedOrigins := flag.String("allowed-origins", strings.Join(cfg.App.AllowedOrigins, ","), "Comma-separated browser origins allowed to connect")
+	maxConnsPerUser := flag.Int("max-conns-per-user", cfg.App.MaxConnectionsPerUser, "Connections one user may hold before the oldest is evicted (0 = unlimited)")
+	pingInterval := flag.Duration("ping-interval", websocket.DefaultPingInterval, "How often clients are pinged")
+	pongTimeout := flag.Duration("pong-timeout", websocket.DefaultPongTimeout, "How long a client may go without answering a ping before it is dropped")
 	flag.Parse()
 
 	// Open database connection
@@ -29,8 +52,15 @@ func main() {
 	}
 	defer db.Close()
 
+	// Reject logged-out, revoked and deleted-account tokens the same way the
+	// API server does; the Revoked_Tokens table is the source of truth
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+
 	// Create hub
 	hub := websocket.NewHub(db)
+	hub.SetMaxConnectionsPerUser(*maxConnsPerUser)
+	hub.SetHeartbeat(*pingInterval, *pongTimeout)
+	hub.SetAllowedOrigins(strings.Split(*allowedOrigins, ","))
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -40,14 +70,14 @@ func main() {
 	go hub.Run(ctx)
 
 	// Setup HTTP routes
-	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		websocket.ServeWS(hub, w, r)
-	})
+	chatHandler := handlers.NewChatHandler(nil)
+	chatHandler.SetRoomHub(hub)
 
-	http.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
-		status := hub.Status()
-		writeStatus(w, status)
-	})
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/ws", chatHandler.ServeRoom)
+	// Polled by the API server's status page
+	r.GET("/status", chatHandler.Status)
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -55,7 +85,8 @@ func main() {
 
 	// Start server in goroutine
 	server := &http.Server{
-		Addr: *address,
+		Addr:    *address,
+		Handler: r,
 	}
 
 	serverErrChan := make(chan error, 1)
@@ -78,10 +109,3 @@ func main() {
 
 	log.Println("WebSocket server stopped")
 }
-
-func writeStatus(w http.ResponseWriter, status websocket.HubStatus) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(status); err != nil {
-		http.Error(w, "unable to encode status", http.StatusInternalServerError)
-	}
-}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDatabaseUnavailable is returned while the circuit breaker is open
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+
+// BreakerState is the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every query through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects queries until the cooldown has passed
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe query through to test recovery
+	BreakerHalfOpen
+)
+
+// String returns the state name used in logs and status output
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops queries from piling up on a failing database. After
+// threshold consecutive failures it opens and rejects queries for the
+// cooldown, then lets one probe through; a successful probe closes it again
+// and a failed one reopens it.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probeAt   time.Time
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a query may run. It returns ErrDatabaseUnavailable
+// while the breaker is open or a half-open probe is already in flight.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return ErrDatabaseUnavailable
+		}
+		b.state = BreakerHalfOpen
+		b.probeAt = now
+		return nil
+	case BreakerHalfOpen:
+		// A probe that never reported back must not wedge the breaker
+		if now.Sub(b.probeAt) < b.cooldown {
+			return ErrDatabaseUnavailable
+		}
+		b.probeAt = now
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of a query let through by Allow. A nil error
+// counts as success; cancellations by the caller are ignored.
+func (b *CircuitBreaker) Record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b := NewCircuitBreaker(3, time.Minute)
+	failure := errors.New("database is locked")
+
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("expected query %d to be allowed, got %v", i, err)
+		}
+		b.Record(failure)
+	}
+	// A success resets the consecutive failure count
+	b.Record(nil)
+	for i := 0; i < 3; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("expected query to be allowed before the threshold, got %v", err)
+		}
+		b.Record(failure)
+	}
+
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected breaker to be open, got %s", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("expected ErrDatabaseUnavailable while open, got %v", err)
+	}
+}
+
+func TestCircuitBreakerRecoversThroughHalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(1, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	b.Record(errors.New("connection refused"))
+	if err := b.Allow(); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("expected breaker to reject during cooldown, got %v", err)
+	}
+
+	// A failed probe reopens the breaker for another cooldown
+	now = now.Add(11 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a probe after the cooldown, got %v", err)
+	}
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open, got %s", b.State())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("expected only one probe while half-open, got %v", err)
+	}
+	b.Record(errors.New("connection refused"))
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected failed probe to reopen the breaker, got %s", b.State())
+	}
+
+	now = now.Add(11 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected a second probe after the cooldown, got %v", err)
+	}
+	b.Record(nil)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected successful probe to close the breaker, got %s", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected queries to flow once closed, got %v", err)
+	}
+}
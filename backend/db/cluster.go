@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Cluster pairs the primary database with an optional read replica. Writes
+// and reads that must see them go to Primary; read-heavy queries that can
+// tolerate replication lag go to Reader, which falls back to the primary when
+// no replica is configured.
+type Cluster struct {
+	primary *sql.DB
+	replica *sql.DB
+}
+
+// NewCluster wraps existing handles; replica may be nil
+func NewCluster(primary, replica *sql.DB) *Cluster {
+	return &Cluster{primary: primary, replica: replica}
+}
+
+// OpenCluster opens the primary and, when replicaDSN is set, a replica with
+// the same driver and pool settings
+func OpenCluster(driver, dsn, replicaDSN string, cfg *PoolConfig) (*Cluster, error) {
+	primary, err := Open(driver, dsn, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if replicaDSN == "" {
+		return NewCluster(primary, nil), nil
+	}
+
+	replica, err := Open(driver, replicaDSN, cfg)
+	if err != nil {
+		primary.Close()
+		return nil, fmt.Errorf("open replica: %w", err)
+	}
+	return NewCluster(primary, replica), nil
+}
+
+// Primary returns the handle for writes
+func (c *Cluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Reader returns the handle for read-only queries
+func (c *Cluster) Reader() *sql.DB {
+	if c.replica != nil {
+		return c.replica
+	}
+	return c.primary
+}
+
+// HasReplica reports whether reads are served by a separate replica
+func (c *Cluster) HasReplica() bool {
+	return c.replica != nil
+}
+
+// Close closes the primary and the replica
+func (c *Cluster) Close() error {
+	var errs []error
+	if c.replica != nil {
+		errs = append(errs, c.replica.Close())
+	}
+	errs = append(errs, c.primary.Close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestClusterRoutesReadsToReplica(t *testing.T) {
+	dir := t.TempDir()
+	cluster, err := OpenCluster("sqlite", filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db"), nil)
+	if err != nil {
+		t.Fatalf("OpenCluster returned error: %v", err)
+	}
+	defer cluster.Close()
+
+	if _, err := cluster.Primary().Exec(`CREATE TABLE node (name TEXT); INSERT INTO node VALUES ('primary')`); err != nil {
+		t.Fatalf("failed to seed primary: %v", err)
+	}
+	if _, err := cluster.Reader().Exec(`CREATE TABLE node (name TEXT); INSERT INTO node VALUES ('replica')`); err != nil {
+		t.Fatalf("failed to seed replica: %v", err)
+	}
+
+	if !cluster.HasReplica() {
+		t.Fatalf("expected a replica to be configured")
+	}
+	var name string
+	if err := cluster.Reader().QueryRow(`SELECT name FROM node`).Scan(&name); err != nil || name != "replica" {
+		t.Fatalf("expected reads to hit the replica, got %q (err=%v)", name, err)
+	}
+	if err := cluster.Primary().QueryRow(`SELECT name FROM node`).Scan(&name); err != nil || name != "primary" {
+		t.Fatalf("expected writes handle to be the primary, got %q (err=%v)", name, err)
+	}
+}
+
+func TestClusterFallsBackToPrimaryWithoutReplica(t *testing.T) {
+	cluster, err := OpenCluster("sqlite", filepath.Join(t.TempDir(), "primary.db"), "", nil)
+	if err != nil {
+		t.Fatalf("OpenCluster returned error: %v", err)
+	}
+	defer cluster.Close()
+
+	if cluster.HasReplica() || cluster.Reader() != cluster.Primary() {
+		t.Fatalf("expected reads to fall back to the primary")
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS Refresh_Tokens (
+    Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Token_Hash TEXT NOT NULL UNIQUE,
+    Expires_At DATETIME NOT NULL,
+    Revoked INTEGER NOT NULL DEFAULT 0,
+    Revoked_At DATETIME,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON Refresh_Tokens(User_Id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS Revoked_Tokens (
+    Jti TEXT PRIMARY KEY,
+    User_Id INTEGER NOT NULL,
+    Expires_At DATETIME NOT NULL,
+    Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON Revoked_Tokens(Expires_At);
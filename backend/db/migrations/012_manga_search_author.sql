@@ -0,0 +1,38 @@
+-- Rebuild the manga_search FTS5 index so it also covers author names.
+DROP TRIGGER IF EXISTS mangas_ai_fts;
+DROP TRIGGER IF EXISTS mangas_au_fts;
+DROP TRIGGER IF EXISTS mangas_ad_fts;
+DROP TABLE IF EXISTS manga_search;
+
+CREATE VIRTUAL TABLE manga_search USING fts5(
+    title,
+    author,
+    synopsis,
+    content='mangas',
+    content_rowid='id'
+);
+
+CREATE TRIGGER mangas_ai_fts
+AFTER INSERT ON mangas
+BEGIN
+    INSERT INTO manga_search(rowid, title, author, synopsis)
+    VALUES (new.id, new.title, new.author, new.synopsis);
+END;
+
+CREATE TRIGGER mangas_au_fts
+AFTER UPDATE ON mangas
+BEGIN
+    INSERT INTO manga_search(manga_search, rowid, title, author, synopsis)
+    VALUES ('delete', old.id, old.title, old.author, old.synopsis);
+    INSERT INTO manga_search(rowid, title, author, synopsis)
+    VALUES (new.id, new.title, new.author, new.synopsis);
+END;
+
+CREATE TRIGGER mangas_ad_fts
+AFTER DELETE ON mangas
+BEGIN
+    INSERT INTO manga_search(manga_search, rowid, title, author, synopsis)
+    VALUES ('delete', old.id, old.title, old.author, old.synopsis);
+END;
+
+INSERT INTO manga_search(manga_search) VALUES ('rebuild');
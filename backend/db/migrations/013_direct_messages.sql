@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS Direct_Messages (
+    Message_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    Sender_Id INTEGER NOT NULL,
+    Recipient_Id INTEGER NOT NULL,
+    Content TEXT NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    Delivered_At DATETIME,
+    FOREIGN KEY (Sender_Id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (Recipient_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_direct_messages_undelivered ON Direct_Messages(Recipient_Id, Delivered_At);
@@ -0,0 +1,2 @@
+ALTER TABLE chat_messages ADD COLUMN Edited_At DATETIME;
+ALTER TABLE chat_messages ADD COLUMN Deleted_At DATETIME;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS Blocked_Users (
+    Blocker_Id INTEGER NOT NULL,
+    Blocked_Id INTEGER NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Blocker_Id, Blocked_Id),
+    FOREIGN KEY (Blocker_Id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (Blocked_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocked_users_blocked ON Blocked_Users(Blocked_Id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS Collections (
+    Collection_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Name TEXT NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (User_Id, Name),
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Collection_Items (
+    Collection_Id INTEGER NOT NULL,
+    Manga_Id INTEGER NOT NULL,
+    Added_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Collection_Id, Manga_Id),
+    FOREIGN KEY (Collection_Id) REFERENCES Collections(Collection_Id) ON DELETE CASCADE,
+    FOREIGN KEY (Manga_Id) REFERENCES mangas(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_items_manga ON Collection_Items(Manga_Id);
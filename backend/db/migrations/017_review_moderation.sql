@@ -0,0 +1,14 @@
+ALTER TABLE ratings ADD COLUMN Hidden_At DATETIME;
+
+CREATE TABLE IF NOT EXISTS Review_Flags (
+    Flag_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    Review_Id INTEGER NOT NULL,
+    User_Id INTEGER NOT NULL,
+    Reason TEXT NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (Review_Id, User_Id),
+    FOREIGN KEY (Review_Id) REFERENCES ratings(id) ON DELETE CASCADE,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_flags_review ON Review_Flags(Review_Id);
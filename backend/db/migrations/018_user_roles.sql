@@ -0,0 +1,7 @@
+-- Denormalised role name read when issuing tokens; backfilled from role_id
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+
+UPDATE users
+SET role = (SELECT name FROM roles WHERE roles.id = users.role_id)
+WHERE role_id IS NOT NULL
+  AND EXISTS (SELECT 1 FROM roles WHERE roles.id = users.role_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Password_Reset_Tokens (
+    Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Token_Hash TEXT NOT NULL UNIQUE,
+    Expires_At DATETIME NOT NULL,
+    Used_At DATETIME,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON Password_Reset_Tokens(User_Id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reading_sessions_user;
+DROP TABLE IF EXISTS Reading_Sessions;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Reading_Sessions (
+    Session_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Manga_Id INTEGER NOT NULL,
+    Chapter_Id INTEGER,
+    Started_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    Ended_At DATETIME,
+    Duration_Seconds INTEGER,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (Manga_Id) REFERENCES mangas(id) ON DELETE CASCADE,
+    FOREIGN KEY (Chapter_Id) REFERENCES chapters(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_sessions_user ON Reading_Sessions(User_Id, Ended_At);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_user;
+DROP TABLE IF EXISTS Notifications;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS Notifications (
+    Notification_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Type TEXT NOT NULL,
+    Manga_Id INTEGER,
+    Chapter INTEGER,
+    Chapter_Id INTEGER,
+    Message TEXT NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    Read_At DATETIME,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (Manga_Id) REFERENCES mangas(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON Notifications(User_Id, Read_At, Created_At);
@@ -0,0 +1,2 @@
+-- notification_subscriptions predates this migration, so only the uniqueness guarantee is reverted.
+DROP INDEX IF EXISTS idx_notification_subs_user_manga;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_progress_history_user_manga;
+DROP TABLE IF EXISTS Progress_History;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Progress_History (
+    History_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Manga_Id INTEGER NOT NULL,
+    Requested_Chapter INTEGER NOT NULL,
+    Applied_Chapter INTEGER NOT NULL,
+    Is_Conflict INTEGER NOT NULL DEFAULT 0,
+    Is_Forced INTEGER NOT NULL DEFAULT 0,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (Manga_Id) REFERENCES mangas(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_progress_history_user_manga ON Progress_History(User_Id, Manga_Id, Created_At);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS Streak_Freezes;
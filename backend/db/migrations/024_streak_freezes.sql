@@ -0,0 +1,10 @@
+-- One row per missed day bridged by a streak freeze; the monthly allowance
+-- is enforced against the month of Freeze_Date
+CREATE TABLE IF NOT EXISTS Streak_Freezes (
+    User_Id INTEGER NOT NULL,
+    Freeze_Date TEXT NOT NULL,
+    Is_Manual INTEGER NOT NULL DEFAULT 0,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (User_Id, Freeze_Date),
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
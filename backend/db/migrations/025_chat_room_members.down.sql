@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_chat_room_members_user;
+DROP TABLE IF EXISTS Chat_Room_Members;
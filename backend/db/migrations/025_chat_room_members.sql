@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS Chat_Room_Members (
+    Room_Id INTEGER NOT NULL,
+    User_Id INTEGER NOT NULL,
+    Role TEXT NOT NULL DEFAULT 'member',
+    Added_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Room_Id, User_Id),
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_room_members_user ON Chat_Room_Members(User_Id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Idempotency_Keys (
+    User_Id INTEGER NOT NULL,
+    Endpoint TEXT NOT NULL,
+    Idempotency_Key TEXT NOT NULL,
+    Request_Hash TEXT NOT NULL,
+    Status_Code INTEGER,
+    Content_Type TEXT,
+    Response_Body BLOB,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    Expires_At DATETIME NOT NULL,
+    PRIMARY KEY (User_Id, Endpoint, Idempotency_Key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON Idempotency_Keys(Expires_At);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_review_votes_user;
+DROP TABLE IF EXISTS Review_Votes;
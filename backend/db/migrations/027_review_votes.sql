@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Review_Votes (
+    Review_Id INTEGER NOT NULL,
+    User_Id INTEGER NOT NULL,
+    Vote INTEGER NOT NULL CHECK (Vote IN (-1, 1)),
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Review_Id, User_Id),
+    FOREIGN KEY (Review_Id) REFERENCES ratings(id) ON DELETE CASCADE,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_votes_user ON Review_Votes(User_Id);
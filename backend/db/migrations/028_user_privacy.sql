@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS User_Privacy (
+    User_Id INTEGER PRIMARY KEY,
+    Share_Completions INTEGER NOT NULL DEFAULT 1,
+    Share_Reviews INTEGER NOT NULL DEFAULT 1,
+    Share_Ratings INTEGER NOT NULL DEFAULT 1,
+    Updated_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS Message_Reads (
+    Room_Id INTEGER NOT NULL,
+    User_Id INTEGER NOT NULL,
+    Last_Read_Message_Id INTEGER NOT NULL,
+    Updated_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Room_Id, User_Id),
+    FOREIGN KEY (User_Id) REFERENCES users(id) ON DELETE CASCADE
+);
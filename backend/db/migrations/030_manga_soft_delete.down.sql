@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_mangas_deleted_at;
+ALTER TABLE mangas DROP COLUMN deleted_at;
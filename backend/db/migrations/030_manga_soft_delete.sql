@@ -0,0 +1,3 @@
+ALTER TABLE mangas ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_mangas_deleted_at ON mangas(deleted_at);
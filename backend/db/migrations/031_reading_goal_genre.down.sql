@@ -0,0 +1 @@
+ALTER TABLE reading_goals DROP COLUMN genre;
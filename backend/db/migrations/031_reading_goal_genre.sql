@@ -0,0 +1 @@
+ALTER TABLE reading_goals ADD COLUMN genre TEXT;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_activities_user_created;
+DROP TABLE IF EXISTS activities;
+DROP INDEX IF EXISTS idx_friends_friend_user;
+DROP TABLE IF EXISTS friends;
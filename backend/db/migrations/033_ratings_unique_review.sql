@@ -0,0 +1,7 @@
+-- Reviews live in ratings, one per user and manga. Older databases may lack
+-- the table constraint, so keep each user's first row and enforce it with an
+-- index that CreateReview's ON CONFLICT relies on.
+DELETE FROM ratings
+WHERE id NOT IN (SELECT MIN(id) FROM ratings GROUP BY user_id, manga_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ratings_user_manga ON ratings(user_id, manga_id);
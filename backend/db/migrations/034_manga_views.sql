@@ -0,0 +1,5 @@
+-- Real view counts; the rating count stood in for them until now and seeds
+-- the column so existing rankings do not reset
+ALTER TABLE mangas ADD COLUMN views INTEGER NOT NULL DEFAULT 0;
+
+UPDATE mangas SET views = rating_count;
@@ -0,0 +1,5 @@
+-- Set once the user has been told a goal was completed so the notification
+-- is sent only once; goals completed before this existed count as notified
+ALTER TABLE reading_goals ADD COLUMN notified INTEGER NOT NULL DEFAULT 0;
+
+UPDATE reading_goals SET notified = 1 WHERE status = 'completed';
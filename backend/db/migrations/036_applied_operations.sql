@@ -0,0 +1,8 @@
+-- Write queue operations that have been applied. A row is inserted in the
+-- same transaction as the operation's write, so an operation replayed after a
+-- crash is skipped instead of applied twice
+CREATE TABLE IF NOT EXISTS Applied_Operations (
+    Operation_Id TEXT PRIMARY KEY,
+    Operation_Type TEXT NOT NULL,
+    Applied_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -1,8 +1,12 @@
 package db
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,19 +16,46 @@ import (
 const schemaMigrationsTable = `
 CREATE TABLE IF NOT EXISTS schema_migrations (
 	version VARCHAR(255) PRIMARY KEY,
-	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	checksum VARCHAR(64)
 )`
 
-// RunMigrations applies all SQL migrations in the provided directory.
-// Migrations are applied in lexicographic order and recorded in the
-// schema_migrations table to make the process idempotent.
+// ErrMigrationChecksumMismatch is returned when an applied migration file was
+// edited after it ran.
+var ErrMigrationChecksumMismatch = errors.New("applied migration was modified")
+
+// MigrationOptions tunes RunMigrationsWithOptions.
+type MigrationOptions struct {
+	// Force accepts edited migration files and records their new checksums
+	// instead of failing the run.
+	Force bool
+}
+
+// migration is one schema version with its forward and optional reverse script.
+// Up scripts are named NNN_name.up.sql (or NNN_name.sql for older migrations)
+// and down scripts NNN_name.down.sql.
+type migration struct {
+	Version  string
+	UpFile   string
+	DownFile string
+}
+
+// RunMigrations applies all pending SQL migrations in the provided directory.
+// Migrations are applied in version order and recorded in the
+// schema_migrations table with a SHA-256 checksum, so versions that were
+// already applied are skipped and edits to them are reported.
 func RunMigrations(db *sql.DB, migrationsDir string) error {
+	return RunMigrationsWithOptions(db, migrationsDir, MigrationOptions{})
+}
+
+// RunMigrationsWithOptions is RunMigrations with explicit options.
+func RunMigrationsWithOptions(db *sql.DB, migrationsDir string, opts MigrationOptions) error {
 	if migrationsDir == "" {
 		return fmt.Errorf("migrations directory is required")
 	}
 
-	if _, err := db.Exec(schemaMigrationsTable); err != nil {
-		return fmt.Errorf("prepare schema_migrations table: %w", err)
+	if err := prepareSchemaMigrations(db); err != nil {
+		return err
 	}
 
 	applied, err := loadAppliedMigrations(db)
@@ -32,69 +63,250 @@ func RunMigrations(db *sql.DB, migrationsDir string) error {
 		return fmt.Errorf("load applied migrations: %w", err)
 	}
 
-	entries, err := os.ReadDir(migrationsDir)
+	migrations, err := loadMigrations(migrationsDir)
 	if err != nil {
-		return fmt.Errorf("read migrations directory: %w", err)
+		return err
 	}
 
-	var files []string
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+	for _, m := range migrations {
+		if m.UpFile == "" {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			return fmt.Errorf("migration %s has a down script but no up script", m.Version)
+		}
+
+		sqlBytes, err := os.ReadFile(filepath.Join(migrationsDir, m.UpFile))
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", m.UpFile, err)
+		}
+		checksum := migrationChecksum(sqlBytes)
+
+		if prev, ok := applied[m.Version]; ok {
+			if err := verifyChecksum(db, m.UpFile, prev, checksum, opts.Force); err != nil {
+				return err
+			}
 			continue
 		}
-		files = append(files, entry.Name())
+
+		if err := execMigration(db, m.UpFile, sqlBytes, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, checksum) VALUES (?, ?)`, m.Version, checksum)
+			return err
+		}); err != nil {
+			return err
+		}
 	}
 
-	sort.Strings(files)
+	return nil
+}
 
-	for _, file := range files {
-		if _, ok := applied[file]; ok {
-			continue
+// RollbackMigration reverts the most recently applied migrations, newest
+// first, by running their down scripts. It stops with an error at the first
+// migration that has no down script.
+func RollbackMigration(db *sql.DB, migrationsDir string, steps int) error {
+	if migrationsDir == "" {
+		return fmt.Errorf("migrations directory is required")
+	}
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+
+	if err := prepareSchemaMigrations(db); err != nil {
+		return err
+	}
+
+	applied, err := loadAppliedMigrations(db)
+	if err != nil {
+		return fmt.Errorf("load applied migrations: %w", err)
+	}
+
+	migrations, err := loadMigrations(migrationsDir)
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[string]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	for _, version := range versions[:steps] {
+		m := byVersion[version]
+		if m.DownFile == "" {
+			return fmt.Errorf("migration %s has no down script", version)
 		}
 
-		path := filepath.Join(migrationsDir, file)
-		sqlBytes, err := os.ReadFile(path)
+		sqlBytes, err := os.ReadFile(filepath.Join(migrationsDir, m.DownFile))
 		if err != nil {
-			return fmt.Errorf("read migration %s: %w", file, err)
+			return fmt.Errorf("read migration %s: %w", m.DownFile, err)
 		}
 
-		tx, err := db.Begin()
-		if err != nil {
-			return fmt.Errorf("begin transaction for %s: %w", file, err)
+		recorded := applied[version].Recorded
+		if err := execMigration(db, m.DownFile, sqlBytes, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, recorded)
+			return err
+		}); err != nil {
+			return err
 		}
+	}
 
-		if _, err := tx.Exec(string(sqlBytes)); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("apply migration %s: %w", file, err)
+	return nil
+}
+
+// prepareSchemaMigrations creates the version table and adds the checksum
+// column to tables created before checksums were tracked
+func prepareSchemaMigrations(db *sql.DB) error {
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		return fmt.Errorf("prepare schema_migrations table: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT checksum FROM schema_migrations WHERE 1 = 0`)
+	if err == nil {
+		rows.Close()
+		return nil
+	}
+	if _, err := db.Exec(`ALTER TABLE schema_migrations ADD COLUMN checksum VARCHAR(64)`); err != nil {
+		return fmt.Errorf("add schema_migrations checksum column: %w", err)
+	}
+	return nil
+}
+
+// verifyChecksum compares an applied migration with its file on disk.
+// Rows recorded before checksums existed adopt the current checksum.
+func verifyChecksum(db *sql.DB, file string, prev appliedMigration, checksum string, force bool) error {
+	if prev.Checksum.Valid && prev.Checksum.String == checksum {
+		return nil
+	}
+	if prev.Checksum.Valid && !force {
+		return fmt.Errorf("%w: %s (recorded checksum %s, file checksum %s)", ErrMigrationChecksumMismatch, file, prev.Checksum.String, checksum)
+	}
+	if prev.Checksum.Valid {
+		log.Printf("db.migrations: %s changed after it was applied; recording new checksum (forced)", file)
+	}
+
+	if _, err := db.Exec(`UPDATE schema_migrations SET checksum = ? WHERE version = ?`, checksum, prev.Recorded); err != nil {
+		return fmt.Errorf("record checksum for %s: %w", file, err)
+	}
+	return nil
+}
+
+func migrationChecksum(sqlBytes []byte) string {
+	sum := sha256.Sum256(sqlBytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// execMigration runs a script and its bookkeeping in one transaction
+func execMigration(db *sql.DB, file string, sqlBytes []byte, record func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction for %s: %w", file, err)
+	}
+
+	if _, err := tx.Exec(string(sqlBytes)); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("apply migration %s: %w", file, err)
+	}
+
+	if err := record(tx); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("record migration %s: %w", file, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %s: %w", file, err)
+	}
+	return nil
+}
+
+// loadMigrations pairs the up and down scripts in dir by version, in version order
+func loadMigrations(migrationsDir string) ([]migration, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[string]*migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
 		}
 
-		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, file); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("record migration %s: %w", file, err)
+		down := strings.HasSuffix(name, ".down.sql")
+		version := migrationVersion(name)
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version}
+			byVersion[version] = m
 		}
 
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit migration %s: %w", file, err)
+		if down {
+			if m.DownFile != "" {
+				return nil, fmt.Errorf("duplicate down migrations for version %s: %s and %s", version, m.DownFile, name)
+			}
+			m.DownFile = name
+			continue
+		}
+		if m.UpFile != "" {
+			return nil, fmt.Errorf("duplicate migrations for version %s: %s and %s", version, m.UpFile, name)
 		}
+		m.UpFile = name
 	}
 
-	return nil
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// migrationVersion returns the version of a migration file: its numeric
+// prefix ("010" for 010_refresh_tokens.up.sql), or the whole name without
+// extensions when it has none
+func migrationVersion(name string) string {
+	base := strings.TrimSuffix(name, ".sql")
+	base = strings.TrimSuffix(strings.TrimSuffix(base, ".up"), ".down")
+
+	prefix, _, found := strings.Cut(base, "_")
+	if found && prefix != "" && strings.Trim(prefix, "0123456789") == "" {
+		return prefix
+	}
+	return base
+}
+
+// appliedMigration is a schema_migrations row. Recorded is the stored
+// version, which for older rows is the full file name, e.g. 010_refresh_tokens.sql.
+type appliedMigration struct {
+	Recorded string
+	Checksum sql.NullString
 }
 
-func loadAppliedMigrations(db *sql.DB) (map[string]struct{}, error) {
-	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+// loadAppliedMigrations maps each applied version to its schema_migrations row
+func loadAppliedMigrations(db *sql.DB) (map[string]appliedMigration, error) {
+	rows, err := db.Query(`SELECT version, checksum FROM schema_migrations`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	applied := make(map[string]struct{})
+	applied := make(map[string]appliedMigration)
 	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
+		var m appliedMigration
+		if err := rows.Scan(&m.Recorded, &m.Checksum); err != nil {
 			return nil, err
 		}
-		applied[version] = struct{}{}
+		applied[migrationVersion(m.Recorded)] = m
 	}
 
 	if err := rows.Err(); err != nil {
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func openMigrationsDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count); err != nil {
+		t.Fatalf("lookup table %s: %v", name, err)
+	}
+	return count > 0
+}
+
+func appliedVersions(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		t.Fatalf("query schema_migrations: %v", err)
+	}
+	defer rows.Close()
+	var versions []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			t.Fatalf("scan version: %v", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func TestRunMigrationsIsIdempotent(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.up.sql":   `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+		"001_widgets.down.sql": `DROP TABLE widgets;`,
+		// Not guarded by IF NOT EXISTS, so a second application would fail
+		"002_gadgets.sql": `CREATE TABLE gadgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+
+	for i := 0; i < 2; i++ {
+		if err := RunMigrations(db, dir); err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+	}
+
+	if !tableExists(t, db, "widgets") || !tableExists(t, db, "gadgets") {
+		t.Fatalf("expected both tables to exist")
+	}
+	versions := appliedVersions(t, db)
+	if len(versions) != 2 || versions[0] != "001" || versions[1] != "002" {
+		t.Fatalf("unexpected applied versions %v", versions)
+	}
+}
+
+func TestRunMigrationsRollsBackWhenRecordingFails(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.up.sql": `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+	// Simulate a crash between applying the body and recording the version
+	if _, err := db.Exec(`
+		CREATE TRIGGER fail_record BEFORE INSERT ON schema_migrations
+		BEGIN SELECT RAISE(ABORT, 'record failed'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if err := RunMigrations(db, dir); err == nil {
+		t.Fatalf("expected the run to fail when the version cannot be recorded")
+	}
+	if tableExists(t, db, "widgets") {
+		t.Fatalf("expected the migration body to be rolled back with its record")
+	}
+
+	if _, err := db.Exec(`DROP TRIGGER fail_record`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("rerun after failure: %v", err)
+	}
+	if !tableExists(t, db, "widgets") {
+		t.Fatalf("expected the migration to apply on the next run")
+	}
+}
+
+func TestRunMigrationsSkipsLegacyFilenameVersions(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version) VALUES ('001_widgets.sql')`); err != nil {
+		t.Fatalf("seed legacy version: %v", err)
+	}
+
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	if tableExists(t, db, "widgets") {
+		t.Fatalf("expected migration recorded under its legacy filename to be skipped")
+	}
+}
+
+func TestRollbackMigrationRevertsOneStep(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.up.sql":   `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+		"001_widgets.down.sql": `DROP TABLE widgets;`,
+		"002_gadgets.up.sql":   `CREATE TABLE gadgets (id INTEGER PRIMARY KEY);`,
+		"002_gadgets.down.sql": `DROP TABLE gadgets;`,
+	})
+	db := openMigrationsDB(t)
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	if err := RollbackMigration(db, dir, 1); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+
+	if tableExists(t, db, "gadgets") {
+		t.Fatalf("expected gadgets to be dropped")
+	}
+	if !tableExists(t, db, "widgets") {
+		t.Fatalf("expected widgets to remain")
+	}
+	if versions := appliedVersions(t, db); len(versions) != 1 || versions[0] != "001" {
+		t.Fatalf("unexpected applied versions after rollback %v", versions)
+	}
+
+	// The rolled back version is pending again
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("reapply: %v", err)
+	}
+	if !tableExists(t, db, "gadgets") {
+		t.Fatalf("expected gadgets to be recreated")
+	}
+}
+
+func TestRollbackMigrationRequiresDownScript(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	if err := RollbackMigration(db, dir, 1); err == nil {
+		t.Fatalf("expected an error for a migration without a down script")
+	}
+	if !tableExists(t, db, "widgets") {
+		t.Fatalf("expected widgets to remain")
+	}
+}
+
+func TestRunMigrationsFailsWhenAppliedMigrationEdited(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	edited := `CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT);`
+	if err := os.WriteFile(filepath.Join(dir, "001_widgets.sql"), []byte(edited), 0o644); err != nil {
+		t.Fatalf("edit migration: %v", err)
+	}
+
+	err := RunMigrations(db, dir)
+	if !errors.Is(err, ErrMigrationChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	if err := RunMigrationsWithOptions(db, dir, MigrationOptions{Force: true}); err != nil {
+		t.Fatalf("forced run: %v", err)
+	}
+	// Forcing records the new checksum, so later runs pass without it
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run after force: %v", err)
+	}
+}
+
+func TestRunMigrationsBackfillsChecksumsForLegacyTable(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (id INTEGER PRIMARY KEY);`,
+	})
+	db := openMigrationsDB(t)
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version VARCHAR(255) PRIMARY KEY, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("create legacy schema_migrations: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version) VALUES ('001_widgets.sql')`); err != nil {
+		t.Fatalf("seed legacy version: %v", err)
+	}
+
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	var checksum sql.NullString
+	if err := db.QueryRow(`SELECT checksum FROM schema_migrations WHERE version = '001_widgets.sql'`).Scan(&checksum); err != nil {
+		t.Fatalf("read checksum: %v", err)
+	}
+	if !checksum.Valid || len(checksum.String) != 64 {
+		t.Fatalf("expected a sha-256 checksum to be backfilled, got %+v", checksum)
+	}
+}
@@ -185,7 +185,7 @@ func (r *Repository) ListMessages(
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, room_id, user_id, content, created_at
 		FROM chat_messages
-		WHERE room_id = ?
+		WHERE room_id = ? AND deleted_at IS NULL
 		ORDER BY created_at ASC, id ASC
 		LIMIT ? OFFSET ?
 	`, roomID, limit, offset)
@@ -217,7 +217,7 @@ func (r *Repository) GetLastMessage(ctx context.Context, roomID int64) (*ChatMes
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, room_id, user_id, content, created_at
 		FROM chat_messages
-		WHERE room_id = ?
+		WHERE room_id = ? AND deleted_at IS NULL
 		ORDER BY created_at DESC, id DESC
 		LIMIT 1
 	`, roomID)
@@ -51,6 +51,14 @@ func (s *Service) SendMessage(ctx context.Context, senderID, receiverID int64, c
 		return nil, nil, ErrCannotMessageSelf
 	}
 
+	blocked, err := s.friendRepo.IsBlocked(ctx, senderID, receiverID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blocked {
+		return nil, nil, ErrNotFriends
+	}
+
 	areFriends, err := s.friendRepo.AreFriends(ctx, senderID, receiverID)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,95 @@
+package comment
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
+)
+
+var (
+	ErrInvalidCursor     = errors.New("invalid pagination cursor")
+	ErrCursorUnsupported = errors.New("cursor pagination supports sort_by recent or oldest only")
+)
+
+// ReviewCursor is the keyset position of a review listing: the sort key and
+// ID of the last review a client has seen
+type ReviewCursor struct {
+	CreatedAt string `json:"t"`
+	ReviewID  int64  `json:"id"`
+}
+
+// EncodeReviewCursor renders a cursor as the opaque token clients send back
+func EncodeReviewCursor(cursor ReviewCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeReviewCursor parses a token produced by EncodeReviewCursor
+func DecodeReviewCursor(token string) (*ReviewCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor ReviewCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt == "" || cursor.ReviewID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// GetReviewsByCursor returns the page of reviews following cursor, or the
+// first page for an empty cursor. Unlike offset pages, keyset pages neither
+// skip nor repeat reviews when others are posted or removed in between.
+// Meta.NextCursor is empty on the last page.
+func (s *Service) GetReviewsByCursor(ctx context.Context, mangaID int64, viewerID *int64, cursor string, limit int, sortBy string) (*GetReviewsResponse, error) {
+	ascending := false
+	switch strings.ToLower(sortBy) {
+	case "", "recent":
+	case "oldest":
+		ascending = true
+	default:
+		return nil, ErrCursorUnsupported
+	}
+
+	var after *ReviewCursor
+	if cursor != "" {
+		decoded, err := DecodeReviewCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = decoded
+	}
+	_, limit = pagination.Normalize(1, limit, pagination.Default())
+
+	var viewer int64
+	if viewerID != nil {
+		viewer = *viewerID
+	}
+	reviews, next, err := s.repo.GetReviewsAfter(ctx, mangaID, viewer, after, limit, ascending)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if reviews == nil {
+		reviews = []Review{}
+	}
+
+	total, err := s.repo.CountReviews(ctx, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	stats, err := s.repo.GetReviewStats(ctx, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	meta := ReviewsMeta{Limit: limit, Total: total}
+	if next != nil {
+		meta.NextCursor = EncodeReviewCursor(*next)
+	}
+	return &GetReviewsResponse{Data: reviews, Meta: meta, Stats: stats}, nil
+}
@@ -0,0 +1,83 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGetReviewsByCursorWithConcurrentWrites(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+	db := svc.repo.db
+
+	// Reviews 100..109, with pairs sharing a timestamp so ties fall back to the ID
+	for i := 0; i < 10; i++ {
+		if _, err := db.Exec(`INSERT INTO ratings (id, user_id, manga_id, score, review, created_at) VALUES (?, 1, 20, 4, ?, ?)`,
+			100+i, fmt.Sprintf("review number %d", i), fmt.Sprintf("2024-01-%02d 10:00:00", 1+i/2)); err != nil {
+			t.Fatalf("failed to seed review: %v", err)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	var order []int64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("cursor paging did not terminate")
+		}
+		resp, err := svc.GetReviewsByCursor(ctx, 20, nil, cursor, 3, "recent")
+		if err != nil {
+			t.Fatalf("GetReviewsByCursor returned error: %v", err)
+		}
+		for _, review := range resp.Data {
+			if seen[review.ReviewID] {
+				t.Fatalf("review %d returned twice (order so far %v)", review.ReviewID, order)
+			}
+			seen[review.ReviewID] = true
+			order = append(order, review.ReviewID)
+		}
+
+		// New reviews land on the first page and would shift offset pages
+		if _, err := db.Exec(`INSERT INTO ratings (user_id, manga_id, score, review) VALUES (2, 20, 5, 'posted while paging')`); err != nil {
+			t.Fatalf("failed to insert review: %v", err)
+		}
+		if pages == 1 {
+			// Remove a review that has already been returned
+			if _, err := db.Exec(`DELETE FROM ratings WHERE id = ?`, order[0]); err != nil {
+				t.Fatalf("failed to delete review: %v", err)
+			}
+		}
+
+		if resp.Meta.NextCursor == "" {
+			break
+		}
+		cursor = resp.Meta.NextCursor
+	}
+
+	want := []int64{109, 108, 107, 106, 105, 104, 103, 102, 101, 100}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+
+	oldest, err := svc.GetReviewsByCursor(ctx, 20, nil, "", 2, "oldest")
+	if err != nil {
+		t.Fatalf("GetReviewsByCursor (oldest) returned error: %v", err)
+	}
+	if len(oldest.Data) != 2 || oldest.Data[0].ReviewID != 100 || oldest.Data[1].ReviewID != 101 || oldest.Meta.NextCursor == "" {
+		t.Fatalf("unexpected oldest page: %+v", oldest)
+	}
+}
+
+func TestGetReviewsByCursorRejectsBadInput(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	if _, err := svc.GetReviewsByCursor(ctx, 10, nil, "not a cursor!", 20, "recent"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	if _, err := svc.GetReviewsByCursor(ctx, 10, nil, "", 20, "rating"); !errors.Is(err, ErrCursorUnsupported) {
+		t.Fatalf("expected ErrCursorUnsupported, got %v", err)
+	}
+}
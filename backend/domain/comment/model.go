@@ -13,18 +13,49 @@ type Review struct {
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	HelpfulVotes   int `json:"helpful_votes"`
+	UnhelpfulVotes int `json:"unhelpful_votes"`
+	// MyVote is the requesting user's vote, "helpful" or "unhelpful"
+	MyVote string `json:"my_vote,omitempty"`
+}
+
+// Review vote values
+const (
+	VoteHelpful   = "helpful"
+	VoteUnhelpful = "unhelpful"
+)
+
+// voteValues maps vote names to the value stored in Review_Votes
+var voteValues = map[string]int{
+	VoteHelpful:   1,
+	VoteUnhelpful: -1,
+}
+
+// voteName is the inverse of voteValues; it returns "" for no vote
+func voteName(vote int) string {
+	switch vote {
+	case 1:
+		return VoteHelpful
+	case -1:
+		return VoteUnhelpful
+	default:
+		return ""
+	}
 }
 
 // ReviewStats contains aggregated review metrics
 type ReviewStats struct {
 	AverageRating float64 `json:"average_rating"`
 	TotalReviews  int     `json:"total_reviews"`
+	// Distribution maps each rating to its number of reviews
+	Distribution map[int]int `json:"distribution"`
 }
 
 // CreateReviewRequest captures incoming payload
 type CreateReviewRequest struct {
-	Rating  int    `json:"rating" binding:"required,min=1,max=10"`
-	Content string `json:"content" binding:"required,min=10,max=5000"`
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Content string `json:"content" binding:"required"` // length checked against the security policy
 }
 
 // CreateReviewResponse is returned after successful creation
@@ -47,18 +78,56 @@ type UpdateReviewResponse struct {
 
 // GetReviewsResponse represents paginated review listing
 type GetReviewsResponse struct {
-	Data []Review    `json:"data"`
-	Meta ReviewsMeta `json:"meta"`
+	Data  []Review     `json:"data"`
+	Meta  ReviewsMeta  `json:"meta"`
+	Stats *ReviewStats `json:"stats,omitempty"`
 }
 
 // ReviewsMeta contains pagination information
 type ReviewsMeta struct {
-	Page  int `json:"page"`
+	// Page is omitted when paging by cursor
+	Page  int `json:"page,omitempty"`
 	Limit int `json:"limit"`
 	Total int `json:"total"`
+	// NextCursor fetches the following page in cursor mode; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // DeleteReviewResponse acknowledges review deletion
 type DeleteReviewResponse struct {
+	Message string       `json:"message"`
+	Stats   *ReviewStats `json:"stats,omitempty"`
+}
+
+// VoteReviewRequest casts or toggles a helpfulness vote
+type VoteReviewRequest struct {
+	Vote string `json:"vote" binding:"required"`
+}
+
+// VoteReviewResponse reports the review's tallies after a vote
+type VoteReviewResponse struct {
+	Message        string `json:"message"`
+	ReviewID       int64  `json:"review_id"`
+	HelpfulVotes   int    `json:"helpful_votes"`
+	UnhelpfulVotes int    `json:"unhelpful_votes"`
+	MyVote         string `json:"my_vote,omitempty"`
+}
+
+// FlagReviewRequest captures a user's report about a review
+type FlagReviewRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// FlagReviewResponse acknowledges a report
+type FlagReviewResponse struct {
 	Message string `json:"message"`
 }
+
+// ModerateReviewResponse is returned to admins after hiding or restoring a review
+type ModerateReviewResponse struct {
+	Message   string       `json:"message"`
+	ReviewID  int64        `json:"review_id"`
+	Hidden    bool         `json:"hidden"`
+	FlagCount int          `json:"flag_count"`
+	Stats     *ReviewStats `json:"stats,omitempty"`
+}
@@ -7,55 +7,76 @@ import (
 	"fmt"
 	"log"
 	"strings"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
+	"github.com/ngocan-dev/mangahub/backend/internal/security"
 )
 
 // Repository handles database operations for reviews
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	reader *sql.DB
 }
 
 // NewRepository creates a new comment repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, reader: db}
 }
 
-// CreateReview inserts a review row atomically
-func (r *Repository) CreateReview(ctx context.Context, userID, mangaID int64, rating int, content string) (int64, error) {
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		return 0, err
+// SetReader routes read-only review listing queries to a replica; nil restores the primary
+func (r *Repository) SetReader(reader *sql.DB) {
+	if reader == nil {
+		reader = r.db
 	}
-	defer tx.Rollback()
+	r.reader = reader
+}
 
-	var existingCount int
-	err = tx.QueryRowContext(ctx, `
-        SELECT COUNT(*) FROM Reviews
-        WHERE User_Id = ? AND Novel_Id = ?
-    `, userID, mangaID).Scan(&existingCount)
-	if err != nil {
-		return 0, err
-	}
-	if existingCount > 0 {
-		return 0, fmt.Errorf("user has already reviewed this manga")
-	}
+// CreateReview stores a review and returns its id. The unique
+// (user_id, manga_id) index settles concurrent attempts: the losing insert
+// changes no row and gets ErrReviewAlreadyExists. A score left without a
+// review text is turned into the review instead.
+func (r *Repository) CreateReview(ctx context.Context, userID, mangaID int64, rating int, content string) (int64, error) {
+	return createReview(ctx, r.db, userID, mangaID, rating, content)
+}
 
-	result, err := tx.ExecContext(ctx, `
-        INSERT INTO Reviews (User_Id, Novel_Id, Rating, Content, Created_At, Updated_At)
+// CreateReviewTx is CreateReview inside the caller's transaction
+func (r *Repository) CreateReviewTx(ctx context.Context, tx *sql.Tx, userID, mangaID int64, rating int, content string) (int64, error) {
+	return createReview(ctx, tx, userID, mangaID, rating, content)
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// createReview runs CreateReview on q, which may be a *sql.DB or *sql.Tx
+func createReview(ctx context.Context, q execQuerier, userID, mangaID int64, rating int, content string) (int64, error) {
+	result, err := q.ExecContext(ctx, `
+        INSERT INTO ratings (user_id, manga_id, score, review, created_at, updated_at)
         VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+        ON CONFLICT(user_id, manga_id) DO UPDATE SET
+            score = excluded.score,
+            review = excluded.review,
+            updated_at = excluded.updated_at
+        WHERE ratings.review IS NULL OR ratings.review = ''
     `, userID, mangaID, rating, content)
 	if err != nil {
 		return 0, err
 	}
-
-	reviewID, err := result.LastInsertId()
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return 0, err
 	}
+	if rows == 0 {
+		return 0, ErrReviewAlreadyExists
+	}
 
-	if err := tx.Commit(); err != nil {
+	// LastInsertId is not the row id when the upsert updated an existing row
+	var reviewID int64
+	err = q.QueryRowContext(ctx, `SELECT id FROM ratings WHERE user_id = ? AND manga_id = ?`, userID, mangaID).Scan(&reviewID)
+	if err != nil {
 		return 0, err
 	}
-
 	return reviewID, nil
 }
 
@@ -73,57 +94,41 @@ func (r *Repository) CheckMangaInCompletedLibrary(ctx context.Context, userID, m
 	return count > 0, nil
 }
 
+// reviewColumns selects a single review from ratings r joined to users u
+const reviewColumns = `
+        SELECT r.id, r.user_id, u.username, r.manga_id, r.score, r.review, r.created_at, r.updated_at
+        FROM ratings r
+        LEFT JOIN users u ON r.user_id = u.id`
+
 // GetReviewByUserAndManga returns review if exists
 func (r *Repository) GetReviewByUserAndManga(ctx context.Context, userID, mangaID int64) (*Review, error) {
-	query := `
-        SELECT r.Review_Id, r.User_Id, u.Username, r.Novel_Id, r.Rating, r.Content, r.Created_At, r.Updated_At
-        FROM Reviews r
-        JOIN Users u ON r.User_Id = u.UserId
-        WHERE r.User_Id = ? AND r.Novel_Id = ?
-    `
-	var review Review
-	var updatedAt sql.NullTime
-	err := r.db.QueryRowContext(ctx, query, userID, mangaID).Scan(
-		&review.ReviewID,
-		&review.UserID,
-		&review.Username,
-		&review.MangaID,
-		&review.Rating,
-		&review.Content,
-		&review.CreatedAt,
-		&updatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	if updatedAt.Valid {
-		review.UpdatedAt = updatedAt.Time
-	}
-
-	return &review, nil
+	row := r.db.QueryRowContext(ctx, reviewColumns+`
+        WHERE r.user_id = ? AND r.manga_id = ? AND r.review IS NOT NULL AND r.review <> ''
+    `, userID, mangaID)
+	return scanReview(row)
 }
 
 // GetReviewByID fetches a review by its identifier
 func (r *Repository) GetReviewByID(ctx context.Context, reviewID int64) (*Review, error) {
-	query := `
-SELECT r.Review_Id, r.User_Id, u.Username, r.Novel_Id, r.Rating, r.Content, r.Created_At, r.Updated_At
-FROM Reviews r
-JOIN Users u ON r.User_Id = u.UserId
-WHERE r.Review_Id = ?
-`
+	row := r.db.QueryRowContext(ctx, reviewColumns+`
+        WHERE r.id = ? AND r.review IS NOT NULL AND r.review <> ''
+    `, reviewID)
+	return scanReview(row)
+}
+
+// scanReview reads a row selected with reviewColumns, returning nil when
+// there is none
+func scanReview(row *sql.Row) (*Review, error) {
 	var review Review
+	var username, content sql.NullString
 	var updatedAt sql.NullTime
-	err := r.db.QueryRowContext(ctx, query, reviewID).Scan(
+	err := row.Scan(
 		&review.ReviewID,
 		&review.UserID,
-		&review.Username,
+		&username,
 		&review.MangaID,
 		&review.Rating,
-		&review.Content,
+		&content,
 		&review.CreatedAt,
 		&updatedAt,
 	)
@@ -133,51 +138,116 @@ WHERE r.Review_Id = ?
 	if err != nil {
 		return nil, err
 	}
+
+	review.Username = username.String
+	review.Content = content.String
 	if updatedAt.Valid {
 		review.UpdatedAt = updatedAt.Time
 	}
 	return &review, nil
 }
 
-// GetReviewsByMangaID fetches paginated list of reviews for a manga.
-func (r *Repository) GetReviewsByMangaID(ctx context.Context, mangaID int64, page, limit int, sortBy string) ([]Review, int, error) {
-	var total int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ratings WHERE manga_id = ? AND review IS NOT NULL AND review <> ''`, mangaID).Scan(&total)
+// GetReviewsByMangaID fetches paginated list of reviews for a manga with their
+// vote tallies. viewerID, when non-zero, fills in that user's own vote.
+func (r *Repository) GetReviewsByMangaID(ctx context.Context, mangaID, viewerID int64, page, limit int, sortBy string) ([]Review, int, error) {
+	total, err := r.CountReviews(ctx, mangaID)
 	if err != nil {
-		if isNoDataError(err) {
-			return []Review{}, 0, nil
-		}
-		log.Printf("comment.repository.GetReviewsByMangaID: count failed manga_id=%d err=%v", mangaID, err)
 		return nil, 0, err
 	}
-
 	if total == 0 {
 		return []Review{}, 0, nil
 	}
 
-	if page < 1 {
-		page = 1
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
+	offset := pagination.Offset(page, limit)
+
+	orderClause := "ORDER BY r.created_at DESC, r.id DESC"
+	switch strings.ToLower(sortBy) {
+	case "rating":
+		orderClause = "ORDER BY r.score DESC, r.created_at DESC"
+	case "helpfulness":
+		orderClause = "ORDER BY COALESCE(v.helpful, 0) - COALESCE(v.unhelpful, 0) DESC, COALESCE(v.helpful, 0) DESC, r.created_at DESC"
+	case "oldest":
+		orderClause = "ORDER BY r.created_at ASC, r.id ASC"
 	}
-	if limit < 1 {
-		limit = 20
+
+	query := reviewListQuery("", orderClause) + " LIMIT ? OFFSET ?"
+	rows, err := r.reader.QueryContext(ctx, query, viewerID, mangaID, limit, offset)
+	if err != nil {
+		log.Printf("comment.repository.GetReviewsByMangaID: query failed manga_id=%d err=%v", mangaID, err)
+		return nil, 0, err
 	}
-	if limit > 100 {
-		limit = 100
+	defer rows.Close()
+
+	reviews, _, err := scanReviewRows(rows)
+	if err != nil {
+		log.Printf("comment.repository.GetReviewsByMangaID: scan failed manga_id=%d err=%v", mangaID, err)
+		return nil, 0, err
 	}
-	offset := (page - 1) * limit
-	if offset < 0 {
-		offset = 0
+	return reviews, total, nil
+}
+
+// GetReviewsAfter fetches up to limit reviews ordered by (created_at, id),
+// newest first unless ascending, starting after the cursor. A nil cursor
+// starts at the first review. The returned cursor points at the last review
+// and is nil when no reviews follow it.
+func (r *Repository) GetReviewsAfter(ctx context.Context, mangaID, viewerID int64, after *ReviewCursor, limit int, ascending bool) ([]Review, *ReviewCursor, error) {
+	_, limit = pagination.Normalize(1, limit, pagination.Default())
+
+	cmp, direction := "<", "DESC"
+	if ascending {
+		cmp, direction = ">", "ASC"
+	}
+	keyset := ""
+	args := []interface{}{viewerID, mangaID}
+	if after != nil {
+		keyset = fmt.Sprintf("AND (r.created_at %[1]s ? OR (r.created_at = ? AND r.id %[1]s ?))", cmp)
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ReviewID)
+	}
+	// One extra row tells whether another page follows
+	query := reviewListQuery(keyset, fmt.Sprintf("ORDER BY r.created_at %[1]s, r.id %[1]s", direction)) + " LIMIT ?"
+	args = append(args, limit+1)
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		if isNoDataError(err) {
+			return []Review{}, nil, nil
+		}
+		log.Printf("comment.repository.GetReviewsAfter: query failed manga_id=%d err=%v", mangaID, err)
+		return nil, nil, err
 	}
+	defer rows.Close()
 
-	orderClause := "ORDER BY r.created_at DESC"
-	switch strings.ToLower(sortBy) {
-	case "rating", "helpfulness":
-		orderClause = "ORDER BY r.score DESC, r.created_at DESC"
-	case "oldest":
-		orderClause = "ORDER BY r.created_at ASC"
+	reviews, keys, err := scanReviewRows(rows)
+	if err != nil {
+		log.Printf("comment.repository.GetReviewsAfter: scan failed manga_id=%d err=%v", mangaID, err)
+		return nil, nil, err
 	}
+	if len(reviews) <= limit {
+		return reviews, nil, nil
+	}
+	reviews = reviews[:limit]
+	return reviews, &ReviewCursor{CreatedAt: keys[limit-1], ReviewID: reviews[limit-1].ReviewID}, nil
+}
 
-	query := fmt.Sprintf(`
+// CountReviews counts the visible reviews of a manga
+func (r *Repository) CountReviews(ctx context.Context, mangaID int64) (int, error) {
+	var total int
+	err := r.reader.QueryRowContext(ctx, `SELECT COUNT(*) FROM ratings WHERE manga_id = ? AND review IS NOT NULL AND review <> '' AND Hidden_At IS NULL`, mangaID).Scan(&total)
+	if err != nil {
+		if isNoDataError(err) {
+			return 0, nil
+		}
+		log.Printf("comment.repository.CountReviews: count failed manga_id=%d err=%v", mangaID, err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// reviewListQuery selects visible reviews of a manga with their vote tallies.
+// Its parameters are the viewer ID and manga ID, followed by any in filter.
+func reviewListQuery(filter, orderClause string) string {
+	return fmt.Sprintf(`
         SELECT
             r.id,
             r.user_id,
@@ -187,28 +257,39 @@ func (r *Repository) GetReviewsByMangaID(ctx context.Context, mangaID int64, pag
             r.score,
             r.review,
             r.created_at,
-            r.updated_at
+            CAST(r.created_at AS TEXT),
+            r.updated_at,
+            COALESCE(v.helpful, 0),
+            COALESCE(v.unhelpful, 0),
+            COALESCE(mv.Vote, 0)
         FROM ratings r
         LEFT JOIN users u ON r.user_id = u.id
-        WHERE r.manga_id = ? AND r.review IS NOT NULL AND r.review <> ''
+        LEFT JOIN (
+            SELECT Review_Id,
+                   SUM(CASE WHEN Vote = 1 THEN 1 ELSE 0 END) AS helpful,
+                   SUM(CASE WHEN Vote = -1 THEN 1 ELSE 0 END) AS unhelpful
+            FROM Review_Votes
+            GROUP BY Review_Id
+        ) v ON v.Review_Id = r.id
+        LEFT JOIN Review_Votes mv ON mv.Review_Id = r.id AND mv.User_Id = ?
+        WHERE r.manga_id = ? AND r.review IS NOT NULL AND r.review <> '' AND r.Hidden_At IS NULL
         %s
-        LIMIT ? OFFSET ?
-    `, orderClause)
-
-	rows, err := r.db.QueryContext(ctx, query, mangaID, limit, offset)
-	if err != nil {
-		log.Printf("comment.repository.GetReviewsByMangaID: query failed manga_id=%d err=%v", mangaID, err)
-		return nil, 0, err
-	}
-	defer rows.Close()
+        %s`, filter, orderClause)
+}
 
+// scanReviewRows reads rows of reviewListQuery. Alongside the reviews it
+// returns each row's created_at exactly as stored, which cursors compare against.
+func scanReviewRows(rows *sql.Rows) ([]Review, []string, error) {
 	var reviews []Review
+	var keys []string
 	for rows.Next() {
 		var review Review
 		var username sql.NullString
 		var avatar sql.NullString
 		var content sql.NullString
+		var createdKey string
 		var updatedAt sql.NullTime
+		var myVote int
 		if err := rows.Scan(
 			&review.ReviewID,
 			&review.UserID,
@@ -218,10 +299,13 @@ func (r *Repository) GetReviewsByMangaID(ctx context.Context, mangaID int64, pag
 			&review.Rating,
 			&content,
 			&review.CreatedAt,
+			&createdKey,
 			&updatedAt,
+			&review.HelpfulVotes,
+			&review.UnhelpfulVotes,
+			&myVote,
 		); err != nil {
-			log.Printf("comment.repository.GetReviewsByMangaID: scan failed manga_id=%d err=%v", mangaID, err)
-			return nil, 0, err
+			return nil, nil, err
 		}
 
 		if username.Valid {
@@ -236,40 +320,62 @@ func (r *Repository) GetReviewsByMangaID(ctx context.Context, mangaID int64, pag
 		if updatedAt.Valid {
 			review.UpdatedAt = updatedAt.Time
 		}
+		review.MyVote = voteName(myVote)
 		reviews = append(reviews, review)
+		keys = append(keys, createdKey)
 	}
-
 	if err := rows.Err(); err != nil {
-		log.Printf("comment.repository.GetReviewsByMangaID: rows error manga_id=%d err=%v", mangaID, err)
-		return nil, 0, err
+		return nil, nil, err
 	}
-
-	return reviews, total, nil
+	return reviews, keys, nil
 }
 
-// GetReviewStats aggregates review information
+// GetReviewStats aggregates review information; hidden reviews are not counted.
+// A single GROUP BY query yields the rating histogram, from which the total
+// and average are derived. Every rating on the scale appears in Distribution,
+// with 0 when nobody gave it.
 func (r *Repository) GetReviewStats(ctx context.Context, mangaID int64) (*ReviewStats, error) {
-	query := `
-        SELECT
-            COALESCE(COUNT(*), 0) as total_reviews,
-            COALESCE(AVG(score), 0) as average_rating
+	stats := &ReviewStats{Distribution: make(map[int]int, security.MaxReviewRating-security.MinReviewRating+1)}
+	for rating := security.MinReviewRating; rating <= security.MaxReviewRating; rating++ {
+		stats.Distribution[rating] = 0
+	}
+
+	rows, err := r.reader.QueryContext(ctx, `
+        SELECT score, COUNT(*)
         FROM ratings
-        WHERE manga_id = ? AND review IS NOT NULL AND review <> ''
-    `
-	var stats ReviewStats
-	err := r.db.QueryRowContext(ctx, query, mangaID).Scan(
-		&stats.TotalReviews,
-		&stats.AverageRating,
-	)
+        WHERE manga_id = ? AND review IS NOT NULL AND review <> '' AND Hidden_At IS NULL
+        GROUP BY score
+    `, mangaID)
 	if err != nil {
 		if isNoDataError(err) {
-			return &ReviewStats{}, nil
+			return stats, nil
 		}
 		log.Printf("comment.repository.GetReviewStats: query failed manga_id=%d err=%v", mangaID, err)
 		return nil, err
 	}
-	stats.AverageRating = float64(int(stats.AverageRating*100+0.5)) / 100
-	return &stats, nil
+	defer rows.Close()
+
+	sum := 0
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			log.Printf("comment.repository.GetReviewStats: scan failed manga_id=%d err=%v", mangaID, err)
+			return nil, err
+		}
+		stats.Distribution[rating] = count
+		stats.TotalReviews += count
+		sum += rating * count
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("comment.repository.GetReviewStats: rows error manga_id=%d err=%v", mangaID, err)
+		return nil, err
+	}
+
+	if stats.TotalReviews > 0 {
+		average := float64(sum) / float64(stats.TotalReviews)
+		stats.AverageRating = float64(int(average*100+0.5)) / 100
+	}
+	return stats, nil
 }
 
 func isNoDataError(err error) bool {
@@ -287,18 +393,18 @@ func (r *Repository) UpdateReview(ctx context.Context, reviewID, userID int64, r
 	setClauses := make([]string, 0, 2)
 	args := make([]interface{}, 0, 4)
 	if rating != nil {
-		setClauses = append(setClauses, "Rating = ?")
+		setClauses = append(setClauses, "score = ?")
 		args = append(args, *rating)
 	}
 	if content != nil {
-		setClauses = append(setClauses, "Content = ?")
+		setClauses = append(setClauses, "review = ?")
 		args = append(args, *content)
 	}
 	if len(setClauses) == 0 {
 		return nil
 	}
-	setClauses = append(setClauses, "Updated_At = CURRENT_TIMESTAMP")
-	query := fmt.Sprintf(`UPDATE Reviews SET %s WHERE Review_Id = ? AND User_Id = ?`, strings.Join(setClauses, ", "))
+	setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
+	query := fmt.Sprintf(`UPDATE ratings SET %s WHERE id = ? AND user_id = ?`, strings.Join(setClauses, ", "))
 	args = append(args, reviewID, userID)
 	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
@@ -316,7 +422,126 @@ func (r *Repository) UpdateReview(ctx context.Context, reviewID, userID int64, r
 
 // DeleteReview removes a review owned by the user
 func (r *Repository) DeleteReview(ctx context.Context, reviewID, userID int64) error {
-	result, err := r.db.ExecContext(ctx, `DELETE FROM Reviews WHERE Review_Id = ? AND User_Id = ?`, reviewID, userID)
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ratings WHERE id = ? AND user_id = ?`, reviewID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetReviewMangaID returns the manga a review belongs to, or 0 when the review does not exist
+func (r *Repository) GetReviewMangaID(ctx context.Context, reviewID int64) (int64, error) {
+	var mangaID int64
+	err := r.db.QueryRowContext(ctx, `
+        SELECT manga_id FROM ratings
+        WHERE id = ? AND review IS NOT NULL AND review <> ''
+    `, reviewID).Scan(&mangaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return mangaID, nil
+}
+
+// GetReviewAuthorID returns the author of a visible review, or 0 when there is no such review
+func (r *Repository) GetReviewAuthorID(ctx context.Context, reviewID int64) (int64, error) {
+	var userID int64
+	err := r.db.QueryRowContext(ctx, `
+        SELECT user_id FROM ratings
+        WHERE id = ? AND review IS NOT NULL AND review <> '' AND Hidden_At IS NULL
+    `, reviewID).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// GetReviewVote returns the user's vote on a review: 1, -1, or 0 when they have not voted
+func (r *Repository) GetReviewVote(ctx context.Context, reviewID, userID int64) (int, error) {
+	var vote int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT Vote FROM Review_Votes WHERE Review_Id = ? AND User_Id = ?
+    `, reviewID, userID).Scan(&vote)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return vote, err
+}
+
+// SetReviewVote stores the user's vote on a review; a vote of 0 removes it
+func (r *Repository) SetReviewVote(ctx context.Context, reviewID, userID int64, vote int) error {
+	if vote == 0 {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM Review_Votes WHERE Review_Id = ? AND User_Id = ?`, reviewID, userID)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO Review_Votes (Review_Id, User_Id, Vote, Created_At)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+        ON CONFLICT(Review_Id, User_Id) DO UPDATE SET Vote = excluded.Vote, Created_At = excluded.Created_At
+    `, reviewID, userID, vote)
+	return err
+}
+
+// CountReviewVotes returns the helpful and unhelpful tallies of a review
+func (r *Repository) CountReviewVotes(ctx context.Context, reviewID int64) (int, int, error) {
+	var helpful, unhelpful int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT
+            COALESCE(SUM(CASE WHEN Vote = 1 THEN 1 ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN Vote = -1 THEN 1 ELSE 0 END), 0)
+        FROM Review_Votes
+        WHERE Review_Id = ?
+    `, reviewID).Scan(&helpful, &unhelpful)
+	return helpful, unhelpful, err
+}
+
+// FlagReview records a user's report. It returns false when the user has
+// already flagged the review.
+func (r *Repository) FlagReview(ctx context.Context, reviewID, userID int64, reason string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+        INSERT INTO Review_Flags (Review_Id, User_Id, Reason, Created_At)
+        SELECT ?, ?, ?, CURRENT_TIMESTAMP
+        WHERE NOT EXISTS (
+            SELECT 1 FROM Review_Flags WHERE Review_Id = ? AND User_Id = ?
+        )
+    `, reviewID, userID, reason, reviewID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// CountReviewFlags returns how many users have flagged a review
+func (r *Repository) CountReviewFlags(ctx context.Context, reviewID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM Review_Flags WHERE Review_Id = ?`, reviewID).Scan(&count)
+	return count, err
+}
+
+// SetReviewHidden hides or restores a review. Hidden reviews stay in the
+// database but are left out of listings and stats.
+func (r *Repository) SetReviewHidden(ctx context.Context, reviewID int64, hidden bool) error {
+	query := `UPDATE ratings SET Hidden_At = NULL WHERE id = ?`
+	if hidden {
+		query = `UPDATE ratings SET Hidden_At = COALESCE(Hidden_At, CURRENT_TIMESTAMP) WHERE id = ?`
+	}
+	result, err := r.db.ExecContext(ctx, query, reviewID)
 	if err != nil {
 		return err
 	}
@@ -2,10 +2,14 @@ package comment
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/ngocan-dev/mangahub/backend/domain/rating"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	"github.com/ngocan-dev/mangahub/backend/internal/security"
 )
 
@@ -13,9 +17,15 @@ var (
 	ErrMangaNotFound         = errors.New("manga not found")
 	ErrMangaNotCompleted     = errors.New("manga must be in completed list to write review")
 	ErrReviewAlreadyExists   = errors.New("review already exists for this manga")
-	ErrInvalidReviewRating   = errors.New("rating must be between 1 and 10")
+	ErrInvalidReviewRating   = errors.New("rating must be between 1 and 5")
 	ErrReviewContentTooShort = errors.New("review content must be at least 10 characters")
-	ErrReviewContentTooLong  = errors.New("review content must not exceed 5000 characters")
+	ErrReviewContentTooLong  = errors.New("review content is too long")
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrReviewForbidden       = errors.New("only the review author can modify this review")
+	ErrReviewAlreadyFlagged  = errors.New("you have already flagged this review")
+	ErrInvalidFlagReason     = errors.New("flag reason must be between 1 and 500 characters")
+	ErrInvalidVote           = errors.New("vote must be helpful or unhelpful")
+	ErrOwnReviewVote         = errors.New("you cannot vote on your own review")
 	ErrDatabaseError         = errors.New("database error")
 )
 
@@ -34,12 +44,18 @@ type ActivityRecorder interface {
 	RecordActivity(ctx context.Context, userID int64, activityType string, mangaID *int64, payload map[string]interface{}) error
 }
 
+// RatingNotifier is told when a review changes a manga's rating stats
+type RatingNotifier interface {
+	RatingChanged(ctx context.Context, mangaID int64)
+}
+
 // Service handles review use cases
 type Service struct {
-	repo          *Repository
-	mangaService  MangaGetter
-	ratingService RatingSetter
-	activityLog   ActivityRecorder
+	repo           *Repository
+	mangaService   MangaGetter
+	ratingService  RatingSetter
+	activityLog    ActivityRecorder
+	ratingNotifier RatingNotifier
 }
 
 // NewService builds a review service
@@ -47,28 +63,36 @@ func NewService(repo *Repository, mangaService MangaGetter, ratingService Rating
 	return &Service{repo: repo, mangaService: mangaService, ratingService: ratingService}
 }
 
+// SetReadReplica routes review listings to a read replica
+func (s *Service) SetReadReplica(replica *sql.DB) {
+	s.repo.SetReader(replica)
+}
+
 // SetActivityRecorder configures the optional activity recorder
 func (s *Service) SetActivityRecorder(recorder ActivityRecorder) {
 	s.activityLog = recorder
 }
 
+// SetRatingNotifier configures who is told about rating stat changes, e.g. to
+// invalidate cached manga details
+func (s *Service) SetRatingNotifier(n RatingNotifier) {
+	s.ratingNotifier = n
+}
+
+func (s *Service) notifyRatingChanged(ctx context.Context, mangaID int64) {
+	if s.ratingNotifier != nil {
+		s.ratingNotifier.RatingChanged(ctx, mangaID)
+	}
+}
+
 // CreateReview stores a new review after validations
 func (s *Service) CreateReview(ctx context.Context, userID, mangaID int64, req CreateReviewRequest) (*CreateReviewResponse, error) {
 	if err := security.ValidateReviewRating(req.Rating); err != nil {
 		return nil, ErrInvalidReviewRating
 	}
 
-	if err := security.ValidateReviewContent(req.Content); err != nil {
-		switch {
-		case errors.Is(err, security.ErrInputTooShort):
-			return nil, ErrReviewContentTooShort
-		case errors.Is(err, security.ErrInputTooLong):
-			return nil, ErrReviewContentTooLong
-		case errors.Is(err, security.ErrContainsSQLInjection):
-			return nil, fmt.Errorf("invalid input: %w", err)
-		default:
-			return nil, fmt.Errorf("validation error: %w", err)
-		}
+	if err := validateReviewContent(req.Content); err != nil {
+		return nil, err
 	}
 
 	sanitized := security.SanitizeReviewContent(req.Content)
@@ -89,15 +113,10 @@ func (s *Service) CreateReview(ctx context.Context, userID, mangaID int64, req C
 		return nil, ErrMangaNotCompleted
 	}
 
-	existing, err := s.repo.GetReviewByUserAndManga(ctx, userID, mangaID)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
-	}
-	if existing != nil {
-		return nil, ErrReviewAlreadyExists
-	}
-
 	if _, err := s.repo.CreateReview(ctx, userID, mangaID, req.Rating, sanitized); err != nil {
+		if errors.Is(err, ErrReviewAlreadyExists) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 
@@ -106,6 +125,8 @@ func (s *Service) CreateReview(ctx context.Context, userID, mangaID int64, req C
 			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 		}
 	}
+	s.notifyRatingChanged(ctx, mangaID)
+
 	review, err := s.repo.GetReviewByUserAndManga(ctx, userID, mangaID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
@@ -123,11 +144,129 @@ func (s *Service) CreateReview(ctx context.Context, userID, mangaID int64, req C
 	}, nil
 }
 
-// GetReviews returns paginated review list
-func (s *Service) GetReviews(ctx context.Context, mangaID int64, page, limit int, sortBy string) (*GetReviewsResponse, error) {
-	page, limit = normalizePagination(page, limit)
+// UpdateReview edits the rating and/or content of a review owned by the user
+func (s *Service) UpdateReview(ctx context.Context, userID, reviewID int64, req UpdateReviewRequest) (*UpdateReviewResponse, error) {
+	if req.Rating != nil {
+		if err := security.ValidateReviewRating(*req.Rating); err != nil {
+			return nil, ErrInvalidReviewRating
+		}
+	}
+
+	var content *string
+	if req.Content != nil {
+		if err := validateReviewContent(*req.Content); err != nil {
+			return nil, err
+		}
+		sanitized := security.SanitizeReviewContent(*req.Content)
+		content = &sanitized
+	}
+
+	review, err := s.getOwnedReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Rating == nil && content == nil {
+		return &UpdateReviewResponse{
+			Message: "review unchanged",
+			Review:  review,
+		}, nil
+	}
+
+	if err := s.repo.UpdateReview(ctx, reviewID, userID, req.Rating, content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	if req.Rating != nil && s.ratingService != nil {
+		if _, err := s.ratingService.SetRating(ctx, userID, review.MangaID, *req.Rating); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+	}
+	if req.Rating != nil {
+		s.notifyRatingChanged(ctx, review.MangaID)
+	}
+
+	updated, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return &UpdateReviewResponse{
+		Message: "review updated successfully",
+		Review:  updated,
+	}, nil
+}
+
+// DeleteReview removes a review owned by the user and returns the manga's recomputed stats
+func (s *Service) DeleteReview(ctx context.Context, userID, reviewID int64) (*DeleteReviewResponse, error) {
+	review, err := s.getOwnedReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteReview(ctx, reviewID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	s.notifyRatingChanged(ctx, review.MangaID)
+
+	stats, err := s.repo.GetReviewStats(ctx, review.MangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	return &DeleteReviewResponse{
+		Message: "review deleted successfully",
+		Stats:   stats,
+	}, nil
+}
+
+// getOwnedReview loads a review and checks that userID is its author
+func (s *Service) getOwnedReview(ctx context.Context, userID, reviewID int64) (*Review, error) {
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	if review.UserID != userID {
+		return nil, ErrReviewForbidden
+	}
+	return review, nil
+}
+
+func validateReviewContent(content string) error {
+	if err := security.ValidateReviewContent(content); err != nil {
+		switch {
+		case errors.Is(err, security.ErrInputTooShort):
+			return ErrReviewContentTooShort
+		case errors.Is(err, security.ErrInputTooLong):
+			return fmt.Errorf("%w: at most %d characters", ErrReviewContentTooLong, security.ReviewLengthLimit())
+		case errors.Is(err, security.ErrContainsSQLInjection):
+			return fmt.Errorf("invalid input: %w", err)
+		default:
+			return fmt.Errorf("validation error: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetReviews returns paginated review list. viewerID, when set, adds the
+// viewer's own vote to each review.
+func (s *Service) GetReviews(ctx context.Context, mangaID int64, viewerID *int64, page, limit int, sortBy string) (*GetReviewsResponse, error) {
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
 
-	reviews, total, err := s.repo.GetReviewsByMangaID(ctx, mangaID, page, limit, sortBy)
+	var viewer int64
+	if viewerID != nil {
+		viewer = *viewerID
+	}
+	reviews, total, err := s.repo.GetReviewsByMangaID(ctx, mangaID, viewer, page, limit, sortBy)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
@@ -136,6 +275,11 @@ func (s *Service) GetReviews(ctx context.Context, mangaID int64, page, limit int
 		reviews = []Review{}
 	}
 
+	stats, err := s.repo.GetReviewStats(ctx, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
 	return &GetReviewsResponse{
 		Data: reviews,
 		Meta: ReviewsMeta{
@@ -143,18 +287,139 @@ func (s *Service) GetReviews(ctx context.Context, mangaID int64, page, limit int
 			Limit: limit,
 			Total: total,
 		},
+		Stats: stats,
+	}, nil
+}
+
+// GetReviewStats returns the average, total and rating histogram of a manga's reviews
+func (s *Service) GetReviewStats(ctx context.Context, mangaID int64) (*ReviewStats, error) {
+	stats, err := s.repo.GetReviewStats(ctx, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return stats, nil
+}
+
+// VoteReview records a helpful or unhelpful vote. Each user holds one vote per
+// review: repeating the same vote withdraws it, the other vote replaces it.
+func (s *Service) VoteReview(ctx context.Context, userID, reviewID int64, req VoteReviewRequest) (*VoteReviewResponse, error) {
+	vote, ok := voteValues[strings.ToLower(strings.TrimSpace(req.Vote))]
+	if !ok {
+		return nil, ErrInvalidVote
+	}
+
+	authorID, err := s.repo.GetReviewAuthorID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if authorID == 0 {
+		return nil, ErrReviewNotFound
+	}
+	if authorID == userID {
+		return nil, ErrOwnReviewVote
+	}
+
+	current, err := s.repo.GetReviewVote(ctx, reviewID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	message := "vote recorded"
+	if current == vote {
+		vote = 0
+		message = "vote removed"
+	}
+	if err := s.repo.SetReviewVote(ctx, reviewID, userID, vote); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	helpful, unhelpful, err := s.repo.CountReviewVotes(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &VoteReviewResponse{
+		Message:        message,
+		ReviewID:       reviewID,
+		HelpfulVotes:   helpful,
+		UnhelpfulVotes: unhelpful,
+		MyVote:         voteName(vote),
 	}, nil
 }
 
-func normalizePagination(page, limit int) (int, int) {
-	if page < 1 {
-		page = 1
+// maxFlagReasonLength caps the reason stored with a review flag
+const maxFlagReasonLength = 500
+
+// FlagReview reports a review for moderation. Each user may flag a review once.
+func (s *Service) FlagReview(ctx context.Context, userID, reviewID int64, req FlagReviewRequest) (*FlagReviewResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxFlagReasonLength {
+		return nil, ErrInvalidFlagReason
+	}
+	reason = security.SanitizeString(reason)
+
+	mangaID, err := s.repo.GetReviewMangaID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if mangaID == 0 {
+		return nil, ErrReviewNotFound
+	}
+
+	created, err := s.repo.FlagReview(ctx, reviewID, userID, reason)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !created {
+		return nil, ErrReviewAlreadyFlagged
+	}
+
+	return &FlagReviewResponse{Message: "review flagged for moderation"}, nil
+}
+
+// HideReview removes a review from listings and rating stats without deleting it
+func (s *Service) HideReview(ctx context.Context, reviewID int64) (*ModerateReviewResponse, error) {
+	return s.setReviewHidden(ctx, reviewID, true)
+}
+
+// UnhideReview restores a hidden review
+func (s *Service) UnhideReview(ctx context.Context, reviewID int64) (*ModerateReviewResponse, error) {
+	return s.setReviewHidden(ctx, reviewID, false)
+}
+
+func (s *Service) setReviewHidden(ctx context.Context, reviewID int64, hidden bool) (*ModerateReviewResponse, error) {
+	mangaID, err := s.repo.GetReviewMangaID(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if mangaID == 0 {
+		return nil, ErrReviewNotFound
+	}
+
+	if err := s.repo.SetReviewHidden(ctx, reviewID, hidden); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	s.notifyRatingChanged(ctx, mangaID)
+
+	flags, err := s.repo.CountReviewFlags(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
-	if limit < 1 {
-		limit = 20
+	stats, err := s.repo.GetReviewStats(ctx, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
-	if limit > 100 {
-		limit = 100
+
+	message := "review restored"
+	if hidden {
+		message = "review hidden"
 	}
-	return page, limit
+	return &ModerateReviewResponse{
+		Message:   message,
+		ReviewID:  reviewID,
+		Hidden:    hidden,
+		FlagCount: flags,
+		Stats:     stats,
+	}, nil
 }
@@ -0,0 +1,417 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupModerationService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL, avatar_url TEXT);
+    CREATE TABLE ratings (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+        review TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        Hidden_At DATETIME
+    );
+    CREATE TABLE Review_Flags (
+        Flag_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+        Review_Id INTEGER NOT NULL,
+        User_Id INTEGER NOT NULL,
+        Reason TEXT NOT NULL,
+        Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (Review_Id, User_Id)
+    );
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob'), (3, 'spammer');
+    INSERT INTO ratings (id, user_id, manga_id, score, review) VALUES
+        (1, 1, 10, 4, 'A thoughtful review'),
+        (2, 2, 10, 5, 'Loved every chapter'),
+        (3, 3, 10, 1, 'buy cheap followers at spam.example');
+    `
+	migration, err := os.ReadFile("../../db/migrations/027_review_votes.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(schema + string(migration)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(NewRepository(db), nil, nil)
+}
+
+func TestHiddenReviewsDoNotAffectAverage(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	for _, userID := range []int64{1, 2} {
+		if _, err := svc.FlagReview(ctx, userID, 3, FlagReviewRequest{Reason: "spam"}); err != nil {
+			t.Fatalf("FlagReview returned error: %v", err)
+		}
+	}
+	if _, err := svc.FlagReview(ctx, 1, 3, FlagReviewRequest{Reason: "spam"}); !errors.Is(err, ErrReviewAlreadyFlagged) {
+		t.Fatalf("expected ErrReviewAlreadyFlagged, got %v", err)
+	}
+
+	resp, err := svc.HideReview(ctx, 3)
+	if err != nil {
+		t.Fatalf("HideReview returned error: %v", err)
+	}
+	if resp.FlagCount != 2 {
+		t.Fatalf("expected 2 flags, got %d", resp.FlagCount)
+	}
+	if resp.Stats.TotalReviews != 2 || resp.Stats.AverageRating != 4.5 {
+		t.Fatalf("expected hidden review to be excluded from stats, got %+v", resp.Stats)
+	}
+
+	list, err := svc.GetReviews(ctx, 10, nil, 1, 20, "recent")
+	if err != nil {
+		t.Fatalf("GetReviews returned error: %v", err)
+	}
+	if list.Meta.Total != 2 {
+		t.Fatalf("expected 2 visible reviews, got %d", list.Meta.Total)
+	}
+	for _, review := range list.Data {
+		if review.ReviewID == 3 {
+			t.Fatalf("hidden review was listed")
+		}
+	}
+
+	restored, err := svc.UnhideReview(ctx, 3)
+	if err != nil {
+		t.Fatalf("UnhideReview returned error: %v", err)
+	}
+	if restored.Stats.TotalReviews != 3 || restored.Stats.AverageRating != 3.33 {
+		t.Fatalf("expected restored review to count again, got %+v", restored.Stats)
+	}
+}
+
+func TestReviewStatsDistribution(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	db := svc.repo.db
+	if _, err := db.Exec(`
+        INSERT INTO users (id, username) VALUES (4, 'carol'), (5, 'dave');
+        INSERT INTO ratings (user_id, manga_id, score, review) VALUES
+            (4, 10, 5, 'Best series this year'),
+            (5, 10, 4, 'Solid but slow start'),
+            (1, 11, 2, 'Different manga entirely'),
+            (2, 10, 3, '');
+    `); err != nil {
+		t.Fatalf("failed to seed reviews: %v", err)
+	}
+
+	resp, err := svc.GetReviews(ctx, 10, nil, 1, 20, "recent")
+	if err != nil {
+		t.Fatalf("GetReviews returned error: %v", err)
+	}
+	stats := resp.Stats
+	if stats == nil {
+		t.Fatal("expected review stats in the listing")
+	}
+
+	// Scores 4, 5, 1, 5, 4; the rating without review text is not a review
+	want := map[int]int{1: 1, 2: 0, 3: 0, 4: 2, 5: 2}
+	if len(stats.Distribution) != len(want) {
+		t.Fatalf("expected %d buckets, got %v", len(want), stats.Distribution)
+	}
+	for rating, count := range want {
+		if stats.Distribution[rating] != count {
+			t.Fatalf("rating %d: expected %d reviews, got %d (%v)", rating, count, stats.Distribution[rating], stats.Distribution)
+		}
+	}
+	if stats.TotalReviews != 5 || stats.AverageRating != 3.8 {
+		t.Fatalf("expected 5 reviews averaging 3.8, got %+v", stats)
+	}
+
+	empty, err := svc.GetReviewStats(ctx, 99)
+	if err != nil {
+		t.Fatalf("GetReviewStats returned error: %v", err)
+	}
+	if empty.TotalReviews != 0 || len(empty.Distribution) != len(want) || empty.Distribution[10] != 0 {
+		t.Fatalf("expected an all-zero histogram, got %+v", empty)
+	}
+}
+
+func TestVoteReviewTogglesAndRejectsOwnReview(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	if _, err := svc.VoteReview(ctx, 1, 1, VoteReviewRequest{Vote: VoteHelpful}); !errors.Is(err, ErrOwnReviewVote) {
+		t.Fatalf("expected ErrOwnReviewVote, got %v", err)
+	}
+	if _, err := svc.VoteReview(ctx, 2, 1, VoteReviewRequest{Vote: "love"}); !errors.Is(err, ErrInvalidVote) {
+		t.Fatalf("expected ErrInvalidVote, got %v", err)
+	}
+	if _, err := svc.VoteReview(ctx, 2, 99, VoteReviewRequest{Vote: VoteHelpful}); !errors.Is(err, ErrReviewNotFound) {
+		t.Fatalf("expected ErrReviewNotFound, got %v", err)
+	}
+
+	steps := []struct {
+		vote      string
+		helpful   int
+		unhelpful int
+		mine      string
+	}{
+		{VoteHelpful, 1, 0, VoteHelpful},
+		{VoteUnhelpful, 0, 1, VoteUnhelpful},
+		{VoteUnhelpful, 0, 0, ""},
+		{VoteHelpful, 1, 0, VoteHelpful},
+	}
+	for i, step := range steps {
+		resp, err := svc.VoteReview(ctx, 2, 1, VoteReviewRequest{Vote: step.vote})
+		if err != nil {
+			t.Fatalf("step %d: VoteReview returned error: %v", i, err)
+		}
+		if resp.HelpfulVotes != step.helpful || resp.UnhelpfulVotes != step.unhelpful || resp.MyVote != step.mine {
+			t.Fatalf("step %d: expected %d/%d mine=%q, got %+v", i, step.helpful, step.unhelpful, step.mine, resp)
+		}
+	}
+
+	viewer := int64(2)
+	list, err := svc.GetReviews(ctx, 10, &viewer, 1, 20, "recent")
+	if err != nil {
+		t.Fatalf("GetReviews returned error: %v", err)
+	}
+	for _, review := range list.Data {
+		if review.ReviewID == 1 && (review.HelpfulVotes != 1 || review.MyVote != VoteHelpful) {
+			t.Fatalf("expected the viewer's helpful vote on review 1, got %+v", review)
+		}
+		if review.ReviewID != 1 && review.MyVote != "" {
+			t.Fatalf("expected no vote on review %d, got %q", review.ReviewID, review.MyVote)
+		}
+	}
+}
+
+func TestHelpfulnessSortUsesNetVotes(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	if _, err := svc.repo.db.Exec(`INSERT INTO users (id, username) VALUES (4, 'carol'), (5, 'dave')`); err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	// Review 3 has the lowest rating but the best net votes; review 2 has the
+	// highest rating but is voted down
+	votes := []struct {
+		userID, reviewID int64
+		vote             string
+	}{
+		{1, 3, VoteHelpful},
+		{2, 3, VoteHelpful},
+		{4, 3, VoteUnhelpful},
+		{4, 1, VoteHelpful},
+		{1, 2, VoteUnhelpful},
+		{5, 2, VoteUnhelpful},
+	}
+	for _, v := range votes {
+		if _, err := svc.VoteReview(ctx, v.userID, v.reviewID, VoteReviewRequest{Vote: v.vote}); err != nil {
+			t.Fatalf("VoteReview(%d, %d) returned error: %v", v.userID, v.reviewID, err)
+		}
+	}
+
+	list, err := svc.GetReviews(ctx, 10, nil, 1, 20, "helpfulness")
+	if err != nil {
+		t.Fatalf("GetReviews returned error: %v", err)
+	}
+	want := []int64{3, 1, 2}
+	if len(list.Data) != len(want) {
+		t.Fatalf("expected %d reviews, got %d", len(want), len(list.Data))
+	}
+	for i, id := range want {
+		if list.Data[i].ReviewID != id {
+			t.Fatalf("position %d: expected review %d, got %d", i, id, list.Data[i].ReviewID)
+		}
+	}
+}
+
+type knownManga struct{}
+
+func (knownManga) Exists(ctx context.Context, mangaID int64) (bool, error) {
+	return true, nil
+}
+
+// setupReviewWriteService uses a file database so concurrent writers get
+// their own connections
+func setupReviewWriteService(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "reviews.db")
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL, avatar_url TEXT);
+    CREATE TABLE ratings (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+        review TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        Hidden_At DATETIME
+    );
+    CREATE TABLE user_library (user_id INTEGER NOT NULL, manga_id INTEGER NOT NULL, status TEXT NOT NULL);
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob');
+    INSERT INTO user_library (user_id, manga_id, status) VALUES (1, 10, 'completed'), (2, 10, 'completed');
+    `
+	migration, err := os.ReadFile("../../db/migrations/033_ratings_unique_review.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(schema + string(migration)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(NewRepository(db), knownManga{}, nil), db
+}
+
+func TestConcurrentCreateReviewStoresOneReview(t *testing.T) {
+	svc, db := setupReviewWriteService(t)
+	ctx := context.Background()
+
+	start := make(chan struct{})
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = svc.CreateReview(ctx, 1, 10, CreateReviewRequest{
+				Rating:  4,
+				Content: fmt.Sprintf("Attempt %d at reviewing this manga", i),
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created, duplicates := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, ErrReviewAlreadyExists):
+			duplicates++
+		default:
+			t.Fatalf("CreateReview returned unexpected error: %v", err)
+		}
+	}
+	if created != 1 || duplicates != 1 {
+		t.Fatalf("expected exactly one review to be created, got %d created and %d duplicates", created, duplicates)
+	}
+
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ratings WHERE user_id = 1 AND manga_id = 10`).Scan(&rows); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	if rows != 1 {
+		t.Fatalf("expected one stored review, got %d", rows)
+	}
+}
+
+func TestCreateReviewFillsInScoreWithoutText(t *testing.T) {
+	svc, db := setupReviewWriteService(t)
+	ctx := context.Background()
+	if _, err := db.Exec(`INSERT INTO ratings (id, user_id, manga_id, score) VALUES (7, 2, 10, 3)`); err != nil {
+		t.Fatalf("failed to seed rating: %v", err)
+	}
+
+	resp, err := svc.CreateReview(ctx, 2, 10, CreateReviewRequest{Rating: 5, Content: "Better on a second read"})
+	if err != nil {
+		t.Fatalf("CreateReview returned error: %v", err)
+	}
+	if resp.Review.ReviewID != 7 || resp.Review.Rating != 5 || resp.Review.Username != "bob" {
+		t.Fatalf("expected the existing score to become the review, got %+v", resp.Review)
+	}
+
+	if _, err := svc.CreateReview(ctx, 2, 10, CreateReviewRequest{Rating: 5, Content: "Trying to review it twice"}); !errors.Is(err, ErrReviewAlreadyExists) {
+		t.Fatalf("expected ErrReviewAlreadyExists, got %v", err)
+	}
+}
+
+func TestUpdateReviewEditsOwnReview(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	rating := 5
+	content := "A thoughtful review, revised after a reread"
+	resp, err := svc.UpdateReview(ctx, 1, 1, UpdateReviewRequest{Rating: &rating, Content: &content})
+	if err != nil {
+		t.Fatalf("UpdateReview returned error: %v", err)
+	}
+	if resp.Review.Rating != 5 || !strings.Contains(resp.Review.Content, content) {
+		t.Fatalf("expected the edit to be stored, got %+v", resp.Review)
+	}
+
+	if _, err := svc.UpdateReview(ctx, 2, 1, UpdateReviewRequest{Content: &content}); !errors.Is(err, ErrReviewForbidden) {
+		t.Fatalf("expected ErrReviewForbidden editing another user's review, got %v", err)
+	}
+	if _, err := svc.UpdateReview(ctx, 1, 99, UpdateReviewRequest{Content: &content}); !errors.Is(err, ErrReviewNotFound) {
+		t.Fatalf("expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestDeleteReviewRemovesOwnReview(t *testing.T) {
+	svc := setupModerationService(t)
+	ctx := context.Background()
+
+	if _, err := svc.DeleteReview(ctx, 1, 2); !errors.Is(err, ErrReviewForbidden) {
+		t.Fatalf("expected ErrReviewForbidden deleting another user's review, got %v", err)
+	}
+
+	resp, err := svc.DeleteReview(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("DeleteReview returned error: %v", err)
+	}
+	if resp.Stats.TotalReviews != 2 {
+		t.Fatalf("expected 2 reviews left, got %d", resp.Stats.TotalReviews)
+	}
+
+	if _, err := svc.DeleteReview(ctx, 2, 2); !errors.Is(err, ErrReviewNotFound) {
+		t.Fatalf("expected ErrReviewNotFound for a deleted review, got %v", err)
+	}
+}
+
+func TestReviewRatingOutsideScoreRangeIsRejected(t *testing.T) {
+	svc, _ := setupReviewWriteService(t)
+	ctx := context.Background()
+
+	// ratings.score only accepts 1-5, so 7 must fail validation rather than the insert
+	if _, err := svc.CreateReview(ctx, 1, 10, CreateReviewRequest{Rating: 7, Content: "Seven out of ten for this one"}); !errors.Is(err, ErrInvalidReviewRating) {
+		t.Fatalf("expected ErrInvalidReviewRating creating a review rated 7, got %v", err)
+	}
+
+	resp, err := svc.CreateReview(ctx, 1, 10, CreateReviewRequest{Rating: 5, Content: "Top marks for this one"})
+	if err != nil {
+		t.Fatalf("CreateReview returned error: %v", err)
+	}
+	rating := 7
+	if _, err := svc.UpdateReview(ctx, 1, resp.Review.ReviewID, UpdateReviewRequest{Rating: &rating}); !errors.Is(err, ErrInvalidReviewRating) {
+		t.Fatalf("expected ErrInvalidReviewRating editing a review to 7, got %v", err)
+	}
+}
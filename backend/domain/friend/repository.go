@@ -7,23 +7,39 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
 )
 
+// FriendIDColumn is the friends column holding the other user of a row
+const FriendIDColumn = "friend_user_id"
+
+// AcceptedFriendIDsQuery selects the ids of a user's accepted friends from
+// rows in either direction. It takes the user id twice. Other domains reading
+// the friends table should build on it rather than spell out the columns.
+const AcceptedFriendIDsQuery = `
+        SELECT ` + FriendIDColumn + ` FROM friends WHERE user_id = ? AND status = 'accepted'
+        UNION
+        SELECT user_id FROM friends WHERE ` + FriendIDColumn + ` = ? AND status = 'accepted'`
+
 // Repository handles friend-related persistence
 type Repository struct {
 	db                *sql.DB
 	friendIDColumn    string
 	friendHasStatus   bool
+	mysqlUpserts      bool
 	friendSchemaOnce  sync.Once
 	friendSchemaError error
 }
 
 // NewRepository builds a friend repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db, friendIDColumn: "friend_user_id"}
+	return &Repository{db: db, friendIDColumn: FriendIDColumn}
 }
 
-// FindUsersByQuery searches users by username or email (case-insensitive) excluding self and existing friends.
+// FindUsersByQuery searches users by username or email (case-insensitive) excluding self and users who blocked the searcher.
 func (r *Repository) FindUsersByQuery(
 	ctx context.Context,
 	userID int64,
@@ -39,12 +55,13 @@ SELECT
 FROM users
 WHERE
 	id != ?
+	AND id NOT IN (SELECT Blocker_Id FROM Blocked_Users WHERE Blocked_Id = ?)
 	AND (
 		LOWER(username) LIKE LOWER(?)
 		OR LOWER(email) LIKE LOWER(?)
 	)
 LIMIT 20
-	`, userID, "%"+query+"%", "%"+query+"%")
+	`, userID, userID, "%"+query+"%", "%"+query+"%")
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +73,7 @@ LIMIT 20
 		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.AvatarURL); err != nil {
 			return nil, err
 		}
+		u.AvatarURL = user.AvatarURLOrDefault(u.ID, u.AvatarURL)
 		users = append(users, u)
 	}
 
@@ -70,15 +88,16 @@ func (r *Repository) FindUserByID(ctx context.Context, id int64) (*UserSummary,
         WHERE id = ?
     `, id)
 
-	var user UserSummary
-	if err := row.Scan(&user.ID, &user.Username, &user.Email, &user.AvatarURL); err != nil {
+	var u UserSummary
+	if err := row.Scan(&u.ID, &u.Username, &u.Email, &u.AvatarURL); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
+	u.AvatarURL = user.AvatarURLOrDefault(u.ID, u.AvatarURL)
 
-	return &user, nil
+	return &u, nil
 }
 
 // AreFriends returns true when two users have a bidirectional link
@@ -142,17 +161,15 @@ func (r *Repository) CreateFriendRequest(ctx context.Context, requesterID, targe
 		return nil, err
 	}
 
-	insertStmt := fmt.Sprintf(`
-        INSERT INTO friends (user_id, %s, status)
-        VALUES (?, ?, 'pending')
-        ON DUPLICATE KEY UPDATE status = VALUES(status)
-    `, r.friendIDColumn)
-	res, err := r.db.ExecContext(ctx, insertStmt, requesterID, targetID)
-	if err != nil {
+	if _, err := r.db.ExecContext(ctx, r.friendUpsertQuery(), requesterID, targetID, "pending"); err != nil {
 		return nil, err
 	}
-	requestID, err := res.LastInsertId()
-	if err != nil {
+
+	// LastInsertId is unreliable when the upsert updated an existing row
+	var requestID int64
+	if err := r.db.QueryRowContext(ctx, `
+        SELECT id FROM friends WHERE user_id = ? AND `+r.friendIDColumn+` = ?
+    `, requesterID, targetID).Scan(&requestID); err != nil {
 		return nil, err
 	}
 	return r.GetFriendRequestByID(ctx, requestID)
@@ -222,16 +239,11 @@ func (r *Repository) CreateFriendshipBidirectional(ctx context.Context, userID,
 		}
 	}()
 
-	insertStmt := fmt.Sprintf(`
-        INSERT INTO friends (user_id, %s, status)
-        VALUES (?, ?, 'accepted')
-        ON DUPLICATE KEY UPDATE status = VALUES(status)
-    `, r.friendIDColumn)
-
-	if _, err = tx.ExecContext(ctx, insertStmt, userID, friendID); err != nil {
+	insertStmt := r.friendUpsertQuery()
+	if _, err = tx.ExecContext(ctx, insertStmt, userID, friendID, "accepted"); err != nil {
 		return err
 	}
-	if _, err = tx.ExecContext(ctx, insertStmt, friendID, userID); err != nil {
+	if _, err = tx.ExecContext(ctx, insertStmt, friendID, userID, "accepted"); err != nil {
 		return err
 	}
 	return nil
@@ -271,22 +283,130 @@ func (r *Repository) AcceptFriendRequestTx(ctx context.Context, requestID, fromU
 		return sql.ErrNoRows
 	}
 
-	insertStmt := fmt.Sprintf(`
-        INSERT INTO friends (user_id, %s, status)
-        VALUES (?, ?, 'accepted')
-        ON DUPLICATE KEY UPDATE status = VALUES(status)
-    `, r.friendIDColumn)
+	insertStmt := r.friendUpsertQuery()
+	if _, err = tx.ExecContext(ctx, insertStmt, fromUserID, toUserID, "accepted"); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, insertStmt, toUserID, fromUserID, "accepted"); err != nil {
+		return err
+	}
+
+	return nil
+}
 
-	if _, err = tx.ExecContext(ctx, insertStmt, fromUserID, toUserID); err != nil {
+// DeleteFriendshipBidirectional removes every friends row between two users
+// in one transaction. It returns sql.ErrNoRows when they are not friends.
+func (r *Repository) DeleteFriendshipBidirectional(ctx context.Context, userID, friendID int64) error {
+	if err := r.ensureFriendSchema(ctx); err != nil {
 		return err
 	}
-	if _, err = tx.ExecContext(ctx, insertStmt, toUserID, fromUserID); err != nil {
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	pairFilter := fmt.Sprintf(`((user_id = ? AND %[1]s = ?) OR (user_id = ? AND %[1]s = ?))`, r.friendIDColumn)
+
+	var accepted int
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM friends
+        WHERE status = 'accepted' AND `+pairFilter, userID, friendID, friendID, userID).Scan(&accepted)
+	if err != nil {
+		return err
+	}
+	if accepted == 0 {
+		err = sql.ErrNoRows
+		return err
+	}
+
+	// Stale pending/rejected rows go too so a new request can be sent later
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM friends
+        WHERE `+pairFilter, userID, friendID, friendID, userID)
+	return err
+}
+
+// BlockUser records a block and removes any friendship or pending request
+// between the two users in one transaction.
+func (r *Repository) BlockUser(ctx context.Context, blockerID, blockedID int64) error {
+	if err := r.ensureFriendSchema(ctx); err != nil {
 		return err
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+        DELETE FROM friends
+        WHERE (user_id = ? AND %[1]s = ?) OR (user_id = ? AND %[1]s = ?)
+    `, r.friendIDColumn), blockerID, blockedID, blockedID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	var existing int
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM Blocked_Users WHERE Blocker_Id = ? AND Blocked_Id = ?
+    `, blockerID, blockedID).Scan(&existing)
+	if err != nil || existing > 0 {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO Blocked_Users (Blocker_Id, Blocked_Id)
+        VALUES (?, ?)
+    `, blockerID, blockedID)
+	return err
+}
+
+// UnblockUser removes a block. It returns sql.ErrNoRows when none exists.
+func (r *Repository) UnblockUser(ctx context.Context, blockerID, blockedID int64) error {
+	res, err := r.db.ExecContext(ctx, `
+        DELETE FROM Blocked_Users WHERE Blocker_Id = ? AND Blocked_Id = ?
+    `, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
+// IsBlocked reports whether either user has blocked the other.
+func (r *Repository) IsBlocked(ctx context.Context, userA, userB int64) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM Blocked_Users
+        WHERE (Blocker_Id = ? AND Blocked_Id = ?) OR (Blocker_Id = ? AND Blocked_Id = ?)
+    `, userA, userB, userB, userA).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // ListFriends returns accepted friends with basic profile data.
 func (r *Repository) ListFriends(ctx context.Context, userID int64) ([]UserSummary, error) {
 	if err := r.ensureFriendSchema(ctx); err != nil {
@@ -405,17 +525,37 @@ func (r *Repository) ensureFriendSchema(ctx context.Context) error {
 		if _, err := r.db.ExecContext(ctx, `SELECT status FROM friends LIMIT 0`); err == nil {
 			r.friendHasStatus = true
 		}
+
+		_, r.mysqlUpserts = r.db.Driver().(*mysql.MySQLDriver)
 	})
 
 	return r.friendSchemaError
 }
 
+// friendUpsertQuery inserts a friends row for (user_id, friend, status),
+// overwriting the status of an existing row for the pair. MySQL and SQLite
+// spell upserts differently, so the syntax follows the driver.
+func (r *Repository) friendUpsertQuery() string {
+	if r.mysqlUpserts {
+		return fmt.Sprintf(`
+        INSERT INTO friends (user_id, %s, status)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE status = VALUES(status)
+    `, r.friendIDColumn)
+	}
+	return fmt.Sprintf(`
+        INSERT INTO friends (user_id, %s, status)
+        VALUES (?, ?, ?)
+        ON CONFLICT(user_id, %s) DO UPDATE SET status = excluded.status
+    `, r.friendIDColumn, r.friendIDColumn)
+}
+
 func (r *Repository) detectFriendIDColumn(ctx context.Context) error {
-	query := `SELECT friend_user_id FROM friends LIMIT 0`
+	query := `SELECT ` + FriendIDColumn + ` FROM friends LIMIT 0`
 	if _, err := r.db.ExecContext(ctx, query); err != nil {
-		return fmt.Errorf("friends table must have friend_user_id column")
+		return fmt.Errorf("friends table must have %s column", FriendIDColumn)
 	}
-	r.friendIDColumn = "friend_user_id"
+	r.friendIDColumn = FriendIDColumn
 	return nil
 }
 
@@ -437,6 +577,7 @@ func scanUserSummaries(rows *sql.Rows) ([]UserSummary, error) {
 		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.AvatarURL); err != nil {
 			return nil, err
 		}
+		u.AvatarURL = user.AvatarURLOrDefault(u.ID, u.AvatarURL)
 		friends = append(friends, u)
 	}
 	return friends, rows.Err()
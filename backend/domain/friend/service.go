@@ -21,21 +21,31 @@ var (
 	ErrBlocked          = errors.New("friendship blocked")
 	ErrNoPendingRequest = errors.New("no pending friend request")
 	ErrInvalidUsername  = errors.New("invalid username")
+	ErrNotFriends       = errors.New("not friends")
+	ErrCannotBlockSelf  = errors.New("cannot block yourself")
+	ErrNotBlocked       = errors.New("user is not blocked")
 )
 
-// Notifier sends optional friend notifications
+// Notifier is told about friend request events so the users involved can be
+// alerted in real time. Errors are ignored: the request itself has already
+// been stored.
 type Notifier interface {
-	NotifyFriendRequest(ctx context.Context, targetUserID int64, requesterUsername string) error
-	NotifyFriendAccepted(ctx context.Context, requesterID int64, targetUsername string) error
+	// FriendRequestSent alerts request.ToUserID about a new request
+	FriendRequestSent(ctx context.Context, request *FriendRequest, requesterUsername string) error
+	// FriendRequestAccepted alerts request.FromUserID that the request was accepted
+	FriendRequestAccepted(ctx context.Context, request *FriendRequest, accepterUsername string) error
 }
 
-type noopNotifier struct{}
+// NoopNotifier discards friend events; NewService uses it when no notifier is given
+type NoopNotifier struct{}
 
-func (noopNotifier) NotifyFriendRequest(ctx context.Context, targetUserID int64, requesterUsername string) error {
+// FriendRequestSent does nothing
+func (NoopNotifier) FriendRequestSent(ctx context.Context, request *FriendRequest, requesterUsername string) error {
 	return nil
 }
 
-func (noopNotifier) NotifyFriendAccepted(ctx context.Context, requesterID int64, targetUsername string) error {
+// FriendRequestAccepted does nothing
+func (NoopNotifier) FriendRequestAccepted(ctx context.Context, request *FriendRequest, accepterUsername string) error {
 	return nil
 }
 
@@ -49,7 +59,7 @@ type Service struct {
 // NewService builds a friend service
 func NewService(repo *Repository, userRepo *user.Repository, notifier Notifier) *Service {
 	if notifier == nil {
-		notifier = noopNotifier{}
+		notifier = NoopNotifier{}
 	}
 	return &Service{repo: repo, userRepo: userRepo, notifier: notifier}
 }
@@ -85,19 +95,19 @@ func (s *Service) SearchUser(ctx context.Context, username string) (*UserSummary
 		return nil, fmt.Errorf("%w: %v", ErrInvalidUsername, err)
 	}
 
-	user, err := s.userRepo.FindByUsernameOrEmail(ctx, username)
+	found, err := s.userRepo.FindByUsernameOrEmail(ctx, username)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
+	if found == nil {
 		return nil, ErrUserNotFound
 	}
 
 	return &UserSummary{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		AvatarURL: user.AvatarURL,
+		ID:        found.ID,
+		Username:  found.Username,
+		Email:     found.Email,
+		AvatarURL: user.AvatarURLOrDefault(found.ID, found.AvatarURL),
 	}, nil
 }
 
@@ -114,6 +124,14 @@ func (s *Service) SendFriendRequest(ctx context.Context, requesterID int64, requ
 		return nil, ErrCannotFriendSelf
 	}
 
+	blocked, err := s.repo.IsBlocked(ctx, requesterID, targetUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrBlocked
+	}
+
 	alreadyFriends, err := s.repo.AreFriends(ctx, requesterID, targetUser.ID)
 	if err != nil {
 		return nil, err
@@ -142,7 +160,7 @@ func (s *Service) SendFriendRequest(ctx context.Context, requesterID int64, requ
 		return nil, err
 	}
 
-	_ = s.notifier.NotifyFriendRequest(ctx, targetUser.ID, requesterUsername)
+	_ = s.notifier.FriendRequestSent(ctx, created, requesterUsername)
 
 	return created, nil
 }
@@ -173,7 +191,7 @@ func (s *Service) AcceptFriendRequest(ctx context.Context, userID int64, accepte
 		AcceptedAt: &now,
 	}
 
-	_ = s.notifier.NotifyFriendAccepted(ctx, req.FromUserID, accepterUsername)
+	_ = s.notifier.FriendRequestAccepted(ctx, req, accepterUsername)
 
 	return friendship, nil
 }
@@ -196,6 +214,48 @@ func (s *Service) RejectFriendRequest(ctx context.Context, userID, requestID int
 	return nil
 }
 
+// RemoveFriend ends an accepted friendship in both directions.
+func (s *Service) RemoveFriend(ctx context.Context, userID, friendID int64) error {
+	if userID == friendID {
+		return ErrNotFriends
+	}
+	if err := s.repo.DeleteFriendshipBidirectional(ctx, userID, friendID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFriends
+		}
+		return err
+	}
+	return nil
+}
+
+// BlockUser blocks another user and ends any friendship with them.
+func (s *Service) BlockUser(ctx context.Context, userID, targetUserID int64) error {
+	if userID == targetUserID {
+		return ErrCannotBlockSelf
+	}
+
+	target, err := s.repo.FindUserByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrUserNotFound
+	}
+
+	return s.repo.BlockUser(ctx, userID, targetUserID)
+}
+
+// UnblockUser lifts a block. Friendships removed by the block are not restored.
+func (s *Service) UnblockUser(ctx context.Context, userID, targetUserID int64) error {
+	if err := s.repo.UnblockUser(ctx, userID, targetUserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotBlocked
+		}
+		return err
+	}
+	return nil
+}
+
 // ListFriends returns accepted friends for a user.
 func (s *Service) ListFriends(ctx context.Context, userID int64) ([]UserSummary, error) {
 	friends, err := s.repo.ListFriends(ctx, userID)
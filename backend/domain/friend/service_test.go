@@ -0,0 +1,288 @@
+package friend
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+)
+
+func setupFriendTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    email TEXT NOT NULL UNIQUE,
+    avatar_url TEXT
+);
+CREATE TABLE friends (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    friend_user_id INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, friend_user_id)
+);
+CREATE TABLE Blocked_Users (
+    Blocker_Id INTEGER NOT NULL,
+    Blocked_Id INTEGER NOT NULL,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (Blocker_Id, Blocked_Id)
+);
+INSERT INTO users (id, username, email) VALUES
+    (1, 'alice', 'alice@example.com'),
+    (2, 'bob', 'bob@example.com'),
+    (3, 'carol', 'carol@example.com');`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestRemoveFriendDeletesBothDirections(t *testing.T) {
+	db := setupFriendTestDB(t)
+	ctx := context.Background()
+
+	if _, err := db.Exec(`
+		INSERT INTO friends (user_id, friend_user_id, status) VALUES
+		(1, 2, 'accepted'), (2, 1, 'accepted'), (1, 3, 'accepted'), (3, 1, 'accepted')
+	`); err != nil {
+		t.Fatalf("failed to seed friendships: %v", err)
+	}
+
+	repo := NewRepository(db)
+	svc := NewService(repo, user.NewRepository(db), nil)
+
+	if err := svc.RemoveFriend(ctx, 1, 2); err != nil {
+		t.Fatalf("RemoveFriend returned error: %v", err)
+	}
+
+	count, err := repo.CountMutualFriendships(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("CountMutualFriendships returned error: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected both directional rows to be removed, %d remain", count)
+	}
+
+	for _, userID := range []int64{1, 2} {
+		friends, err := svc.ListFriends(ctx, userID)
+		if err != nil {
+			t.Fatalf("ListFriends returned error: %v", err)
+		}
+		for _, f := range friends {
+			if f.ID == 1 || f.ID == 2 {
+				t.Fatalf("user %d still lists user %d as a friend", userID, f.ID)
+			}
+		}
+	}
+
+	// Other friendships are untouched
+	if ok, err := repo.AreFriends(ctx, 1, 3); err != nil || !ok {
+		t.Fatalf("expected users 1 and 3 to remain friends, got %v, %v", ok, err)
+	}
+
+	if err := svc.RemoveFriend(ctx, 1, 2); !errors.Is(err, ErrNotFriends) {
+		t.Fatalf("expected ErrNotFriends on second removal, got %v", err)
+	}
+
+	// Nothing is left that would make SendFriendRequest refuse a new request
+	resent, err := svc.SendFriendRequest(ctx, 1, "alice", 2)
+	if err != nil {
+		t.Fatalf("sending a request after removal returned error: %v", err)
+	}
+	if resent == nil || resent.FromUserID != 1 || resent.ToUserID != 2 || resent.Status != "pending" {
+		t.Fatalf("expected a pending request from alice to bob, got %+v", resent)
+	}
+	if ok, err := repo.AreFriends(ctx, 1, 2); err != nil || ok {
+		t.Fatalf("expected the new request to wait for acceptance, got %v, %v", ok, err)
+	}
+}
+
+func TestBlockUserHidesBlockerAndRejectsRequests(t *testing.T) {
+	db := setupFriendTestDB(t)
+	ctx := context.Background()
+
+	if _, err := db.Exec(`
+		INSERT INTO friends (user_id, friend_user_id, status) VALUES (1, 2, 'accepted'), (2, 1, 'accepted')
+	`); err != nil {
+		t.Fatalf("failed to seed friendship: %v", err)
+	}
+
+	repo := NewRepository(db)
+	svc := NewService(repo, user.NewRepository(db), nil)
+
+	// Alice blocks Bob
+	if err := svc.BlockUser(ctx, 1, 2); err != nil {
+		t.Fatalf("BlockUser returned error: %v", err)
+	}
+	if err := svc.BlockUser(ctx, 1, 2); err != nil {
+		t.Fatalf("blocking twice should be a no-op, got %v", err)
+	}
+
+	if ok, err := repo.AreFriends(ctx, 1, 2); err != nil || ok {
+		t.Fatalf("expected the friendship to be removed, got %v, %v", ok, err)
+	}
+
+	results, err := svc.SearchUsers(ctx, 2, "alice")
+	if err != nil {
+		t.Fatalf("SearchUsers returned error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected the blocker to be hidden from the blocked user, got %+v", results)
+	}
+	results, err = svc.SearchUsers(ctx, 3, "alice")
+	if err != nil {
+		t.Fatalf("SearchUsers returned error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected other users to still find the blocker, got %+v", results)
+	}
+
+	// Neither side can start a new friendship
+	if _, err := svc.SendFriendRequest(ctx, 2, "bob", 1); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked for the blocked user, got %v", err)
+	}
+	if _, err := svc.SendFriendRequest(ctx, 1, "alice", 2); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked for the blocker, got %v", err)
+	}
+
+	if err := svc.UnblockUser(ctx, 1, 2); err != nil {
+		t.Fatalf("UnblockUser returned error: %v", err)
+	}
+	if blocked, err := repo.IsBlocked(ctx, 1, 2); err != nil || blocked {
+		t.Fatalf("expected the block to be lifted, got %v, %v", blocked, err)
+	}
+	if err := svc.UnblockUser(ctx, 1, 2); !errors.Is(err, ErrNotBlocked) {
+		t.Fatalf("expected ErrNotBlocked, got %v", err)
+	}
+}
+
+func TestFriendRequestUpsertsOnSQLite(t *testing.T) {
+	db := setupFriendTestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	svc := NewService(repo, user.NewRepository(db), nil)
+
+	created, err := svc.SendFriendRequest(ctx, 1, "alice", 2)
+	if err != nil {
+		t.Fatalf("SendFriendRequest returned error: %v", err)
+	}
+	if created == nil || created.ID == 0 || created.Status != "pending" {
+		t.Fatalf("expected a pending request, got %+v", created)
+	}
+
+	// A rejected request can be sent again and reuses the existing row
+	if err := repo.UpdateFriendRequestStatus(ctx, created.ID, "rejected"); err != nil {
+		t.Fatalf("UpdateFriendRequestStatus returned error: %v", err)
+	}
+	resent, err := svc.SendFriendRequest(ctx, 1, "alice", 2)
+	if err != nil {
+		t.Fatalf("resending the request returned error: %v", err)
+	}
+	if resent.ID != created.ID || resent.Status != "pending" {
+		t.Fatalf("expected request %d to be pending again, got %+v", created.ID, resent)
+	}
+
+	if _, err := svc.AcceptFriendRequest(ctx, 2, "bob", resent.ID); err != nil {
+		t.Fatalf("AcceptFriendRequest returned error: %v", err)
+	}
+	if ok, err := repo.AreFriends(ctx, 1, 2); err != nil || !ok {
+		t.Fatalf("expected alice and bob to be friends, got %v, %v", ok, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.CreateFriendshipBidirectional(ctx, 1, 3); err != nil {
+			t.Fatalf("CreateFriendshipBidirectional run %d returned error: %v", i, err)
+		}
+	}
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM friends`).Scan(&rows); err != nil {
+		t.Fatalf("failed to count friends: %v", err)
+	}
+	if rows != 4 {
+		t.Fatalf("expected one row per direction and pair, got %d", rows)
+	}
+}
+
+type friendEvent struct {
+	kind      string
+	requestID int64
+	notified  int64
+	username  string
+}
+
+type recordingNotifier struct {
+	events []friendEvent
+}
+
+func (n *recordingNotifier) FriendRequestSent(ctx context.Context, request *FriendRequest, requesterUsername string) error {
+	n.events = append(n.events, friendEvent{"sent", request.ID, request.ToUserID, requesterUsername})
+	return nil
+}
+
+func (n *recordingNotifier) FriendRequestAccepted(ctx context.Context, request *FriendRequest, accepterUsername string) error {
+	n.events = append(n.events, friendEvent{"accepted", request.ID, request.FromUserID, accepterUsername})
+	return errors.New("push failed")
+}
+
+func TestNotifierToldAboutRequestAndAccept(t *testing.T) {
+	db := setupFriendTestDB(t)
+	ctx := context.Background()
+	notifier := &recordingNotifier{}
+	svc := NewService(NewRepository(db), user.NewRepository(db), notifier)
+
+	created, err := svc.SendFriendRequest(ctx, 1, "alice", 2)
+	if err != nil {
+		t.Fatalf("SendFriendRequest returned error: %v", err)
+	}
+	// A failing notifier must not fail the accept
+	if _, err := svc.AcceptFriendRequest(ctx, 2, "bob", created.ID); err != nil {
+		t.Fatalf("AcceptFriendRequest returned error: %v", err)
+	}
+	if _, err := svc.SendFriendRequest(ctx, 1, "alice", 2); !errors.Is(err, ErrAlreadyFriends) {
+		t.Fatalf("expected ErrAlreadyFriends, got %v", err)
+	}
+
+	want := []friendEvent{
+		{"sent", created.ID, 2, "alice"},
+		{"accepted", created.ID, 1, "bob"},
+	}
+	if len(notifier.events) != len(want) {
+		t.Fatalf("expected events %+v, got %+v", want, notifier.events)
+	}
+	for i := range want {
+		if notifier.events[i] != want[i] {
+			t.Fatalf("event %d: expected %+v, got %+v", i, want[i], notifier.events[i])
+		}
+	}
+}
+
+func TestNilNotifierDefaultsToNoop(t *testing.T) {
+	db := setupFriendTestDB(t)
+	svc := NewService(NewRepository(db), user.NewRepository(db), nil)
+	if _, ok := svc.notifier.(NoopNotifier); !ok {
+		t.Fatalf("expected NoopNotifier, got %T", svc.notifier)
+	}
+
+	created, err := svc.SendFriendRequest(context.Background(), 1, "alice", 3)
+	if err != nil {
+		t.Fatalf("SendFriendRequest returned error: %v", err)
+	}
+	if _, err := svc.AcceptFriendRequest(context.Background(), 3, "carol", created.ID); err != nil {
+		t.Fatalf("AcceptFriendRequest returned error: %v", err)
+	}
+}
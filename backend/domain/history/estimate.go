@@ -0,0 +1,99 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+// velocityWindowDays is how far back reading velocity is measured
+const velocityWindowDays = 14
+
+// EstimateOngoing is returned instead of a date for manga still being published
+const EstimateOngoing = "ongoing"
+
+// estimateDateFormat is how estimated completion dates are rendered
+const estimateDateFormat = "2006-01-02"
+
+// estimateCompletionDate projects when the remaining chapters will be read at
+// chaptersPerDay. It returns "" when nothing is left or there is no recent
+// reading to extrapolate from.
+func estimateCompletionDate(remaining int, chaptersPerDay float64, now time.Time) string {
+	if remaining <= 0 || chaptersPerDay <= 0 {
+		return ""
+	}
+	days := int(math.Ceil(float64(remaining) / chaptersPerDay))
+	return now.UTC().AddDate(0, 0, days).Format(estimateDateFormat)
+}
+
+// estimateCompletion estimates when the user finishes a manga from their
+// reading velocity over the last velocityWindowDays. Failures are logged and
+// leave the estimate empty so they never fail a progress update.
+func (s *Service) estimateCompletion(ctx context.Context, userID, mangaID int64, currentChapter int) string {
+	logger := logging.FromContext(ctx)
+
+	status, err := s.repo.MangaStatus(ctx, mangaID)
+	if err != nil {
+		logger.Warn("history.estimateCompletion: load manga status failed", "manga_id", mangaID, "error", err)
+		return ""
+	}
+	if strings.EqualFold(status, EstimateOngoing) {
+		return EstimateOngoing
+	}
+
+	if s.chapterService == nil {
+		return ""
+	}
+	maxChapter, err := s.chapterService.GetMaxChapterNumber(ctx, mangaID)
+	if err != nil {
+		logger.Warn("history.estimateCompletion: load max chapter failed", "manga_id", mangaID, "error", err)
+		return ""
+	}
+
+	now := time.Now()
+	read, err := s.repo.ChaptersReadSince(ctx, userID, now.AddDate(0, 0, -velocityWindowDays))
+	if err != nil {
+		logger.Warn("history.estimateCompletion: load reading velocity failed", "user_id", userID, "error", err)
+		return ""
+	}
+	return estimateCompletionDate(maxChapter-currentChapter, float64(read)/velocityWindowDays, now)
+}
+
+// MangaStatus returns the publication status of a manga, or "" when unknown
+func (r *Repository) MangaStatus(ctx context.Context, mangaID int64) (string, error) {
+	var status string
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(status, '') FROM mangas WHERE id = ?`, mangaID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return status, err
+}
+
+// ChaptersReadSince counts the chapters the user advanced through across all
+// manga since the given time. Each applied Progress_History entry contributes
+// the distance from the previous applied chapter of the same manga, so
+// re-reads and backwards moves add nothing. The first entry for a manga
+// counts as a single chapter since earlier reading was never tracked.
+func (r *Repository) ChaptersReadSince(ctx context.Context, userID int64, since time.Time) (int, error) {
+	exists, err := r.tableExists(ctx, "Progress_History")
+	if err != nil || !exists {
+		return 0, err
+	}
+	var read int
+	err = r.db.QueryRowContext(ctx, `
+        SELECT COALESCE(SUM(Delta), 0)
+        FROM (
+            SELECT Created_At,
+                   Applied_Chapter - COALESCE(LAG(Applied_Chapter) OVER (PARTITION BY Manga_Id ORDER BY History_Id), Applied_Chapter - 1) AS Delta
+            FROM Progress_History
+            WHERE User_Id = ? AND Is_Conflict = 0
+        )
+        WHERE Created_At >= ? AND Delta > 0
+    `, userID, since.UTC().Format(goalTimeFormat)).Scan(&read)
+	return read, err
+}
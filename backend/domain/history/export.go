@@ -0,0 +1,116 @@
+package history
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/export"
+)
+
+// Supported reading analytics export formats
+const (
+	ExportFormatCSV  = export.FormatCSV
+	ExportFormatJSON = export.FormatJSON
+)
+
+var ErrInvalidExportFormat = export.ErrInvalidFormat
+
+var (
+	exportDailyHeader   = []string{"date", "chapters_read"}
+	exportSummaryHeader = []string{"metric", "value"}
+	exportGoalsHeader   = []string{"goal_id", "goal_type", "genre", "target_value", "current_value", "period_type", "period_start", "period_end", "status"}
+)
+
+// AnalyticsExport is the JSON form of a reading analytics export
+type AnalyticsExport struct {
+	Daily   []ReadingAnalyticsPoint `json:"daily"`
+	Streaks StreakSummary           `json:"streaks"`
+	Goals   []ReadingGoal           `json:"goals"`
+}
+
+// StreakSummary reports a user's reading streaks in an export
+type StreakSummary struct {
+	CurrentStreakDays      int `json:"current_streak_days"`
+	LongestStreakDays      int `json:"longest_streak_days"`
+	StreakFreezesRemaining int `json:"streak_freezes_remaining"`
+}
+
+// ExportReadingAnalytics writes the user's daily reading buckets followed by
+// a summary of streaks and goals to w. Everything is loaded before writing,
+// so nothing reaches w when a query fails.
+//
+// The CSV holds three sections separated by blank lines, each with its own
+// header row: the daily buckets, the streak metrics and the goals.
+func (s *Service) ExportReadingAnalytics(ctx context.Context, userID int64, format string, w io.Writer) error {
+	if _, err := export.ContentType(format); err != nil {
+		return err
+	}
+
+	buckets, err := s.GetReadingAnalyticsBuckets(ctx, userID)
+	if err != nil {
+		return err
+	}
+	goals, err := s.ListGoals(ctx, userID)
+	if err != nil {
+		return err
+	}
+	streaks, frozen, err := s.repo.readingStreaks(ctx, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	export := &AnalyticsExport{
+		Daily: buckets.Daily,
+		Streaks: StreakSummary{
+			CurrentStreakDays:      streaks.Current,
+			LongestStreakDays:      streaks.Longest,
+			StreakFreezesRemaining: freezesLeftIn(frozen, time.Now().UTC()),
+		},
+		Goals: goals,
+	}
+	if export.Daily == nil {
+		export.Daily = []ReadingAnalyticsPoint{}
+	}
+
+	if format == ExportFormatJSON {
+		return json.NewEncoder(w).Encode(export)
+	}
+	return writeAnalyticsCSV(w, export)
+}
+
+func writeAnalyticsCSV(w io.Writer, export *AnalyticsExport) error {
+	// csv.Writer quotes genres or other fields containing commas, quotes or newlines
+	cw := csv.NewWriter(w)
+	records := [][]string{exportDailyHeader}
+	for _, point := range export.Daily {
+		records = append(records, []string{point.Date, strconv.Itoa(point.ChaptersRead)})
+	}
+
+	records = append(records, []string{}, exportSummaryHeader,
+		[]string{"current_streak_days", strconv.Itoa(export.Streaks.CurrentStreakDays)},
+		[]string{"longest_streak_days", strconv.Itoa(export.Streaks.LongestStreakDays)},
+		[]string{"streak_freezes_remaining", strconv.Itoa(export.Streaks.StreakFreezesRemaining)},
+	)
+
+	records = append(records, []string{}, exportGoalsHeader)
+	for _, goal := range export.Goals {
+		records = append(records, []string{
+			strconv.FormatInt(goal.GoalID, 10),
+			goal.GoalType,
+			goal.Genre,
+			strconv.Itoa(goal.TargetValue),
+			strconv.Itoa(goal.CurrentValue),
+			goal.PeriodType,
+			goal.StartDate.UTC().Format(time.RFC3339),
+			goal.EndDate.UTC().Format(time.RFC3339),
+			goal.Status,
+		})
+	}
+
+	return cw.WriteAll(records)
+}
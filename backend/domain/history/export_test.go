@@ -0,0 +1,118 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func setupExportTestService(t *testing.T) *Service {
+	t.Helper()
+	db := setupSessionTestDB(t)
+	if _, err := db.Exec(`
+    CREATE TABLE Streak_Freezes (
+        User_Id INTEGER NOT NULL,
+        Freeze_Date TEXT NOT NULL,
+        Is_Manual INTEGER NOT NULL DEFAULT 0,
+        Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (User_Id, Freeze_Date)
+    );
+    INSERT INTO tags (id, name) VALUES (1, 'Slice of Life, Comedy');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(NewRepository(db), nil, nil, nil)
+}
+
+func TestExportReadingAnalyticsCSVMatchesBuckets(t *testing.T) {
+	svc := setupExportTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := svc.CreateGoal(ctx, 1, CreateGoalRequest{
+		GoalType:    "chapters",
+		TargetValue: 20,
+		PeriodType:  "monthly",
+		PeriodStart: now.AddDate(0, 0, -7),
+		PeriodEnd:   now.AddDate(0, 0, 7),
+		Genre:       "slice of life, comedy",
+	}); err != nil {
+		t.Fatalf("CreateGoal returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.ExportReadingAnalytics(ctx, 1, ExportFormatCSV, &buf); err != nil {
+		t.Fatalf("ExportReadingAnalytics returned error: %v", err)
+	}
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+
+	buckets, err := svc.GetReadingAnalyticsBuckets(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetReadingAnalyticsBuckets returned error: %v", err)
+	}
+	if len(buckets.Daily) == 0 {
+		t.Fatal("expected the seeded history to produce daily buckets")
+	}
+
+	// Blank lines are skipped by the reader, so sections start at their headers
+	if !reflect.DeepEqual(records[0], exportDailyHeader) {
+		t.Fatalf("unexpected daily header %v", records[0])
+	}
+	var daily [][]string
+	rest := records[1:]
+	for len(rest) > 0 && !reflect.DeepEqual(rest[0], exportSummaryHeader) {
+		daily = append(daily, rest[0])
+		rest = rest[1:]
+	}
+	if len(daily) != len(buckets.Daily) {
+		t.Fatalf("expected %d daily rows, got %d", len(buckets.Daily), len(daily))
+	}
+	for i, point := range buckets.Daily {
+		want := []string{point.Date, strconv.Itoa(point.ChaptersRead)}
+		if !reflect.DeepEqual(daily[i], want) {
+			t.Fatalf("row %d: got %v, want %v", i, daily[i], want)
+		}
+	}
+
+	if len(rest) != 6 {
+		t.Fatalf("expected streak and goal sections with one goal, got %v", rest)
+	}
+	// User 1 read today and on the two days before
+	if rest[1][0] != "current_streak_days" || rest[1][1] != "3" {
+		t.Fatalf("unexpected current streak row %v", rest[1])
+	}
+	if !reflect.DeepEqual(rest[4], exportGoalsHeader) {
+		t.Fatalf("unexpected goals header %v", rest[4])
+	}
+	// The genre contains a comma and must survive quoting
+	if goal := rest[5]; goal[2] != "Slice of Life, Comedy" || goal[3] != "20" {
+		t.Fatalf("unexpected goal row %v", goal)
+	}
+
+	buf.Reset()
+	if err := svc.ExportReadingAnalytics(ctx, 1, ExportFormatJSON, &buf); err != nil {
+		t.Fatalf("ExportReadingAnalytics returned error: %v", err)
+	}
+	var export AnalyticsExport
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(export.Daily, buckets.Daily) || len(export.Goals) != 1 {
+		t.Fatalf("unexpected JSON export %+v", export)
+	}
+
+	if err := svc.ExportReadingAnalytics(ctx, 1, "xlsx", &buf); !errors.Is(err, ErrInvalidExportFormat) {
+		t.Fatalf("expected ErrInvalidExportFormat, got %v", err)
+	}
+}
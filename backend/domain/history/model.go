@@ -13,6 +13,8 @@ type UserProgress struct {
 // UpdateProgressRequest represents progress update payload
 type UpdateProgressRequest struct {
 	CurrentChapter int `json:"current_chapter" binding:"required"`
+	// Force allows moving progress backwards
+	Force bool `json:"force,omitempty"`
 }
 
 // UpdateProgressResponse represents response after update
@@ -20,6 +22,81 @@ type UpdateProgressResponse struct {
 	Message      string        `json:"message"`
 	UserProgress *UserProgress `json:"user_progress"`
 	Broadcasted  bool          `json:"broadcasted"`
+	// Conflict is set when a higher chapter recorded by another device was kept
+	Conflict bool `json:"conflict,omitempty"`
+	// EstimatedCompletion is a YYYY-MM-DD date projected from recent reading
+	// velocity, or "ongoing" while the manga is still being published
+	EstimatedCompletion string `json:"estimated_completion,omitempty"`
+}
+
+// BatchProgressItem is one entry of a batched progress sync
+type BatchProgressItem struct {
+	MangaID        int64      `json:"manga_id"`
+	CurrentChapter int        `json:"current_chapter"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+}
+
+// Batch item statuses
+const (
+	BatchStatusApplied    = "applied"
+	BatchStatusSuperseded = "superseded"
+	BatchStatusUnchanged  = "unchanged"
+	BatchStatusInvalid    = "invalid"
+)
+
+// BatchProgressResult reports the outcome of a single batch item
+type BatchProgressResult struct {
+	Index          int    `json:"index"`
+	MangaID        int64  `json:"manga_id"`
+	CurrentChapter int    `json:"current_chapter"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BatchUpdateProgressResponse summarises a batched progress sync
+type BatchUpdateProgressResponse struct {
+	Results     []BatchProgressResult `json:"results"`
+	Applied     int                   `json:"applied"`
+	Broadcasted int                   `json:"broadcasted"`
+}
+
+// StartReadingSessionRequest opens a reading session
+type StartReadingSessionRequest struct {
+	MangaID   int64  `json:"manga_id" binding:"required"`
+	ChapterID *int64 `json:"chapter_id,omitempty"`
+}
+
+// EndReadingSessionRequest closes a reading session
+type EndReadingSessionRequest struct {
+	SessionID int64 `json:"session_id" binding:"required"`
+}
+
+// ReadingSession is a timed stretch of reading
+type ReadingSession struct {
+	SessionID       int64      `json:"session_id"`
+	UserID          int64      `json:"user_id"`
+	MangaID         int64      `json:"manga_id"`
+	ChapterID       *int64     `json:"chapter_id,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Capped          bool       `json:"capped,omitempty"`
+}
+
+// Activity types accepted by the friends feed filter. Stored types are
+// matched case-insensitively.
+const (
+	ActivityTypeCompletedManga = "completed_manga"
+	ActivityTypeReview         = "review"
+	ActivityTypeRating         = "rating"
+	ActivityTypeRead           = "read"
+)
+
+var validActivityTypes = map[string]bool{
+	ActivityTypeCompletedManga: true,
+	ActivityTypeReview:         true,
+	ActivityTypeRating:         true,
+	ActivityTypeRead:           true,
 }
 
 // Activity represents user activity entry
@@ -81,29 +158,59 @@ type ReadingGoal struct {
 	GoalType     string    `json:"goal_type"`
 	TargetValue  int       `json:"target_value"`
 	CurrentValue int       `json:"current_value"`
+	PeriodType   string    `json:"period_type"`
+	Genre        string    `json:"genre,omitempty"`
 	StartDate    time.Time `json:"start_date"`
 	EndDate      time.Time `json:"end_date"`
+	Status       string    `json:"status"`
 	Completed    bool      `json:"completed"`
+	Progress     float64   `json:"progress"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// notified is set once the completion was announced
+	notified bool
+}
+
+// CreateGoalRequest holds payload for creating a reading goal
+type CreateGoalRequest struct {
+	GoalType    string    `json:"goal_type" binding:"required"`
+	TargetValue int       `json:"target_value" binding:"required"`
+	PeriodType  string    `json:"period_type" binding:"required"`
+	PeriodStart time.Time `json:"period_start" binding:"required"`
+	PeriodEnd   time.Time `json:"period_end" binding:"required"`
+	Genre       string    `json:"genre"`
+}
+
+// UpdateGoalRequest holds a partial update for a reading goal
+type UpdateGoalRequest struct {
+	GoalType    *string    `json:"goal_type"`
+	TargetValue *int       `json:"target_value"`
+	PeriodType  *string    `json:"period_type"`
+	PeriodStart *time.Time `json:"period_start"`
+	PeriodEnd   *time.Time `json:"period_end"`
+	// Genre rescopes the goal; an empty string removes the filter
+	Genre *string `json:"genre"`
 }
 
 // ReadingStatistics aggregates user reading metrics
 type ReadingStatistics struct {
-	UserID                int64         `json:"user_id"`
-	TotalChaptersRead     int           `json:"total_chapters_read"`
-	TotalMangaRead        int           `json:"total_manga_read"`
-	TotalMangaReading     int           `json:"total_manga_reading"`
-	TotalMangaPlanned     int           `json:"total_manga_planned"`
-	FavoriteGenres        []GenreStat   `json:"favorite_genres"`
-	AverageRating         float64       `json:"average_rating"`
-	TotalReadingTimeHours float64       `json:"total_reading_time_hours"`
-	CurrentStreakDays     int           `json:"current_streak_days"`
-	LongestStreakDays     int           `json:"longest_streak_days"`
-	MonthlyStats          []MonthlyStat `json:"monthly_stats"`
-	YearlyStats           []YearlyStat  `json:"yearly_stats"`
-	LastCalculatedAt      time.Time     `json:"last_calculated_at"`
-	Goals                 []ReadingGoal `json:"goals,omitempty"`
+	UserID                int64       `json:"user_id"`
+	TotalChaptersRead     int         `json:"total_chapters_read"`
+	TotalMangaRead        int         `json:"total_manga_read"`
+	TotalMangaReading     int         `json:"total_manga_reading"`
+	TotalMangaPlanned     int         `json:"total_manga_planned"`
+	FavoriteGenres        []GenreStat `json:"favorite_genres"`
+	AverageRating         float64     `json:"average_rating"`
+	TotalReadingTimeHours float64     `json:"total_reading_time_hours"`
+	CurrentStreakDays     int         `json:"current_streak_days"`
+	LongestStreakDays     int         `json:"longest_streak_days"`
+	// StreakFreezesRemaining counts this month's unused streak freezes
+	StreakFreezesRemaining int           `json:"streak_freezes_remaining"`
+	MonthlyStats           []MonthlyStat `json:"monthly_stats"`
+	YearlyStats            []YearlyStat  `json:"yearly_stats"`
+	LastCalculatedAt       time.Time     `json:"last_calculated_at"`
+	Goals                  []ReadingGoal `json:"goals,omitempty"`
 }
 
 // ReadingAnalyticsRequest represents analytics filters
@@ -120,6 +227,22 @@ type ReadingSummary struct {
 	TotalChaptersRead int        `json:"total_chapters_read"`
 	ReadingStreak     int        `json:"reading_streak"`
 	LastReadAt        *time.Time `json:"last_read_at"`
+	// StreakFreezesRemaining counts this month's unused streak freezes
+	StreakFreezesRemaining int `json:"streak_freezes_remaining"`
+}
+
+// StreakFreezeRequest applies a streak freeze to a missed day.
+// Date is YYYY-MM-DD and defaults to yesterday (UTC).
+type StreakFreezeRequest struct {
+	Date string `json:"date"`
+}
+
+// StreakFreezeResponse reports the frozen day and the resulting streak
+type StreakFreezeResponse struct {
+	FrozenDate        string `json:"frozen_date"`
+	FreezesRemaining  int    `json:"freezes_remaining"`
+	CurrentStreakDays int    `json:"current_streak_days"`
+	LongestStreakDays int    `json:"longest_streak_days"`
 }
 
 // ReadingAnalyticsPoint represents a time-bucketed analytics entry.
@@ -128,9 +251,18 @@ type ReadingAnalyticsPoint struct {
 	ChaptersRead int    `json:"chapters_read"`
 }
 
+// GenreAnalyticsPoint counts chapters finished in one genre during a month.
+type GenreAnalyticsPoint struct {
+	Date         string `json:"date"`
+	Genre        string `json:"genre"`
+	ChaptersRead int    `json:"chapters_read"`
+}
+
 // ReadingAnalyticsResponse contains grouped analytics buckets.
 type ReadingAnalyticsResponse struct {
 	Daily   []ReadingAnalyticsPoint `json:"daily"`
 	Weekly  []ReadingAnalyticsPoint `json:"weekly"`
 	Monthly []ReadingAnalyticsPoint `json:"monthly"`
+	// MonthlyGenres breaks the monthly buckets down by genre
+	MonthlyGenres []GenreAnalyticsPoint `json:"monthly_genres"`
 }
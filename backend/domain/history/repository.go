@@ -5,18 +5,32 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"log"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 )
 
 // Repository handles reading history persistence
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	reader *sql.DB
 }
 
 // NewRepository builds history repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, reader: db}
+}
+
+// SetReader routes read-only analytics queries to a replica; nil restores the primary
+func (r *Repository) SetReader(reader *sql.DB) {
+	if reader == nil {
+		reader = r.db
+	}
+	r.reader = reader
 }
 
 func (r *Repository) tableExists(ctx context.Context, name string) (bool, error) {
@@ -60,7 +74,7 @@ LIMIT 1
 `
 	var summary ReadingSummary
 	var lastReadAt sql.NullTime
-	if err := r.db.QueryRowContext(ctx, query, userID, userID, userID).Scan(
+	if err := r.reader.QueryRowContext(ctx, query, userID, userID, userID).Scan(
 		&summary.TotalManga,
 		&summary.TotalChaptersRead,
 		&summary.ReadingStreak,
@@ -69,7 +83,7 @@ LIMIT 1
 		if err == sql.ErrNoRows {
 			return &summary, nil
 		}
-		log.Printf("history.repository.GetReadingSummary: query error user_id=%d err=%v", userID, err)
+		logging.FromContext(ctx).Error("history.repository.GetReadingSummary: query error", "user_id", userID, "err", err)
 		return nil, err
 	}
 	if lastReadAt.Valid {
@@ -81,9 +95,10 @@ LIMIT 1
 // GetReadingAnalyticsBuckets aggregates daily/weekly/monthly analytics.
 func (r *Repository) GetReadingAnalyticsBuckets(ctx context.Context, userID int64) (*ReadingAnalyticsResponse, error) {
 	resp := &ReadingAnalyticsResponse{
-		Daily:   []ReadingAnalyticsPoint{},
-		Weekly:  []ReadingAnalyticsPoint{},
-		Monthly: []ReadingAnalyticsPoint{},
+		Daily:         []ReadingAnalyticsPoint{},
+		Weekly:        []ReadingAnalyticsPoint{},
+		Monthly:       []ReadingAnalyticsPoint{},
+		MonthlyGenres: []GenreAnalyticsPoint{},
 	}
 
 	type bucketQuery struct {
@@ -128,16 +143,16 @@ func (r *Repository) GetReadingAnalyticsBuckets(ctx context.Context, userID int6
 	}
 
 	for _, q := range queries {
-		rows, err := r.db.QueryContext(ctx, q.sql, userID)
+		rows, err := r.reader.QueryContext(ctx, q.sql, userID)
 		if err != nil {
-			log.Printf("history.repository.GetReadingAnalyticsBuckets: query error user_id=%d err=%v", userID, err)
+			logging.FromContext(ctx).Error("history.repository.GetReadingAnalyticsBuckets: query error", "user_id", userID, "err", err)
 			return nil, err
 		}
 		for rows.Next() {
 			var bucket sql.NullString
 			var chapters int
 			if err := rows.Scan(&bucket, &chapters); err != nil {
-				log.Printf("history.repository.GetReadingAnalyticsBuckets: scan error user_id=%d err=%v", userID, err)
+				logging.FromContext(ctx).Error("history.repository.GetReadingAnalyticsBuckets: scan error", "user_id", userID, "err", err)
 				rows.Close()
 				return nil, err
 			}
@@ -150,16 +165,52 @@ func (r *Repository) GetReadingAnalyticsBuckets(ctx context.Context, userID int6
 			})
 		}
 		if err := rows.Err(); err != nil {
-			log.Printf("history.repository.GetReadingAnalyticsBuckets: rows error user_id=%d err=%v", userID, err)
+			logging.FromContext(ctx).Error("history.repository.GetReadingAnalyticsBuckets: rows error", "user_id", userID, "err", err)
 			rows.Close()
 			return nil, err
 		}
 		rows.Close()
 	}
 
+	genres, err := r.monthlyGenreBuckets(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("history.repository.GetReadingAnalyticsBuckets: genre query error", "user_id", userID, "err", err)
+		return nil, err
+	}
+	resp.MonthlyGenres = genres
+
 	return resp, nil
 }
 
+// monthlyGenreBuckets counts finished chapters per genre and month over the
+// last twelve months, newest month first and busiest genre first within it
+func (r *Repository) monthlyGenreBuckets(ctx context.Context, userID int64) ([]GenreAnalyticsPoint, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+        SELECT strftime('%Y-%m-01', rh.created_at) AS bucket_date, t.name, COUNT(*) AS chapters_read
+        FROM reading_history rh
+        JOIN manga_tags mt ON mt.manga_id = rh.manga_id
+        JOIN tags t ON t.id = mt.tag_id
+        WHERE rh.user_id = ? AND rh.event_type = 'finished_chapter'
+          AND rh.created_at >= date('now', 'start of month', '-11 months')
+        GROUP BY bucket_date, t.id, t.name
+        ORDER BY bucket_date DESC, chapters_read DESC, t.name
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []GenreAnalyticsPoint{}
+	for rows.Next() {
+		var point GenreAnalyticsPoint
+		if err := rows.Scan(&point.Date, &point.Genre, &point.ChaptersRead); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, rows.Err()
+}
+
 // GetUserProgress retrieves progress record
 func (r *Repository) GetUserProgress(ctx context.Context, userID, mangaID int64) (*UserProgress, error) {
 	query := `
@@ -192,6 +243,53 @@ func (r *Repository) GetUserProgress(ctx context.Context, userID, mangaID int64)
 	return &progress, nil
 }
 
+// GetUserProgressBatch retrieves the user's progress for several manga in one
+// query, keyed by manga ID. Manga without progress are absent from the map.
+func (r *Repository) GetUserProgressBatch(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]*UserProgress, error) {
+	found := make(map[int64]*UserProgress, len(mangaIDs))
+	if len(mangaIDs) == 0 {
+		return found, nil
+	}
+
+	placeholders := make([]string, len(mangaIDs))
+	args := make([]interface{}, 0, len(mangaIDs)+1)
+	args = append(args, userID)
+	for i, id := range mangaIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT
+            rp.manga_id,
+            COALESCE(c.number, 0) as current_chapter,
+            rp.current_chapter_id,
+            COALESCE(rp.progress_percent, 0) as progress_percent,
+            rp.last_read_at
+        FROM reading_progress rp
+        LEFT JOIN chapters c ON rp.current_chapter_id = c.id
+        WHERE rp.user_id = ? AND rp.manga_id IN (`+strings.Join(placeholders, ", ")+`)
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mangaID int64
+		var progress UserProgress
+		var chapterID sql.NullInt64
+		if err := rows.Scan(&mangaID, &progress.CurrentChapter, &chapterID, &progress.ProgressPercent, &progress.LastReadAt); err != nil {
+			return nil, err
+		}
+		if chapterID.Valid {
+			progress.CurrentChapterID = &chapterID.Int64
+		}
+		found[mangaID] = &progress
+	}
+	return found, rows.Err()
+}
+
 // UpdateProgress updates progress table
 func (r *Repository) UpdateProgress(ctx context.Context, userID, mangaID int64, chapter int, chapterID *int64, progressPercent float64) error {
 	res, err := r.db.ExecContext(ctx, `
@@ -216,6 +314,125 @@ ON CONFLICT(user_id, manga_id) DO UPDATE SET
 	return err
 }
 
+// ProgressReconciliation is the outcome of ReconcileProgress
+type ProgressReconciliation struct {
+	Applied   bool
+	Chapter   int
+	ChapterID *int64
+}
+
+// ReconcileProgress writes progress unless it would move the user backwards:
+// the stored chapter only changes when the new one is at least as high, or
+// when force is set. The check and the write are a single statement so
+// concurrent updates from several devices cannot regress progress. Every
+// call is logged to Progress_History, overridden ones with Is_Conflict set.
+func (r *Repository) ReconcileProgress(ctx context.Context, userID, mangaID int64, chapter int, chapterID *int64, progressPercent float64, force bool) (result *ProgressReconciliation, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	return reconcileProgressTx(ctx, tx, userID, mangaID, chapter, chapterID, progressPercent, nil, force)
+}
+
+// ReconcileProgressTx is ReconcileProgress inside the caller's transaction
+func (r *Repository) ReconcileProgressTx(ctx context.Context, tx *sql.Tx, userID, mangaID int64, chapter int, chapterID *int64, progressPercent float64, force bool) (*ProgressReconciliation, error) {
+	return reconcileProgressTx(ctx, tx, userID, mangaID, chapter, chapterID, progressPercent, nil, force)
+}
+
+// reconcileProgressTx runs the guarded upsert and history insert of
+// ReconcileProgress inside tx. readAt overrides last_read_at when set.
+func reconcileProgressTx(ctx context.Context, tx *sql.Tx, userID, mangaID int64, chapter int, chapterID *int64, progressPercent float64, readAt *time.Time, force bool) (*ProgressReconciliation, error) {
+	var lastReadAt interface{}
+	if readAt != nil {
+		lastReadAt = readAt.UTC().Format(goalTimeFormat)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+INSERT INTO reading_progress (user_id, manga_id, current_chapter_id, last_read_at, progress_percent, current_page)
+VALUES (?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), ?, 0)
+ON CONFLICT(user_id, manga_id) DO UPDATE SET
+    current_chapter_id = excluded.current_chapter_id,
+    progress_percent = excluded.progress_percent,
+    last_read_at = excluded.last_read_at
+WHERE ? OR COALESCE((SELECT number FROM chapters WHERE id = reading_progress.current_chapter_id), 0) <= ?
+`, userID, mangaID, chapterID, lastReadAt, progressPercent, force, chapter)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProgressReconciliation{Applied: rows > 0}
+	var storedID sql.NullInt64
+	if err := tx.QueryRowContext(ctx, `
+SELECT COALESCE(c.number, 0), rp.current_chapter_id
+FROM reading_progress rp
+LEFT JOIN chapters c ON rp.current_chapter_id = c.id
+WHERE rp.user_id = ? AND rp.manga_id = ?
+`, userID, mangaID).Scan(&result.Chapter, &storedID); err != nil {
+		return nil, err
+	}
+	if storedID.Valid {
+		result.ChapterID = &storedID.Int64
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO Progress_History (User_Id, Manga_Id, Requested_Chapter, Applied_Chapter, Is_Conflict, Is_Forced)
+VALUES (?, ?, ?, ?, ?, ?)
+`, userID, mangaID, chapter, result.Chapter, !result.Applied, force); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ProgressWrite is a single progress row written by ApplyProgressBatch
+type ProgressWrite struct {
+	MangaID         int64
+	Chapter         int
+	ChapterID       *int64
+	ProgressPercent float64
+	ReadAt          *time.Time
+}
+
+// ApplyProgressBatch reconciles several progress rows for a user in one
+// transaction. Each write goes through the same highest-chapter-wins guard
+// and Progress_History logging as ReconcileProgress, so a batch built from
+// stale state cannot move progress backwards. Results follow the order of
+// writes.
+func (r *Repository) ApplyProgressBatch(ctx context.Context, userID int64, writes []ProgressWrite) (results []*ProgressReconciliation, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	results = make([]*ProgressReconciliation, 0, len(writes))
+	for _, w := range writes {
+		result, err := reconcileProgressTx(ctx, tx, userID, w.MangaID, w.Chapter, w.ChapterID, w.ProgressPercent, w.ReadAt, false)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // IsMangaCompleted checks whether the user completed the manga in their library
 func (r *Repository) IsMangaCompleted(ctx context.Context, userID, mangaID int64) (bool, error) {
 	var count int
@@ -237,15 +454,11 @@ func (r *Repository) GetFriends(ctx context.Context, userID int64) ([]int64, err
 		return nil, err
 	}
 	if !exists {
-		log.Printf("history.repository.GetFriends: friends table missing, returning empty list for user_id=%d", userID)
+		logging.FromContext(ctx).Warn("history.repository.GetFriends: friends table missing, returning empty list", "user_id", userID)
 		return []int64{}, nil
 	}
-	query := `
-        SELECT friend_id FROM friends WHERE user_id = ? AND status = 'accepted'
-        UNION
-        SELECT user_id FROM friends WHERE friend_id = ? AND status = 'accepted'
-    `
-	log.Printf("history.repository.GetFriends: querying friends for user_id=%d", userID)
+	query := friend.AcceptedFriendIDsQuery
+	logging.FromContext(ctx).Info("history.repository.GetFriends: querying friends", "user_id", userID)
 	rows, err := r.db.QueryContext(ctx, query, userID, userID)
 	if err != nil {
 		return nil, err
@@ -270,7 +483,7 @@ func (r *Repository) RecordActivity(ctx context.Context, userID int64, activityT
 		return err
 	}
 	if !exists {
-		log.Printf("history.repository.RecordActivity: activities table missing user_id=%d type=%s", userID, activityType)
+		logging.FromContext(ctx).Warn("history.repository.RecordActivity: activities table missing", "user_id", userID, "type", activityType)
 		return nil
 	}
 	payloadJSON := ""
@@ -286,30 +499,68 @@ func (r *Repository) RecordActivity(ctx context.Context, userID int64, activityT
 	return err
 }
 
-// GetFriendsActivities returns friend feed entries
-func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, page, limit int) ([]Activity, int, error) {
-	log.Printf("history.repository.GetFriendsActivities: start user_id=%d page=%d limit=%d", userID, page, limit)
-	if page < 1 {
-		page = 1
+// reviewRatingWindowSeconds is how far apart a review and a rating by the
+// same user on the same manga may be recorded and still count as one event
+const reviewRatingWindowSeconds = 2
+
+// GetFriendsActivities returns friend feed entries. When types is non-empty
+// only activities of those (lowercase) types are counted and listed. Types a
+// friend stopped sharing in User_Privacy are always left out. A rating
+// recorded alongside a listed review is the same event and is left out too.
+func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, page, limit int, types []string) ([]Activity, int, error) {
+	logging.FromContext(ctx).Info("history.repository.GetFriendsActivities: start", "user_id", userID, "page", page, "limit", limit, "types", types)
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
+	offset := pagination.Offset(page, limit)
+
+	typeFilter := ""
+	args := []interface{}{userID, userID}
+	hasPrivacy, err := r.tableExists(ctx, "User_Privacy")
+	if err != nil {
+		return nil, 0, err
+	}
+	if hasPrivacy {
+		typeFilter = `
+          AND NOT EXISTS (
+            SELECT 1 FROM User_Privacy p
+            WHERE p.User_Id = a.user_id AND (
+                (p.Share_Completions = 0 AND LOWER(a.type) = ?) OR
+                (p.Share_Reviews = 0 AND LOWER(a.type) = ?) OR
+                (p.Share_Ratings = 0 AND LOWER(a.type) = ?)
+            )
+          )`
+		args = append(args, ActivityTypeCompletedManga, ActivityTypeReview, ActivityTypeRating)
 	}
-	if limit < 1 {
-		limit = 20
+	if len(types) > 0 {
+		typeFilter += " AND LOWER(a.type) IN (?" + strings.Repeat(", ?", len(types)-1) + ")"
+		for _, t := range types {
+			args = append(args, t)
+		}
 	}
-	if limit > 100 {
-		limit = 100
+	if len(types) == 0 || slices.Contains(types, ActivityTypeReview) {
+		reviewVisible := ""
+		if hasPrivacy {
+			reviewVisible = `
+                AND NOT EXISTS (SELECT 1 FROM User_Privacy p WHERE p.User_Id = r.user_id AND p.Share_Reviews = 0)`
+		}
+		typeFilter += `
+          AND NOT (LOWER(a.type) = ? AND EXISTS (
+            SELECT 1 FROM activities r
+            WHERE r.user_id = a.user_id
+              AND r.manga_id = a.manga_id
+              AND LOWER(r.type) = ?
+              AND ABS(CAST(strftime('%s', r.created_at) AS INTEGER) - CAST(strftime('%s', a.created_at) AS INTEGER)) <= ?` + reviewVisible + `
+          ))`
+		args = append(args, ActivityTypeRating, ActivityTypeReview, reviewRatingWindowSeconds)
 	}
-	offset := (page - 1) * limit
 
 	countQuery := `
         SELECT COUNT(*)
         FROM activities a
-        JOIN friends f ON f.friend_id = a.user_id
-        WHERE f.user_id = ?
-    `
-	log.Printf("history.repository.GetFriendsActivities: count_sql=%s", countQuery)
+        WHERE a.user_id IN (` + friend.AcceptedFriendIDsQuery + `)` + typeFilter
+	logging.FromContext(ctx).Debug("history.repository.GetFriendsActivities: count query", "sql", countQuery)
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
-		log.Printf("history.repository.GetFriendsActivities: count query user_id=%d err=%v", userID, err)
+	if err := r.reader.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		logging.FromContext(ctx).Error("history.repository.GetFriendsActivities: count query failed", "user_id", userID, "err", err)
 		if errors.Is(err, sql.ErrNoRows) {
 			return []Activity{}, 0, nil
 		}
@@ -331,21 +582,20 @@ func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, pag
             a.payload,
             a.created_at
         FROM activities a
-        JOIN friends f ON f.friend_id = a.user_id
         JOIN users u ON u.id = a.user_id
         LEFT JOIN mangas m ON m.id = a.manga_id
-        WHERE f.user_id = ?
+        WHERE a.user_id IN (` + friend.AcceptedFriendIDsQuery + `)` + typeFilter + `
         ORDER BY a.created_at DESC
         LIMIT ? OFFSET ?
     `
-	log.Printf("history.repository.GetFriendsActivities: feed_sql=%s", query)
-	log.Printf("history.repository.GetFriendsActivities: query user_id=%d limit=%d offset=%d", userID, limit, offset)
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	logging.FromContext(ctx).Debug("history.repository.GetFriendsActivities: feed query", "sql", query)
+	logging.FromContext(ctx).Info("history.repository.GetFriendsActivities: query", "user_id", userID, "limit", limit, "offset", offset)
+	rows, err := r.reader.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return []Activity{}, total, nil
 		}
-		log.Printf("history.repository.GetFriendsActivities: list query failed user_id=%d err=%v", userID, err)
+		logging.FromContext(ctx).Error("history.repository.GetFriendsActivities: list query failed", "user_id", userID, "err", err)
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -367,7 +617,7 @@ func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, pag
 			&payloadRaw,
 			&activity.CreatedAt,
 		); err != nil {
-			log.Printf("history.repository.GetFriendsActivities: scan error user_id=%d err=%v", userID, err)
+			logging.FromContext(ctx).Error("history.repository.GetFriendsActivities: scan error", "user_id", userID, "err", err)
 			continue
 		}
 		if payloadRaw.Valid && payloadRaw.String != "" {
@@ -375,7 +625,7 @@ func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, pag
 			if err := json.Unmarshal([]byte(payloadRaw.String), &payload); err == nil {
 				activity.Payload = payload
 			} else {
-				log.Printf("history.repository.GetFriendsActivities: payload parse error user_id=%d payload=%s err=%v", userID, payloadRaw.String, err)
+				logging.FromContext(ctx).Error("history.repository.GetFriendsActivities: payload parse error", "user_id", userID, "payload", payloadRaw.String, "err", err)
 			}
 		}
 		activities = append(activities, activity)
@@ -388,8 +638,8 @@ func (r *Repository) GetFriendsActivities(ctx context.Context, userID int64, pag
 func (r *Repository) CalculateReadingStatistics(ctx context.Context, userID int64) (*ReadingStatistics, error) {
 	stats := &ReadingStatistics{UserID: userID}
 
-	log.Printf("history.repository.CalculateReadingStatistics: aggregating stats for user_id=%d", userID)
-	err := r.db.QueryRowContext(ctx, `
+	logging.FromContext(ctx).Info("history.repository.CalculateReadingStatistics: aggregating stats", "user_id", userID)
+	err := r.reader.QueryRowContext(ctx, `
         SELECT
             (SELECT COALESCE(COUNT(*), 0) FROM reading_history WHERE user_id = ? AND event_type = 'finished_chapter') AS total_chapters_read,
             COUNT(DISTINCT CASE WHEN lib.status = 'completed' THEN lib.manga_id END) as total_manga_read,
@@ -413,8 +663,8 @@ func (r *Repository) CalculateReadingStatistics(ctx context.Context, userID int6
 	stats.AverageRating = float64(int(stats.AverageRating*100+0.5)) / 100
 
 	stats.MonthlyStats = []MonthlyStat{}
-	log.Printf("history.repository.CalculateReadingStatistics: querying monthly stats user_id=%d", userID)
-	rows, err := r.db.QueryContext(ctx, `
+	logging.FromContext(ctx).Info("history.repository.CalculateReadingStatistics: querying monthly stats", "user_id", userID)
+	rows, err := r.reader.QueryContext(ctx, `
         SELECT
             COALESCE(CAST(strftime('%Y', created_at) AS INTEGER), 0) as year,
             COALESCE(CAST(strftime('%m', created_at) AS INTEGER), 0) as month,
@@ -434,14 +684,14 @@ func (r *Repository) CalculateReadingStatistics(ctx context.Context, userID int6
 			if err := rows.Scan(&stat.Year, &stat.Month, &stat.ChaptersRead, &stat.MangaCompleted, &stat.MangaStarted); err == nil {
 				stats.MonthlyStats = append(stats.MonthlyStats, stat)
 			} else {
-				log.Printf("history.repository.CalculateReadingStatistics: scan monthly stats failed user_id=%d err=%v", userID, err)
+				logging.FromContext(ctx).Error("history.repository.CalculateReadingStatistics: scan monthly stats failed", "user_id", userID, "err", err)
 			}
 		}
 	}
 
 	stats.YearlyStats = []YearlyStat{}
-	log.Printf("history.repository.CalculateReadingStatistics: querying yearly stats user_id=%d", userID)
-	rows, err = r.db.QueryContext(ctx, `
+	logging.FromContext(ctx).Info("history.repository.CalculateReadingStatistics: querying yearly stats", "user_id", userID)
+	rows, err = r.reader.QueryContext(ctx, `
         SELECT
             COALESCE(CAST(strftime('%Y', created_at) AS INTEGER), 0) as year,
             COALESCE(SUM(CASE WHEN event_type = 'finished_chapter' THEN 1 ELSE 0 END), 0) as chapters_read,
@@ -461,16 +711,90 @@ func (r *Repository) CalculateReadingStatistics(ctx context.Context, userID int6
 			if err := rows.Scan(&stat.Year, &stat.ChaptersRead, &stat.MangaCompleted, &stat.MangaStarted, &stat.TotalDays); err == nil {
 				stats.YearlyStats = append(stats.YearlyStats, stat)
 			} else {
-				log.Printf("history.repository.CalculateReadingStatistics: scan yearly stats failed user_id=%d err=%v", userID, err)
+				logging.FromContext(ctx).Error("history.repository.CalculateReadingStatistics: scan yearly stats failed", "user_id", userID, "err", err)
 			}
 		}
 	}
 
+	stats.TotalReadingTimeHours, err = r.readingTimeHours(ctx, userID, stats.TotalChaptersRead)
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.repository.CalculateReadingStatistics: reading time failed", "user_id", userID, "err", err)
+	}
+
+	stats.FavoriteGenres, err = r.favoriteGenres(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.repository.CalculateReadingStatistics: favorite genres failed", "user_id", userID, "err", err)
+		stats.FavoriteGenres = []GenreStat{}
+	}
+
+	streaks, _, err := r.readingStreaks(ctx, userID, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.repository.CalculateReadingStatistics: reading streaks failed", "user_id", userID, "err", err)
+	} else {
+		stats.CurrentStreakDays = streaks.Current
+		stats.LongestStreakDays = streaks.Longest
+	}
+
 	stats.LastCalculatedAt = time.Now()
 
 	return stats, nil
 }
 
+// estimatedMinutesPerChapter approximates reading time for users without sessions
+const estimatedMinutesPerChapter = 5
+
+// readingTimeHours prefers recorded session time and falls back to the
+// per-chapter estimate when the user has no sessions.
+func (r *Repository) readingTimeHours(ctx context.Context, userID int64, chaptersRead int) (float64, error) {
+	seconds, sessions, err := r.SumReadingSessionSeconds(ctx, userID, MaxReadingSessionDuration, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	hours := float64(chaptersRead*estimatedMinutesPerChapter) / 60
+	if sessions > 0 {
+		hours = float64(seconds) / 3600
+	}
+	return float64(int(hours*100+0.5)) / 100, nil
+}
+
+// favoriteGenreLimit is how many genres CalculateReadingStatistics reports
+const favoriteGenreLimit = 5
+
+// favoriteGenres ranks the tags of the user's library titles by how many
+// titles carry them, then by chapters finished. Dropped titles do not count.
+func (r *Repository) favoriteGenres(ctx context.Context, userID int64) ([]GenreStat, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+        SELECT
+            t.name,
+            COUNT(DISTINCT lib.manga_id) AS manga_count,
+            (SELECT COUNT(*)
+             FROM reading_history rh
+             JOIN manga_tags rmt ON rmt.manga_id = rh.manga_id
+             WHERE rh.user_id = ? AND rh.event_type = 'finished_chapter' AND rmt.tag_id = t.id) AS chapters
+        FROM libraries lib
+        JOIN manga_tags mt ON mt.manga_id = lib.manga_id
+        JOIN tags t ON t.id = mt.tag_id
+        WHERE lib.user_id = ? AND lib.status <> 'dropped'
+        GROUP BY t.id, t.name
+        ORDER BY manga_count DESC, chapters DESC, t.name
+        LIMIT ?
+    `, userID, userID, favoriteGenreLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []GenreStat{}
+	for rows.Next() {
+		var stat GenreStat
+		if err := rows.Scan(&stat.Genre, &stat.Count, &stat.Chapters); err != nil {
+			return nil, err
+		}
+		genres = append(genres, stat)
+	}
+	return genres, rows.Err()
+}
+
 // SaveReadingStatistics persists cached stats
 func (r *Repository) SaveReadingStatistics(ctx context.Context, stats *ReadingStatistics) error {
 	favoriteGenresJSON, err := json.Marshal(stats.FavoriteGenres)
@@ -508,7 +832,6 @@ INSERT INTO reading_statistics (
     monthly_stats = excluded.monthly_stats,
     yearly_stats = excluded.yearly_stats,
     last_calculated_at = excluded.last_calculated_at
-    )
 `,
 		stats.UserID,
 		stats.TotalChaptersRead,
@@ -527,6 +850,30 @@ INSERT INTO reading_statistics (
 	return err
 }
 
+// ActiveReaderIDs lists users with reading history since the given time
+func (r *Repository) ActiveReaderIDs(ctx context.Context, since time.Time) ([]int64, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+        SELECT DISTINCT user_id
+        FROM reading_history
+        WHERE created_at >= ?
+        ORDER BY user_id
+    `, since.UTC().Format(goalTimeFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
 // GetCachedReadingStatistics retrieves cached copy
 func (r *Repository) GetCachedReadingStatistics(ctx context.Context, userID int64) (*ReadingStatistics, error) {
 	query := `
@@ -549,7 +896,7 @@ func (r *Repository) GetCachedReadingStatistics(ctx context.Context, userID int6
 	var stats ReadingStatistics
 	var favoriteGenresJSON, monthlyStatsJSON, yearlyStatsJSON sql.NullString
 	var lastCalculatedAt sql.NullTime
-	log.Printf("history.repository.GetCachedReadingStatistics: fetching cache for user_id=%d", userID)
+	logging.FromContext(ctx).Info("history.repository.GetCachedReadingStatistics: fetching cache", "user_id", userID)
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(
 		&stats.TotalChaptersRead,
 		&stats.TotalMangaRead,
@@ -588,25 +935,228 @@ func (r *Repository) GetCachedReadingStatistics(ctx context.Context, userID int6
 	return &stats, nil
 }
 
+// goalGenreFilter keeps history rows of manga tagged with the goal's genre, or all rows
+// when the goal has no genre
+const goalGenreFilter = `
+              AND (reading_goals.genre IS NULL OR EXISTS (
+                  SELECT 1
+                  FROM manga_tags mt
+                  JOIN tags t ON t.id = mt.tag_id
+                  WHERE mt.manga_id = rh.manga_id AND t.name = reading_goals.genre
+              ))`
+
+// goalValueExpr computes a goal's current value from reading history inside its period.
+// Reading time is not recorded yet, so reading_time goals keep their stored value.
+const goalValueExpr = `
+    CASE reading_goals.goal_type
+        WHEN 'chapters' THEN (
+            SELECT COUNT(*)
+            FROM reading_history rh
+            WHERE rh.user_id = reading_goals.user_id AND rh.event_type = 'finished_chapter'
+              AND rh.created_at >= reading_goals.period_start AND rh.created_at < reading_goals.period_end` + goalGenreFilter + `
+        )
+        WHEN 'manga' THEN (
+            SELECT COUNT(DISTINCT rh.manga_id)
+            FROM reading_history rh
+            WHERE rh.user_id = reading_goals.user_id AND rh.event_type = 'finished_manga'
+              AND rh.created_at >= reading_goals.period_start AND rh.created_at < reading_goals.period_end` + goalGenreFilter + `
+        )
+        ELSE COALESCE(reading_goals.current_value, 0)
+    END`
+
+// goalTimeFormat matches CURRENT_TIMESTAMP so period bounds compare correctly with history rows
+const goalTimeFormat = "2006-01-02 15:04:05"
+
+const goalColumns = `id, user_id, goal_type, target_value, COALESCE(current_value, 0), period_type, COALESCE(genre, ''), period_start, period_end, status, created_at, updated_at, notified`
+
 // UpdateReadingGoalProgress updates goal progress values
 func (r *Repository) UpdateReadingGoalProgress(ctx context.Context, userID int64) error {
 	_, err := r.db.ExecContext(ctx, `
         UPDATE reading_goals
-        SET current_value = (
-            SELECT COALESCE(COUNT(*), 0)
-            FROM reading_history rh
-            WHERE rh.user_id = reading_goals.user_id AND rh.event_type = 'finished_chapter'
-        ),
+        SET current_value = `+goalValueExpr+`,
         updated_at = CURRENT_TIMESTAMP
         WHERE user_id = ?
     `, userID)
 	return err
 }
 
+// CreateReadingGoal inserts a goal and computes its current value from existing history
+func (r *Repository) CreateReadingGoal(ctx context.Context, goal *ReadingGoal) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+        INSERT INTO reading_goals (user_id, goal_type, target_value, current_value, period_type, genre, period_start, period_end, status)
+        VALUES (?, ?, ?, 0, ?, ?, ?, ?, 'active')
+    `, goal.UserID, goal.GoalType, goal.TargetValue, goal.PeriodType, goalGenre(goal),
+		goal.StartDate.UTC().Format(goalTimeFormat), goal.EndDate.UTC().Format(goalTimeFormat))
+	if err != nil {
+		return 0, err
+	}
+	goalID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+        UPDATE reading_goals SET current_value = `+goalValueExpr+` WHERE id = ?
+    `, goalID); err != nil {
+		return 0, err
+	}
+
+	return goalID, tx.Commit()
+}
+
+// UpdateReadingGoal saves goal fields and recomputes its current value
+func (r *Repository) UpdateReadingGoal(ctx context.Context, goal *ReadingGoal) error {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE reading_goals
+        SET goal_type = ?, target_value = ?, period_type = ?, genre = ?, period_start = ?, period_end = ?,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND user_id = ?
+    `, goal.GoalType, goal.TargetValue, goal.PeriodType, goalGenre(goal),
+		goal.StartDate.UTC().Format(goalTimeFormat), goal.EndDate.UTC().Format(goalTimeFormat),
+		goal.GoalID, goal.UserID)
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+        UPDATE reading_goals SET current_value = `+goalValueExpr+` WHERE id = ?
+    `, goal.GoalID)
+	return err
+}
+
+// UpdateReadingGoalStatus stores a goal's status
+func (r *Repository) UpdateReadingGoalStatus(ctx context.Context, goalID int64, status string) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE reading_goals SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+    `, status, goalID)
+	return err
+}
+
+// ClaimGoalNotification sets the goal's notified flag and reports whether this
+// call set it, so only one caller announces a completion
+func (r *Repository) ClaimGoalNotification(ctx context.Context, goalID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE reading_goals SET notified = 1 WHERE id = ? AND notified = 0`, goalID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// DeleteReadingGoal removes a user's goal
+func (r *Repository) DeleteReadingGoal(ctx context.Context, userID, goalID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM reading_goals WHERE id = ? AND user_id = ?`, goalID, userID)
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// goalGenre stores an unscoped goal's genre as NULL
+func goalGenre(goal *ReadingGoal) interface{} {
+	if goal.Genre == "" {
+		return nil
+	}
+	return goal.Genre
+}
+
+// GenreName returns the stored spelling of a genre matched case-insensitively,
+// or an empty string when no such genre exists
+func (r *Repository) GenreName(ctx context.Context, genre string) (string, error) {
+	var name string
+	err := r.db.QueryRowContext(ctx, `
+        SELECT name FROM tags WHERE name = ? COLLATE NOCASE LIMIT 1
+    `, genre).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
+
+// GetReadingGoal retrieves a single goal owned by the user
+func (r *Repository) GetReadingGoal(ctx context.Context, userID, goalID int64) (*ReadingGoal, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT `+goalColumns+`
+        FROM reading_goals
+        WHERE id = ? AND user_id = ?
+    `, goalID, userID)
+	goal, err := scanReadingGoal(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// GetReadingGoals retrieves all of a user's goals, newest period first
+func (r *Repository) GetReadingGoals(ctx context.Context, userID int64) ([]ReadingGoal, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+goalColumns+`
+        FROM reading_goals
+        WHERE user_id = ?
+        ORDER BY period_end DESC, id DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []ReadingGoal
+	for rows.Next() {
+		goal, err := scanReadingGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, *goal)
+	}
+	return goals, rows.Err()
+}
+
+type goalScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReadingGoal(row goalScanner) (*ReadingGoal, error) {
+	var goal ReadingGoal
+	if err := row.Scan(
+		&goal.GoalID,
+		&goal.UserID,
+		&goal.GoalType,
+		&goal.TargetValue,
+		&goal.CurrentValue,
+		&goal.PeriodType,
+		&goal.Genre,
+		&goal.StartDate,
+		&goal.EndDate,
+		&goal.Status,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+		&goal.notified,
+	); err != nil {
+		return nil, err
+	}
+	goal.Completed = goal.Status == "completed"
+	return &goal, nil
+}
+
 // GetActiveReadingGoals retrieves goals still active
 func (r *Repository) GetActiveReadingGoals(ctx context.Context, userID int64) ([]ReadingGoal, error) {
 	rows, err := r.db.QueryContext(ctx, `
-        SELECT id, user_id, goal_type, target_value, current_value, period_start, period_end, status, created_at, updated_at
+        SELECT `+goalColumns+`
         FROM reading_goals
         WHERE user_id = ? AND status = 'active'
     `, userID)
@@ -617,23 +1167,105 @@ func (r *Repository) GetActiveReadingGoals(ctx context.Context, userID int64) ([
 
 	var goals []ReadingGoal
 	for rows.Next() {
-		var goal ReadingGoal
-		var status string
-		if err := rows.Scan(
-			&goal.GoalID,
-			&goal.UserID,
-			&goal.GoalType,
-			&goal.TargetValue,
-			&goal.CurrentValue,
-			&goal.StartDate,
-			&goal.EndDate,
-			&status,
-			&goal.CreatedAt,
-			&goal.UpdatedAt,
-		); err == nil {
-			goal.Completed = status == "completed"
-			goals = append(goals, goal)
+		if goal, err := scanReadingGoal(rows); err == nil {
+			goal.Progress = goalProgress(goal.CurrentValue, goal.TargetValue)
+			goals = append(goals, *goal)
 		}
 	}
 	return goals, rows.Err()
 }
+
+// MaxReadingSessionDuration caps sessions that were never ended, or ended long after the reader left
+const MaxReadingSessionDuration = 2 * time.Hour
+
+// CreateReadingSession opens a session starting at startedAt
+func (r *Repository) CreateReadingSession(ctx context.Context, userID, mangaID int64, chapterID *int64, startedAt time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO Reading_Sessions (User_Id, Manga_Id, Chapter_Id, Started_At)
+        VALUES (?, ?, ?, ?)
+    `, userID, mangaID, chapterID, startedAt.UTC().Format(goalTimeFormat))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// GetReadingSession loads one of the user's sessions, or nil when it does not exist
+func (r *Repository) GetReadingSession(ctx context.Context, userID, sessionID int64) (*ReadingSession, error) {
+	var session ReadingSession
+	var chapterID, duration sql.NullInt64
+	var endedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+        SELECT Session_Id, User_Id, Manga_Id, Chapter_Id, Started_At, Ended_At, Duration_Seconds
+        FROM Reading_Sessions
+        WHERE Session_Id = ? AND User_Id = ?
+    `, sessionID, userID).Scan(&session.SessionID, &session.UserID, &session.MangaID, &chapterID,
+		&session.StartedAt, &endedAt, &duration)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if chapterID.Valid {
+		session.ChapterID = &chapterID.Int64
+	}
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+	}
+	session.DurationSeconds = int(duration.Int64)
+	return &session, nil
+}
+
+// EndReadingSession closes an open session; it reports false when the session was already ended
+func (r *Repository) EndReadingSession(ctx context.Context, sessionID int64, endedAt time.Time, durationSeconds int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE Reading_Sessions
+        SET Ended_At = ?, Duration_Seconds = ?
+        WHERE Session_Id = ? AND Ended_At IS NULL
+    `, endedAt.UTC().Format(goalTimeFormat), durationSeconds, sessionID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+// CloseOpenReadingSessions ends every open session of the user at now,
+// capping each duration at maxDuration
+func (r *Repository) CloseOpenReadingSessions(ctx context.Context, userID int64, now time.Time, maxDuration time.Duration) error {
+	nowStr := now.UTC().Format(goalTimeFormat)
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE Reading_Sessions
+        SET Ended_At = ?,
+            Duration_Seconds = MAX(0, MIN(CAST(strftime('%s', ?) AS INTEGER) - CAST(strftime('%s', Started_At) AS INTEGER), ?))
+        WHERE User_Id = ? AND Ended_At IS NULL
+    `, nowStr, nowStr, int(maxDuration.Seconds()), userID)
+	return err
+}
+
+// SumReadingSessionSeconds totals the user's recorded reading time. Sessions
+// still open after maxDuration are treated as abandoned and count as
+// maxDuration; younger open sessions are still running and count as nothing.
+func (r *Repository) SumReadingSessionSeconds(ctx context.Context, userID int64, maxDuration time.Duration, now time.Time) (int64, int, error) {
+	exists, err := r.tableExists(ctx, "Reading_Sessions")
+	if err != nil || !exists {
+		return 0, 0, err
+	}
+	maxSeconds := int64(maxDuration.Seconds())
+	cutoff := now.Add(-maxDuration).UTC().Format(goalTimeFormat)
+	var total int64
+	var sessions int
+	err = r.db.QueryRowContext(ctx, `
+        SELECT
+            COALESCE(SUM(CASE
+                WHEN Duration_Seconds IS NOT NULL THEN Duration_Seconds
+                WHEN Started_At <= ? THEN ?
+                ELSE 0
+            END), 0),
+            COUNT(CASE WHEN Duration_Seconds IS NOT NULL OR Started_At <= ? THEN 1 END)
+        FROM Reading_Sessions
+        WHERE User_Id = ?
+    `, cutoff, maxSeconds, cutoff, userID).Scan(&total, &sessions)
+	return total, sessions, err
+}
@@ -6,23 +6,65 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
 )
 
 var (
-	ErrInvalidChapterNumber = errors.New("invalid chapter number")
-	ErrMangaNotFound        = errors.New("manga not found")
-	ErrMangaNotInLibrary    = errors.New("manga not in library")
-	ErrDatabaseError        = errors.New("database error")
-	ErrNoData               = errors.New("no data available")
+	ErrInvalidChapterNumber  = errors.New("invalid chapter number")
+	ErrMangaNotFound         = errors.New("manga not found")
+	ErrMangaNotInLibrary     = errors.New("manga not in library")
+	ErrDatabaseError         = errors.New("database error")
+	ErrNoData                = errors.New("no data available")
+	ErrGoalNotFound          = errors.New("reading goal not found")
+	ErrInvalidGoalType       = errors.New("goal_type must be one of: chapters, manga, reading_time")
+	ErrInvalidPeriodType     = errors.New("period_type must be one of: daily, weekly, monthly, yearly")
+	ErrInvalidGoalPeriod     = errors.New("period_end must be after period_start")
+	ErrInvalidGoalTarget     = errors.New("target_value must be positive")
+	ErrUnknownGoalGenre      = errors.New("genre does not match any known genre")
+	ErrEmptyProgressBatch    = errors.New("progress batch is empty")
+	ErrProgressBatchTooLarge = fmt.Errorf("progress batch exceeds %d items", MaxProgressBatchSize)
+	ErrNoProgressIDs         = errors.New("manga_ids must not be empty")
+	ErrTooManyProgressIDs    = fmt.Errorf("at most %d manga_ids may be requested at once", MaxProgressLookupIDs)
+	ErrInvalidProgressID     = errors.New("manga_ids must be a comma-separated list of positive ids")
+	ErrFutureReadAt          = errors.New("read_at cannot be in the future")
+	ErrSessionNotFound       = errors.New("reading session not found")
+	ErrSessionAlreadyEnded   = errors.New("reading session already ended")
+	ErrInvalidFreezeDate     = fmt.Errorf("date must be a YYYY-MM-DD day before today and within the last %d days", streakFreezeWindowDays)
+	ErrFreezeNotNeeded       = errors.New("you already read on that day")
+	ErrDayAlreadyFrozen      = errors.New("that day is already frozen")
+	ErrNoStreakFreezes       = errors.New("no streak freezes left for that month")
+	ErrInvalidActivityType   = errors.New("types must be a comma-separated list of: completed_manga, review, rating, read")
 )
 
+// MaxProgressBatchSize caps the number of items accepted by BatchUpdateProgress
+const MaxProgressBatchSize = 100
+
+// MaxProgressLookupIDs caps how many manga GetProgressBatch looks up at once
+const MaxProgressLookupIDs = 100
+
+var validGoalTypes = map[string]bool{
+	"chapters":     true,
+	"manga":        true,
+	"reading_time": true,
+}
+
+var validPeriodTypes = map[string]bool{
+	"daily":   true,
+	"weekly":  true,
+	"monthly": true,
+	"yearly":  true,
+}
+
 // ChapterService exposes chapter operations needed by history
 type ChapterService interface {
 	ValidateChapter(ctx context.Context, mangaID int64, chapter int) (*pkgchapter.ChapterSummary, error)
 	GetChapterCount(ctx context.Context, mangaID int64) (int, error)
+	GetMaxChapterNumber(ctx context.Context, mangaID int64) (int, error)
 }
 
 // LibraryChecker verifies library membership
@@ -40,13 +82,26 @@ type MangaChecker interface {
 	Exists(ctx context.Context, mangaID int64) (bool, error)
 }
 
+// GoalNotifier is told when a user completes a reading goal
+type GoalNotifier interface {
+	GoalCompleted(ctx context.Context, goal *ReadingGoal) error
+}
+
+// PopularityNotifier is told when reading progress changes a manga's activity
+type PopularityNotifier interface {
+	ProgressChanged(ctx context.Context, mangaID int64)
+}
+
 // Service manages history use cases
 type Service struct {
-	repo           *Repository
-	chapterService ChapterService
-	libraryChecker LibraryChecker
-	broadcaster    Broadcaster
-	mangaChecker   MangaChecker
+	repo               *Repository
+	chapterService     ChapterService
+	libraryChecker     LibraryChecker
+	broadcaster        Broadcaster
+	mangaChecker       MangaChecker
+	popularityNotifier PopularityNotifier
+	statsWorker        *StatsWorker
+	goalNotifiers      []GoalNotifier
 }
 
 // NewService builds history service
@@ -59,11 +114,34 @@ func NewService(repo *Repository, chapterSvc ChapterService, libraryChecker Libr
 	}
 }
 
+// SetReadReplica routes analytics and statistics reads to a read replica
+func (s *Service) SetReadReplica(replica *sql.DB) {
+	s.repo.SetReader(replica)
+}
+
 // SetBroadcaster injects optional broadcaster
 func (s *Service) SetBroadcaster(b Broadcaster) {
 	s.broadcaster = b
 }
 
+// SetPopularityNotifier configures who is told about reading activity, e.g. to
+// refresh cached popular lists
+func (s *Service) SetPopularityNotifier(n PopularityNotifier) {
+	s.popularityNotifier = n
+}
+
+// SetGoalNotifiers announces completed goals through each notifier, e.g. one
+// that stores a notification and others that push it live
+func (s *Service) SetGoalNotifiers(notifiers ...GoalNotifier) {
+	s.goalNotifiers = notifiers
+}
+
+func (s *Service) notifyProgressChanged(ctx context.Context, mangaID int64) {
+	if s.popularityNotifier != nil {
+		s.popularityNotifier.ProgressChanged(ctx, mangaID)
+	}
+}
+
 // GetProgress returns user's progress
 func (s *Service) GetProgress(ctx context.Context, userID, mangaID int64) (*UserProgress, error) {
 	progress, err := s.repo.GetUserProgress(ctx, userID, mangaID)
@@ -73,6 +151,34 @@ func (s *Service) GetProgress(ctx context.Context, userID, mangaID int64) (*User
 	return progress, nil
 }
 
+// GetProgressBatch returns the user's progress for several manga, keyed by
+// manga ID. Manga the user has not started are absent from the map.
+func (s *Service) GetProgressBatch(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]*UserProgress, error) {
+	if len(mangaIDs) == 0 {
+		return nil, ErrNoProgressIDs
+	}
+	seen := make(map[int64]bool, len(mangaIDs))
+	ids := make([]int64, 0, len(mangaIDs))
+	for _, id := range mangaIDs {
+		if id <= 0 {
+			return nil, ErrInvalidProgressID
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxProgressLookupIDs {
+		return nil, ErrTooManyProgressIDs
+	}
+
+	progress, err := s.repo.GetUserProgressBatch(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return progress, nil
+}
+
 // HasCompletedManga reports whether the user finished the manga in their library
 func (s *Service) HasCompletedManga(ctx context.Context, userID, mangaID int64) (bool, error) {
 	completed, err := s.repo.IsMangaCompleted(ctx, userID, mangaID)
@@ -129,14 +235,15 @@ func (s *Service) UpdateProgress(ctx context.Context, userID, mangaID int64, req
 		return nil, ErrInvalidChapterNumber
 	}
 
-	if existingProgress != nil && req.CurrentChapter <= existingProgress.CurrentChapter {
+	if existingProgress != nil && req.CurrentChapter == existingProgress.CurrentChapter && !req.Force {
 		if existingProgress.ProgressPercent == 0 {
 			existingProgress.ProgressPercent = math.Min(100, (float64(existingProgress.CurrentChapter)/float64(totalChapters))*100)
 		}
 		return &UpdateProgressResponse{
-			Message:      "progress unchanged",
-			UserProgress: existingProgress,
-			Broadcasted:  false,
+			Message:             "progress unchanged",
+			UserProgress:        existingProgress,
+			Broadcasted:         false,
+			EstimatedCompletion: s.estimateCompletion(ctx, userID, mangaID, existingProgress.CurrentChapter),
 		}, nil
 	}
 
@@ -148,13 +255,24 @@ func (s *Service) UpdateProgress(ctx context.Context, userID, mangaID int64, req
 		chapterID = &id
 	}
 
-	if err := s.repo.UpdateProgress(ctx, userID, mangaID, req.CurrentChapter, chapterID, progressPercent); err != nil {
+	// Highest chapter wins unless the caller forces a backwards move; another
+	// device may have advanced progress since existingProgress was read
+	reconciled, err := s.repo.ReconcileProgress(ctx, userID, mangaID, req.CurrentChapter, chapterID, progressPercent, req.Force)
+	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
+	if reconciled.Applied {
+		s.notifyProgressChanged(ctx, mangaID)
+		if reconciled.Chapter >= totalChapters {
+			// Finishing a manga moves most statistics at once
+			s.ScheduleRecompute(userID)
+		}
+	}
 
+	// Devices converge on the reconciled chapter, not the raw input
 	broadcasted := false
 	if s.broadcaster != nil {
-		if err := s.broadcaster.BroadcastProgress(ctx, userID, mangaID, req.CurrentChapter, chapterID); err == nil {
+		if err := s.broadcaster.BroadcastProgress(ctx, userID, mangaID, reconciled.Chapter, reconciled.ChapterID); err == nil {
 			broadcasted = true
 		}
 	}
@@ -164,21 +282,213 @@ func (s *Service) UpdateProgress(ctx context.Context, userID, mangaID int64, req
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 
+	if !reconciled.Applied {
+		return &UpdateProgressResponse{
+			Message:             fmt.Sprintf("progress kept at chapter %d; use force to move backwards", reconciled.Chapter),
+			UserProgress:        progress,
+			Broadcasted:         broadcasted,
+			Conflict:            true,
+			EstimatedCompletion: s.estimateCompletion(ctx, userID, mangaID, reconciled.Chapter),
+		}, nil
+	}
+
 	_ = s.repo.RecordActivity(ctx, userID, "READ", &mangaID, map[string]interface{}{
 		"current_chapter": req.CurrentChapter,
 		"chapter_id":      chapterID,
 	})
 
 	return &UpdateProgressResponse{
-		Message:      "progress updated successfully",
-		UserProgress: progress,
-		Broadcasted:  broadcasted,
+		Message:             "progress updated successfully",
+		UserProgress:        progress,
+		Broadcasted:         broadcasted,
+		EstimatedCompletion: s.estimateCompletion(ctx, userID, mangaID, reconciled.Chapter),
 	}, nil
 }
 
-// GetFriendsActivityFeed returns friend activities
-func (s *Service) GetFriendsActivityFeed(ctx context.Context, userID int64, page, limit int) (*ActivityFeedResponse, error) {
-	activities, total, err := s.repo.GetFriendsActivities(ctx, userID, page, limit)
+// BatchUpdateProgress applies a batch of offline progress entries in a single
+// transaction. Each item is validated on its own and reported in the results;
+// only the highest valid chapter per manga is written and broadcast.
+func (s *Service) BatchUpdateProgress(ctx context.Context, userID int64, items []BatchProgressItem) (*BatchUpdateProgressResponse, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyProgressBatch
+	}
+	if len(items) > MaxProgressBatchSize {
+		return nil, ErrProgressBatchTooLarge
+	}
+	if s.mangaChecker == nil {
+		return nil, fmt.Errorf("%w: manga checker not configured", ErrDatabaseError)
+	}
+
+	type mangaState struct {
+		err           error
+		totalChapters int
+		current       int
+		winner        int
+		chapterID     *int64
+		readAt        *time.Time
+	}
+
+	results := make([]BatchProgressResult, len(items))
+	states := make(map[int64]*mangaState)
+	var order []int64
+	now := time.Now()
+
+	for i, item := range items {
+		results[i] = BatchProgressResult{Index: i, MangaID: item.MangaID, CurrentChapter: item.CurrentChapter}
+
+		state, seen := states[item.MangaID]
+		if !seen {
+			state = &mangaState{winner: -1}
+			states[item.MangaID] = state
+			order = append(order, item.MangaID)
+			if err := s.loadBatchMangaState(ctx, userID, item.MangaID, &state.err, &state.totalChapters, &state.current); err != nil {
+				return nil, err
+			}
+		}
+		if state.err != nil {
+			results[i].Status, results[i].Error = BatchStatusInvalid, state.err.Error()
+			continue
+		}
+		if item.CurrentChapter < 1 {
+			results[i].Status, results[i].Error = BatchStatusInvalid, ErrInvalidChapterNumber.Error()
+			continue
+		}
+		if item.ReadAt != nil && item.ReadAt.After(now) {
+			results[i].Status, results[i].Error = BatchStatusInvalid, ErrFutureReadAt.Error()
+			continue
+		}
+
+		summary, err := s.chapterService.ValidateChapter(ctx, item.MangaID, item.CurrentChapter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		if summary == nil {
+			results[i].Status, results[i].Error = BatchStatusInvalid, ErrInvalidChapterNumber.Error()
+			continue
+		}
+
+		if item.CurrentChapter <= state.current {
+			results[i].Status = BatchStatusUnchanged
+			continue
+		}
+		if state.winner >= 0 {
+			results[state.winner].Status = BatchStatusSuperseded
+		}
+		results[i].Status = BatchStatusApplied
+		state.winner = i
+		state.current = item.CurrentChapter
+		state.readAt = item.ReadAt
+		state.chapterID = nil
+		if summary.ID != 0 {
+			id := summary.ID
+			state.chapterID = &id
+		}
+	}
+
+	var writes []ProgressWrite
+	for _, mangaID := range order {
+		state := states[mangaID]
+		if state.winner < 0 {
+			continue
+		}
+		writes = append(writes, ProgressWrite{
+			MangaID:         mangaID,
+			Chapter:         state.current,
+			ChapterID:       state.chapterID,
+			ProgressPercent: math.Min(100, (float64(state.current)/float64(state.totalChapters))*100),
+			ReadAt:          state.readAt,
+		})
+	}
+
+	resp := &BatchUpdateProgressResponse{Results: results}
+	if len(writes) == 0 {
+		return resp, nil
+	}
+	// The states above were read outside the transaction; another device may
+	// have advanced progress since, so the repository guard has the last word
+	reconciled, err := s.repo.ApplyProgressBatch(ctx, userID, writes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	for i, w := range writes {
+		mangaID := w.MangaID
+		result := reconciled[i]
+		if !result.Applied {
+			results[states[mangaID].winner].Status = BatchStatusUnchanged
+			continue
+		}
+		resp.Applied++
+		s.notifyProgressChanged(ctx, mangaID)
+		if s.broadcaster != nil {
+			if err := s.broadcaster.BroadcastProgress(ctx, userID, mangaID, result.Chapter, result.ChapterID); err == nil {
+				resp.Broadcasted++
+			}
+		}
+		_ = s.repo.RecordActivity(ctx, userID, "READ", &mangaID, map[string]interface{}{
+			"current_chapter": result.Chapter,
+			"chapter_id":      result.ChapterID,
+		})
+	}
+	if resp.Applied > 0 {
+		// An offline sync usually covers many chapters at once
+		s.ScheduleRecompute(userID)
+	}
+
+	return resp, nil
+}
+
+// loadBatchMangaState runs the per-manga checks of a batch once. Validation
+// failures are reported through itemErr; only infrastructure errors are returned.
+func (s *Service) loadBatchMangaState(ctx context.Context, userID, mangaID int64, itemErr *error, totalChapters, current *int) error {
+	exists, err := s.mangaChecker.Exists(ctx, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !exists {
+		*itemErr = ErrMangaNotFound
+		return nil
+	}
+
+	inLibrary, err := s.libraryChecker.CheckLibraryExists(ctx, userID, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !inLibrary {
+		*itemErr = ErrMangaNotInLibrary
+		return nil
+	}
+
+	count, err := s.chapterService.GetChapterCount(ctx, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if count < 1 {
+		*itemErr = ErrInvalidChapterNumber
+		return nil
+	}
+	*totalChapters = count
+
+	existing, err := s.repo.GetUserProgress(ctx, userID, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if existing != nil {
+		*current = existing.CurrentChapter
+	}
+	return nil
+}
+
+// GetFriendsActivityFeed returns friend activities, restricted to the given
+// activity types; an empty list returns every type
+func (s *Service) GetFriendsActivityFeed(ctx context.Context, userID int64, page, limit int, types []string) (*ActivityFeedResponse, error) {
+	types, err := normalizeActivityTypes(types)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
+
+	activities, total, err := s.repo.GetFriendsActivities(ctx, userID, page, limit, types)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return &ActivityFeedResponse{
@@ -206,17 +516,48 @@ func (s *Service) GetFriendsActivityFeed(ctx context.Context, userID int64, page
 	return resp, nil
 }
 
-// GetReadingStatistics returns cached/calculated stats
+// normalizeActivityTypes lowercases and deduplicates requested feed types
+func normalizeActivityTypes(types []string) ([]string, error) {
+	seen := make(map[string]bool, len(types))
+	normalized := make([]string, 0, len(types))
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		if !validActivityTypes[t] {
+			return nil, ErrInvalidActivityType
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	return normalized, nil
+}
+
+// GetReadingStatistics returns cached/calculated stats. The background
+// StatsWorker keeps the cache of active readers fresh, so recalculating here
+// is mostly limited to force and to users it has not seen.
 func (s *Service) GetReadingStatistics(ctx context.Context, userID int64, force bool) (*ReadingStatistics, error) {
 	if !force {
 		cached, err := s.repo.GetCachedReadingStatistics(ctx, userID)
 		if err == nil && cached != nil {
-			if time.Since(cached.LastCalculatedAt) < time.Hour {
+			if time.Since(cached.LastCalculatedAt) < statsCacheTTL {
+				cached.StreakFreezesRemaining = s.streakFreezesRemaining(ctx, userID)
 				return cached, nil
 			}
 		}
 	}
 
+	stats, err := s.recomputeStatistics(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats.StreakFreezesRemaining = s.streakFreezesRemaining(ctx, userID)
+	return stats, nil
+}
+
+// recomputeStatistics aggregates a user's statistics and refreshes the cache
+func (s *Service) recomputeStatistics(ctx context.Context, userID int64) (*ReadingStatistics, error) {
 	stats, err := s.repo.CalculateReadingStatistics(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
@@ -229,12 +570,160 @@ func (s *Service) GetReadingStatistics(ctx context.Context, userID int64, force
 	}
 
 	if err := s.repo.SaveReadingStatistics(ctx, stats); err != nil {
-		// ignore cache error
+		// A stale cache only costs the next read a recalculation
+		logging.FromContext(ctx).Warn("history.service.recomputeStatistics: cache save failed", "user_id", userID, "err", err)
 	}
-
 	return stats, nil
 }
 
+// streakFreezesRemaining counts this month's unused freezes; lookup errors
+// report none left rather than failing the statistics request
+func (s *Service) streakFreezesRemaining(ctx context.Context, userID int64) int {
+	frozen, err := s.repo.StreakFreezeDays(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.service.streakFreezesRemaining: lookup failed", "user_id", userID, "err", err)
+		return 0
+	}
+	return freezesLeftIn(frozen, time.Now().UTC())
+}
+
+// ApplyStreakFreeze spends a freeze on a recent missed day so it no longer
+// breaks the user's streak
+func (s *Service) ApplyStreakFreeze(ctx context.Context, userID int64, req StreakFreezeRequest) (*StreakFreezeResponse, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	day := today.AddDate(0, 0, -1)
+	if strings.TrimSpace(req.Date) != "" {
+		parsed, err := time.Parse(streakDayFormat, strings.TrimSpace(req.Date))
+		if err != nil {
+			return nil, ErrInvalidFreezeDate
+		}
+		day = parsed
+	}
+	if !day.Before(today) || day.Before(today.AddDate(0, 0, -streakFreezeWindowDays)) {
+		return nil, ErrInvalidFreezeDate
+	}
+	key := day.Format(streakDayFormat)
+
+	read, err := s.repo.HasReadingOn(ctx, userID, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if read {
+		return nil, ErrFreezeNotNeeded
+	}
+
+	frozen, err := s.repo.StreakFreezeDays(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if frozen[key] {
+		return nil, ErrDayAlreadyFrozen
+	}
+	if freezesLeftIn(frozen, day) == 0 {
+		return nil, ErrNoStreakFreezes
+	}
+
+	inserted, err := s.repo.InsertStreakFreeze(ctx, userID, key, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !inserted {
+		return nil, ErrDayAlreadyFrozen
+	}
+
+	streaks, frozen, err := s.repo.readingStreaks(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &StreakFreezeResponse{
+		FrozenDate:        key,
+		FreezesRemaining:  freezesLeftIn(frozen, now),
+		CurrentStreakDays: streaks.Current,
+		LongestStreakDays: streaks.Longest,
+	}, nil
+}
+
+// FavoriteGenres returns the user's most read genres from their reading statistics.
+// Users without statistics yet get an empty list.
+func (s *Service) FavoriteGenres(ctx context.Context, userID int64) ([]GenreStat, error) {
+	stats, err := s.GetReadingStatistics(ctx, userID, false)
+	if err != nil {
+		if errors.Is(err, ErrNoData) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return stats.FavoriteGenres, nil
+}
+
+// StartReadingSession opens a timed reading session. Any session the user
+// left open is closed first, capped at MaxReadingSessionDuration.
+func (s *Service) StartReadingSession(ctx context.Context, userID int64, req StartReadingSessionRequest) (*ReadingSession, error) {
+	if s.mangaChecker != nil {
+		exists, err := s.mangaChecker.Exists(ctx, req.MangaID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		if !exists {
+			return nil, ErrMangaNotFound
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := s.repo.CloseOpenReadingSessions(ctx, userID, now, MaxReadingSessionDuration); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	sessionID, err := s.repo.CreateReadingSession(ctx, userID, req.MangaID, req.ChapterID, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &ReadingSession{
+		SessionID: sessionID,
+		UserID:    userID,
+		MangaID:   req.MangaID,
+		ChapterID: req.ChapterID,
+		StartedAt: now,
+	}, nil
+}
+
+// EndReadingSession closes a session and records its duration, capped at
+// MaxReadingSessionDuration for sessions left running.
+func (s *Service) EndReadingSession(ctx context.Context, userID int64, req EndReadingSessionRequest) (*ReadingSession, error) {
+	session, err := s.repo.GetReadingSession(ctx, userID, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.EndedAt != nil {
+		return nil, ErrSessionAlreadyEnded
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	duration := now.Sub(session.StartedAt)
+	if duration < 0 {
+		duration = 0
+	}
+	if duration > MaxReadingSessionDuration {
+		duration = MaxReadingSessionDuration
+		session.Capped = true
+	}
+
+	ended, err := s.repo.EndReadingSession(ctx, session.SessionID, now, int(duration.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !ended {
+		return nil, ErrSessionAlreadyEnded
+	}
+	session.EndedAt = &now
+	session.DurationSeconds = int(duration.Seconds())
+	return session, nil
+}
+
 // RecordActivity proxies to the repository to allow other services to reuse the activity feed.
 func (s *Service) RecordActivity(ctx context.Context, userID int64, activityType string, mangaID *int64, payload map[string]interface{}) error {
 	return s.repo.RecordActivity(ctx, userID, activityType, mangaID, payload)
@@ -303,6 +792,14 @@ func (s *Service) GetReadingSummary(ctx context.Context, userID int64) (*Reading
 	if summary == nil {
 		return &ReadingSummary{}, nil
 	}
+
+	streaks, frozen, err := s.repo.readingStreaks(ctx, userID, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.service.GetReadingSummary: reading streaks failed", "user_id", userID, "err", err)
+		return summary, nil
+	}
+	summary.ReadingStreak = streaks.Current
+	summary.StreakFreezesRemaining = freezesLeftIn(frozen, time.Now().UTC())
 	return summary, nil
 }
 
@@ -312,19 +809,259 @@ func (s *Service) GetReadingAnalyticsBuckets(ctx context.Context, userID int64)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return &ReadingAnalyticsResponse{
-				Daily:   []ReadingAnalyticsPoint{},
-				Weekly:  []ReadingAnalyticsPoint{},
-				Monthly: []ReadingAnalyticsPoint{},
+				Daily:         []ReadingAnalyticsPoint{},
+				Weekly:        []ReadingAnalyticsPoint{},
+				Monthly:       []ReadingAnalyticsPoint{},
+				MonthlyGenres: []GenreAnalyticsPoint{},
 			}, nil
 		}
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 	if resp == nil {
 		return &ReadingAnalyticsResponse{
-			Daily:   []ReadingAnalyticsPoint{},
-			Weekly:  []ReadingAnalyticsPoint{},
-			Monthly: []ReadingAnalyticsPoint{},
+			Daily:         []ReadingAnalyticsPoint{},
+			Weekly:        []ReadingAnalyticsPoint{},
+			Monthly:       []ReadingAnalyticsPoint{},
+			MonthlyGenres: []GenreAnalyticsPoint{},
 		}, nil
 	}
 	return resp, nil
 }
+
+// CreateGoal validates and stores a reading goal, counting progress already made in the period
+func (s *Service) CreateGoal(ctx context.Context, userID int64, req CreateGoalRequest) (*ReadingGoal, error) {
+	goal := &ReadingGoal{
+		UserID:      userID,
+		GoalType:    req.GoalType,
+		TargetValue: req.TargetValue,
+		PeriodType:  req.PeriodType,
+		StartDate:   req.PeriodStart,
+		EndDate:     req.PeriodEnd,
+		Genre:       req.Genre,
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	if err := s.resolveGoalGenre(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	goalID, err := s.repo.CreateReadingGoal(ctx, goal)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.loadGoal(ctx, userID, goalID)
+}
+
+// ListGoals returns the user's goals with refreshed progress
+func (s *Service) ListGoals(ctx context.Context, userID int64) ([]ReadingGoal, error) {
+	return s.refreshGoals(ctx, userID)
+}
+
+// refreshGoals recomputes the user's goal progress and statuses, announcing
+// goals that were just completed
+func (s *Service) refreshGoals(ctx context.Context, userID int64) ([]ReadingGoal, error) {
+	if err := s.repo.UpdateReadingGoalProgress(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	goals, err := s.repo.GetReadingGoals(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if goals == nil {
+		goals = []ReadingGoal{}
+	}
+	for i := range goals {
+		if err := s.syncGoalStatus(ctx, &goals[i]); err != nil {
+			return nil, err
+		}
+	}
+	return goals, nil
+}
+
+// UpdateGoal applies a partial update to a user's goal and recomputes its progress
+func (s *Service) UpdateGoal(ctx context.Context, userID, goalID int64, req UpdateGoalRequest) (*ReadingGoal, error) {
+	goal, err := s.repo.GetReadingGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if goal == nil {
+		return nil, ErrGoalNotFound
+	}
+
+	if req.GoalType != nil {
+		goal.GoalType = *req.GoalType
+	}
+	if req.TargetValue != nil {
+		goal.TargetValue = *req.TargetValue
+	}
+	if req.PeriodType != nil {
+		goal.PeriodType = *req.PeriodType
+	}
+	if req.PeriodStart != nil {
+		goal.StartDate = *req.PeriodStart
+	}
+	if req.PeriodEnd != nil {
+		goal.EndDate = *req.PeriodEnd
+	}
+	if req.Genre != nil {
+		goal.Genre = *req.Genre
+	}
+	if err := validateGoal(goal); err != nil {
+		return nil, err
+	}
+	if err := s.resolveGoalGenre(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateReadingGoal(ctx, goal); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.loadGoal(ctx, userID, goalID)
+}
+
+// DeleteGoal removes a user's goal
+func (s *Service) DeleteGoal(ctx context.Context, userID, goalID int64) error {
+	if err := s.repo.DeleteReadingGoal(ctx, userID, goalID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrGoalNotFound
+		}
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return nil
+}
+
+func (s *Service) loadGoal(ctx context.Context, userID, goalID int64) (*ReadingGoal, error) {
+	goal, err := s.repo.GetReadingGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if goal == nil {
+		return nil, ErrGoalNotFound
+	}
+	if err := s.syncGoalStatus(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// syncGoalStatus derives status and progress from the current value and period,
+// persisting the status when it changed
+func (s *Service) syncGoalStatus(ctx context.Context, goal *ReadingGoal) error {
+	status := "active"
+	switch {
+	case goal.CurrentValue >= goal.TargetValue:
+		status = "completed"
+	case time.Now().After(goal.EndDate):
+		status = "failed"
+	}
+
+	if status != goal.Status {
+		if err := s.repo.UpdateReadingGoalStatus(ctx, goal.GoalID, status); err != nil {
+			return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		goal.Status = status
+		if status == "completed" {
+			s.ScheduleRecompute(goal.UserID)
+		}
+	}
+	if status == "completed" && !goal.notified {
+		s.notifyGoalCompleted(ctx, goal)
+	}
+	goal.Completed = status == "completed"
+	goal.Progress = goalProgress(goal.CurrentValue, goal.TargetValue)
+	return nil
+}
+
+// notifyGoalCompleted announces a completed goal once. The notified flag is
+// claimed first so concurrent refreshes cannot both announce it; without
+// notifiers it is left for a service that has them.
+func (s *Service) notifyGoalCompleted(ctx context.Context, goal *ReadingGoal) {
+	if len(s.goalNotifiers) == 0 {
+		return
+	}
+	claimed, err := s.repo.ClaimGoalNotification(ctx, goal.GoalID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("history.service.notifyGoalCompleted: claim failed", "goal_id", goal.GoalID, "err", err)
+		return
+	}
+	goal.notified = true
+	if !claimed {
+		return
+	}
+	for _, n := range s.goalNotifiers {
+		if err := n.GoalCompleted(ctx, goal); err != nil {
+			logging.FromContext(ctx).Warn("history.service.notifyGoalCompleted: notifier failed", "goal_id", goal.GoalID, "err", err)
+		}
+	}
+}
+
+// resolveGoalGenre checks that a genre-scoped goal names an existing genre and
+// stores the genre's canonical spelling so progress queries match it exactly
+func (s *Service) resolveGoalGenre(ctx context.Context, goal *ReadingGoal) error {
+	goal.Genre = strings.TrimSpace(goal.Genre)
+	if goal.Genre == "" {
+		return nil
+	}
+	name, err := s.repo.GenreName(ctx, goal.Genre)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if name == "" {
+		return ErrUnknownGoalGenre
+	}
+	goal.Genre = name
+	return nil
+}
+
+func validateGoal(goal *ReadingGoal) error {
+	if !validGoalTypes[goal.GoalType] {
+		return ErrInvalidGoalType
+	}
+	if !validPeriodTypes[goal.PeriodType] {
+		return ErrInvalidPeriodType
+	}
+	if goal.TargetValue <= 0 {
+		return ErrInvalidGoalTarget
+	}
+	if !goal.EndDate.After(goal.StartDate) {
+		return ErrInvalidGoalPeriod
+	}
+	return nil
+}
+
+// CompletionMessage tells the user the goal is done, e.g. "You completed
+// your monthly goal of 10 chapters"
+func (g *ReadingGoal) CompletionMessage() string {
+	unit := g.GoalType
+	switch g.GoalType {
+	case "reading_time":
+		unit = "minutes of reading"
+	case "manga":
+		unit = "manga"
+		if g.Genre != "" {
+			unit = g.Genre + " manga"
+		}
+	case "chapters":
+		if g.Genre != "" {
+			unit = g.Genre + " chapters"
+		}
+	}
+	return fmt.Sprintf("You completed your %s goal of %d %s", g.PeriodType, g.TargetValue, unit)
+}
+
+// goalProgress returns completion as a percentage capped at 100
+func goalProgress(current, target int) float64 {
+	if target <= 0 {
+		return 0
+	}
+	progress := float64(current) / float64(target) * 100
+	if progress > 100 {
+		progress = 100
+	}
+	return math.Round(progress*100) / 100
+}
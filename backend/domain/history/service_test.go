@@ -0,0 +1,1143 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupGoalTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE reading_history (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        chapter_id INTEGER,
+        event_type TEXT NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+    CREATE TABLE reading_goals (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        goal_type TEXT NOT NULL,
+        target_value INTEGER NOT NULL,
+        current_value INTEGER DEFAULT 0,
+        period_type TEXT NOT NULL,
+        genre TEXT,
+        period_start DATETIME NOT NULL,
+        period_end DATETIME NOT NULL,
+        status TEXT NOT NULL DEFAULT 'active',
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        notified INTEGER NOT NULL DEFAULT 0
+    );
+    CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE);
+    CREATE TABLE manga_tags (manga_id INTEGER NOT NULL, tag_id INTEGER NOT NULL, PRIMARY KEY (manga_id, tag_id));
+    INSERT INTO reading_history (user_id, manga_id, event_type, created_at) VALUES
+        (1, 1, 'finished_chapter', datetime('now', '-1 day')),
+        (1, 1, 'finished_chapter', datetime('now', '-2 days')),
+        (1, 2, 'finished_chapter', datetime('now', '-40 days')),
+        (2, 1, 'finished_chapter', datetime('now', '-1 day'));
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestCreateGoalComputesProgressFromPeriod(t *testing.T) {
+	svc := NewService(NewRepository(setupGoalTestDB(t)), nil, nil, nil)
+	now := time.Now()
+
+	goal, err := svc.CreateGoal(context.Background(), 1, CreateGoalRequest{
+		GoalType:    "chapters",
+		TargetValue: 4,
+		PeriodType:  "monthly",
+		PeriodStart: now.AddDate(0, 0, -30),
+		PeriodEnd:   now.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal returned error: %v", err)
+	}
+	if goal.CurrentValue != 2 {
+		t.Fatalf("expected current value 2, got %d", goal.CurrentValue)
+	}
+	if goal.Progress != 50 {
+		t.Fatalf("expected progress 50, got %v", goal.Progress)
+	}
+	if goal.Status != "active" {
+		t.Fatalf("expected active status, got %q", goal.Status)
+	}
+
+	target := 2
+	updated, err := svc.UpdateGoal(context.Background(), 1, goal.GoalID, UpdateGoalRequest{TargetValue: &target})
+	if err != nil {
+		t.Fatalf("UpdateGoal returned error: %v", err)
+	}
+	if !updated.Completed || updated.Progress != 100 {
+		t.Fatalf("expected completed goal at 100%%, got completed=%v progress=%v", updated.Completed, updated.Progress)
+	}
+
+	if err := svc.DeleteGoal(context.Background(), 2, goal.GoalID); !errors.Is(err, ErrGoalNotFound) {
+		t.Fatalf("expected ErrGoalNotFound deleting another user's goal, got %v", err)
+	}
+	if err := svc.DeleteGoal(context.Background(), 1, goal.GoalID); err != nil {
+		t.Fatalf("DeleteGoal returned error: %v", err)
+	}
+}
+
+func TestCreateGoalValidation(t *testing.T) {
+	svc := NewService(NewRepository(setupGoalTestDB(t)), nil, nil, nil)
+	now := time.Now()
+
+	cases := []struct {
+		name string
+		req  CreateGoalRequest
+		want error
+	}{
+		{"goal type", CreateGoalRequest{GoalType: "pages", TargetValue: 1, PeriodType: "daily", PeriodStart: now, PeriodEnd: now.Add(time.Hour)}, ErrInvalidGoalType},
+		{"period type", CreateGoalRequest{GoalType: "manga", TargetValue: 1, PeriodType: "hourly", PeriodStart: now, PeriodEnd: now.Add(time.Hour)}, ErrInvalidPeriodType},
+		{"period order", CreateGoalRequest{GoalType: "manga", TargetValue: 1, PeriodType: "daily", PeriodStart: now, PeriodEnd: now}, ErrInvalidGoalPeriod},
+	}
+	for _, tc := range cases {
+		if _, err := svc.CreateGoal(context.Background(), 1, tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestGenreGoalCountsOnlyMatchingManga(t *testing.T) {
+	db := setupGoalTestDB(t)
+	if _, err := db.Exec(`
+    INSERT INTO tags (id, name) VALUES (1, 'Romance'), (2, 'Fantasy');
+    INSERT INTO manga_tags (manga_id, tag_id) VALUES (10, 1), (11, 2);
+    `); err != nil {
+		t.Fatalf("failed to seed genres: %v", err)
+	}
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	req := CreateGoalRequest{
+		GoalType:    "chapters",
+		TargetValue: 2,
+		PeriodType:  "monthly",
+		PeriodStart: now.AddDate(0, 0, -1),
+		PeriodEnd:   now.AddDate(0, 0, 30),
+		Genre:       "Cooking",
+	}
+	if _, err := svc.CreateGoal(ctx, 5, req); !errors.Is(err, ErrUnknownGoalGenre) {
+		t.Fatalf("expected ErrUnknownGoalGenre, got %v", err)
+	}
+
+	req.Genre = "fantasy"
+	goal, err := svc.CreateGoal(ctx, 5, req)
+	if err != nil {
+		t.Fatalf("CreateGoal returned error: %v", err)
+	}
+	if goal.Genre != "Fantasy" {
+		t.Fatalf("expected the stored genre spelling, got %q", goal.Genre)
+	}
+
+	currentValue := func() int {
+		t.Helper()
+		goals, err := svc.ListGoals(ctx, 5)
+		if err != nil || len(goals) != 1 {
+			t.Fatalf("ListGoals returned %v (err %v)", goals, err)
+		}
+		return goals[0].CurrentValue
+	}
+
+	if _, err := db.Exec(`INSERT INTO reading_history (user_id, manga_id, event_type) VALUES (5, 10, 'finished_chapter')`); err != nil {
+		t.Fatalf("failed to record reading: %v", err)
+	}
+	if got := currentValue(); got != 0 {
+		t.Fatalf("expected a romance chapter to leave the fantasy goal at 0, got %d", got)
+	}
+
+	if _, err := db.Exec(`INSERT INTO reading_history (user_id, manga_id, event_type) VALUES (5, 11, 'finished_chapter')`); err != nil {
+		t.Fatalf("failed to record reading: %v", err)
+	}
+	if got := currentValue(); got != 1 {
+		t.Fatalf("expected a fantasy chapter to advance the goal to 1, got %d", got)
+	}
+
+	analytics, err := svc.GetReadingAnalyticsBuckets(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetReadingAnalyticsBuckets returned error: %v", err)
+	}
+	month := now.UTC().Format("2006-01") + "-01"
+	want := []GenreAnalyticsPoint{
+		{Date: month, Genre: "Fantasy", ChaptersRead: 1},
+		{Date: month, Genre: "Romance", ChaptersRead: 1},
+	}
+	if !reflect.DeepEqual(analytics.MonthlyGenres, want) {
+		t.Fatalf("unexpected genre breakdown: got %+v, want %+v", analytics.MonthlyGenres, want)
+	}
+}
+
+type fakeMangaChecker map[int64]bool
+
+func (f fakeMangaChecker) Exists(ctx context.Context, mangaID int64) (bool, error) {
+	return f[mangaID], nil
+}
+
+func (f fakeMangaChecker) CheckLibraryExists(ctx context.Context, userID, mangaID int64) (bool, error) {
+	return f[mangaID], nil
+}
+
+type sqlChapterService struct{ db *sql.DB }
+
+func (s sqlChapterService) ValidateChapter(ctx context.Context, mangaID int64, chapter int) (*pkgchapter.ChapterSummary, error) {
+	var summary pkgchapter.ChapterSummary
+	err := s.db.QueryRowContext(ctx, `SELECT id, manga_id, number FROM chapters WHERE manga_id = ? AND number = ?`, mangaID, chapter).
+		Scan(&summary.ID, &summary.MangaID, &summary.Number)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &summary, err
+}
+
+func (s sqlChapterService) GetChapterCount(ctx context.Context, mangaID int64) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM chapters WHERE manga_id = ?`, mangaID).Scan(&count)
+	return count, err
+}
+
+func (s sqlChapterService) GetMaxChapterNumber(ctx context.Context, mangaID int64) (int, error) {
+	var max int
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(number), 0) FROM chapters WHERE manga_id = ?`, mangaID).Scan(&max)
+	return max, err
+}
+
+type recordingBroadcaster struct {
+	mu    sync.Mutex
+	calls []int
+}
+
+func (b *recordingBroadcaster) BroadcastProgress(ctx context.Context, userID, mangaID int64, chapter int, chapterID *int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, chapter)
+	return nil
+}
+
+func setupBatchProgressDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../db/migrations/023_progress_history.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	schema := `
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL
+    );
+    CREATE TABLE reading_progress (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        current_chapter_id INTEGER,
+        current_page INTEGER,
+        progress_percent REAL,
+        last_read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (user_id, manga_id)
+    );
+    INSERT INTO chapters (manga_id, number) VALUES (1, 1), (1, 2), (1, 3), (1, 4), (2, 1), (2, 2);
+    INSERT INTO reading_progress (user_id, manga_id, current_chapter_id, progress_percent) VALUES (1, 2, 6, 100);
+    `
+	if _, err := db.Exec(schema + string(migration)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestBatchUpdateProgressMixesValidAndInvalidChapters(t *testing.T) {
+	db := setupBatchProgressDB(t)
+	checker := fakeMangaChecker{1: true, 2: true}
+	svc := NewService(NewRepository(db), sqlChapterService{db}, checker, checker)
+	broadcaster := &recordingBroadcaster{}
+	svc.SetBroadcaster(broadcaster)
+	worker := NewStatsWorker(svc, time.Hour)
+	svc.SetStatsWorker(worker)
+
+	readAt := time.Now().Add(-time.Hour)
+	resp, err := svc.BatchUpdateProgress(context.Background(), 1, []BatchProgressItem{
+		{MangaID: 1, CurrentChapter: 2},
+		{MangaID: 1, CurrentChapter: 9},
+		{MangaID: 1, CurrentChapter: 3, ReadAt: &readAt},
+		{MangaID: 1, CurrentChapter: 0},
+		{MangaID: 2, CurrentChapter: 1},
+		{MangaID: 3, CurrentChapter: 1},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpdateProgress returned error: %v", err)
+	}
+
+	want := []string{BatchStatusSuperseded, BatchStatusInvalid, BatchStatusApplied, BatchStatusInvalid, BatchStatusUnchanged, BatchStatusInvalid}
+	for i, status := range want {
+		if resp.Results[i].Status != status {
+			t.Fatalf("item %d: expected status %q, got %q (%s)", i, status, resp.Results[i].Status, resp.Results[i].Error)
+		}
+	}
+	if resp.Results[5].Error != ErrMangaNotFound.Error() {
+		t.Fatalf("expected manga not found for unknown manga, got %q", resp.Results[5].Error)
+	}
+	if resp.Applied != 1 {
+		t.Fatalf("expected 1 applied write, got %d", resp.Applied)
+	}
+	if len(broadcaster.calls) != 1 || broadcaster.calls[0] != 3 {
+		t.Fatalf("expected a single broadcast of chapter 3, got %v", broadcaster.calls)
+	}
+
+	progress, err := svc.GetProgress(context.Background(), 1, 1)
+	if err != nil || progress == nil {
+		t.Fatalf("GetProgress returned progress=%v err=%v", progress, err)
+	}
+	if progress.CurrentChapter != 3 || progress.ProgressPercent != 75 {
+		t.Fatalf("expected chapter 3 at 75%%, got chapter %d at %v", progress.CurrentChapter, progress.ProgressPercent)
+	}
+	if !progress.LastReadAt.Equal(readAt.UTC().Truncate(time.Second)) {
+		t.Fatalf("expected last_read_at %v, got %v", readAt.UTC().Truncate(time.Second), progress.LastReadAt)
+	}
+
+	var requested, applied int
+	if err := db.QueryRow(`SELECT Requested_Chapter, Applied_Chapter FROM Progress_History WHERE User_Id = 1 AND Manga_Id = 1`).Scan(&requested, &applied); err != nil {
+		t.Fatalf("expected the batch write to be logged to Progress_History: %v", err)
+	}
+	if requested != 3 || applied != 3 {
+		t.Fatalf("expected history 3 -> 3, got %d -> %d", requested, applied)
+	}
+	if len(worker.queue) != 1 {
+		t.Fatalf("expected a statistics recompute to be scheduled, got %d", len(worker.queue))
+	}
+}
+
+// racingChapterService lets another device write progress after a batch has
+// read its starting state but before the batch is applied
+type racingChapterService struct {
+	sqlChapterService
+	race func()
+}
+
+func (s racingChapterService) ValidateChapter(ctx context.Context, mangaID int64, chapter int) (*pkgchapter.ChapterSummary, error) {
+	s.race()
+	return s.sqlChapterService.ValidateChapter(ctx, mangaID, chapter)
+}
+
+func TestBatchUpdateProgressKeepsNewerOnlineWrite(t *testing.T) {
+	db := setupBatchProgressDB(t)
+	ctx := context.Background()
+	checker := fakeMangaChecker{1: true}
+	onlineSvc := NewService(NewRepository(db), sqlChapterService{db}, checker, checker)
+
+	// The online device reaches chapter 4 while the offline batch for
+	// chapter 3 is already in flight
+	raced := false
+	race := func() {
+		if raced {
+			return
+		}
+		raced = true
+		if _, err := onlineSvc.UpdateProgress(ctx, 1, 1, UpdateProgressRequest{CurrentChapter: 4}); err != nil {
+			t.Fatalf("online UpdateProgress returned error: %v", err)
+		}
+	}
+	offlineSvc := NewService(NewRepository(db), racingChapterService{sqlChapterService{db}, race}, checker, checker)
+	broadcaster := &recordingBroadcaster{}
+	offlineSvc.SetBroadcaster(broadcaster)
+
+	readAt := time.Now().Add(-time.Hour)
+	resp, err := offlineSvc.BatchUpdateProgress(ctx, 1, []BatchProgressItem{{MangaID: 1, CurrentChapter: 3, ReadAt: &readAt}})
+	if err != nil {
+		t.Fatalf("BatchUpdateProgress returned error: %v", err)
+	}
+	if resp.Applied != 0 || resp.Results[0].Status != BatchStatusUnchanged {
+		t.Fatalf("expected the stale offline write to be left unapplied, got applied=%d status=%q", resp.Applied, resp.Results[0].Status)
+	}
+	if len(broadcaster.calls) != 0 {
+		t.Fatalf("expected no broadcast for an unapplied write, got %v", broadcaster.calls)
+	}
+
+	progress, err := onlineSvc.GetProgress(ctx, 1, 1)
+	if err != nil || progress == nil {
+		t.Fatalf("GetProgress returned progress=%v err=%v", progress, err)
+	}
+	if progress.CurrentChapter != 4 {
+		t.Fatalf("expected the newer online chapter 4 to win, got %d", progress.CurrentChapter)
+	}
+
+	var conflicts int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM Progress_History WHERE Manga_Id = 1 AND Requested_Chapter = 3 AND Applied_Chapter = 4 AND Is_Conflict = 1`).Scan(&conflicts); err != nil {
+		t.Fatalf("failed to read Progress_History: %v", err)
+	}
+	if conflicts != 1 {
+		t.Fatalf("expected the overridden offline write to be logged as a conflict, got %d", conflicts)
+	}
+}
+
+func TestBatchUpdateProgressRejectsOversizedBatch(t *testing.T) {
+	svc := NewService(NewRepository(setupGoalTestDB(t)), nil, nil, fakeMangaChecker{})
+	items := make([]BatchProgressItem, MaxProgressBatchSize+1)
+	if _, err := svc.BatchUpdateProgress(context.Background(), 1, items); !errors.Is(err, ErrProgressBatchTooLarge) {
+		t.Fatalf("expected ErrProgressBatchTooLarge, got %v", err)
+	}
+	if _, err := svc.BatchUpdateProgress(context.Background(), 1, nil); !errors.Is(err, ErrEmptyProgressBatch) {
+		t.Fatalf("expected ErrEmptyProgressBatch, got %v", err)
+	}
+}
+
+func setupSessionTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupGoalTestDB(t)
+	schema := `
+    CREATE TABLE libraries (user_id INTEGER, manga_id INTEGER, status TEXT);
+    CREATE TABLE ratings (user_id INTEGER, manga_id INTEGER, score INTEGER);
+    CREATE TABLE Reading_Sessions (
+        Session_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+        User_Id INTEGER NOT NULL,
+        Manga_Id INTEGER NOT NULL,
+        Chapter_Id INTEGER,
+        Started_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        Ended_At DATETIME,
+        Duration_Seconds INTEGER
+    );
+    INSERT INTO reading_history (user_id, manga_id, event_type) VALUES
+        (1, 3, 'finished_chapter'), (1, 3, 'finished_chapter'), (1, 3, 'finished_chapter');
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestReadingTimeFallsBackToEstimateWithoutSessions(t *testing.T) {
+	svc := NewService(NewRepository(setupSessionTestDB(t)), nil, nil, nil)
+
+	stats, err := svc.GetReadingStatistics(context.Background(), 1, true)
+	if err != nil {
+		t.Fatalf("GetReadingStatistics returned error: %v", err)
+	}
+	// 6 finished chapters at 5 minutes each
+	if stats.TotalChaptersRead != 6 || stats.TotalReadingTimeHours != 0.5 {
+		t.Fatalf("expected 6 chapters and 0.5h estimate, got %d chapters and %vh", stats.TotalChaptersRead, stats.TotalReadingTimeHours)
+	}
+}
+
+func TestReadingSessionsOverrideEstimate(t *testing.T) {
+	db := setupSessionTestDB(t)
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	session, err := svc.StartReadingSession(ctx, 1, StartReadingSessionRequest{MangaID: 1})
+	if err != nil {
+		t.Fatalf("StartReadingSession returned error: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE Reading_Sessions SET Started_At = datetime('now', '-30 minutes') WHERE Session_Id = ?`, session.SessionID); err != nil {
+		t.Fatalf("failed to backdate session: %v", err)
+	}
+	ended, err := svc.EndReadingSession(ctx, 1, EndReadingSessionRequest{SessionID: session.SessionID})
+	if err != nil {
+		t.Fatalf("EndReadingSession returned error: %v", err)
+	}
+	if ended.DurationSeconds < 1799 || ended.DurationSeconds > 1801 || ended.Capped {
+		t.Fatalf("expected an uncapped 30 minute session, got %ds capped=%v", ended.DurationSeconds, ended.Capped)
+	}
+	if _, err := svc.EndReadingSession(ctx, 1, EndReadingSessionRequest{SessionID: session.SessionID}); !errors.Is(err, ErrSessionAlreadyEnded) {
+		t.Fatalf("expected ErrSessionAlreadyEnded, got %v", err)
+	}
+	if _, err := svc.EndReadingSession(ctx, 2, EndReadingSessionRequest{SessionID: session.SessionID}); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for another user, got %v", err)
+	}
+
+	// An abandoned session counts as the cap; one still running counts as nothing
+	if _, err := db.Exec(`
+        INSERT INTO Reading_Sessions (User_Id, Manga_Id, Started_At) VALUES
+            (1, 1, datetime('now', '-5 hours')),
+            (1, 1, datetime('now', '-1 minute'))
+    `); err != nil {
+		t.Fatalf("failed to insert open sessions: %v", err)
+	}
+
+	stats, err := svc.GetReadingStatistics(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("GetReadingStatistics returned error: %v", err)
+	}
+	if stats.TotalReadingTimeHours != 2.5 {
+		t.Fatalf("expected 2.5h of recorded time, got %vh", stats.TotalReadingTimeHours)
+	}
+}
+
+func TestStartReadingSessionCapsAbandonedSession(t *testing.T) {
+	db := setupSessionTestDB(t)
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	first, err := svc.StartReadingSession(ctx, 1, StartReadingSessionRequest{MangaID: 1})
+	if err != nil {
+		t.Fatalf("StartReadingSession returned error: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE Reading_Sessions SET Started_At = datetime('now', '-3 hours') WHERE Session_Id = ?`, first.SessionID); err != nil {
+		t.Fatalf("failed to backdate session: %v", err)
+	}
+	if _, err := svc.StartReadingSession(ctx, 1, StartReadingSessionRequest{MangaID: 2}); err != nil {
+		t.Fatalf("StartReadingSession returned error: %v", err)
+	}
+
+	abandoned, err := NewRepository(db).GetReadingSession(ctx, 1, first.SessionID)
+	if err != nil {
+		t.Fatalf("GetReadingSession returned error: %v", err)
+	}
+	if abandoned.EndedAt == nil || abandoned.DurationSeconds != int(MaxReadingSessionDuration.Seconds()) {
+		t.Fatalf("expected abandoned session closed at the cap, got ended=%v duration=%d", abandoned.EndedAt, abandoned.DurationSeconds)
+	}
+}
+
+// setupProgressConflictDB returns a file-backed database so concurrent updates
+// really run on separate connections
+func setupProgressConflictDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "progress.db")
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../db/migrations/023_progress_history.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	schema := `
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL
+    );
+    CREATE TABLE reading_progress (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        current_chapter_id INTEGER,
+        current_page INTEGER,
+        progress_percent REAL,
+        last_read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (user_id, manga_id)
+    );
+    INSERT INTO chapters (manga_id, number) VALUES (1, 1), (1, 2), (1, 3), (1, 4), (1, 5), (1, 6), (1, 7), (1, 8), (1, 9), (1, 10);
+    `
+	if _, err := db.Exec(schema + string(migration)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestConcurrentProgressUpdatesKeepHighestChapter(t *testing.T) {
+	for round := 0; round < 10; round++ {
+		db := setupProgressConflictDB(t)
+		checker := fakeMangaChecker{1: true}
+		svc := NewService(NewRepository(db), sqlChapterService{db}, checker, checker)
+		broadcaster := &recordingBroadcaster{}
+		svc.SetBroadcaster(broadcaster)
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		errs := make(chan error, 2)
+		for _, chapter := range []int{8, 5} {
+			wg.Add(1)
+			go func(chapter int) {
+				defer wg.Done()
+				<-start
+				_, err := svc.UpdateProgress(context.Background(), 1, 1, UpdateProgressRequest{CurrentChapter: chapter})
+				errs <- err
+			}(chapter)
+		}
+		close(start)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("UpdateProgress returned error: %v", err)
+			}
+		}
+
+		progress, err := svc.GetProgress(context.Background(), 1, 1)
+		if err != nil || progress == nil {
+			t.Fatalf("GetProgress returned progress=%v err=%v", progress, err)
+		}
+		if progress.CurrentChapter != 8 {
+			t.Fatalf("round %d: expected chapter 8 to stick, got %d", round, progress.CurrentChapter)
+		}
+
+		var conflicts, applied int
+		if err := db.QueryRow(`SELECT COALESCE(SUM(Is_Conflict), 0), COALESCE(MAX(Applied_Chapter), 0) FROM Progress_History`).Scan(&conflicts, &applied); err != nil {
+			t.Fatalf("failed to read progress history: %v", err)
+		}
+		if applied != 8 {
+			t.Fatalf("round %d: expected history to record chapter 8 as applied, got %d", round, applied)
+		}
+		// The lower update only conflicts when it lands after the higher one
+		if conflicts > 1 {
+			t.Fatalf("round %d: expected at most one conflict, got %d", round, conflicts)
+		}
+		broadcaster.mu.Lock()
+		last := broadcaster.calls[len(broadcaster.calls)-1]
+		broadcaster.mu.Unlock()
+		if conflicts == 1 && last != 8 {
+			t.Fatalf("round %d: expected the reconciled chapter 8 to be broadcast, got %v", round, broadcaster.calls)
+		}
+	}
+}
+
+func TestUpdateProgressReportsConflictAndHonoursForce(t *testing.T) {
+	db := setupProgressConflictDB(t)
+	checker := fakeMangaChecker{1: true}
+	svc := NewService(NewRepository(db), sqlChapterService{db}, checker, checker)
+	broadcaster := &recordingBroadcaster{}
+	svc.SetBroadcaster(broadcaster)
+	ctx := context.Background()
+
+	if _, err := svc.UpdateProgress(ctx, 1, 1, UpdateProgressRequest{CurrentChapter: 6}); err != nil {
+		t.Fatalf("UpdateProgress returned error: %v", err)
+	}
+
+	resp, err := svc.UpdateProgress(ctx, 1, 1, UpdateProgressRequest{CurrentChapter: 2})
+	if err != nil {
+		t.Fatalf("UpdateProgress returned error: %v", err)
+	}
+	if !resp.Conflict || resp.UserProgress.CurrentChapter != 6 {
+		t.Fatalf("expected conflict keeping chapter 6, got conflict=%v chapter=%d", resp.Conflict, resp.UserProgress.CurrentChapter)
+	}
+	if got := broadcaster.calls[len(broadcaster.calls)-1]; got != 6 {
+		t.Fatalf("expected reconciled chapter 6 to be broadcast, got %d", got)
+	}
+
+	resp, err = svc.UpdateProgress(ctx, 1, 1, UpdateProgressRequest{CurrentChapter: 2, Force: true})
+	if err != nil {
+		t.Fatalf("UpdateProgress returned error: %v", err)
+	}
+	if resp.Conflict || resp.UserProgress.CurrentChapter != 2 {
+		t.Fatalf("expected forced update to chapter 2, got conflict=%v chapter=%d", resp.Conflict, resp.UserProgress.CurrentChapter)
+	}
+
+	var conflicts, forced int
+	if err := db.QueryRow(`SELECT SUM(Is_Conflict), SUM(Is_Forced) FROM Progress_History`).Scan(&conflicts, &forced); err != nil {
+		t.Fatalf("failed to read progress history: %v", err)
+	}
+	if conflicts != 1 || forced != 1 {
+		t.Fatalf("expected 1 conflict and 1 forced entry, got %d and %d", conflicts, forced)
+	}
+}
+
+func TestReadingStreakFreezeBridgesSingleMissedDay(t *testing.T) {
+	today := time.Date(2026, 10, 5, 15, 0, 0, 0, time.UTC)
+	days := []string{"2026-10-01", "2026-10-02", "2026-10-04", "2026-10-05"}
+
+	res := calculateReadingStreaks(days, map[string]bool{}, StreakFreezesPerMonth, today)
+	if res.Current != 4 || res.Longest != 4 {
+		t.Fatalf("expected the one-day gap to be bridged, got current=%d longest=%d", res.Current, res.Longest)
+	}
+	if len(res.NewFreezes) != 1 || res.NewFreezes[0] != "2026-10-03" {
+		t.Fatalf("expected a freeze to be consumed for 2026-10-03, got %v", res.NewFreezes)
+	}
+
+	// A day frozen earlier is reused rather than consuming another freeze
+	res = calculateReadingStreaks(days, map[string]bool{"2026-10-03": true}, StreakFreezesPerMonth, today)
+	if res.Current != 4 || len(res.NewFreezes) != 0 {
+		t.Fatalf("expected the recorded freeze to be reused, got current=%d new=%v", res.Current, res.NewFreezes)
+	}
+
+	// Without freezes left the gap breaks the streak
+	res = calculateReadingStreaks(days, map[string]bool{}, 0, today)
+	if res.Current != 2 || res.Longest != 2 {
+		t.Fatalf("expected the streak to break without freezes, got current=%d longest=%d", res.Current, res.Longest)
+	}
+}
+
+func TestReadingStreakTwoMissedDaysStillBreak(t *testing.T) {
+	today := time.Date(2026, 10, 6, 9, 0, 0, 0, time.UTC)
+	days := []string{"2026-10-01", "2026-10-02", "2026-10-03", "2026-10-06"}
+
+	res := calculateReadingStreaks(days, map[string]bool{}, StreakFreezesPerMonth, today)
+	if res.Current != 1 || res.Longest != 3 {
+		t.Fatalf("expected a two-day gap to break the streak, got current=%d longest=%d", res.Current, res.Longest)
+	}
+	if len(res.NewFreezes) != 0 {
+		t.Fatalf("expected no freezes to be consumed, got %v", res.NewFreezes)
+	}
+
+	// Two days without reading up to today also ends the current streak
+	res = calculateReadingStreaks([]string{"2026-10-03"}, map[string]bool{}, StreakFreezesPerMonth, today)
+	if res.Current != 0 || res.Longest != 1 {
+		t.Fatalf("expected no current streak, got current=%d longest=%d", res.Current, res.Longest)
+	}
+}
+
+func TestApplyStreakFreezeManually(t *testing.T) {
+	db := setupGoalTestDB(t)
+	migration, err := os.ReadFile("../../db/migrations/024_streak_freezes.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO reading_history (user_id, manga_id, event_type, created_at) VALUES
+        (5, 1, 'finished_chapter', datetime('now', '-3 days')),
+        (5, 1, 'finished_chapter', datetime('now', '-2 days'))`); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	resp, err := svc.ApplyStreakFreeze(ctx, 5, StreakFreezeRequest{})
+	if err != nil {
+		t.Fatalf("ApplyStreakFreeze returned error: %v", err)
+	}
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	if resp.FrozenDate != yesterday || resp.CurrentStreakDays != 2 {
+		t.Fatalf("expected yesterday frozen with a 2 day streak, got %+v", resp)
+	}
+
+	if _, err := svc.ApplyStreakFreeze(ctx, 5, StreakFreezeRequest{Date: yesterday}); !errors.Is(err, ErrDayAlreadyFrozen) {
+		t.Fatalf("expected ErrDayAlreadyFrozen, got %v", err)
+	}
+	readDay := time.Now().UTC().AddDate(0, 0, -2).Format("2006-01-02")
+	if _, err := svc.ApplyStreakFreeze(ctx, 5, StreakFreezeRequest{Date: readDay}); !errors.Is(err, ErrFreezeNotNeeded) {
+		t.Fatalf("expected ErrFreezeNotNeeded, got %v", err)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if _, err := svc.ApplyStreakFreeze(ctx, 5, StreakFreezeRequest{Date: today}); !errors.Is(err, ErrInvalidFreezeDate) {
+		t.Fatalf("expected ErrInvalidFreezeDate for today, got %v", err)
+	}
+}
+
+func TestEstimateCompletionDate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	cases := []struct {
+		remaining int
+		velocity  float64
+		want      string
+	}{
+		{30, 2, "2024-03-16"},
+		{5, 2, "2024-03-04"},
+		{0, 2, ""},
+		{10, 0, ""},
+	}
+	for _, tc := range cases {
+		if got := estimateCompletionDate(tc.remaining, tc.velocity, now); got != tc.want {
+			t.Fatalf("remaining=%d velocity=%v: expected %q, got %q", tc.remaining, tc.velocity, tc.want, got)
+		}
+	}
+}
+
+func TestUpdateProgressEstimatesCompletionFromVelocity(t *testing.T) {
+	db := setupProgressConflictDB(t)
+	schema := `
+    CREATE TABLE mangas (id INTEGER PRIMARY KEY, status TEXT);
+    INSERT INTO mangas (id, status) VALUES (1, 'completed'), (2, 'completed'), (3, 'ongoing');
+    WITH RECURSIVE n(x) AS (SELECT 11 UNION ALL SELECT x + 1 FROM n WHERE x < 40)
+    INSERT INTO chapters (manga_id, number) SELECT 1, x FROM n;
+    INSERT INTO chapters (manga_id, number) VALUES (3, 1), (3, 2);
+    INSERT INTO Progress_History (User_Id, Manga_Id, Requested_Chapter, Applied_Chapter, Is_Conflict, Created_At) VALUES
+        (1, 2, 10, 10, 0, datetime('now', '-20 days')),
+        (1, 2, 37, 37, 0, datetime('now', '-3 days')),
+        (1, 2, 5, 37, 1, datetime('now', '-2 days')),
+        (2, 2, 30, 30, 0, datetime('now', '-1 days'));
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	checker := fakeMangaChecker{1: true, 3: true}
+	svc := NewService(NewRepository(db), sqlChapterService{db}, checker, checker)
+	ctx := context.Background()
+
+	resp, err := svc.UpdateProgress(ctx, 1, 1, UpdateProgressRequest{CurrentChapter: 10})
+	if err != nil {
+		t.Fatalf("UpdateProgress returned error: %v", err)
+	}
+	// 27 chapters of manga 2 plus the first chapter logged for manga 1 over
+	// 14 days is 2 chapters a day; 30 chapters remain
+	want := time.Now().UTC().AddDate(0, 0, 15).Format(estimateDateFormat)
+	if resp.EstimatedCompletion != want {
+		t.Fatalf("expected estimated completion %q, got %q", want, resp.EstimatedCompletion)
+	}
+
+	resp, err = svc.UpdateProgress(ctx, 1, 3, UpdateProgressRequest{CurrentChapter: 1})
+	if err != nil {
+		t.Fatalf("UpdateProgress returned error: %v", err)
+	}
+	if resp.EstimatedCompletion != EstimateOngoing {
+		t.Fatalf("expected %q for an ongoing manga, got %q", EstimateOngoing, resp.EstimatedCompletion)
+	}
+}
+
+// setupActivityFeedDB seeds alice (1) whose friend bob (2) has one activity
+// of each type plus extra reviews, and a stranger (3) outside her feed who
+// only has a pending request from her. The friends and activities tables
+// come from their migration so the feed runs against the real columns.
+func setupActivityFeedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupGoalTestDB(t)
+	if _, err := db.Exec(`
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL);
+    CREATE TABLE mangas (id INTEGER PRIMARY KEY, title TEXT, cover_url TEXT);
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	migration, err := os.ReadFile("../../db/migrations/032_friends_activities.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+	schema := `
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob'), (3, 'stranger');
+    INSERT INTO mangas (id, title, cover_url) VALUES (1, 'Hero Saga', 'hero.jpg');
+    INSERT INTO friends (user_id, friend_user_id, status) VALUES (1, 2, 'accepted'), (1, 3, 'pending');
+    INSERT INTO activities (user_id, type, manga_id, created_at) VALUES
+        (2, 'REVIEW', 1, '2024-01-01 10:00:00'),
+        (2, 'COMPLETED_MANGA', 1, '2024-01-02 10:00:00'),
+        (2, 'REVIEW', 1, '2024-01-03 10:00:00'),
+        (2, 'RATING', 1, '2024-01-04 10:00:00'),
+        (2, 'READ', 1, '2024-01-05 10:00:00'),
+        (2, 'REVIEW', 1, '2024-01-06 10:00:00'),
+        (3, 'REVIEW', 1, '2024-01-07 10:00:00');
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestFriendsActivityFeedFiltersByType(t *testing.T) {
+	db := setupActivityFeedDB(t)
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	var seen []int64
+	for page := 1; page <= 2; page++ {
+		resp, err := svc.GetFriendsActivityFeed(ctx, 1, page, 2, []string{" Review ", "review"})
+		if err != nil {
+			t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+		}
+		if resp.Total != 3 || resp.Pages != 2 {
+			t.Fatalf("expected 3 reviews over 2 pages, got total=%d pages=%d", resp.Total, resp.Pages)
+		}
+		for _, activity := range resp.Activities {
+			if activity.ActivityType != "REVIEW" {
+				t.Fatalf("expected only reviews, got %q", activity.ActivityType)
+			}
+			seen = append(seen, activity.ActivityID)
+		}
+	}
+	if len(seen) != 3 || seen[0] != 6 || seen[1] != 3 || seen[2] != 1 {
+		t.Fatalf("expected reviews 6, 3, 1 newest first, got %v", seen)
+	}
+
+	all, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, nil)
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if all.Total != 6 {
+		t.Fatalf("expected every friend activity without a filter, got %d", all.Total)
+	}
+
+	if _, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, []string{"review", "likes"}); !errors.Is(err, ErrInvalidActivityType) {
+		t.Fatalf("expected ErrInvalidActivityType, got %v", err)
+	}
+}
+
+func TestFriendsActivityFeedReadsFriendshipsFromFriendRepository(t *testing.T) {
+	db := setupActivityFeedDB(t)
+	ctx := context.Background()
+
+	// Carol (4) befriends the stranger through the friend domain's own writes
+	if _, err := db.Exec(`INSERT INTO users (id, username) VALUES (4, 'carol')`); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if err := friend.NewRepository(db).CreateFriendshipBidirectional(ctx, 4, 3); err != nil {
+		t.Fatalf("CreateFriendshipBidirectional returned error: %v", err)
+	}
+
+	repo := NewRepository(db)
+	friends, err := repo.GetFriends(ctx, 4)
+	if err != nil {
+		t.Fatalf("GetFriends returned error: %v", err)
+	}
+	if len(friends) != 1 || friends[0] != 3 {
+		t.Fatalf("expected carol's only friend to be 3, got %v", friends)
+	}
+
+	feed, err := NewService(repo, nil, nil, nil).GetFriendsActivityFeed(ctx, 4, 1, 20, nil)
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if feed.Total != 1 || feed.Activities[0].Username != "stranger" {
+		t.Fatalf("expected the stranger's review in carol's feed, got %+v", feed)
+	}
+}
+
+func TestFriendsActivityFeedRespectsPrivacy(t *testing.T) {
+	db := setupActivityFeedDB(t)
+	migration, err := os.ReadFile("../../db/migrations/028_user_privacy.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	before, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, nil)
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if before.Total != 6 {
+		t.Fatalf("expected every activity to be shared by default, got %d", before.Total)
+	}
+
+	off := false
+	if _, err := user.UpdatePrivacy(ctx, db, 2, user.UpdatePrivacyRequest{ShareReviews: &off}); err != nil {
+		t.Fatalf("UpdatePrivacy returned error: %v", err)
+	}
+
+	after, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, nil)
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if after.Total != 3 {
+		t.Fatalf("expected bob's 3 reviews to be hidden, got total %d", after.Total)
+	}
+	for _, activity := range after.Activities {
+		if activity.ActivityType == "REVIEW" {
+			t.Fatalf("review %d is still visible after bob stopped sharing reviews", activity.ActivityID)
+		}
+	}
+
+	reviews, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, []string{ActivityTypeReview})
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if reviews.Total != 0 || len(reviews.Activities) != 0 {
+		t.Fatalf("expected no reviews in the filtered feed, got %d", reviews.Total)
+	}
+}
+
+func TestFriendsActivityFeedFoldsRatingIntoReview(t *testing.T) {
+	db := setupActivityFeedDB(t)
+	if _, err := db.Exec(`
+    INSERT INTO mangas (id, title, cover_url) VALUES (2, 'Quiet Days', 'quiet.jpg');
+    INSERT INTO activities (user_id, type, manga_id, created_at) VALUES
+        (2, 'REVIEW', 2, '2024-02-01 10:00:00'),
+        (2, 'RATING', 2, '2024-02-01 10:00:01');
+    `); err != nil {
+		t.Fatalf("failed to seed activities: %v", err)
+	}
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+
+	resp, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, nil)
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if resp.Total != 7 || len(resp.Activities) != 7 {
+		t.Fatalf("expected the review and rating of manga 2 to count once, got total=%d listed=%d", resp.Total, len(resp.Activities))
+	}
+	var entries []string
+	for _, activity := range resp.Activities {
+		if activity.MangaID == 2 {
+			entries = append(entries, activity.ActivityType)
+		}
+	}
+	if len(entries) != 1 || entries[0] != "REVIEW" {
+		t.Fatalf("expected one review entry for manga 2, got %v", entries)
+	}
+
+	// Asking for ratings alone still lists them since no review is shown
+	ratings, err := svc.GetFriendsActivityFeed(ctx, 1, 1, 20, []string{ActivityTypeRating})
+	if err != nil {
+		t.Fatalf("GetFriendsActivityFeed returned error: %v", err)
+	}
+	if ratings.Total != 2 {
+		t.Fatalf("expected both ratings in the rating-only feed, got %d", ratings.Total)
+	}
+}
+
+func TestGetProgressBatchMatchesPerMangaLookups(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL
+    );
+    CREATE TABLE reading_progress (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        current_chapter_id INTEGER,
+        current_page INTEGER,
+        progress_percent REAL,
+        last_read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (user_id, manga_id)
+    );
+    INSERT INTO chapters (manga_id, number) VALUES (1, 1), (1, 2), (2, 5), (3, 1);
+    INSERT INTO reading_progress (user_id, manga_id, current_chapter_id, progress_percent, last_read_at) VALUES
+        (1, 1, 2, 50, '2024-03-01 10:00:00'),
+        (1, 2, 3, 100, '2024-03-02 10:00:00'),
+        (1, 3, NULL, 0, '2024-03-03 10:00:00'),
+        (2, 4, 1, 25, '2024-03-04 10:00:00');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	ctx := context.Background()
+	// Manga 4 is only in another user's progress and 5 has none at all
+	ids := []int64{1, 2, 3, 4, 5, 2}
+
+	batch, err := svc.GetProgressBatch(ctx, 1, ids)
+	if err != nil {
+		t.Fatalf("GetProgressBatch returned error: %v", err)
+	}
+	for _, id := range ids {
+		single, err := svc.GetProgress(ctx, 1, id)
+		if err != nil {
+			t.Fatalf("GetProgress(%d) returned error: %v", id, err)
+		}
+		if !reflect.DeepEqual(batch[id], single) {
+			t.Errorf("manga %d: batch returned %+v, GetProgress returned %+v", id, batch[id], single)
+		}
+	}
+	if len(batch) != 3 {
+		t.Fatalf("expected progress for 3 manga, got %d", len(batch))
+	}
+
+	if _, err := svc.GetProgressBatch(ctx, 1, nil); !errors.Is(err, ErrNoProgressIDs) {
+		t.Fatalf("expected ErrNoProgressIDs, got %v", err)
+	}
+	if _, err := svc.GetProgressBatch(ctx, 1, []int64{1, -2}); !errors.Is(err, ErrInvalidProgressID) {
+		t.Fatalf("expected ErrInvalidProgressID, got %v", err)
+	}
+	tooMany := make([]int64, MaxProgressLookupIDs+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	if _, err := svc.GetProgressBatch(ctx, 1, tooMany); !errors.Is(err, ErrTooManyProgressIDs) {
+		t.Fatalf("expected ErrTooManyProgressIDs, got %v", err)
+	}
+}
+
+type recordingGoalNotifier struct {
+	mu    sync.Mutex
+	goals []int64
+}
+
+func (n *recordingGoalNotifier) GoalCompleted(ctx context.Context, goal *ReadingGoal) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.goals = append(n.goals, goal.GoalID)
+	return nil
+}
+
+func TestGoalCompletionIsNotifiedOnce(t *testing.T) {
+	db := setupGoalTestDB(t)
+	ctx := context.Background()
+	notifier := &recordingGoalNotifier{}
+	svc := NewService(NewRepository(db), nil, nil, nil)
+	svc.SetGoalNotifiers(notifier)
+	worker := NewStatsWorker(svc, time.Hour)
+
+	now := time.Now()
+	goal, err := svc.CreateGoal(ctx, 1, CreateGoalRequest{
+		GoalType:    "chapters",
+		TargetValue: 3,
+		PeriodType:  "monthly",
+		PeriodStart: now.AddDate(0, 0, -30),
+		PeriodEnd:   now.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal returned error: %v", err)
+	}
+	if goal.Status != "active" || len(notifier.goals) != 0 {
+		t.Fatalf("expected an active goal and no notification, got status %q and %v", goal.Status, notifier.goals)
+	}
+
+	readChapter := func() {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO reading_history (user_id, manga_id, event_type) VALUES (1, 3, 'finished_chapter')`); err != nil {
+			t.Fatalf("failed to record reading: %v", err)
+		}
+		// Progress updates schedule this recompute
+		if err := worker.recompute(ctx, 1); err != nil {
+			t.Logf("recompute: %v", err)
+		}
+	}
+
+	readChapter()
+	if len(notifier.goals) != 1 || notifier.goals[0] != goal.GoalID {
+		t.Fatalf("expected one notification for goal %d, got %v", goal.GoalID, notifier.goals)
+	}
+
+	// Further progress, listing and another service instance must not repeat it
+	readChapter()
+	if _, err := svc.ListGoals(ctx, 1); err != nil {
+		t.Fatalf("ListGoals returned error: %v", err)
+	}
+	other := NewService(NewRepository(db), nil, nil, nil)
+	other.SetGoalNotifiers(notifier)
+	if _, err := other.ListGoals(ctx, 1); err != nil {
+		t.Fatalf("ListGoals returned error: %v", err)
+	}
+	if len(notifier.goals) != 1 {
+		t.Fatalf("expected exactly one notification, got %v", notifier.goals)
+	}
+}
+
+func TestGoalCompletionMessage(t *testing.T) {
+	goal := &ReadingGoal{GoalType: "chapters", TargetValue: 10, PeriodType: "monthly", Genre: "Fantasy"}
+	if got, want := goal.CompletionMessage(), "You completed your monthly goal of 10 Fantasy chapters"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+// DefaultStatsRecomputeInterval is how often the worker refreshes the cached
+// statistics of active users; it must stay below statsCacheTTL so reads keep
+// hitting the cache
+const DefaultStatsRecomputeInterval = 15 * time.Minute
+
+// statsCacheTTL is how long cached statistics are served before a read
+// recomputes them itself
+const statsCacheTTL = time.Hour
+
+// statsActiveWindow is how recently a user must have read to be refreshed
+const statsActiveWindow = 24 * time.Hour
+
+// statsQueueSize bounds pending on-demand recomputes; beyond it requests are
+// dropped and the user waits for the next sweep
+const statsQueueSize = 256
+
+// StatsWorker recomputes reading statistics in the background: periodically
+// for users who read recently, and right away for users scheduled through
+// Service.ScheduleRecompute
+type StatsWorker struct {
+	service  *Service
+	interval time.Duration
+	queue    chan int64
+}
+
+// NewStatsWorker builds a worker that sweeps active users every interval.
+// It does nothing until Run is started.
+func NewStatsWorker(service *Service, interval time.Duration) *StatsWorker {
+	if interval <= 0 {
+		interval = DefaultStatsRecomputeInterval
+	}
+	return &StatsWorker{
+		service:  service,
+		interval: interval,
+		queue:    make(chan int64, statsQueueSize),
+	}
+}
+
+// SetStatsWorker routes ScheduleRecompute to the worker. Services sharing a
+// worker can all schedule through it.
+func (s *Service) SetStatsWorker(w *StatsWorker) {
+	s.statsWorker = w
+}
+
+// ScheduleRecompute asks the background worker to refresh a user's cached
+// statistics soon. It never blocks and is a no-op without a worker.
+func (s *Service) ScheduleRecompute(userID int64) {
+	if s.statsWorker != nil {
+		s.statsWorker.schedule(userID)
+	}
+}
+
+func (w *StatsWorker) schedule(userID int64) {
+	select {
+	case w.queue <- userID:
+	default:
+	}
+}
+
+// Run processes scheduled users and periodic sweeps until ctx is cancelled
+func (w *StatsWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case userID := <-w.queue:
+			if err := w.recompute(ctx, userID); err != nil {
+				logging.FromContext(ctx).Warn("history.StatsWorker: recompute failed", "user_id", userID, "err", err)
+			}
+		case <-ticker.C:
+			refreshed, err := w.RecomputeActive(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Warn("history.StatsWorker: sweep failed", "err", err)
+				continue
+			}
+			if refreshed > 0 {
+				logging.FromContext(ctx).Info("history.StatsWorker: refreshed statistics", "users", refreshed)
+			}
+		}
+	}
+}
+
+// RecomputeActive refreshes the statistics of every user who read within
+// the active window, returning how many were refreshed
+func (w *StatsWorker) RecomputeActive(ctx context.Context) (int, error) {
+	userIDs, err := w.service.repo.ActiveReaderIDs(ctx, time.Now().Add(-statsActiveWindow))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	refreshed := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+		if err := w.recompute(ctx, userID); err != nil {
+			logging.FromContext(ctx).Warn("history.StatsWorker: recompute failed", "user_id", userID, "err", err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+func (w *StatsWorker) recompute(ctx context.Context, userID int64) error {
+	// Progress updates schedule a recompute, so goals complete here too
+	if _, err := w.service.refreshGoals(ctx, userID); err != nil {
+		logging.FromContext(ctx).Warn("history.StatsWorker: goal refresh failed", "user_id", userID, "err", err)
+	}
+	_, err := w.service.recomputeStatistics(ctx, userID)
+	return err
+}
@@ -0,0 +1,98 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func setupStatsWorkerDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupSessionTestDB(t)
+	if _, err := db.Exec(`
+    CREATE TABLE reading_statistics (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL UNIQUE,
+        total_chapters_read INTEGER DEFAULT 0,
+        total_manga_read INTEGER DEFAULT 0,
+        total_manga_reading INTEGER DEFAULT 0,
+        total_manga_planned INTEGER DEFAULT 0,
+        favorite_genres TEXT,
+        average_rating REAL DEFAULT 0,
+        total_reading_time_hours REAL DEFAULT 0,
+        current_streak_days INTEGER DEFAULT 0,
+        longest_streak_days INTEGER DEFAULT 0,
+        monthly_stats TEXT,
+        yearly_stats TEXT,
+        last_calculated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+    INSERT INTO reading_history (user_id, manga_id, event_type, created_at) VALUES
+        (3, 1, 'finished_chapter', datetime('now', '-3 days'));
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+// waitForCachedStats polls the statistics cache until the user's entry appears
+func waitForCachedStats(t *testing.T, repo *Repository, userID int64) *ReadingStatistics {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		cached, err := repo.GetCachedReadingStatistics(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("GetCachedReadingStatistics returned error: %v", err)
+		}
+		if cached != nil {
+			return cached
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("statistics of user %d were never cached", userID)
+	return nil
+}
+
+func TestStatsWorkerRefreshesActiveReadersWithoutRequests(t *testing.T) {
+	repo := NewRepository(setupStatsWorkerDB(t))
+	svc := NewService(repo, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewStatsWorker(svc, 20*time.Millisecond).Run(ctx)
+
+	// User 1 read today; user 3 last read days ago and is left alone
+	cached := waitForCachedStats(t, repo, 1)
+	if cached.TotalChaptersRead != 6 {
+		t.Fatalf("expected 6 chapters in the cached statistics, got %d", cached.TotalChaptersRead)
+	}
+	if other, err := repo.GetCachedReadingStatistics(context.Background(), 3); err != nil || other != nil {
+		t.Fatalf("expected no statistics for an inactive user, got %+v (err %v)", other, err)
+	}
+}
+
+func TestScheduleRecomputeRefreshesImmediately(t *testing.T) {
+	repo := NewRepository(setupStatsWorkerDB(t))
+	svc := NewService(repo, nil, nil, nil)
+	worker := NewStatsWorker(svc, time.Hour)
+	svc.SetStatsWorker(worker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	svc.ScheduleRecompute(2)
+	cached := waitForCachedStats(t, repo, 2)
+	if cached.TotalChaptersRead != 1 {
+		t.Fatalf("expected 1 chapter in the cached statistics, got %d", cached.TotalChaptersRead)
+	}
+
+	// The freshly cached copy now serves reads
+	stats, err := svc.GetReadingStatistics(context.Background(), 2, false)
+	if err != nil {
+		t.Fatalf("GetReadingStatistics returned error: %v", err)
+	}
+	if !stats.LastCalculatedAt.Equal(cached.LastCalculatedAt) {
+		t.Fatalf("expected the cached statistics to be served, got calculation time %v (cached %v)", stats.LastCalculatedAt, cached.LastCalculatedAt)
+	}
+}
@@ -0,0 +1,206 @@
+package history
+
+import (
+	"context"
+	"time"
+)
+
+// StreakFreezesPerMonth is how many missed days a user can bridge per calendar month
+const StreakFreezesPerMonth = 2
+
+// streakFreezeWindowDays limits how far back a freeze can be applied manually
+const streakFreezeWindowDays = 7
+
+// streakDayFormat is how reading days and freeze dates are keyed
+const streakDayFormat = "2006-01-02"
+
+// streakResult is the outcome of calculateReadingStreaks
+type streakResult struct {
+	Current int
+	Longest int
+	// NewFreezes lists missed days bridged by freezes consumed in this pass
+	NewFreezes []string
+}
+
+// calculateReadingStreaks walks the user's distinct reading days in ascending
+// order. A gap of exactly one missed day is bridged when that day is already
+// frozen or a freeze is left for its month; the frozen day keeps the streak
+// alive without adding to it. The current streak only counts while the last
+// reading day is today or yesterday, or yesterday itself can be frozen.
+func calculateReadingStreaks(readDays []string, frozen map[string]bool, allowance int, today time.Time) streakResult {
+	var res streakResult
+
+	used := make(map[string]int)
+	for day := range frozen {
+		used[day[:7]]++
+	}
+	bridged := make(map[string]bool, len(frozen))
+	for day := range frozen {
+		bridged[day] = true
+	}
+	bridge := func(missed time.Time) bool {
+		day := missed.Format(streakDayFormat)
+		if bridged[day] {
+			return true
+		}
+		month := day[:7]
+		if used[month] >= allowance {
+			return false
+		}
+		used[month]++
+		bridged[day] = true
+		res.NewFreezes = append(res.NewFreezes, day)
+		return true
+	}
+
+	var (
+		run  int
+		prev time.Time
+	)
+	for _, raw := range readDays {
+		day, err := time.Parse(streakDayFormat, raw)
+		if err != nil {
+			continue
+		}
+		if run == 0 {
+			run = 1
+		} else {
+			switch gap := daysBetween(prev, day); {
+			case gap <= 0:
+				continue
+			case gap == 1:
+				run++
+			case gap == 2 && bridge(prev.AddDate(0, 0, 1)):
+				run++
+			default:
+				run = 1
+			}
+		}
+		if run > res.Longest {
+			res.Longest = run
+		}
+		prev = day
+	}
+
+	if run == 0 {
+		return res
+	}
+	todayDay := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	switch gap := daysBetween(prev, todayDay); {
+	case gap <= 1:
+		res.Current = run
+	case gap == 2 && bridge(todayDay.AddDate(0, 0, -1)):
+		res.Current = run
+	}
+	return res
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// freezesLeftIn returns the unused freezes for the month containing day
+func freezesLeftIn(frozen map[string]bool, day time.Time) int {
+	month := day.Format("2006-01")
+	left := StreakFreezesPerMonth
+	for d := range frozen {
+		if d[:7] == month {
+			left--
+		}
+	}
+	if left < 0 {
+		left = 0
+	}
+	return left
+}
+
+// readingStreaks calculates a user's streaks and records any freezes consumed
+// to bridge single missed days
+func (r *Repository) readingStreaks(ctx context.Context, userID int64, now time.Time) (streakResult, map[string]bool, error) {
+	days, err := r.readingDays(ctx, userID)
+	if err != nil {
+		return streakResult{}, nil, err
+	}
+	frozen, err := r.StreakFreezeDays(ctx, userID)
+	if err != nil {
+		return streakResult{}, nil, err
+	}
+
+	res := calculateReadingStreaks(days, frozen, StreakFreezesPerMonth, now.UTC())
+	for _, day := range res.NewFreezes {
+		if _, err := r.InsertStreakFreeze(ctx, userID, day, false); err != nil {
+			return streakResult{}, nil, err
+		}
+		frozen[day] = true
+	}
+	return res, frozen, nil
+}
+
+// readingDays lists the distinct UTC days with reading activity, oldest first
+func (r *Repository) readingDays(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+        SELECT DISTINCT substr(created_at, 1, 10) AS day
+        FROM reading_history
+        WHERE user_id = ?
+        ORDER BY day
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// StreakFreezeDays returns the days a user has bridged with a freeze
+func (r *Repository) StreakFreezeDays(ctx context.Context, userID int64) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT Freeze_Date FROM Streak_Freezes WHERE User_Id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	frozen := make(map[string]bool)
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		frozen[day] = true
+	}
+	return frozen, rows.Err()
+}
+
+// InsertStreakFreeze records a frozen day; it reports false when the day was already frozen
+func (r *Repository) InsertStreakFreeze(ctx context.Context, userID int64, day string, manual bool) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO Streak_Freezes (User_Id, Freeze_Date, Is_Manual)
+        VALUES (?, ?, ?)
+        ON CONFLICT(User_Id, Freeze_Date) DO NOTHING
+    `, userID, day, manual)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// HasReadingOn reports whether the user has reading activity on a UTC day
+func (r *Repository) HasReadingOn(ctx context.Context, userID int64, day string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM reading_history WHERE user_id = ? AND substr(created_at, 1, 10) = ?
+    `, userID, day).Scan(&count)
+	return count > 0, err
+}
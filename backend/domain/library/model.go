@@ -1,6 +1,10 @@
 package library
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
 
 // LibraryStatus describes how a manga appears in user's library without rating/favorite metadata
 type LibraryStatus struct {
@@ -40,7 +44,113 @@ type UpdateLibraryStatusRequest struct {
 	Status string `json:"status" binding:"required"`
 }
 
-// GetLibraryResponse represents a full library listing
+// Library listing sort orders
+const (
+	SortLastUpdated = "last_updated"
+	SortTitle       = "title"
+	SortRating      = "rating"
+)
+
+// LibraryQuery filters, sorts and pages a library listing. Status and Sort
+// are optional; without a limit every matching entry is returned.
+type LibraryQuery struct {
+	Status string `form:"status"`
+	Sort   string `form:"sort"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// GetLibraryResponse represents a library listing. Total counts the entries
+// matching the status filter; StatusCounts covers the whole library.
 type GetLibraryResponse struct {
-	Entries []LibraryEntry `json:"entries"`
+	Entries      []LibraryEntry `json:"entries"`
+	Total        int            `json:"total"`
+	Page         int            `json:"page,omitempty"`
+	Limit        int            `json:"limit,omitempty"`
+	StatusCounts map[string]int `json:"status_counts"`
+}
+
+// ExportEntry is one row of a library export
+type ExportEntry struct {
+	MangaID        int64      `json:"manga_id"`
+	Title          string     `json:"title"`
+	Status         string     `json:"status"`
+	CurrentChapter int        `json:"current_chapter"`
+	IsFavorite     bool       `json:"is_favorite"`
+	AddedAt        *time.Time `json:"added_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	LastReadAt     *time.Time `json:"last_read_at,omitempty"`
+}
+
+// ImportRef holds a manga ID or title. IDs may be sent as JSON numbers.
+type ImportRef string
+
+// UnmarshalJSON accepts both strings and numbers
+func (r *ImportRef) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*r = ImportRef(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*r = ImportRef(n.String())
+	return nil
+}
+
+// ImportEntry is one row of a library import
+type ImportEntry struct {
+	TitleOrID      ImportRef `json:"title_or_id"`
+	Status         string    `json:"status"`
+	CurrentChapter int       `json:"current_chapter"`
+	IsFavorite     bool      `json:"is_favorite"`
+}
+
+// ImportError explains why an import row was skipped. Row is 1-based.
+type ImportError struct {
+	Row       int    `json:"row"`
+	TitleOrID string `json:"title_or_id"`
+	Error     string `json:"error"`
+}
+
+// ImportSummary reports the outcome of a library import
+type ImportSummary struct {
+	Imported int           `json:"imported"`
+	Skipped  int           `json:"skipped"`
+	Errors   []ImportError `json:"errors"`
+}
+
+// Collection is a user-named shelf grouping manga from their library
+type Collection struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	MangaIDs  []int64   `json:"manga_ids"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CollectionSummary identifies a collection a manga belongs to
+type CollectionSummary struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateCollectionRequest holds payload for creating a collection
+type CreateCollectionRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AddToCollectionRequest holds payload for adding manga to a collection
+type AddToCollectionRequest struct {
+	MangaID int64 `json:"manga_id" binding:"required"`
+}
+
+// ListCollectionsResponse represents the user's collections
+type ListCollectionsResponse struct {
+	Collections []Collection `json:"collections"`
 }
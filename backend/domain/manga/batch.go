@@ -0,0 +1,79 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MaxBatchDetailIDs caps how many manga a single batch detail request may ask for
+const MaxBatchDetailIDs = 100
+
+var (
+	ErrNoBatchIDs      = errors.New("ids must not be empty")
+	ErrTooManyBatchIDs = fmt.Errorf("at most %d ids may be requested at once", MaxBatchDetailIDs)
+	ErrInvalidBatchID  = errors.New("ids must be positive")
+)
+
+// GetDetailsBatch returns the details of several manga keyed by ID, for
+// library and collection views that would otherwise fetch them one by one.
+// The manga are loaded with a single query and their chapter counts with
+// another. Chapter lists are left out to keep the response small, and IDs
+// that do not exist are simply absent from the map.
+func (s *Service) GetDetailsBatch(ctx context.Context, mangaIDs []int64) (map[int64]*MangaDetail, error) {
+	ids, err := normalizeBatchIDs(mangaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	found, err := s.repo.GetByIDs(ctx, ids)
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	counts := map[int64]int{}
+	if s.chapterService != nil && len(found) > 0 {
+		if c, err := s.chapterService.GetChapterCounts(ctx, ids); err == nil {
+			counts = c
+		}
+	}
+
+	details := make(map[int64]*MangaDetail, len(found))
+	for id, m := range found {
+		details[id] = &MangaDetail{
+			Manga:        *m,
+			ChapterCount: counts[id],
+		}
+	}
+	return details, nil
+}
+
+// normalizeBatchIDs validates the requested IDs and drops duplicates,
+// keeping the first occurrence of each
+func normalizeBatchIDs(mangaIDs []int64) ([]int64, error) {
+	if len(mangaIDs) == 0 {
+		return nil, ErrNoBatchIDs
+	}
+
+	seen := make(map[int64]bool, len(mangaIDs))
+	ids := make([]int64, 0, len(mangaIDs))
+	for _, id := range mangaIDs {
+		if id <= 0 {
+			return nil, ErrInvalidBatchID
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > MaxBatchDetailIDs {
+		return nil, ErrTooManyBatchIDs
+	}
+	return ids, nil
+}
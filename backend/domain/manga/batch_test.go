@@ -0,0 +1,111 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
+)
+
+// countingChapters reports fixed chapter counts and records how it was queried
+type countingChapters struct {
+	counts      map[int64]int
+	singleCalls int
+	batchCalls  int
+}
+
+func (c *countingChapters) GetChapterCount(ctx context.Context, mangaID int64) (int, error) {
+	c.singleCalls++
+	return c.counts[mangaID], nil
+}
+
+func (c *countingChapters) GetChapterCounts(ctx context.Context, mangaIDs []int64) (map[int64]int, error) {
+	c.batchCalls++
+	counts := make(map[int64]int, len(mangaIDs))
+	for _, id := range mangaIDs {
+		if n, ok := c.counts[id]; ok {
+			counts[id] = n
+		}
+	}
+	return counts, nil
+}
+
+func (c *countingChapters) GetChapters(ctx context.Context, mangaID int64, limit, offset int) ([]pkgchapter.ChapterSummary, error) {
+	return nil, nil
+}
+
+func TestGetDetailsBatch_MatchesSingleFetches(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	service := NewService(db)
+	chapters := &countingChapters{counts: map[int64]int{1: 12, 3: 4}}
+	service.SetChapterService(chapters)
+	ctx := context.Background()
+
+	ids := []int64{3, 1, 2, 1, 99}
+	batch, err := service.GetDetailsBatch(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetDetailsBatch failed: %v", err)
+	}
+	if chapters.batchCalls != 1 || chapters.singleCalls != 0 {
+		t.Fatalf("expected one batched chapter count lookup, got batch=%d single=%d", chapters.batchCalls, chapters.singleCalls)
+	}
+	if len(batch) != 3 {
+		t.Fatalf("expected 3 details, got %d", len(batch))
+	}
+	if _, ok := batch[99]; ok {
+		t.Fatal("expected the unknown id to be absent")
+	}
+
+	for _, id := range []int64{1, 2, 3} {
+		single, err := service.GetDetails(ctx, id, nil)
+		if err != nil {
+			t.Fatalf("GetDetails(%d) failed: %v", id, err)
+		}
+		got, ok := batch[id]
+		if !ok {
+			t.Fatalf("expected id %d in the batch", id)
+		}
+		if got.Manga != single.Manga {
+			t.Fatalf("id %d: batch manga %+v differs from single fetch %+v", id, got.Manga, single.Manga)
+		}
+		if got.ChapterCount != single.ChapterCount {
+			t.Fatalf("id %d: batch chapter count %d differs from single fetch %d", id, got.ChapterCount, single.ChapterCount)
+		}
+	}
+}
+
+func TestGetDetailsBatch_ValidatesIDs(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	service := NewService(db)
+	ctx := context.Background()
+
+	if _, err := service.GetDetailsBatch(ctx, nil); !errors.Is(err, ErrNoBatchIDs) {
+		t.Fatalf("expected ErrNoBatchIDs, got %v", err)
+	}
+	if _, err := service.GetDetailsBatch(ctx, []int64{1, 0}); !errors.Is(err, ErrInvalidBatchID) {
+		t.Fatalf("expected ErrInvalidBatchID, got %v", err)
+	}
+
+	tooMany := make([]int64, MaxBatchDetailIDs+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	if _, err := service.GetDetailsBatch(ctx, tooMany); !errors.Is(err, ErrTooManyBatchIDs) {
+		t.Fatalf("expected ErrTooManyBatchIDs, got %v", err)
+	}
+
+	// Duplicates count once towards the cap
+	repeated := make([]int64, MaxBatchDetailIDs+1)
+	for i := range repeated {
+		repeated[i] = 1
+	}
+	if _, err := service.GetDetailsBatch(ctx, repeated); err != nil {
+		t.Fatalf("expected repeated ids to be accepted, got %v", err)
+	}
+}
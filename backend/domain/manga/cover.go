@@ -0,0 +1,223 @@
+package manga
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCoverBytes caps the size of an upstream cover image the proxy will cache
+const MaxCoverBytes = 5 << 20
+
+// coverRefreshInterval is how long a cached cover is served before the
+// upstream is asked (conditionally) whether it changed
+const coverRefreshInterval = 24 * time.Hour
+
+// maxCoverETagLength keeps ETag-derived file names under filesystem limits
+const maxCoverETagLength = 100
+
+var (
+	ErrCoverTooLarge    = errors.New("cover image is too large")
+	ErrCoverUnavailable = errors.New("cover image unavailable")
+)
+
+// coverHTTPClient fetches upstream covers; CDNs that hang must not tie up
+// request handlers
+var coverHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// coverPlaceholder is served when a manga has no cover or the upstream fails
+var coverPlaceholder = []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 200 300" width="200" height="300">` +
+	`<rect width="200" height="300" fill="#e5e7eb"/>` +
+	`<path d="M70 120h60v80H70z" fill="none" stroke="#9ca3af" stroke-width="6"/>` +
+	`<circle cx="88" cy="142" r="8" fill="#9ca3af"/>` +
+	`<path d="M76 194l22-30 14 18 10-12 12 24z" fill="#9ca3af"/>` +
+	`</svg>`)
+
+// Cover is a cover image ready to serve
+type Cover struct {
+	Data        []byte
+	ContentType string
+	// ETag is the upstream ETag, or empty when the upstream sent none
+	ETag string
+	// Placeholder is set when Data is the generic placeholder image
+	Placeholder bool
+}
+
+// SetCoverCache configures the directory upstream covers are cached in.
+// Without it every request goes to the upstream.
+func (s *Service) SetCoverCache(dir string) {
+	s.coverDir = dir
+}
+
+// PlaceholderCover returns the image served when no real cover is available
+func PlaceholderCover() *Cover {
+	return &Cover{Data: coverPlaceholder, ContentType: "image/svg+xml", Placeholder: true}
+}
+
+// GetCover returns a manga's cover image, served from the disk cache while
+// it is fresh and revalidated against the upstream ETag once it is not.
+// Upstream failures fall back to a stale cached copy, then to the placeholder.
+func (s *Service) GetCover(ctx context.Context, mangaID int64) (*Cover, error) {
+	manga, err := s.GetByID(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	if manga == nil {
+		return nil, ErrMangaNotFound
+	}
+	if strings.TrimSpace(manga.Image) == "" {
+		return PlaceholderCover(), nil
+	}
+
+	cached, modTime := s.cachedCover(mangaID)
+	if cached != nil && time.Since(modTime) < coverRefreshInterval {
+		return cached, nil
+	}
+
+	etag := ""
+	if cached != nil {
+		etag = cached.ETag
+	}
+	fetched, notModified, err := fetchCover(ctx, manga.Image, etag)
+	switch {
+	case err != nil:
+		if cached != nil {
+			return cached, nil
+		}
+		return PlaceholderCover(), nil
+	case notModified:
+		s.touchCover(mangaID, cached.ETag)
+		return cached, nil
+	}
+
+	s.storeCover(mangaID, fetched)
+	return fetched, nil
+}
+
+// fetchCover downloads coverURL. With a known etag the request is
+// conditional and notModified reports a 304.
+func fetchCover(ctx context.Context, coverURL, etag string) (cover *Cover, notModified bool, err error) {
+	u, err := url.Parse(coverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, false, fmt.Errorf("%w: invalid cover url %q", ErrCoverUnavailable, coverURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := coverHTTPClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrCoverUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%w: upstream returned %d", ErrCoverUnavailable, resp.StatusCode)
+	}
+	if resp.ContentLength > MaxCoverBytes {
+		return nil, false, ErrCoverTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxCoverBytes+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrCoverUnavailable, err)
+	}
+	if len(data) > MaxCoverBytes {
+		return nil, false, ErrCoverTooLarge
+	}
+	// Trust the bytes rather than the upstream header so the proxy never
+	// serves something that is not an image
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, false, fmt.Errorf("%w: upstream sent %s", ErrCoverUnavailable, contentType)
+	}
+
+	cover = &Cover{Data: data, ContentType: contentType}
+	if tag := resp.Header.Get("ETag"); len(tag) <= maxCoverETagLength {
+		cover.ETag = tag
+	}
+	return cover, false, nil
+}
+
+// coverFileName keys a cached cover by manga ID and upstream ETag. The ETag
+// is hex encoded so it is safe in a file name and can be read back.
+func (s *Service) coverFileName(mangaID int64, etag string) string {
+	return filepath.Join(s.coverDir, strconv.FormatInt(mangaID, 10)+"-"+hex.EncodeToString([]byte(etag))+".img")
+}
+
+// cachedCover returns the cached cover of a manga and when it was last
+// confirmed against the upstream
+func (s *Service) cachedCover(mangaID int64) (*Cover, time.Time) {
+	if s.coverDir == "" {
+		return nil, time.Time{}
+	}
+	matches, err := filepath.Glob(filepath.Join(s.coverDir, strconv.FormatInt(mangaID, 10)+"-*.img"))
+	if err != nil || len(matches) == 0 {
+		return nil, time.Time{}
+	}
+
+	name := matches[0]
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	encoded := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), strconv.FormatInt(mangaID, 10)+"-"), ".img")
+	etag, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	return &Cover{Data: data, ContentType: http.DetectContentType(data), ETag: string(etag)}, info.ModTime()
+}
+
+// storeCover replaces the cached cover of a manga. Caching is best effort;
+// a failed write only means the next request goes upstream again.
+func (s *Service) storeCover(mangaID int64, cover *Cover) {
+	if s.coverDir == "" {
+		return
+	}
+	if err := os.MkdirAll(s.coverDir, 0o755); err != nil {
+		return
+	}
+	name := s.coverFileName(mangaID, cover.ETag)
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, cover.Data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	// Drop copies cached under an older ETag
+	matches, _ := filepath.Glob(filepath.Join(s.coverDir, strconv.FormatInt(mangaID, 10)+"-*.img"))
+	for _, match := range matches {
+		if match != name {
+			_ = os.Remove(match)
+		}
+	}
+}
+
+// touchCover marks a cached cover as confirmed by the upstream just now
+func (s *Service) touchCover(mangaID int64, etag string) {
+	now := time.Now()
+	_ = os.Chtimes(s.coverFileName(mangaID, etag), now, now)
+}
@@ -0,0 +1,114 @@
+package manga
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testCoverPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+
+func TestGetCoverCachesUpstream(t *testing.T) {
+	db := setupTestDB(t)
+	seedNovels(t, db)
+
+	var fetches, revalidations atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(testCoverPNG)
+	}))
+	defer upstream.Close()
+
+	if _, err := db.Exec(`UPDATE mangas SET cover_url = ? WHERE id = 1`, upstream.URL+"/covers/hero-saga.jpg"); err != nil {
+		t.Fatalf("failed to set cover url: %v", err)
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	svc := NewService(db)
+	svc.SetCoverCache(dir)
+
+	cover, err := svc.GetCover(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetCover failed: %v", err)
+	}
+	if cover.Placeholder || !bytes.Equal(cover.Data, testCoverPNG) || cover.ContentType != "image/png" || cover.ETag != `"v1"` {
+		t.Fatalf("unexpected cover: %+v", cover)
+	}
+
+	// A fresh service over the same directory must be served from disk
+	again := NewService(db)
+	again.SetCoverCache(dir)
+	cover, err = again.GetCover(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetCover (cached) failed: %v", err)
+	}
+	if !bytes.Equal(cover.Data, testCoverPNG) || cover.ETag != `"v1"` {
+		t.Fatalf("unexpected cached cover: %+v", cover)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("expected a single upstream fetch, got %d", n)
+	}
+
+	// Once stale, the cached copy is revalidated with its ETag
+	matches, _ := filepath.Glob(filepath.Join(dir, "1-*.img"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one cached file, got %v", matches)
+	}
+	old := time.Now().Add(-2 * coverRefreshInterval)
+	if err := os.Chtimes(matches[0], old, old); err != nil {
+		t.Fatalf("failed to age cached cover: %v", err)
+	}
+	cover, err = svc.GetCover(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetCover (revalidate) failed: %v", err)
+	}
+	if !bytes.Equal(cover.Data, testCoverPNG) || revalidations.Load() != 1 {
+		t.Fatalf("expected a 304 revalidation, got %d (cover %+v)", revalidations.Load(), cover)
+	}
+}
+
+func TestGetCoverFallsBackToPlaceholder(t *testing.T) {
+	db := setupTestDB(t)
+	seedNovels(t, db)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	if _, err := db.Exec(`UPDATE mangas SET cover_url = ? WHERE id = 1`, upstream.URL+"/covers/hero-saga.jpg"); err != nil {
+		t.Fatalf("failed to set cover url: %v", err)
+	}
+
+	svc := NewService(db)
+	svc.SetCoverCache(t.TempDir())
+
+	// Manga 2 keeps a relative cover URL the proxy cannot fetch
+	for _, mangaID := range []int64{1, 2} {
+		cover, err := svc.GetCover(context.Background(), mangaID)
+		if err != nil {
+			t.Fatalf("manga %d: GetCover failed: %v", mangaID, err)
+		}
+		if !cover.Placeholder || cover.ContentType != "image/svg+xml" || len(cover.Data) == 0 {
+			t.Fatalf("manga %d: expected the placeholder, got %+v", mangaID, cover)
+		}
+	}
+
+	if _, err := svc.GetCover(context.Background(), 99); !errors.Is(err, ErrMangaNotFound) {
+		t.Fatalf("expected ErrMangaNotFound, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package manga
 
 import (
+	"github.com/ngocan-dev/mangahub/backend/domain/comment"
 	"github.com/ngocan-dev/mangahub/backend/domain/history"
 	"github.com/ngocan-dev/mangahub/backend/domain/library"
 	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
@@ -19,7 +20,10 @@ type Manga struct {
 	Description string  `json:"description"`
 	Image       string  `json:"image"`
 	RatingPoint float64 `json:"rating_point"`
-	Views       int64   `json:"views,omitempty"`
+	// WeightedRating is RatingPoint pulled toward the global mean while a
+	// manga has few ratings; rankings by rating use it
+	WeightedRating float64 `json:"weighted_rating,omitempty"`
+	Views          int64   `json:"views,omitempty"`
 	// RelevanceScore represents the ranking score returned by full-text search.
 	// It is omitted when not performing text search.
 	RelevanceScore float64 `json:"relevance_score,omitempty"`
@@ -48,6 +52,72 @@ type SearchResponse struct {
 	Pages   int     `json:"pages"`
 }
 
+// Popularity windows accepted by GetPopularManga
+const (
+	PopularPeriodDay   = "day"
+	PopularPeriodWeek  = "week"
+	PopularPeriodMonth = "month"
+	PopularPeriodAll   = "all"
+)
+
+// PopularMangaResponse represents a paginated popular manga list for a time window
+type PopularMangaResponse struct {
+	Results []Manga `json:"results"`
+	Period  string  `json:"period"`
+	Total   int     `json:"total"`
+	Page    int     `json:"page"`
+	Limit   int     `json:"limit"`
+	Pages   int     `json:"pages"`
+}
+
+// TrendingManga is a manga ranked by recent reading momentum
+type TrendingManga struct {
+	Manga
+	// Readers is the number of distinct users who advanced progress in the window
+	Readers int `json:"readers"`
+	// Score sums the readers' recency weights; a reader counts 1 when they
+	// read just now and approaches 0 toward the start of the window
+	Score float64 `json:"trending_score"`
+}
+
+// TrendingMangaResponse lists trending manga for a rolling window
+type TrendingMangaResponse struct {
+	Results []TrendingManga `json:"results"`
+	Window  string          `json:"window"`
+	Limit   int             `json:"limit"`
+}
+
+// Suggestion is a lightweight title match for search-as-you-type
+type Suggestion struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// SuggestResponse lists title suggestions for a typed prefix
+type SuggestResponse struct {
+	Query   string       `json:"query"`
+	Results []Suggestion `json:"results"`
+}
+
+// Where recommendations came from
+const (
+	RecommendationSourceGenres  = "genres"
+	RecommendationSourcePopular = "popular"
+)
+
+// RecommendationsResponse lists manga suggested for a user
+type RecommendationsResponse struct {
+	Results []Manga  `json:"results"`
+	Genres  []string `json:"genres"`
+	Source  string   `json:"source"`
+}
+
+// SimilarMangaResponse lists manga that share tags with a source manga
+type SimilarMangaResponse struct {
+	MangaID int64   `json:"manga_id"`
+	Results []Manga `json:"results"`
+}
+
 // MangaDetail represents detailed manga information
 type MangaDetail struct {
 	Manga
@@ -55,6 +125,48 @@ type MangaDetail struct {
 	Chapters      []pkgchapter.ChapterSummary `json:"chapters,omitempty"`
 	LibraryStatus *library.LibraryStatus      `json:"library_status,omitempty"`
 	UserProgress  *history.UserProgress       `json:"user_progress,omitempty"`
+	Collections   []library.CollectionSummary `json:"collections,omitempty"`
+	ReviewStats   *comment.ReviewStats        `json:"review_stats,omitempty"`
+}
+
+// BatchDetailsRequest asks for the details of several manga at once
+type BatchDetailsRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// BatchDetailsResponse maps each found manga ID to its details. Missing lists
+// requested IDs that do not exist.
+type BatchDetailsResponse struct {
+	Results map[int64]*MangaDetail `json:"results"`
+	Missing []int64                `json:"missing,omitempty"`
+}
+
+// Tag is a genre tag and how many manga carry it
+type Tag struct {
+	Name       string `json:"name"`
+	MangaCount int    `json:"manga_count"`
+}
+
+// TagListResponse lists every tag in use
+type TagListResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+// TagMangaResponse is a page of manga carrying a tag
+type TagMangaResponse struct {
+	Tag     string  `json:"tag"`
+	Results []Manga `json:"results"`
+	Total   int     `json:"total"`
+	Page    int     `json:"page"`
+	Limit   int     `json:"limit"`
+	Pages   int     `json:"pages"`
+}
+
+// MangaDeletionResponse reports the outcome of deleting or restoring a manga
+type MangaDeletionResponse struct {
+	Message string `json:"message"`
+	MangaID int64  `json:"manga_id"`
+	Deleted bool   `json:"deleted"`
 }
 
 // CreateMangaRequest captures data required to create a manga record.
@@ -0,0 +1,153 @@
+package manga
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// seedPopularActivity adds three manga: Old Favourite was read heavily two
+// months ago, New Hit this week and Quiet never
+func seedPopularActivity(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	schema := `
+    CREATE TABLE reading_progress (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        last_read_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE TABLE reading_history (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        event_type TEXT NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    INSERT INTO mangas (id, slug, title, rating_average, rating_count) VALUES
+        (1, 'old-favourite', 'Old Favourite', 4.9, 500),
+        (2, 'new-hit', 'New Hit', 3.0, 10),
+        (3, 'quiet', 'Quiet', 2.0, 1);
+
+    -- Old Favourite was read heavily two months ago, New Hit this week
+    INSERT INTO reading_progress (user_id, manga_id, last_read_at) VALUES
+        (1, 1, datetime('now', '-60 days')),
+        (2, 1, datetime('now', '-60 days')),
+        (3, 1, datetime('now', '-60 days')),
+        (1, 2, datetime('now', '-2 days'));
+
+    INSERT INTO reading_history (user_id, manga_id, event_type, created_at) VALUES
+        (1, 1, 'opened', datetime('now', '-60 days')),
+        (1, 2, 'opened', datetime('now', '-2 days')),
+        (2, 2, 'opened', datetime('now', '-3 hours'));
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to seed activity: %v", err)
+	}
+}
+
+func TestRepositoryGetPopularManga_RanksByActivityInWindow(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	seedPopularActivity(t, db)
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	cases := []struct {
+		period string
+		want   []string
+	}{
+		{PopularPeriodWeek, []string{"New Hit", "Old Favourite", "Quiet"}},
+		{PopularPeriodAll, []string{"Old Favourite", "New Hit", "Quiet"}},
+	}
+	for _, tc := range cases {
+		since, err := popularPeriodStart(tc.period, now)
+		if err != nil {
+			t.Fatalf("popularPeriodStart(%s) returned error: %v", tc.period, err)
+		}
+		results, total, err := repo.GetPopularManga(ctx, since, 10, 0)
+		if err != nil {
+			t.Fatalf("GetPopularManga(%s) failed: %v", tc.period, err)
+		}
+		if total != 3 || len(results) != 3 {
+			t.Fatalf("%s: expected 3 results, got total=%d len=%d", tc.period, total, len(results))
+		}
+		for i, title := range tc.want {
+			if results[i].Title != title {
+				t.Fatalf("%s: result %d expected %q, got %q", tc.period, i, title, results[i].Title)
+			}
+		}
+	}
+
+	page, _, err := repo.GetPopularManga(ctx, time.Time{}, 1, 1)
+	if err != nil {
+		t.Fatalf("GetPopularManga page 2 failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Title != "New Hit" {
+		t.Fatalf("expected second page to contain New Hit, got %+v", page)
+	}
+
+	if _, err := popularPeriodStart("year", now); err != ErrInvalidPeriod {
+		t.Fatalf("expected ErrInvalidPeriod, got %v", err)
+	}
+}
+
+func TestServiceGetPopularMangaPaginates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedPopularActivity(t, db)
+
+	svc := NewService(db)
+	ctx := context.Background()
+
+	first, err := svc.GetPopularManga(ctx, 2, PopularPeriodAll)
+	if err != nil {
+		t.Fatalf("GetPopularManga failed: %v", err)
+	}
+	if first.Page != 1 || first.Limit != 2 || first.Total != 3 || first.Pages != 2 || first.Period != PopularPeriodAll {
+		t.Fatalf("unexpected first page metadata: %+v", first)
+	}
+	if len(first.Results) != 2 || first.Results[0].Title != "Old Favourite" || first.Results[1].Title != "New Hit" {
+		t.Fatalf("unexpected first page: %+v", first.Results)
+	}
+
+	second, err := svc.GetPopularMangaPage(ctx, 2, 2, PopularPeriodAll)
+	if err != nil {
+		t.Fatalf("GetPopularMangaPage(2) failed: %v", err)
+	}
+	if second.Page != 2 || second.Total != 3 || second.Pages != 2 {
+		t.Fatalf("unexpected second page metadata: %+v", second)
+	}
+	if len(second.Results) != 1 || second.Results[0].Title != "Quiet" {
+		t.Fatalf("expected the second page to contain Quiet, got %+v", second.Results)
+	}
+
+	beyond, err := svc.GetPopularMangaPage(ctx, 3, 2, PopularPeriodAll)
+	if err != nil {
+		t.Fatalf("GetPopularMangaPage(3) failed: %v", err)
+	}
+	if beyond.Page != 3 || len(beyond.Results) != 0 {
+		t.Fatalf("expected an empty page past the end, got %+v", beyond)
+	}
+
+	// Pages below one fall back to the first page
+	clamped, err := svc.GetPopularMangaPage(ctx, 0, 2, PopularPeriodWeek)
+	if err != nil {
+		t.Fatalf("GetPopularMangaPage(0) failed: %v", err)
+	}
+	if clamped.Page != 1 || len(clamped.Results) != 2 || clamped.Results[0].Title != "New Hit" {
+		t.Fatalf("expected the first week page, got %+v", clamped)
+	}
+
+	if _, err := svc.GetPopularManga(ctx, 2, "year"); !errors.Is(err, ErrInvalidPeriod) {
+		t.Fatalf("expected ErrInvalidPeriod, got %v", err)
+	}
+}
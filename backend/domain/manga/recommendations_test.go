@@ -0,0 +1,121 @@
+package manga
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/internal/testdb"
+)
+
+// setupRecommendationService seeds the migrated schema and takes favourite
+// genres from the real history service, so the genres and the library
+// exclusion read the same tables as in production. The bundled catalogue is
+// soft-deleted to keep it out of the rankings.
+func setupRecommendationService(t *testing.T) *Service {
+	t.Helper()
+
+	db := testdb.Migrated(t)
+
+	schema := `
+    UPDATE mangas SET deleted_at = CURRENT_TIMESTAMP;
+    INSERT INTO users (id, username, email, password_hash) VALUES
+        (500, 'reader', 'reader@example.com', 'x'),
+        (501, 'newcomer', 'newcomer@example.com', 'x'),
+        (502, 'other', 'other@example.com', 'x');
+    INSERT INTO mangas (id, slug, title, rating_average, rating_count, views) VALUES
+        (1001, 'already-read', 'Already Read', 5.0, 900, 900),
+        (1002, 'both-genres', 'Both Genres', 3.5, 20, 20),
+        (1003, 'fantasy-top', 'Fantasy Top', 4.8, 300, 300),
+        (1004, 'fantasy-low', 'Fantasy Low', 3.0, 900, 5),
+        (1005, 'romance-only', 'Romance Only', 4.9, 800, 800),
+        (1006, 'fantasy-viewed', 'Fantasy Viewed', 3.0, 5, 500);
+    INSERT INTO manga_tags (manga_id, tag_id)
+        SELECT m.id, t.id FROM mangas m, tags t
+        WHERE (m.id IN (1001, 1002) AND t.name IN ('Fantasy', 'Action'))
+           OR (m.id IN (1003, 1004, 1006) AND t.name = 'Fantasy')
+           OR (m.id = 1005 AND t.name = 'Romance');
+    INSERT INTO libraries (user_id, manga_id, status) VALUES (500, 1001, 'completed');
+    INSERT INTO reading_progress (user_id, manga_id) VALUES (500, 1005), (502, 1005);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to seed recommendations: %v", err)
+	}
+
+	svc := NewService(db)
+	svc.SetGenreAffinity(history.NewService(history.NewRepository(db), nil, nil, nil))
+	return svc
+}
+
+func TestGetRecommendations_RanksByGenreMatchThenRating(t *testing.T) {
+	svc := setupRecommendationService(t)
+
+	resp, err := svc.GetRecommendations(context.Background(), 500, 10)
+	if err != nil {
+		t.Fatalf("GetRecommendations returned error: %v", err)
+	}
+	if resp.Source != RecommendationSourceGenres {
+		t.Fatalf("expected genre-based recommendations, got %q", resp.Source)
+	}
+
+	// Both Genres shares two favorite genres, so it outranks the better rated
+	// single-genre titles; equal ratings fall back to views, not rating count;
+	// Already Read is in the library and never shows up
+	want := []string{"Both Genres", "Fantasy Top", "Fantasy Viewed", "Fantasy Low"}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, resp.Results)
+	}
+	for i, title := range want {
+		if resp.Results[i].Title != title {
+			t.Fatalf("position %d: expected %q, got %q", i, title, resp.Results[i].Title)
+		}
+	}
+}
+
+func TestGetRecommendations_ColdStartFallsBackToPopular(t *testing.T) {
+	svc := setupRecommendationService(t)
+
+	resp, err := svc.GetRecommendations(context.Background(), 501, 3)
+	if err != nil {
+		t.Fatalf("GetRecommendations returned error: %v", err)
+	}
+	if resp.Source != RecommendationSourcePopular {
+		t.Fatalf("expected popular fallback, got %q", resp.Source)
+	}
+	if len(resp.Results) != 3 || resp.Results[0].Title != "Romance Only" {
+		t.Fatalf("expected the most active manga first, got %+v", resp.Results)
+	}
+}
+
+func TestGetSimilarManga_RanksBySharedTags(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    INSERT INTO mangas (id, slug, title, rating_average) VALUES
+        (1, 'source', 'Source', 4.0),
+        (2, 'three-shared', 'Three Shared', 3.0),
+        (3, 'one-shared', 'One Shared', 5.0),
+        (4, 'unrelated', 'Unrelated', 5.0);
+    INSERT INTO tags (id, name) VALUES (1, 'Fantasy'), (2, 'Action'), (3, 'Drama'), (4, 'Comedy');
+    INSERT INTO manga_tags (manga_id, tag_id) VALUES
+        (1, 1), (1, 2), (1, 3),
+        (2, 1), (2, 2), (2, 3),
+        (3, 1),
+        (4, 4);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+
+	resp, err := NewService(db).GetSimilarManga(context.Background(), 1, 10)
+	if err != nil {
+		t.Fatalf("GetSimilarManga returned error: %v", err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("expected 2 similar manga, got %+v", resp.Results)
+	}
+	if resp.Results[0].Title != "Three Shared" || resp.Results[1].Title != "One Shared" {
+		t.Fatalf("expected three shared tags to rank first, got %q then %q", resp.Results[0].Title, resp.Results[1].Title)
+	}
+}
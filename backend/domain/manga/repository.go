@@ -3,19 +3,70 @@ package manga
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"math"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 )
 
+// ErrFTSUnavailable is returned by SearchFTS when full-text search cannot be used
+var ErrFTSUnavailable = errors.New("full-text search unavailable")
+
+// DefaultRatingPrior is how many average-rated votes every manga starts with
+// when computing its weighted rating
+const DefaultRatingPrior = 25
+
 // Repository handles manga metadata queries
 type Repository struct {
-	db *sql.DB
+	db          *sql.DB
+	reader      *sql.DB
+	ftsDisabled atomic.Bool
+	ratingPrior float64
 }
 
 // NewRepository creates repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, reader: db, ratingPrior: DefaultRatingPrior}
+}
+
+// SetRatingPrior sets m, the number of votes at the global mean blended into
+// every weighted rating; larger values need more reviews to move a manga
+func (r *Repository) SetRatingPrior(m float64) {
+	if m < 0 {
+		m = 0
+	}
+	r.ratingPrior = m
+}
+
+// globalMeanRating is C, the mean score across every rating of every manga
+// still in the catalogue; soft-deleted manga no longer pull it either way
+const globalMeanRating = `SELECT COALESCE(SUM(rating_average * rating_count) / NULLIF(SUM(rating_count), 0), 0) FROM mangas WHERE deleted_at IS NULL`
+
+// weightedRatingExpr is the Bayesian rating (v/(v+m))*R + (m/(v+m))*C of the
+// manga aliased m, written as (v*R + m*C) / (v+m). A manga with few ratings
+// stays near the global mean until enough votes accumulate.
+func (r *Repository) weightedRatingExpr() string {
+	prior := strconv.FormatFloat(r.ratingPrior, 'f', -1, 64)
+	return fmt.Sprintf("COALESCE((m.rating_count * m.rating_average + %[1]s * (%[2]s)) / (m.rating_count + %[1]s), 0)", prior, globalMeanRating)
+}
+
+// roundRating keeps two decimals of a computed rating
+func roundRating(rating float64) float64 {
+	return math.Round(rating*100) / 100
+}
+
+// SetReader routes read-only search and lookup queries to a replica; nil restores the primary
+func (r *Repository) SetReader(reader *sql.DB) {
+	if reader == nil {
+		reader = r.db
+	}
+	r.reader = reader
 }
 
 // Search searches for manga/novels based on criteria
@@ -28,11 +79,76 @@ func (r *Repository) Search(ctx context.Context, req SearchRequest) ([]Manga, in
 	trimmedQuery := strings.TrimSpace(req.Query)
 	if trimmedQuery != "" {
 		like := "%" + trimmedQuery + "%"
-		conditions = append(conditions, "(m.title LIKE ? OR m.synopsis LIKE ? OR m.alt_title LIKE ?)")
-		args = append(args, like, like, like)
+		conditions = append(conditions, "(m.title LIKE ? OR m.author LIKE ? OR m.synopsis LIKE ? OR m.alt_title LIKE ?)")
+		args = append(args, like, like, like, like)
+	}
+
+	filterConditions, filterArgs := searchFilters(req)
+	conditions = append(conditions, filterConditions...)
+	args = append(args, filterArgs...)
+
+	var orderBy string
+	switch req.SortBy {
+	case "date_updated":
+		orderBy = "m.updated_at DESC"
+	case "relevance":
+		orderBy = "m.views DESC, m.rating_average DESC"
+	default:
+		orderBy = "weighted_rating DESC, m.rating_count DESC"
+	}
+
+	return r.runSearch(ctx, req, "0 AS relevance", "", conditions, args, orderBy)
+}
+
+// SearchFTS searches manga using the manga_search FTS5 index.
+// Results are ranked with bm25, weighting title matches above author
+// matches above synopsis matches. It returns ErrFTSUnavailable when the
+// SQLite build lacks FTS5 or the index has not been created.
+func (r *Repository) SearchFTS(ctx context.Context, req SearchRequest) ([]Manga, int, error) {
+	if r.ftsDisabled.Load() {
+		return nil, 0, ErrFTSUnavailable
+	}
+
+	match := buildFTSQuery(req.Query)
+	if match == "" {
+		return r.Search(ctx, req)
 	}
 
-	// --- Filters ---
+	join := `
+JOIN (
+    SELECT rowid AS manga_id, rank
+    FROM manga_search
+    WHERE manga_search MATCH ? AND rank MATCH 'bm25(10.0, 5.0, 1.0)'
+) fts ON fts.manga_id = m.id
+`
+	conditions, args := searchFilters(req)
+	args = append([]interface{}{match}, args...)
+
+	var orderBy string
+	switch req.SortBy {
+	case "rating":
+		orderBy = "weighted_rating DESC, m.rating_count DESC"
+	case "date_updated":
+		orderBy = "m.updated_at DESC"
+	default:
+		orderBy = "fts.rank ASC, m.rating_count DESC"
+	}
+
+	results, total, err := r.runSearch(ctx, req, "-fts.rank AS relevance", join, conditions, args, orderBy)
+	if err != nil && isFTSUnavailable(err) {
+		logging.FromContext(ctx).Warn("repository: full-text search unavailable, falling back to LIKE", "err", err)
+		r.ftsDisabled.Store(true)
+		return nil, 0, ErrFTSUnavailable
+	}
+	return results, total, err
+}
+
+// searchFilters builds the non-text WHERE conditions shared by both search paths
+func searchFilters(req SearchRequest) ([]string, []interface{}) {
+	// Soft-deleted manga never show up in search
+	conditions := []string{"m.deleted_at IS NULL"}
+	var args []interface{}
+
 	if len(req.Genres) > 0 {
 		placeholders := make([]string, len(req.Genres))
 		for i, g := range req.Genres {
@@ -64,6 +180,11 @@ m.id IN (
 		args = append(args, *req.MaxRating)
 	}
 
+	return conditions, args
+}
+
+// runSearch executes a paginated search query and its count query
+func (r *Repository) runSearch(ctx context.Context, req SearchRequest, relevanceColumn, join string, conditions []string, args []interface{}, orderBy string) ([]Manga, int, error) {
 	baseQuery := `
 SELECT
     m.id,
@@ -77,8 +198,11 @@ SELECT
     m.synopsis,
     m.cover_url,
     m.rating_average,
-    m.rating_count
+    m.views,
+    ` + r.weightedRatingExpr() + ` AS weighted_rating,
+    ` + relevanceColumn + `
 FROM mangas m
+` + join + `
 LEFT JOIN manga_tags mt ON m.id = mt.manga_id
 LEFT JOIN tags t ON mt.tag_id = t.id
 `
@@ -92,33 +216,15 @@ LEFT JOIN tags t ON mt.tag_id = t.id
 	groupBy := " GROUP BY m.id"
 	baseQuery += groupBy
 
+	// Ordering does not affect the total, so count before sorting
+	countQuery := "SELECT COUNT(*) FROM (" + baseQuery + ") as counted"
+
 	// --- Sorting ---
-	switch req.SortBy {
-	case "rating":
-		baseQuery += " ORDER BY m.rating_average DESC, m.rating_count DESC"
-	case "date_updated":
-		baseQuery += " ORDER BY m.updated_at DESC"
-	case "relevance":
-		baseQuery += " ORDER BY m.rating_count DESC, m.rating_average DESC"
-	default:
-		baseQuery += " ORDER BY m.rating_average DESC, m.rating_count DESC"
-	}
+	baseQuery += " ORDER BY " + orderBy
 
 	// --- Pagination ---
-	limit := req.Limit
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-	page := req.Page
-	if page <= 0 {
-		page = 1
-	}
-	offset := (page - 1) * limit
-
-	countQuery := "SELECT COUNT(*) FROM (" + baseQuery + ") as counted"
+	page, limit := pagination.Normalize(req.Page, req.Limit, pagination.Default())
+	offset := pagination.Offset(page, limit)
 
 	queryArgs := make([]interface{}, len(args))
 	copy(queryArgs, args)
@@ -127,7 +233,7 @@ LEFT JOIN tags t ON mt.tag_id = t.id
 	queryArgs = append(queryArgs, limit, offset)
 
 	// --- Execute Search Query ---
-	rows, err := r.db.QueryContext(ctx, baseQuery, queryArgs...)
+	rows, err := r.reader.QueryContext(ctx, baseQuery, queryArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -158,10 +264,13 @@ LEFT JOIN tags t ON mt.tag_id = t.id
 			&image,
 			&m.RatingPoint,
 			&views,
+			&m.WeightedRating,
+			&m.RelevanceScore,
 		); err != nil {
 			return nil, 0, err
 		}
 		m.Name = m.Title
+		m.WeightedRating = roundRating(m.WeightedRating)
 		m.Views = views
 		m.Author = author.String
 		m.Artist = artist.String
@@ -182,16 +291,35 @@ LEFT JOIN tags t ON mt.tag_id = t.id
 
 	// --- Count Query ---
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := r.reader.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
 	return results, total, nil
 }
 
-// GetByID retrieves manga details by ID
-func (r *Repository) GetByID(ctx context.Context, mangaID int64) (*Manga, error) {
-	query := `
+// buildFTSQuery turns free text into an FTS5 query of quoted prefix terms,
+// so user input cannot inject FTS operators
+func buildFTSQuery(query string) string {
+	terms := strings.Fields(query)
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ReplaceAll(term, `"`, `""`)
+		quoted = append(quoted, `"`+term+`"*`)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func isFTSUnavailable(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such module: fts5") ||
+		strings.Contains(msg, "no such table: manga_search") ||
+		strings.Contains(msg, "no such function: bm25")
+}
+
+// detailQuery selects a live manga with its genres for each row matching where
+func (r *Repository) detailQuery(where string) string {
+	return `
 SELECT
     m.id,
     m.slug,
@@ -204,14 +332,23 @@ SELECT
     m.synopsis,
     m.cover_url,
     m.rating_average,
-    m.rating_count
+    m.views,
+    ` + r.weightedRatingExpr() + `
 FROM mangas m
 LEFT JOIN manga_tags mt ON m.id = mt.manga_id
 LEFT JOIN tags t ON mt.tag_id = t.id
-WHERE m.id = ?
+WHERE ` + where + ` AND m.deleted_at IS NULL
 GROUP BY m.id
 `
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanDetail reads one row produced by detailQuery
+func scanDetail(row rowScanner) (*Manga, error) {
 	var (
 		m      Manga
 		alt    sql.NullString
@@ -222,7 +359,7 @@ GROUP BY m.id
 		genres sql.NullString
 		views  int64
 	)
-	err := r.db.QueryRowContext(ctx, query, mangaID).Scan(
+	if err := row.Scan(
 		&m.ID,
 		&m.Slug,
 		&m.Title,
@@ -235,15 +372,12 @@ GROUP BY m.id
 		&image,
 		&m.RatingPoint,
 		&views,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
+		&m.WeightedRating,
+	); err != nil {
 		return nil, err
 	}
 	m.Name = m.Title
+	m.WeightedRating = roundRating(m.WeightedRating)
 	m.Views = views
 	m.Author = author.String
 	m.Artist = artist.String
@@ -258,50 +392,109 @@ GROUP BY m.id
 	return &m, nil
 }
 
-// GetPopularManga returns the most popular manga based on rating points
-func (r *Repository) GetPopularManga(ctx context.Context, limit int) ([]Manga, error) {
-	if limit <= 0 {
-		limit = 10
+// GetByID retrieves manga details by ID
+func (r *Repository) GetByID(ctx context.Context, mangaID int64) (*Manga, error) {
+	m, err := scanDetail(r.reader.QueryRowContext(ctx, r.detailQuery("m.id = ?"), mangaID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetByIDs retrieves the details of several manga in one query, keyed by ID.
+// IDs that do not exist or are soft-deleted are absent from the map.
+func (r *Repository) GetByIDs(ctx context.Context, mangaIDs []int64) (map[int64]*Manga, error) {
+	found := make(map[int64]*Manga, len(mangaIDs))
+	if len(mangaIDs) == 0 {
+		return found, nil
 	}
-	if limit > 50 {
-		limit = 50
+
+	placeholders := make([]string, len(mangaIDs))
+	args := make([]interface{}, len(mangaIDs))
+	for i, id := range mangaIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := r.reader.QueryContext(ctx, r.detailQuery("m.id IN ("+strings.Join(placeholders, ", ")+")"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanDetail(rows)
+		if err != nil {
+			return nil, err
+		}
+		found[m.ID] = m
+	}
+	return found, rows.Err()
+}
+
+// GetPopularManga returns manga ranked by reading activity since the given time.
+// Activity counts reading_progress updates and reading_history events; ties
+// (including manga with no activity in the window) fall back to weighted rating,
+// then view count.
+// A zero since counts all activity.
+func (r *Repository) GetPopularManga(ctx context.Context, since time.Time, limit, offset int) ([]Manga, int, error) {
+	limit = pagination.Limit(limit, popularLimits)
+	if offset < 0 {
+		offset = 0
 	}
 
+	// Timestamps are stored as "YYYY-MM-DD HH:MM:SS" UTC text, so compare in the same format
+	sinceStr := since.UTC().Format("2006-01-02 15:04:05")
+
 	query := `
 SELECT
-    id,
-    slug,
-    title,
-    alt_title,
-    author,
-    artist,
-    status,
-    synopsis,
-    cover_url,
-    rating_average,
-    rating_count
-FROM mangas
-ORDER BY rating_average DESC, rating_count DESC, updated_at DESC
-LIMIT ?
+    m.id,
+    m.slug,
+    m.title,
+    m.alt_title,
+    m.author,
+    m.artist,
+    m.status,
+    m.synopsis,
+    m.cover_url,
+    m.rating_average,
+    m.views,
+    ` + r.weightedRatingExpr() + ` AS weighted_rating
+FROM mangas m
+LEFT JOIN (
+    SELECT manga_id, SUM(events) AS activity
+    FROM (
+        SELECT manga_id, COUNT(*) AS events FROM reading_progress WHERE last_read_at >= ? GROUP BY manga_id
+        UNION ALL
+        SELECT manga_id, COUNT(*) AS events FROM reading_history WHERE created_at >= ? GROUP BY manga_id
+    )
+    GROUP BY manga_id
+) a ON a.manga_id = m.id
+WHERE m.deleted_at IS NULL
+ORDER BY COALESCE(a.activity, 0) DESC, weighted_rating DESC, m.views DESC, m.updated_at DESC
+LIMIT ? OFFSET ?
 `
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.reader.QueryContext(ctx, query, sinceStr, sinceStr, limit, offset)
 	if err != nil {
-		log.Printf("repository: GetPopularManga query failed (limit=%d): %v", limit, err)
-		return nil, err
+		logging.FromContext(ctx).Error("repository: GetPopularManga query failed", "since", sinceStr, "limit", limit, "offset", offset, "err", err)
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var popular []Manga
 	for rows.Next() {
 		var (
-			m           Manga
-			alt         sql.NullString
-			author      sql.NullString
-			artist      sql.NullString
-			desc        sql.NullString
-			image       sql.NullString
-			ratingCount sql.NullInt64
+			m         Manga
+			alt       sql.NullString
+			author    sql.NullString
+			artist    sql.NullString
+			desc      sql.NullString
+			image     sql.NullString
+			viewCount sql.NullInt64
 		)
 		if err := rows.Scan(
 			&m.ID,
@@ -314,18 +507,20 @@ LIMIT ?
 			&desc,
 			&image,
 			&m.RatingPoint,
-			&ratingCount,
+			&viewCount,
+			&m.WeightedRating,
 		); err != nil {
-			log.Printf("repository: GetPopularManga scan failed (id=%d): %v", m.ID, err)
-			return nil, err
+			logging.FromContext(ctx).Error("repository: GetPopularManga scan failed", "manga_id", m.ID, "err", err)
+			return nil, 0, err
 		}
 		m.Name = m.Title
+		m.WeightedRating = roundRating(m.WeightedRating)
 		m.Author = author.String
 		m.Artist = artist.String
 		m.Description = desc.String
 		m.Image = image.String
-		if ratingCount.Valid {
-			m.Views = ratingCount.Int64
+		if viewCount.Valid {
+			m.Views = viewCount.Int64
 		}
 		if alt.Valid && m.Slug == "" {
 			m.Slug = alt.String
@@ -334,19 +529,169 @@ LIMIT ?
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("repository: GetPopularManga rows iteration failed: %v", err)
+		logging.FromContext(ctx).Error("repository: GetPopularManga rows iteration failed", "err", err)
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.reader.QueryRowContext(ctx, `SELECT COUNT(*) FROM mangas WHERE deleted_at IS NULL`).Scan(&total); err != nil {
+		logging.FromContext(ctx).Error("repository: GetPopularManga count failed", "err", err)
+		return nil, 0, err
+	}
+
+	return popular, total, nil
+}
+
+// GetRecommendations returns manga tagged with any of the given genres that
+// are not in the user's library, ordered by the number of matching genres,
+// then rating and views. The library is the same libraries table the
+// favourite genres are drawn from.
+func (r *Repository) GetRecommendations(ctx context.Context, userID int64, genres []string, limit int) ([]Manga, error) {
+	if len(genres) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(genres))
+	args := make([]interface{}, 0, len(genres)+2)
+	for i, g := range genres {
+		placeholders[i] = "?"
+		args = append(args, strings.ToLower(g))
+	}
+	args = append(args, userID, limit)
+
+	query := fmt.Sprintf(`
+SELECT
+    m.id,
+    m.slug,
+    m.title,
+    m.alt_title,
+    m.author,
+    m.artist,
+    m.status,
+    m.synopsis,
+    m.cover_url,
+    m.rating_average,
+    m.views,
+    COUNT(DISTINCT t.id) AS genre_matches
+FROM mangas m
+JOIN manga_tags mt ON mt.manga_id = m.id
+JOIN tags t ON t.id = mt.tag_id
+WHERE LOWER(t.name) IN (%s)
+  AND m.id NOT IN (SELECT manga_id FROM libraries WHERE user_id = ?)
+  AND m.deleted_at IS NULL
+GROUP BY m.id, m.slug, m.title, m.alt_title, m.author, m.artist, m.status, m.synopsis, m.cover_url, m.rating_average, m.views
+ORDER BY genre_matches DESC, m.rating_average DESC, m.views DESC, m.id
+LIMIT ?
+`, strings.Join(placeholders, ", "))
+
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRankedManga(rows)
+}
+
+// scanRankedManga reads manga rows followed by a ranking count column
+func scanRankedManga(rows *sql.Rows) ([]Manga, error) {
+	var results []Manga
+	for rows.Next() {
+		var (
+			m         Manga
+			alt       sql.NullString
+			author    sql.NullString
+			artist    sql.NullString
+			desc      sql.NullString
+			image     sql.NullString
+			viewCount sql.NullInt64
+			rank      int
+		)
+		if err := rows.Scan(
+			&m.ID,
+			&m.Slug,
+			&m.Title,
+			&alt,
+			&author,
+			&artist,
+			&m.Status,
+			&desc,
+			&image,
+			&m.RatingPoint,
+			&viewCount,
+			&rank,
+		); err != nil {
+			return nil, err
+		}
+		m.Name = m.Title
+		m.Author = author.String
+		m.Artist = artist.String
+		m.Description = desc.String
+		m.Image = image.String
+		if viewCount.Valid {
+			m.Views = viewCount.Int64
+		}
+		if alt.Valid && m.Slug == "" {
+			m.Slug = alt.String
+		}
+		results = append(results, m)
+	}
+	return results, rows.Err()
+}
+
+// GetSimilarManga returns manga sharing tags with the given manga, ordered by
+// the number of shared tags, then rating and rating count. The source manga
+// itself is excluded.
+func (r *Repository) GetSimilarManga(ctx context.Context, mangaID int64, limit int) ([]Manga, error) {
+	query := `
+SELECT
+    m.id,
+    m.slug,
+    m.title,
+    m.alt_title,
+    m.author,
+    m.artist,
+    m.status,
+    m.synopsis,
+    m.cover_url,
+    m.rating_average,
+    m.views,
+    COUNT(DISTINCT mt.tag_id) AS shared_tags
+FROM manga_tags source
+JOIN manga_tags mt ON mt.tag_id = source.tag_id AND mt.manga_id <> source.manga_id
+JOIN mangas m ON m.id = mt.manga_id
+WHERE source.manga_id = ? AND m.deleted_at IS NULL
+GROUP BY m.id, m.slug, m.title, m.alt_title, m.author, m.artist, m.status, m.synopsis, m.cover_url, m.rating_average, m.views
+ORDER BY shared_tags DESC, m.rating_average DESC, m.rating_count DESC, m.id
+LIMIT ?
+`
+	rows, err := r.reader.QueryContext(ctx, query, mangaID, limit)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return popular, nil
+	return scanRankedManga(rows)
 }
 
-// GetByTitle retrieves a manga by title (case-insensitive)
+// GetByTitle retrieves a manga by title (case-insensitive). Soft-deleted manga
+// still match so imports do not recreate a retired title.
 func (r *Repository) GetByTitle(ctx context.Context, title string) (*Manga, error) {
+	return r.lookupOne(ctx, "LOWER(title) = LOWER(?)", title)
+}
+
+// GetBySlug retrieves a manga by its exact slug. Like GetByTitle it matches
+// soft-deleted manga, whose slugs stay reserved by the unique constraint.
+func (r *Repository) GetBySlug(ctx context.Context, slug string) (*Manga, error) {
+	return r.lookupOne(ctx, "slug = ?", slug)
+}
+
+// lookupOne returns the first manga matching a single-argument condition
+func (r *Repository) lookupOne(ctx context.Context, condition string, arg interface{}) (*Manga, error) {
 	query := `
-SELECT id, slug, title, alt_title, author, artist, status, synopsis, cover_url, rating_average, rating_count
+SELECT id, slug, title, alt_title, author, artist, status, synopsis, cover_url, rating_average, views
 FROM mangas
-WHERE LOWER(title) = LOWER(?)
+WHERE ` + condition + `
 LIMIT 1
 `
 
@@ -359,7 +704,7 @@ LIMIT 1
 		image  sql.NullString
 		views  int64
 	)
-	err := r.db.QueryRowContext(ctx, query, title).Scan(
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
 		&m.ID,
 		&m.Slug,
 		&m.Title,
@@ -390,6 +735,42 @@ LIMIT 1
 	return &m, nil
 }
 
+// MatchTitle finds the manga whose title or alternative title best matches
+// the given text. Exact matches win, then the shortest title containing the
+// text. It returns nil when nothing matches.
+func (r *Repository) MatchTitle(ctx context.Context, title string) (*Manga, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(title), " "))
+	if normalized == "" {
+		return nil, nil
+	}
+	pattern := "%" + normalized + "%"
+
+	query := `
+SELECT id, title
+FROM mangas
+WHERE (LOWER(title) LIKE ? OR LOWER(COALESCE(alt_title, '')) LIKE ?)
+  AND deleted_at IS NULL
+ORDER BY CASE
+             WHEN LOWER(title) = ? THEN 0
+             WHEN LOWER(COALESCE(alt_title, '')) = ? THEN 1
+             ELSE 2
+         END,
+         LENGTH(title),
+         id
+LIMIT 1
+`
+	var m Manga
+	err := r.db.QueryRowContext(ctx, query, pattern, pattern, normalized, normalized).Scan(&m.ID, &m.Title)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.Name = m.Title
+	return &m, nil
+}
+
 // Create inserts a manga and its tags, returning the new ID.
 func (r *Repository) Create(ctx context.Context, req CreateMangaRequest) (int64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -403,9 +784,9 @@ func (r *Repository) Create(ctx context.Context, req CreateMangaRequest) (int64,
 	}
 
 	result, err := tx.ExecContext(ctx, `
-INSERT INTO mangas (slug, title, alt_title, cover_url, author, artist, status, synopsis, language, rating_average, rating_count, last_chapter)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-`, req.Slug, req.Title, req.AltTitle, req.CoverURL, req.Author, req.Artist, req.Status, req.Synopsis, req.Language, req.Rating, req.Views, req.LastChapter)
+INSERT INTO mangas (slug, title, alt_title, cover_url, author, artist, status, synopsis, language, rating_average, rating_count, views, last_chapter)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`, req.Slug, req.Title, req.AltTitle, req.CoverURL, req.Author, req.Artist, req.Status, req.Synopsis, req.Language, req.Rating, req.Views, req.Views, req.LastChapter)
 	if err != nil {
 		return 0, err
 	}
@@ -30,7 +30,9 @@ func setupTestDB(t *testing.T) *sql.DB {
         language TEXT DEFAULT 'ja',
         rating_average REAL NOT NULL DEFAULT 0,
         rating_count INTEGER NOT NULL DEFAULT 0,
-        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+        views INTEGER NOT NULL DEFAULT 0,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        deleted_at DATETIME
     );
 
     CREATE TABLE tags (
@@ -178,3 +180,79 @@ func TestRepositorySearch_NoResults(t *testing.T) {
 		t.Fatalf("expected no results, got %d", len(resp))
 	}
 }
+
+func TestRepositorySearch_WeightedRatingOutranksSingleReview(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec(`
+        INSERT INTO mangas (slug, title, rating_average, rating_count) VALUES
+            ('one-hit', 'One Hit Wonder', 10, 1),
+            ('beloved', 'Beloved Series', 9, 3000),
+            ('middling', 'Middling Tale', 6, 500);
+    `); err != nil {
+		t.Fatalf("failed to seed manga: %v", err)
+	}
+
+	repo := NewRepository(db)
+	repo.SetRatingPrior(50)
+	ctx := context.Background()
+
+	results, _, err := repo.Search(ctx, SearchRequest{SortBy: "rating"})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	want := []string{"Beloved Series", "One Hit Wonder", "Middling Tale"}
+	for i, title := range want {
+		if results[i].Title != title {
+			t.Fatalf("position %d: expected %s, got %s", i, title, results[i].Title)
+		}
+	}
+	if results[1].RatingPoint != 10 {
+		t.Fatalf("expected the raw rating to stay 10, got %v", results[1].RatingPoint)
+	}
+
+	// C = (10 + 27000 + 3000) / 3501 = 8.5718; (10 + 50*C) / 51 = 8.60
+	manga, err := repo.GetByID(ctx, results[1].ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if manga.WeightedRating != 8.6 {
+		t.Fatalf("expected weighted rating 8.6 for a single 10/10 review, got %v", manga.WeightedRating)
+	}
+
+	defaults, _, err := repo.Search(ctx, SearchRequest{})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if defaults[0].Title != "Beloved Series" {
+		t.Fatalf("expected the default sort to use the weighted rating, got %s first", defaults[0].Title)
+	}
+}
+
+func TestWeightedRatingIgnoresSoftDeletedManga(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec(`
+        INSERT INTO mangas (slug, title, rating_average, rating_count, deleted_at) VALUES
+            ('one-hit', 'One Hit Wonder', 10, 1, NULL),
+            ('steady', 'Steady Seller', 8, 99, NULL),
+            ('pulled', 'Pulled Series', 1, 9900, CURRENT_TIMESTAMP);
+    `); err != nil {
+		t.Fatalf("failed to seed manga: %v", err)
+	}
+
+	repo := NewRepository(db)
+	repo.SetRatingPrior(100)
+
+	// C = (10 + 792) / 100 = 8.02 without the pulled series; (10 + 100*C) / 101 = 8.04.
+	// Counting it would drop C to 1.07 and the weighted rating to 1.16.
+	manga, err := repo.GetByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if manga.WeightedRating != 8.04 {
+		t.Fatalf("expected weighted rating 8.04 from live manga only, got %v", manga.WeightedRating)
+	}
+}
@@ -0,0 +1,173 @@
+package manga
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+const ftsTestSchema = `
+CREATE VIRTUAL TABLE manga_search USING fts5(
+    title,
+    author,
+    synopsis,
+    content='mangas',
+    content_rowid='id'
+);
+
+CREATE TRIGGER mangas_ai_fts
+AFTER INSERT ON mangas
+BEGIN
+    INSERT INTO manga_search(rowid, title, author, synopsis)
+    VALUES (new.id, new.title, new.author, new.synopsis);
+END;
+`
+
+func setupFTS(tb testing.TB, db *sql.DB) {
+	tb.Helper()
+	if _, err := db.Exec(ftsTestSchema); err != nil {
+		tb.Fatalf("failed to create fts schema: %v", err)
+	}
+}
+
+func TestRepositorySearchFTS_RanksTitleAboveAuthorAndSynopsis(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	setupFTS(t, db)
+
+	for _, m := range []struct{ slug, title, author, synopsis string }{
+		{"synopsis-only", "Quiet Days", "Someone", "a dragon appears at the end"},
+		{"author-only", "Sky Road", "Dragon Writer", "travel story"},
+		{"title-match", "Dragon Knight", "Other", "knight story"},
+	} {
+		if _, err := db.Exec(`INSERT INTO mangas (slug, title, author, synopsis) VALUES (?, ?, ?, ?)`, m.slug, m.title, m.author, m.synopsis); err != nil {
+			t.Fatalf("failed to insert manga: %v", err)
+		}
+	}
+
+	repo := NewRepository(db)
+	results, total, err := repo.SearchFTS(context.Background(), SearchRequest{Query: "dragon", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchFTS failed: %v", err)
+	}
+	if total != 3 || len(results) != 3 {
+		t.Fatalf("expected 3 results, got total=%d len=%d", total, len(results))
+	}
+
+	want := []string{"Dragon Knight", "Sky Road", "Quiet Days"}
+	for i, title := range want {
+		if results[i].Title != title {
+			t.Fatalf("result %d: expected %q, got %q", i, title, results[i].Title)
+		}
+	}
+}
+
+func TestRepositorySearchFTS_UnavailableWithoutIndex(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	repo := NewRepository(db)
+	if _, _, err := repo.SearchFTS(context.Background(), SearchRequest{Query: "hero"}); err != ErrFTSUnavailable {
+		t.Fatalf("expected ErrFTSUnavailable, got %v", err)
+	}
+}
+
+func seedSearchBenchmark(b *testing.B, withFTS bool) *Repository {
+	b.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		b.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	b.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        slug TEXT NOT NULL UNIQUE,
+        title TEXT NOT NULL,
+        alt_title TEXT,
+        cover_url TEXT,
+        author TEXT,
+        artist TEXT,
+        status TEXT NOT NULL DEFAULT 'ongoing',
+        synopsis TEXT,
+        rating_average REAL NOT NULL DEFAULT 0,
+        rating_count INTEGER NOT NULL DEFAULT 0,
+        views INTEGER NOT NULL DEFAULT 0,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        deleted_at DATETIME
+    );
+    CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE);
+    CREATE TABLE manga_tags (manga_id INTEGER NOT NULL, tag_id INTEGER NOT NULL, PRIMARY KEY (manga_id, tag_id));
+    `); err != nil {
+		b.Fatalf("failed to create schema: %v", err)
+	}
+	if withFTS {
+		setupFTS(b, db)
+	}
+
+	words := []string{"dragon", "knight", "shadow", "ocean", "sword", "garden", "storm", "crystal", "hunter", "academy"}
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("failed to begin seed transaction: %v", err)
+	}
+	for i := 0; i < 10000; i++ {
+		title := fmt.Sprintf("%s %s %d", words[i%len(words)], words[(i/10)%len(words)], i)
+		synopsis := fmt.Sprintf("A tale of the %s and the %s", words[(i/3)%len(words)], words[(i/7)%len(words)])
+		if _, err := tx.Exec(`INSERT INTO mangas (slug, title, author, synopsis, rating_average, rating_count) VALUES (?, ?, ?, ?, ?, ?)`,
+			fmt.Sprintf("manga-%d", i), title, fmt.Sprintf("Author %d", i%500), synopsis, float64(i%50)/10, i%1000); err != nil {
+			b.Fatalf("failed to seed manga: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("failed to commit seed: %v", err)
+	}
+
+	return NewRepository(db)
+}
+
+var searchBenchmarkQueries = []struct {
+	name  string
+	query string
+}{
+	{"common_term", "crystal"},
+	{"rare_term", "4271"},
+}
+
+func BenchmarkRepositorySearchLike(b *testing.B) {
+	repo := seedSearchBenchmark(b, false)
+	ctx := context.Background()
+
+	for _, q := range searchBenchmarkQueries {
+		b.Run(q.name, func(b *testing.B) {
+			req := SearchRequest{Query: q.query, Limit: 20, SortBy: "relevance"}
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.Search(ctx, req); err != nil {
+					b.Fatalf("search failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRepositorySearchFTS(b *testing.B) {
+	repo := seedSearchBenchmark(b, true)
+	ctx := context.Background()
+
+	for _, q := range searchBenchmarkQueries {
+		b.Run(q.name, func(b *testing.B) {
+			req := SearchRequest{Query: q.query, Limit: 20, SortBy: "relevance"}
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.SearchFTS(ctx, req); err != nil {
+					b.Fatalf("search failed: %v", err)
+				}
+			}
+		})
+	}
+}
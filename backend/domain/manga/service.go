@@ -2,12 +2,20 @@ package manga
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
 )
 
@@ -15,13 +23,28 @@ var (
 	ErrDatabaseError       = errors.New("database error")
 	ErrMangaNotFound       = errors.New("manga not found")
 	ErrDatabaseUnavailable = errors.New("database unavailable")
+	ErrInvalidPeriod       = errors.New("period must be one of: day, week, month, all")
 )
 
 const defaultChapterListLimit = 100
 
+// maxRankedLimit caps the ranked listings (popular, trending, recommended and
+// similar manga). They are ranked per request rather than read off an index,
+// so they stay well below the catalogue's pagination maximum.
+const maxRankedLimit = 50
+
+// Page sizes of the ranked listings
+var (
+	popularLimits     = pagination.Defaults{Limit: 40, MaxLimit: maxRankedLimit}
+	trendingLimits    = pagination.Defaults{Limit: 20, MaxLimit: maxRankedLimit}
+	recommendedLimits = pagination.Defaults{Limit: 20, MaxLimit: maxRankedLimit}
+	similarLimits     = pagination.Defaults{Limit: 10, MaxLimit: maxRankedLimit}
+)
+
 // ChapterService exposes chapter operations required by the manga service
 type ChapterService interface {
 	GetChapterCount(ctx context.Context, mangaID int64) (int, error)
+	GetChapterCounts(ctx context.Context, mangaIDs []int64) (map[int64]int, error)
 	GetChapters(ctx context.Context, mangaID int64, limit, offset int) ([]pkgchapter.ChapterSummary, error)
 }
 
@@ -30,19 +53,48 @@ type Service struct {
 	repo           *Repository
 	cache          MangaCacher
 	dbHealth       DBHealthChecker
+	breaker        DBBreaker
 	writeQueue     WriteQueue
 	chapterService ChapterService
+	genreAffinity  GenreAffinity
+	coverDir       string
+
+	// views buffers view counts in Redis; localViews takes them without
+	// Redis or while it fails. See RecordView.
+	views      ViewBuffer
+	localViews *memoryViewBuffer
+
+	// lastPopularInvalidation is the unix nano time popular lists were last
+	// dropped because of reading progress
+	lastPopularInvalidation atomic.Int64
 }
 
+// popularInvalidationInterval limits how often reading progress clears the
+// popular lists; busy readers would otherwise keep the cache permanently cold
+const popularInvalidationInterval = time.Minute
+
 // MangaCacher interface for manga caching
 type MangaCacher interface {
 	GetMangaDetail(ctx context.Context, mangaID int64) (*MangaDetail, error)
 	SetMangaDetail(ctx context.Context, mangaID int64, detail *MangaDetail) error
-	InvalidateMangaDetail(ctx context.Context, mangaID int64) error
+	InvalidateManga(ctx context.Context, mangaID int64) error
+	InvalidatePopular(ctx context.Context) error
+	InvalidateSearch(ctx context.Context) error
 	GetSearchResults(ctx context.Context, cacheKey string) (*SearchResponse, error)
 	SetSearchResults(ctx context.Context, cacheKey string, response *SearchResponse) error
-	GetPopularManga(ctx context.Context, limit int) ([]Manga, error)
-	SetPopularManga(ctx context.Context, limit int, popular []Manga) error
+	GetPopularManga(ctx context.Context, period string, page, limit int) (*PopularMangaResponse, error)
+	SetPopularManga(ctx context.Context, period string, page, limit int, popular *PopularMangaResponse) error
+	GetTrendingManga(ctx context.Context, window string, limit int) (*TrendingMangaResponse, error)
+	SetTrendingManga(ctx context.Context, window string, limit int, trending *TrendingMangaResponse) error
+	GetSuggestions(ctx context.Context, prefix string, limit int) (*SuggestResponse, error)
+	SetSuggestions(ctx context.Context, prefix string, limit int, suggestions *SuggestResponse) error
+	GetTags(ctx context.Context) (*TagListResponse, error)
+	SetTags(ctx context.Context, tags *TagListResponse) error
+	InvalidateTags(ctx context.Context) error
+	GetRecommendations(ctx context.Context, userID int64, limit int) (*RecommendationsResponse, error)
+	SetRecommendations(ctx context.Context, userID int64, limit int, recommendations *RecommendationsResponse) error
+	GetSimilarManga(ctx context.Context, mangaID int64, limit int) (*SimilarMangaResponse, error)
+	SetSimilarManga(ctx context.Context, mangaID int64, limit int, similar *SimilarMangaResponse) error
 }
 
 // DBHealthChecker exposes database status
@@ -50,6 +102,17 @@ type DBHealthChecker interface {
 	IsHealthy() bool
 }
 
+// DBBreaker short-circuits database reads while the database keeps failing
+type DBBreaker interface {
+	Allow() error
+	Record(err error)
+}
+
+// GenreAffinity exposes a user's favorite genres, most read first
+type GenreAffinity interface {
+	FavoriteGenres(ctx context.Context, userID int64) ([]history.GenreStat, error)
+}
+
 // WriteQueue defines the queuing operations used by the manga service
 type WriteQueue interface {
 	Enqueue(opType string, userID, mangaID int64, data map[string]interface{}) error
@@ -58,7 +121,8 @@ type WriteQueue interface {
 // NewService creates a manga service
 func NewService(db *sql.DB) *Service {
 	return &Service{
-		repo: NewRepository(db),
+		repo:       NewRepository(db),
+		localViews: newMemoryViewBuffer(),
 	}
 }
 
@@ -72,6 +136,22 @@ func (s *Service) SetDBHealth(checker DBHealthChecker) {
 	s.dbHealth = checker
 }
 
+// SetReadReplica routes search and lookup reads to a read replica
+func (s *Service) SetReadReplica(replica *sql.DB) {
+	s.repo.SetReader(replica)
+}
+
+// SetRatingPrior tunes how many votes a manga needs before its weighted
+// rating moves away from the global mean
+func (s *Service) SetRatingPrior(m float64) {
+	s.repo.SetRatingPrior(m)
+}
+
+// SetCircuitBreaker sets the breaker consulted before read queries
+func (s *Service) SetCircuitBreaker(breaker DBBreaker) {
+	s.breaker = breaker
+}
+
 // SetWriteQueue sets the write queue used for offline write queuing
 func (s *Service) SetWriteQueue(queue WriteQueue) {
 	s.writeQueue = queue
@@ -82,6 +162,23 @@ func (s *Service) IsDBHealthy() bool {
 	return s.dbHealth == nil || s.dbHealth.IsHealthy()
 }
 
+// allowRead reports whether a read query may hit the database
+func (s *Service) allowRead() bool {
+	return s.breaker == nil || s.breaker.Allow() == nil
+}
+
+// recordRead reports a read query's outcome to the circuit breaker
+func (s *Service) recordRead(err error) {
+	if s.breaker != nil {
+		s.breaker.Record(err)
+	}
+}
+
+// SetGenreAffinity configures where recommendations get favorite genres from
+func (s *Service) SetGenreAffinity(g GenreAffinity) {
+	s.genreAffinity = g
+}
+
 // SetChapterService injects the chapter service
 func (s *Service) SetChapterService(chapterSvc ChapterService) {
 	s.chapterService = chapterSvc
@@ -89,18 +186,11 @@ func (s *Service) SetChapterService(chapterSvc ChapterService) {
 
 // Search searches for manga based on criteria
 func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
-	if req.Page < 1 {
-		req.Page = 1
-	}
-	if req.Limit < 1 {
-		req.Limit = 20
-	}
-	if req.Limit > 100 {
-		req.Limit = 100
-	}
+	req.Page, req.Limit = pagination.Normalize(req.Page, req.Limit, pagination.Default())
 	if req.Page > 10000 {
 		req.Page = 10000
 	}
+	req = normalizeSearchRequest(req)
 
 	dbHealthy := s.IsDBHealthy()
 
@@ -111,11 +201,24 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchRespons
 		}
 	}
 
-	if !dbHealthy {
+	if !dbHealthy || !s.allowRead() {
 		return nil, ErrDatabaseUnavailable
 	}
 
-	results, total, err := s.repo.Search(ctx, req)
+	var (
+		results []Manga
+		total   int
+		err     error
+	)
+	if strings.TrimSpace(req.Query) != "" {
+		results, total, err = s.repo.SearchFTS(ctx, req)
+		if errors.Is(err, ErrFTSUnavailable) {
+			results, total, err = s.repo.Search(ctx, req)
+		}
+	} else {
+		results, total, err = s.repo.Search(ctx, req)
+	}
+	s.recordRead(err)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
@@ -133,7 +236,7 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchRespons
 		Pages:   pages,
 	}
 
-	if s.cache != nil {
+	if s.cache != nil && total != searchTotalEstimated {
 		cacheKey := GenerateSearchCacheKey(req)
 		_ = s.cache.SetSearchResults(ctx, cacheKey, response)
 	}
@@ -141,69 +244,255 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchRespons
 	return response, nil
 }
 
-// GenerateSearchCacheKey generates a cache key for search request
-func GenerateSearchCacheKey(req SearchRequest) string {
-	key := fmt.Sprintf("q:%s", req.Query)
+// searchTotalEstimated marks a total that is an estimate rather than an exact
+// count; such responses are not cached
+const searchTotalEstimated = -1
+
+// normalizeSearchRequest puts equivalent requests into one canonical form so
+// they run the same SQL and share a cache entry: the query is trimmed with
+// inner whitespace collapsed, genres are deduplicated and sorted, and rating
+// bounds are rounded to one decimal.
+func normalizeSearchRequest(req SearchRequest) SearchRequest {
+	req.Query = strings.Join(strings.Fields(req.Query), " ")
+	req.Status = strings.TrimSpace(req.Status)
+	req.SortBy = strings.TrimSpace(req.SortBy)
+
 	if len(req.Genres) > 0 {
-		key += fmt.Sprintf(":genres:%v", req.Genres)
+		seen := make(map[string]bool, len(req.Genres))
+		genres := make([]string, 0, len(req.Genres))
+		for _, g := range req.Genres {
+			g = strings.TrimSpace(g)
+			if g == "" || seen[g] {
+				continue
+			}
+			seen[g] = true
+			genres = append(genres, g)
+		}
+		sort.Strings(genres)
+		req.Genres = genres
 	}
-	if req.Status != "" {
-		key += fmt.Sprintf(":status:%s", req.Status)
+
+	roundRating := func(r *float64) *float64 {
+		if r == nil {
+			return nil
+		}
+		rounded := math.Round(*r*10) / 10
+		return &rounded
 	}
+	req.MinRating = roundRating(req.MinRating)
+	req.MaxRating = roundRating(req.MaxRating)
+	return req
+}
+
+// GenerateSearchCacheKey returns a deterministic fingerprint of the normalized
+// search request. Structurally identical requests get the same key.
+func GenerateSearchCacheKey(req SearchRequest) string {
+	req = normalizeSearchRequest(req)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "q=%s\x00genres=%s\x00status=%s", req.Query, strings.Join(req.Genres, ","), req.Status)
 	if req.MinRating != nil {
-		key += fmt.Sprintf(":min_rating:%.1f", *req.MinRating)
+		fmt.Fprintf(&b, "\x00min=%.1f", *req.MinRating)
 	}
 	if req.MaxRating != nil {
-		key += fmt.Sprintf(":max_rating:%.1f", *req.MaxRating)
+		fmt.Fprintf(&b, "\x00max=%.1f", *req.MaxRating)
 	}
 	if req.YearFrom != nil {
-		key += fmt.Sprintf(":year_from:%d", *req.YearFrom)
+		fmt.Fprintf(&b, "\x00from=%d", *req.YearFrom)
 	}
 	if req.YearTo != nil {
-		key += fmt.Sprintf(":year_to:%d", *req.YearTo)
+		fmt.Fprintf(&b, "\x00to=%d", *req.YearTo)
 	}
-	key += fmt.Sprintf(":page:%d:limit:%d:sort:%s", req.Page, req.Limit, req.SortBy)
-	return key
+	fmt.Fprintf(&b, "\x00page=%d\x00limit=%d\x00sort=%s", req.Page, req.Limit, req.SortBy)
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// GetPopularManga returns the most active manga within a time window
+func (s *Service) GetPopularManga(ctx context.Context, limit int, period string) (*PopularMangaResponse, error) {
+	return s.GetPopularMangaPage(ctx, 1, limit, period)
 }
 
-// GetPopularManga returns the top-rated manga list with caching support
+// GetPopularMangaPage returns a page of the most active manga within a time window with caching support
 // Step 1: System identifies frequently requested manga
 // Step 4: Subsequent requests serve data from cache
-func (s *Service) GetPopularManga(ctx context.Context, limit int) ([]Manga, error) {
-	if limit <= 0 {
-		limit = 40
+func (s *Service) GetPopularMangaPage(ctx context.Context, page, limit int, period string) (*PopularMangaResponse, error) {
+	page, limit = pagination.Normalize(page, limit, popularLimits)
+	if period == "" {
+		period = PopularPeriodAll
 	}
-	if limit > 50 {
-		limit = 50
+
+	since, err := popularPeriodStart(period, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
 	dbHealthy := s.IsDBHealthy()
 
 	if s.cache != nil {
-		if cached, err := s.cache.GetPopularManga(ctx, limit); err == nil && cached != nil {
+		if cached, err := s.cache.GetPopularManga(ctx, period, page, limit); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	if !dbHealthy || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	popular, total, err := s.repo.GetPopularManga(ctx, since, limit, pagination.Offset(page, limit))
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if popular == nil {
+		popular = []Manga{}
+	}
+
+	pages := int(math.Ceil(float64(total) / float64(limit)))
+	if pages == 0 {
+		pages = 1
+	}
+
+	response := &PopularMangaResponse{
+		Results: popular,
+		Period:  period,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		Pages:   pages,
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetPopularManga(ctx, period, page, limit, response)
+	}
+
+	return response, nil
+}
+
+// GetRecommendations suggests manga from the user's favorite genres that are
+// not in their library yet, ranked by how many of those genres they share,
+// then by rating and views. Library entries include completed and dropped
+// titles, so those are never suggested again. Users without reading history
+// get the all-time popular list instead.
+func (s *Service) GetRecommendations(ctx context.Context, userID int64, limit int) (*RecommendationsResponse, error) {
+	limit = pagination.Limit(limit, recommendedLimits)
+
+	if s.cache != nil {
+		if cached, err := s.cache.GetRecommendations(ctx, userID, limit); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	genres := []string{}
+	if s.genreAffinity != nil {
+		favorites, err := s.genreAffinity.FavoriteGenres(ctx, userID)
+		if err != nil {
+			logging.FromContext(ctx).Warn("manga.Service.GetRecommendations: favorite genres failed", "user_id", userID, "err", err)
+		}
+		for _, g := range favorites {
+			genres = append(genres, g.Genre)
+		}
+	}
+
+	response := &RecommendationsResponse{Genres: genres, Source: RecommendationSourceGenres}
+	if len(genres) > 0 {
+		results, err := s.repo.GetRecommendations(ctx, userID, genres, limit)
+		s.recordRead(err)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		response.Results = results
+	}
+
+	if len(response.Results) == 0 {
+		popular, _, err := s.repo.GetPopularManga(ctx, time.Time{}, limit, 0)
+		s.recordRead(err)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		response.Results = popular
+		response.Source = RecommendationSourcePopular
+	}
+	if response.Results == nil {
+		response.Results = []Manga{}
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetRecommendations(ctx, userID, limit, response)
+	}
+
+	return response, nil
+}
+
+// GetSimilarManga returns manga sharing the most tags with the given manga,
+// for "you might also like" sections
+func (s *Service) GetSimilarManga(ctx context.Context, mangaID int64, limit int) (*SimilarMangaResponse, error) {
+	limit = pagination.Limit(limit, similarLimits)
+
+	if s.cache != nil {
+		if cached, err := s.cache.GetSimilarManga(ctx, mangaID, limit); err == nil && cached != nil {
 			return cached, nil
 		}
 	}
 
-	if !dbHealthy {
+	if !s.IsDBHealthy() || !s.allowRead() {
 		return nil, ErrDatabaseUnavailable
 	}
 
-	popular, err := s.repo.GetPopularManga(ctx, limit)
+	source, err := s.repo.GetByID(ctx, mangaID)
+	s.recordRead(err)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
+	if source == nil {
+		return nil, ErrMangaNotFound
+	}
+
+	results, err := s.repo.GetSimilarManga(ctx, mangaID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if results == nil {
+		results = []Manga{}
+	}
 
+	response := &SimilarMangaResponse{MangaID: mangaID, Results: results}
 	if s.cache != nil {
-		_ = s.cache.SetPopularManga(ctx, limit, popular)
+		_ = s.cache.SetSimilarManga(ctx, mangaID, limit, response)
 	}
 
-	return popular, nil
+	return response, nil
+}
+
+// popularPeriodStart returns the beginning of the activity window for a period.
+// The zero time means no lower bound.
+func popularPeriodStart(period string, now time.Time) (time.Time, error) {
+	switch period {
+	case PopularPeriodDay:
+		return now.Add(-24 * time.Hour), nil
+	case PopularPeriodWeek:
+		return now.AddDate(0, 0, -7), nil
+	case PopularPeriodMonth:
+		return now.AddDate(0, -1, 0), nil
+	case PopularPeriodAll:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, ErrInvalidPeriod
+	}
 }
 
 // GetByID retrieves a manga entity
 func (s *Service) GetByID(ctx context.Context, mangaID int64) (*Manga, error) {
+	if !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
 	manga, err := s.repo.GetByID(ctx, mangaID)
+	s.recordRead(err)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
@@ -212,7 +501,11 @@ func (s *Service) GetByID(ctx context.Context, mangaID int64) (*Manga, error) {
 
 // Exists reports whether a manga exists by ID
 func (s *Service) Exists(ctx context.Context, mangaID int64) (bool, error) {
+	if !s.allowRead() {
+		return false, ErrDatabaseUnavailable
+	}
 	manga, err := s.repo.GetByID(ctx, mangaID)
+	s.recordRead(err)
 	if err != nil {
 		return false, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
@@ -236,7 +529,12 @@ func (s *Service) GetDetails(ctx context.Context, mangaID int64, userID *int64)
 		return nil, ErrDatabaseUnavailable
 	}
 
+	if !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
 	manga, err := s.repo.GetByID(ctx, mangaID)
+	s.recordRead(err)
 	if err != nil {
 		if s.cache != nil {
 			if cached, cacheErr := s.cache.GetMangaDetail(ctx, mangaID); cacheErr == nil && cached != nil {
@@ -285,6 +583,15 @@ func (s *Service) GetByTitle(ctx context.Context, title string) (*Manga, error)
 	return m, nil
 }
 
+// MatchTitle fuzzy-matches a title, e.g. one exported from another site.
+func (s *Service) MatchTitle(ctx context.Context, title string) (*Manga, error) {
+	m, err := s.repo.MatchTitle(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return m, nil
+}
+
 // CreateManga inserts a manga and returns its ID.
 func (s *Service) CreateManga(ctx context.Context, req CreateMangaRequest) (int64, error) {
 	if !s.IsDBHealthy() {
@@ -307,9 +614,58 @@ func (s *Service) CreateManga(ctx context.Context, req CreateMangaRequest) (int6
 		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 
-	if s.cache != nil {
-		_ = s.cache.InvalidateMangaDetail(ctx, id)
-	}
+	s.InvalidateManga(ctx, id)
+	s.invalidatePopular(ctx)
+	s.InvalidateSearch(ctx)
+	s.invalidateTags(ctx)
 
 	return id, nil
 }
+
+// InvalidateManga drops cached data derived from a manga's details or rating
+func (s *Service) InvalidateManga(ctx context.Context, mangaID int64) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateManga(ctx, mangaID); err != nil {
+		logging.FromContext(ctx).Warn("manga.Service.InvalidateManga: cache invalidation failed", "manga_id", mangaID, "err", err)
+	}
+}
+
+// InvalidateSearch drops all cached search results, e.g. after new manga are imported
+func (s *Service) InvalidateSearch(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateSearch(ctx); err != nil {
+		logging.FromContext(ctx).Warn("manga.Service.InvalidateSearch: cache invalidation failed", "err", err)
+	}
+}
+
+func (s *Service) invalidatePopular(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidatePopular(ctx); err != nil {
+		logging.FromContext(ctx).Warn("manga.Service.InvalidatePopular: cache invalidation failed", "err", err)
+		return
+	}
+	s.lastPopularInvalidation.Store(time.Now().UnixNano())
+}
+
+// RatingChanged refreshes caches after a review changed a manga's rating stats.
+// Ratings break ties in the popular ranking, so popular lists are dropped too.
+func (s *Service) RatingChanged(ctx context.Context, mangaID int64) {
+	s.InvalidateManga(ctx, mangaID)
+	s.invalidatePopular(ctx)
+}
+
+// ProgressChanged refreshes popular lists after reading activity on a manga.
+// Invalidation is throttled to popularInvalidationInterval.
+func (s *Service) ProgressChanged(ctx context.Context, mangaID int64) {
+	last := s.lastPopularInvalidation.Load()
+	if last != 0 && time.Since(time.Unix(0, last)) < popularInvalidationInterval {
+		return
+	}
+	s.invalidatePopular(ctx)
+}
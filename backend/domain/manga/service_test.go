@@ -0,0 +1,93 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+)
+
+func TestServiceShortCircuitsReadsWhileBreakerIsOpen(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`INSERT INTO mangas (id, slug, title) VALUES (1, 'berserk', 'Berserk')`); err != nil {
+		t.Fatalf("failed to seed manga: %v", err)
+	}
+
+	svc := NewService(db)
+	breaker := dbpkg.NewCircuitBreaker(2, 50*time.Millisecond)
+	svc.SetCircuitBreaker(breaker)
+	ctx := context.Background()
+
+	// Simulate an outage: queries against the table fail
+	if _, err := db.Exec(`ALTER TABLE mangas RENAME TO mangas_offline`); err != nil {
+		t.Fatalf("failed to simulate outage: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetByID(ctx, 1); !errors.Is(err, ErrDatabaseError) {
+			t.Fatalf("expected database error during outage, got %v", err)
+		}
+	}
+	if breaker.State() != dbpkg.BreakerOpen {
+		t.Fatalf("expected breaker to open after repeated failures, got %s", breaker.State())
+	}
+	if _, err := svc.GetByID(ctx, 1); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("expected ErrDatabaseUnavailable while the breaker is open, got %v", err)
+	}
+	if _, err := svc.Search(ctx, SearchRequest{}); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("expected search to be short-circuited, got %v", err)
+	}
+
+	// Recovery: after the cooldown a probe succeeds and closes the breaker
+	if _, err := db.Exec(`ALTER TABLE mangas_offline RENAME TO mangas`); err != nil {
+		t.Fatalf("failed to end outage: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	m, err := svc.GetByID(ctx, 1)
+	if err != nil || m == nil {
+		t.Fatalf("expected probe to succeed, got manga=%v err=%v", m, err)
+	}
+	if breaker.State() != dbpkg.BreakerClosed {
+		t.Fatalf("expected breaker to close after recovery, got %s", breaker.State())
+	}
+}
+
+func TestServiceReadsFromReplicaAndWritesToPrimary(t *testing.T) {
+	primary := setupTestDB(t)
+	defer primary.Close()
+	primary.SetMaxOpenConns(1)
+	replica := setupTestDB(t)
+	defer replica.Close()
+	replica.SetMaxOpenConns(1)
+
+	if _, err := primary.Exec(`INSERT INTO mangas (id, slug, title) VALUES (1, 'berserk', 'Berserk (primary)')`); err != nil {
+		t.Fatalf("failed to seed primary: %v", err)
+	}
+	if _, err := replica.Exec(`INSERT INTO mangas (id, slug, title) VALUES (1, 'berserk', 'Berserk (replica)')`); err != nil {
+		t.Fatalf("failed to seed replica: %v", err)
+	}
+
+	svc := NewService(primary)
+	svc.SetReadReplica(dbpkg.NewCluster(primary, replica).Reader())
+	ctx := context.Background()
+
+	m, err := svc.GetByID(ctx, 1)
+	if err != nil || m == nil || m.Title != "Berserk (replica)" {
+		t.Fatalf("expected GetByID to read from the replica, got %v (err=%v)", m, err)
+	}
+	results, err := svc.Search(ctx, SearchRequest{})
+	if err != nil || len(results.Results) != 1 || results.Results[0].Title != "Berserk (replica)" {
+		t.Fatalf("expected Search to read from the replica, got %+v (err=%v)", results, err)
+	}
+
+	// Lookups that feed writes, such as duplicate checks on create, stay on the primary
+	m, err = svc.GetByTitle(ctx, "Berserk (primary)")
+	if err != nil || m == nil {
+		t.Fatalf("expected GetByTitle to read from the primary, got %v (err=%v)", m, err)
+	}
+}
@@ -0,0 +1,80 @@
+package manga
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSlugSuffix bounds the search for a free slug
+const maxSlugSuffix = 1000
+
+// fallbackSlug is used for titles without a single letter or digit
+const fallbackSlug = "manga"
+
+// Slugify turns a title into a URL slug: lowercase letters and digits of any
+// script joined by single dashes. Spaces (including the full-width space),
+// underscores, dashes and the katakana middle dot separate words; other
+// punctuation is dropped. Titles that leave nothing, e.g. only symbols, get
+// a generic slug.
+func Slugify(title string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			lastDash = false
+		case unicode.IsMark(r):
+			// Combining marks such as a decomposed dakuten belong to the
+			// preceding letter
+			if b.Len() > 0 && !lastDash {
+				b.WriteRune(r)
+			}
+		case unicode.IsSpace(r) || r == '_' || r == '-' || r == '・' || r == '·':
+			if b.Len() > 0 && !lastDash {
+				b.WriteRune('-')
+				lastDash = true
+			}
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		return fallbackSlug
+	}
+	return slug
+}
+
+// GetBySlug retrieves a manga by slug, including soft-deleted manga
+func (s *Service) GetBySlug(ctx context.Context, slug string) (*Manga, error) {
+	if strings.TrimSpace(slug) == "" {
+		return nil, nil
+	}
+	m, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return m, nil
+}
+
+// UniqueSlug returns slug when no manga uses it, otherwise the first free
+// slug-2, slug-3, ... so different titles that slugify alike can coexist.
+func (s *Service) UniqueSlug(ctx context.Context, slug string) (string, error) {
+	if strings.TrimSpace(slug) == "" {
+		slug = fallbackSlug
+	}
+	candidate := slug
+	for n := 2; n <= maxSlugSuffix; n++ {
+		existing, err := s.GetBySlug(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+		candidate = slug + "-" + strconv.Itoa(n)
+	}
+	return "", fmt.Errorf("%w: no free slug for %q", ErrDatabaseError, slug)
+}
@@ -0,0 +1,59 @@
+package manga
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Seonbi's Borrowed Blade":       "seonbis-borrowed-blade",
+		"  Crimson  Blade -- of_Kyoto ": "crimson-blade-of-kyoto",
+		"進撃の巨人":                         "進撃の巨人",
+		"鋼の錬金術師　ＦＵＬＬＭＥＴＡＬ":              "鋼の錬金術師-ｆｕｌｌｍｅｔａｌ",
+		"ソードアート・オンライン":                  "ソードアート-オンライン",
+		// カ followed by a combining dakuten, as in NFD text
+		"\u30ab\u3099\u30eb": "\u30ab\u3099\u30eb",
+		"Café Noir":          "café-noir",
+		"!!!":                "manga",
+	}
+	for title, want := range cases {
+		if got := Slugify(title); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestUniqueSlugSuffixesCollisions(t *testing.T) {
+	db := setupTestDB(t)
+	seedNovels(t, db)
+	svc := NewService(db)
+	ctx := context.Background()
+
+	// "Hero: Saga" slugifies to the seeded "hero-saga"
+	base := Slugify("Hero: Saga")
+	slug, err := svc.UniqueSlug(ctx, base)
+	if err != nil {
+		t.Fatalf("UniqueSlug failed: %v", err)
+	}
+	if slug != "hero-saga-2" {
+		t.Fatalf("expected hero-saga-2, got %q", slug)
+	}
+
+	// Soft-deleted manga keep their slug reserved
+	if _, err := db.Exec(`INSERT INTO mangas (slug, title, deleted_at) VALUES (?, 'Hero: Saga', CURRENT_TIMESTAMP)`, slug); err != nil {
+		t.Fatalf("failed to insert manga: %v", err)
+	}
+	if slug, err = svc.UniqueSlug(ctx, base); err != nil || slug != "hero-saga-3" {
+		t.Fatalf("expected hero-saga-3, got %q (err %v)", slug, err)
+	}
+
+	if slug, err = svc.UniqueSlug(ctx, "brand-new"); err != nil || slug != "brand-new" {
+		t.Fatalf("expected a free slug to be kept, got %q (err %v)", slug, err)
+	}
+
+	found, err := svc.GetBySlug(ctx, "mystery-tales")
+	if err != nil || found == nil || found.Title != "Mystery Tales" {
+		t.Fatalf("expected GetBySlug to find Mystery Tales, got %+v (err %v)", found, err)
+	}
+}
@@ -0,0 +1,57 @@
+package manga
+
+import (
+	"context"
+	"fmt"
+)
+
+// SoftDeleteManga retires a manga without removing it. The manga disappears
+// from search, details, popular and trending lists, and from users' libraries,
+// while its reviews, library entries and reading progress are kept so
+// RestoreManga can bring everything back.
+func (s *Service) SoftDeleteManga(ctx context.Context, mangaID int64) error {
+	return s.setMangaDeleted(ctx, mangaID, true)
+}
+
+// RestoreManga undoes SoftDeleteManga
+func (s *Service) RestoreManga(ctx context.Context, mangaID int64) error {
+	return s.setMangaDeleted(ctx, mangaID, false)
+}
+
+func (s *Service) setMangaDeleted(ctx context.Context, mangaID int64, deleted bool) error {
+	if !s.IsDBHealthy() {
+		return ErrDatabaseUnavailable
+	}
+
+	changed, err := s.repo.SetDeleted(ctx, mangaID, deleted)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !changed {
+		return ErrMangaNotFound
+	}
+
+	s.InvalidateManga(ctx, mangaID)
+	s.invalidatePopular(ctx)
+	s.InvalidateSearch(ctx)
+	s.invalidateTags(ctx)
+	return nil
+}
+
+// SetDeleted marks a live manga as deleted, or restores a deleted one. It
+// reports false when no manga with that ID is in the opposite state.
+func (r *Repository) SetDeleted(ctx context.Context, mangaID int64, deleted bool) (bool, error) {
+	query := `UPDATE mangas SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`
+	if !deleted {
+		query = `UPDATE mangas SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+	}
+	result, err := r.db.ExecContext(ctx, query, mangaID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,65 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func searchTitles(t *testing.T, service *Service, query string) []string {
+	t.Helper()
+	results, _, err := service.repo.Search(context.Background(), SearchRequest{Query: query, Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	titles := make([]string, 0, len(results))
+	for _, m := range results {
+		titles = append(titles, m.Title)
+	}
+	return titles
+}
+
+func TestSoftDeleteMangaHidesFromSearchUntilRestored(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	service := NewService(db)
+	ctx := context.Background()
+
+	existing, err := service.repo.GetByTitle(ctx, "Hero Saga")
+	if err != nil || existing == nil {
+		t.Fatalf("failed to look up seeded manga: %v", err)
+	}
+
+	if titles := searchTitles(t, service, "hero"); len(titles) != 2 {
+		t.Fatalf("expected 2 results before deleting, got %v", titles)
+	}
+
+	if err := service.SoftDeleteManga(ctx, existing.ID); err != nil {
+		t.Fatalf("SoftDeleteManga failed: %v", err)
+	}
+	titles := searchTitles(t, service, "hero")
+	if len(titles) != 1 || titles[0] != "Action Hero" {
+		t.Fatalf("expected only Action Hero after deleting, got %v", titles)
+	}
+	if m, err := service.GetByID(ctx, existing.ID); err != nil || m != nil {
+		t.Fatalf("expected a deleted manga to have no details, got %+v (err=%v)", m, err)
+	}
+	if err := service.SoftDeleteManga(ctx, existing.ID); !errors.Is(err, ErrMangaNotFound) {
+		t.Fatalf("expected ErrMangaNotFound deleting twice, got %v", err)
+	}
+
+	if err := service.RestoreManga(ctx, existing.ID); err != nil {
+		t.Fatalf("RestoreManga failed: %v", err)
+	}
+	if titles := searchTitles(t, service, "hero"); len(titles) != 2 {
+		t.Fatalf("expected 2 results after restoring, got %v", titles)
+	}
+	if m, err := service.GetByID(ctx, existing.ID); err != nil || m == nil || m.Title != "Hero Saga" {
+		t.Fatalf("expected restored manga details, got %+v (err=%v)", m, err)
+	}
+	if err := service.RestoreManga(ctx, existing.ID); !errors.Is(err, ErrMangaNotFound) {
+		t.Fatalf("expected ErrMangaNotFound restoring a live manga, got %v", err)
+	}
+}
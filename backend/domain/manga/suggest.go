@@ -0,0 +1,136 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+const (
+	// MaxSuggestions caps how many titles a suggestion lookup returns
+	MaxSuggestions = 10
+	// minSuggestQueryLength is the shortest prefix worth looking up; shorter
+	// input matches too much of the catalogue to be useful
+	minSuggestQueryLength = 2
+	// maxSuggestQueryLength bounds the prefix so long input cannot bloat cache keys
+	maxSuggestQueryLength = 100
+)
+
+// Suggest returns up to limit live manga whose title has a word starting
+// with q, most read first. It is meant to be called on every keystroke, so
+// queries shorter than two characters return nothing without touching the
+// database and results are cached per normalized prefix.
+func (s *Service) Suggest(ctx context.Context, q string, limit int) (*SuggestResponse, error) {
+	if limit <= 0 || limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	q = strings.ToLower(strings.Join(strings.Fields(q), " "))
+	if utf8.RuneCountInString(q) > maxSuggestQueryLength {
+		q = string([]rune(q)[:maxSuggestQueryLength])
+	}
+	response := &SuggestResponse{Query: q, Results: []Suggestion{}}
+	if utf8.RuneCountInString(q) < minSuggestQueryLength {
+		return response, nil
+	}
+
+	if s.cache != nil {
+		if cached, err := s.cache.GetSuggestions(ctx, q, limit); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	results, err := s.repo.SuggestTitlesFTS(ctx, q, limit)
+	if errors.Is(err, ErrFTSUnavailable) {
+		results, err = s.repo.SuggestTitles(ctx, q, limit)
+	}
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if results != nil {
+		response.Results = results
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetSuggestions(ctx, q, limit, response)
+	}
+
+	return response, nil
+}
+
+// SuggestTitlesFTS finds titles with a word starting with prefix through the
+// manga_search index, ordered by rating count. It returns ErrFTSUnavailable
+// when the index cannot be used.
+func (r *Repository) SuggestTitlesFTS(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	if r.ftsDisabled.Load() {
+		return nil, ErrFTSUnavailable
+	}
+
+	match := buildFTSQuery(prefix)
+	if match == "" {
+		return nil, nil
+	}
+
+	results, err := r.querySuggestions(ctx, `
+SELECT m.id, m.title
+FROM manga_search fts
+JOIN mangas m ON m.id = fts.rowid
+WHERE manga_search MATCH ?
+  AND m.deleted_at IS NULL
+ORDER BY m.rating_count DESC, m.rating_average DESC, m.id
+LIMIT ?
+`, "title : ("+match+")", limit)
+	if err != nil && isFTSUnavailable(err) {
+		logging.FromContext(ctx).Warn("repository: full-text suggestions unavailable, falling back to LIKE", "err", err)
+		r.ftsDisabled.Store(true)
+		return nil, ErrFTSUnavailable
+	}
+	return results, err
+}
+
+// SuggestTitles is the LIKE fallback for SuggestTitlesFTS. It matches titles
+// that start with prefix or contain it at the start of a later word, so both
+// paths suggest the same manga.
+func (r *Repository) SuggestTitles(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	escaped := escapeLike(prefix)
+	return r.querySuggestions(ctx, `
+SELECT id, title
+FROM mangas
+WHERE (title LIKE ? ESCAPE '\' OR title LIKE ? ESCAPE '\')
+  AND deleted_at IS NULL
+ORDER BY rating_count DESC, rating_average DESC, id
+LIMIT ?
+`, escaped+"%", "% "+escaped+"%", limit)
+}
+
+func (r *Repository) querySuggestions(ctx context.Context, query string, args ...interface{}) ([]Suggestion, error) {
+	rows, err := r.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Suggestion
+	for rows.Next() {
+		var s Suggestion
+		if err := rows.Scan(&s.ID, &s.Title); err != nil {
+			return nil, err
+		}
+		results = append(results, s)
+	}
+	return results, rows.Err()
+}
+
+// escapeLike escapes LIKE wildcards so user input only matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
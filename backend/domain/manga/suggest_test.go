@@ -0,0 +1,90 @@
+package manga
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRepositorySuggestTitles_TwoCharacterPrefixRankedByViews(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fts  bool
+	}{
+		{"like", false},
+		{"fts", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			defer db.Close()
+			if tc.fts {
+				setupFTS(t, db)
+			}
+			seedNovels(t, db)
+
+			// Most viewed of all, but the prefix only starts a later word
+			if _, err := db.Exec(`INSERT INTO mangas (slug, title, rating_count) VALUES ('the-hermit', 'The Hermit', 3000)`); err != nil {
+				t.Fatalf("failed to insert manga: %v", err)
+			}
+			// Deleted manga are never suggested
+			if _, err := db.Exec(`INSERT INTO mangas (slug, title, rating_count, deleted_at) VALUES ('hex', 'Hex', 9000, CURRENT_TIMESTAMP)`); err != nil {
+				t.Fatalf("failed to insert manga: %v", err)
+			}
+
+			repo := NewRepository(db)
+			var (
+				got []Suggestion
+				err error
+			)
+			if tc.fts {
+				got, err = repo.SuggestTitlesFTS(context.Background(), "he", MaxSuggestions)
+			} else {
+				got, err = repo.SuggestTitles(context.Background(), "he", MaxSuggestions)
+			}
+			if err != nil {
+				t.Fatalf("suggest failed: %v", err)
+			}
+
+			want := []string{"The Hermit", "Hero Saga", "Action Hero"}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d suggestions, got %+v", len(want), got)
+			}
+			for i, title := range want {
+				if got[i].Title != title || got[i].ID == 0 {
+					t.Fatalf("suggestion %d: expected %q, got %+v", i, title, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestServiceSuggest_ShortQueryAndLikeWildcards(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	svc := NewService(db)
+
+	short, err := svc.Suggest(context.Background(), " h ", 0)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if short.Query != "h" || len(short.Results) != 0 {
+		t.Fatalf("expected no suggestions for a 1-character query, got %+v", short)
+	}
+
+	wildcard, err := svc.Suggest(context.Background(), "%%", 0)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if len(wildcard.Results) != 0 {
+		t.Fatalf("expected LIKE wildcards to match literally, got %+v", wildcard.Results)
+	}
+
+	mixed, err := svc.Suggest(context.Background(), "MYST", 0)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if len(mixed.Results) != 1 || mixed.Results[0].Title != "Mystery Tales" {
+		t.Fatalf("expected a case-insensitive match on Mystery Tales, got %+v", mixed.Results)
+	}
+}
@@ -0,0 +1,149 @@
+package manga
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
+)
+
+// ErrTagNotFound is returned when browsing a tag that does not exist
+var ErrTagNotFound = errors.New("tag not found")
+
+// ListTags returns every tag carried by at least one manga, with how many
+// manga carry it. The list rarely changes, so it is cached until manga are
+// added or removed.
+func (s *Service) ListTags(ctx context.Context) (*TagListResponse, error) {
+	if s.cache != nil {
+		if cached, err := s.cache.GetTags(ctx); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	tags, err := s.repo.ListTags(ctx)
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if tags == nil {
+		tags = []Tag{}
+	}
+
+	response := &TagListResponse{Tags: tags}
+	if s.cache != nil {
+		_ = s.cache.SetTags(ctx, response)
+	}
+	return response, nil
+}
+
+// GetMangaByTag returns a page of manga carrying the tag, best rated first.
+// Tag names match case-insensitively.
+func (s *Service) GetMangaByTag(ctx context.Context, name string, page, limit int) (*TagMangaResponse, error) {
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrTagNotFound
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	tag, err := s.repo.GetTagName(ctx, name)
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if tag == "" {
+		return nil, ErrTagNotFound
+	}
+
+	results, total, err := s.repo.GetMangaByTag(ctx, tag, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if results == nil {
+		results = []Manga{}
+	}
+
+	pages := int(math.Ceil(float64(total) / float64(limit)))
+	if pages == 0 {
+		pages = 1
+	}
+
+	return &TagMangaResponse{
+		Tag:     tag,
+		Results: results,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		Pages:   pages,
+	}, nil
+}
+
+// invalidateTags drops the cached tag list after manga are added or removed
+func (s *Service) invalidateTags(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateTags(ctx); err != nil {
+		logging.FromContext(ctx).Warn("manga.Service.invalidateTags: cache invalidation failed", "err", err)
+	}
+}
+
+// ListTags counts the live manga carrying each tag, ordered by name.
+// Tags only used by soft-deleted manga are left out.
+func (r *Repository) ListTags(ctx context.Context) ([]Tag, error) {
+	rows, err := r.reader.QueryContext(ctx, `
+SELECT t.name, COUNT(DISTINCT m.id) AS manga_count
+FROM tags t
+JOIN manga_tags mt ON mt.tag_id = t.id
+JOIN mangas m ON m.id = mt.manga_id
+WHERE m.deleted_at IS NULL
+GROUP BY t.id, t.name
+ORDER BY t.name
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.Name, &tag.MangaCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetTagName returns the stored spelling of a tag matched case-insensitively,
+// or "" when no such tag exists
+func (r *Repository) GetTagName(ctx context.Context, name string) (string, error) {
+	var stored string
+	err := r.reader.QueryRowContext(ctx, `SELECT name FROM tags WHERE LOWER(name) = LOWER(?) ORDER BY id LIMIT 1`, name).Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return stored, err
+}
+
+// GetMangaByTag returns a page of live manga carrying the tag, ordered by
+// weighted rating, along with the total number of such manga
+func (r *Repository) GetMangaByTag(ctx context.Context, tag string, page, limit int) ([]Manga, int, error) {
+	req := SearchRequest{Genres: []string{tag}, Page: page, Limit: limit}
+	conditions, args := searchFilters(req)
+	return r.runSearch(ctx, req, "0 AS relevance", "", conditions, args, "weighted_rating DESC, m.rating_count DESC, m.id")
+}
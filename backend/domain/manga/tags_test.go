@@ -0,0 +1,92 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListTags_CountsLiveManga(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	service := NewService(db)
+	ctx := context.Background()
+
+	resp, err := service.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want := []Tag{{"Action", 2}, {"Fantasy", 1}, {"Mystery", 1}, {"Thriller", 1}}
+	if len(resp.Tags) != len(want) {
+		t.Fatalf("expected %d tags, got %+v", len(want), resp.Tags)
+	}
+	for i, tag := range want {
+		if resp.Tags[i] != tag {
+			t.Fatalf("tag %d: expected %+v, got %+v", i, tag, resp.Tags[i])
+		}
+	}
+
+	// Soft-deleted manga no longer count towards their tags
+	hero, err := service.repo.GetByTitle(ctx, "Hero Saga")
+	if err != nil || hero == nil {
+		t.Fatalf("failed to look up seeded manga: %v", err)
+	}
+	if err := service.SoftDeleteManga(ctx, hero.ID); err != nil {
+		t.Fatalf("SoftDeleteManga failed: %v", err)
+	}
+	resp, err = service.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want = []Tag{{"Action", 1}, {"Mystery", 1}, {"Thriller", 1}}
+	if len(resp.Tags) != len(want) {
+		t.Fatalf("expected %d tags after deleting, got %+v", len(want), resp.Tags)
+	}
+	for i, tag := range want {
+		if resp.Tags[i] != tag {
+			t.Fatalf("tag %d after deleting: expected %+v, got %+v", i, tag, resp.Tags[i])
+		}
+	}
+}
+
+func TestGetMangaByTag_PaginatesByRating(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedNovels(t, db)
+
+	service := NewService(db)
+	ctx := context.Background()
+
+	first, err := service.GetMangaByTag(ctx, "action", 1, 1)
+	if err != nil {
+		t.Fatalf("GetMangaByTag page 1 failed: %v", err)
+	}
+	if first.Tag != "Action" || first.Total != 2 || first.Pages != 2 {
+		t.Fatalf("unexpected page metadata: tag=%q total=%d pages=%d", first.Tag, first.Total, first.Pages)
+	}
+	if len(first.Results) != 1 || first.Results[0].Title != "Hero Saga" {
+		t.Fatalf("expected Hero Saga first, got %+v", first.Results)
+	}
+
+	second, err := service.GetMangaByTag(ctx, "Action", 2, 1)
+	if err != nil {
+		t.Fatalf("GetMangaByTag page 2 failed: %v", err)
+	}
+	if len(second.Results) != 1 || second.Results[0].Title != "Action Hero" {
+		t.Fatalf("expected Action Hero on page 2, got %+v", second.Results)
+	}
+
+	past, err := service.GetMangaByTag(ctx, "Action", 3, 1)
+	if err != nil {
+		t.Fatalf("GetMangaByTag page 3 failed: %v", err)
+	}
+	if len(past.Results) != 0 || past.Total != 2 {
+		t.Fatalf("expected an empty page past the end, got %+v", past)
+	}
+
+	if _, err := service.GetMangaByTag(ctx, "Romance", 1, 10); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("expected ErrTagNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package manga
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
+)
+
+// ErrInvalidTrendingWindow is returned for windows other than day, week or month
+var ErrInvalidTrendingWindow = errors.New("window must be one of: day, week, month")
+
+// trendingMinReaders is how many distinct readers a manga needs inside the
+// window before it can trend; fewer readers are treated as noise
+const trendingMinReaders = 3
+
+// GetTrending returns the manga gaining readers fastest right now. Manga are
+// ranked by the distinct users who advanced their progress within the rolling
+// window, each reader weighted by how recently they read, so a sudden surge
+// outranks a title with a steady trickle of readers. Results are cached per
+// window.
+func (s *Service) GetTrending(ctx context.Context, limit int, window string) (*TrendingMangaResponse, error) {
+	limit = pagination.Limit(limit, trendingLimits)
+	if window == "" {
+		window = PopularPeriodWeek
+	}
+	if window == PopularPeriodAll {
+		return nil, ErrInvalidTrendingWindow
+	}
+
+	now := time.Now()
+	since, err := popularPeriodStart(window, now)
+	if err != nil {
+		return nil, ErrInvalidTrendingWindow
+	}
+
+	if s.cache != nil {
+		if cached, err := s.cache.GetTrendingManga(ctx, window, limit); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	if !s.IsDBHealthy() || !s.allowRead() {
+		return nil, ErrDatabaseUnavailable
+	}
+
+	results, err := s.repo.GetTrendingManga(ctx, since, now, trendingMinReaders, limit)
+	s.recordRead(err)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if results == nil {
+		results = []TrendingManga{}
+	}
+
+	response := &TrendingMangaResponse{Results: results, Window: window, Limit: limit}
+	if s.cache != nil {
+		_ = s.cache.SetTrendingManga(ctx, window, limit, response)
+	}
+
+	return response, nil
+}
+
+// GetTrendingManga ranks manga by recency-weighted distinct readers in
+// Progress_History since the given time. Each reader counts once, at the time
+// of their latest progress, with a weight falling linearly from 1 at now to 0
+// at since. Conflicting (rejected) updates are ignored and manga with fewer
+// than minReaders readers are left out.
+func (r *Repository) GetTrendingManga(ctx context.Context, since, now time.Time, minReaders, limit int) ([]TrendingManga, error) {
+	windowDays := now.Sub(since).Hours() / 24
+	if windowDays <= 0 {
+		return nil, nil
+	}
+
+	// Timestamps are stored as "YYYY-MM-DD HH:MM:SS" UTC text, so compare in the same format
+	sinceStr := since.UTC().Format("2006-01-02 15:04:05")
+	nowStr := now.UTC().Format("2006-01-02 15:04:05")
+
+	query := `
+SELECT
+    m.id,
+    m.slug,
+    m.title,
+    m.alt_title,
+    m.author,
+    m.artist,
+    m.status,
+    m.synopsis,
+    m.cover_url,
+    m.rating_average,
+    m.views,
+    t.readers,
+    t.score
+FROM (
+    SELECT Manga_Id,
+           COUNT(*) AS readers,
+           SUM(MAX(0.0, 1.0 - (julianday(?) - julianday(Last_Read_At)) / ?)) AS score
+    FROM (
+        SELECT Manga_Id, User_Id, MAX(Created_At) AS Last_Read_At
+        FROM Progress_History
+        WHERE Created_At >= ? AND Is_Conflict = 0
+        GROUP BY Manga_Id, User_Id
+    )
+    GROUP BY Manga_Id
+    HAVING COUNT(*) >= ?
+) t
+JOIN mangas m ON m.id = t.Manga_Id
+WHERE m.deleted_at IS NULL
+ORDER BY t.score DESC, t.readers DESC, m.id
+LIMIT ?
+`
+	rows, err := r.reader.QueryContext(ctx, query, nowStr, windowDays, sinceStr, minReaders, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("repository: GetTrendingManga query failed", "since", sinceStr, "limit", limit, "err", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []TrendingManga
+	for rows.Next() {
+		var (
+			t      TrendingManga
+			alt    sql.NullString
+			author sql.NullString
+			artist sql.NullString
+			desc   sql.NullString
+			image  sql.NullString
+			count  sql.NullInt64
+		)
+		if err := rows.Scan(
+			&t.ID,
+			&t.Slug,
+			&t.Title,
+			&alt,
+			&author,
+			&artist,
+			&t.Status,
+			&desc,
+			&image,
+			&t.RatingPoint,
+			&count,
+			&t.Readers,
+			&t.Score,
+		); err != nil {
+			logging.FromContext(ctx).Error("repository: GetTrendingManga scan failed", "manga_id", t.ID, "err", err)
+			return nil, err
+		}
+		t.Name = t.Title
+		t.Score = roundRating(t.Score)
+		t.Author = author.String
+		t.Artist = artist.String
+		t.Description = desc.String
+		t.Image = image.String
+		if count.Valid {
+			t.Views = count.Int64
+		}
+		if alt.Valid && t.Slug == "" {
+			t.Slug = alt.String
+		}
+		results = append(results, t)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,85 @@
+package manga
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestGetTrending_SurgeOutranksEvergreen(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	migration, err := os.ReadFile("../../db/migrations/023_progress_history.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+
+	seed := `
+    INSERT INTO mangas (id, slug, title, rating_average, rating_count) VALUES
+        (1, 'evergreen', 'Evergreen', 4.9, 900),
+        (2, 'surging', 'Surging', 3.5, 20),
+        (3, 'niche', 'Niche', 4.0, 5);
+
+    -- Evergreen has more readers, spread evenly across the week and before it
+    INSERT INTO Progress_History (User_Id, Manga_Id, Requested_Chapter, Applied_Chapter, Created_At) VALUES
+        (1, 1, 10, 10, datetime('now', '-30 days')),
+        (1, 1, 11, 11, datetime('now', '-6 days')),
+        (2, 1, 4, 4, datetime('now', '-5 days')),
+        (3, 1, 7, 7, datetime('now', '-4 days')),
+        (4, 1, 2, 2, datetime('now', '-3 days')),
+        (5, 1, 9, 9, datetime('now', '-2 days'));
+
+    -- Surging picked up three readers in the last few hours
+    INSERT INTO Progress_History (User_Id, Manga_Id, Requested_Chapter, Applied_Chapter, Created_At) VALUES
+        (6, 2, 1, 1, datetime('now', '-3 hours')),
+        (6, 2, 2, 2, datetime('now', '-2 hours')),
+        (7, 2, 1, 1, datetime('now', '-2 hours')),
+        (8, 2, 1, 1, datetime('now', '-1 hours'));
+
+    -- Niche is recent but has too few readers, and a rejected update never counts
+    INSERT INTO Progress_History (User_Id, Manga_Id, Requested_Chapter, Applied_Chapter, Is_Conflict, Created_At) VALUES
+        (1, 3, 5, 5, 0, datetime('now', '-1 hours')),
+        (2, 3, 5, 5, 0, datetime('now', '-1 hours')),
+        (3, 3, 1, 5, 1, datetime('now', '-1 hours'));
+    `
+	if _, err := db.Exec(seed); err != nil {
+		t.Fatalf("failed to seed progress: %v", err)
+	}
+
+	service := NewService(db)
+	ctx := context.Background()
+
+	week, err := service.GetTrending(ctx, 10, PopularPeriodWeek)
+	if err != nil {
+		t.Fatalf("GetTrending(week) failed: %v", err)
+	}
+	if len(week.Results) != 2 {
+		t.Fatalf("expected 2 trending manga, got %+v", week.Results)
+	}
+	if week.Results[0].Title != "Surging" || week.Results[1].Title != "Evergreen" {
+		t.Fatalf("expected Surging above Evergreen, got %q then %q", week.Results[0].Title, week.Results[1].Title)
+	}
+	if week.Results[0].Readers != 3 || week.Results[1].Readers != 5 {
+		t.Fatalf("unexpected reader counts: %d and %d", week.Results[0].Readers, week.Results[1].Readers)
+	}
+	if week.Results[0].Score <= week.Results[1].Score {
+		t.Fatalf("expected a higher score for Surging, got %.2f vs %.2f", week.Results[0].Score, week.Results[1].Score)
+	}
+
+	// Only the surge happened within the last day
+	day, err := service.GetTrending(ctx, 10, PopularPeriodDay)
+	if err != nil {
+		t.Fatalf("GetTrending(day) failed: %v", err)
+	}
+	if len(day.Results) != 1 || day.Results[0].Title != "Surging" {
+		t.Fatalf("expected only Surging for the day window, got %+v", day.Results)
+	}
+
+	if _, err := service.GetTrending(ctx, 10, PopularPeriodAll); err != ErrInvalidTrendingWindow {
+		t.Fatalf("expected ErrInvalidTrendingWindow, got %v", err)
+	}
+}
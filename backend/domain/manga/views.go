@@ -0,0 +1,163 @@
+package manga
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+// DefaultViewWindow is how long repeated views by one viewer count once
+const DefaultViewWindow = 30 * time.Minute
+
+// ViewBuffer collects manga views between flushes to the database
+type ViewBuffer interface {
+	// RecordView counts a view unless the viewer already viewed the manga
+	// within window, and reports whether it was counted
+	RecordView(ctx context.Context, mangaID int64, viewer string, window time.Duration) (bool, error)
+	// DrainViews returns and clears the views buffered since the last drain
+	DrainViews(ctx context.Context) (map[int64]int64, error)
+}
+
+// SetViewBuffer buffers views in a shared store such as Redis. Without one,
+// or while it fails, views are buffered in memory.
+func (s *Service) SetViewBuffer(buffer ViewBuffer) {
+	s.views = buffer
+}
+
+// RecordView counts a view of the manga by viewer, e.g. "user:7" or an IP
+// address. Repeated views within DefaultViewWindow count once. The count
+// reaches the database on the next FlushViews.
+func (s *Service) RecordView(ctx context.Context, mangaID int64, viewer string) bool {
+	if s.views != nil {
+		counted, err := s.views.RecordView(ctx, mangaID, viewer, DefaultViewWindow)
+		if err == nil {
+			return counted
+		}
+		logging.FromContext(ctx).Warn("manga: buffering view in memory", "manga_id", mangaID, "err", err)
+	}
+	counted, _ := s.localViews.RecordView(ctx, mangaID, viewer, DefaultViewWindow)
+	return counted
+}
+
+// FlushViews adds the buffered views to the mangas table. Counts that cannot
+// be written are kept in memory for the next flush.
+func (s *Service) FlushViews(ctx context.Context) error {
+	counts, _ := s.localViews.DrainViews(ctx)
+	if s.views != nil {
+		shared, err := s.views.DrainViews(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Warn("manga: draining buffered views failed", "err", err)
+		}
+		for mangaID, n := range shared {
+			counts[mangaID] += n
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	if err := s.repo.AddViews(ctx, counts); err != nil {
+		s.localViews.restore(counts)
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return nil
+}
+
+// StartViewFlusher flushes buffered views every interval until ctx is
+// cancelled. Servers call FlushViews once more after they stop taking
+// requests so a shutdown does not lose views.
+func (s *Service) StartViewFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.FlushViews(ctx); err != nil {
+				logging.FromContext(ctx).Error("manga: view flush failed", "err", err)
+			}
+		}
+	}
+}
+
+// AddViews increments the view count of each manga in one transaction
+func (r *Repository) AddViews(ctx context.Context, counts map[int64]int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE mangas SET views = views + ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for mangaID, n := range counts {
+		if _, err := stmt.ExecContext(ctx, n, mangaID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// memoryViewBuffer is the in-process ViewBuffer
+type memoryViewBuffer struct {
+	mu     sync.Mutex
+	counts map[int64]int64
+	// seen holds when each manga/viewer pair stops being debounced
+	seen map[string]time.Time
+}
+
+func newMemoryViewBuffer() *memoryViewBuffer {
+	return &memoryViewBuffer{
+		counts: make(map[int64]int64),
+		seen:   make(map[string]time.Time),
+	}
+}
+
+func (b *memoryViewBuffer) RecordView(ctx context.Context, mangaID int64, viewer string, window time.Duration) (bool, error) {
+	key := strconv.FormatInt(mangaID, 10) + ":" + viewer
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until, ok := b.seen[key]; ok && now.Before(until) {
+		return false, nil
+	}
+	b.seen[key] = now.Add(window)
+	b.counts[mangaID]++
+	return true, nil
+}
+
+func (b *memoryViewBuffer) DrainViews(ctx context.Context) (map[int64]int64, error) {
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts := b.counts
+	b.counts = make(map[int64]int64)
+	// Draining is periodic, so it also bounds the debounce map
+	for key, until := range b.seen {
+		if !now.Before(until) {
+			delete(b.seen, key)
+		}
+	}
+	return counts, nil
+}
+
+// restore puts back counts that could not be written
+func (b *memoryViewBuffer) restore(counts map[int64]int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for mangaID, n := range counts {
+		b.counts[mangaID] += n
+	}
+}
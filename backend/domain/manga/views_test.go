@@ -0,0 +1,92 @@
+package manga
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingViewBuffer stands in for Redis while it is down
+type failingViewBuffer struct{}
+
+func (failingViewBuffer) RecordView(ctx context.Context, mangaID int64, viewer string, window time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (failingViewBuffer) DrainViews(ctx context.Context) (map[int64]int64, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRepeatedViewsBySameViewerCountOnce(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	if _, err := db.Exec(`INSERT INTO mangas (id, slug, title, views) VALUES (1, 'berserk', 'Berserk', 10)`); err != nil {
+		t.Fatalf("failed to seed manga: %v", err)
+	}
+
+	svc := NewService(db)
+	ctx := context.Background()
+
+	if !svc.RecordView(ctx, 1, "user:7") {
+		t.Fatal("expected the first view to count")
+	}
+	for i := 0; i < 3; i++ {
+		if svc.RecordView(ctx, 1, "user:7") {
+			t.Fatal("expected a repeat view within the window to be ignored")
+		}
+	}
+	if !svc.RecordView(ctx, 1, "ip:203.0.113.5") {
+		t.Fatal("expected another viewer's view to count")
+	}
+
+	// Views only reach the database when flushed
+	m, err := svc.repo.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if m.Views != 10 {
+		t.Fatalf("expected views to be buffered until a flush, got %d", m.Views)
+	}
+
+	if err := svc.FlushViews(ctx); err != nil {
+		t.Fatalf("FlushViews returned error: %v", err)
+	}
+	if m, err = svc.repo.GetByID(ctx, 1); err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if m.Views != 12 {
+		t.Fatalf("expected 12 views after the flush, got %d", m.Views)
+	}
+
+	// The buffer was drained, so flushing again adds nothing
+	if err := svc.FlushViews(ctx); err != nil {
+		t.Fatalf("FlushViews returned error: %v", err)
+	}
+	if m, err = svc.repo.GetByID(ctx, 1); err != nil || m.Views != 12 {
+		t.Fatalf("expected views to stay at 12, got %+v (err %v)", m, err)
+	}
+}
+
+func TestViewsAreBufferedInMemoryWhileSharedBufferFails(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	if _, err := db.Exec(`INSERT INTO mangas (id, slug, title) VALUES (1, 'berserk', 'Berserk')`); err != nil {
+		t.Fatalf("failed to seed manga: %v", err)
+	}
+
+	svc := NewService(db)
+	svc.SetViewBuffer(failingViewBuffer{})
+	ctx := context.Background()
+
+	if !svc.RecordView(ctx, 1, "user:7") || svc.RecordView(ctx, 1, "user:7") {
+		t.Fatal("expected the in-memory buffer to count the first view only")
+	}
+	if err := svc.FlushViews(ctx); err != nil {
+		t.Fatalf("FlushViews returned error: %v", err)
+	}
+	m, err := svc.repo.GetByID(ctx, 1)
+	if err != nil || m.Views != 1 {
+		t.Fatalf("expected 1 view, got %+v (err %v)", m, err)
+	}
+}
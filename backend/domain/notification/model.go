@@ -0,0 +1,54 @@
+package notification
+
+import "time"
+
+// TypeChapterRelease marks a notification about a newly released chapter
+const TypeChapterRelease = "chapter_release"
+
+// TypeGoalCompleted marks a notification about a completed reading goal
+const TypeGoalCompleted = "goal_completed"
+
+// Notification is a durable record of something pushed to a user
+type Notification struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	Type      string     `json:"type"`
+	MangaID   *int64     `json:"manga_id,omitempty"`
+	Chapter   *int       `json:"chapter,omitempty"`
+	ChapterID *int64     `json:"chapter_id,omitempty"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	Read      bool       `json:"read"`
+}
+
+// ListResponse is a page of a user's notifications
+type ListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Total         int            `json:"total"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	Pages         int            `json:"pages"`
+	UnreadCount   int            `json:"unread_count"`
+}
+
+// MarkReadResponse reports the result of marking notifications read
+type MarkReadResponse struct {
+	Marked      int `json:"marked"`
+	UnreadCount int `json:"unread_count"`
+}
+
+// Subscription is a user's request to be notified about a manga. A nil
+// MangaID means every manga, as registered by UDP clients with all_novels.
+type Subscription struct {
+	MangaID    *int64    `json:"manga_id,omitempty"`
+	MangaTitle string    `json:"manga_title,omitempty"`
+	AllManga   bool      `json:"all_manga,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SubscriptionsResponse lists a user's active subscriptions
+type SubscriptionsResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Total         int            `json:"total"`
+}
@@ -0,0 +1,264 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Repository persists notifications
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository builds a notification repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// LibraryUserIDs returns the users who have the manga in their library
+func (r *Repository) LibraryUserIDs(ctx context.Context, mangaID int64) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT user_id FROM libraries WHERE manga_id = ?`, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SubscriberUserIDs returns users with an active subscription to the manga or to all manga
+func (r *Repository) SubscriberUserIDs(ctx context.Context, mangaID int64) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT DISTINCT user_id FROM notification_subscriptions
+        WHERE is_active = 1 AND (manga_id = ? OR manga_id IS NULL)
+    `, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Subscribe activates the user's subscription to a manga. It reports false
+// when an active subscription already existed.
+func (r *Repository) Subscribe(ctx context.Context, userID, mangaID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE notification_subscriptions SET is_active = 1
+        WHERE user_id = ? AND manga_id = ? AND is_active = 0
+    `, userID, mangaID)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := res.RowsAffected(); rows > 0 {
+		return true, nil
+	}
+
+	res, err = r.db.ExecContext(ctx, `
+        INSERT INTO notification_subscriptions (user_id, manga_id, is_active)
+        SELECT ?, ?, 1
+        WHERE NOT EXISTS (
+            SELECT 1 FROM notification_subscriptions WHERE user_id = ? AND manga_id = ?
+        )
+    `, userID, mangaID, userID, mangaID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+// Unsubscribe removes the user's subscription to a manga. It reports false
+// when there was no active subscription.
+func (r *Repository) Unsubscribe(ctx context.Context, userID, mangaID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        DELETE FROM notification_subscriptions WHERE user_id = ? AND manga_id = ? AND is_active = 1
+    `, userID, mangaID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+// ListSubscriptions returns the user's active subscriptions, newest first
+func (r *Repository) ListSubscriptions(ctx context.Context, userID int64) ([]Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT ns.manga_id, COALESCE(m.title, ''), ns.created_at
+        FROM notification_subscriptions ns
+        LEFT JOIN mangas m ON m.id = ns.manga_id
+        WHERE ns.user_id = ? AND ns.is_active = 1
+        ORDER BY ns.created_at DESC, ns.id DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []Subscription{}
+	for rows.Next() {
+		var (
+			sub     Subscription
+			mangaID sql.NullInt64
+		)
+		if err := rows.Scan(&mangaID, &sub.MangaTitle, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		if mangaID.Valid {
+			sub.MangaID = &mangaID.Int64
+		} else {
+			sub.AllManga = true
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, rows.Err()
+}
+
+// CreateForUsers stores one copy of n per user in a single transaction
+func (r *Repository) CreateForUsers(ctx context.Context, n Notification, userIDs []int64) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+        INSERT INTO Notifications (User_Id, Type, Manga_Id, Chapter, Chapter_Id, Message)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, userID := range userIDs {
+		if _, err = stmt.ExecContext(ctx, userID, n.Type, n.MangaID, n.Chapter, n.ChapterID, n.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns a page of the user's notifications, newest first, and the total matching
+func (r *Repository) List(ctx context.Context, userID int64, unreadOnly bool, limit, offset int) ([]Notification, int, error) {
+	where := []string{"User_Id = ?"}
+	if unreadOnly {
+		where = append(where, "Read_At IS NULL")
+	}
+	clause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM Notifications WHERE `+clause, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT Notification_Id, User_Id, Type, Manga_Id, Chapter, Chapter_Id, Message, Created_At, Read_At
+        FROM Notifications
+        WHERE `+clause+`
+        ORDER BY Created_At DESC, Notification_Id DESC
+        LIMIT ? OFFSET ?
+    `, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var (
+			n         Notification
+			mangaID   sql.NullInt64
+			chapter   sql.NullInt64
+			chapterID sql.NullInt64
+			readAt    sql.NullTime
+		)
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &mangaID, &chapter, &chapterID, &n.Message, &n.CreatedAt, &readAt); err != nil {
+			return nil, 0, err
+		}
+		if mangaID.Valid {
+			n.MangaID = &mangaID.Int64
+		}
+		if chapter.Valid {
+			c := int(chapter.Int64)
+			n.Chapter = &c
+		}
+		if chapterID.Valid {
+			n.ChapterID = &chapterID.Int64
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+			n.Read = true
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, total, rows.Err()
+}
+
+// CountUnread returns how many of the user's notifications are unread
+func (r *Repository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM Notifications WHERE User_Id = ? AND Read_At IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// Exists reports whether the notification belongs to the user
+func (r *Repository) Exists(ctx context.Context, userID, notificationID int64) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM Notifications WHERE Notification_Id = ? AND User_Id = ?
+    `, notificationID, userID).Scan(&count)
+	return count > 0, err
+}
+
+// MarkRead marks one unread notification read and returns the rows changed
+func (r *Repository) MarkRead(ctx context.Context, userID, notificationID int64, at time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE Notifications SET Read_At = ?
+        WHERE Notification_Id = ? AND User_Id = ? AND Read_At IS NULL
+    `, at.UTC().Format(timeFormat), notificationID, userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
+
+// MarkAllRead marks every unread notification of the user read
+func (r *Repository) MarkAllRead(ctx context.Context, userID int64, at time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE Notifications SET Read_At = ? WHERE User_Id = ? AND Read_At IS NULL
+    `, at.UTC().Format(timeFormat), userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
+
+// timeFormat matches CURRENT_TIMESTAMP so stored times sort with Created_At
+const timeFormat = "2006-01-02 15:04:05"
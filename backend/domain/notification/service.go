@@ -0,0 +1,202 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrMangaNotFound        = errors.New("manga not found")
+	ErrAlreadySubscribed    = errors.New("already subscribed to this manga")
+	ErrNotSubscribed        = errors.New("not subscribed to this manga")
+	ErrDatabaseError        = errors.New("database error")
+)
+
+// MangaChecker verifies manga existence
+type MangaChecker interface {
+	Exists(ctx context.Context, mangaID int64) (bool, error)
+}
+
+// Service manages durable notifications and subscriptions
+type Service struct {
+	repo         *Repository
+	mangaChecker MangaChecker
+}
+
+// NewService builds a notification service
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// SetMangaChecker enables manga existence checks when subscribing
+func (s *Service) SetMangaChecker(checker MangaChecker) {
+	s.mangaChecker = checker
+}
+
+// Subscribe subscribes the user to chapter notifications for a manga
+func (s *Service) Subscribe(ctx context.Context, userID, mangaID int64) error {
+	if s.mangaChecker != nil {
+		exists, err := s.mangaChecker.Exists(ctx, mangaID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		if !exists {
+			return ErrMangaNotFound
+		}
+	}
+
+	created, err := s.repo.Subscribe(ctx, userID, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !created {
+		return ErrAlreadySubscribed
+	}
+	return nil
+}
+
+// Unsubscribe removes the user's subscription to a manga
+func (s *Service) Unsubscribe(ctx context.Context, userID, mangaID int64) error {
+	removed, err := s.repo.Unsubscribe(ctx, userID, mangaID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !removed {
+		return ErrNotSubscribed
+	}
+	return nil
+}
+
+// ListSubscriptions returns the user's active subscriptions
+func (s *Service) ListSubscriptions(ctx context.Context, userID int64) (*SubscriptionsResponse, error) {
+	subscriptions, err := s.repo.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &SubscriptionsResponse{Subscriptions: subscriptions, Total: len(subscriptions)}, nil
+}
+
+// RecordChapterRelease stores a chapter-release notification for everyone who
+// follows the manga: readers with it in their library, subscribers, and
+// liveUserIDs, the users the live UDP push went to. It returns how many users
+// were recorded.
+func (s *Service) RecordChapterRelease(ctx context.Context, mangaID int64, mangaName string, chapter int, chapterID int64, liveUserIDs []int64) (int, error) {
+	libraryUsers, err := s.repo.LibraryUserIDs(ctx, mangaID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	subscribers, err := s.repo.SubscriberUserIDs(ctx, mangaID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	seen := make(map[int64]bool, len(libraryUsers)+len(subscribers)+len(liveUserIDs))
+	var recipients []int64
+	for _, ids := range [][]int64{libraryUsers, subscribers, liveUserIDs} {
+		for _, id := range ids {
+			if id > 0 && !seen[id] {
+				seen[id] = true
+				recipients = append(recipients, id)
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	n := Notification{
+		Type:    TypeChapterRelease,
+		MangaID: &mangaID,
+		Chapter: &chapter,
+		Message: fmt.Sprintf("%s chapter %d is out", mangaName, chapter),
+	}
+	if chapterID > 0 {
+		n.ChapterID = &chapterID
+	}
+	if err := s.repo.CreateForUsers(ctx, n, recipients); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return len(recipients), nil
+}
+
+// GoalCompleted stores a notification that the user completed a reading goal
+func (s *Service) GoalCompleted(ctx context.Context, goal *history.ReadingGoal) error {
+	n := Notification{
+		Type:    TypeGoalCompleted,
+		Message: goal.CompletionMessage(),
+	}
+	if err := s.repo.CreateForUsers(ctx, n, []int64{goal.UserID}); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return nil
+}
+
+// List returns a page of the user's notifications with their unread count
+func (s *Service) List(ctx context.Context, userID int64, unreadOnly bool, page, limit int) (*ListResponse, error) {
+	page, limit = pagination.Normalize(page, limit, pagination.Default())
+
+	notifications, total, err := s.repo.List(ctx, userID, unreadOnly, limit, pagination.Offset(page, limit))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+
+	pages := int(math.Ceil(float64(total) / float64(limit)))
+	if pages == 0 {
+		pages = 1
+	}
+	return &ListResponse{
+		Notifications: notifications,
+		Total:         total,
+		Page:          page,
+		Limit:         limit,
+		Pages:         pages,
+		UnreadCount:   unread,
+	}, nil
+}
+
+// MarkRead marks one of the user's notifications read. Marking an already
+// read notification is not an error.
+func (s *Service) MarkRead(ctx context.Context, userID, notificationID int64) (*MarkReadResponse, error) {
+	exists, err := s.repo.Exists(ctx, userID, notificationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !exists {
+		return nil, ErrNotificationNotFound
+	}
+
+	marked, err := s.repo.MarkRead(ctx, userID, notificationID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.markReadResponse(ctx, userID, marked)
+}
+
+// MarkAllRead marks every unread notification of the user read
+func (s *Service) MarkAllRead(ctx context.Context, userID int64) (*MarkReadResponse, error) {
+	marked, err := s.repo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.markReadResponse(ctx, userID, marked)
+}
+
+func (s *Service) markReadResponse(ctx context.Context, userID int64, marked int) (*MarkReadResponse, error) {
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &MarkReadResponse{Marked: marked, UnreadCount: unread}, nil
+}
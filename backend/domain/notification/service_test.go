@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE mangas (id INTEGER PRIMARY KEY, title TEXT NOT NULL);
+    INSERT INTO mangas (id, title) VALUES (10, 'Berserk'), (20, 'Vagabond');
+    CREATE TABLE libraries (user_id INTEGER NOT NULL, manga_id INTEGER NOT NULL);
+    INSERT INTO libraries (user_id, manga_id) VALUES (1, 10), (2, 10), (3, 20);
+    `
+	for _, name := range []string{"021_notifications.sql", "022_notification_subscriptions.sql"} {
+		migration, err := os.ReadFile("../../db/migrations/" + name)
+		if err != nil {
+			t.Fatalf("failed to read migration %s: %v", name, err)
+		}
+		schema += string(migration)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+type fakeMangaChecker map[int64]bool
+
+func (f fakeMangaChecker) Exists(_ context.Context, mangaID int64) (bool, error) {
+	return f[mangaID], nil
+}
+
+func TestRecordChapterReleaseReachesLibraryAndLiveUsers(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	recorded, err := svc.RecordChapterRelease(ctx, 10, "Berserk", 5, 500, []int64{2, 4})
+	if err != nil {
+		t.Fatalf("RecordChapterRelease returned error: %v", err)
+	}
+	if recorded != 3 {
+		t.Fatalf("expected users 1, 2 and 4 to be recorded once each, got %d", recorded)
+	}
+
+	for userID, want := range map[int64]int{1: 1, 2: 1, 3: 0, 4: 1} {
+		resp, err := svc.List(ctx, userID, false, 1, 20)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if resp.Total != want || resp.UnreadCount != want {
+			t.Fatalf("user %d: expected %d unread notifications, got total=%d unread=%d", userID, want, resp.Total, resp.UnreadCount)
+		}
+	}
+}
+
+func TestListPaginatesAndMarksRead(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	for chapter := 1; chapter <= 5; chapter++ {
+		if _, err := svc.RecordChapterRelease(ctx, 10, "Berserk", chapter, 0, nil); err != nil {
+			t.Fatalf("RecordChapterRelease returned error: %v", err)
+		}
+	}
+
+	first, err := svc.List(ctx, 1, false, 1, 2)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if first.Total != 5 || first.Pages != 3 || len(first.Notifications) != 2 {
+		t.Fatalf("unexpected first page: total=%d pages=%d len=%d", first.Total, first.Pages, len(first.Notifications))
+	}
+	if got := *first.Notifications[0].Chapter; got != 5 {
+		t.Fatalf("expected newest notification first, got chapter %d", got)
+	}
+	last, err := svc.List(ctx, 1, false, 3, 2)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(last.Notifications) != 1 || *last.Notifications[0].Chapter != 1 {
+		t.Fatalf("expected the oldest notification alone on the last page, got %+v", last.Notifications)
+	}
+
+	newest := first.Notifications[0].ID
+	marked, err := svc.MarkRead(ctx, 1, newest)
+	if err != nil {
+		t.Fatalf("MarkRead returned error: %v", err)
+	}
+	if marked.Marked != 1 || marked.UnreadCount != 4 {
+		t.Fatalf("expected 1 marked and 4 unread, got %+v", marked)
+	}
+	if again, err := svc.MarkRead(ctx, 1, newest); err != nil || again.Marked != 0 {
+		t.Fatalf("expected marking twice to be a no-op, got %+v err=%v", again, err)
+	}
+	if _, err := svc.MarkRead(ctx, 2, newest); !errors.Is(err, ErrNotificationNotFound) {
+		t.Fatalf("expected ErrNotificationNotFound for another user's notification, got %v", err)
+	}
+
+	unread, err := svc.List(ctx, 1, true, 1, 20)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if unread.Total != 4 {
+		t.Fatalf("expected 4 unread notifications, got %d", unread.Total)
+	}
+	for _, n := range unread.Notifications {
+		if n.ID == newest || n.Read {
+			t.Fatalf("unread filter returned a read notification: %+v", n)
+		}
+	}
+
+	all, err := svc.MarkAllRead(ctx, 1)
+	if err != nil {
+		t.Fatalf("MarkAllRead returned error: %v", err)
+	}
+	if all.Marked != 4 || all.UnreadCount != 0 {
+		t.Fatalf("expected 4 marked and none unread, got %+v", all)
+	}
+	if other, _ := svc.List(ctx, 2, true, 1, 20); other.UnreadCount != 5 {
+		t.Fatalf("expected other users' notifications untouched, got %d unread", other.UnreadCount)
+	}
+}
+
+func TestSubscribeListUnsubscribeRoundTrip(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	svc.SetMangaChecker(fakeMangaChecker{10: true, 20: true})
+	ctx := context.Background()
+
+	if err := svc.Subscribe(ctx, 5, 20); err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	if err := svc.Subscribe(ctx, 5, 20); !errors.Is(err, ErrAlreadySubscribed) {
+		t.Fatalf("expected ErrAlreadySubscribed on second subscribe, got %v", err)
+	}
+	if err := svc.Subscribe(ctx, 5, 99); !errors.Is(err, ErrMangaNotFound) {
+		t.Fatalf("expected ErrMangaNotFound for unknown manga, got %v", err)
+	}
+
+	list, err := svc.ListSubscriptions(ctx, 5)
+	if err != nil {
+		t.Fatalf("ListSubscriptions returned error: %v", err)
+	}
+	if list.Total != 1 || *list.Subscriptions[0].MangaID != 20 || list.Subscriptions[0].MangaTitle != "Vagabond" {
+		t.Fatalf("unexpected subscriptions: %+v", list.Subscriptions)
+	}
+
+	if err := svc.Unsubscribe(ctx, 5, 20); err != nil {
+		t.Fatalf("Unsubscribe returned error: %v", err)
+	}
+	if err := svc.Unsubscribe(ctx, 5, 20); !errors.Is(err, ErrNotSubscribed) {
+		t.Fatalf("expected ErrNotSubscribed on second unsubscribe, got %v", err)
+	}
+	list, err = svc.ListSubscriptions(ctx, 5)
+	if err != nil {
+		t.Fatalf("ListSubscriptions returned error: %v", err)
+	}
+	if list.Total != 0 {
+		t.Fatalf("expected no subscriptions after unsubscribe, got %d", list.Total)
+	}
+
+	if err := svc.Subscribe(ctx, 5, 20); err != nil {
+		t.Fatalf("expected resubscribe to succeed, got %v", err)
+	}
+}
+
+func TestRecordChapterReleaseReachesSubscribers(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	if err := svc.Subscribe(ctx, 5, 20); err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO notification_subscriptions (user_id, manga_id, is_active) VALUES (6, NULL, 1)`); err != nil {
+		t.Fatalf("failed to insert all-manga subscription: %v", err)
+	}
+
+	recorded, err := svc.RecordChapterRelease(ctx, 20, "Vagabond", 3, 0, nil)
+	if err != nil {
+		t.Fatalf("RecordChapterRelease returned error: %v", err)
+	}
+	if recorded != 3 {
+		t.Fatalf("expected library user 3 and subscribers 5 and 6 to be recorded, got %d", recorded)
+	}
+}
+
+func TestGoalCompletedStoresNotificationForGoalOwner(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	goal := &history.ReadingGoal{GoalID: 4, UserID: 2, GoalType: "manga", TargetValue: 3, PeriodType: "yearly"}
+	if err := svc.GoalCompleted(ctx, goal); err != nil {
+		t.Fatalf("GoalCompleted returned error: %v", err)
+	}
+
+	resp, err := svc.List(ctx, 2, true, 1, 20)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(resp.Notifications) != 1 {
+		t.Fatalf("expected one notification, got %d", len(resp.Notifications))
+	}
+	n := resp.Notifications[0]
+	if n.Type != TypeGoalCompleted || n.Message != goal.CompletionMessage() || n.MangaID != nil {
+		t.Fatalf("unexpected notification %+v", n)
+	}
+}
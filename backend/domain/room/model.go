@@ -0,0 +1,33 @@
+package room
+
+// Member roles
+const (
+	RoleOwner  = "owner"
+	RoleMember = "member"
+)
+
+// Room is a WebSocket chat room. Rooms with a member list are private;
+// rooms without one (such as "general") are open to everyone.
+type Room struct {
+	ID          int64  `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	IsPrivate   bool   `json:"is_private"`
+	MemberCount int    `json:"member_count"`
+}
+
+// CreateRoomRequest is the payload of POST /rooms
+type CreateRoomRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AddMemberRequest is the payload of POST /rooms/:id/members
+type AddMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+// RoomsResponse lists the rooms a user can join
+type RoomsResponse struct {
+	Rooms []Room `json:"rooms"`
+	Total int    `json:"total"`
+}
@@ -0,0 +1,157 @@
+package room
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Repository persists chat rooms and their members
+type Repository struct {
+	db *sql.DB
+}
+
+// NewRepository constructs a room repository
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// CreatePrivateRoom inserts a room and makes ownerID its owner
+func (r *Repository) CreatePrivateRoom(ctx context.Context, ownerID int64, code, name string) (*Room, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO Chat_Rooms (Room_Code, Room_Name) VALUES (?, ?)`, code, name)
+	if err != nil {
+		return nil, err
+	}
+	roomID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+        INSERT INTO Chat_Room_Members (Room_Id, User_Id, Role) VALUES (?, ?, ?)
+    `, roomID, ownerID, RoleOwner); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &Room{ID: roomID, Code: code, Name: name, IsPrivate: true, MemberCount: 1}, nil
+}
+
+// GetRoom fetches a room by id; it returns nil when the room does not exist
+func (r *Repository) GetRoom(ctx context.Context, roomID int64) (*Room, error) {
+	var room Room
+	err := r.db.QueryRowContext(ctx, `
+        SELECT cr.Room_Id, COALESCE(cr.Room_Code, ''), COALESCE(cr.Room_Name, ''),
+               (SELECT COUNT(*) FROM Chat_Room_Members m WHERE m.Room_Id = cr.Room_Id)
+        FROM Chat_Rooms cr
+        WHERE cr.Room_Id = ?
+    `, roomID).Scan(&room.ID, &room.Code, &room.Name, &room.MemberCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	room.IsPrivate = room.MemberCount > 0
+	return &room, nil
+}
+
+// ListRooms returns public rooms and the private rooms userID belongs to
+func (r *Repository) ListRooms(ctx context.Context, userID int64) ([]Room, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT cr.Room_Id, COALESCE(cr.Room_Code, ''), COALESCE(cr.Room_Name, ''), COUNT(m.User_Id)
+        FROM Chat_Rooms cr
+        LEFT JOIN Chat_Room_Members m ON m.Room_Id = cr.Room_Id
+        GROUP BY cr.Room_Id, cr.Room_Code, cr.Room_Name
+        HAVING COUNT(m.User_Id) = 0 OR SUM(CASE WHEN m.User_Id = ? THEN 1 ELSE 0 END) > 0
+        ORDER BY cr.Room_Id
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []Room{}
+	for rows.Next() {
+		var room Room
+		if err := rows.Scan(&room.ID, &room.Code, &room.Name, &room.MemberCount); err != nil {
+			return nil, err
+		}
+		room.IsPrivate = room.MemberCount > 0
+		rooms = append(rooms, room)
+	}
+	return rooms, rows.Err()
+}
+
+// MemberRole returns the user's role in a room, or "" when they are not a member
+func (r *Repository) MemberRole(ctx context.Context, roomID, userID int64) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx, `
+        SELECT Role FROM Chat_Room_Members WHERE Room_Id = ? AND User_Id = ?
+    `, roomID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+// AddMember adds a user to a room; it reports false when they were already a member
+func (r *Repository) AddMember(ctx context.Context, roomID, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO Chat_Room_Members (Room_Id, User_Id, Role)
+        VALUES (?, ?, ?)
+        ON CONFLICT(Room_Id, User_Id) DO NOTHING
+    `, roomID, userID, RoleMember)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RemoveMember removes a user from a room; it reports false when they were not a member
+func (r *Repository) RemoveMember(ctx context.Context, roomID, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+        DELETE FROM Chat_Room_Members WHERE Room_Id = ? AND User_Id = ?
+    `, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// UserExists reports whether a user account exists
+func (r *Repository) UserExists(ctx context.Context, userID int64) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE id = ?`, userID).Scan(&count)
+	return count > 0, err
+}
+
+// CanJoin reports whether userID may join a room. Rooms without members are
+// public; private rooms only admit their members.
+func (r *Repository) CanJoin(ctx context.Context, roomID, userID int64) (bool, error) {
+	var members, mine int
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*), COALESCE(SUM(CASE WHEN User_Id = ? THEN 1 ELSE 0 END), 0)
+        FROM Chat_Room_Members
+        WHERE Room_Id = ?
+    `, userID, roomID).Scan(&members, &mine)
+	if err != nil {
+		return false, err
+	}
+	return members == 0 || mine > 0, nil
+}
@@ -0,0 +1,166 @@
+package room
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxRoomNameLength caps the length of a room name
+const MaxRoomNameLength = 100
+
+var (
+	ErrInvalidRoomName  = errors.New("room name must be between 1 and 100 characters")
+	ErrRoomNotFound     = errors.New("room not found")
+	ErrPublicRoom       = errors.New("public rooms have no member list")
+	ErrNotRoomOwner     = errors.New("only the room owner can manage members")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrAlreadyMember    = errors.New("user is already a member of this room")
+	ErrNotMember        = errors.New("user is not a member of this room")
+	ErrOwnerCannotLeave = errors.New("the room owner cannot be removed")
+	ErrDatabaseError    = errors.New("database error")
+)
+
+// Service manages private chat rooms and their members
+type Service struct {
+	repo *Repository
+}
+
+// NewService builds a room service
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// CreateRoom creates a private room owned by userID with a generated join code
+func (s *Service) CreateRoom(ctx context.Context, userID int64, req CreateRoomRequest) (*Room, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxRoomNameLength {
+		return nil, ErrInvalidRoomName
+	}
+
+	code, err := generateRoomCode()
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.repo.CreatePrivateRoom(ctx, userID, code, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return room, nil
+}
+
+// ListRooms returns the rooms userID can join
+func (s *Service) ListRooms(ctx context.Context, userID int64) (*RoomsResponse, error) {
+	rooms, err := s.repo.ListRooms(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &RoomsResponse{Rooms: rooms, Total: len(rooms)}, nil
+}
+
+// AddMember lets the owner of a private room admit another user
+func (s *Service) AddMember(ctx context.Context, actorID, roomID, userID int64) (*Room, error) {
+	if err := s.requireOwner(ctx, actorID, roomID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.UserExists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	added, err := s.repo.AddMember(ctx, roomID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !added {
+		return nil, ErrAlreadyMember
+	}
+	return s.reload(ctx, roomID)
+}
+
+// RemoveMember removes a user from a private room. The owner can remove
+// anyone but themselves; other members can only remove themselves.
+func (s *Service) RemoveMember(ctx context.Context, actorID, roomID, userID int64) (*Room, error) {
+	room, actorRole, err := s.privateRoom(ctx, actorID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case actorRole == RoleOwner && userID == actorID:
+		return nil, ErrOwnerCannotLeave
+	case actorRole != RoleOwner && userID != actorID:
+		return nil, ErrNotRoomOwner
+	}
+
+	removed, err := s.repo.RemoveMember(ctx, room.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !removed {
+		return nil, ErrNotMember
+	}
+	return s.reload(ctx, roomID)
+}
+
+// privateRoom loads a private room the actor belongs to. Non-members get
+// ErrRoomNotFound so private rooms are not revealed to outsiders.
+func (s *Service) privateRoom(ctx context.Context, actorID, roomID int64) (*Room, string, error) {
+	room, err := s.repo.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if room == nil {
+		return nil, "", ErrRoomNotFound
+	}
+	if !room.IsPrivate {
+		return nil, "", ErrPublicRoom
+	}
+
+	role, err := s.repo.MemberRole(ctx, roomID, actorID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if role == "" {
+		return nil, "", ErrRoomNotFound
+	}
+	return room, role, nil
+}
+
+func (s *Service) requireOwner(ctx context.Context, actorID, roomID int64) error {
+	_, role, err := s.privateRoom(ctx, actorID, roomID)
+	if err != nil {
+		return err
+	}
+	if role != RoleOwner {
+		return ErrNotRoomOwner
+	}
+	return nil
+}
+
+func (s *Service) reload(ctx context.Context, roomID int64) (*Room, error) {
+	room, err := s.repo.GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+	return room, nil
+}
+
+// generateRoomCode returns a random code used to join a room
+func generateRoomCode() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate room code: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
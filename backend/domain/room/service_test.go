@@ -0,0 +1,98 @@
+package room
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../db/migrations/025_chat_room_members.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	schema := `
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL);
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+    CREATE TABLE Chat_Rooms (Room_Id INTEGER PRIMARY KEY AUTOINCREMENT, Room_Code TEXT UNIQUE, Room_Name TEXT);
+    INSERT INTO Chat_Rooms (Room_Code, Room_Name) VALUES ('general', 'General Chat');
+    ` + string(migration)
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestPrivateRoomMembership(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	created, err := svc.CreateRoom(ctx, 1, CreateRoomRequest{Name: "  Book club "})
+	if err != nil {
+		t.Fatalf("CreateRoom returned error: %v", err)
+	}
+	if created.Name != "Book club" || len(created.Code) != 12 || !created.IsPrivate {
+		t.Fatalf("unexpected room: %+v", created)
+	}
+
+	listed, err := svc.ListRooms(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListRooms returned error: %v", err)
+	}
+	if listed.Total != 1 || listed.Rooms[0].Code != "general" {
+		t.Fatalf("expected bob to see only the general room, got %+v", listed.Rooms)
+	}
+
+	if _, err := svc.AddMember(ctx, 2, created.ID, 3); !errors.Is(err, ErrRoomNotFound) {
+		t.Fatalf("expected non-members to get ErrRoomNotFound, got %v", err)
+	}
+	if _, err := svc.AddMember(ctx, 1, 1, 2); !errors.Is(err, ErrPublicRoom) {
+		t.Fatalf("expected ErrPublicRoom for the general room, got %v", err)
+	}
+	updated, err := svc.AddMember(ctx, 1, created.ID, 2)
+	if err != nil {
+		t.Fatalf("AddMember returned error: %v", err)
+	}
+	if updated.MemberCount != 2 {
+		t.Fatalf("expected 2 members, got %d", updated.MemberCount)
+	}
+	if _, err := svc.AddMember(ctx, 1, created.ID, 2); !errors.Is(err, ErrAlreadyMember) {
+		t.Fatalf("expected ErrAlreadyMember, got %v", err)
+	}
+	if _, err := svc.AddMember(ctx, 2, created.ID, 3); !errors.Is(err, ErrNotRoomOwner) {
+		t.Fatalf("expected ErrNotRoomOwner, got %v", err)
+	}
+
+	listed, err = svc.ListRooms(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListRooms returned error: %v", err)
+	}
+	if listed.Total != 2 {
+		t.Fatalf("expected bob to see the private room after being added, got %+v", listed.Rooms)
+	}
+
+	if _, err := svc.RemoveMember(ctx, 1, created.ID, 1); !errors.Is(err, ErrOwnerCannotLeave) {
+		t.Fatalf("expected ErrOwnerCannotLeave, got %v", err)
+	}
+	if _, err := svc.RemoveMember(ctx, 2, created.ID, 2); err != nil {
+		t.Fatalf("expected a member to leave, got %v", err)
+	}
+	allowed, err := NewRepository(db).CanJoin(ctx, created.ID, 2)
+	if err != nil || allowed {
+		t.Fatalf("expected bob to lose access after leaving, allowed=%v err=%v", allowed, err)
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// accountPurge lists the statements that remove or detach a user's data
+// before the users row itself is deleted. Every ? is bound to the user's ID.
+// Foreign keys are not enforced on every connection, so nothing relies on
+// ON DELETE CASCADE. Revoked_Tokens is left alone so the blacklist keeps
+// working until the user's tokens expire.
+var accountPurge = []string{
+	// Library and reading progress
+	`DELETE FROM libraries WHERE user_id = ?`,
+	`DELETE FROM favorites WHERE user_id = ?`,
+	`DELETE FROM Collection_Items WHERE Collection_Id IN (SELECT Collection_Id FROM Collections WHERE User_Id = ?)`,
+	`DELETE FROM Collections WHERE User_Id = ?`,
+	`DELETE FROM reading_progress WHERE user_id = ?`,
+	`DELETE FROM Progress_History WHERE User_Id = ?`,
+	`DELETE FROM reading_history WHERE user_id = ?`,
+	`DELETE FROM Reading_Sessions WHERE User_Id = ?`,
+	`DELETE FROM reading_statistics WHERE user_id = ?`,
+	`DELETE FROM reading_goals WHERE user_id = ?`,
+	`DELETE FROM Streak_Freezes WHERE User_Id = ?`,
+
+	// Reviews and ratings, including other users' votes and flags on them
+	`DELETE FROM Review_Votes WHERE User_Id = ? OR Review_Id IN (SELECT id FROM ratings WHERE user_id = ?)`,
+	`DELETE FROM Review_Flags WHERE User_Id = ? OR Review_Id IN (SELECT id FROM ratings WHERE user_id = ?)`,
+	`DELETE FROM ratings WHERE user_id = ?`,
+	`DELETE FROM comments WHERE user_id = ?`,
+
+	// Friendships and messages
+	`DELETE FROM friends WHERE user_id = ? OR friend_user_id = ?`,
+	`DELETE FROM Blocked_Users WHERE Blocker_Id = ? OR Blocked_Id = ?`,
+	`DELETE FROM activities WHERE user_id = ?`,
+	`DELETE FROM Direct_Messages WHERE Sender_Id = ? OR Recipient_Id = ?`,
+	`DELETE FROM chat_messages WHERE user_id = ?`,
+	`DELETE FROM Message_Reads WHERE User_Id = ?`,
+	`DELETE FROM Chat_Room_Members WHERE User_Id = ?`,
+
+	// Subscriptions, settings and sessions
+	`DELETE FROM notification_subscriptions WHERE user_id = ?`,
+	`DELETE FROM Notifications WHERE User_Id = ?`,
+	`DELETE FROM User_Privacy WHERE User_Id = ?`,
+	`DELETE FROM sync_sessions WHERE user_id = ?`,
+	`DELETE FROM Idempotency_Keys WHERE User_Id = ?`,
+	`DELETE FROM Password_Reset_Tokens WHERE User_Id = ?`,
+	`DELETE FROM Refresh_Tokens WHERE User_Id = ?`,
+
+	// Shared content outlives its creator
+	`UPDATE chat_rooms SET created_by = NULL WHERE created_by = ?`,
+	`UPDATE mangas SET created_by = NULL WHERE created_by = ?`,
+	`UPDATE chapters SET uploaded_by = NULL WHERE uploaded_by = ?`,
+}
+
+// DeleteAccount removes the user and everything they own in one transaction
+// after checking their password. Revoking access tokens and closing live
+// connections is left to the caller.
+func DeleteAccount(ctx context.Context, db *sql.DB, userID int64, req DeleteAccountRequest) (err error) {
+	var passwordHash string
+	err = db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE id = ?`, userID).Scan(&passwordHash)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)) != nil {
+		return ErrIncorrectPassword
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	for _, query := range accountPurge {
+		args := make([]interface{}, strings.Count(query, "?"))
+		for i := range args {
+			args[i] = userID
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	// A concurrent deletion already removed the row
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
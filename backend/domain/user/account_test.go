@@ -0,0 +1,202 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/testdb"
+)
+
+// userColumn is a column holding a user ID
+type userColumn struct{ table, column string }
+
+// unreferencedUserColumns hold user IDs without a foreign key to users, so
+// the schema cannot list them
+var unreferencedUserColumns = []userColumn{
+	{"Idempotency_Keys", "User_Id"},
+}
+
+// referencingUserColumns lists every column with a foreign key to users
+func referencingUserColumns(t *testing.T, db *sql.DB) []userColumn {
+	t.Helper()
+
+	rows, err := db.Query(`
+        SELECT m.name, f."from"
+        FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+        WHERE m.type = 'table' AND f."table" = 'users'
+        ORDER BY m.name, f."from"`)
+	if err != nil {
+		t.Fatalf("failed to list foreign keys: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []userColumn
+	for rows.Next() {
+		var col userColumn
+		if err := rows.Scan(&col.table, &col.column); err != nil {
+			t.Fatalf("failed to scan foreign key: %v", err)
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to list foreign keys: %v", err)
+	}
+	return columns
+}
+
+// seedUserRow inserts a row into table with every listed user column set to
+// userID. Other required columns get the first value their check allows, or
+// else a value derived from userID so rows of different users stay unique.
+func seedUserRow(t *testing.T, db *sql.DB, table string, userColumns map[string]bool, userID int64) {
+	t.Helper()
+
+	var ddl string
+	if err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&ddl); err != nil {
+		t.Fatalf("failed to read the schema of %s: %v", table, err)
+	}
+	rows, err := db.Query(fmt.Sprintf(`SELECT name, type, "notnull", dflt_value IS NOT NULL FROM pragma_table_info('%s')`, table))
+	if err != nil {
+		t.Fatalf("failed to read the columns of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	var names []string
+	var values []interface{}
+	for rows.Next() {
+		var (
+			name, colType    string
+			notNull, hasDflt bool
+		)
+		if err := rows.Scan(&name, &colType, &notNull, &hasDflt); err != nil {
+			t.Fatalf("failed to scan a column of %s: %v", table, err)
+		}
+		switch {
+		case userColumns[name]:
+			values = append(values, userID)
+		case !notNull || hasDflt:
+			continue
+		default:
+			values = append(values, requiredValue(ddl, table, name, colType, userID))
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to read the columns of %s: %v", table, err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (?%s)`, table, strings.Join(names, ", "), strings.Repeat(", ?", len(names)-1))
+	if _, err := db.Exec(query, values...); err != nil {
+		t.Fatalf("failed to seed %s: %v", table, err)
+	}
+}
+
+func requiredValue(ddl, table, column, colType string, userID int64) interface{} {
+	if allowed := regexp.MustCompile(`CHECK \(` + column + ` (?:IN \(|BETWEEN )'?([^',) ]*)`).FindStringSubmatch(ddl); allowed != nil {
+		return allowed[1]
+	}
+	colType = strings.ToUpper(colType)
+	switch {
+	case strings.Contains(colType, "INT"), strings.Contains(colType, "REAL"):
+		return userID
+	case strings.Contains(colType, "DATE"), strings.Contains(colType, "TIME"):
+		return time.Now().UTC()
+	default:
+		return fmt.Sprintf("%s-%s-%d", table, column, userID)
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestDeleteAccountPurgesEveryTableReferencingUsers(t *testing.T) {
+	db := testdb.Migrated(t)
+	ctx := context.Background()
+
+	columns := append(referencingUserColumns(t, db), unreferencedUserColumns...)
+	if len(columns) < 30 {
+		t.Fatalf("expected the migrated schema to reference users from many tables, found %v", columns)
+	}
+	userColumns := make(map[string]map[string]bool)
+	var tables []string
+	for _, col := range columns {
+		if userColumns[col.table] == nil {
+			userColumns[col.table] = make(map[string]bool)
+			tables = append(tables, col.table)
+		}
+		userColumns[col.table][col.column] = true
+	}
+
+	alice := createTestUser(t, db, "purge-alice", "purge-alice@example.com", "password123")
+	bob := createTestUser(t, db, "purge-bob", "purge-bob@example.com", "password123")
+	carol := createTestUser(t, db, "purge-carol", "purge-carol@example.com", "password123")
+	for _, table := range tables {
+		for _, id := range []int64{alice, bob} {
+			seedUserRow(t, db, table, userColumns[table], id)
+		}
+	}
+
+	// Rows tied to alice only through her collections and reviews
+	var collectionID, reviewID int64
+	if err := db.QueryRow(`SELECT Collection_Id FROM Collections WHERE User_Id = ?`, alice).Scan(&collectionID); err != nil {
+		t.Fatalf("failed to read collection: %v", err)
+	}
+	if err := db.QueryRow(`SELECT id FROM ratings WHERE user_id = ?`, alice).Scan(&reviewID); err != nil {
+		t.Fatalf("failed to read review: %v", err)
+	}
+	if _, err := db.Exec(`
+        INSERT INTO Collection_Items (Collection_Id, Manga_Id) VALUES (?, 1);
+        INSERT INTO Review_Votes (Review_Id, User_Id, Vote) VALUES (?, ?, 1);
+        INSERT INTO Review_Flags (Review_Id, User_Id, Reason) VALUES (?, ?, 'spam');
+    `, collectionID, reviewID, carol, reviewID, carol); err != nil {
+		t.Fatalf("failed to seed rows: %v", err)
+	}
+
+	err := DeleteAccount(ctx, db, alice, DeleteAccountRequest{Password: "wrong-password"})
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+	}
+	if countRows(t, db, `SELECT COUNT(*) FROM libraries WHERE user_id = ?`, alice) != 1 {
+		t.Fatal("a rejected deletion must not remove anything")
+	}
+
+	if err := DeleteAccount(ctx, db, alice, DeleteAccountRequest{Password: "password123"}); err != nil {
+		t.Fatalf("DeleteAccount returned error: %v", err)
+	}
+
+	if countRows(t, db, `SELECT COUNT(*) FROM users WHERE id = ?`, alice) != 0 {
+		t.Fatal("expected the users row to be deleted")
+	}
+	for _, col := range columns {
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s = ?`, col.table, col.column)
+		if n := countRows(t, db, query, alice); n != 0 {
+			t.Errorf("%s.%s still has %d rows for the deleted user", col.table, col.column, n)
+		}
+		if n := countRows(t, db, query, bob); n != 1 {
+			t.Errorf("%s.%s has %d rows for another user, want 1", col.table, col.column, n)
+		}
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM Collection_Items WHERE Collection_Id = ?`, collectionID); n != 0 {
+		t.Errorf("expected the deleted user's collection items to be removed, got %d", n)
+	}
+	for _, table := range []string{"Review_Votes", "Review_Flags"} {
+		if n := countRows(t, db, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE Review_Id = ?`, table), reviewID); n != 0 {
+			t.Errorf("expected %s on the deleted user's review to be removed, got %d", table, n)
+		}
+	}
+
+	if err := DeleteAccount(ctx, db, alice, DeleteAccountRequest{Password: "password123"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound for a deleted account, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxAvatarBytes caps the size of an uploaded avatar
+const MaxAvatarBytes = 2 << 20
+
+var (
+	ErrAvatarTooLarge = errors.New("avatar must be 2 MiB or smaller")
+	ErrAvatarType     = errors.New("avatar must be a PNG or JPEG image")
+	ErrAvatarNotFound = errors.New("avatar not found")
+)
+
+// avatarExtensions maps accepted image types to the extension they are stored under
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// AvatarPath is the URL an avatar is served from. Users without an upload
+// get a generated identicon at the same address.
+func AvatarPath(userID int64) string {
+	return "/avatars/" + strconv.FormatInt(userID, 10)
+}
+
+// AvatarURLOrDefault returns avatarURL, falling back to the user's identicon
+func AvatarURLOrDefault(userID int64, avatarURL string) string {
+	if strings.TrimSpace(avatarURL) != "" {
+		return avatarURL
+	}
+	return AvatarPath(userID)
+}
+
+// AvatarStore keeps uploaded avatars on disk, one file per user
+type AvatarStore struct {
+	dir string
+}
+
+// NewAvatarStore stores avatars under dir, creating it on first upload
+func NewAvatarStore(dir string) *AvatarStore {
+	return &AvatarStore{dir: dir}
+}
+
+// Save validates an uploaded image, stores it and points users.avatar_url at it.
+// It returns the new avatar URL, versioned so clients drop cached copies.
+func (s *AvatarStore) Save(ctx context.Context, db *sql.DB, userID int64, r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxAvatarBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxAvatarBytes {
+		return "", ErrAvatarTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", ErrAvatarType
+	}
+	// The header alone can be forged; make sure the image actually decodes
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return "", ErrAvatarType
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("create avatar dir: %w", err)
+	}
+	name := s.fileName(userID, ext)
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("write avatar: %w", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("write avatar: %w", err)
+	}
+	// Drop an older upload stored under the other image type
+	for _, other := range avatarExtensions {
+		if other != ext {
+			_ = os.Remove(s.fileName(userID, other))
+		}
+	}
+
+	url := fmt.Sprintf("%s?v=%d", AvatarPath(userID), time.Now().Unix())
+	res, err := db.ExecContext(ctx, `UPDATE users SET avatar_url = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, url, userID)
+	if err != nil {
+		return "", err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		_ = os.Remove(name)
+		return "", ErrUserNotFound
+	}
+	return url, nil
+}
+
+// Open returns a user's uploaded avatar and its content type
+func (s *AvatarStore) Open(userID int64) ([]byte, string, error) {
+	for contentType, ext := range avatarExtensions {
+		data, err := os.ReadFile(s.fileName(userID, ext))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return data, contentType, nil
+	}
+	return nil, "", ErrAvatarNotFound
+}
+
+// Remove deletes the user's uploaded avatar, if any
+func (s *AvatarStore) Remove(userID int64) error {
+	for _, ext := range avatarExtensions {
+		if err := os.Remove(s.fileName(userID, ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AvatarStore) fileName(userID int64, ext string) string {
+	return filepath.Join(s.dir, strconv.FormatInt(userID, 10)+ext)
+}
+
+// Identicon renders a deterministic 5x5 mirrored SVG avatar for a user
+func Identicon(userID int64) []byte {
+	sum := sha256.Sum256([]byte("avatar:" + strconv.FormatInt(userID, 10)))
+	color := fmt.Sprintf("#%02x%02x%02x", sum[0], sum[1], sum[2])
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 5" width="120" height="120" shape-rendering="crispEdges">`)
+	b.WriteString(`<rect width="5" height="5" fill="#f0f0f0"/>`)
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 3; col++ {
+			if sum[3+row*3+col]%2 == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`, col, row, color)
+			if col < 2 {
+				fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`, 4-col, row, color)
+			}
+		}
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
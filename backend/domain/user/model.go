@@ -24,6 +24,29 @@ type LoginRequest struct {
 
 // LoginResponse represents successful login response
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	User         *User  `json:"user"`
+}
+
+// ChangePasswordRequest represents a password change by a logged-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// DeleteAccountRequest confirms an account deletion with the user's password
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// PasswordResetRequest starts the reset flow for an account
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// PasswordResetConfirmRequest sets a new password using a reset token
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PrivacySettings controls which of a user's activities appear in friends' feeds
+type PrivacySettings struct {
+	ShareCompletions bool `json:"share_completions"`
+	ShareReviews     bool `json:"share_reviews"`
+	ShareRatings     bool `json:"share_ratings"`
+}
+
+// UpdatePrivacyRequest changes only the settings that are present
+type UpdatePrivacyRequest struct {
+	ShareCompletions *bool `json:"share_completions"`
+	ShareReviews     *bool `json:"share_reviews"`
+	ShareRatings     *bool `json:"share_ratings"`
+}
+
+// GetPrivacy returns the user's privacy settings. Users who never changed
+// them share everything.
+func GetPrivacy(ctx context.Context, db *sql.DB, userID int64) (*PrivacySettings, error) {
+	settings := PrivacySettings{ShareCompletions: true, ShareReviews: true, ShareRatings: true}
+	err := db.QueryRowContext(ctx, `
+        SELECT Share_Completions, Share_Reviews, Share_Ratings
+        FROM User_Privacy
+        WHERE User_Id = ?
+    `, userID).Scan(&settings.ShareCompletions, &settings.ShareReviews, &settings.ShareRatings)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdatePrivacy applies the provided settings and returns the result
+func UpdatePrivacy(ctx context.Context, db *sql.DB, userID int64, req UpdatePrivacyRequest) (*PrivacySettings, error) {
+	settings, err := GetPrivacy(ctx, db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ShareCompletions != nil {
+		settings.ShareCompletions = *req.ShareCompletions
+	}
+	if req.ShareReviews != nil {
+		settings.ShareReviews = *req.ShareReviews
+	}
+	if req.ShareRatings != nil {
+		settings.ShareRatings = *req.ShareRatings
+	}
+
+	_, err = db.ExecContext(ctx, `
+        INSERT INTO User_Privacy (User_Id, Share_Completions, Share_Reviews, Share_Ratings, Updated_At)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT(User_Id) DO UPDATE SET
+            Share_Completions = excluded.Share_Completions,
+            Share_Reviews = excluded.Share_Reviews,
+            Share_Ratings = excluded.Share_Ratings,
+            Updated_At = excluded.Updated_At
+    `, userID, settings.ShareCompletions, settings.ShareReviews, settings.ShareRatings, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+// LogResetSender delivers password reset links by writing them to the log.
+// It stands in for email during development and must not be used where logs
+// are readable by anyone but the account owner.
+type LogResetSender struct {
+	resetURL string
+}
+
+// NewLogResetSender builds a sender whose links point at resetURL, the
+// frontend page that accepts the token query parameter.
+func NewLogResetSender(resetURL string) *LogResetSender {
+	return &LogResetSender{resetURL: resetURL}
+}
+
+// SendPasswordReset logs the reset link for email
+func (s *LogResetSender) SendPasswordReset(ctx context.Context, email, token string) error {
+	link, err := url.Parse(s.resetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	logging.FromContext(ctx).Info("user.LogResetSender: password reset link", "email", email, "reset_url", link.String())
+	return nil
+}
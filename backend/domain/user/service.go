@@ -2,7 +2,10 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
@@ -24,6 +27,12 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrPasswordHashing    = errors.New("password hashing failed")
+	ErrIncorrectPassword  = errors.New("current password is incorrect")
+	ErrResetTokenInvalid  = errors.New("reset token is invalid or already used")
+	ErrResetTokenExpired  = errors.New("reset token has expired")
+
+	// PasswordResetTTL is how long a reset token stays valid
+	PasswordResetTTL = time.Hour
 )
 
 // A2: invalid email
@@ -168,10 +177,8 @@ func Login(ctx context.Context, db *sql.DB, req LoginRequest) (*LoginResponse, e
 		return nil, ErrInvalidCredentials
 	}
 
-	// Step 3: Generate JWT token
-	// Import auth package for token generation
-	// We'll need to import it at the top
-	token, err := generateTokenForUser(userID, username, email)
+	// Step 3: Generate JWT token carrying the user's role
+	token, err := auth.GenerateTokenWithRole(userID, username, email, auth.LookupRole(ctx, db, userID))
 	if err != nil {
 		return nil, err
 	}
@@ -187,7 +194,130 @@ func Login(ctx context.Context, db *sql.DB, req LoginRequest) (*LoginResponse, e
 	}, nil
 }
 
-// generateTokenForUser is a helper to generate token
-func generateTokenForUser(userID int64, username, email string) (string, error) {
-	return auth.GenerateToken(userID, username, email)
+// ChangePassword replaces the user's password after checking the current one.
+// All of the user's refresh tokens are revoked so other sessions must log in again.
+func ChangePassword(ctx context.Context, db *sql.DB, userID int64, req ChangePasswordRequest) error {
+	if err := security.ValidatePassword(req.NewPassword); err != nil {
+		return fmt.Errorf("invalid password: %w", err)
+	}
+
+	var passwordHash string
+	err := db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE id = ?`, userID).Scan(&passwordHash)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.CurrentPassword)) != nil {
+		return ErrIncorrectPassword
+	}
+
+	return setPassword(ctx, db, userID, req.NewPassword, nil)
+}
+
+// RequestPasswordReset creates a single-use reset token for the account with
+// the given email and returns it for delivery. An unknown email returns an
+// empty token and no error so callers cannot probe for accounts.
+func RequestPasswordReset(ctx context.Context, db *sql.DB, email string) (string, error) {
+	var userID int64
+	err := db.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, strings.TrimSpace(email)).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	now := time.Now().UTC()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO Password_Reset_Tokens (User_Id, Token_Hash, Expires_At, Created_At)
+		VALUES (?, ?, ?, ?)
+	`, userID, hashResetToken(token), now.Add(PasswordResetTTL), now); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConfirmPasswordReset consumes a reset token and sets a new password
+func ConfirmPasswordReset(ctx context.Context, db *sql.DB, req PasswordResetConfirmRequest) error {
+	if err := security.ValidatePassword(req.NewPassword); err != nil {
+		return fmt.Errorf("invalid password: %w", err)
+	}
+
+	var (
+		tokenID   int64
+		userID    int64
+		expiresAt time.Time
+	)
+	err := db.QueryRowContext(ctx, `
+		SELECT Token_Id, User_Id, Expires_At
+		FROM Password_Reset_Tokens
+		WHERE Token_Hash = ? AND Used_At IS NULL
+	`, hashResetToken(strings.TrimSpace(req.Token))).Scan(&tokenID, &userID, &expiresAt)
+	if err == sql.ErrNoRows {
+		return ErrResetTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if !expiresAt.After(time.Now()) {
+		return ErrResetTokenExpired
+	}
+
+	return setPassword(ctx, db, userID, req.NewPassword, &tokenID)
+}
+
+// setPassword stores a new hash, marks the reset token (if any) as used and
+// revokes refresh tokens in one transaction
+func setPassword(ctx context.Context, db *sql.DB, userID int64, password string, resetTokenID *int64) (err error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPasswordHashing, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	now := time.Now().UTC()
+	if resetTokenID != nil {
+		// The Used_At guard keeps the token single-use under concurrent confirms
+		res, err := tx.ExecContext(ctx, `
+			UPDATE Password_Reset_Tokens SET Used_At = ?
+			WHERE Token_Id = ? AND Used_At IS NULL
+		`, now, *resetTokenID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrResetTokenInvalid
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?`, string(hashed), now, userID); err != nil {
+		return err
+	}
+	return auth.RevokeUserRefreshTokens(ctx, tx, userID)
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
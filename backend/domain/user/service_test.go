@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -117,3 +118,102 @@ func TestLoginUserNotFound(t *testing.T) {
 		t.Fatalf("expected ErrUserNotFound, got %v", err)
 	}
 }
+
+func setupPasswordTables(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	schema := `
+CREATE TABLE Refresh_Tokens (
+    Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Token_Hash TEXT NOT NULL UNIQUE,
+    Expires_At DATETIME NOT NULL,
+    Revoked INTEGER NOT NULL DEFAULT 0,
+    Revoked_At DATETIME,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE Password_Reset_Tokens (
+    Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Token_Hash TEXT NOT NULL UNIQUE,
+    Expires_At DATETIME NOT NULL,
+    Used_At DATETIME,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create password tables: %v", err)
+	}
+}
+
+func TestChangePasswordRejectsWrongCurrentPassword(t *testing.T) {
+	db := setupTestDB(t)
+	setupPasswordTables(t, db)
+	userID := createTestUser(t, db, "reader3", "reader3@example.com", "old-password1")
+
+	err := ChangePassword(context.Background(), db, userID, ChangePasswordRequest{
+		CurrentPassword: "not-my-password",
+		NewPassword:     "new-password1",
+	})
+	if err != ErrIncorrectPassword {
+		t.Fatalf("expected ErrIncorrectPassword, got %v", err)
+	}
+
+	refreshToken, _, err := auth.GenerateRefreshToken(context.Background(), db, userID)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken returned error: %v", err)
+	}
+	if err := ChangePassword(context.Background(), db, userID, ChangePasswordRequest{
+		CurrentPassword: "old-password1",
+		NewPassword:     "new-password1",
+	}); err != nil {
+		t.Fatalf("ChangePassword returned error: %v", err)
+	}
+
+	if _, err := auth.ValidateRefreshToken(context.Background(), db, refreshToken); err != auth.ErrRefreshTokenRevoked {
+		t.Fatalf("expected refresh token to be revoked, got %v", err)
+	}
+	if _, err := Login(context.Background(), db, LoginRequest{Email: "reader3@example.com", Password: "new-password1"}); err != nil {
+		t.Fatalf("expected login with the new password, got %v", err)
+	}
+}
+
+func TestConfirmPasswordResetRejectsExpiredToken(t *testing.T) {
+	db := setupTestDB(t)
+	setupPasswordTables(t, db)
+	createTestUser(t, db, "reader4", "reader4@example.com", "old-password1")
+
+	token, err := RequestPasswordReset(context.Background(), db, "reader4@example.com")
+	if err != nil || token == "" {
+		t.Fatalf("RequestPasswordReset returned token=%q err=%v", token, err)
+	}
+	if _, err := db.Exec(`UPDATE Password_Reset_Tokens SET Expires_At = ?`, time.Now().Add(-time.Minute).UTC()); err != nil {
+		t.Fatalf("failed to expire token: %v", err)
+	}
+
+	err = ConfirmPasswordReset(context.Background(), db, PasswordResetConfirmRequest{Token: token, NewPassword: "new-password1"})
+	if err != ErrResetTokenExpired {
+		t.Fatalf("expected ErrResetTokenExpired, got %v", err)
+	}
+}
+
+func TestConfirmPasswordResetIsSingleUse(t *testing.T) {
+	db := setupTestDB(t)
+	setupPasswordTables(t, db)
+	createTestUser(t, db, "reader5", "reader5@example.com", "old-password1")
+
+	if token, err := RequestPasswordReset(context.Background(), db, "nobody@example.com"); err != nil || token != "" {
+		t.Fatalf("expected no token for an unknown email, got %q, %v", token, err)
+	}
+
+	token, err := RequestPasswordReset(context.Background(), db, "reader5@example.com")
+	if err != nil {
+		t.Fatalf("RequestPasswordReset returned error: %v", err)
+	}
+	req := PasswordResetConfirmRequest{Token: token, NewPassword: "new-password1"}
+	if err := ConfirmPasswordReset(context.Background(), db, req); err != nil {
+		t.Fatalf("ConfirmPasswordReset returned error: %v", err)
+	}
+	if err := ConfirmPasswordReset(context.Background(), db, req); err != ErrResetTokenInvalid {
+		t.Fatalf("expected a used token to be rejected, got %v", err)
+	}
+}
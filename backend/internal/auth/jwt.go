@@ -1,7 +1,11 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,7 +17,23 @@ var (
 	ErrInvalidSigningMethod = errors.New("invalid signing method")
 	ErrInvalidClaims        = errors.New("invalid token claims")
 	ErrTokenNotBefore       = errors.New("token not yet valid")
-	jwtSecret               = []byte("mangahub-secret-key-change-in-production")
+	// ErrInvalidAudience is returned for tokens minted for another service.
+	// It wraps ErrInvalidClaims so existing checks keep treating it as such.
+	ErrInvalidAudience = fmt.Errorf("%w: audience mismatch", ErrInvalidClaims)
+	jwtSecret          = []byte("mangahub-secret-key-change-in-production")
+)
+
+// Defaults for the access token lifetime and its iss/aud claims
+const (
+	DefaultAccessTokenTTL = 24 * time.Hour
+	DefaultIssuer         = "mangahub"
+	DefaultAudience       = "mangahub"
+)
+
+var (
+	accessTokenTTL = DefaultAccessTokenTTL
+	tokenIssuer    = DefaultIssuer
+	tokenAudience  = DefaultAudience
 )
 
 // SetSecret overrides the JWT secret used for signing and validation.
@@ -24,27 +44,68 @@ func SetSecret(secret string) {
 	jwtSecret = []byte(secret)
 }
 
+// SetAccessTokenTTL overrides the lifetime of newly issued access tokens.
+func SetAccessTokenTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	accessTokenTTL = ttl
+}
+
+// SetIssuer overrides the iss claim written into and required of tokens.
+func SetIssuer(issuer string) {
+	if issuer == "" {
+		return
+	}
+	tokenIssuer = issuer
+}
+
+// SetAudience overrides the aud claim written into tokens. Tokens whose
+// audience does not include it are rejected, so every server sharing the
+// secret must be configured with the same audience to accept each other's tokens.
+func SetAudience(audience string) {
+	if audience == "" {
+		return
+	}
+	tokenAudience = audience
+}
+
 // Claims represents JWT claims
 type Claims struct {
 	UserID   int64  `json:"user_id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates a JWT token for a user
 func GenerateToken(userID int64, username, email string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token expires in 24 hours
+	return GenerateTokenWithRole(userID, username, email, "")
+}
+
+// GenerateTokenWithRole generates a JWT token carrying the user's role
+func GenerateTokenWithRole(userID int64, username, email, role string) (string, error) {
+	now := time.Now()
+	expirationTime := now.Add(accessTokenTTL)
+
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
 
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		Email:    email,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "mangahub",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    tokenIssuer,
+			Audience:  jwt.ClaimStrings{tokenAudience},
 			Subject:   username,
 		},
 	}
@@ -63,6 +124,47 @@ func GenerateToken(userID int64, username, email string) (string, error) {
 // Expired tokens trigger reauthentication
 // Token claims are properly validated
 func ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject tokens that were explicitly revoked (logout)
+	if err := checkRevoked(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// UserIDFromToken returns the user ID of a correctly signed, unexpired token
+// without consulting the revocation list. It is meant for cheap request
+// attribution such as rate limiting, not for authorization.
+func UserIDFromToken(tokenString string) (int64, bool) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// RoleFromToken returns the role claim of a correctly signed, unexpired token
+// without consulting the revocation list. Tokens issued before roles were
+// added carry no claim and count as RoleUser. Like UserIDFromToken it must
+// not be used for authorization.
+func RoleFromToken(tokenString string) (string, bool) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return "", false
+	}
+	if claims.Role == "" {
+		return RoleUser, true
+	}
+	return claims.Role, true
+}
+
+// parseToken verifies the signature and claims of a token
+func parseToken(tokenString string) (*Claims, error) {
 	if tokenString == "" {
 		return nil, ErrInvalidToken
 	}
@@ -113,10 +215,15 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	// Validate issuer
-	if claims.Issuer != "mangahub" {
+	if claims.Issuer != tokenIssuer {
 		return nil, ErrInvalidClaims
 	}
 
+	// Reject tokens minted for another service
+	if !slices.Contains(claims.Audience, tokenAudience) {
+		return nil, ErrInvalidAudience
+	}
+
 	// Validate subject matches username
 	if claims.Subject != claims.Username {
 		return nil, ErrInvalidClaims
@@ -140,3 +247,12 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// newTokenID returns a random identifier used as the jti claim
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func withTokenSettings(t *testing.T, ttl time.Duration, issuer, audience string) {
+	t.Helper()
+	prevTTL, prevIssuer, prevAudience := accessTokenTTL, tokenIssuer, tokenAudience
+	t.Cleanup(func() {
+		accessTokenTTL, tokenIssuer, tokenAudience = prevTTL, prevIssuer, prevAudience
+	})
+	SetAccessTokenTTL(ttl)
+	SetIssuer(issuer)
+	SetAudience(audience)
+}
+
+func TestAccessTokenExpiresAfterConfiguredTTL(t *testing.T) {
+	withTokenSettings(t, time.Second, DefaultIssuer, DefaultAudience)
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("expected a fresh token to validate, got %v", err)
+	}
+	if lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time); lifetime != time.Second {
+		t.Fatalf("expected a 1s lifetime, got %s", lifetime)
+	}
+
+	// NumericDate has second precision, so wait past the next whole second
+	time.Sleep(2100 * time.Millisecond)
+	if _, err := ValidateToken(token); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+}
+
+func TestTokenCarriesIssuerAndAudience(t *testing.T) {
+	withTokenSettings(t, time.Hour, "mangahub-api", "mangahub-services")
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if claims.Issuer != "mangahub-api" {
+		t.Fatalf("expected issuer mangahub-api, got %q", claims.Issuer)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "mangahub-services" {
+		t.Fatalf("expected audience [mangahub-services], got %v", claims.Audience)
+	}
+}
+
+func TestValidateTokenRejectsAudienceMismatch(t *testing.T) {
+	withTokenSettings(t, time.Hour, DefaultIssuer, "mangahub-staging")
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+
+	SetAudience("mangahub-production")
+	_, err = ValidateToken(token)
+	if !errors.Is(err, ErrInvalidAudience) {
+		t.Fatalf("expected ErrInvalidAudience, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidClaims) {
+		t.Fatalf("expected the audience error to count as invalid claims, got %v", err)
+	}
+	if _, ok := UserIDFromToken(token); ok {
+		t.Fatal("expected UserIDFromToken to reject a token for another audience")
+	}
+}
+
+func TestValidateTokenRejectsIssuerMismatch(t *testing.T) {
+	withTokenSettings(t, time.Hour, "someone-else", DefaultAudience)
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+
+	SetIssuer(DefaultIssuer)
+	if _, err := ValidateToken(token); !errors.Is(err, ErrInvalidClaims) {
+		t.Fatalf("expected ErrInvalidClaims, got %v", err)
+	}
+}
+
+func TestRoleFromToken(t *testing.T) {
+	admin, err := GenerateTokenWithRole(1, "alice", "alice@example.com", RoleAdmin)
+	if err != nil {
+		t.Fatalf("GenerateTokenWithRole returned error: %v", err)
+	}
+	if role, ok := RoleFromToken(admin); !ok || role != RoleAdmin {
+		t.Fatalf("expected role %q, got %q (ok=%v)", RoleAdmin, role, ok)
+	}
+
+	legacy, err := GenerateToken(2, "bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	if role, ok := RoleFromToken(legacy); !ok || role != RoleUser {
+		t.Fatalf("expected tokens without a role to count as %q, got %q (ok=%v)", RoleUser, role, ok)
+	}
+
+	if _, ok := RoleFromToken("not-a-token"); ok {
+		t.Fatal("expected an invalid token to have no role")
+	}
+}
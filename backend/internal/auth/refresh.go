@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+
+	refreshTokenTTL      = 30 * 24 * time.Hour
+	refreshTokenRotation = true
+)
+
+// RefreshToken describes a stored refresh token.
+// The raw token value is never persisted; only its SHA-256 hash is stored.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+// SetRefreshTokenRotation controls whether each refresh revokes the
+// presented token and issues a new one.
+func SetRefreshTokenRotation(enabled bool) {
+	refreshTokenRotation = enabled
+}
+
+// SetRefreshTokenTTL overrides the lifetime of newly issued refresh tokens.
+func SetRefreshTokenTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	refreshTokenTTL = ttl
+}
+
+// GenerateRefreshToken issues a new opaque refresh token for a user and stores it
+func GenerateRefreshToken(ctx context.Context, db *sql.DB, userID int64) (string, time.Time, error) {
+	return insertRefreshToken(ctx, db, userID)
+}
+
+// ValidateRefreshToken checks that a refresh token exists, is not revoked and has not expired
+func ValidateRefreshToken(ctx context.Context, db *sql.DB, token string) (*RefreshToken, error) {
+	return lookupRefreshToken(ctx, db, token)
+}
+
+// RotateRefreshToken validates a refresh token and, when rotation is enabled,
+// revokes it and issues a replacement in the same transaction.
+// When rotation is disabled the presented token is returned unchanged.
+func RotateRefreshToken(ctx context.Context, db *sql.DB, token string) (string, *RefreshToken, error) {
+	if !refreshTokenRotation {
+		rt, err := lookupRefreshToken(ctx, db, token)
+		if err != nil {
+			return "", nil, err
+		}
+		return token, rt, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lookupRefreshToken(ctx, tx, token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Guard against two concurrent refreshes both consuming the same token
+	res, err := tx.ExecContext(ctx, `
+		UPDATE Refresh_Tokens
+		SET Revoked = 1, Revoked_At = ?
+		WHERE Token_Id = ? AND Revoked = 0
+	`, time.Now().UTC(), current.ID)
+	if err != nil {
+		return "", nil, err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return "", nil, ErrRefreshTokenRevoked
+	}
+
+	newToken, expiresAt, err := insertRefreshToken(ctx, tx, current.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", nil, err
+	}
+
+	return newToken, &RefreshToken{UserID: current.UserID, ExpiresAt: expiresAt}, nil
+}
+
+// RevokeRefreshToken marks a refresh token as revoked
+func RevokeRefreshToken(ctx context.Context, db *sql.DB, token string) error {
+	res, err := db.ExecContext(ctx, `
+		UPDATE Refresh_Tokens
+		SET Revoked = 1, Revoked_At = ?
+		WHERE Token_Hash = ? AND Revoked = 0
+	`, time.Now().UTC(), hashRefreshToken(token))
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return ErrRefreshTokenNotFound
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every active refresh token of a user,
+// e.g. after a password change. q may be a *sql.DB or *sql.Tx.
+func RevokeUserRefreshTokens(ctx context.Context, q execQuerier, userID int64) error {
+	_, err := q.ExecContext(ctx, `
+		UPDATE Refresh_Tokens
+		SET Revoked = 1, Revoked_At = ?
+		WHERE User_Id = ? AND Revoked = 0
+	`, time.Now().UTC(), userID)
+	return err
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func insertRefreshToken(ctx context.Context, q execQuerier, userID int64) (string, time.Time, error) {
+	if userID <= 0 {
+		return "", time.Time{}, ErrInvalidClaims
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("generate refresh token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(refreshTokenTTL)
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO Refresh_Tokens (User_Id, Token_Hash, Expires_At, Revoked, Created_At)
+		VALUES (?, ?, ?, 0, ?)
+	`, userID, hashRefreshToken(token), expiresAt, now); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+func lookupRefreshToken(ctx context.Context, q execQuerier, token string) (*RefreshToken, error) {
+	if token == "" {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	var rt RefreshToken
+	var revoked bool
+	err := q.QueryRowContext(ctx, `
+		SELECT Token_Id, User_Id, Expires_At, Revoked
+		FROM Refresh_Tokens
+		WHERE Token_Hash = ?
+	`, hashRefreshToken(token)).Scan(&rt.ID, &rt.UserID, &rt.ExpiresAt, &revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, ErrRefreshTokenRevoked
+	}
+	if !rt.ExpiresAt.After(time.Now()) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	return &rt, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupRefreshTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file:test_refresh?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	schema := `
+CREATE TABLE Refresh_Tokens (
+    Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+    User_Id INTEGER NOT NULL,
+    Token_Hash TEXT NOT NULL UNIQUE,
+    Expires_At DATETIME NOT NULL,
+    Revoked INTEGER NOT NULL DEFAULT 0,
+    Revoked_At DATETIME,
+    Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP TABLE Refresh_Tokens`)
+	})
+
+	return db
+}
+
+func TestRotateRefreshTokenIssuesNewToken(t *testing.T) {
+	db := setupRefreshTestDB(t)
+	ctx := context.Background()
+
+	token, _, err := GenerateRefreshToken(ctx, db, 7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken returned error: %v", err)
+	}
+
+	rotated, rt, err := RotateRefreshToken(ctx, db, token)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken returned error: %v", err)
+	}
+	if rotated == token {
+		t.Fatalf("expected a new refresh token after rotation")
+	}
+	if rt.UserID != 7 {
+		t.Fatalf("expected user id 7, got %d", rt.UserID)
+	}
+
+	if _, err := ValidateRefreshToken(ctx, db, rotated); err != nil {
+		t.Fatalf("expected rotated token to be valid, got %v", err)
+	}
+}
+
+func TestRotateRefreshTokenRejectsRevokedReuse(t *testing.T) {
+	db := setupRefreshTestDB(t)
+	ctx := context.Background()
+
+	token, _, err := GenerateRefreshToken(ctx, db, 7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken returned error: %v", err)
+	}
+	if _, _, err := RotateRefreshToken(ctx, db, token); err != nil {
+		t.Fatalf("RotateRefreshToken returned error: %v", err)
+	}
+
+	if _, _, err := RotateRefreshToken(ctx, db, token); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("expected ErrRefreshTokenRevoked on reuse, got %v", err)
+	}
+}
+
+func TestValidateRefreshTokenExpired(t *testing.T) {
+	db := setupRefreshTestDB(t)
+	ctx := context.Background()
+
+	token, _, err := GenerateRefreshToken(ctx, db, 7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken returned error: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE Refresh_Tokens SET Expires_At = ?`, time.Now().Add(-time.Minute).UTC()); err != nil {
+		t.Fatalf("failed to expire token: %v", err)
+	}
+
+	if _, err := ValidateRefreshToken(ctx, db, token); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Fatalf("expected ErrRefreshTokenExpired, got %v", err)
+	}
+}
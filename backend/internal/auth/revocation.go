@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	revokedTokenPrefix     = "auth:revoked:"
+	revocationCheckTimeout = 2 * time.Second
+
+	// userRevocationPrefix marks a Revoked_Tokens row covering every token of
+	// a user rather than a single jti
+	userRevocationPrefix = "user:"
+)
+
+var (
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrRevocationUnavailable is returned when neither Redis nor the
+	// database can say whether a token was revoked
+	ErrRevocationUnavailable = errors.New("token revocation check unavailable")
+
+	revocations *RevocationStore
+)
+
+// RevocationCache is the subset of the Redis cache client used for the blacklist.
+type RevocationCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// RevocationStore records revoked access tokens by their jti.
+// Redis answers lookups for tokens it knows to be revoked; the Revoked_Tokens
+// table is the source of truth and is consulted on every cache miss or error.
+type RevocationStore struct {
+	db    *sql.DB
+	cache RevocationCache
+}
+
+// NewRevocationStore creates a revocation store. cache may be nil.
+func NewRevocationStore(db *sql.DB, cache RevocationCache) *RevocationStore {
+	return &RevocationStore{db: db, cache: cache}
+}
+
+// SetRevocationStore enables blacklist checks in ValidateToken.
+func SetRevocationStore(store *RevocationStore) {
+	revocations = store
+}
+
+// RevokeToken blacklists the token identified by the given claims until it expires.
+func RevokeToken(ctx context.Context, claims *Claims) error {
+	if revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+	if claims == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return ErrInvalidClaims
+	}
+	return revocations.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time)
+}
+
+// RevokeUserTokens blacklists every access token of the user until the
+// longest-lived of them has expired. It is meant for deleted accounts, whose
+// IDs are never reused.
+func RevokeUserTokens(ctx context.Context, userID int64) error {
+	if revocations == nil {
+		return errors.New("token revocation is not configured")
+	}
+	return revocations.Revoke(ctx, userRevocationKey(userID), userID, time.Now().Add(accessTokenTTL))
+}
+
+func userRevocationKey(userID int64) string {
+	return userRevocationPrefix + strconv.FormatInt(userID, 10)
+}
+
+// Revoke stores a jti in the blacklist until expiresAt.
+func (s *RevocationStore) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO Revoked_Tokens (Jti, User_Id, Expires_At, Revoked_At)
+		VALUES (?, ?, ?, ?)
+	`, jti, userID, expiresAt.UTC(), time.Now().UTC()); err != nil {
+		// Revoking an already revoked token is not an error
+		if revoked, lookupErr := s.isRevokedInDB(ctx, jti); lookupErr == nil && revoked {
+			return nil
+		}
+		return err
+	}
+
+	if s.cache != nil {
+		ttl := time.Until(expiresAt)
+		if ttl > 0 {
+			if err := s.cache.Set(ctx, revokedTokenPrefix+jti, "1", ttl); err != nil {
+				log.Printf("auth.revocation: failed to cache revoked token: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether any of the given jtis has been revoked. A Redis
+// hit answers directly; a miss is not proof of validity because the cache may
+// have been flushed or never populated, so the database decides in a single
+// query and revoked entries found there are written back to the cache.
+func (s *RevocationStore) IsRevoked(ctx context.Context, jtis ...string) (bool, error) {
+	if len(jtis) == 0 {
+		return false, nil
+	}
+
+	cacheAvailable := s.cache != nil
+	if cacheAvailable {
+		for _, jti := range jtis {
+			data, err := s.cache.Get(ctx, revokedTokenPrefix+jti)
+			if err != nil {
+				log.Printf("auth.revocation: redis unavailable, falling back to database: %v", err)
+				cacheAvailable = false
+				break
+			}
+			if data != nil {
+				return true, nil
+			}
+		}
+	}
+
+	args := make([]interface{}, len(jtis))
+	for i, jti := range jtis {
+		args[i] = jti
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT Jti, Expires_At FROM Revoked_Tokens
+		WHERE Jti IN (?`+strings.Repeat(", ?", len(jtis)-1)+`)
+	`, args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	revoked := false
+	for rows.Next() {
+		var (
+			jti       string
+			expiresAt time.Time
+		)
+		if err := rows.Scan(&jti, &expiresAt); err != nil {
+			return false, err
+		}
+		revoked = true
+
+		if cacheAvailable {
+			if ttl := time.Until(expiresAt); ttl > 0 {
+				if err := s.cache.Set(ctx, revokedTokenPrefix+jti, "1", ttl); err != nil {
+					log.Printf("auth.revocation: failed to cache revoked token: %v", err)
+				}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+func (s *RevocationStore) isRevokedInDB(ctx context.Context, jti string) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(1) FROM Revoked_Tokens WHERE Jti = ?
+	`, jti).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes blacklist entries whose tokens have expired anyway.
+func (s *RevocationStore) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM Revoked_Tokens WHERE Expires_At < ?
+	`, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// StartCleanup periodically deletes expired blacklist entries until ctx is cancelled.
+func (s *RevocationStore) StartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("auth.revocation: cleanup failed: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("auth.revocation: removed %d expired revoked tokens", removed)
+			}
+		}
+	}
+}
+
+func checkRevoked(claims *Claims) error {
+	if revocations == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), revocationCheckTimeout)
+	defer cancel()
+
+	keys := []string{userRevocationKey(claims.UserID)}
+	if claims.ID != "" {
+		keys = append(keys, claims.ID)
+	}
+	revoked, err := revocations.IsRevoked(ctx, keys...)
+	if err != nil {
+		// Without an answer the token may have been revoked, so reject it
+		log.Printf("auth.revocation: failed to check tokens of user %d: %v", claims.UserID, err)
+		return fmt.Errorf("%w: %v", ErrRevocationUnavailable, err)
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errors.New("redis down")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return errors.New("redis down")
+}
+
+func setupRevocationTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file:test_revocation?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	schema := `
+CREATE TABLE Revoked_Tokens (
+    Jti TEXT PRIMARY KEY,
+    User_Id INTEGER NOT NULL,
+    Expires_At DATETIME NOT NULL,
+    Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP TABLE Revoked_Tokens`)
+	})
+
+	return db
+}
+
+func TestValidateTokenRejectsRevokedToken(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	SetRevocationStore(NewRevocationStore(db, failingCache{}))
+	t.Cleanup(func() { SetRevocationStore(nil) })
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("expected token to be valid before logout, got %v", err)
+	}
+	if claims.ID == "" {
+		t.Fatalf("expected jti claim to be set")
+	}
+
+	// Redis is down, so both the write and the check must go through the database
+	if err := RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+
+	if _, err := ValidateToken(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("expected ErrTokenRevoked, got %v", err)
+	}
+}
+
+func TestRevokeUserTokensRejectsEveryTokenOfTheUser(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	SetRevocationStore(NewRevocationStore(db, failingCache{}))
+	t.Cleanup(func() { SetRevocationStore(nil) })
+
+	first, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	second, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	other, err := GenerateToken(2, "bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	if err := RevokeUserTokens(context.Background(), 1); err != nil {
+		t.Fatalf("RevokeUserTokens returned error: %v", err)
+	}
+
+	for _, token := range []string{first, second} {
+		if _, err := ValidateToken(token); !errors.Is(err, ErrTokenRevoked) {
+			t.Fatalf("expected ErrTokenRevoked, got %v", err)
+		}
+	}
+	if _, err := ValidateToken(other); err != nil {
+		t.Fatalf("expected another user's token to stay valid, got %v", err)
+	}
+}
+
+func TestDeleteExpiredRemovesOldEntries(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	store := NewRevocationStore(db, nil)
+	ctx := context.Background()
+
+	if err := store.Revoke(ctx, "old", 1, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if err := store.Revoke(ctx, "current", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	removed, err := store.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpired returned error: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 expired entry removed, got %d", removed)
+	}
+	if revoked, _ := store.IsRevoked(ctx, "current"); !revoked {
+		t.Fatalf("expected unexpired entry to remain revoked")
+	}
+}
+
+// memoryCache behaves like the Redis client: a missing key is (nil, nil)
+type memoryCache struct {
+	entries map[string][]byte
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.entries[key], nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.entries[key] = []byte(value.(string))
+	return nil
+}
+
+func TestIsRevokedChecksDatabaseOnCacheMiss(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	ctx := context.Background()
+
+	// The row exists but the cache never saw it, e.g. after a Redis flush
+	if err := NewRevocationStore(db, nil).Revoke(ctx, "flushed", 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	cache := &memoryCache{entries: map[string][]byte{}}
+	store := NewRevocationStore(db, cache)
+
+	revoked, err := store.IsRevoked(ctx, "flushed")
+	if err != nil {
+		t.Fatalf("IsRevoked returned error: %v", err)
+	}
+	if !revoked {
+		t.Fatalf("expected a token revoked in the database to stay revoked on a cache miss")
+	}
+	if cache.entries[revokedTokenPrefix+"flushed"] == nil {
+		t.Fatalf("expected the revocation to be written back to the cache")
+	}
+
+	if revoked, err := store.IsRevoked(ctx, "never-revoked"); err != nil || revoked {
+		t.Fatalf("expected an unknown jti to be valid, got revoked=%v err=%v", revoked, err)
+	}
+}
+
+func TestValidateTokenFailsClosedWhenRevocationLookupFails(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	SetRevocationStore(NewRevocationStore(db, failingCache{}))
+	t.Cleanup(func() { SetRevocationStore(nil) })
+
+	token, err := GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	if _, err := ValidateToken(token); err != nil {
+		t.Fatalf("expected token to be valid while the database answers, got %v", err)
+	}
+
+	// Neither Redis nor the database can answer
+	if _, err := db.Exec(`ALTER TABLE Revoked_Tokens RENAME TO Revoked_Tokens_Offline`); err != nil {
+		t.Fatalf("failed to take the table offline: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DROP TABLE Revoked_Tokens_Offline`) })
+
+	if _, err := ValidateToken(token); !errors.Is(err, ErrRevocationUnavailable) {
+		t.Fatalf("expected ErrRevocationUnavailable, got %v", err)
+	}
+}
+
+func TestIsRevokedMatchesAnyKey(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	ctx := context.Background()
+	cache := &memoryCache{entries: map[string][]byte{}}
+	store := NewRevocationStore(db, cache)
+
+	if err := NewRevocationStore(db, nil).Revoke(ctx, userRevocationKey(1), 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+
+	revoked, err := store.IsRevoked(ctx, userRevocationKey(1), "some-jti")
+	if err != nil || !revoked {
+		t.Fatalf("expected the user-level entry to revoke the token, got revoked=%v err=%v", revoked, err)
+	}
+	if cache.entries[revokedTokenPrefix+userRevocationKey(1)] == nil {
+		t.Fatalf("expected the matching entry to be written back to the cache")
+	}
+	if cache.entries[revokedTokenPrefix+"some-jti"] != nil {
+		t.Fatalf("expected only revoked keys to be cached")
+	}
+
+	if revoked, err := store.IsRevoked(ctx, userRevocationKey(2), "other-jti"); err != nil || revoked {
+		t.Fatalf("expected another user's token to be valid, got revoked=%v err=%v", revoked, err)
+	}
+}
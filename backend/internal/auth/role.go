@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+)
+
+// Roles carried in the role claim
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// LookupRole returns the role stored for a user. Lookup failures fall back to
+// RoleUser so that a missing column never grants more access.
+func LookupRole(ctx context.Context, db *sql.DB, userID int64) string {
+	if db == nil {
+		return RoleUser
+	}
+	var role sql.NullString
+	err := db.QueryRowContext(ctx, `SELECT role FROM users WHERE id = ?`, userID).Scan(&role)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("auth.LookupRole: user_id=%d err=%v", userID, err)
+		}
+		return RoleUser
+	}
+	if r := strings.TrimSpace(role.String); r != "" {
+		return strings.ToLower(r)
+	}
+	return RoleUser
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// probeTimeout bounds each reconnect attempt
+	probeTimeout = 2 * time.Second
+	// allMangaKeys matches every key written by MangaCache
+	allMangaKeys = "manga:*"
+)
+
+// Available reports whether the cache is talking to Redis. While it is false
+// every read is a miss and writes are skipped, so callers fall through to the
+// database.
+func (c *MangaCache) Available() bool {
+	return c.available.Load()
+}
+
+// markUnavailable stops using Redis until the reconnect probe succeeds
+func (c *MangaCache) markUnavailable(err error) {
+	if c.available.CompareAndSwap(true, false) {
+		log.Printf("Warning: Redis cache unavailable: %v. Serving from the database until it recovers.", err)
+	}
+}
+
+// lookup reads a key and returns nil on a miss. Redis errors are logged and
+// treated as misses so a cache outage never fails a request.
+func (c *MangaCache) lookup(ctx context.Context, kind, key string) []byte {
+	if !c.Available() {
+		c.recordLookup(kind, nil, nil)
+		return nil
+	}
+	data, err := c.client.Get(ctx, key)
+	c.recordLookup(kind, data, err)
+	if err != nil {
+		c.markUnavailable(err)
+		return nil
+	}
+	return data
+}
+
+// store writes a value, skipping it while Redis is unavailable. Only values
+// that cannot be encoded are reported.
+func (c *MangaCache) store(ctx context.Context, kind, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.recordSet(kind, err)
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	if !c.Available() {
+		return nil
+	}
+	err = c.client.Set(ctx, key, string(data), expiration)
+	c.recordSet(kind, err)
+	if err != nil {
+		c.markUnavailable(err)
+	}
+	return nil
+}
+
+// invalidate deletes a key. Deletes that cannot reach Redis are dropped: the
+// probe flushes every manga key before caching resumes.
+func (c *MangaCache) invalidate(ctx context.Context, key string) error {
+	if !c.Available() {
+		return nil
+	}
+	if err := c.client.Delete(ctx, key); err != nil {
+		c.markUnavailable(err)
+	}
+	return nil
+}
+
+// invalidatePattern is invalidate for every key matching pattern
+func (c *MangaCache) invalidatePattern(ctx context.Context, pattern string) error {
+	if !c.Available() {
+		return nil
+	}
+	if err := c.client.DeletePattern(ctx, pattern); err != nil {
+		c.markUnavailable(err)
+	}
+	return nil
+}
+
+// StartReconnectProbe pings Redis every interval while the cache is
+// unavailable and resumes caching once it answers. Invalidations were lost
+// during the outage, so all manga keys are flushed first. It returns when
+// ctx is cancelled.
+func (c *MangaCache) StartReconnectProbe(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !c.Available() {
+				c.probe(ctx)
+			}
+		}
+	}
+}
+
+func (c *MangaCache) probe(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	if err := c.client.Ping(probeCtx); err != nil {
+		return
+	}
+	if err := c.client.DeletePattern(probeCtx, allMangaKeys); err != nil {
+		log.Printf("Warning: Redis reachable but flushing stale manga keys failed: %v", err)
+		return
+	}
+	c.available.Store(true)
+	log.Println("Redis cache reconnected; caching resumed")
+}
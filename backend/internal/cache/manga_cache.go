@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
@@ -14,33 +15,144 @@ const (
 	mangaDetailPrefix  = "manga:detail:"
 	mangaSearchPrefix  = "manga:search:"
 	popularMangaPrefix = "manga:popular:"
+	recommendedPrefix  = "manga:recommended:"
+	similarMangaPrefix = "manga:similar:"
+	trendingPrefix     = "manga:trending:"
+	tagListKey         = "manga:tags"
+	// Suggestions live under the search prefix so InvalidateSearch drops them too
+	suggestPrefix = mangaSearchPrefix + "suggest:"
 
 	// Cache expiration times
 	mangaDetailExpiration  = 1 * time.Hour    // Manga details cached for 1 hour
-	mangaSearchExpiration  = 30 * time.Minute // Search results cached for 30 minutes
-	popularMangaExpiration = 15 * time.Minute // Popular manga cached for 15 minutes
+	mangaSearchExpiration  = 5 * time.Minute  // Search results cached for 5 minutes
+	popularMangaExpiration = 15 * time.Minute // All-time popular manga cached for 15 minutes
+	recommendedExpiration  = 10 * time.Minute // Per-user recommendations cached for 10 minutes
+	similarMangaExpiration = 1 * time.Hour    // Similar manga cached for 1 hour
+	tagListExpiration      = 6 * time.Hour    // Tag list changes rarely and is invalidated on writes
+	suggestExpiration      = 10 * time.Minute // Title suggestions cached for 10 minutes
 )
 
+// popularPeriodExpiration keeps short windows fresher than the all-time list
+var popularPeriodExpiration = map[string]time.Duration{
+	manga.PopularPeriodDay:   2 * time.Minute,
+	manga.PopularPeriodWeek:  5 * time.Minute,
+	manga.PopularPeriodMonth: 10 * time.Minute,
+	manga.PopularPeriodAll:   popularMangaExpiration,
+}
+
+// Cache key types reported by Stats
+const (
+	KeyTypeDetails         = "details"
+	KeyTypeSearch          = "search"
+	KeyTypePopular         = "popular"
+	KeyTypeRecommendations = "recommendations"
+	KeyTypeSimilar         = "similar"
+	KeyTypeTrending        = "trending"
+	KeyTypeTags            = "tags"
+	KeyTypeSuggestions     = "suggestions"
+)
+
+var keyTypes = []string{KeyTypeDetails, KeyTypeSearch, KeyTypePopular, KeyTypeRecommendations, KeyTypeSimilar, KeyTypeTrending, KeyTypeTags, KeyTypeSuggestions}
+
 // MangaCache provides caching for manga data
 type MangaCache struct {
 	client *Client
+
+	// counters is keyed by key type and never modified after construction
+	counters map[string]*keyTypeCounters
+
+	// available is false after a Redis error until the reconnect probe
+	// reaches Redis again; see StartReconnectProbe
+	available atomic.Bool
+}
+
+type keyTypeCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	sets   atomic.Uint64
+	errors atomic.Uint64
+}
+
+// KeyTypeStats reports cache activity for one kind of key
+type KeyTypeStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Sets   uint64 `json:"sets"`
+	Errors uint64 `json:"errors"`
+}
+
+// CacheStats reports how often cached reads were served from Redis. Hits,
+// Misses and Errors are totals over all key types.
+type CacheStats struct {
+	Available bool                    `json:"available"`
+	Hits      uint64                  `json:"hits"`
+	Misses    uint64                  `json:"misses"`
+	Errors    uint64                  `json:"errors"`
+	HitRatio  float64                 `json:"hit_ratio"`
+	ByKeyType map[string]KeyTypeStats `json:"by_key_type"`
+}
+
+// Stats returns the hit, miss and error counters since startup
+func (c *MangaCache) Stats() CacheStats {
+	stats := CacheStats{Available: c.Available(), ByKeyType: make(map[string]KeyTypeStats, len(c.counters))}
+	for kind, counters := range c.counters {
+		kt := KeyTypeStats{
+			Hits:   counters.hits.Load(),
+			Misses: counters.misses.Load(),
+			Sets:   counters.sets.Load(),
+			Errors: counters.errors.Load(),
+		}
+		stats.ByKeyType[kind] = kt
+		stats.Hits += kt.Hits
+		stats.Misses += kt.Misses
+		stats.Errors += kt.Errors
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// recordLookup counts a read; Redis errors are counted as errors, not misses
+func (c *MangaCache) recordLookup(kind string, data []byte, err error) {
+	counters := c.counters[kind]
+	switch {
+	case err != nil:
+		counters.errors.Add(1)
+	case data == nil:
+		counters.misses.Add(1)
+	default:
+		counters.hits.Add(1)
+	}
+}
+
+// recordSet counts a write
+func (c *MangaCache) recordSet(kind string, err error) {
+	if err != nil {
+		c.counters[kind].errors.Add(1)
+		return
+	}
+	c.counters[kind].sets.Add(1)
 }
 
 // NewMangaCache creates a new manga cache
 func NewMangaCache(client *Client) *MangaCache {
-	return &MangaCache{client: client}
+	counters := make(map[string]*keyTypeCounters, len(keyTypes))
+	for _, kind := range keyTypes {
+		counters[kind] = &keyTypeCounters{}
+	}
+	c := &MangaCache{client: client, counters: counters}
+	c.available.Store(true)
+	return c
 }
 
 // GetMangaDetail retrieves manga detail from cache
 func (c *MangaCache) GetMangaDetail(ctx context.Context, mangaID int64) (*manga.MangaDetail, error) {
 	key := fmt.Sprintf("%s%d", mangaDetailPrefix, mangaID)
 
-	data, err := c.client.Get(ctx, key)
-	if err != nil {
-		return nil, err
-	}
+	data := c.lookup(ctx, KeyTypeDetails, key)
 	if data == nil {
-		return nil, nil // Not in cache
+		return nil, nil
 	}
 
 	var detail manga.MangaDetail
@@ -56,32 +168,39 @@ func (c *MangaCache) GetMangaDetail(ctx context.Context, mangaID int64) (*manga.
 // Step 3: System sets appropriate cache expiration times
 func (c *MangaCache) SetMangaDetail(ctx context.Context, mangaID int64, detail *manga.MangaDetail) error {
 	key := fmt.Sprintf("%s%d", mangaDetailPrefix, mangaID)
-	return c.client.Set(ctx, key, detail, mangaDetailExpiration)
+	return c.store(ctx, KeyTypeDetails, key, detail, mangaDetailExpiration)
 }
 
 // InvalidateMangaDetail removes manga detail from cache
 // Step 5: System updates cache when data changes
 func (c *MangaCache) InvalidateMangaDetail(ctx context.Context, mangaID int64) error {
 	key := fmt.Sprintf("%s%d", mangaDetailPrefix, mangaID)
-	return c.client.Delete(ctx, key)
+	return c.invalidate(ctx, key)
+}
+
+// InvalidateManga drops every cached entry derived from one manga: its detail
+// and its similar-manga lists. The detail key is deleted directly; similar
+// lists share the "manga:similar:<id>:" prefix so a single scan finds them.
+func (c *MangaCache) InvalidateManga(ctx context.Context, mangaID int64) error {
+	if err := c.InvalidateMangaDetail(ctx, mangaID); err != nil {
+		return err
+	}
+	return c.invalidatePattern(ctx, fmt.Sprintf("%s%d:*", similarMangaPrefix, mangaID))
 }
 
 // InvalidateAllMangaDetails removes all manga detail caches
 func (c *MangaCache) InvalidateAllMangaDetails(ctx context.Context) error {
 	pattern := fmt.Sprintf("%s*", mangaDetailPrefix)
-	return c.client.DeletePattern(ctx, pattern)
+	return c.invalidatePattern(ctx, pattern)
 }
 
 // GetSearchResults retrieves search results from cache
 func (c *MangaCache) GetSearchResults(ctx context.Context, cacheKey string) (*manga.SearchResponse, error) {
 	key := fmt.Sprintf("%s%s", mangaSearchPrefix, cacheKey)
 
-	data, err := c.client.Get(ctx, key)
-	if err != nil {
-		return nil, err
-	}
+	data := c.lookup(ctx, KeyTypeSearch, key)
 	if data == nil {
-		return nil, nil // Not in cache
+		return nil, nil
 	}
 
 	var response manga.SearchResponse
@@ -95,67 +214,182 @@ func (c *MangaCache) GetSearchResults(ctx context.Context, cacheKey string) (*ma
 // SetSearchResults stores search results in cache
 func (c *MangaCache) SetSearchResults(ctx context.Context, cacheKey string, response *manga.SearchResponse) error {
 	key := fmt.Sprintf("%s%s", mangaSearchPrefix, cacheKey)
-	return c.client.Set(ctx, key, response, mangaSearchExpiration)
+	return c.store(ctx, KeyTypeSearch, key, response, mangaSearchExpiration)
+}
+
+// InvalidateSearch removes every cached search result
+func (c *MangaCache) InvalidateSearch(ctx context.Context) error {
+	return c.invalidatePattern(ctx, mangaSearchPrefix+"*")
 }
 
-// GetPopularManga retrieves cached popular manga lists
+// GetPopularManga retrieves a cached popular manga page for a period
 // Step 4: Subsequent requests serve data from cache
-func (c *MangaCache) GetPopularManga(ctx context.Context, limit int) ([]manga.Manga, error) {
-	key := fmt.Sprintf("%s%d", popularMangaPrefix, limit)
+func (c *MangaCache) GetPopularManga(ctx context.Context, period string, page, limit int) (*manga.PopularMangaResponse, error) {
+	key := popularMangaKey(period, page, limit)
 
-	data, err := c.client.Get(ctx, key)
-	if err != nil {
-		return nil, err
-	}
+	data := c.lookup(ctx, KeyTypePopular, key)
 	if data == nil {
 		return nil, nil
 	}
 
-	var popular []manga.Manga
+	var popular manga.PopularMangaResponse
 	if err := json.Unmarshal(data, &popular); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal popular manga: %w", err)
 	}
 
-	return popular, nil
+	return &popular, nil
 }
 
-// SetPopularManga caches the popular manga list with expiration
+// SetPopularManga caches a popular manga page with a period-specific expiration
 // Step 2: System stores manga details in Redis cache
 // Step 3: System sets appropriate cache expiration times
-func (c *MangaCache) SetPopularManga(ctx context.Context, limit int, popular []manga.Manga) error {
-	key := fmt.Sprintf("%s%d", popularMangaPrefix, limit)
-	return c.client.Set(ctx, key, popular, popularMangaExpiration)
+func (c *MangaCache) SetPopularManga(ctx context.Context, period string, page, limit int, popular *manga.PopularMangaResponse) error {
+	expiration, ok := popularPeriodExpiration[period]
+	if !ok {
+		expiration = popularMangaExpiration
+	}
+	return c.store(ctx, KeyTypePopular, popularMangaKey(period, page, limit), popular, expiration)
 }
 
-// InvalidatePopularManga removes cached popular manga lists
-// Step 5: System updates cache when data changes
-func (c *MangaCache) InvalidatePopularManga(ctx context.Context) error {
-	pattern := fmt.Sprintf("%s*", popularMangaPrefix)
-	return c.client.DeletePattern(ctx, pattern)
+func popularMangaKey(period string, page, limit int) string {
+	return fmt.Sprintf("%s%s:page:%d:limit:%d", popularMangaPrefix, period, page, limit)
 }
 
-// GenerateSearchCacheKey generates a cache key for search request
-func GenerateSearchCacheKey(req manga.SearchRequest) string {
-	// Create a unique key based on search parameters
-	key := fmt.Sprintf("q:%s", req.Query)
-	if len(req.Genres) > 0 {
-		key += fmt.Sprintf(":genres:%v", req.Genres)
+// GetTrendingManga retrieves cached trending manga for a window
+func (c *MangaCache) GetTrendingManga(ctx context.Context, window string, limit int) (*manga.TrendingMangaResponse, error) {
+	data := c.lookup(ctx, KeyTypeTrending, trendingMangaKey(window, limit))
+	if data == nil {
+		return nil, nil
 	}
-	if req.Status != "" {
-		key += fmt.Sprintf(":status:%s", req.Status)
+
+	var trending manga.TrendingMangaResponse
+	if err := json.Unmarshal(data, &trending); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trending manga: %w", err)
 	}
-	if req.MinRating != nil {
-		key += fmt.Sprintf(":min_rating:%.1f", *req.MinRating)
+
+	return &trending, nil
+}
+
+// SetTrendingManga caches trending manga for a window; shorter windows
+// expire sooner, like the popular lists
+func (c *MangaCache) SetTrendingManga(ctx context.Context, window string, limit int, trending *manga.TrendingMangaResponse) error {
+	expiration, ok := popularPeriodExpiration[window]
+	if !ok {
+		expiration = popularMangaExpiration
 	}
-	if req.MaxRating != nil {
-		key += fmt.Sprintf(":max_rating:%.1f", *req.MaxRating)
+	return c.store(ctx, KeyTypeTrending, trendingMangaKey(window, limit), trending, expiration)
+}
+
+func trendingMangaKey(window string, limit int) string {
+	return fmt.Sprintf("%s%s:limit:%d", trendingPrefix, window, limit)
+}
+
+// GetSuggestions retrieves cached title suggestions for a prefix
+func (c *MangaCache) GetSuggestions(ctx context.Context, prefix string, limit int) (*manga.SuggestResponse, error) {
+	data := c.lookup(ctx, KeyTypeSuggestions, suggestionsKey(prefix, limit))
+	if data == nil {
+		return nil, nil
+	}
+
+	var suggestions manga.SuggestResponse
+	if err := json.Unmarshal(data, &suggestions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suggestions: %w", err)
+	}
+
+	return &suggestions, nil
+}
+
+// SetSuggestions caches title suggestions for a prefix
+func (c *MangaCache) SetSuggestions(ctx context.Context, prefix string, limit int, suggestions *manga.SuggestResponse) error {
+	return c.store(ctx, KeyTypeSuggestions, suggestionsKey(prefix, limit), suggestions, suggestExpiration)
+}
+
+func suggestionsKey(prefix string, limit int) string {
+	return fmt.Sprintf("%s%d:%s", suggestPrefix, limit, prefix)
+}
+
+// GetTags retrieves the cached tag list
+func (c *MangaCache) GetTags(ctx context.Context) (*manga.TagListResponse, error) {
+	data := c.lookup(ctx, KeyTypeTags, tagListKey)
+	if data == nil {
+		return nil, nil
+	}
+
+	var tags manga.TagListResponse
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	return &tags, nil
+}
+
+// SetTags stores the tag list
+func (c *MangaCache) SetTags(ctx context.Context, tags *manga.TagListResponse) error {
+	return c.store(ctx, KeyTypeTags, tagListKey, tags, tagListExpiration)
+}
+
+// InvalidateTags removes the cached tag list
+func (c *MangaCache) InvalidateTags(ctx context.Context) error {
+	return c.invalidate(ctx, tagListKey)
+}
+
+// InvalidatePopular removes cached popular manga lists for every period
+// Step 5: System updates cache when data changes
+func (c *MangaCache) InvalidatePopular(ctx context.Context) error {
+	pattern := fmt.Sprintf("%s*", popularMangaPrefix)
+	return c.invalidatePattern(ctx, pattern)
+}
+
+// GetRecommendations retrieves a user's cached recommendations
+func (c *MangaCache) GetRecommendations(ctx context.Context, userID int64, limit int) (*manga.RecommendationsResponse, error) {
+	data := c.lookup(ctx, KeyTypeRecommendations, recommendationsKey(userID, limit))
+	if data == nil {
+		return nil, nil
+	}
+
+	var recommendations manga.RecommendationsResponse
+	if err := json.Unmarshal(data, &recommendations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recommendations: %w", err)
 	}
-	if req.YearFrom != nil {
-		key += fmt.Sprintf(":year_from:%d", *req.YearFrom)
+
+	return &recommendations, nil
+}
+
+// SetRecommendations caches a user's recommendations briefly so library
+// changes show up soon
+func (c *MangaCache) SetRecommendations(ctx context.Context, userID int64, limit int, recommendations *manga.RecommendationsResponse) error {
+	return c.store(ctx, KeyTypeRecommendations, recommendationsKey(userID, limit), recommendations, recommendedExpiration)
+}
+
+func recommendationsKey(userID int64, limit int) string {
+	return fmt.Sprintf("%s%d:limit:%d", recommendedPrefix, userID, limit)
+}
+
+// GetSimilarManga retrieves cached similar manga for a manga
+func (c *MangaCache) GetSimilarManga(ctx context.Context, mangaID int64, limit int) (*manga.SimilarMangaResponse, error) {
+	data := c.lookup(ctx, KeyTypeSimilar, similarMangaKey(mangaID, limit))
+	if data == nil {
+		return nil, nil
 	}
-	if req.YearTo != nil {
-		key += fmt.Sprintf(":year_to:%d", *req.YearTo)
+
+	var similar manga.SimilarMangaResponse
+	if err := json.Unmarshal(data, &similar); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal similar manga: %w", err)
 	}
-	key += fmt.Sprintf(":page:%d:limit:%d:sort:%s", req.Page, req.Limit, req.SortBy)
-	return key
+
+	return &similar, nil
+}
+
+// SetSimilarManga stores similar manga for a manga
+func (c *MangaCache) SetSimilarManga(ctx context.Context, mangaID int64, limit int, similar *manga.SimilarMangaResponse) error {
+	return c.store(ctx, KeyTypeSimilar, similarMangaKey(mangaID, limit), similar, similarMangaExpiration)
+}
+
+func similarMangaKey(mangaID int64, limit int) string {
+	return fmt.Sprintf("%s%d:limit:%d", similarMangaPrefix, mangaID, limit)
+}
+
+// GenerateSearchCacheKey generates a cache key for search request
+func GenerateSearchCacheKey(req manga.SearchRequest) string {
+	return manga.GenerateSearchCacheKey(req)
 }
@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+)
+
+func newTestMangaCache(t *testing.T) (*MangaCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client, err := NewClient(mr.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewMangaCache(client), mr
+}
+
+func setupMangaDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        slug TEXT NOT NULL UNIQUE,
+        title TEXT NOT NULL,
+        alt_title TEXT,
+        cover_url TEXT,
+        author TEXT,
+        artist TEXT,
+        status TEXT NOT NULL DEFAULT 'ongoing',
+        synopsis TEXT,
+        language TEXT,
+        last_chapter INTEGER,
+        rating_average REAL NOT NULL DEFAULT 0,
+        rating_count INTEGER NOT NULL DEFAULT 0,
+        views INTEGER NOT NULL DEFAULT 0,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        deleted_at DATETIME
+    );
+    CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE);
+    CREATE TABLE manga_tags (manga_id INTEGER NOT NULL, tag_id INTEGER NOT NULL);
+    INSERT INTO mangas (id, slug, title, rating_average, rating_count) VALUES (1, 'berserk', 'Berserk', 4.0, 10);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestRatingChangeServesFreshMangaDetail(t *testing.T) {
+	db := setupMangaDB(t)
+	mangaCache, _ := newTestMangaCache(t)
+	svc := manga.NewService(db)
+	svc.SetCache(mangaCache)
+	ctx := context.Background()
+
+	if _, err := svc.GetDetails(ctx, 1, nil); err != nil {
+		t.Fatalf("GetDetails returned error: %v", err)
+	}
+	if cached, _ := mangaCache.GetMangaDetail(ctx, 1); cached == nil {
+		t.Fatal("expected manga detail to be cached after the first read")
+	}
+
+	if _, err := db.Exec(`UPDATE mangas SET rating_average = 4.5, rating_count = 11 WHERE id = 1`); err != nil {
+		t.Fatalf("failed to update manga: %v", err)
+	}
+	svc.RatingChanged(ctx, 1)
+
+	detail, err := svc.GetDetails(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetDetails returned error: %v", err)
+	}
+	if detail.RatingPoint != 4.5 {
+		t.Fatalf("expected fresh rating 4.5, got %v", detail.RatingPoint)
+	}
+}
+
+func TestInvalidateMangaTargetsOneManga(t *testing.T) {
+	mangaCache, mr := newTestMangaCache(t)
+	ctx := context.Background()
+
+	for _, id := range []int64{1, 2} {
+		if err := mangaCache.SetMangaDetail(ctx, id, &manga.MangaDetail{}); err != nil {
+			t.Fatalf("SetMangaDetail returned error: %v", err)
+		}
+		if err := mangaCache.SetSimilarManga(ctx, id, 5, &manga.SimilarMangaResponse{}); err != nil {
+			t.Fatalf("SetSimilarManga returned error: %v", err)
+		}
+	}
+	if err := mangaCache.SetPopularManga(ctx, manga.PopularPeriodWeek, 1, 10, &manga.PopularMangaResponse{}); err != nil {
+		t.Fatalf("SetPopularManga returned error: %v", err)
+	}
+
+	if err := mangaCache.InvalidateManga(ctx, 1); err != nil {
+		t.Fatalf("InvalidateManga returned error: %v", err)
+	}
+	if mr.Exists(mangaDetailPrefix+"1") || mr.Exists(similarMangaKey(1, 5)) {
+		t.Fatal("expected manga 1 entries to be removed")
+	}
+	if !mr.Exists(mangaDetailPrefix+"2") || !mr.Exists(similarMangaKey(2, 5)) {
+		t.Fatal("expected manga 2 entries to be kept")
+	}
+
+	if err := mangaCache.InvalidatePopular(ctx); err != nil {
+		t.Fatalf("InvalidatePopular returned error: %v", err)
+	}
+	if mr.Exists(popularMangaKey(manga.PopularPeriodWeek, 1, 10)) {
+		t.Fatal("expected popular lists to be removed")
+	}
+}
+
+func TestStatsCountMissThenHitPerKeyType(t *testing.T) {
+	mangaCache, mr := newTestMangaCache(t)
+	ctx := context.Background()
+
+	if detail, err := mangaCache.GetMangaDetail(ctx, 7); err != nil || detail != nil {
+		t.Fatalf("expected a miss, got detail=%v err=%v", detail, err)
+	}
+	if err := mangaCache.SetMangaDetail(ctx, 7, &manga.MangaDetail{}); err != nil {
+		t.Fatalf("SetMangaDetail returned error: %v", err)
+	}
+	if detail, err := mangaCache.GetMangaDetail(ctx, 7); err != nil || detail == nil {
+		t.Fatalf("expected a hit, got detail=%v err=%v", detail, err)
+	}
+	if _, err := mangaCache.GetPopularManga(ctx, manga.PopularPeriodAll, 1, 10); err != nil {
+		t.Fatalf("GetPopularManga returned error: %v", err)
+	}
+
+	stats := mangaCache.Stats()
+	details := stats.ByKeyType[KeyTypeDetails]
+	if details.Hits != 1 || details.Misses != 1 || details.Sets != 1 || details.Errors != 0 {
+		t.Fatalf("unexpected details counters: %+v", details)
+	}
+	if popular := stats.ByKeyType[KeyTypePopular]; popular.Hits != 0 || popular.Misses != 1 {
+		t.Fatalf("unexpected popular counters: %+v", popular)
+	}
+	if search := stats.ByKeyType[KeyTypeSearch]; search != (KeyTypeStats{}) {
+		t.Fatalf("expected untouched search counters, got %+v", search)
+	}
+	if stats.Hits != 1 || stats.Misses != 2 || stats.HitRatio != 1.0/3.0 {
+		t.Fatalf("unexpected totals: hits=%d misses=%d ratio=%v", stats.Hits, stats.Misses, stats.HitRatio)
+	}
+
+	mr.Close()
+	if cached, err := mangaCache.GetSearchResults(ctx, "q:berserk"); err != nil || cached != nil {
+		t.Fatalf("expected a miss with Redis down, got cached=%v err=%v", cached, err)
+	}
+	if search := mangaCache.Stats().ByKeyType[KeyTypeSearch]; search.Errors != 1 || search.Misses != 0 {
+		t.Fatalf("expected the failed lookup to count as an error, got %+v", search)
+	}
+}
+
+func TestRedisOutageFallsBackToDatabaseAndRecovers(t *testing.T) {
+	db := setupMangaDB(t)
+	mangaCache, mr := newTestMangaCache(t)
+	svc := manga.NewService(db)
+	svc.SetCache(mangaCache)
+	ctx := context.Background()
+
+	if _, err := svc.GetDetails(ctx, 1, nil); err != nil {
+		t.Fatalf("GetDetails returned error: %v", err)
+	}
+
+	// Redis starts failing after startup; the invalidation is lost with it
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if _, err := db.Exec(`UPDATE mangas SET rating_average = 4.5, rating_count = 11 WHERE id = 1`); err != nil {
+		t.Fatalf("failed to update manga: %v", err)
+	}
+	svc.RatingChanged(ctx, 1)
+
+	detail, err := svc.GetDetails(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("expected the detail to be served from the database, got %v", err)
+	}
+	if detail.Title != "Berserk" || detail.RatingPoint != 4.5 {
+		t.Fatalf("expected fresh database data, got %+v", detail)
+	}
+	if mangaCache.Available() || mangaCache.Stats().Available {
+		t.Fatal("expected the cache to report Redis as unavailable")
+	}
+
+	probeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go mangaCache.StartReconnectProbe(probeCtx, 10*time.Millisecond)
+	mr.SetError("")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !mangaCache.Available() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected caching to resume once Redis recovered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if mr.Exists(mangaDetailPrefix + "1") {
+		t.Fatal("expected the detail cached before the outage to be flushed on recovery")
+	}
+
+	if _, err := svc.GetDetails(ctx, 1, nil); err != nil {
+		t.Fatalf("GetDetails returned error: %v", err)
+	}
+	cached, err := mangaCache.GetMangaDetail(ctx, 1)
+	if err != nil || cached == nil || cached.RatingPoint != 4.5 {
+		t.Fatalf("expected the fresh detail to be cached again, got %+v (err %v)", cached, err)
+	}
+}
+
+func TestSearchCacheMatchesStructurallyIdenticalRequests(t *testing.T) {
+	db := setupMangaDB(t)
+	if _, err := db.Exec(`
+        INSERT INTO tags (id, name) VALUES (1, 'Action'), (2, 'Drama');
+        INSERT INTO manga_tags (manga_id, tag_id) VALUES (1, 1), (1, 2);
+    `); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+	mangaCache, _ := newTestMangaCache(t)
+	svc := manga.NewService(db)
+	svc.SetCache(mangaCache)
+	ctx := context.Background()
+
+	minA, minB := 3.0, 3.04
+	first := manga.SearchRequest{Genres: []string{"Drama", "Action"}, MinRating: &minA, Page: 1, Limit: 10}
+	second := manga.SearchRequest{Genres: []string{"Action", "Drama", "Action"}, MinRating: &minB, Page: 1, Limit: 10}
+	if manga.GenerateSearchCacheKey(first) != manga.GenerateSearchCacheKey(second) {
+		t.Fatal("expected identical fingerprints for equivalent requests")
+	}
+
+	if resp, err := svc.Search(ctx, first); err != nil || resp.Total != 1 {
+		t.Fatalf("Search returned resp=%+v err=%v", resp, err)
+	}
+	if _, err := svc.Search(ctx, second); err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	search := mangaCache.Stats().ByKeyType[KeyTypeSearch]
+	if search.Misses != 1 || search.Hits != 1 {
+		t.Fatalf("expected the second search to hit the cache, got %+v", search)
+	}
+
+	otherPage := second
+	otherPage.Page = 2
+	if _, err := svc.Search(ctx, otherPage); err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if search := mangaCache.Stats().ByKeyType[KeyTypeSearch]; search.Misses != 2 || search.Hits != 1 {
+		t.Fatalf("expected a different page to miss, got %+v", search)
+	}
+
+	if _, err := svc.CreateManga(ctx, manga.CreateMangaRequest{Title: "Vagabond", Slug: "vagabond"}); err != nil {
+		t.Fatalf("CreateManga returned error: %v", err)
+	}
+	if _, err := svc.Search(ctx, first); err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if search := mangaCache.Stats().ByKeyType[KeyTypeSearch]; search.Misses != 3 {
+		t.Fatalf("expected creating a manga to bust cached searches, got %+v", search)
+	}
+}
+
+func TestRecordViewDebouncesPerViewerUntilDrained(t *testing.T) {
+	c, mr := newTestMangaCache(t)
+	ctx := context.Background()
+
+	for i, want := range []bool{true, false, false} {
+		counted, err := c.RecordView(ctx, 1, "user:7", time.Minute)
+		if err != nil || counted != want {
+			t.Fatalf("view %d: got counted=%v err=%v, want %v", i, counted, err, want)
+		}
+	}
+	if counted, err := c.RecordView(ctx, 2, "user:7", time.Minute); err != nil || !counted {
+		t.Fatalf("expected a view of another manga to count, got %v (err %v)", counted, err)
+	}
+
+	counts, err := c.DrainViews(ctx)
+	if err != nil {
+		t.Fatalf("DrainViews returned error: %v", err)
+	}
+	if len(counts) != 2 || counts[1] != 1 || counts[2] != 1 {
+		t.Fatalf("unexpected drained counts %v", counts)
+	}
+	if counts, err := c.DrainViews(ctx); err != nil || len(counts) != 0 {
+		t.Fatalf("expected the buffer to be empty after a drain, got %v (err %v)", counts, err)
+	}
+
+	// The viewer counts again once the window has passed
+	mr.FastForward(time.Minute)
+	if counted, err := c.RecordView(ctx, 1, "user:7", time.Minute); err != nil || !counted {
+		t.Fatalf("expected a view after the window to count, got %v (err %v)", counted, err)
+	}
+}
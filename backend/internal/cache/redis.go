@@ -67,18 +67,25 @@ func (c *Client) Delete(ctx context.Context, key string) error {
 	return c.rdb.Del(ctx, key).Err()
 }
 
-// DeletePattern removes all keys matching a pattern
+// DeletePattern removes all keys matching a pattern. It walks the keyspace
+// with SCAN rather than KEYS so large keyspaces do not block Redis.
 func (c *Client) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := c.rdb.Keys(ctx, pattern).Result()
-	if err != nil {
-		return err
-	}
-
-	if len(keys) > 0 {
-		return c.rdb.Del(ctx, keys...).Err()
+	var cursor uint64
+	for {
+		keys, next, err := c.rdb.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
 	}
-
-	return nil
 }
 
 // Exists checks if a key exists in cache
@@ -87,6 +94,16 @@ func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
 	return count > 0, err
 }
 
+// Eval runs a Lua script atomically on the Redis server
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return c.rdb.Eval(ctx, script, keys, args...).Result()
+}
+
+// Ping checks that Redis is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	return c.rdb.Close()
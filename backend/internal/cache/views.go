@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// View keys sit outside manga:* so the reconnect flush keeps unflushed counts
+const (
+	pendingViewsKey = "views:pending"
+	viewSeenPrefix  = "views:seen:"
+)
+
+var errCacheUnavailable = errors.New("redis cache unavailable")
+
+// recordViewScript counts a view only when the viewer's debounce key is new.
+// KEYS[1] is the debounce key, KEYS[2] the pending counts hash; ARGV[1] is
+// the window in milliseconds and ARGV[2] the manga ID.
+const recordViewScript = `
+if redis.call('SET', KEYS[1], '1', 'NX', 'PX', ARGV[1]) then
+    redis.call('HINCRBY', KEYS[2], ARGV[2], 1)
+    return 1
+end
+return 0
+`
+
+// drainViewsScript reads and clears the pending counts hash in one step so
+// views recorded meanwhile are never lost
+const drainViewsScript = `
+local counts = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return counts
+`
+
+// RecordView buffers a view of the manga unless the viewer already viewed it
+// within window, and reports whether it was counted. It fails while Redis is
+// unavailable so the caller can buffer the view elsewhere.
+func (c *MangaCache) RecordView(ctx context.Context, mangaID int64, viewer string, window time.Duration) (bool, error) {
+	if !c.Available() {
+		return false, errCacheUnavailable
+	}
+	id := strconv.FormatInt(mangaID, 10)
+	res, err := c.client.Eval(ctx, recordViewScript, []string{viewSeenPrefix + id + ":" + viewer, pendingViewsKey}, window.Milliseconds(), id)
+	if err != nil {
+		c.markUnavailable(err)
+		return false, err
+	}
+	counted, _ := res.(int64)
+	return counted == 1, nil
+}
+
+// DrainViews returns and clears the buffered view counts. While Redis is
+// unavailable nothing is drained; the counts wait for it to recover.
+func (c *MangaCache) DrainViews(ctx context.Context) (map[int64]int64, error) {
+	if !c.Available() {
+		return nil, nil
+	}
+	res, err := c.client.Eval(ctx, drainViewsScript, []string{pendingViewsKey})
+	if err != nil {
+		c.markUnavailable(err)
+		return nil, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values)%2 != 0 {
+		return nil, fmt.Errorf("unexpected drain views script result: %v", res)
+	}
+	counts := make(map[int64]int64, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		field, _ := values[i].(string)
+		value, _ := values[i+1].(string)
+		mangaID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected buffered view key %q", field)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected buffered view count %q", value)
+		}
+		counts[mangaID] = n
+	}
+	return counts, nil
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config contains all application configuration grouped by subsystem.
 type Config struct {
 	App  AppConfig
@@ -7,6 +9,11 @@ type Config struct {
 	GRPC GRPCConfig
 	UDP  UDPConfig
 	Auth AuthConfig
+	Demo DemoConfig
+	// Security sets the input limits applied by the security validators
+	Security SecurityConfig
+	// Pagination sets the default and maximum page size of listings
+	Pagination PaginationConfig
 
 	EnableDemoData bool
 }
@@ -19,17 +26,64 @@ type AppConfig struct {
 	TCPServerAddr  string
 	WSServerAddr   string
 	AllowedOrigins []string
+	WriteQueuePath string
+
+	// TCP sync deadlines: how long a new connection has to authenticate,
+	// how long one read or write may take
+	TCPAuthTimeout  time.Duration
+	TCPReadTimeout  time.Duration
+	TCPWriteTimeout time.Duration
+
+	// MaxConnectionsPerUser caps each user's TCP sync and WebSocket
+	// connections; the oldest is evicted past it and 0 disables the cap
+	MaxConnectionsPerUser int
+
+	// CORS settings; empty lists fall back to the middleware defaults
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSExposedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// AvatarDir stores uploaded user avatars
+	AvatarDir string
+
+	// CoverCacheDir caches manga covers proxied from external CDNs
+	CoverCacheDir string
+
+	// RatingPrior is m in the Bayesian weighted rating: how many votes at
+	// the global mean each manga starts with
+	RatingPrior int
+
+	// RateLimitBackend is "memory" or "redis"
+	RateLimitBackend string
+	// RateLimitTiers is requests per minute by tier: "anonymous" for
+	// requests without a token, otherwise the token's role
+	RateLimitTiers map[string]int
+
+	// RequestTimeout bounds each HTTP request; RouteTimeouts overrides it
+	// for specific route paths such as "/analytics/reading"
+	RequestTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+
+	// StatsRecomputeInterval is how often reading statistics of active
+	// users are refreshed in the background
+	StatsRecomputeInterval time.Duration
 }
 
 type DBConfig struct {
-	Driver        string
-	DSN           string
+	Driver string
+	DSN    string
+	// ReplicaDSN optionally points read-heavy queries at a read-only replica
+	ReplicaDSN    string
 	MigrationsDir string
 	DatabaseURL   string
 }
 
 type GRPCConfig struct {
 	ServerAddr string
+	// PublicMethods lists RPCs that may be called without a bearer token
+	PublicMethods []string
 }
 
 type UDPConfig struct {
@@ -37,8 +91,45 @@ type UDPConfig struct {
 	MaxClients        int
 	MaxClientsFromEnv bool
 	Disabled          bool
+	// ReadTimeout is how long the receive loop blocks between shutdown checks
+	ReadTimeout time.Duration
+	// StaleAfter drops clients that stay silent this long
+	StaleAfter time.Duration
 }
 
 type AuthConfig struct {
-	JWTSecret string
+	JWTSecret            string
+	RefreshTokenRotation bool
+	// AccessTokenTTL is how long issued access tokens stay valid
+	AccessTokenTTL time.Duration
+	// Issuer and Audience are written into tokens and required when validating
+	Issuer   string
+	Audience string
+	// PasswordResetSender selects how reset tokens reach users: "none" drops
+	// them, "log" writes the reset link to the server log for development
+	PasswordResetSender string
+	// PasswordResetURL is the frontend page reset links point at
+	PasswordResetURL string
+}
+
+// SecurityConfig holds the input limits for chat messages and reviews
+type SecurityConfig struct {
+	MaxMessageLength int
+	MaxReviewLength  int
+}
+
+// PaginationConfig bounds the limit query parameter of paginated listings
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// DemoConfig shapes the demo dataset seeded at startup and by the import tool
+type DemoConfig struct {
+	// MangaCount caps how many manga are seeded; 0 keeps the default size
+	MangaCount int
+	// SeedFile is an optional JSON dataset used instead of generated data
+	SeedFile string
+	// RandSeed makes generated data reproducible; 0 seeds from the clock
+	RandSeed int64
 }
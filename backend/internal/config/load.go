@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Load reads all configuration from environment variables (optionally via .env)
@@ -25,6 +26,10 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	dbReplicaDSN, err := getString("DB_REPLICA_DSN", "", false)
+	if err != nil {
+		return nil, err
+	}
 	migrationsDir, err := getString("MIGRATIONS_DIR", "", true)
 	if err != nil {
 		return nil, err
@@ -55,6 +60,10 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	grpcPublicMethods, err := getString("GRPC_PUBLIC_METHODS", "SearchManga,GetManga", false)
+	if err != nil {
+		return nil, err
+	}
 	tcpAddr, err := getString("TCP_SERVER_ADDR", "", false)
 	if err != nil {
 		return nil, err
@@ -76,18 +85,196 @@ func Load() (*Config, error) {
 		udpMaxClients = 1000
 	}
 	udpDisabled := isEnvSet("UDP_SERVER_DISABLED")
+	udpReadTimeout, err := getMinDuration("UDP_READ_TIMEOUT", time.Second, 100*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	udpStaleAfter, err := getMinDuration("UDP_STALE_AFTER", 30*time.Minute, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpAuthTimeout, err := getMinDuration("TCP_AUTH_TIMEOUT", 30*time.Second, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	tcpReadTimeout, err := getMinDuration("TCP_READ_TIMEOUT", 30*time.Second, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	tcpWriteTimeout, err := getMinDuration("TCP_WRITE_TIMEOUT", 10*time.Second, time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConnsPerUser, err := getInt("MAX_CONNECTIONS_PER_USER", 5, false)
+	if err != nil {
+		return nil, err
+	}
+	if maxConnsPerUser < 0 {
+		return nil, fmt.Errorf("env MAX_CONNECTIONS_PER_USER must not be negative, got %d", maxConnsPerUser)
+	}
 
 	allowedOrigins, err := getString("ALLOWED_ORIGINS", "http://localhost:3000", false)
 	if err != nil {
 		return nil, err
 	}
 
+	corsMethods, err := getString("CORS_ALLOWED_METHODS", "", false)
+	if err != nil {
+		return nil, err
+	}
+	corsHeaders, err := getString("CORS_ALLOWED_HEADERS", "", false)
+	if err != nil {
+		return nil, err
+	}
+	corsExposed, err := getString("CORS_EXPOSED_HEADERS", "", false)
+	if err != nil {
+		return nil, err
+	}
+	corsAllowCredentials := os.Getenv("CORS_ALLOW_CREDENTIALS") == "true"
+	corsMaxAge, err := getDuration("CORS_MAX_AGE", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	writeQueuePath, err := getString("WRITE_QUEUE_PATH", "data/write_queue.json", false)
+	if err != nil {
+		return nil, err
+	}
+
+	avatarDir, err := getString("AVATAR_DIR", "data/avatars", false)
+	if err != nil {
+		return nil, err
+	}
+
+	coverCacheDir, err := getString("COVER_CACHE_DIR", "data/covers", false)
+	if err != nil {
+		return nil, err
+	}
+
+	ratingPrior, err := getInt("RATING_PRIOR", 25, false)
+	if err != nil {
+		return nil, err
+	}
+	if ratingPrior < 0 {
+		return nil, fmt.Errorf("env RATING_PRIOR must not be negative, got %d", ratingPrior)
+	}
+
+	rateLimitBackend, err := getString("RATE_LIMIT_BACKEND", "memory", false)
+	if err != nil {
+		return nil, err
+	}
+	rateLimitBackend = strings.ToLower(rateLimitBackend)
+	if rateLimitBackend != "memory" && rateLimitBackend != "redis" {
+		return nil, fmt.Errorf("env RATE_LIMIT_BACKEND must be memory or redis, got %q", rateLimitBackend)
+	}
+
+	rateLimitTiers, err := getString("RATE_LIMIT_TIERS", "anonymous=100,user=100,premium=300,admin=1000", false)
+	if err != nil {
+		return nil, err
+	}
+	parsedRateLimitTiers, err := parseIntMap("RATE_LIMIT_TIERS", rateLimitTiers)
+	if err != nil {
+		return nil, err
+	}
+
+	requestTimeout, err := getDuration("REQUEST_TIMEOUT", 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	routeTimeouts, err := getString("REQUEST_TIMEOUT_OVERRIDES", "/analytics/reading=10s,/statistics/reading=10s,/library/export=30s", false)
+	if err != nil {
+		return nil, err
+	}
+	parsedRouteTimeouts, err := parseDurationMap("REQUEST_TIMEOUT_OVERRIDES", routeTimeouts)
+	if err != nil {
+		return nil, err
+	}
+
+	statsRecomputeInterval, err := getDuration("STATS_RECOMPUTE_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	jwtSecret, err := getString("JWT_SECRET", "mangahub-secret-key-change-in-production", false)
 	if err != nil {
 		return nil, err
 	}
 
+	refreshTokenRotation := os.Getenv("REFRESH_TOKEN_ROTATION") != "false"
+
+	accessTokenTTL, err := getDuration("JWT_ACCESS_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	jwtIssuer, err := getString("JWT_ISSUER", "mangahub", false)
+	if err != nil {
+		return nil, err
+	}
+	jwtAudience, err := getString("JWT_AUDIENCE", "mangahub", false)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetSender, err := getString("PASSWORD_RESET_SENDER", "none", false)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetSender = strings.ToLower(passwordResetSender)
+	if passwordResetSender != "none" && passwordResetSender != "log" {
+		return nil, fmt.Errorf("env PASSWORD_RESET_SENDER must be none or log, got %q", passwordResetSender)
+	}
+	passwordResetURL, err := getString("PASSWORD_RESET_URL", "http://localhost:3000/reset-password", false)
+	if err != nil {
+		return nil, err
+	}
+
 	enableDemoData := os.Getenv("ENABLE_DEMO_DATA") == "true"
+	demoMangaCount, err := getInt("DEMO_MANGA_COUNT", 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if demoMangaCount < 0 {
+		return nil, fmt.Errorf("env DEMO_MANGA_COUNT must not be negative, got %d", demoMangaCount)
+	}
+	demoSeedFile, err := getString("DEMO_SEED_FILE", "", false)
+	if err != nil {
+		return nil, err
+	}
+	demoRandSeed, err := getInt("DEMO_RAND_SEED", 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMessageLength, err := getInt("SECURITY_MAX_MESSAGE_LENGTH", 1000, false)
+	if err != nil {
+		return nil, err
+	}
+	if maxMessageLength < 1 {
+		return nil, fmt.Errorf("env SECURITY_MAX_MESSAGE_LENGTH must be positive, got %d", maxMessageLength)
+	}
+	maxReviewLength, err := getInt("SECURITY_MAX_REVIEW_LENGTH", 5000, false)
+	if err != nil {
+		return nil, err
+	}
+	if maxReviewLength < 10 {
+		return nil, fmt.Errorf("env SECURITY_MAX_REVIEW_LENGTH must be at least 10, got %d", maxReviewLength)
+	}
+
+	pageMaxLimit, err := getInt("PAGE_MAX_LIMIT", 100, false)
+	if err != nil {
+		return nil, err
+	}
+	if pageMaxLimit < 1 {
+		return nil, fmt.Errorf("env PAGE_MAX_LIMIT must be positive, got %d", pageMaxLimit)
+	}
+	pageDefaultLimit, err := getInt("PAGE_DEFAULT_LIMIT", 20, false)
+	if err != nil {
+		return nil, err
+	}
+	if pageDefaultLimit < 1 || pageDefaultLimit > pageMaxLimit {
+		return nil, fmt.Errorf("env PAGE_DEFAULT_LIMIT must be between 1 and PAGE_MAX_LIMIT (%d), got %d", pageMaxLimit, pageDefaultLimit)
+	}
 
 	cfg := Config{
 		App: AppConfig{
@@ -98,24 +285,72 @@ func Load() (*Config, error) {
 			TCPServerAddr:  tcpAddr,
 			WSServerAddr:   wsAddr,
 			AllowedOrigins: parseCSV(allowedOrigins),
+			WriteQueuePath: writeQueuePath,
+			AvatarDir:      avatarDir,
+			CoverCacheDir:  coverCacheDir,
+
+			CORSAllowedMethods:   parseCSV(corsMethods),
+			CORSAllowedHeaders:   parseCSV(corsHeaders),
+			CORSExposedHeaders:   parseCSV(corsExposed),
+			CORSAllowCredentials: corsAllowCredentials,
+			CORSMaxAge:           corsMaxAge,
+
+			RatingPrior: ratingPrior,
+
+			TCPAuthTimeout:  tcpAuthTimeout,
+			TCPReadTimeout:  tcpReadTimeout,
+			TCPWriteTimeout: tcpWriteTimeout,
+
+			MaxConnectionsPerUser: maxConnsPerUser,
+
+			RateLimitBackend: rateLimitBackend,
+			RateLimitTiers:   parsedRateLimitTiers,
+
+			RequestTimeout: requestTimeout,
+			RouteTimeouts:  parsedRouteTimeouts,
+
+			StatsRecomputeInterval: statsRecomputeInterval,
 		},
 		DB: DBConfig{
 			Driver:        dbDriver,
 			DSN:           dbDSN,
+			ReplicaDSN:    dbReplicaDSN,
 			MigrationsDir: migrationsDir,
 			DatabaseURL:   databaseURL,
 		},
 		GRPC: GRPCConfig{
-			ServerAddr: grpcAddr,
+			ServerAddr:    grpcAddr,
+			PublicMethods: parseCSV(grpcPublicMethods),
 		},
 		UDP: UDPConfig{
 			ServerAddr:        udpAddr,
 			MaxClients:        udpMaxClients,
 			MaxClientsFromEnv: udpMaxClientsSet,
 			Disabled:          udpDisabled,
+			ReadTimeout:       udpReadTimeout,
+			StaleAfter:        udpStaleAfter,
 		},
 		Auth: AuthConfig{
-			JWTSecret: jwtSecret,
+			JWTSecret:            jwtSecret,
+			RefreshTokenRotation: refreshTokenRotation,
+			AccessTokenTTL:       accessTokenTTL,
+			Issuer:               jwtIssuer,
+			Audience:             jwtAudience,
+			PasswordResetSender:  passwordResetSender,
+			PasswordResetURL:     passwordResetURL,
+		},
+		Demo: DemoConfig{
+			MangaCount: demoMangaCount,
+			SeedFile:   demoSeedFile,
+			RandSeed:   int64(demoRandSeed),
+		},
+		Security: SecurityConfig{
+			MaxMessageLength: maxMessageLength,
+			MaxReviewLength:  maxReviewLength,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: pageDefaultLimit,
+			MaxLimit:     pageMaxLimit,
 		},
 		EnableDemoData: enableDemoData,
 	}
@@ -228,6 +463,63 @@ func getOptionalInt(key string) (int, bool, error) {
 	return parsed, true, nil
 }
 
+func getDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	val, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(val) == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(val))
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("env %s must be a positive duration such as 2s, got %q", key, val)
+	}
+	return parsed, nil
+}
+
+// getMinDuration is getDuration that also rejects values shorter than min
+func getMinDuration(key string, defaultValue, min time.Duration) (time.Duration, error) {
+	d, err := getDuration(key, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+	if d < min {
+		return 0, fmt.Errorf("env %s must be at least %s, got %s", key, min, d)
+	}
+	return d, nil
+}
+
+// parseDurationMap parses "key=duration" pairs separated by commas
+func parseDurationMap(key, value string) (map[string]time.Duration, error) {
+	results := make(map[string]time.Duration)
+	for _, pair := range parseCSV(value) {
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("env %s: expected key=duration, got %q", key, pair)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("env %s: invalid duration for %s: %q", key, strings.TrimSpace(name), raw)
+		}
+		results[strings.TrimSpace(name)] = d
+	}
+	return results, nil
+}
+
+func parseIntMap(key, value string) (map[string]int, error) {
+	results := make(map[string]int)
+	for _, pair := range parseCSV(value) {
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("env %s: expected key=number, got %q", key, pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("env %s: invalid number for %s: %q", key, strings.TrimSpace(name), raw)
+		}
+		results[strings.ToLower(strings.TrimSpace(name))] = n
+	}
+	return results, nil
+}
+
 func isEnvSet(key string) bool {
 	val, ok := os.LookupEnv(key)
 	return ok && strings.TrimSpace(val) != ""
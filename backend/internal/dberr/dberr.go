@@ -0,0 +1,21 @@
+// Package dberr classifies driver errors the same way for MySQL and SQLite.
+package dberr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// IsDuplicateKey reports a unique constraint violation on MySQL or SQLite
+func IsDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
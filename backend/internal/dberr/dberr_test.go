@@ -0,0 +1,44 @@
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
+)
+
+func TestIsDuplicateKey(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE t (name TEXT NOT NULL UNIQUE); INSERT INTO t (name) VALUES ('a')`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	_, sqliteErr := db.Exec(`INSERT INTO t (name) VALUES ('a')`)
+	_, notNullErr := db.Exec(`INSERT INTO t (name) VALUES (NULL)`)
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"sqlite unique", sqliteErr, true},
+		{"sqlite not null", notNullErr, false},
+		{"mysql duplicate entry", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062}), true},
+		{"mysql other", &mysql.MySQLError{Number: 1452}, false},
+		{"plain", errors.New("boom"), false},
+	}
+	for _, tc := range cases {
+		if got := IsDuplicateKey(tc.err); got != tc.want {
+			t.Errorf("%s: IsDuplicateKey(%v) = %v, want %v", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package demo defines the dataset format used to seed demo manga.
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MangaSeed is one manga of a demo dataset
+type MangaSeed struct {
+	Title    string        `json:"title"`
+	AltTitle string        `json:"alt_title,omitempty"`
+	Slug     string        `json:"slug,omitempty"`
+	Author   string        `json:"author,omitempty"`
+	Artist   string        `json:"artist,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Synopsis string        `json:"synopsis,omitempty"`
+	Genres   []string      `json:"genres,omitempty"`
+	Rating   float64       `json:"rating,omitempty"`
+	Views    int64         `json:"views,omitempty"`
+	Language string        `json:"language,omitempty"`
+	CoverURL string        `json:"cover_url,omitempty"`
+	Chapters []ChapterSeed `json:"chapters"`
+}
+
+// ChapterSeed is one chapter of a seeded manga
+type ChapterSeed struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// seedFile is the JSON document read by LoadSeedFile
+type seedFile struct {
+	Manga []MangaSeed `json:"manga"`
+}
+
+// LoadSeedFile reads a JSON dataset of the form {"manga": [...]}. Every manga
+// needs a title, and chapter numbers must be positive and unique per manga.
+func LoadSeedFile(path string) ([]MangaSeed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read demo seed file: %w", err)
+	}
+
+	var file seedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse demo seed file %s: %w", path, err)
+	}
+	if len(file.Manga) == 0 {
+		return nil, fmt.Errorf("demo seed file %s lists no manga", path)
+	}
+
+	for i, seed := range file.Manga {
+		if strings.TrimSpace(seed.Title) == "" {
+			return nil, fmt.Errorf("demo seed file %s: manga %d has no title", path, i+1)
+		}
+		numbers := make(map[int]struct{}, len(seed.Chapters))
+		for _, ch := range seed.Chapters {
+			if ch.Number <= 0 {
+				return nil, fmt.Errorf("demo seed file %s: %s has chapter number %d", path, seed.Title, ch.Number)
+			}
+			if _, dup := numbers[ch.Number]; dup {
+				return nil, fmt.Errorf("demo seed file %s: %s repeats chapter %d", path, seed.Title, ch.Number)
+			}
+			numbers[ch.Number] = struct{}{}
+		}
+	}
+	return file.Manga, nil
+}
+
+// Limit keeps the first count seeds; a count of 0 keeps them all
+func Limit(seeds []MangaSeed, count int) []MangaSeed {
+	if count > 0 && count < len(seeds) {
+		return seeds[:count]
+	}
+	return seeds
+}
+
+// LastChapter is the highest chapter number of a seed
+func (m MangaSeed) LastChapter() int {
+	last := 0
+	for _, ch := range m.Chapters {
+		if ch.Number > last {
+			last = ch.Number
+		}
+	}
+	return last
+}
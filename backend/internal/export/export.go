@@ -0,0 +1,25 @@
+// Package export holds the download formats shared by the library and
+// reading analytics exports.
+package export
+
+import "errors"
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var ErrInvalidFormat = errors.New("format must be csv or json")
+
+// ContentType returns the MIME type served for an export format
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8", nil
+	case FormatJSON:
+		return "application/json; charset=utf-8", nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+type userIDKey struct{}
+
+// AuthInterceptor validates bearer tokens on incoming calls. Methods on the
+// public list are served without a token.
+type AuthInterceptor struct {
+	public map[string]bool
+}
+
+// NewAuthInterceptor creates an interceptor. Public methods may be given as
+// full names ("/manga.MangaService/SearchManga") or bare names ("SearchManga").
+func NewAuthInterceptor(publicMethods []string) *AuthInterceptor {
+	public := make(map[string]bool, len(publicMethods))
+	for _, m := range publicMethods {
+		if m = strings.TrimSpace(m); m != "" {
+			public[m] = true
+		}
+	}
+	return &AuthInterceptor{public: public}
+}
+
+// Unary returns the interceptor for unary RPCs
+func (a *AuthInterceptor) Unary() grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming RPCs
+func (a *AuthInterceptor) Stream() grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *AuthInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.isPublic(fullMethod) {
+		return ctx, nil
+	}
+	userID, err := userIDFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, userIDKey{}, userID), nil
+}
+
+func (a *AuthInterceptor) isPublic(fullMethod string) bool {
+	if a.public[fullMethod] {
+		return true
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return a.public[fullMethod[i+1:]]
+	}
+	return false
+}
+
+// UserIDFromContext returns the user authenticated by AuthInterceptor
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok
+}
+
+// authenticatedStream carries the authenticated context into stream handlers
+type authenticatedStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// userIDFromMetadata validates the bearer token sent with the call
+func userIDFromMetadata(ctx context.Context) (int64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return 0, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	token := strings.TrimSpace(values[0])
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		return 0, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+const testSecret = "grpc-interceptor-test-secret"
+
+func withBearer(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func callUnary(t *testing.T, interceptor *AuthInterceptor, ctx context.Context, method string) (int64, error) {
+	t.Helper()
+
+	var seen int64
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen, _ = UserIDFromContext(ctx)
+		return "ok", nil
+	}
+	_, err := interceptor.Unary()(ctx, nil, &grpclib.UnaryServerInfo{FullMethod: method}, handler)
+	return seen, err
+}
+
+func TestAuthInterceptorAllowsPublicMethodsWithoutToken(t *testing.T) {
+	interceptor := NewAuthInterceptor([]string{"SearchManga"})
+
+	if _, err := callUnary(t, interceptor, context.Background(), "/manga.MangaService/SearchManga"); err != nil {
+		t.Fatalf("expected public method to be allowed, got %v", err)
+	}
+}
+
+func TestAuthInterceptorInjectsUserID(t *testing.T) {
+	auth.SetSecret(testSecret)
+	interceptor := NewAuthInterceptor([]string{"SearchManga"})
+
+	token, err := auth.GenerateToken(42, "reader", "reader@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	userID, err := callUnary(t, interceptor, withBearer(token), "/manga.MangaService/UpdateProgress")
+	if err != nil {
+		t.Fatalf("expected valid token to be accepted, got %v", err)
+	}
+	if userID != 42 {
+		t.Fatalf("expected user 42 in context, got %d", userID)
+	}
+}
+
+func TestAuthInterceptorDeniesMissingToken(t *testing.T) {
+	interceptor := NewAuthInterceptor([]string{"SearchManga"})
+
+	_, err := callUnary(t, interceptor, context.Background(), "/manga.MangaService/UpdateProgress")
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	// Stream calls go through the same check
+	handler := func(srv interface{}, ss grpclib.ServerStream) error { return nil }
+	err = interceptor.Stream()(nil, &fakeServerStream{ctx: context.Background()}, &grpclib.StreamServerInfo{FullMethod: "/manga.MangaService/StreamProgress"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for stream, got %v", err)
+	}
+}
+
+func TestAuthInterceptorDeniesExpiredToken(t *testing.T) {
+	auth.SetSecret(testSecret)
+	interceptor := NewAuthInterceptor(nil)
+
+	claims := auth.Claims{
+		UserID: 42,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	_, err = callUnary(t, interceptor, withBearer(expired), "/manga.MangaService/GetManga")
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for expired token, got %v", err)
+	}
+}
+
+type fakeServerStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ngocan-dev/mangahub/backend/domain/history"
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
@@ -23,6 +24,7 @@ type Server struct {
 	db             *sql.DB
 	mangaService   *manga.Service
 	historyService *history.Service
+	progressSource ProgressSource
 }
 
 // NewServer creates a new gRPC server instance
@@ -102,12 +104,7 @@ func (s *Server) SearchManga(ctx context.Context, req *pb.SearchMangaRequest) (*
 	}
 
 	// Set defaults
-	if searchReq.Page < 1 {
-		searchReq.Page = 1
-	}
-	if searchReq.Limit < 1 {
-		searchReq.Limit = 20
-	}
+	searchReq.Page, searchReq.Limit = pagination.Normalize(searchReq.Page, searchReq.Limit, pagination.Default())
 
 	// Step 3: Execute database query with filters
 	searchResp, err := s.mangaService.Search(ctx, searchReq)
@@ -190,6 +187,14 @@ func (s *Server) convertToProtoManga(m *manga.MangaDetail) *pb.Manga {
 // 5. Server returns success confirmation
 func (s *Server) UpdateProgress(ctx context.Context, req *pb.UpdateProgressRequest) (*pb.UpdateProgressResponse, error) {
 	// Step 2: Validate request parameters
+	// Callers may only update their own progress
+	if userID, ok := UserIDFromContext(ctx); ok {
+		if req.UserId == 0 {
+			req.UserId = userID
+		} else if req.UserId != userID {
+			return nil, status.Error(codes.PermissionDenied, "cannot update another user's progress")
+		}
+	}
 	if req.UserId <= 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id: must be greater than 0")
 	}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
+	pb "github.com/ngocan-dev/mangahub/backend/proto/manga"
+)
+
+// ProgressSource fans out progress updates per user; *tcp.Server implements it
+type ProgressSource interface {
+	SubscribeProgress(userID int64) (<-chan tcp.ProgressUpdate, func())
+}
+
+// SetProgressSource configures where StreamProgress reads updates from
+func (s *Server) SetProgressSource(src ProgressSource) {
+	s.progressSource = src
+}
+
+// StreamProgress pushes the caller's progress updates until the client
+// disconnects. The caller is identified by the bearer token in the
+// "authorization" metadata.
+func (s *Server) StreamProgress(req *pb.StreamProgressRequest, stream pb.MangaService_StreamProgressServer) error {
+	ctx := stream.Context()
+
+	// AuthInterceptor has normally done this already; validate here as well
+	// so the stream is never served anonymously
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		var err error
+		if userID, err = userIDFromMetadata(ctx); err != nil {
+			return err
+		}
+	}
+	if s.progressSource == nil {
+		return status.Error(codes.Unavailable, "progress streaming not configured")
+	}
+
+	updates, unsubscribe := s.progressSource.SubscribeProgress(userID)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if req.MangaId > 0 && update.NovelID != req.MangaId {
+				continue
+			}
+			if err := stream.Send(toProtoProgressUpdate(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toProtoProgressUpdate(u tcp.ProgressUpdate) *pb.ProgressUpdate {
+	msg := &pb.ProgressUpdate{
+		UserId:         u.UserID,
+		MangaId:        u.NovelID,
+		CurrentChapter: int32(u.Chapter),
+		Timestamp:      u.Timestamp,
+	}
+	if u.ChapterID != nil {
+		msg.ChapterId = *u.ChapterID
+	}
+	return msg
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
+	pb "github.com/ngocan-dev/mangahub/backend/proto/manga"
+)
+
+func startStreamTestServer(t *testing.T) (pb.MangaServiceClient, *tcp.Server) {
+	t.Helper()
+
+	source := tcp.NewServer("127.0.0.1:0", 10, nil)
+	mangaServer := &Server{}
+	mangaServer.SetProgressSource(source)
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterMangaServiceServer(grpcServer, mangaServer)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial in-process server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMangaServiceClient(conn), source
+}
+
+func waitForSubscribers(t *testing.T, source *tcp.Server, userID int64, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for source.SubscriberCount(userID) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers for user %d, got %d", want, userID, source.SubscriberCount(userID))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamProgressDeliversOwnUpdatesAndUnsubscribes(t *testing.T) {
+	client, source := startStreamTestServer(t)
+
+	token, err := auth.GenerateToken(7, "reader", "reader@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token))
+	defer cancel()
+
+	stream, err := client.StreamProgress(ctx, &pb.StreamProgressRequest{})
+	if err != nil {
+		t.Fatalf("StreamProgress returned error: %v", err)
+	}
+	waitForSubscribers(t, source, 7, 1)
+
+	chapterID := int64(300)
+	source.BroadcastProgress(context.Background(), 8, 1, 99, nil)
+	source.BroadcastProgress(context.Background(), 7, 2, 12, &chapterID)
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv returned error: %v", err)
+	}
+	if update.UserId != 7 || update.MangaId != 2 || update.CurrentChapter != 12 || update.ChapterId != 300 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	cancel()
+	waitForSubscribers(t, source, 7, 0)
+}
+
+func TestStreamProgressRequiresValidToken(t *testing.T) {
+	client, _ := startStreamTestServer(t)
+
+	for name, ctx := range map[string]context.Context{
+		"missing": context.Background(),
+		"invalid": metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt"),
+	} {
+		stream, err := client.StreamProgress(ctx, &pb.StreamProgressRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("%s token: expected Unauthenticated, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+// accountDeletedReason is sent to live connections of a deleted account
+const accountDeletedReason = "this account has been deleted"
+
+// ConnectionCloser drops every live realtime connection of a user
+type ConnectionCloser interface {
+	DisconnectUser(userID int64, reason string)
+}
+
+// SetConnectionClosers wires the WebSocket and TCP servers whose connections
+// are closed when an account is deleted
+func (h *UserHandler) SetConnectionClosers(closers ...ConnectionCloser) {
+	h.connections = closers
+}
+
+// DeleteAccount handles DELETE /me. The password must be confirmed in the
+// body; afterwards every token of the user is revoked, their avatar is
+// removed and their live connections are closed.
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req user.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := user.DeleteAccount(ctx, h.DB, userID, req)
+	switch {
+	case err == nil:
+	case errors.Is(err, user.ErrIncorrectPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "password is incorrect"})
+		return
+	case errors.Is(err, user.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	default:
+		log.Printf("handler.DeleteAccount: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	// The account is gone either way; a failed revocation only leaves tokens
+	// that no longer match a user until they expire
+	if err := auth.RevokeUserTokens(ctx, userID); err != nil {
+		log.Printf("handler.DeleteAccount: failed to revoke tokens of user_id=%d err=%v", userID, err)
+	}
+	if h.avatars != nil {
+		if err := h.avatars.Remove(userID); err != nil {
+			log.Printf("handler.DeleteAccount: failed to remove avatar of user_id=%d err=%v", userID, err)
+		}
+	}
+	for _, closer := range h.connections {
+		closer.DisconnectUser(userID, accountDeletedReason)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+}
@@ -70,10 +70,104 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Issue a long-lived refresh token alongside the access token
+	refreshToken, _, err := auth.GenerateRefreshToken(c.Request.Context(), h.DB, response.User.ID)
+	if err != nil {
+		log.Printf("login: failed to issue refresh token for user %d: %v", response.User.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+	response.RefreshToken = refreshToken
+
 	// Success: Return token and user info
 	c.JSON(http.StatusOK, response)
 }
 
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh exchanges a valid refresh token for a new access token.
+// When rotation is enabled the presented refresh token is revoked and a new one is returned.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "refresh_token is required",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	refreshToken, rt, err := auth.RotateRefreshToken(ctx, h.DB, req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrRefreshTokenExpired):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "expired refresh token",
+				"message": "your session has expired. please login again",
+				"code":    "REFRESH_TOKEN_EXPIRED",
+			})
+		case errors.Is(err, auth.ErrRefreshTokenRevoked):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "revoked refresh token",
+				"message": "this refresh token has already been used or revoked. please login again",
+				"code":    "REFRESH_TOKEN_REVOKED",
+			})
+		case errors.Is(err, auth.ErrRefreshTokenNotFound):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "invalid refresh token",
+				"message": "the provided refresh token is invalid",
+				"code":    "REFRESH_TOKEN_INVALID",
+			})
+		default:
+			log.Printf("refresh: failed to rotate refresh token: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "internal server error",
+			})
+		}
+		return
+	}
+
+	var username, email string
+	err = h.DB.QueryRowContext(ctx, `
+		SELECT username, email
+		FROM users
+		WHERE id = ?
+	`, rt.UserID).Scan(&username, &email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "invalid refresh token",
+				"message": "the account for this refresh token no longer exists",
+				"code":    "REFRESH_TOKEN_INVALID",
+			})
+			return
+		}
+		log.Printf("refresh: failed to load user %d: %v", rt.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+
+	token, err := auth.GenerateTokenWithRole(rt.UserID, username, email, auth.LookupRole(ctx, h.DB, rt.UserID))
+	if err != nil {
+		log.Printf("refresh: failed to generate token for user %d: %v", rt.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         token,
+		"refresh_token": refreshToken,
+	})
+}
+
 // RequireAuth is a middleware that validates JWT token and sets user context
 // Ensure only authenticated users access protected resources
 // Invalid tokens are rejected
@@ -126,6 +220,24 @@ func (h *AuthHandler) RequireAuth(c *gin.Context) {
 			c.Abort()
 			return
 		}
+		if errors.Is(err, auth.ErrTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "revoked token",
+				"message": "this token has been revoked. please login again",
+				"code":    "TOKEN_REVOKED",
+			})
+			c.Abort()
+			return
+		}
+		if errors.Is(err, auth.ErrRevocationUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "authentication unavailable",
+				"message": "unable to verify the token right now. please try again",
+				"code":    "AUTH_UNAVAILABLE",
+			})
+			c.Abort()
+			return
+		}
 		if errors.Is(err, auth.ErrTokenNotBefore) {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error":   "token not yet valid",
@@ -170,10 +282,41 @@ func (h *AuthHandler) RequireAuth(c *gin.Context) {
 	c.Set("userID", claims.UserID) // compatibility with existing handlers
 	c.Set("username", claims.Username)
 	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
 
 	c.Next()
 }
 
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout revokes the presented access token (and refresh token, if provided)
+// so it can no longer be used on any transport.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	tokenString := getTokenFromRequest(c)
+	claims, err := auth.ValidateToken(tokenString)
+	if err != nil || claims == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	if err := auth.RevokeToken(c.Request.Context(), claims); err != nil {
+		log.Printf("logout: failed to revoke token for user %d: %v", claims.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	var req logoutRequest
+	if err := c.ShouldBindJSON(&req); err == nil && req.RefreshToken != "" {
+		if err := auth.RevokeRefreshToken(c.Request.Context(), h.DB, req.RefreshToken); err != nil && !errors.Is(err, auth.ErrRefreshTokenNotFound) {
+			log.Printf("logout: failed to revoke refresh token for user %d: %v", claims.UserID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
 // Me returns authenticated user info based on the JWT claims.
 func (h *AuthHandler) Me(c *gin.Context) {
 	userID, ok := c.Get("user_id")
@@ -217,6 +360,58 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	c.JSON(http.StatusOK, u)
 }
 
+// RequestUserID identifies the caller from the bearer token without the
+// revocation lookup RequireAuth performs. It lets global middleware such as
+// the rate limiter key requests by user before authentication has run.
+func RequestUserID(c *gin.Context) (int64, bool) {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int64); ok && id > 0 {
+			return id, true
+		}
+	}
+	tokenString := getTokenFromRequest(c)
+	if tokenString == "" {
+		return 0, false
+	}
+	return auth.UserIDFromToken(tokenString)
+}
+
+// OptionalUserID identifies the caller on a public route with the same
+// checks RequireAuth runs, including revocation. Requests without a valid
+// token are treated as anonymous rather than rejected. Use it wherever the
+// user ID unlocks per-user data or writes.
+func OptionalUserID(c *gin.Context) (int64, bool) {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int64); ok && id > 0 {
+			return id, true
+		}
+	}
+	tokenString := getTokenFromRequest(c)
+	if tokenString == "" {
+		return 0, false
+	}
+	claims, err := auth.ValidateToken(tokenString)
+	if err != nil || claims == nil || claims.UserID <= 0 {
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// RequestRole returns the role of the caller, if any. Like RequestUserID it
+// also works on public routes, where the auth middleware has not run.
+func RequestRole(c *gin.Context) (string, bool) {
+	if role, ok := c.Get("role"); ok {
+		if name, ok := role.(string); ok && name != "" {
+			return name, true
+		}
+	}
+	tokenString := getTokenFromRequest(c)
+	if tokenString == "" {
+		return "", false
+	}
+	return auth.RoleFromToken(tokenString)
+}
+
 func getTokenFromRequest(c *gin.Context) string {
 	// Prefer standard Authorization header
 	authHeader := strings.TrimSpace(c.GetHeader("Authorization"))
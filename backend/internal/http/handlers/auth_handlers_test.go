@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+func TestRequireAuthRejectsRevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", "file:http_revocation?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE Revoked_Tokens (
+		Jti TEXT PRIMARY KEY,
+		User_Id INTEGER NOT NULL,
+		Expires_At DATETIME NOT NULL,
+		Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	defer auth.SetRevocationStore(nil)
+
+	token, err := auth.GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	h := NewAuthHandler(db)
+	r := gin.New()
+	r.GET("/protected", h.RequireAuth, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	call := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := call(); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 before logout, got %d", w.Code)
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+
+	w := call()
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after logout, got %d", w.Code)
+	}
+	var body map[string]string
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body["code"] != "TOKEN_REVOKED" {
+		t.Fatalf("expected TOKEN_REVOKED code, got %q", body["code"])
+	}
+}
+
+func TestOptionalUserIDIgnoresRevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", "file:http_optional_auth?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE Revoked_Tokens (
+		Jti TEXT PRIMARY KEY,
+		User_Id INTEGER NOT NULL,
+		Expires_At DATETIME NOT NULL,
+		Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	defer auth.SetRevocationStore(nil)
+
+	token, err := auth.GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/public", func(c *gin.Context) {
+		if id, ok := OptionalUserID(c); ok {
+			c.JSON(http.StatusOK, gin.H{"user_id": id})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": 0})
+	})
+	call := func(token string) int64 {
+		req := httptest.NewRequest(http.MethodGet, "/public", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var body struct {
+			UserID int64 `json:"user_id"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return body.UserID
+	}
+
+	if got := call(token); got != 1 {
+		t.Fatalf("expected user 1 before logout, got %d", got)
+	}
+	if got := call(""); got != 0 {
+		t.Fatalf("expected an anonymous caller without a token, got %d", got)
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+
+	if got := call(token); got != 0 {
+		t.Fatalf("expected a revoked token to be treated as anonymous, got user %d", got)
+	}
+	// RequestUserID still attributes the request, which is all it is for
+	req := httptest.NewRequest(http.MethodGet, "/public", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	if id, ok := RequestUserID(c); !ok || id != 1 {
+		t.Fatalf("expected RequestUserID to keep attributing the request, got %d %v", id, ok)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+)
+
+// avatarFormField is the multipart field carrying the uploaded image
+const avatarFormField = "avatar"
+
+// SetAvatarStore configures where uploaded avatars are kept. Without a store,
+// uploads are rejected and every user gets a generated identicon.
+func (h *UserHandler) SetAvatarStore(store *user.AvatarStore) {
+	h.avatars = store
+}
+
+// UploadAvatar handles POST /me/avatar with a multipart PNG or JPEG image
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+	if h.avatars == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "avatar uploads are not configured"})
+		return
+	}
+
+	// Leave headroom for the multipart envelope around the image itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, user.MaxAvatarBytes+64<<10)
+	header, err := c.FormFile(avatarFormField)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": user.ErrAvatarTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field \"avatar\" is required"})
+		return
+	}
+	if header.Size > user.MaxAvatarBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": user.ErrAvatarTooLarge.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read upload"})
+		return
+	}
+	defer file.Close()
+
+	url, err := h.avatars.Save(c.Request.Context(), h.DB, userID, file)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"avatar_url": url})
+	case errors.Is(err, user.ErrAvatarTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrAvatarType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.UploadAvatar: user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// GetAvatar handles GET /avatars/:id, serving the uploaded image or a
+// generated identicon when the user has none
+func (h *UserHandler) GetAvatar(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if h.avatars != nil {
+		data, contentType, err := h.avatars.Open(userID)
+		if err == nil {
+			// Uploads get a new ?v= URL, so each version can be cached for long
+			c.Header("Cache-Control", "public, max-age=86400")
+			c.Data(http.StatusOK, contentType, data)
+			return
+		}
+		if !errors.Is(err, user.ErrAvatarNotFound) {
+			log.Printf("handler.GetAvatar: user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "image/svg+xml", user.Identicon(userID))
+}
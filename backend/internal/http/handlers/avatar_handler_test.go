@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+)
+
+func newAvatarTestRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	dbConn.SetMaxOpenConns(1)
+	t.Cleanup(func() { dbConn.Close() })
+	if _, err := dbConn.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY,
+		username TEXT NOT NULL,
+		avatar_url TEXT,
+		updated_at DATETIME
+	); INSERT INTO users (id, username) VALUES (1, 'alice')`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	h := NewUserHandler(dbConn)
+	h.SetAvatarStore(user.NewAvatarStore(t.TempDir()))
+
+	r := gin.New()
+	r.POST("/me/avatar", func(c *gin.Context) { c.Set("user_id", int64(1)) }, h.UploadAvatar)
+	r.GET("/avatars/:id", h.GetAvatar)
+	return r, dbConn
+}
+
+func uploadAvatar(t *testing.T, r *gin.Engine, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("avatar", "avatar.bin")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = part.Write(data)
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/me/avatar", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUploadAvatarStoresPNG(t *testing.T) {
+	r, dbConn := newAvatarTestRouter(t)
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	rec := uploadAvatar(t, r, img.Bytes())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		AvatarURL string `json:"avatar_url"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if !strings.HasPrefix(resp.AvatarURL, "/avatars/1?v=") {
+		t.Fatalf("unexpected avatar url %q", resp.AvatarURL)
+	}
+
+	var stored string
+	if err := dbConn.QueryRow(`SELECT avatar_url FROM users WHERE id = 1`).Scan(&stored); err != nil || stored != resp.AvatarURL {
+		t.Fatalf("expected users.avatar_url %q, got %q (%v)", resp.AvatarURL, stored, err)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/avatars/1", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || !bytes.Equal(rec.Body.Bytes(), img.Bytes()) {
+		t.Fatalf("expected the uploaded png, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestUploadAvatarRejectsNonImage(t *testing.T) {
+	r, _ := newAvatarTestRouter(t)
+
+	rec := uploadAvatar(t, r, []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A PNG signature followed by garbage is not a decodable image
+	rec = uploadAvatar(t, r, append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415 for a forged header, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUploadAvatarRejectsOversizedFile(t *testing.T) {
+	r, _ := newAvatarTestRouter(t)
+
+	data := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, user.MaxAvatarBytes)...)
+	rec := uploadAvatar(t, r, data)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetAvatarFallsBackToIdenticon(t *testing.T) {
+	r, _ := newAvatarTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/avatars/2", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/svg+xml" {
+		t.Fatalf("expected an svg identicon, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !bytes.Equal(rec.Body.Bytes(), user.Identicon(2)) {
+		t.Fatalf("expected a deterministic identicon")
+	}
+
+	if got := user.AvatarURLOrDefault(2, ""); got != "/avatars/2" {
+		t.Fatalf("expected default avatar url /avatars/2, got %q", got)
+	}
+	if got := user.AvatarURLOrDefault(2, "/avatars/2?v=5"); got != "/avatars/2?v=5" {
+		t.Fatalf("expected stored avatar url to win, got %q", got)
+	}
+}
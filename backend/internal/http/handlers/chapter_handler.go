@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
 	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
@@ -14,7 +19,20 @@ import (
 
 // ChapterHandler handles chapter-specific endpoints.
 type ChapterHandler struct {
-	DB *sql.DB
+	DB               *sql.DB
+	progressUpdater  ChapterProgressUpdater
+	releaseNotifier  chapterservice.ReleaseNotifier
+	mangaInvalidator MangaInvalidator
+}
+
+// ChapterProgressUpdater records reading progress when a user navigates chapters
+type ChapterProgressUpdater interface {
+	UpdateProgress(ctx context.Context, userID, mangaID int64, req history.UpdateProgressRequest) (*history.UpdateProgressResponse, error)
+}
+
+// MangaInvalidator drops cached manga details after a chapter changes them
+type MangaInvalidator interface {
+	InvalidateManga(ctx context.Context, mangaID int64)
 }
 
 // NewChapterHandler constructs a ChapterHandler.
@@ -22,7 +40,72 @@ func NewChapterHandler(db *sql.DB) *ChapterHandler {
 	return &ChapterHandler{DB: db}
 }
 
+// SetProgressUpdater enables progress tracking on chapter navigation.
+func (h *ChapterHandler) SetProgressUpdater(u ChapterProgressUpdater) {
+	h.progressUpdater = u
+}
+
+// SetReleaseNotifier notifies subscribers of chapters published by admins.
+func (h *ChapterHandler) SetReleaseNotifier(n chapterservice.ReleaseNotifier) {
+	h.releaseNotifier = n
+}
+
+// SetMangaInvalidator refreshes cached manga details after chapter writes.
+func (h *ChapterHandler) SetMangaInvalidator(inv MangaInvalidator) {
+	h.mangaInvalidator = inv
+}
+
+type chapterNavigationResponse struct {
+	*pkgchapter.ChapterNavigation
+	// ProgressChapter is the reader's current chapter after navigating, when tracked
+	ProgressChapter int `json:"progress_chapter,omitempty"`
+}
+
+// GetNavigation returns the previous and next chapters of a chapter. For a
+// reader whose token passes full validation, revocation included, it also
+// advances their progress to this chapter when the manga is in their library.
+func (h *ChapterHandler) GetNavigation(c *gin.Context) {
+	chapterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || chapterID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid chapter id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(h.DB))
+	nav, err := chapterSvc.GetAdjacentChapters(ctx, chapterID)
+	if err != nil {
+		log.Printf("handler.GetNavigation: chapter_id=%d err=%v", chapterID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if nav == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "chapter not found"})
+		return
+	}
+
+	resp := chapterNavigationResponse{ChapterNavigation: nav}
+	if userID, ok := OptionalUserID(c); ok && h.progressUpdater != nil {
+		result, err := h.progressUpdater.UpdateProgress(ctx, userID, nav.Current.MangaID, history.UpdateProgressRequest{CurrentChapter: nav.Current.Number})
+		switch {
+		case err == nil:
+			if result.UserProgress != nil {
+				resp.ProgressChapter = result.UserProgress.CurrentChapter
+			}
+		case errors.Is(err, history.ErrMangaNotInLibrary):
+			// Browsing outside the library does not create progress
+		default:
+			log.Printf("handler.GetNavigation: progress update failed user_id=%d chapter_id=%d err=%v", userID, chapterID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetChapter retrieves a chapter by its identifier.
+// With page and/or page_size query params only one paragraph-aligned page of
+// the content is returned. Clients sending Accept: text/plain get the raw
+// text without the JSON wrapper.
 func (h *ChapterHandler) GetChapter(c *gin.Context) {
 	chapterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || chapterID <= 0 {
@@ -30,8 +113,25 @@ func (h *ChapterHandler) GetChapter(c *gin.Context) {
 		return
 	}
 
+	page, pageSize, paginate, ok := parseContentPaging(c)
+	if !ok {
+		return
+	}
+
 	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(h.DB))
-	chapter, err := chapterSvc.GetChapterByID(c.Request.Context(), chapterID)
+	var (
+		chapter     *pkgchapter.Chapter
+		contentPage *chapterservice.ContentPage
+	)
+	if paginate {
+		chapter, contentPage, err = chapterSvc.GetChapterPage(c.Request.Context(), chapterID, page, pageSize)
+	} else {
+		chapter, err = chapterSvc.GetChapterByID(c.Request.Context(), chapterID)
+	}
+	if errors.Is(err, chapterservice.ErrPageOutOfRange) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -41,9 +141,118 @@ func (h *ChapterHandler) GetChapter(c *gin.Context) {
 		return
 	}
 
-	resp := mapChapterResponse(chapter)
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) == gin.MIMEPlain {
+		content := chapter.ContentText
+		if contentPage != nil {
+			content = contentPage.Content
+			c.Header("X-Page", strconv.Itoa(contentPage.Page))
+			c.Header("X-Total-Pages", strconv.Itoa(contentPage.TotalPages))
+		}
+		c.String(http.StatusOK, content)
+		return
+	}
 
-	c.JSON(http.StatusOK, resp)
+	if contentPage != nil {
+		c.JSON(http.StatusOK, mapChapterPageResponse(chapter, contentPage))
+		return
+	}
+	c.JSON(http.StatusOK, mapChapterResponse(chapter))
+}
+
+// CreateChapter publishes a new chapter of a manga (admin only).
+func (h *ChapterHandler) CreateChapter(c *gin.Context) {
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	var req chapterservice.NewChapterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(h.DB))
+	chapterSvc.SetReleaseNotifier(h.releaseNotifier)
+	chapter, err := chapterSvc.PublishChapter(c.Request.Context(), mangaID, req)
+	if err != nil {
+		h.writeChapterError(c, "CreateChapter", err)
+		return
+	}
+	h.invalidateManga(c.Request.Context(), chapter.MangaID)
+	c.JSON(http.StatusCreated, mapChapterResponse(chapter))
+}
+
+// UpdateChapter edits a chapter's number, title or content (admin only).
+func (h *ChapterHandler) UpdateChapter(c *gin.Context) {
+	chapterID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || chapterID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid chapter id"})
+		return
+	}
+
+	var req chapterservice.UpdateChapterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(h.DB))
+	chapter, err := chapterSvc.UpdateChapter(c.Request.Context(), chapterID, req)
+	if err != nil {
+		h.writeChapterError(c, "UpdateChapter", err)
+		return
+	}
+	h.invalidateManga(c.Request.Context(), chapter.MangaID)
+	c.JSON(http.StatusOK, mapChapterResponse(chapter))
+}
+
+func (h *ChapterHandler) writeChapterError(c *gin.Context, op string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, chapterservice.ErrMangaNotFound), errors.Is(err, chapterservice.ErrChapterNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, chapterservice.ErrChapterNumberTaken):
+		status = http.StatusConflict
+	case errors.Is(err, chapterservice.ErrInvalidChapterNumber), errors.Is(err, chapterservice.ErrEmptyChapterContent):
+		status = http.StatusBadRequest
+	default:
+		log.Printf("handler.%s: err=%v", op, err)
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ChapterHandler) invalidateManga(ctx context.Context, mangaID int64) {
+	if h.mangaInvalidator != nil {
+		h.mangaInvalidator.InvalidateManga(ctx, mangaID)
+	}
+}
+
+// parseContentPaging reads the optional page/page_size query params.
+// paginate reports whether either was given.
+func parseContentPaging(c *gin.Context) (page, pageSize int, paginate, ok bool) {
+	pageParam, hasPage := c.GetQuery("page")
+	sizeParam, hasSize := c.GetQuery("page_size")
+	if !hasPage && !hasSize {
+		return 0, 0, false, true
+	}
+
+	page, pageSize = 1, chapterservice.DefaultContentPageSize
+	var err error
+	if hasPage {
+		if page, err = strconv.Atoi(pageParam); err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page parameter"})
+			return 0, 0, false, false
+		}
+	}
+	if hasSize {
+		if pageSize, err = strconv.Atoi(sizeParam); err != nil || pageSize < 1 || pageSize > chapterservice.MaxContentPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("page_size must be between 1 and %d", chapterservice.MaxContentPageSize)})
+			return 0, 0, false, false
+		}
+	}
+	return page, pageSize, true, true
 }
 
 type chapterResponse struct {
@@ -55,6 +264,31 @@ type chapterResponse struct {
 	CreatedAt     string `json:"created_at"`
 }
 
+type chapterPageResponse struct {
+	ID            int64  `json:"id"`
+	MangaID       int64  `json:"manga_id"`
+	ChapterNumber int    `json:"chapter_number"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	Page          int    `json:"page"`
+	TotalPages    int    `json:"total_pages"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func mapChapterPageResponse(ch *pkgchapter.Chapter, page *chapterservice.ContentPage) chapterPageResponse {
+	full := mapChapterResponse(ch)
+	return chapterPageResponse{
+		ID:            full.ID,
+		MangaID:       full.MangaID,
+		ChapterNumber: full.ChapterNumber,
+		Title:         full.Title,
+		Content:       page.Content,
+		Page:          page.Page,
+		TotalPages:    page.TotalPages,
+		CreatedAt:     full.CreatedAt,
+	}
+}
+
 func mapChapterResponse(ch *pkgchapter.Chapter) chapterResponse {
 	createdAt := ""
 	if ch.CreatedAt != nil {
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+func newChapterTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        content_text TEXT,
+        created_at DATETIME,
+        updated_at DATETIME
+    );
+    INSERT INTO chapters (id, manga_id, number, title, content_text)
+    VALUES (1, 1, 1, 'Romance Dawn', 'The sea was calm.' || char(10) || char(10) || 'Luffy set sail.');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/chapters/:id", NewChapterHandler(db).GetChapter)
+	return r
+}
+
+func TestGetChapterPlainText(t *testing.T) {
+	r := newChapterTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/chapters/1?page=2&page_size=20", nil)
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Fatalf("expected text/plain, got %q", ct)
+	}
+	if rec.Body.String() != "Luffy set sail." {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+	if rec.Header().Get("X-Total-Pages") != "2" {
+		t.Fatalf("expected X-Total-Pages 2, got %q", rec.Header().Get("X-Total-Pages"))
+	}
+}
+
+func TestGetChapterPagedJSON(t *testing.T) {
+	r := newChapterTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chapters/1?page_size=20", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp chapterPageResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp.Content != "The sea was calm." || resp.Page != 1 || resp.TotalPages != 2 {
+		t.Fatalf("unexpected page %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chapters/1?page=3&page_size=20", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 past the last page, got %d", rec.Code)
+	}
+}
+
+type recordingProgressUpdater struct {
+	calls []int64
+}
+
+func (r *recordingProgressUpdater) UpdateProgress(ctx context.Context, userID, mangaID int64, req history.UpdateProgressRequest) (*history.UpdateProgressResponse, error) {
+	r.calls = append(r.calls, userID)
+	return &history.UpdateProgressResponse{}, nil
+}
+
+func TestGetNavigationOnlyWritesProgressForValidTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", "file:http_navigation?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+    CREATE TABLE Revoked_Tokens (
+        Jti TEXT PRIMARY KEY,
+        User_Id INTEGER NOT NULL,
+        Expires_At DATETIME NOT NULL,
+        Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        content_text TEXT,
+        created_at DATETIME,
+        updated_at DATETIME
+    );
+    INSERT INTO chapters (id, manga_id, number, title) VALUES (1, 1, 1, 'One'), (2, 1, 2, 'Two');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	defer auth.SetRevocationStore(nil)
+
+	token, err := auth.GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	updater := &recordingProgressUpdater{}
+	h := NewChapterHandler(db)
+	h.SetProgressUpdater(updater)
+	r := gin.New()
+	r.GET("/chapters/:id/navigation", h.GetNavigation)
+
+	call := func(token string) {
+		req := httptest.NewRequest(http.MethodGet, "/chapters/2/navigation", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	call("")
+	call(token)
+	if len(updater.calls) != 1 || updater.calls[0] != 1 {
+		t.Fatalf("expected one progress write for user 1, got %v", updater.calls)
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+
+	// A logged-out token still browses but no longer writes progress
+	call(token)
+	if len(updater.calls) != 1 {
+		t.Fatalf("expected a revoked token not to write progress, got %v", updater.calls)
+	}
+}
@@ -9,12 +9,20 @@ import (
 
 type ChatHandler struct {
 	hub *ws.DirectChatHub
+
+	// roomHub serves group chat rooms on the WebSocket server address
+	roomHub *ws.Hub
 }
 
 func NewChatHandler(hub *ws.DirectChatHub) *ChatHandler {
 	return &ChatHandler{hub: hub}
 }
 
+// SetRoomHub enables the room chat endpoints, ServeRoom and Status.
+func (h *ChatHandler) SetRoomHub(hub *ws.Hub) {
+	h.roomHub = hub
+}
+
 func (h *ChatHandler) Serve(c *gin.Context) {
 	userIDAny, exists := c.Get("user_id")
 	if !exists {
@@ -31,3 +39,24 @@ func (h *ChatHandler) Serve(c *gin.Context) {
 	// ✅ ENTRY POINT DUY NHẤT
 	h.hub.HandleWS(c.Writer, c.Request, userID)
 }
+
+// ServeRoom upgrades to a room chat connection; clients authenticate with
+// their join message.
+func (h *ChatHandler) ServeRoom(c *gin.Context) {
+	if h.roomHub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "room chat is not served here"})
+		return
+	}
+	ws.ServeWS(h.roomHub, c.Writer, c.Request)
+}
+
+// Status reports whether the room chat hub is running, how many clients are
+// connected across all rooms, how many rooms are occupied and the hub's
+// uptime. The API server's status page polls it on the WebSocket address.
+func (h *ChatHandler) Status(c *gin.Context) {
+	if h.roomHub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "room chat is not served here"})
+		return
+	}
+	c.JSON(http.StatusOK, h.roomHub.Status())
+}
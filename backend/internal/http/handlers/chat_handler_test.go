@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	ws "github.com/ngocan-dev/mangahub/backend/internal/websocket"
+)
+
+func TestChatHandlerStatusMatchesStatusCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := ws.NewHub(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+	deadline := time.Now().Add(time.Second)
+	for !hub.Status().Running {
+		if time.Now().After(deadline) {
+			t.Fatal("hub did not start")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	h := NewChatHandler(nil)
+	h.SetRoomHub(hub)
+	r := gin.New()
+	r.GET("/status", h.Status)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/status")
+	if err != nil {
+		t.Fatalf("GET /status failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var payload map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for key, want := range map[string]any{"running": true, "clients": float64(0), "rooms": float64(0)} {
+		if payload[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, payload[key])
+		}
+	}
+	if uptime, ok := payload["uptime"].(string); !ok || uptime == "" {
+		t.Fatalf("expected an uptime string, got %v", payload["uptime"])
+	}
+
+	// The status page reaches the same endpoint through the ws:// address
+	status, load, err := checkWebSocket(context.Background(), "ws://"+strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("checkWebSocket failed: %v", err)
+	}
+	if status != "online" || !strings.HasSuffix(load, "0 clients, 0 rooms") {
+		t.Fatalf("unexpected status %q load %q", status, load)
+	}
+}
+
+func TestChatHandlerStatusWithoutRoomHub(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/status", NewChatHandler(nil).Status)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a room hub, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	libraryservice "github.com/ngocan-dev/mangahub/backend/internal/service/library"
+)
+
+// CollectionHandler manages the user's named library collections
+type CollectionHandler struct {
+	service *libraryservice.Service
+}
+
+// NewCollectionHandler constructs a CollectionHandler
+func NewCollectionHandler(service *libraryservice.Service) *CollectionHandler {
+	return &CollectionHandler{service: service}
+}
+
+// List returns the authenticated user's collections
+func (h *CollectionHandler) List(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.ListCollections: user_id=%d err=%v", userID, err)
+		c.JSON(collectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create adds a collection for the authenticated user
+func (h *CollectionHandler) Create(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domainlibrary.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	collection, err := h.service.CreateCollection(c.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("handler.CreateCollection: user_id=%d err=%v", userID, err)
+		c.JSON(collectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// AddItem puts a manga from the user's library into a collection
+func (h *CollectionHandler) AddItem(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	var req domainlibrary.AddToCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.MangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "manga_id is required"})
+		return
+	}
+
+	collection, err := h.service.AddToCollection(c.Request.Context(), userID, collectionID, req.MangaID)
+	if err != nil {
+		log.Printf("handler.AddToCollection: user_id=%d collection_id=%d manga_id=%d err=%v", userID, collectionID, req.MangaID, err)
+		c.JSON(collectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// RemoveItem takes a manga out of a collection
+func (h *CollectionHandler) RemoveItem(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	collectionID, ok := parseCollectionID(c)
+	if !ok {
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(c.Param("manga_id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	collection, err := h.service.RemoveFromCollection(c.Request.Context(), userID, collectionID, mangaID)
+	if err != nil {
+		log.Printf("handler.RemoveFromCollection: user_id=%d collection_id=%d manga_id=%d err=%v", userID, collectionID, mangaID, err)
+		c.JSON(collectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func parseCollectionID(c *gin.Context) (int64, bool) {
+	collectionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || collectionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return 0, false
+	}
+	return collectionID, true
+}
+
+func collectionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, libraryservice.ErrInvalidCollectionName):
+		return http.StatusBadRequest
+	case errors.Is(err, libraryservice.ErrCollectionExists):
+		return http.StatusConflict
+	case errors.Is(err, libraryservice.ErrCollectionNotFound),
+		errors.Is(err, libraryservice.ErrMangaNotInCollection),
+		errors.Is(err, libraryservice.ErrMangaNotInLibrary):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
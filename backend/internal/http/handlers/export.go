@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/export"
+)
+
+// serveExport sends the output of write as a dated download named after
+// prefix. An unsupported format is answered with 400. If write fails before
+// any byte is sent, the download headers are dropped and a JSON error carrying
+// failMessage takes the file's place; once part of the file is out the status
+// can no longer change. The write error is returned for logging.
+func serveExport(c *gin.Context, prefix, format, failMessage string, write func(w io.Writer) error) error {
+	contentType, err := export.ContentType(format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", prefix, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	err = write(c.Writer)
+	if err != nil && !c.Writer.Written() {
+		header := c.Writer.Header()
+		header.Del("Content-Disposition")
+		header.Set("Content-Type", "application/json; charset=utf-8")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMessage})
+	}
+	return err
+}
@@ -13,7 +13,13 @@ import (
 
 // FriendHandler manages friend workflows (search, request, accept)
 type FriendHandler struct {
-	service *friend.Service
+	service  *friend.Service
+	presence []PresenceChecker
+}
+
+// PresenceChecker reports whether a user has a live realtime connection
+type PresenceChecker interface {
+	IsUserOnline(userID int64) bool
 }
 
 // NewFriendHandler constructs a FriendHandler
@@ -21,6 +27,11 @@ func NewFriendHandler(service *friend.Service) *FriendHandler {
 	return &FriendHandler{service: service}
 }
 
+// SetPresenceCheckers wires the WebSocket and TCP servers consulted for online friends
+func (h *FriendHandler) SetPresenceCheckers(checkers ...PresenceChecker) {
+	h.presence = checkers
+}
+
 // Search allows a user to look up another user by username
 func (h *FriendHandler) Search(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -183,6 +194,87 @@ func (h *FriendHandler) RejectRequest(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "request rejected"})
 }
 
+// RemoveFriend unfriends the user identified by :id
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	friendID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || friendID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.service.RemoveFriend(c.Request.Context(), userID, friendID); err != nil {
+		if errors.Is(err, friend.ErrNotFriends) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not friends"})
+			return
+		}
+		log.Printf("handler.RemoveFriend: user_id=%d friend_id=%d err=%v", userID, friendID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove friend"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "friend removed"})
+}
+
+// BlockUser blocks the user identified by :id
+func (h *FriendHandler) BlockUser(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || targetID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.service.BlockUser(c.Request.Context(), userID, targetID); err != nil {
+		switch {
+		case errors.Is(err, friend.ErrCannotBlockSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot block yourself"})
+		case errors.Is(err, friend.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		default:
+			log.Printf("handler.BlockUser: user_id=%d target_id=%d err=%v", userID, targetID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to block user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user blocked"})
+}
+
+// UnblockUser removes a block on the user identified by :id
+func (h *FriendHandler) UnblockUser(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || targetID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.service.UnblockUser(c.Request.Context(), userID, targetID); err != nil {
+		if errors.Is(err, friend.ErrNotBlocked) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user is not blocked"})
+			return
+		}
+		log.Printf("handler.UnblockUser: user_id=%d target_id=%d err=%v", userID, targetID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unblock user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked"})
+}
+
 // ListFriends returns accepted friends for current user
 func (h *FriendHandler) ListFriends(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -201,6 +293,37 @@ func (h *FriendHandler) ListFriends(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"friends": friends})
 }
 
+// OnlineFriends returns accepted friends with at least one active WebSocket or TCP connection
+func (h *FriendHandler) OnlineFriends(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+	friends, err := h.service.ListFriends(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.OnlineFriends: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load friends"})
+		return
+	}
+
+	online := []friend.UserSummary{}
+	for _, f := range friends {
+		if h.isOnline(f.ID) {
+			online = append(online, f)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"friends": online})
+}
+
+func (h *FriendHandler) isOnline(userID int64) bool {
+	for _, checker := range h.presence {
+		if checker != nil && checker.IsUserOnline(userID) {
+			return true
+		}
+	}
+	return false
+}
+
 // PendingRequests lists incoming friend requests for the authenticated user.
 func (h *FriendHandler) PendingRequests(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+)
+
+// GoalHandler manages reading goal endpoints
+type GoalHandler struct {
+	service *history.Service
+}
+
+// NewGoalHandler constructs a GoalHandler
+func NewGoalHandler(service *history.Service) *GoalHandler {
+	return &GoalHandler{service: service}
+}
+
+// Create adds a reading goal for the authenticated user
+func (h *GoalHandler) Create(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req history.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "goal_type, target_value, period_type, period_start and period_end are required"})
+		return
+	}
+
+	goal, err := h.service.CreateGoal(c.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("handler.CreateGoal: user_id=%d err=%v", userID, err)
+		c.JSON(goalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// List returns the authenticated user's reading goals
+func (h *GoalHandler) List(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	goals, err := h.service.ListGoals(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.ListGoals: user_id=%d err=%v", userID, err)
+		c.JSON(goalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"goals": goals})
+}
+
+// Update edits one of the authenticated user's reading goals
+func (h *GoalHandler) Update(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	goalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || goalID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+
+	var req history.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	goal, err := h.service.UpdateGoal(c.Request.Context(), userID, goalID, req)
+	if err != nil {
+		log.Printf("handler.UpdateGoal: user_id=%d goal_id=%d err=%v", userID, goalID, err)
+		c.JSON(goalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// Delete removes one of the authenticated user's reading goals
+func (h *GoalHandler) Delete(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	goalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || goalID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+
+	if err := h.service.DeleteGoal(c.Request.Context(), userID, goalID); err != nil {
+		log.Printf("handler.DeleteGoal: user_id=%d goal_id=%d err=%v", userID, goalID, err)
+		c.JSON(goalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "goal deleted"})
+}
+
+func goalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, history.ErrInvalidGoalType),
+		errors.Is(err, history.ErrInvalidPeriodType),
+		errors.Is(err, history.ErrInvalidGoalPeriod),
+		errors.Is(err, history.ErrInvalidGoalTarget),
+		errors.Is(err, history.ErrUnknownGoalGenre):
+		return http.StatusBadRequest
+	case errors.Is(err, history.ErrGoalNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"errors"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
 	"github.com/ngocan-dev/mangahub/backend/internal/cache"
+	"github.com/ngocan-dev/mangahub/backend/internal/pagination"
 	"github.com/ngocan-dev/mangahub/backend/internal/queue"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
@@ -56,13 +58,17 @@ func NewMangaHandlerWithService(db *sql.DB, mangaService *manga.Service) *MangaH
 
 	libraryRepo := libraryrepository.NewRepository(db)
 	librarySvc := libraryservice.NewService(libraryRepo, mangaService, nil)
+	librarySvc.SetTitleMatcher(mangaService)
 
 	historyRepo := history.NewRepository(db)
 	historySvc := history.NewService(historyRepo, chapterSvc, librarySvc, mangaService)
+	mangaService.SetGenreAffinity(historySvc)
+	historySvc.SetPopularityNotifier(mangaService)
 
 	reviewRepo := comment.NewRepository(db)
 	reviewSvc := comment.NewService(reviewRepo, mangaService, nil)
 	reviewSvc.SetActivityRecorder(historySvc)
+	reviewSvc.SetRatingNotifier(mangaService)
 
 	return &MangaHandler{
 		DB:             db,
@@ -81,6 +87,13 @@ func (h *MangaHandler) SetBroadcaster(b history.Broadcaster) {
 	}
 }
 
+// SetLibraryBroadcaster configures the broadcaster for library change events.
+func (h *MangaHandler) SetLibraryBroadcaster(b libraryservice.Broadcaster) {
+	if h.libraryService != nil {
+		h.libraryService.SetBroadcaster(b)
+	}
+}
+
 // SetDBHealth sets the DB health checker on the manga service.
 func (h *MangaHandler) SetDBHealth(checker manga.DBHealthChecker) {
 	h.dbHealth = checker
@@ -89,6 +102,26 @@ func (h *MangaHandler) SetDBHealth(checker manga.DBHealthChecker) {
 	}
 }
 
+// SetReadReplica routes read-only manga, review and analytics queries to a replica.
+func (h *MangaHandler) SetReadReplica(replica *sql.DB) {
+	if h.mangaService != nil {
+		h.mangaService.SetReadReplica(replica)
+	}
+	if h.historyService != nil {
+		h.historyService.SetReadReplica(replica)
+	}
+	if h.reviewService != nil {
+		h.reviewService.SetReadReplica(replica)
+	}
+}
+
+// SetStatsWorker lets progress updates schedule statistics recomputes.
+func (h *MangaHandler) SetStatsWorker(w *history.StatsWorker) {
+	if h.historyService != nil {
+		h.historyService.SetStatsWorker(w)
+	}
+}
+
 // SetWriteQueue attaches a write queue to the manga service.
 func (h *MangaHandler) SetWriteQueue(q *queue.WriteQueue) {
 	h.writeQueue = q
@@ -97,7 +130,7 @@ func (h *MangaHandler) SetWriteQueue(q *queue.WriteQueue) {
 	}
 }
 
-// GetPopularManga returns the popular manga list, leveraging cache when available.
+// GetPopularManga returns a page of popular manga for a time window, leveraging cache when available.
 func (h *MangaHandler) GetPopularManga(c *gin.Context) {
 	const (
 		defaultLimit = 20
@@ -116,13 +149,24 @@ func (h *MangaHandler) GetPopularManga(c *gin.Context) {
 		}
 	}
 
-	popular, err := h.mangaService.GetPopularManga(c.Request.Context(), limit)
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page parameter"})
+		return
+	}
+
+	period := c.DefaultQuery("period", manga.PopularPeriodAll)
+
+	popular, err := h.mangaService.GetPopularMangaPage(c.Request.Context(), page, limit, period)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, manga.ErrDatabaseUnavailable) {
+		switch {
+		case errors.Is(err, manga.ErrInvalidPeriod):
+			status = http.StatusBadRequest
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
 			status = http.StatusServiceUnavailable
 		}
-		log.Printf("handler: GetPopularManga failed (limit=%d): %v", limit, err)
+		log.Printf("handler: GetPopularManga failed (period=%s page=%d limit=%d): %v", period, page, limit, err)
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
@@ -130,6 +174,125 @@ func (h *MangaHandler) GetPopularManga(c *gin.Context) {
 	c.JSON(http.StatusOK, popular)
 }
 
+// GetTrending returns manga with the most recent reading momentum.
+func (h *MangaHandler) GetTrending(c *gin.Context) {
+	const (
+		defaultLimit = 20
+		maxLimit     = 50
+	)
+
+	limit := defaultLimit
+	if s, ok := c.GetQuery("limit"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			limit = defaultLimit
+		} else if n > maxLimit {
+			limit = maxLimit
+		} else {
+			limit = n
+		}
+	}
+
+	window := c.DefaultQuery("window", manga.PopularPeriodWeek)
+
+	trending, err := h.mangaService.GetTrending(c.Request.Context(), limit, window)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, manga.ErrInvalidTrendingWindow):
+			status = http.StatusBadRequest
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		log.Printf("handler: GetTrending failed (window=%s limit=%d): %v", window, limit, err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trending)
+}
+
+// Suggest returns up to 10 {id, title} matches for a typed prefix, most read
+// first. Responses may be cached briefly by browsers so debounced
+// search-as-you-type repeats the same request cheaply.
+func (h *MangaHandler) Suggest(c *gin.Context) {
+	q := c.Query("q")
+
+	limit := manga.MaxSuggestions
+	if s, ok := c.GetQuery("limit"); ok {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 && n < limit {
+			limit = n
+		}
+	}
+
+	suggestions, err := h.mangaService.Suggest(c.Request.Context(), q, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, manga.ErrDatabaseUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		log.Printf("handler: Suggest failed (q=%q): %v", q, err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// ListTags returns every tag with how many manga carry it.
+func (h *MangaHandler) ListTags(c *gin.Context) {
+	tags, err := h.mangaService.ListTags(c.Request.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, manga.ErrDatabaseUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		log.Printf("handler: ListTags failed: %v", err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// defaultPageLimit is the configured page size, as a query parameter default
+func defaultPageLimit() string {
+	return strconv.Itoa(pagination.Default().Limit)
+}
+
+// GetMangaByTag returns a page of manga carrying a tag, best rated first.
+func (h *MangaHandler) GetMangaByTag(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page parameter"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultPageLimit()))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+		return
+	}
+
+	name := c.Param("name")
+	resp, err := h.mangaService.GetMangaByTag(c.Request.Context(), name, page, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, manga.ErrTagNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			status = http.StatusServiceUnavailable
+		default:
+			log.Printf("handler: GetMangaByTag failed (tag=%s page=%d limit=%d): %v", name, page, limit, err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // Search finds manga using query parameters.
 func (h *MangaHandler) Search(c *gin.Context) {
 	var req manga.SearchRequest
@@ -156,7 +319,170 @@ func (h *MangaHandler) Search(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// GetDetails retrieves manga detail information.
+// GetSimilarManga lists manga that share the most tags with the given manga.
+func (h *MangaHandler) GetSimilarManga(c *gin.Context) {
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+		return
+	}
+
+	resp, err := h.mangaService.GetSimilarManga(c.Request.Context(), mangaID, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, manga.ErrMangaNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFeed serves an RSS feed of a manga's latest chapters so feed readers can
+// follow releases.
+func (h *MangaHandler) GetFeed(c *gin.Context) {
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	m, err := h.mangaService.GetByID(ctx, mangaID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, manga.ErrDatabaseUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		log.Printf("handler: GetFeed failed to load manga_id=%d: %v", mangaID, err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if m == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": manga.ErrMangaNotFound.Error()})
+		return
+	}
+
+	limit := chapterservice.DefaultFeedSize
+	if s, ok := c.GetQuery("limit"); ok {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	chapterSvc := chapterservice.NewService(chapterrepository.NewRepository(h.DB))
+	feed, err := chapterSvc.ReleaseFeed(ctx, chapterservice.FeedSource{
+		MangaID:     m.ID,
+		Title:       m.Title,
+		Description: m.Description,
+		BaseURL:     requestBaseURL(c),
+	}, limit)
+	if err != nil {
+		log.Printf("handler: GetFeed failed for manga_id=%d: %v", mangaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", feed)
+}
+
+// requestBaseURL is the origin the client reached the API on, honouring a
+// TLS-terminating proxy
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// GetCover handles GET /covers/:id, proxying the manga's cover image through
+// the local cache and serving a placeholder when the upstream is unavailable
+func (h *MangaHandler) GetCover(c *gin.Context) {
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	cover, err := h.mangaService.GetCover(c.Request.Context(), mangaID)
+	if err != nil {
+		switch {
+		case errors.Is(err, manga.ErrMangaNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			log.Printf("handler: GetCover failed for manga_id=%d: %v", mangaID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if cover.Placeholder {
+		// Short-lived so clients pick up the real cover once the upstream recovers
+		c.Header("Cache-Control", "public, max-age=300")
+		c.Data(http.StatusOK, cover.ContentType, cover.Data)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	if cover.ETag != "" {
+		c.Header("ETag", cover.ETag)
+		if c.GetHeader("If-None-Match") == cover.ETag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.Data(http.StatusOK, cover.ContentType, cover.Data)
+}
+
+// GetRecommendations suggests manga for the authenticated user based on their favorite genres.
+func (h *MangaHandler) GetRecommendations(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+		return
+	}
+
+	resp, err := h.mangaService.GetRecommendations(c.Request.Context(), userID, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, manga.ErrDatabaseUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		log.Printf("handler: GetRecommendations failed (user_id=%d limit=%d): %v", userID, limit, err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// skipViewCountHeader lets crawlers and monitoring fetch details without
+// counting a view
+const skipViewCountHeader = "X-Skip-View-Count"
+
+// GetDetails retrieves manga detail information. Each call counts a view
+// unless the skipViewCountHeader is set; repeat views by the same user, or
+// the same address when anonymous, are debounced by the manga service.
 func (h *MangaHandler) GetDetails(c *gin.Context) {
 	idParam := c.Param("id")
 	mangaID, err := strconv.ParseInt(idParam, 10, 64)
@@ -165,16 +491,10 @@ func (h *MangaHandler) GetDetails(c *gin.Context) {
 		return
 	}
 
+	// The route is public; a valid bearer token only adds the user's own data
 	var userID *int64
-	if val, exists := c.Get("user_id"); exists {
-		switch v := val.(type) {
-		case int64:
-			userID = &v
-		case string:
-			if parsed, parseErr := strconv.ParseInt(v, 10, 64); parseErr == nil {
-				userID = &parsed
-			}
-		}
+	if id, ok := OptionalUserID(c); ok {
+		userID = &id
 	}
 
 	detail, err := h.mangaService.GetDetails(c.Request.Context(), mangaID, userID)
@@ -190,28 +510,117 @@ func (h *MangaHandler) GetDetails(c *gin.Context) {
 		return
 	}
 
+	if c.GetHeader(skipViewCountHeader) == "" {
+		viewer := "ip:" + c.ClientIP()
+		if userID != nil {
+			viewer = "user:" + strconv.FormatInt(*userID, 10)
+		}
+		h.mangaService.RecordView(c.Request.Context(), mangaID, viewer)
+	}
+
 	if userID != nil {
 		progress, _ := h.historyService.GetProgress(c.Request.Context(), *userID, mangaID)
 		detail.UserProgress = progress
 		status, _ := h.libraryService.GetLibraryStatus(c.Request.Context(), *userID, mangaID)
 		detail.LibraryStatus = status
+		collections, err := h.libraryService.GetMangaCollections(c.Request.Context(), *userID, mangaID)
+		if err != nil {
+			log.Printf("handler: GetDetails collections lookup failed (user_id=%d manga_id=%d): %v", *userID, mangaID, err)
+		}
+		detail.Collections = collections
+	}
+
+	// Stats are loaded per request so cached details never show stale counts
+	if h.reviewService != nil {
+		stats, err := h.reviewService.GetReviewStats(c.Request.Context(), mangaID)
+		if err != nil {
+			log.Printf("handler: GetDetails review stats lookup failed (manga_id=%d): %v", mangaID, err)
+		}
+		detail.ReviewStats = stats
 	}
 
 	c.JSON(http.StatusOK, detail)
 }
 
-// GetLibrary lists the authenticated user's library entries.
+// GetDetailsBatch returns the details of several manga in one request. When
+// the caller is authenticated each detail also carries their progress and
+// library status.
+func (h *MangaHandler) GetDetailsBatch(c *gin.Context) {
+	var req manga.BatchDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	details, err := h.mangaService.GetDetailsBatch(ctx, req.IDs)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, manga.ErrNoBatchIDs), errors.Is(err, manga.ErrTooManyBatchIDs), errors.Is(err, manga.ErrInvalidBatchID):
+			status = http.StatusBadRequest
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			status = http.StatusServiceUnavailable
+		default:
+			log.Printf("handler: GetDetailsBatch failed (ids=%d): %v", len(req.IDs), err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := manga.BatchDetailsResponse{Results: details}
+	found := make([]int64, 0, len(details))
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, ok := details[id]; ok {
+			found = append(found, id)
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
+	}
+
+	// The route is public; a valid bearer token only adds the user's own data
+	if userID, ok := OptionalUserID(c); ok && len(found) > 0 {
+		progress, err := h.historyService.GetProgressBatch(ctx, userID, found)
+		if err != nil {
+			log.Printf("handler: GetDetailsBatch progress lookup failed (user_id=%d): %v", userID, err)
+		}
+		statuses, err := h.libraryService.GetLibraryStatuses(ctx, userID, found)
+		if err != nil {
+			log.Printf("handler: GetDetailsBatch library lookup failed (user_id=%d): %v", userID, err)
+		}
+		for _, id := range found {
+			details[id].UserProgress = progress[id]
+			details[id].LibraryStatus = statuses[id]
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetLibrary lists the authenticated user's library entries, optionally filtered
+// by status, sorted and paged through query parameters.
 func (h *MangaHandler) GetLibrary(c *gin.Context) {
 	userID, ok := RequireUserID(c)
 	if !ok {
 		return
 	}
 
-	resp, err := h.libraryService.GetLibrary(c.Request.Context(), userID)
+	var query domainlibrary.LibraryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid library parameters"})
+		return
+	}
+
+	resp, err := h.libraryService.GetLibrary(c.Request.Context(), userID, query)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, libraryservice.ErrDatabaseError) {
-			status = http.StatusInternalServerError
+		if errors.Is(err, libraryservice.ErrInvalidStatus) || errors.Is(err, libraryservice.ErrInvalidSort) {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -224,6 +633,58 @@ func (h *MangaHandler) GetLibrary(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// ExportLibrary streams the authenticated user's library as a CSV or JSON download.
+func (h *MangaHandler) ExportLibrary(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", libraryservice.ExportFormatJSON))
+	err := serveExport(c, "mangahub-library", format, "unable to export library", func(w io.Writer) error {
+		return h.libraryService.ExportLibrary(c.Request.Context(), userID, format, w)
+	})
+	if err != nil {
+		log.Printf("handler.ExportLibrary: user_id=%d format=%s err=%v", userID, format, err)
+	}
+}
+
+// maxImportBodyBytes caps the size of a library import upload.
+const maxImportBodyBytes = 1 << 20
+
+// ImportLibrary adds entries from an uploaded JSON array to the authenticated user's library.
+func (h *MangaHandler) ImportLibrary(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes)
+	var entries []domainlibrary.ImportEntry
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expected a JSON array of library entries"})
+		return
+	}
+
+	summary, err := h.libraryService.ImportLibrary(c.Request.Context(), userID, entries)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, libraryservice.ErrImportTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		log.Printf("handler.ImportLibrary: user_id=%d entries=%d err=%v", userID, len(entries), err)
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
 // AddToLibrary adds a manga to the authenticated user's library.
 func (h *MangaHandler) AddToLibrary(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -261,6 +722,31 @@ func (h *MangaHandler) AddToLibrary(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// RemoveFromLibrary removes a manga and its reading progress from the authenticated user's library.
+func (h *MangaHandler) RemoveFromLibrary(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	if err := h.libraryService.RemoveFromLibrary(c.Request.Context(), userID, mangaID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, libraryservice.ErrMangaNotInLibrary) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "manga removed from library"})
+}
+
 // UpdateProgress updates reading progress for a manga.
 func (h *MangaHandler) UpdateProgress(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -300,6 +786,74 @@ func (h *MangaHandler) UpdateProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetProgressBatch returns the authenticated user's progress for the manga in
+// ?manga_ids=1,2,3, keyed by manga ID, so a library list needs one request.
+func (h *MangaHandler) GetProgressBatch(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var mangaIDs []int64
+	for _, raw := range strings.Split(c.Query("manga_ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": history.ErrInvalidProgressID.Error()})
+			return
+		}
+		mangaIDs = append(mangaIDs, id)
+	}
+
+	progress, err := h.historyService.GetProgressBatch(c.Request.Context(), userID, mangaIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, history.ErrNoProgressIDs), errors.Is(err, history.ErrTooManyProgressIDs), errors.Is(err, history.ErrInvalidProgressID):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("handler.GetProgressBatch: user_id=%d ids=%d err=%v", userID, len(mangaIDs), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to load progress"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// BatchUpdateProgress applies several progress entries queued while offline.
+func (h *MangaHandler) BatchUpdateProgress(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var items []history.BatchProgressItem
+	if err := c.ShouldBindJSON(&items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be an array of {manga_id, current_chapter, read_at}"})
+		return
+	}
+
+	resp, err := h.historyService.BatchUpdateProgress(c.Request.Context(), userID, items)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, history.ErrEmptyProgressBatch):
+			status = http.StatusBadRequest
+		case errors.Is(err, history.ErrProgressBatchTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		}
+		if status == http.StatusInternalServerError {
+			log.Printf("handler.BatchUpdateProgress: user_id=%d items=%d err=%v", userID, len(items), err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // CreateReview creates a review for a manga.
 func (h *MangaHandler) CreateReview(c *gin.Context) {
 	userID, ok := RequireUserID(c)
@@ -339,7 +893,207 @@ func (h *MangaHandler) CreateReview(c *gin.Context) {
 	c.JSON(http.StatusCreated, resp)
 }
 
-// GetReviews returns reviews for a manga.
+// UpdateReview edits a review owned by the authenticated user.
+func (h *MangaHandler) UpdateReview(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || reviewID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	var req comment.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review payload"})
+		return
+	}
+
+	resp, err := h.reviewService.UpdateReview(c.Request.Context(), userID, reviewID, req)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteReview removes a review owned by the authenticated user.
+func (h *MangaHandler) DeleteReview(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || reviewID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	resp, err := h.reviewService.DeleteReview(c.Request.Context(), userID, reviewID)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// FlagReview reports a review to moderators.
+func (h *MangaHandler) FlagReview(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || reviewID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	var req comment.FlagReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	resp, err := h.reviewService.FlagReview(c.Request.Context(), userID, reviewID, req)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// VoteReview casts or toggles a helpful/unhelpful vote on a review.
+func (h *MangaHandler) VoteReview(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || reviewID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	var req comment.VoteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vote is required"})
+		return
+	}
+
+	resp, err := h.reviewService.VoteReview(c.Request.Context(), userID, reviewID, req)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// HideReview hides a review from listings and stats (admin only).
+func (h *MangaHandler) HideReview(c *gin.Context) {
+	h.moderateReview(c, true)
+}
+
+// UnhideReview restores a hidden review (admin only).
+func (h *MangaHandler) UnhideReview(c *gin.Context) {
+	h.moderateReview(c, false)
+}
+
+func (h *MangaHandler) moderateReview(c *gin.Context, hide bool) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || reviewID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	var resp *comment.ModerateReviewResponse
+	if hide {
+		resp, err = h.reviewService.HideReview(c.Request.Context(), reviewID)
+	} else {
+		resp, err = h.reviewService.UnhideReview(c.Request.Context(), reviewID)
+	}
+	if err != nil {
+		if errors.Is(err, comment.ErrDatabaseError) {
+			log.Printf("handler.moderateReview: review_id=%d hide=%t err=%v", reviewID, hide, err)
+		}
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteManga soft-deletes a manga (admin only).
+func (h *MangaHandler) DeleteManga(c *gin.Context) {
+	h.setMangaDeleted(c, true)
+}
+
+// RestoreManga brings back a soft-deleted manga (admin only).
+func (h *MangaHandler) RestoreManga(c *gin.Context) {
+	h.setMangaDeleted(c, false)
+}
+
+func (h *MangaHandler) setMangaDeleted(c *gin.Context, deleted bool) {
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	message := "manga deleted"
+	if deleted {
+		err = h.mangaService.SoftDeleteManga(c.Request.Context(), mangaID)
+	} else {
+		err = h.mangaService.RestoreManga(c.Request.Context(), mangaID)
+		message = "manga restored"
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, manga.ErrMangaNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, manga.ErrDatabaseUnavailable):
+			status = http.StatusServiceUnavailable
+		default:
+			log.Printf("handler.setMangaDeleted: manga_id=%d deleted=%t err=%v", mangaID, deleted, err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, manga.MangaDeletionResponse{
+		Message: message,
+		MangaID: mangaID,
+		Deleted: deleted,
+	})
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, comment.ErrInvalidReviewRating), errors.Is(err, comment.ErrReviewContentTooShort), errors.Is(err, comment.ErrReviewContentTooLong), errors.Is(err, comment.ErrInvalidFlagReason), errors.Is(err, comment.ErrInvalidVote):
+		return http.StatusBadRequest
+	case errors.Is(err, comment.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, comment.ErrReviewForbidden), errors.Is(err, comment.ErrOwnReviewVote):
+		return http.StatusForbidden
+	case errors.Is(err, comment.ErrReviewAlreadyFlagged):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetReviews returns reviews for a manga, by page or by cursor.
 func (h *MangaHandler) GetReviews(c *gin.Context) {
 	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil || mangaID <= 0 {
@@ -353,22 +1107,33 @@ func (h *MangaHandler) GetReviews(c *gin.Context) {
 		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", defaultPageLimit()))
 	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
 		return
 	}
-	if limit > 100 {
-		limit = 100
-	}
+	limit = min(limit, pagination.Default().MaxLimit)
 
 	sortBy := c.DefaultQuery("sort_by", "recent")
 
-	resp, err := h.reviewService.GetReviews(c.Request.Context(), mangaID, page, limit, sortBy)
+	// The route is public; a valid bearer token adds the user's own votes
+	var viewerID *int64
+	if id, ok := OptionalUserID(c); ok {
+		viewerID = &id
+	}
+
+	// Sending cursor (empty for the first page) switches to keyset paging,
+	// which stays stable while reviews are posted; page is then ignored
+	var resp *comment.GetReviewsResponse
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		resp, err = h.reviewService.GetReviewsByCursor(c.Request.Context(), mangaID, viewerID, cursor, limit, sortBy)
+	} else {
+		resp, err = h.reviewService.GetReviews(c.Request.Context(), mangaID, viewerID, page, limit, sortBy)
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, comment.ErrDatabaseError) {
-			status = http.StatusInternalServerError
+		if errors.Is(err, comment.ErrInvalidCursor) || errors.Is(err, comment.ErrCursorUnsupported) {
+			status = http.StatusBadRequest
 		}
 		log.Printf("handler.GetReviews: manga_id=%d page=%d limit=%d err=%v", mangaID, page, limit, err)
 		c.JSON(status, gin.H{"error": err.Error()})
@@ -386,10 +1151,19 @@ func (h *MangaHandler) GetFriendsActivityFeed(c *gin.Context) {
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", defaultPageLimit()))
+
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
 
-	log.Printf("handler.GetFriendsActivityFeed: user_id=%d page=%d limit=%d", userID, page, limit)
-	resp, err := h.historyService.GetFriendsActivityFeed(c.Request.Context(), userID, page, limit)
+	log.Printf("handler.GetFriendsActivityFeed: user_id=%d page=%d limit=%d types=%v", userID, page, limit, types)
+	resp, err := h.historyService.GetFriendsActivityFeed(c.Request.Context(), userID, page, limit, types)
+	if errors.Is(err, history.ErrInvalidActivityType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Printf("handler.GetFriendsActivityFeed: user_id=%d error=%v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to load friends activity"})
@@ -464,18 +1238,20 @@ func (h *MangaHandler) GetReadingAnalytics(c *gin.Context) {
 			return
 		}
 		c.JSON(http.StatusOK, &history.ReadingAnalyticsResponse{
-			Daily:   []history.ReadingAnalyticsPoint{},
-			Weekly:  []history.ReadingAnalyticsPoint{},
-			Monthly: []history.ReadingAnalyticsPoint{},
+			Daily:         []history.ReadingAnalyticsPoint{},
+			Weekly:        []history.ReadingAnalyticsPoint{},
+			Monthly:       []history.ReadingAnalyticsPoint{},
+			MonthlyGenres: []history.GenreAnalyticsPoint{},
 		})
 		return
 	}
 
 	if analytics == nil {
 		analytics = &history.ReadingAnalyticsResponse{
-			Daily:   []history.ReadingAnalyticsPoint{},
-			Weekly:  []history.ReadingAnalyticsPoint{},
-			Monthly: []history.ReadingAnalyticsPoint{},
+			Daily:         []history.ReadingAnalyticsPoint{},
+			Weekly:        []history.ReadingAnalyticsPoint{},
+			Monthly:       []history.ReadingAnalyticsPoint{},
+			MonthlyGenres: []history.GenreAnalyticsPoint{},
 		}
 	} else {
 		if analytics.Daily == nil {
@@ -487,7 +1263,27 @@ func (h *MangaHandler) GetReadingAnalytics(c *gin.Context) {
 		if analytics.Monthly == nil {
 			analytics.Monthly = []history.ReadingAnalyticsPoint{}
 		}
+		if analytics.MonthlyGenres == nil {
+			analytics.MonthlyGenres = []history.GenreAnalyticsPoint{}
+		}
 	}
 
 	c.JSON(http.StatusOK, analytics)
 }
+
+// ExportReadingAnalytics sends the authenticated user's daily reading buckets,
+// streaks and goals as a CSV or JSON download.
+func (h *MangaHandler) ExportReadingAnalytics(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", history.ExportFormatCSV))
+	err := serveExport(c, "mangahub-reading", format, "unable to export reading analytics", func(w io.Writer) error {
+		return h.historyService.ExportReadingAnalytics(c.Request.Context(), userID, format, w)
+	})
+	if err != nil {
+		log.Printf("handler.ExportReadingAnalytics: user_id=%d format=%s err=%v", userID, format, err)
+	}
+}
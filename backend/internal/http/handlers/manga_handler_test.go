@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/comment"
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	libraryservice "github.com/ngocan-dev/mangahub/backend/internal/service/library"
+	"github.com/ngocan-dev/mangahub/backend/internal/testdb"
+)
+
+// newReviewTestRouter serves the review edit routes with alice (1) owning
+// review 1 and bob (2) owning review 2. The X-User-Id header stands in for
+// the auth middleware.
+func newReviewTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL, avatar_url TEXT);
+    CREATE TABLE ratings (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+        review TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        Hidden_At DATETIME
+    );
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob');
+    INSERT INTO ratings (id, user_id, manga_id, score, review) VALUES
+        (1, 1, 10, 4, 'A thoughtful review'),
+        (2, 2, 10, 5, 'Loved every chapter');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	h := &MangaHandler{DB: db, reviewService: comment.NewService(comment.NewRepository(db), nil, nil)}
+	asUser := func(c *gin.Context) {
+		if id, err := strconv.ParseInt(c.GetHeader("X-User-Id"), 10, 64); err == nil {
+			c.Set("user_id", id)
+		}
+	}
+	r := gin.New()
+	r.PUT("/reviews/:id", asUser, h.UpdateReview)
+	r.DELETE("/reviews/:id", asUser, h.DeleteReview)
+	return r
+}
+
+func serveAsUser(r *gin.Engine, method, path, body string, userID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-Id", strconv.Itoa(userID))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUpdateReviewHandler(t *testing.T) {
+	r := newReviewTestRouter(t)
+	body := `{"rating": 3, "content": "Still good on a second read"}`
+
+	if rec := serveAsUser(r, http.MethodPut, "/reviews/1", body, 1); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 editing own review, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAsUser(r, http.MethodPut, "/reviews/1", body, 2); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 editing another user's review, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAsUser(r, http.MethodPut, "/reviews/99", body, 1); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing review, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAsUser(r, http.MethodPut, "/reviews/1", `{"rating": 7}`, 1); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a rating outside 1-5, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDeleteReviewHandler(t *testing.T) {
+	r := newReviewTestRouter(t)
+
+	if rec := serveAsUser(r, http.MethodDelete, "/reviews/2", "", 1); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 deleting another user's review, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAsUser(r, http.MethodDelete, "/reviews/2", "", 2); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 deleting own review, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serveAsUser(r, http.MethodDelete, "/reviews/2", "", 2); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a deleted review, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetReviewsShowsOwnVoteOnlyForValidTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL, avatar_url TEXT);
+    CREATE TABLE ratings (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        score INTEGER NOT NULL,
+        review TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        Hidden_At DATETIME
+    );
+    CREATE TABLE Review_Votes (
+        Review_Id INTEGER NOT NULL,
+        User_Id INTEGER NOT NULL,
+        Vote INTEGER NOT NULL,
+        PRIMARY KEY (Review_Id, User_Id)
+    );
+    CREATE TABLE Revoked_Tokens (
+        Jti TEXT PRIMARY KEY,
+        User_Id INTEGER NOT NULL,
+        Expires_At DATETIME NOT NULL,
+        Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+    INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob');
+    INSERT INTO ratings (id, user_id, manga_id, score, review) VALUES (1, 1, 10, 4, 'A thoughtful review');
+    INSERT INTO Review_Votes (Review_Id, User_Id, Vote) VALUES (1, 2, 1);
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	defer auth.SetRevocationStore(nil)
+
+	token, err := auth.GenerateToken(2, "bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	h := &MangaHandler{DB: db, reviewService: comment.NewService(comment.NewRepository(db), nil, nil)}
+	r := gin.New()
+	r.GET("/mangas/:id/reviews", h.GetReviews)
+
+	myVote := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/mangas/10/reviews", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp comment.GetReviewsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Data) != 1 {
+			t.Fatalf("unexpected response %s: %v", rec.Body.String(), err)
+		}
+		return resp.Data[0].MyVote
+	}
+
+	if got := myVote(); got != comment.VoteHelpful {
+		t.Fatalf("expected bob's helpful vote, got %q", got)
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+	if got := myVote(); got != "" {
+		t.Fatalf("expected no vote for a revoked token, got %q", got)
+	}
+}
+
+func TestExportFailureReturnsPlainJSONError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// No tables exist, so both exports fail before writing anything
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	h := &MangaHandler{
+		DB:             db,
+		libraryService: libraryservice.NewService(libraryrepository.NewRepository(db), nil, nil),
+		historyService: history.NewService(history.NewRepository(db), nil, nil, nil),
+	}
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", int64(1)) })
+	r.GET("/library/export", h.ExportLibrary)
+	r.GET("/analytics/export", h.ExportReadingAnalytics)
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/library/export?format=csv", `{"error":"unable to export library"}`},
+		{"/analytics/export?format=csv", `{"error":"unable to export reading analytics"}`},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("%s: expected 500, got %d: %s", tc.path, rec.Code, rec.Body.String())
+		}
+		if _, ok := rec.Header()["Content-Disposition"]; ok {
+			t.Fatalf("%s: expected no Content-Disposition on an error, got %q", tc.path, rec.Header().Get("Content-Disposition"))
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Fatalf("%s: expected a JSON error, got Content-Type %q", tc.path, ct)
+		}
+		if body := rec.Body.String(); body != tc.want {
+			t.Fatalf("%s: expected a fixed error message, got %s", tc.path, body)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/library/export?format=xlsx", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unsupported format, got %d", rec.Code)
+	}
+}
+
+func TestGetFriendsActivityFeedHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// viewer (100) is friends with open (101) and private (102), who hides
+	// reviews; stranger (103) is not a friend
+	db := testdb.Migrated(t)
+	if _, err := db.Exec(`
+    INSERT INTO users (id, username, email, password_hash) VALUES
+        (100, 'viewer', 'viewer@example.com', 'x'),
+        (101, 'open', 'open@example.com', 'x'),
+        (102, 'private', 'private@example.com', 'x'),
+        (103, 'stranger', 'stranger@example.com', 'x');
+    INSERT INTO friends (user_id, friend_user_id, status) VALUES
+        (100, 101, 'accepted'),
+        (102, 100, 'accepted'),
+        (103, 100, 'pending');
+    INSERT INTO User_Privacy (User_Id, Share_Reviews) VALUES (102, 0);
+    INSERT INTO activities (user_id, type, manga_id, created_at) VALUES
+        (101, 'read', 2, '2026-01-01 10:00:00'),
+        (101, 'review', 2, '2026-01-01 11:00:00'),
+        (101, 'rating', 2, '2026-01-01 11:00:00'),
+        (102, 'review', 6, '2026-01-01 12:00:00'),
+        (102, 'rating', 6, '2026-01-01 12:00:00'),
+        (103, 'read', 2, '2026-01-01 13:00:00');
+    `); err != nil {
+		t.Fatalf("failed to seed activities: %v", err)
+	}
+
+	h := &MangaHandler{DB: db, historyService: history.NewService(history.NewRepository(db), nil, nil, nil)}
+	asUser := func(c *gin.Context) {
+		if id, err := strconv.ParseInt(c.GetHeader("X-User-Id"), 10, 64); err == nil {
+			c.Set("user_id", id)
+		}
+	}
+	r := gin.New()
+	r.GET("/friends/activity", asUser, h.GetFriendsActivityFeed)
+
+	feed := func(query string) []string {
+		t.Helper()
+		rec := serveAsUser(r, http.MethodGet, "/friends/activity"+query, "", 100)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var resp history.ActivityFeedResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%q: failed to decode response: %v", query, err)
+		}
+		if resp.Total != len(resp.Activities) {
+			t.Fatalf("%q: total %d does not match %d activities", query, resp.Total, len(resp.Activities))
+		}
+		got := make([]string, 0, len(resp.Activities))
+		for _, a := range resp.Activities {
+			got = append(got, a.Username+":"+strings.ToLower(a.ActivityType))
+		}
+		return got
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		// open's rating folds into the review posted with it; private's
+		// review is hidden, so the rating stands on its own
+		{"", []string{"private:rating", "open:review", "open:read"}},
+		{"?types=read", []string{"open:read"}},
+		{"?types=review", []string{"open:review"}},
+		{"?types=rating,review", []string{"private:rating", "open:review"}},
+	}
+	for _, tc := range cases {
+		if got := feed(tc.query); strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%q: expected %v, got %v", tc.query, tc.want, got)
+		}
+	}
+
+	if rec := serveAsUser(r, http.MethodGet, "/friends/activity?types=likes", "", 100); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown type, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/friends/activity", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", rec.Code)
+	}
+}
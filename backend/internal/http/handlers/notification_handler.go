@@ -5,11 +5,13 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	"github.com/ngocan-dev/mangahub/backend/domain/notification"
 	"github.com/ngocan-dev/mangahub/backend/internal/auth"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
@@ -17,8 +19,9 @@ import (
 )
 
 type NotificationHandler struct {
-	DB       *sql.DB
-	notifier *udp.Notifier
+	DB            *sql.DB
+	notifier      *udp.Notifier
+	notifications *notification.Service
 }
 
 func NewNotificationHandler(db *sql.DB, notifier *udp.Notifier) *NotificationHandler {
@@ -33,6 +36,11 @@ func (h *NotificationHandler) SetNotifier(notifier *udp.Notifier) {
 	h.notifier = notifier
 }
 
+// SetNotificationService enables the notification history endpoints
+func (h *NotificationHandler) SetNotificationService(svc *notification.Service) {
+	h.notifications = svc
+}
+
 // NotifyChapterReleaseRequest represents request to notify about chapter release
 type NotifyChapterReleaseRequest struct {
 	NovelID   int64 `json:"novel_id" binding:"required"`
@@ -114,6 +122,135 @@ func (h *NotificationHandler) NotifyChapterRelease(c *gin.Context) {
 	})
 }
 
+// ListNotifications handles GET /notifications?page=&limit=&unread=true
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", defaultPageLimit()))
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	resp, err := h.notifications.List(c.Request.Context(), userID, unreadOnly, page, limit)
+	if err != nil {
+		log.Printf("handler.ListNotifications: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkNotificationRead handles POST /notifications/:id/read
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	notificationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || notificationID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	resp, err := h.notifications.MarkRead(c.Request.Context(), userID, notificationID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, resp)
+	case errors.Is(err, notification.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.MarkNotificationRead: user_id=%d notification_id=%d err=%v", userID, notificationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification"})
+	}
+}
+
+// MarkAllNotificationsRead handles POST /notifications/read-all
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.notifications.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.MarkAllNotificationsRead: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Subscribe handles POST /mangas/:id/subscribe
+func (h *NotificationHandler) Subscribe(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	err = h.notifications.Subscribe(c.Request.Context(), userID, mangaID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, gin.H{"message": "subscribed", "manga_id": mangaID})
+	case errors.Is(err, notification.ErrMangaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, notification.ErrAlreadySubscribed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.Subscribe: user_id=%d manga_id=%d err=%v", userID, mangaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to subscribe"})
+	}
+}
+
+// Unsubscribe handles DELETE /mangas/:id/subscribe
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	mangaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || mangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid manga id"})
+		return
+	}
+
+	err = h.notifications.Unsubscribe(c.Request.Context(), userID, mangaID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "unsubscribed", "manga_id": mangaID})
+	case errors.Is(err, notification.ErrNotSubscribed):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.Unsubscribe: user_id=%d manga_id=%d err=%v", userID, mangaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unsubscribe"})
+	}
+}
+
+// ListSubscriptions handles GET /subscriptions
+func (h *NotificationHandler) ListSubscriptions(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.notifications.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.ListSubscriptions: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load subscriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func getNotificationClaims(c *gin.Context) (*auth.Claims, bool) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
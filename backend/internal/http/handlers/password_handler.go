@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+)
+
+// PasswordResetSender delivers reset tokens to users, e.g. by email
+type PasswordResetSender interface {
+	SendPasswordReset(ctx context.Context, email, token string) error
+}
+
+// SetPasswordResetSender configures how reset tokens reach users. Without a
+// sender, reset requests are accepted but no token is delivered.
+func (h *UserHandler) SetPasswordResetSender(sender PasswordResetSender) {
+	h.resetSender = sender
+}
+
+// ChangePassword handles POST /me/password
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req user.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "current_password and new_password are required"})
+		return
+	}
+
+	err := user.ChangePassword(c.Request.Context(), h.DB, userID, req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "password changed"})
+	case errors.Is(err, user.ErrIncorrectPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case isValidationError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.ChangePassword: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// RequestPasswordReset handles POST /password/reset/request. The response is
+// the same whether or not the email belongs to an account.
+func (h *UserHandler) RequestPasswordReset(c *gin.Context) {
+	var req user.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := user.RequestPasswordReset(ctx, h.DB, req.Email)
+	if err != nil {
+		log.Printf("handler.RequestPasswordReset: err=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if token != "" {
+		if h.resetSender == nil {
+			log.Printf("handler.RequestPasswordReset: no reset sender configured; token not delivered")
+		} else if err := h.resetSender.SendPasswordReset(ctx, req.Email, token); err != nil {
+			log.Printf("handler.RequestPasswordReset: delivery failed: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered, a reset link has been sent"})
+}
+
+// ConfirmPasswordReset handles POST /password/reset/confirm
+func (h *UserHandler) ConfirmPasswordReset(c *gin.Context) {
+	var req user.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token and new_password are required"})
+		return
+	}
+
+	err := user.ConfirmPasswordReset(c.Request.Context(), h.DB, req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "password has been reset"})
+	case errors.Is(err, user.ErrResetTokenInvalid), errors.Is(err, user.ErrResetTokenExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case isValidationError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.ConfirmPasswordReset: err=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+func TestPasswordResetWithLogSenderLetsUserLogIn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("oldpass123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	if _, err := db.Exec(`
+    CREATE TABLE users (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        username TEXT NOT NULL UNIQUE,
+        email TEXT NOT NULL UNIQUE,
+        password_hash TEXT NOT NULL,
+        role TEXT,
+        updated_at DATETIME
+    );
+    CREATE TABLE Password_Reset_Tokens (
+        Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+        User_Id INTEGER NOT NULL,
+        Token_Hash TEXT NOT NULL UNIQUE,
+        Expires_At DATETIME NOT NULL,
+        Used_At DATETIME,
+        Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+    CREATE TABLE Refresh_Tokens (
+        Token_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+        User_Id INTEGER NOT NULL,
+        Token_Hash TEXT NOT NULL UNIQUE,
+        Expires_At DATETIME NOT NULL,
+        Revoked INTEGER NOT NULL DEFAULT 0,
+        Revoked_At DATETIME,
+        Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO users (username, email, password_hash) VALUES ('alice', 'alice@example.com', ?)`, string(hashed)); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	// The log sender is the delivery channel, so the link is read back from the log
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&logs, slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	userHandler := NewUserHandler(db)
+	userHandler.SetPasswordResetSender(user.NewLogResetSender("http://localhost:3000/reset-password"))
+	authHandler := NewAuthHandler(db)
+	r := gin.New()
+	r.POST("/password/reset/request", userHandler.RequestPasswordReset)
+	r.POST("/password/reset/confirm", userHandler.ConfirmPasswordReset)
+	r.POST("/login", authHandler.Login)
+
+	post := func(path, body string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("/password/reset/request", `{"email":"alice@example.com"}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202 for the reset request, got %d", code)
+	}
+
+	var token string
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry struct {
+			Email    string `json:"email"`
+			ResetURL string `json:"reset_url"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil || entry.ResetURL == "" {
+			continue
+		}
+		if entry.Email != "alice@example.com" {
+			t.Fatalf("expected the link to be addressed to alice, got %q", entry.Email)
+		}
+		link, err := url.Parse(entry.ResetURL)
+		if err != nil {
+			t.Fatalf("failed to parse reset link %q: %v", entry.ResetURL, err)
+		}
+		token = link.Query().Get("token")
+	}
+	if token == "" {
+		t.Fatalf("expected a reset link in the log, got %q", logs.String())
+	}
+
+	if code := post("/password/reset/confirm", `{"token":"`+token+`","new_password":"newpass456"}`); code != http.StatusOK {
+		t.Fatalf("expected 200 for the reset confirmation, got %d", code)
+	}
+	if code := post("/login", `{"email":"alice@example.com","password":"oldpass123"}`); code != http.StatusUnauthorized {
+		t.Fatalf("expected the old password to be rejected, got %d", code)
+	}
+	if code := post("/login", `{"email":"alice@example.com","password":"newpass456"}`); code != http.StatusOK {
+		t.Fatalf("expected login with the new password to succeed, got %d", code)
+	}
+}
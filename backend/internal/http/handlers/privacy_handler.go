@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/user"
+)
+
+// GetPrivacy handles GET /me/privacy
+func (h *UserHandler) GetPrivacy(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := user.GetPrivacy(c.Request.Context(), h.DB, userID)
+	if err != nil {
+		log.Printf("handler.GetPrivacy: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdatePrivacy handles PUT /me/privacy
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req user.UpdatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid privacy settings"})
+		return
+	}
+
+	settings, err := user.UpdatePrivacy(c.Request.Context(), h.DB, userID, req)
+	if err != nil {
+		log.Printf("handler.UpdatePrivacy: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
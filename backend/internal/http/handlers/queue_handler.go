@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/queue"
+)
+
+// QueueHandler exposes write queue administration endpoints
+type QueueHandler struct {
+	writeQueue *queue.WriteQueue
+}
+
+// NewQueueHandler constructs a QueueHandler
+func NewQueueHandler(writeQueue *queue.WriteQueue) *QueueHandler {
+	return &QueueHandler{writeQueue: writeQueue}
+}
+
+// ListDeadLetters returns operations that exhausted their retries
+func (h *QueueHandler) ListDeadLetters(c *gin.Context) {
+	letters := h.writeQueue.DeadLetters()
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"total":        len(letters),
+	})
+}
+
+// RetryDeadLetter moves a dead letter back onto the write queue
+func (h *QueueHandler) RetryDeadLetter(c *gin.Context) {
+	id := c.Param("id")
+
+	op, err := h.writeQueue.RetryDeadLetter(id)
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("handler.RetryDeadLetter: id=%s err=%v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to requeue operation"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "operation requeued",
+		"operation": op,
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+)
+
+// StartReadingSession handles POST /reading/sessions/start
+func (h *MangaHandler) StartReadingSession(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req history.StartReadingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.MangaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "manga_id is required"})
+		return
+	}
+
+	session, err := h.historyService.StartReadingSession(c.Request.Context(), userID, req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, session)
+	case errors.Is(err, history.ErrMangaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.StartReadingSession: user_id=%d manga_id=%d err=%v", userID, req.MangaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to start reading session"})
+	}
+}
+
+// EndReadingSession handles POST /reading/sessions/end
+func (h *MangaHandler) EndReadingSession(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req history.EndReadingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.SessionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required"})
+		return
+	}
+
+	session, err := h.historyService.EndReadingSession(c.Request.Context(), userID, req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, session)
+	case errors.Is(err, history.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, history.ErrSessionAlreadyEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.EndReadingSession: user_id=%d session_id=%d err=%v", userID, req.SessionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to end reading session"})
+	}
+}
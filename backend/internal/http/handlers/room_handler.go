@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/room"
+)
+
+// RoomHandler manages chat room creation and membership
+type RoomHandler struct {
+	service *room.Service
+}
+
+// NewRoomHandler constructs a RoomHandler
+func NewRoomHandler(service *room.Service) *RoomHandler {
+	return &RoomHandler{service: service}
+}
+
+// List returns the public rooms and the private rooms the user belongs to
+func (h *RoomHandler) List(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListRooms(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.ListRooms: user_id=%d err=%v", userID, err)
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Create makes a private room owned by the authenticated user
+func (h *RoomHandler) Create(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req room.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	created, err := h.service.CreateRoom(c.Request.Context(), userID, req)
+	if err != nil {
+		log.Printf("handler.CreateRoom: user_id=%d err=%v", userID, err)
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// AddMember admits a user to a private room
+func (h *RoomHandler) AddMember(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	roomID, ok := parseRoomID(c)
+	if !ok {
+		return
+	}
+
+	var req room.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.UserID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+
+	updated, err := h.service.AddMember(c.Request.Context(), userID, roomID, req.UserID)
+	if err != nil {
+		log.Printf("handler.AddRoomMember: user_id=%d room_id=%d member_id=%d err=%v", userID, roomID, req.UserID, err)
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// RemoveMember removes a user from a private room
+func (h *RoomHandler) RemoveMember(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	roomID, ok := parseRoomID(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil || memberID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	updated, err := h.service.RemoveMember(c.Request.Context(), userID, roomID, memberID)
+	if err != nil {
+		log.Printf("handler.RemoveRoomMember: user_id=%d room_id=%d member_id=%d err=%v", userID, roomID, memberID, err)
+		c.JSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func parseRoomID(c *gin.Context) (int64, bool) {
+	roomID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || roomID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
+		return 0, false
+	}
+	return roomID, true
+}
+
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, room.ErrInvalidRoomName),
+		errors.Is(err, room.ErrPublicRoom),
+		errors.Is(err, room.ErrOwnerCannotLeave):
+		return http.StatusBadRequest
+	case errors.Is(err, room.ErrNotRoomOwner):
+		return http.StatusForbidden
+	case errors.Is(err, room.ErrAlreadyMember):
+		return http.StatusConflict
+	case errors.Is(err, room.ErrRoomNotFound),
+		errors.Is(err, room.ErrUserNotFound),
+		errors.Is(err, room.ErrNotMember):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -17,9 +17,11 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/cache"
 	"github.com/ngocan-dev/mangahub/backend/internal/queue"
 	"github.com/ngocan-dev/mangahub/backend/internal/tcp"
 	"github.com/ngocan-dev/mangahub/backend/internal/udp"
+	"github.com/ngocan-dev/mangahub/backend/internal/version"
 	mangapb "github.com/ngocan-dev/mangahub/backend/proto/manga"
 )
 
@@ -50,11 +52,20 @@ type ResourceStatus struct {
 
 // ServerStatus is the full payload returned to the CLI.
 type ServerStatus struct {
-	Overall   string          `json:"overall"`
-	Services  []ServiceStatus `json:"services"`
-	Database  DatabaseStatus  `json:"database"`
-	Resources ResourceStatus  `json:"resources"`
-	Issues    []string        `json:"issues"`
+	Version   string            `json:"version"`
+	GitCommit string            `json:"git_commit"`
+	BuildTime string            `json:"build_time"`
+	Overall   string            `json:"overall"`
+	Services  []ServiceStatus   `json:"services"`
+	Database  DatabaseStatus    `json:"database"`
+	Resources ResourceStatus    `json:"resources"`
+	Cache     *cache.CacheStats `json:"cache,omitempty"`
+	Issues    []string          `json:"issues"`
+}
+
+// CacheStatsSource is implemented by cache.MangaCache.
+type CacheStatsSource interface {
+	Stats() cache.CacheStats
 }
 
 // StatusHandler exposes the server status endpoint backed by real runtime data.
@@ -65,6 +76,7 @@ type StatusHandler struct {
 	writeQueue  *queue.WriteQueue
 	tcpServer   *tcp.Server
 	udpServer   *udp.Server
+	cache       CacheStatsSource
 	dsn         string
 	apiAddress  string
 	grpcAddress string
@@ -94,6 +106,11 @@ func (h *StatusHandler) SetUDPServer(server *udp.Server) {
 	h.udpServer = server
 }
 
+// SetCache wires the manga cache whose counters are reported in the status.
+func (h *StatusHandler) SetCache(source CacheStatsSource) {
+	h.cache = source
+}
+
 // SetAddresses configures advertised service addresses.
 func (h *StatusHandler) SetAddresses(api, grpcAddr, tcpAddr, udpAddr string) {
 	h.apiAddress = api
@@ -107,6 +124,39 @@ func (h *StatusHandler) SetWSAddress(addr string) {
 	h.wsAddress = addr
 }
 
+// Healthz is a liveness probe: it answers as long as the process serves HTTP.
+func (h *StatusHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz is a readiness probe: it checks only the database so load balancers
+// can poll it cheaply, and returns 503 while the database is unavailable.
+func (h *StatusHandler) Readyz(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database not configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 500*time.Millisecond)
+	defer cancel()
+
+	if err := h.db.PingContext(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": fmt.Sprintf("database ping failed: %v", err)})
+		return
+	}
+	if h.dbHealth != nil && !h.dbHealth.IsHealthy() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database connection is unhealthy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// GetVersion reports which build of the server is running.
+func (h *StatusHandler) GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
+
 // GetStatus aggregates live status information for the CLI.
 func (h *StatusHandler) GetStatus(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
@@ -117,16 +167,30 @@ func (h *StatusHandler) GetStatus(c *gin.Context) {
 	resources := collectResources()
 
 	issues := append(serviceIssues, dbIssues...)
+	var cacheStats *cache.CacheStats
+	if h.cache != nil {
+		stats := h.cache.Stats()
+		cacheStats = &stats
+		if !stats.Available {
+			issues = append(issues, "redis cache unavailable; serving from the database")
+		}
+	}
+
 	overall := "healthy"
 	if len(issues) > 0 {
 		overall = "degraded"
 	}
 
+	build := version.Get()
 	status := ServerStatus{
+		Version:   build.Version,
+		GitCommit: build.GitCommit,
+		BuildTime: build.BuildTime,
 		Overall:   overall,
 		Services:  services,
 		Database:  dbStatus,
 		Resources: resources,
+		Cache:     cacheStats,
 		Issues:    issues,
 	}
 
@@ -242,6 +306,9 @@ func (h *StatusHandler) collectServices(ctx context.Context) ([]ServiceStatus, [
 			} else {
 				udpLoad = fmt.Sprintf("%d clients", stats.Clients)
 			}
+			if stats.Unacked > 0 {
+				udpLoad = fmt.Sprintf("%s, %d unacked", udpLoad, stats.Unacked)
+			}
 		} else {
 			udpError = "UDP notification server is not accepting packets"
 			issues = append(issues, udpError)
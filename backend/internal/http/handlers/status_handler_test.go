@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/db"
+	"github.com/ngocan-dev/mangahub/backend/internal/version"
+)
+
+func newProbeTestRouter(t *testing.T, dbConn *sql.DB) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	monitor := db.NewHealthMonitor(dbConn, time.Minute, time.Minute)
+	h := NewStatusHandler(time.Now(), dbConn, monitor, nil, "")
+
+	r := gin.New()
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+	r.GET("/server/status", h.GetStatus)
+	r.GET("/version", h.GetVersion)
+	return r
+}
+
+func TestProbesWithHealthyDatabase(t *testing.T) {
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { dbConn.Close() })
+	r := newProbeTestRouter(t, dbConn)
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestReadyzReportsUnavailableWhenDatabaseIsDown(t *testing.T) {
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	dbConn.Close()
+	r := newProbeTestRouter(t, dbConn)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected liveness to stay 200 while the database is down, got %d", rec.Code)
+	}
+}
+
+func TestVersionFieldsComeFromBuildVars(t *testing.T) {
+	prevVersion, prevCommit, prevBuildTime := version.Version, version.GitCommit, version.BuildTime
+	t.Cleanup(func() {
+		version.Version, version.GitCommit, version.BuildTime = prevVersion, prevCommit, prevBuildTime
+	})
+	// What -ldflags "-X ..." would set
+	version.Version = "1.4.0"
+	version.GitCommit = "abc1234"
+	version.BuildTime = "2024-05-01T10:00:00Z"
+
+	dbConn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { dbConn.Close() })
+	r := newProbeTestRouter(t, dbConn)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/version: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var info version.Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode /version: %v", err)
+	}
+	want := version.Info{Version: "1.4.0", GitCommit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}
+	if info != want {
+		t.Fatalf("/version returned %+v, want %+v", info, want)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/server/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/server/status: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var status ServerStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode /server/status: %v", err)
+	}
+	if status.Version != want.Version || status.GitCommit != want.GitCommit || status.BuildTime != want.BuildTime {
+		t.Fatalf("status reported version %q, commit %q, build time %q; want %+v",
+			status.Version, status.GitCommit, status.BuildTime, want)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+)
+
+// StreakHandler manages reading streak endpoints
+type StreakHandler struct {
+	service *history.Service
+}
+
+// NewStreakHandler constructs a StreakHandler
+func NewStreakHandler(service *history.Service) *StreakHandler {
+	return &StreakHandler{service: service}
+}
+
+// Freeze handles POST /streaks/freeze, spending a freeze on a missed day.
+// The body is optional; without a date yesterday is frozen.
+func (h *StreakHandler) Freeze(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req history.StreakFreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	resp, err := h.service.ApplyStreakFreeze(c.Request.Context(), userID, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, history.ErrInvalidFreezeDate):
+			status = http.StatusBadRequest
+		case errors.Is(err, history.ErrFreezeNotNeeded),
+			errors.Is(err, history.ErrDayAlreadyFrozen),
+			errors.Is(err, history.ErrNoStreakFreezes):
+			status = http.StatusConflict
+		default:
+			log.Printf("handler.FreezeStreak: user_id=%d err=%v", userID, err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
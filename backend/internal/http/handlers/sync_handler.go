@@ -3,9 +3,12 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -75,6 +78,57 @@ func (h *SyncStatusHandler) GetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// ListDevices handles GET /sync/devices
+func (h *SyncStatusHandler) ListDevices(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if h.tcpServer == nil {
+		c.JSON(http.StatusOK, gin.H{"devices": []tcp.Device{}, "total": 0})
+		return
+	}
+
+	devices, err := h.tcpServer.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("handler.ListDevices: user_id=%d err=%v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load devices"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"devices": devices, "total": len(devices)})
+}
+
+// RevokeDevice handles DELETE /sync/devices/:id
+func (h *SyncStatusHandler) RevokeDevice(c *gin.Context) {
+	userID, ok := RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || sessionID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid device id"})
+		return
+	}
+
+	if h.tcpServer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": tcp.ErrDeviceNotFound.Error()})
+		return
+	}
+
+	err = h.tcpServer.RevokeDevice(c.Request.Context(), userID, sessionID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "device revoked", "id": sessionID})
+	case errors.Is(err, tcp.ErrDeviceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Printf("handler.RevokeDevice: user_id=%d device_id=%d err=%v", userID, sessionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke device"})
+	}
+}
+
 func (h *SyncStatusHandler) collectLocalStatus(ctx context.Context) SyncLayerStatus {
 	if h.db == nil {
 		return SyncLayerStatus{OK: false, Message: "database connection is not configured"}
@@ -18,7 +18,10 @@ import (
 )
 
 type UserHandler struct {
-	DB *sql.DB
+	DB          *sql.DB
+	resetSender PasswordResetSender
+	avatars     *user.AvatarStore
+	connections []ConnectionCloser
 }
 
 func NewUserHandler(db *sql.DB) *UserHandler {
@@ -92,7 +95,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	// Step 5: success
-	token, err := auth.GenerateToken(u.ID, u.Username, u.Email)
+	token, err := auth.GenerateTokenWithRole(u.ID, u.Username, u.Email, auth.RoleUser)
 	if err != nil {
 		log.Printf("register: token generation failed user_id=%d: %v", u.ID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create session token"})
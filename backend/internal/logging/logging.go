@@ -0,0 +1,49 @@
+// Package logging provides the structured JSON logger shared by the servers
+// and carries the request ID through contexts so a single request can be
+// traced from handler to service to repository.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+type requestIDKey struct{}
+
+// New returns a JSON logger writing to w
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Setup installs a JSON logger on stderr as the process default. Output from
+// the standard log package is routed through it as well.
+func Setup() *slog.Logger {
+	logger := New(os.Stderr, slog.LevelInfo)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" when there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the request ID when ctx has one
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		return logger.With(slog.String("request_id", id))
+	}
+	return logger
+}
@@ -20,3 +20,8 @@ type GetDetails interface {
 type GetByID interface {
 	GetByID(ctx context.Context, mangaID int64) (*domain.Manga, error)
 }
+
+// MatchTitle exposes fuzzy title lookup
+type MatchTitle interface {
+	MatchTitle(ctx context.Context, title string) (*domain.Manga, error)
+}
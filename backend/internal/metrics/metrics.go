@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/cache"
+)
+
+// Exported metric names:
+//
+//	mangahub_http_requests_total{method,route,status}    counter   HTTP requests handled
+//	mangahub_http_request_duration_seconds{method,route} histogram HTTP request latency
+//	mangahub_tcp_connected_clients                       gauge     TCP progress sync clients
+//	mangahub_udp_connected_clients                       gauge     UDP notification clients
+//	mangahub_websocket_connected_clients                 gauge     WebSocket chat connections
+//	mangahub_write_queue_depth                           gauge     Writes waiting for the database
+//	mangahub_write_queue_dead_letters                    gauge     Writes that exhausted their retries
+//	mangahub_cache_hits_total                            counter   Manga cache reads served from Redis
+//	mangahub_cache_misses_total                          counter   Manga cache reads that fell through
+//	mangahub_cache_hit_ratio                             gauge     hits / (hits + misses), 0 before any read
+//	mangahub_cache_available                             gauge     1 while the manga cache can reach Redis
+//	mangahub_db_healthy                                  gauge     1 when the database health check passes
+//
+// The Go runtime and process collectors are registered as well.
+const namespace = "mangahub"
+
+// ClientCounter is implemented by the TCP and UDP servers
+type ClientCounter interface {
+	GetClientCount() int
+}
+
+// ConnectionCounter is implemented by the WebSocket chat hub
+type ConnectionCounter interface {
+	ConnectionCount() int
+}
+
+// QueueSource is implemented by queue.WriteQueue
+type QueueSource interface {
+	Size() int
+	DeadLetterCount() int
+}
+
+// CacheSource is implemented by cache.MangaCache
+type CacheSource interface {
+	Stats() cache.CacheStats
+}
+
+// HealthSource is implemented by db.HealthMonitor
+type HealthSource interface {
+	IsHealthy() bool
+}
+
+// Metrics owns the Prometheus registry for the API server
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// New creates a registry with the HTTP collectors registered
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency in seconds, by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Middleware records request counts and latency per matched route
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// Use the route template so IDs in paths do not explode cardinality
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		m.requests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		m.requestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return gin.WrapH(h)
+}
+
+// SetTCPServer exposes the TCP client gauge
+func (m *Metrics) SetTCPServer(server ClientCounter) {
+	m.gauge("tcp_connected_clients", "Connected TCP progress sync clients.", func() float64 {
+		return float64(server.GetClientCount())
+	})
+}
+
+// SetUDPServer exposes the UDP client gauge
+func (m *Metrics) SetUDPServer(server ClientCounter) {
+	m.gauge("udp_connected_clients", "Registered UDP notification clients.", func() float64 {
+		return float64(server.GetClientCount())
+	})
+}
+
+// SetWebSocketHub exposes the WebSocket connection gauge
+func (m *Metrics) SetWebSocketHub(hub ConnectionCounter) {
+	m.gauge("websocket_connected_clients", "Open WebSocket chat connections.", func() float64 {
+		return float64(hub.ConnectionCount())
+	})
+}
+
+// SetWriteQueue exposes write queue depth and dead letters
+func (m *Metrics) SetWriteQueue(queue QueueSource) {
+	m.gauge("write_queue_depth", "Write operations waiting for the database.", func() float64 {
+		return float64(queue.Size())
+	})
+	m.gauge("write_queue_dead_letters", "Write operations that exhausted their retries.", func() float64 {
+		return float64(queue.DeadLetterCount())
+	})
+}
+
+// SetCache exposes cache hit and miss counters, their ratio and whether
+// Redis is reachable
+func (m *Metrics) SetCache(source CacheSource) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Manga cache reads served from Redis.",
+		}, func() float64 {
+			return float64(source.Stats().Hits)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Manga cache reads that were not in Redis.",
+		}, func() float64 {
+			return float64(source.Stats().Misses)
+		}),
+	)
+	m.gauge("cache_hit_ratio", "Share of manga cache reads served from Redis.", func() float64 {
+		stats := source.Stats()
+		if stats.Hits+stats.Misses == 0 {
+			return 0
+		}
+		return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+	})
+	m.gauge("cache_available", "1 while the manga cache can reach Redis, 0 otherwise.", func() float64 {
+		if source.Stats().Available {
+			return 1
+		}
+		return 0
+	})
+}
+
+// SetDBHealth exposes the database health gauge
+func (m *Metrics) SetDBHealth(health HealthSource) {
+	m.gauge("db_healthy", "1 when the database health check passes, 0 otherwise.", func() float64 {
+		if health.IsHealthy() {
+			return 1
+		}
+		return 0
+	})
+}
+
+func (m *Metrics) gauge(name, help string, fn func() float64) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, fn))
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fixedHealth bool
+
+func (h fixedHealth) IsHealthy() bool { return bool(h) }
+
+func TestMetricsEndpointReportsRequestsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := New()
+	m.SetDBHealth(fixedHealth(true))
+
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/mangas/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", m.Handler())
+
+	for _, path := range []string{"/mangas/1", "/mangas/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`mangahub_http_requests_total{method="GET",route="/mangas/:id",status="200"} 2`,
+		`mangahub_http_request_duration_seconds_count{method="GET",route="/mangas/:id"} 2`,
+		`mangahub_db_healthy 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics output to contain %q", want)
+		}
+	}
+}
@@ -2,18 +2,41 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CORSConfig describes which cross-origin browser requests are allowed
+type CORSConfig struct {
+	// AllowedOrigins are matched case-sensitively; "*" allows any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight result
+	MaxAge time.Duration
+}
+
+// Default CORS settings used when a list is left empty
+var (
+	DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	DefaultCORSHeaders = []string{"Authorization", "Content-Type", "X-Requested-With", RequestIDHeader, IdempotencyKeyHeader}
+	DefaultCORSExposed = []string{RequestIDHeader, IdempotentReplayHeader, "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+)
+
 // CORSMiddleware configures CORS headers and handles preflight requests.
-// Allowed origins are matched case-sensitively against the provided list.
-// An origin of "*" allows all origins.
-func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+// The matching origin is always echoed back rather than "*", which browsers
+// require when credentials are allowed. OPTIONS requests are answered with
+// 204 without reaching the handlers, and preflights from origins outside the
+// allowlist are rejected with 403.
+func CORSMiddleware(cfg CORSConfig) gin.HandlerFunc {
 	allowAll := false
-	origins := make(map[string]struct{}, len(allowedOrigins))
-	for _, origin := range allowedOrigins {
+	origins := make(map[string]struct{}, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
 		o := strings.TrimSpace(origin)
 		if o == "" {
 			continue
@@ -24,14 +47,44 @@ func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 		origins[o] = struct{}{}
 	}
 
+	methods := strings.Join(orDefault(cfg.AllowedMethods, DefaultCORSMethods), ", ")
+	headers := strings.Join(orDefault(cfg.AllowedHeaders, DefaultCORSHeaders), ", ")
+	exposed := strings.Join(orDefault(cfg.ExposedHeaders, DefaultCORSExposed), ", ")
+	maxAge := ""
+	if cfg.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(cfg.MaxAge / time.Second))
+	}
+
 	return func(c *gin.Context) {
 		origin := c.GetHeader("Origin")
-		if origin != "" && (allowAll || containsOrigin(origins, origin)) {
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if origin != "" {
+			c.Writer.Header().Add("Vary", "Origin")
+			if !allowAll && !containsOrigin(origins, origin) {
+				if preflight {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				// Without CORS headers the browser refuses to expose the response
+				c.Next()
+				return
+			}
+
 			c.Header("Access-Control-Allow-Origin", origin)
-			c.Header("Vary", "Origin")
-			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Requested-With")
-			c.Header("Access-Control-Max-Age", "600")
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+			if preflight {
+				c.Header("Access-Control-Allow-Methods", methods)
+				c.Header("Access-Control-Allow-Headers", headers)
+				if maxAge != "" {
+					c.Header("Access-Control-Max-Age", maxAge)
+				}
+			}
 		}
 
 		if c.Request.Method == http.MethodOptions {
@@ -47,3 +100,10 @@ func containsOrigin(origins map[string]struct{}, origin string) bool {
 	_, ok := origins[origin]
 	return ok
 }
+
+func orDefault(values, fallback []string) []string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(cfg CORSConfig, reached *bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CORSMiddleware(cfg))
+	handler := func(c *gin.Context) {
+		*reached = true
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+	r.GET("/mangas", handler)
+	r.POST("/mangas/:id/library", handler)
+	return r
+}
+
+func TestCORSMiddlewarePreflight(t *testing.T) {
+	var reached bool
+	r := newCORSRouter(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           5 * time.Minute,
+	}, &reached)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/mangas/1/library", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization")
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for preflight, got %d", rec.Code)
+	}
+	if reached {
+		t.Fatal("preflight should not reach the handler")
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+		"Access-Control-Max-Age":           "300",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Fatalf("expected %s %q, got %q", header, value, got)
+		}
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Fatalf("expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORSMiddlewareRejectsUnknownOrigin(t *testing.T) {
+	var reached bool
+	r := newCORSRouter(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, &reached)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/mangas", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for preflight from unknown origin, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no Allow-Origin header, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/mangas", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	r.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no Allow-Origin header for unknown origin, got %q", got)
+	}
+}
+
+func TestCORSMiddlewareEchoesOriginWithCredentials(t *testing.T) {
+	var reached bool
+	r := newCORSRouter(CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		ExposedHeaders:   []string{RequestIDHeader},
+		AllowCredentials: true,
+	}, &reached)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/mangas", nil)
+	req.Header.Set("Origin", "https://reader.example.org")
+	r.ServeHTTP(rec, req)
+
+	if !reached || rec.Code != http.StatusOK {
+		t.Fatalf("expected the request to reach the handler, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://reader.example.org" {
+		t.Fatalf("expected the request origin to be echoed instead of a wildcard, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("expected credentials to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != RequestIDHeader {
+		t.Fatalf("expected exposed headers %q, got %q", RequestIDHeader, got)
+	}
+}
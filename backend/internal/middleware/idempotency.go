@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader lets clients safely retry a POST
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayHeader marks responses served from a stored result
+const IdempotentReplayHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength bounds client supplied keys
+const maxIdempotencyKeyLength = 255
+
+// Idempotency replays the stored response when a client repeats a request
+// with the same Idempotency-Key. Keys are scoped per user and endpoint
+// (method and concrete path) and kept in Idempotency_Keys until they expire.
+type Idempotency struct {
+	db       *sql.DB
+	ttl      time.Duration
+	identify func(c *gin.Context) (int64, bool)
+}
+
+// NewIdempotency stores responses in db for ttl
+func NewIdempotency(db *sql.DB, ttl time.Duration) *Idempotency {
+	return &Idempotency{db: db, ttl: ttl}
+}
+
+// SetIdentityFunc sets how the authenticated user of a request is found.
+// Requests without a user are passed through untouched.
+func (i *Idempotency) SetIdentityFunc(identify func(c *gin.Context) (int64, bool)) {
+	i.identify = identify
+}
+
+// Middleware claims the key before the handler runs. Successful responses are
+// stored for replay; failed ones release the key so the client can retry.
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || i.identify == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+		userID, ok := i.identify(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		endpoint := c.Request.Method + " " + c.Request.URL.Path
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+
+		claimed, stored, err := i.claim(ctx, userID, endpoint, key, requestHash)
+		if err != nil {
+			log.Printf("middleware.Idempotency: claim user_id=%d endpoint=%q err=%v", userID, endpoint, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to process Idempotency-Key"})
+			return
+		}
+		if !claimed {
+			switch {
+			case stored.requestHash != requestHash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+			case !stored.statusCode.Valid:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header(IdempotentReplayHeader, "true")
+				c.Data(int(stored.statusCode.Int64), stored.contentType.String, stored.body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The handler may have been cancelled; finish bookkeeping regardless
+		saveCtx := context.WithoutCancel(ctx)
+		status := recorder.Status()
+		if status >= 200 && status < 300 {
+			err = i.complete(saveCtx, userID, endpoint, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		} else {
+			err = i.release(saveCtx, userID, endpoint, key)
+		}
+		if err != nil {
+			log.Printf("middleware.Idempotency: store response user_id=%d endpoint=%q err=%v", userID, endpoint, err)
+		}
+	}
+}
+
+// storedRequest is a previously claimed key
+type storedRequest struct {
+	requestHash string
+	statusCode  sql.NullInt64
+	contentType sql.NullString
+	body        []byte
+}
+
+// claim reserves the key for this request. When the key is already taken it
+// returns false and the earlier request.
+func (i *Idempotency) claim(ctx context.Context, userID int64, endpoint, key, requestHash string) (bool, *storedRequest, error) {
+	now := time.Now().UTC()
+	if _, err := i.db.ExecContext(ctx, `DELETE FROM Idempotency_Keys WHERE Expires_At <= ?`, now); err != nil {
+		return false, nil, err
+	}
+
+	res, err := i.db.ExecContext(ctx, `
+		INSERT INTO Idempotency_Keys (User_Id, Endpoint, Idempotency_Key, Request_Hash, Created_At, Expires_At)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(User_Id, Endpoint, Idempotency_Key) DO NOTHING
+	`, userID, endpoint, key, requestHash, now, now.Add(i.ttl))
+	if err != nil {
+		return false, nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return false, nil, err
+	} else if n > 0 {
+		return true, nil, nil
+	}
+
+	var stored storedRequest
+	err = i.db.QueryRowContext(ctx, `
+		SELECT Request_Hash, Status_Code, Content_Type, Response_Body
+		FROM Idempotency_Keys
+		WHERE User_Id = ? AND Endpoint = ? AND Idempotency_Key = ?
+	`, userID, endpoint, key).Scan(&stored.requestHash, &stored.statusCode, &stored.contentType, &stored.body)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released between the insert and the lookup; let the client retry
+		stored.requestHash = requestHash
+		return false, &stored, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return false, &stored, nil
+}
+
+func (i *Idempotency) complete(ctx context.Context, userID int64, endpoint, key string, status int, contentType string, body []byte) error {
+	_, err := i.db.ExecContext(ctx, `
+		UPDATE Idempotency_Keys
+		SET Status_Code = ?, Content_Type = ?, Response_Body = ?
+		WHERE User_Id = ? AND Endpoint = ? AND Idempotency_Key = ?
+	`, status, contentType, body, userID, endpoint, key)
+	return err
+}
+
+func (i *Idempotency) release(ctx context.Context, userID int64, endpoint, key string) error {
+	_, err := i.db.ExecContext(ctx, `
+		DELETE FROM Idempotency_Keys WHERE User_Id = ? AND Endpoint = ? AND Idempotency_Key = ?
+	`, userID, endpoint, key)
+	return err
+}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	_ "modernc.org/sqlite"
+)
+
+func setupIdempotencyRouter(t *testing.T) (*gin.Engine, *sql.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../db/migrations/026_idempotency_keys.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration) + `
+		CREATE TABLE reviews (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER NOT NULL, content TEXT NOT NULL);
+	`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	idempotency := NewIdempotency(db, time.Hour)
+	idempotency.SetIdentityFunc(func(c *gin.Context) (int64, bool) { return 7, true })
+
+	r := gin.New()
+	r.POST("/mangas/:id/reviews", idempotency.Middleware(), func(c *gin.Context) {
+		res, err := db.Exec(`INSERT INTO reviews (user_id, content) VALUES (7, 'great')`)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		id, _ := res.LastInsertId()
+		c.JSON(http.StatusCreated, gin.H{"review_id": id, "created_at": time.Now().UnixNano()})
+	})
+	return r, db
+}
+
+func postWithKey(r *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func countReviews(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reviews`).Scan(&n); err != nil {
+		t.Fatalf("failed to count reviews: %v", err)
+	}
+	return n
+}
+
+func TestIdempotencyKeyReplaysStoredResponse(t *testing.T) {
+	r, db := setupIdempotencyRouter(t)
+	body := `{"rating":5,"content":"great"}`
+
+	first := postWithKey(r, "/mangas/1/reviews", "retry-1", body)
+	second := postWithKey(r, "/mangas/1/reviews", "retry-1", body)
+
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("expected both responses to be 201, got %d and %d", first.Code, second.Code)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("expected identical bodies, got %s and %s", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayHeader) != "true" {
+		t.Fatalf("expected the repeat to be marked as replayed")
+	}
+	if n := countReviews(t, db); n != 1 {
+		t.Fatalf("expected one review row, got %d", n)
+	}
+
+	// Keys are scoped to the endpoint, and requests without a key are not deduplicated
+	if rec := postWithKey(r, "/mangas/2/reviews", "retry-1", body); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayHeader) != "" {
+		t.Fatalf("expected the key to be independent on another endpoint, got %d", rec.Code)
+	}
+	postWithKey(r, "/mangas/1/reviews", "", body)
+	if n := countReviews(t, db); n != 3 {
+		t.Fatalf("expected three review rows, got %d", n)
+	}
+}
+
+func TestIdempotencyKeyRejectsDifferentPayload(t *testing.T) {
+	r, _ := setupIdempotencyRouter(t)
+
+	postWithKey(r, "/mangas/1/reviews", "retry-2", `{"rating":5}`)
+	rec := postWithKey(r, "/mangas/1/reviews", "retry-2", `{"rating":3}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for a reused key, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	r, db := setupIdempotencyRouter(t)
+	body := `{"rating":5}`
+
+	postWithKey(r, "/mangas/1/reviews", "retry-3", body)
+	if _, err := db.Exec(`UPDATE Idempotency_Keys SET Expires_At = ?`, time.Now().Add(-time.Minute).UTC()); err != nil {
+		t.Fatalf("failed to expire key: %v", err)
+	}
+	if rec := postWithKey(r, "/mangas/1/reviews", "retry-3", body); rec.Header().Get(IdempotentReplayHeader) != "" {
+		t.Fatalf("expected an expired key to run the request again")
+	}
+	if n := countReviews(t, db); n != 2 {
+		t.Fatalf("expected two review rows, got %d", n)
+	}
+}
@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,15 +18,46 @@ type RateLimiter struct {
 	clients     map[string]*clientLimiter
 	rate        int           // requests per window
 	window      time.Duration // time window
+	routes      map[string]routeLimit
+	tiers       map[string]routeLimit
+	identify    func(c *gin.Context) (int64, bool)
+	tierOf      func(c *gin.Context) (string, bool)
+	store       RateLimitStore
 	cleanupTick *time.Ticker
 }
 
+// RateLimitStore keeps buckets outside the process so that several API
+// instances share limits. The in-memory buckets are used when no store is
+// set or the store returns an error.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rate int, window time.Duration) (Quota, error)
+}
+
+// TierAnonymous is the tier of requests without a valid token
+const TierAnonymous = "anonymous"
+
+// routeLimit is the bucket size and refill window applied to a request
+type routeLimit struct {
+	rate   int
+	window time.Duration
+}
+
 type clientLimiter struct {
 	tokens     int
+	limit      routeLimit
 	lastUpdate time.Time
 	mu         sync.Mutex
 }
 
+// Quota describes a client's bucket after a request was counted
+type Quota struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token when not allowed
+}
+
 // NewRateLimiter creates a new rate limiter
 // rate: number of requests allowed
 // window: time window for the rate limit
@@ -31,6 +66,8 @@ func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
 		clients: make(map[string]*clientLimiter),
 		rate:    rate,
 		window:  window,
+		routes:  make(map[string]routeLimit),
+		tiers:   make(map[string]routeLimit),
 	}
 
 	// Cleanup old entries every minute
@@ -40,6 +77,43 @@ func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
 	return rl
 }
 
+// WithRoute overrides the default limit for one route. path is the route
+// template as registered with gin (e.g. "/mangas/:id"). Each route keeps its
+// own bucket per client.
+func (rl *RateLimiter) WithRoute(method, path string, limit int, window time.Duration) *RateLimiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.routes[routeKey(method, path)] = routeLimit{rate: limit, window: window}
+	return rl
+}
+
+// WithTier overrides the default limit for callers in a tier, such as a role
+// from their token or TierAnonymous. Route overrides still apply to every
+// tier, so credential endpoints stay tight for trusted clients too.
+func (rl *RateLimiter) WithTier(tier string, limit int, window time.Duration) *RateLimiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.tiers[tier] = routeLimit{rate: limit, window: window}
+	return rl
+}
+
+// SetTierFunc sets how the caller's tier is read from a request. Requests it
+// reports no tier for, and all requests when it is unset, are TierAnonymous.
+func (rl *RateLimiter) SetTierFunc(tierOf func(c *gin.Context) (string, bool)) {
+	rl.tierOf = tierOf
+}
+
+// SetIdentityFunc sets how authenticated users are recognised before the
+// auth middleware has run. Requests without a user fall back to the client IP.
+func (rl *RateLimiter) SetIdentityFunc(identify func(c *gin.Context) (int64, bool)) {
+	rl.identify = identify
+}
+
+// SetStore moves bucket state to a shared store
+func (rl *RateLimiter) SetStore(store RateLimitStore) {
+	rl.store = store
+}
+
 // cleanup removes old client limiters
 func (rl *RateLimiter) cleanup() {
 	for range rl.cleanupTick.C {
@@ -47,7 +121,7 @@ func (rl *RateLimiter) cleanup() {
 		now := time.Now()
 		for key, limiter := range rl.clients {
 			limiter.mu.Lock()
-			if now.Sub(limiter.lastUpdate) > rl.window*2 {
+			if now.Sub(limiter.lastUpdate) > limiter.limit.window*2 {
 				delete(rl.clients, key)
 			}
 			limiter.mu.Unlock()
@@ -57,7 +131,7 @@ func (rl *RateLimiter) cleanup() {
 }
 
 // getClientLimiter gets or creates a limiter for a client
-func (rl *RateLimiter) getClientLimiter(key string) *clientLimiter {
+func (rl *RateLimiter) getClientLimiter(key string, limit routeLimit) *clientLimiter {
 	rl.mu.RLock()
 	limiter, exists := rl.clients[key]
 	rl.mu.RUnlock()
@@ -68,7 +142,8 @@ func (rl *RateLimiter) getClientLimiter(key string) *clientLimiter {
 		limiter, exists = rl.clients[key]
 		if !exists {
 			limiter = &clientLimiter{
-				tokens:     rl.rate,
+				tokens:     limit.rate,
+				limit:      limit,
 				lastUpdate: time.Now(),
 			}
 			rl.clients[key] = limiter
@@ -79,9 +154,27 @@ func (rl *RateLimiter) getClientLimiter(key string) *clientLimiter {
 	return limiter
 }
 
-// Allow checks if a request is allowed
+// Allow checks if a request is allowed under the default limit
 func (rl *RateLimiter) Allow(key string) bool {
-	limiter := rl.getClientLimiter(key)
+	return rl.take(key, routeLimit{rate: rl.rate, window: rl.window}).Allowed
+}
+
+// takeShared counts a request in the configured store, falling back to the
+// local buckets so an unreachable store does not block traffic
+func (rl *RateLimiter) takeShared(ctx context.Context, key string, limit routeLimit) Quota {
+	if rl.store != nil {
+		quota, err := rl.store.Take(ctx, key, limit.rate, limit.window)
+		if err == nil {
+			return quota
+		}
+		log.Printf("middleware.RateLimiter: store unavailable, using local limits: %v", err)
+	}
+	return rl.take(key, limit)
+}
+
+// take counts one request against a client's bucket
+func (rl *RateLimiter) take(key string, limit routeLimit) Quota {
+	limiter := rl.getClientLimiter(key, limit)
 
 	limiter.mu.Lock()
 	defer limiter.mu.Unlock()
@@ -90,38 +183,60 @@ func (rl *RateLimiter) Allow(key string) bool {
 	elapsed := now.Sub(limiter.lastUpdate)
 
 	// Refill tokens based on elapsed time
-	if elapsed >= rl.window {
-		limiter.tokens = rl.rate
+	if elapsed >= limit.window {
+		limiter.tokens = limit.rate
 		limiter.lastUpdate = now
+		elapsed = 0
 	} else {
 		// Add tokens proportionally
-		tokensToAdd := int(float64(rl.rate) * elapsed.Seconds() / rl.window.Seconds())
+		tokensToAdd := int(float64(limit.rate) * elapsed.Seconds() / limit.window.Seconds())
 		if tokensToAdd > 0 {
-			limiter.tokens = min(limiter.tokens+tokensToAdd, rl.rate)
+			limiter.tokens = min(limiter.tokens+tokensToAdd, limit.rate)
 			limiter.lastUpdate = now
+			elapsed = 0
 		}
 	}
 
+	quota := Quota{Limit: limit.rate}
 	if limiter.tokens > 0 {
 		limiter.tokens--
-		return true
+		quota.Allowed = true
 	}
+	quota.Remaining = limiter.tokens
 
-	return false
+	perToken := limit.window / time.Duration(max(limit.rate, 1))
+	quota.Reset = max(time.Duration(limit.rate-limiter.tokens)*perToken-elapsed, 0)
+	if !quota.Allowed {
+		quota.RetryAfter = max(perToken-elapsed, 0)
+	}
+	return quota
 }
 
 // RateLimitMiddleware creates a Gin middleware for rate limiting
 func (rl *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get client identifier (IP address or user ID)
-		clientKey := c.ClientIP()
+		limit := rl.tierLimit(c)
+		bucket := rl.clientKey(c)
 
-		// If user is authenticated, use user ID instead
-		if userID, exists := c.Get("user_id"); exists {
-			clientKey = "user:" + string(rune(userID.(int64)))
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		rl.mu.RLock()
+		override, ok := rl.routes[routeKey(c.Request.Method, route)]
+		rl.mu.RUnlock()
+		if ok {
+			limit = override
+			bucket = routeKey(c.Request.Method, route) + "|" + bucket
 		}
 
-		if !rl.Allow(clientKey) {
+		quota := rl.takeShared(c.Request.Context(), bucket, limit)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(quota.Reset)))
+
+		if !quota.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(quota.RetryAfter), 1)))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "rate limit exceeded. please try again later",
 			})
@@ -133,9 +248,42 @@ func (rl *RateLimiter) RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// tierLimit is the default limit for the caller's tier
+func (rl *RateLimiter) tierLimit(c *gin.Context) routeLimit {
+	tier := TierAnonymous
+	if rl.tierOf != nil {
+		if name, ok := rl.tierOf(c); ok && name != "" {
+			tier = name
+		}
+	}
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	if limit, ok := rl.tiers[tier]; ok {
+		return limit
 	}
-	return b
+	return routeLimit{rate: rl.rate, window: rl.window}
+}
+
+// clientKey identifies the caller by user ID when known, otherwise by IP
+func (rl *RateLimiter) clientKey(c *gin.Context) string {
+	if rl.identify != nil {
+		if userID, ok := rl.identify(c); ok {
+			return "user:" + strconv.FormatInt(userID, 10)
+		}
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int64); ok {
+			return "user:" + strconv.FormatInt(id, 10)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
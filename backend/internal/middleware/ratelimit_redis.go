@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// tokenBucketScript refills a bucket continuously from the Redis clock so
+// that every API instance sees the same state.
+// KEYS[1] bucket key; ARGV[1] rate; ARGV[2] window in milliseconds.
+// Returns {allowed, remaining, reset_ms, retry_after_ms}.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = rate
+	ts = now
+end
+
+tokens = math.min(rate, tokens + math.max(0, now - ts) * rate / window)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], window * 2)
+
+local per_token = window / rate
+local retry = 0
+if allowed == 0 then
+	retry = math.ceil((1 - tokens) * per_token)
+end
+return {allowed, math.floor(tokens), math.ceil((rate - tokens) * per_token), retry}
+`
+
+// ScriptRunner is the subset of cache.Client used by RedisStore
+type ScriptRunner interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// RedisStore keeps token buckets in Redis so limits are shared across instances
+type RedisStore struct {
+	client ScriptRunner
+}
+
+// NewRedisStore creates a Redis-backed rate limit store
+func NewRedisStore(client ScriptRunner) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take counts one request against a bucket
+func (s *RedisStore) Take(ctx context.Context, key string, rate int, window time.Duration) (Quota, error) {
+	res, err := s.client.Eval(ctx, tokenBucketScript, []string{rateLimitKeyPrefix + key}, rate, window.Milliseconds())
+	if err != nil {
+		return Quota{}, err
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 4 {
+		return Quota{}, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+	nums := make([]int64, len(values))
+	for i, v := range values {
+		n, ok := v.(int64)
+		if !ok {
+			return Quota{}, fmt.Errorf("unexpected rate limit script result: %v", res)
+		}
+		nums[i] = n
+	}
+
+	return Quota{
+		Allowed:    nums[0] == 1,
+		Limit:      rate,
+		Remaining:  int(nums[1]),
+		Reset:      time.Duration(nums[2]) * time.Millisecond,
+		RetryAfter: time.Duration(nums[3]) * time.Millisecond,
+	}, nil
+}
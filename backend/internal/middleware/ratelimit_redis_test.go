@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/cache"
+)
+
+func newRedisBackedLimiter(t *testing.T, addr string) *RateLimiter {
+	t.Helper()
+
+	client, err := cache.NewClient(addr, "", 0)
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	rl := NewRateLimiter(3, time.Minute)
+	rl.SetStore(NewRedisStore(client))
+	return rl
+}
+
+func TestRedisStoreSharesLimitsAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	first := newRateLimitedRouter(newRedisBackedLimiter(t, mr.Addr()))
+	second := newRateLimitedRouter(newRedisBackedLimiter(t, mr.Addr()))
+
+	for i, r := range []*gin.Engine{first, second, first} {
+		if rec := doRequest(r, http.MethodGet, "/mangas/search"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec := doRequest(second, http.MethodGet, "/mangas/search")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the shared bucket to be exhausted, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header on 429")
+	}
+}
+
+func TestRedisStoreFallsBackToMemoryWhenUnavailable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rl := newRedisBackedLimiter(t, mr.Addr())
+	r := newRateLimitedRouter(rl)
+	mr.Close()
+
+	for i := 0; i < 3; i++ {
+		if rec := doRequest(r, http.MethodGet, "/mangas/search"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 from local fallback, got %d", i+1, rec.Code)
+		}
+	}
+	if rec := doRequest(r, http.MethodGet, "/mangas/search"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected local fallback to enforce the limit, got %d", rec.Code)
+	}
+}
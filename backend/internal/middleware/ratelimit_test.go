@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitedRouter(rl *RateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(rl.RateLimitMiddleware())
+	r.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/mangas/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func doRequest(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLoginHasTighterLimitThanSearch(t *testing.T) {
+	rl := NewRateLimiter(5, time.Minute).WithRoute(http.MethodPost, "/login", 2, time.Minute)
+	r := newRateLimitedRouter(rl)
+
+	for i := 0; i < 2; i++ {
+		if rec := doRequest(r, http.MethodPost, "/login"); rec.Code != http.StatusOK {
+			t.Fatalf("login attempt %d: expected 200, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec := doRequest(r, http.MethodPost, "/login")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected third login to be limited, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header on 429")
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Fatalf("expected X-RateLimit-Remaining 0, got %q", got)
+	}
+
+	// The login bucket is separate from the default one
+	for i := 0; i < 5; i++ {
+		rec := doRequest(r, http.MethodGet, "/mangas/search")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("search request %d: expected 200, got %d", i+1, rec.Code)
+		}
+		if got, want := rec.Header().Get("X-RateLimit-Remaining"), 4-i; got != strconv.Itoa(want) {
+			t.Fatalf("search request %d: expected remaining %d, got %s", i+1, want, got)
+		}
+	}
+	if rec := doRequest(r, http.MethodGet, "/mangas/search"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected sixth search to be limited, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitKeysByUser(t *testing.T) {
+	rl := NewRateLimiter(1, time.Minute)
+	rl.SetIdentityFunc(func(c *gin.Context) (int64, bool) {
+		if c.GetHeader("X-User") == "" {
+			return 0, false
+		}
+		return 42, true
+	})
+	r := newRateLimitedRouter(rl)
+
+	req := func(user string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/mangas/search", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := req("42"); code != http.StatusOK {
+		t.Fatalf("expected user request to pass, got %d", code)
+	}
+	// Same IP, but anonymous traffic has its own bucket
+	if code := req(""); code != http.StatusOK {
+		t.Fatalf("expected anonymous request to pass, got %d", code)
+	}
+	if code := req("42"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected second user request to be limited, got %d", code)
+	}
+}
+
+func TestRateLimitTiers(t *testing.T) {
+	rl := NewRateLimiter(100, time.Minute).
+		WithTier(TierAnonymous, 2, time.Minute).
+		WithTier("premium", 5, time.Minute).
+		WithRoute(http.MethodPost, "/login", 1, time.Minute)
+	users := map[string]int64{"premium": 7, "user": 8}
+	rl.SetIdentityFunc(func(c *gin.Context) (int64, bool) {
+		id, ok := users[c.GetHeader("X-Role")]
+		return id, ok
+	})
+	rl.SetTierFunc(func(c *gin.Context) (string, bool) {
+		role := c.GetHeader("X-Role")
+		return role, role != ""
+	})
+	r := newRateLimitedRouter(rl)
+
+	send := func(method, path, role string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+	allowed := func(role string, n int, limit string) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			rec := send(http.MethodGet, "/mangas/search", role)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%q request %d: expected 200, got %d", role, i+1, rec.Code)
+			}
+			if got := rec.Header().Get("X-RateLimit-Limit"); got != limit {
+				t.Fatalf("%q request %d: expected limit %s, got %s", role, i+1, limit, got)
+			}
+		}
+		if rec := send(http.MethodGet, "/mangas/search", role); rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("%q: expected request %d to be limited, got %d", role, n+1, rec.Code)
+		}
+	}
+
+	allowed("", 2, "2")
+	allowed("premium", 5, "5")
+	// Tiers without their own budget get the default limit
+	allowed("user", 100, "100")
+
+	// Route overrides apply to premium callers too
+	if rec := send(http.MethodPost, "/login", "premium"); rec.Code != http.StatusOK {
+		t.Fatalf("expected first premium login to pass, got %d", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/login", "premium"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected second premium login to be limited, got %d", rec.Code)
+	}
+}
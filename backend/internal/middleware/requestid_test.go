@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/logging"
+)
+
+func TestRequestIDMiddlewarePropagatesIncomingID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo))
+	defer slog.SetDefault(previous)
+
+	var seen string
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/mangas/:id", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/mangas/7", nil)
+	req.Header.Set(RequestIDHeader, "trace-abc-123")
+	r.ServeHTTP(rec, req)
+
+	if seen != "trace-abc-123" {
+		t.Fatalf("expected the handler context to carry the incoming ID, got %q", seen)
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "trace-abc-123" {
+		t.Fatalf("expected the response to echo the incoming ID, got %q", got)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["request_id"] != "trace-abc-123" || entry["path"] != "/mangas/:id" || entry["status"] != float64(http.StatusNoContent) {
+		t.Fatalf("unexpected access log entry: %v", entry)
+	}
+}
+
+func TestRequestIDMiddlewareReplacesInvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	r.ServeHTTP(rec, req)
+
+	got := rec.Header().Get(RequestIDHeader)
+	if got == "" || got == "bad id\nwith newline" {
+		t.Fatalf("expected a generated request ID, got %q", got)
+	}
+}
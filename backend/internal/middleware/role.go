@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request through only when the authenticated user's
+// role claim matches one of roles. It must run after the auth middleware,
+// which stores the claim under "role".
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, r := range roles {
+		allowed[r] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := c.Get("user_id"); !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+
+		role, _ := c.Get("role")
+		name, _ := role.(string)
+		if _, ok := allowed[name]; !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "insufficient role",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRoleRouter(role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if role != "" {
+			c.Set("user_id", int64(1))
+			c.Set("role", role)
+		}
+		c.Next()
+	})
+	r.POST("/admin/notify", RequireRole("admin"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestRequireRole(t *testing.T) {
+	cases := []struct {
+		name string
+		role string
+		want int
+	}{
+		{"admin allowed", "admin", http.StatusOK},
+		{"user denied", "user", http.StatusForbidden},
+		{"unauthenticated", "", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := doRequest(newRoleRouter(tc.role), http.MethodPost, "/admin/notify")
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rec.Code)
+			}
+		})
+	}
+}
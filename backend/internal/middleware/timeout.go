@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutMiddleware bounds every request with a context deadline.
+// Routes listed in overrides (keyed by the registered path, e.g.
+// "/analytics/reading") get their own budget instead of the default.
+//
+// Handlers are expected to stop once the context is done. Whatever they write
+// after the deadline is replaced by a 504 so the client never receives a
+// partial or misleading error.
+func RequestTimeoutMiddleware(defaultTimeout time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := defaultTimeout
+		if d, ok := overrides[c.FullPath()]; ok {
+			timeout = d
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		tw := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx, request: c.Request, timeout: timeout}
+		c.Writer = tw
+		c.Next()
+
+		// The handler gave up without writing anything
+		tw.expired()
+	}
+}
+
+// timeoutWriter swaps the handler's response for a 504 once the deadline
+// has passed, unless the response was already started in time
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	request  *http.Request
+	timeout  time.Duration
+	timedOut bool
+}
+
+func (w *timeoutWriter) expired() bool {
+	if w.timedOut {
+		return true
+	}
+	if w.ResponseWriter.Written() || !errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	w.timedOut = true
+	log.Printf("middleware.RequestTimeout: %s %s cancelled after %s", w.request.Method, w.request.URL.Path, w.timeout)
+
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = w.ResponseWriter.WriteString(`{"error":"request timed out"}`)
+	return true
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
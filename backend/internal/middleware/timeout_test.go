@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func slowHandler(c *gin.Context) {
+	select {
+	case <-c.Request.Context().Done():
+		c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+	case <-time.After(200 * time.Millisecond):
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+}
+
+func TestRequestTimeoutReturnsGatewayTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RequestTimeoutMiddleware(20*time.Millisecond, map[string]time.Duration{
+		"/analytics/reading": time.Second,
+	}))
+	r.GET("/slow", slowHandler)
+	r.GET("/analytics/reading", slowHandler)
+
+	start := time.Now()
+	rec := doRequest(r, http.MethodGet, "/slow")
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("expected the request to stop at the deadline, took %s", elapsed)
+	}
+
+	// The override gives the heavy route enough time to finish
+	if rec := doRequest(r, http.MethodGet, "/analytics/reading"); rec.Code != http.StatusOK {
+		t.Fatalf("expected overridden route to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
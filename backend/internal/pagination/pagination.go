@@ -0,0 +1,59 @@
+// Package pagination normalizes the page and limit values of paginated
+// queries so every listing clamps them the same way.
+package pagination
+
+// Defaults bounds the page size of a listing
+type Defaults struct {
+	// Limit is used when no positive limit is given
+	Limit int
+	// MaxLimit caps larger limits
+	MaxLimit int
+}
+
+var defaults = Defaults{Limit: 20, MaxLimit: 100}
+
+// SetDefaults overrides the page sizes returned by Default. Non-positive
+// values keep the current setting and Limit never exceeds MaxLimit.
+func SetDefaults(d Defaults) {
+	if d.MaxLimit > 0 {
+		defaults.MaxLimit = d.MaxLimit
+	}
+	if d.Limit > 0 {
+		defaults.Limit = d.Limit
+	}
+	defaults.Limit = min(defaults.Limit, defaults.MaxLimit)
+}
+
+// Default returns the configured page sizes
+func Default() Defaults {
+	return defaults
+}
+
+// Normalize returns page and limit clamped to valid values: pages start at
+// 1, a non-positive limit becomes d.Limit and limits past d.MaxLimit are cut
+// to it.
+func Normalize(page, limit int, d Defaults) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	return page, Limit(limit, d)
+}
+
+// Limit clamps limit the way Normalize does, for listings without pages
+func Limit(limit int, d Defaults) int {
+	if limit < 1 {
+		limit = d.Limit
+	}
+	if limit > d.MaxLimit {
+		limit = d.MaxLimit
+	}
+	return limit
+}
+
+// Offset returns the number of rows before page
+func Offset(page, limit int) int {
+	if page < 1 {
+		return 0
+	}
+	return (page - 1) * limit
+}
@@ -0,0 +1,67 @@
+package pagination
+
+import "testing"
+
+func TestNormalizeClamps(t *testing.T) {
+	d := Defaults{Limit: 20, MaxLimit: 100}
+	cases := []struct {
+		name                string
+		page, limit         int
+		wantPage, wantLimit int
+	}{
+		{"negative page", -3, 10, 1, 10},
+		{"zero page", 0, 10, 1, 10},
+		{"over max limit", 2, 500, 2, 100},
+		{"zero limit", 4, 0, 4, 20},
+		{"negative limit", 1, -5, 1, 20},
+		{"in range", 3, 50, 3, 50},
+	}
+	for _, tc := range cases {
+		page, limit := Normalize(tc.page, tc.limit, d)
+		if page != tc.wantPage || limit != tc.wantLimit {
+			t.Errorf("%s: Normalize(%d, %d) = (%d, %d), want (%d, %d)", tc.name, tc.page, tc.limit, page, limit, tc.wantPage, tc.wantLimit)
+		}
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	saved := Default()
+	t.Cleanup(func() { defaults = saved })
+
+	SetDefaults(Defaults{Limit: 30, MaxLimit: 50})
+	if got := Default(); got != (Defaults{Limit: 30, MaxLimit: 50}) {
+		t.Fatalf("unexpected defaults %+v", got)
+	}
+
+	// A default past the new maximum is pulled down to it
+	SetDefaults(Defaults{MaxLimit: 25})
+	if got := Default(); got != (Defaults{Limit: 25, MaxLimit: 25}) {
+		t.Fatalf("unexpected defaults %+v", got)
+	}
+
+	SetDefaults(Defaults{Limit: -1, MaxLimit: 0})
+	if got := Default(); got != (Defaults{Limit: 25, MaxLimit: 25}) {
+		t.Fatalf("expected invalid values to be ignored, got %+v", got)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	if got := Offset(3, 20); got != 40 {
+		t.Fatalf("expected offset 40, got %d", got)
+	}
+	if got := Offset(0, 20); got != 0 {
+		t.Fatalf("expected offset 0 for page 0, got %d", got)
+	}
+}
+
+func TestLimitClamps(t *testing.T) {
+	d := Defaults{Limit: 10, MaxLimit: 50}
+	cases := []struct{ limit, want int }{
+		{-1, 10}, {0, 10}, {1, 1}, {50, 50}, {51, 50}, {500, 50},
+	}
+	for _, tc := range cases {
+		if got := Limit(tc.limit, d); got != tc.want {
+			t.Errorf("Limit(%d) = %d, want %d", tc.limit, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package queue
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDeadLetterNotFound is returned when retrying an unknown dead letter
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is an operation that exhausted its retries
+type DeadLetter struct {
+	Operation WriteOperation `json:"operation"`
+	LastError string         `json:"last_error"`
+	Attempts  int            `json:"attempts"`
+	FailedAt  time.Time      `json:"failed_at"`
+}
+
+// DeadLetterQueue holds operations that could not be applied so an operator
+// can inspect and requeue them
+type DeadLetterQueue struct {
+	mu    sync.RWMutex
+	items []DeadLetter
+}
+
+// NewDeadLetterQueue creates an empty dead-letter queue
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{items: make([]DeadLetter, 0)}
+}
+
+// Add records a failed operation
+func (d *DeadLetterQueue) Add(op WriteOperation, err error, attempts int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	letter := DeadLetter{
+		Operation: op,
+		Attempts:  attempts,
+		FailedAt:  time.Now(),
+	}
+	if err != nil {
+		letter.LastError = err.Error()
+	}
+	d.items = append(d.items, letter)
+}
+
+// List returns a copy of all dead letters, oldest first
+func (d *DeadLetterQueue) List() []DeadLetter {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	result := make([]DeadLetter, len(d.items))
+	copy(result, d.items)
+	return result
+}
+
+// Remove deletes and returns the dead letter for an operation ID
+func (d *DeadLetterQueue) Remove(id string) (*DeadLetter, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, letter := range d.items {
+		if letter.Operation.ID == id {
+			d.items = append(d.items[:i], d.items[i+1:]...)
+			return &letter, true
+		}
+	}
+	return nil, false
+}
+
+// Size returns the number of dead letters
+func (d *DeadLetterQueue) Size() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.items)
+}
+
+func (d *DeadLetterQueue) replace(items []DeadLetter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.items = items
+}
+
+// DeadLetters returns operations that exhausted their retries
+func (q *WriteQueue) DeadLetters() []DeadLetter {
+	return q.deadLetters.List()
+}
+
+// DeadLetterCount returns the number of operations that exhausted their retries
+func (q *WriteQueue) DeadLetterCount() int {
+	return q.deadLetters.Size()
+}
+
+// RetryDeadLetter moves a dead letter back onto the queue with its retry
+// count reset
+func (q *WriteQueue) RetryDeadLetter(id string) (*WriteOperation, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letter, ok := q.deadLetters.Remove(id)
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	op := letter.Operation
+	op.Retries = 0
+	q.operations = append(q.operations, op)
+	q.persistLocked()
+	return &op, nil
+}
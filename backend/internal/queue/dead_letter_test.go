@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExhaustedOperationMovesToDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	failing := errors.New("database unavailable")
+
+	q := NewWriteQueue(10, 2, func(ctx context.Context, op WriteOperation) error {
+		return failing
+	})
+	if err := q.Enqueue("update_progress", 1, 2, map[string]interface{}{"current_chapter": 3}); err != nil {
+		t.Fatalf("Enqueue returned error: %v", err)
+	}
+
+	if processed, failed := q.ProcessAll(ctx); processed != 0 || failed != 3 {
+		t.Fatalf("expected 3 failed attempts, got processed=%d failed=%d", processed, failed)
+	}
+
+	letters := q.DeadLetters()
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Attempts != 3 || letters[0].LastError != failing.Error() {
+		t.Fatalf("unexpected dead letter: %+v", letters[0])
+	}
+
+	op, err := q.RetryDeadLetter(letters[0].Operation.ID)
+	if err != nil {
+		t.Fatalf("RetryDeadLetter returned error: %v", err)
+	}
+	if op.Retries != 0 || q.Size() != 1 || len(q.DeadLetters()) != 0 {
+		t.Fatalf("expected operation back on the queue with retries reset")
+	}
+
+	if _, err := q.RetryDeadLetter("missing"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Fatalf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
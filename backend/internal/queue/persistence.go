@@ -0,0 +1,156 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// maxAppliedIDs bounds how many applied operation IDs are remembered for
+// skipping operations that are restored after a crash without a database
+// round trip; Applied_Operations is the authoritative record
+const maxAppliedIDs = 1000
+
+// queueSnapshot is the on-disk representation of a write queue
+type queueSnapshot struct {
+	Operations  []WriteOperation `json:"operations"`
+	Applied     []string         `json:"applied"`
+	DeadLetters []DeadLetter     `json:"dead_letters,omitempty"`
+}
+
+// SetPersistence enables writing the queue to path whenever it changes.
+// An empty path disables persistence.
+func (q *WriteQueue) SetPersistence(path string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.persistPath = path
+}
+
+// Persist writes pending operations, dead letters and recently applied
+// operation IDs to disk
+func (q *WriteQueue) Persist() error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.writeSnapshotLocked()
+}
+
+// Restore loads operations persisted by a previous run and queues them ahead
+// of anything enqueued since startup. Operations whose IDs were already
+// applied, or are already queued, are skipped. A missing file is not an error.
+func (q *WriteQueue) Restore() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.persistPath == "" {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(q.persistPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read write queue: %w", err)
+	}
+
+	var snapshot queueSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("decode write queue: %w", err)
+	}
+
+	for _, id := range snapshot.Applied {
+		q.markAppliedLocked(id)
+	}
+
+	queued := make(map[string]struct{}, len(q.operations))
+	for _, op := range q.operations {
+		queued[op.ID] = struct{}{}
+	}
+
+	restored := make([]WriteOperation, 0, len(snapshot.Operations))
+	for _, op := range snapshot.Operations {
+		if _, ok := q.applied[op.ID]; ok {
+			continue
+		}
+		if _, ok := queued[op.ID]; ok {
+			continue
+		}
+		queued[op.ID] = struct{}{}
+		restored = append(restored, op)
+	}
+
+	q.operations = append(restored, q.operations...)
+	if len(snapshot.DeadLetters) > 0 {
+		q.deadLetters.replace(append(snapshot.DeadLetters, q.deadLetters.List()...))
+	}
+	return len(restored), q.writeSnapshotLocked()
+}
+
+func (q *WriteQueue) wasApplied(id string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	_, ok := q.applied[id]
+	return ok
+}
+
+// markAppliedLocked records a processed operation ID. Caller must hold q.mu.
+func (q *WriteQueue) markAppliedLocked(id string) {
+	if _, ok := q.applied[id]; ok {
+		return
+	}
+	q.applied[id] = struct{}{}
+	q.appliedOrder = append(q.appliedOrder, id)
+	if len(q.appliedOrder) > maxAppliedIDs {
+		oldest := q.appliedOrder[0]
+		q.appliedOrder = q.appliedOrder[1:]
+		delete(q.applied, oldest)
+	}
+}
+
+func (q *WriteQueue) persist() {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	q.persistLocked()
+}
+
+// persistLocked writes the snapshot and logs failures; the in-memory queue
+// keeps working when the disk is unavailable. Caller must hold q.mu.
+func (q *WriteQueue) persistLocked() {
+	if err := q.writeSnapshotLocked(); err != nil {
+		log.Printf("queue: failed to persist write queue: %v", err)
+	}
+}
+
+// writeSnapshotLocked atomically replaces the persistence file. Caller must hold q.mu.
+func (q *WriteQueue) writeSnapshotLocked() error {
+	if q.persistPath == "" {
+		return nil
+	}
+
+	q.persistMu.Lock()
+	defer q.persistMu.Unlock()
+
+	data, err := json.Marshal(queueSnapshot{
+		Operations:  q.operations,
+		Applied:     q.appliedOrder,
+		DeadLetters: q.deadLetters.List(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(q.persistPath); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	tmp := q.persistPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.persistPath)
+}
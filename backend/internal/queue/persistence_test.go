@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestoreRecoversPendingWritesAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "write_queue.json")
+	ctx := context.Background()
+
+	var applied []string
+	process := func(ctx context.Context, op WriteOperation) error {
+		applied = append(applied, op.ID)
+		return nil
+	}
+
+	before := NewWriteQueue(10, 3, process)
+	before.SetPersistence(path)
+	if err := before.Enqueue("update_progress", 1, 2, map[string]interface{}{"current_chapter": 5}); err != nil {
+		t.Fatalf("Enqueue returned error: %v", err)
+	}
+	if err := before.Enqueue("update_progress", 1, 3, map[string]interface{}{"current_chapter": 7}); err != nil {
+		t.Fatalf("Enqueue returned error: %v", err)
+	}
+	if err := before.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+	// The process dies here with one write still pending
+
+	after := NewWriteQueue(10, 3, process)
+	after.SetPersistence(path)
+	restored, err := after.Restore()
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("expected 1 restored operation, got %d", restored)
+	}
+
+	op := after.Peek()
+	if op.MangaID != 3 {
+		t.Fatalf("expected pending write for manga 3, got %d", op.MangaID)
+	}
+	if chapter, _ := op.Data["current_chapter"].(float64); chapter != 7 {
+		t.Fatalf("expected current_chapter 7 after restore, got %v", op.Data["current_chapter"])
+	}
+
+	if processed, failed := after.ProcessAll(ctx); processed != 1 || failed != 0 {
+		t.Fatalf("expected 1 processed and 0 failed, got %d and %d", processed, failed)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("expected each write to be applied once, got %v", applied)
+	}
+}
+
+func TestRestoreSkipsAlreadyAppliedOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "write_queue.json")
+
+	// Crash after the write was applied but before it left the pending list
+	op := WriteOperation{ID: "1-2-100-1", Type: "add_to_library", UserID: 1, MangaID: 2, CreatedAt: time.Now()}
+	data, err := json.Marshal(queueSnapshot{
+		Operations: []WriteOperation{op},
+		Applied:    []string{op.ID},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+
+	calls := 0
+	q := NewWriteQueue(10, 3, func(ctx context.Context, op WriteOperation) error {
+		calls++
+		return nil
+	})
+	q.SetPersistence(path)
+
+	restored, err := q.Restore()
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	if restored != 0 || !q.IsEmpty() {
+		t.Fatalf("expected applied operation to be skipped, restored %d", restored)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no reprocessing, got %d calls", calls)
+	}
+}
@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -13,6 +14,7 @@ import (
 	"github.com/ngocan-dev/mangahub/backend/domain/manga"
 	chapterrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
 	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	"github.com/ngocan-dev/mangahub/backend/internal/security"
 	chapterservice "github.com/ngocan-dev/mangahub/backend/internal/service/chapter"
 )
 
@@ -54,6 +56,10 @@ func (p *WriteProcessor) ProcessOperation(ctx context.Context, op WriteOperation
 func (p *WriteProcessor) processAddToLibrary(ctx context.Context, op WriteOperation) error {
 	status, _ := op.Data["status"].(string)
 	currentChapter, _ := op.Data["current_chapter"].(int)
+	if f, ok := op.Data["current_chapter"].(float64); ok {
+		// Operations restored from disk carry JSON numbers
+		currentChapter = int(f)
+	}
 
 	// Create repository and add to library
 	libraryRepo := libraryrepository.NewRepository(p.db)
@@ -62,14 +68,11 @@ func (p *WriteProcessor) processAddToLibrary(ctx context.Context, op WriteOperat
 		currentChapter = 1
 	}
 
-	duplicate, err := libraryRepo.AddToLibrary(ctx, op.UserID, op.MangaID, status, currentChapter)
-	if err != nil {
+	return p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+		// An entry that already exists is not an error
+		_, err := libraryRepo.AddToLibraryTx(ctx, tx, op.UserID, op.MangaID, status, currentChapter)
 		return err
-	}
-	if duplicate {
-		return nil
-	}
-	return nil
+	})
 }
 
 // processUpdateProgress processes an update progress operation
@@ -125,8 +128,12 @@ func (p *WriteProcessor) processUpdateProgress(ctx context.Context, op WriteOper
 		chapterID = &id
 	}
 
+	// Queued writes replay late, so they must not overtake newer progress
 	historyRepo := history.NewRepository(p.db)
-	return historyRepo.UpdateProgress(ctx, op.UserID, op.MangaID, currentChapter, chapterID, progressPercent)
+	return p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+		_, err := historyRepo.ReconcileProgressTx(ctx, tx, op.UserID, op.MangaID, currentChapter, chapterID, progressPercent, false)
+		return err
+	})
 }
 
 // processBroadcastProgress attempts to broadcast a queued progress update
@@ -175,11 +182,11 @@ func (p *WriteProcessor) processCreateReview(ctx context.Context, op WriteOperat
 
 	content, _ := op.Data["content"].(string)
 
-	if rating < 1 || rating > 10 {
-		return fmt.Errorf("invalid rating")
+	if err := security.ValidateReviewRating(rating); err != nil {
+		return fmt.Errorf("invalid rating: %w", err)
 	}
-	if len(content) < 10 || len(content) > 5000 {
-		return fmt.Errorf("invalid content length")
+	if err := security.ValidateLength(content, security.MinReviewContentLength, security.ReviewLengthLimit()); err != nil {
+		return fmt.Errorf("invalid content length: %w", err)
 	}
 
 	// Create repositories used during review creation
@@ -204,8 +211,48 @@ func (p *WriteProcessor) processCreateReview(ctx context.Context, op WriteOperat
 		return fmt.Errorf("manga must be completed to write review")
 	}
 
-	_, err = commentRepo.CreateReview(ctx, op.UserID, op.MangaID, rating, content)
-	return err
+	return p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+		_, err := commentRepo.CreateReviewTx(ctx, tx, op.UserID, op.MangaID, rating, content)
+		if errors.Is(err, comment.ErrReviewAlreadyExists) {
+			return nil // Created concurrently, same as above
+		}
+		return err
+	})
+}
+
+// applyOnce runs write in a transaction that also records the operation ID in
+// Applied_Operations. The write and its record commit together, so an
+// operation replayed after a crash finds its ID and is skipped.
+func (p *WriteProcessor) applyOnce(ctx context.Context, op WriteOperation, write func(tx *sql.Tx) error) (err error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO Applied_Operations (Operation_Id, Operation_Type)
+		VALUES (?, ?)
+		ON CONFLICT(Operation_Id) DO NOTHING
+	`, op.ID, op.Type)
+	if err != nil {
+		return err
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		log.Printf("queue: skipping operation %s (%s), already applied", op.ID, op.Type)
+		return nil
+	}
+	return write(tx)
 }
 
 // StartProcessing starts the background processor
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/security"
+)
+
+func setupAppliedOperationsDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../db/migrations/036_applied_operations.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE writes (op_id TEXT NOT NULL)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+func countWrites(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM writes`).Scan(&n); err != nil {
+		t.Fatalf("failed to count writes: %v", err)
+	}
+	return n
+}
+
+func TestApplyOnceSkipsOperationReplayedAfterCrash(t *testing.T) {
+	db := setupAppliedOperationsDB(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "write_queue.json")
+
+	var p *WriteProcessor
+	process := func(ctx context.Context, op WriteOperation) error {
+		return p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `INSERT INTO writes (op_id) VALUES (?)`, op.ID)
+			return err
+		})
+	}
+
+	before := NewWriteQueue(10, 3, process)
+	p = NewWriteProcessor(before, nil, db, nil)
+	before.SetPersistence(path)
+	if err := before.Enqueue("update_progress", 1, 2, map[string]interface{}{"current_chapter": 5}); err != nil {
+		t.Fatalf("Enqueue returned error: %v", err)
+	}
+	snapshot, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read queue file: %v", err)
+	}
+	if err := before.ProcessNext(ctx); err != nil {
+		t.Fatalf("ProcessNext returned error: %v", err)
+	}
+
+	// The process died after the write committed but before the queue file
+	// recorded it, so the operation comes back on restart
+	if err := os.WriteFile(path, snapshot, 0o600); err != nil {
+		t.Fatalf("failed to restore queue file: %v", err)
+	}
+	after := NewWriteQueue(10, 3, process)
+	after.SetPersistence(path)
+	if restored, err := after.Restore(); err != nil || restored != 1 {
+		t.Fatalf("expected the operation to be restored, got %d, %v", restored, err)
+	}
+	if processed, failed := after.ProcessAll(ctx); processed != 1 || failed != 0 {
+		t.Fatalf("expected 1 processed and 0 failed, got %d and %d", processed, failed)
+	}
+
+	if n := countWrites(t, db); n != 1 {
+		t.Fatalf("expected the write to be applied once, got %d", n)
+	}
+}
+
+func TestApplyOnceRollsBackClaimWhenWriteFails(t *testing.T) {
+	db := setupAppliedOperationsDB(t)
+	ctx := context.Background()
+	p := NewWriteProcessor(nil, nil, db, nil)
+	op := WriteOperation{ID: "op-1", Type: "create_review"}
+
+	err := p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO writes (op_id) VALUES (?)`, op.ID); err != nil {
+			return err
+		}
+		return errors.New("constraint failed")
+	})
+	if err == nil {
+		t.Fatalf("expected the write error to be returned")
+	}
+
+	// The failed attempt left nothing behind, so the retry applies it
+	if err := p.applyOnce(ctx, op, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO writes (op_id) VALUES (?)`, op.ID)
+		return err
+	}); err != nil {
+		t.Fatalf("retry returned error: %v", err)
+	}
+	if n := countWrites(t, db); n != 1 {
+		t.Fatalf("expected exactly one write after the retry, got %d", n)
+	}
+}
+
+func TestProcessCreateReviewRejectsRatingOutsideScoreRange(t *testing.T) {
+	db := setupAppliedOperationsDB(t)
+	p := NewWriteProcessor(nil, nil, db, nil)
+
+	for _, rating := range []interface{}{0, 6, float64(10)} {
+		op := WriteOperation{ID: "review", Type: "create_review", UserID: 1, MangaID: 2, Data: map[string]interface{}{
+			"rating":  rating,
+			"content": "A queued review long enough to pass validation.",
+		}}
+		if err := p.ProcessOperation(context.Background(), op); !errors.Is(err, security.ErrInvalidFormat) {
+			t.Fatalf("rating %v: expected ErrInvalidFormat, got %v", rating, err)
+		}
+	}
+}
@@ -26,6 +26,14 @@ type WriteQueue struct {
 	maxSize     int
 	maxRetries  int
 	processFunc func(ctx context.Context, op WriteOperation) error
+	deadLetters *DeadLetterQueue
+
+	// Persistence (see persistence.go)
+	persistPath  string
+	persistMu    sync.Mutex
+	applied      map[string]struct{}
+	appliedOrder []string
+	nextID       uint64
 }
 
 // NewWriteQueue creates a new write queue
@@ -35,6 +43,8 @@ func NewWriteQueue(maxSize, maxRetries int, processFunc func(ctx context.Context
 		maxSize:     maxSize,
 		maxRetries:  maxRetries,
 		processFunc: processFunc,
+		deadLetters: NewDeadLetterQueue(),
+		applied:     make(map[string]struct{}),
 	}
 }
 
@@ -47,8 +57,9 @@ func (q *WriteQueue) Enqueue(opType string, userID, mangaID int64, data map[stri
 		return fmt.Errorf("write queue is full")
 	}
 
+	q.nextID++
 	op := WriteOperation{
-		ID:        fmt.Sprintf("%d-%d-%d", userID, mangaID, time.Now().UnixNano()),
+		ID:        fmt.Sprintf("%d-%d-%d-%d", userID, mangaID, time.Now().UnixNano(), q.nextID),
 		Type:      opType,
 		UserID:    userID,
 		MangaID:   mangaID,
@@ -58,6 +69,7 @@ func (q *WriteQueue) Enqueue(opType string, userID, mangaID int64, data map[stri
 	}
 
 	q.operations = append(q.operations, op)
+	q.persistLocked()
 	return nil
 }
 
@@ -107,18 +119,34 @@ func (q *WriteQueue) ProcessNext(ctx context.Context) error {
 		return nil // Queue is empty
 	}
 
+	// Operations restored after a crash may already have been applied. This
+	// is only a shortcut: the ID is saved after processFunc returns, so the
+	// database writes record their operation ID in the same transaction
+	// (see WriteProcessor.applyOnce) and a replay missed here is skipped there
+	if q.wasApplied(op.ID) {
+		q.persist()
+		return nil
+	}
+
 	err := q.processFunc(ctx, *op)
 	if err != nil {
-		// Re-queue if retries available
+		// Re-queue if retries available, otherwise park it for an operator
+		q.mu.Lock()
 		if op.Retries < q.maxRetries {
 			op.Retries++
-			q.mu.Lock()
 			q.operations = append(q.operations, *op)
-			q.mu.Unlock()
+		} else {
+			q.deadLetters.Add(*op, err, op.Retries+1)
 		}
+		q.persistLocked()
+		q.mu.Unlock()
 		return err
 	}
 
+	q.mu.Lock()
+	q.markAppliedLocked(op.ID)
+	q.persistLocked()
+	q.mu.Unlock()
 	return nil
 }
 
@@ -150,6 +178,7 @@ func (q *WriteQueue) Clear() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	q.operations = make([]WriteOperation, 0)
+	q.persistLocked()
 }
 
 // MarshalJSON implements json.Marshaler for persistence
@@ -4,11 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/ngocan-dev/mangahub/backend/internal/dberr"
 	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
 )
 
+// ErrDuplicateChapter is returned when a manga already has a chapter with the
+// same number and language
+var ErrDuplicateChapter = errors.New("duplicate chapter")
+
 // Repository encapsulates all database access related to chapters.
 type Repository struct {
 	db *sql.DB
@@ -182,6 +188,94 @@ func (r *Repository) GetChapterCount(ctx context.Context, mangaID int64) (int, e
 	return count, nil
 }
 
+// GetChapterCounts returns the number of chapters of each manga in one query.
+// Manga without chapters are absent from the map.
+func (r *Repository) GetChapterCounts(ctx context.Context, mangaIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(mangaIDs))
+	if len(mangaIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := make([]string, len(mangaIDs))
+	args := make([]interface{}, len(mangaIDs))
+	for i, id := range mangaIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT manga_id, COUNT(*) FROM chapters WHERE manga_id IN (`+strings.Join(placeholders, ", ")+`) GROUP BY manga_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mangaID int64
+		var count int
+		if err := rows.Scan(&mangaID, &count); err != nil {
+			return nil, err
+		}
+		counts[mangaID] = count
+	}
+	return counts, rows.Err()
+}
+
+// GetRecentChapters returns the latest released chapters of a manga, newest
+// first, without their content.
+func (r *Repository) GetRecentChapters(ctx context.Context, mangaID int64, limit int) ([]pkgchapter.Chapter, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, manga_id, number, title, created_at, updated_at
+        FROM chapters
+        WHERE manga_id = ?
+        ORDER BY created_at DESC, number DESC
+        LIMIT ?
+    `, mangaID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chapters []pkgchapter.Chapter
+	for rows.Next() {
+		var (
+			chapter   pkgchapter.Chapter
+			title     sql.NullString
+			createdAt sql.NullTime
+			updatedAt sql.NullTime
+		)
+		if err := rows.Scan(&chapter.ID, &chapter.MangaID, &chapter.Number, &title, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		chapter.Title = title.String
+		if createdAt.Valid {
+			t := createdAt.Time
+			chapter.CreatedAt = &t
+		}
+		if updatedAt.Valid {
+			t := updatedAt.Time
+			chapter.UpdatedAt = &t
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters, rows.Err()
+}
+
+// GetMaxChapterNumber returns the highest chapter number of a manga, or 0 when it has none.
+func (r *Repository) GetMaxChapterNumber(ctx context.Context, mangaID int64) (int, error) {
+	var maxChapter sql.NullInt64
+	if err := r.db.QueryRowContext(ctx, `SELECT MAX(number) FROM chapters WHERE manga_id = ?`, mangaID).Scan(&maxChapter); err != nil {
+		return 0, err
+	}
+	return int(maxChapter.Int64), nil
+}
+
 // CreateChapter inserts a chapter and updates manga metadata.
 func (r *Repository) CreateChapter(ctx context.Context, mangaID int64, number int, title string, contentText string, language string) (int64, error) {
 	if language == "" {
@@ -203,13 +297,129 @@ ON CONFLICT(manga_id, number, language) DO UPDATE SET title=excluded.title, cont
 		return 0, err
 	}
 
+	if err := r.bumpLastChapter(ctx, mangaID, number, now); err != nil {
+		return 0, err
+	}
+
+	return chapterID, nil
+}
+
+// InsertChapter inserts a new chapter and updates manga metadata. Unlike
+// CreateChapter it never overwrites an existing chapter; a duplicate number
+// fails with the database's unique constraint error.
+func (r *Repository) InsertChapter(ctx context.Context, mangaID int64, number int, title string, contentText string, language string) (int64, error) {
+	if language == "" {
+		language = "ja"
+	}
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, `
+INSERT INTO chapters (manga_id, number, title, language, content_text, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`, mangaID, number, title, language, contentText, now, now)
+	if err != nil {
+		if dberr.IsDuplicateKey(err) {
+			return 0, ErrDuplicateChapter
+		}
+		return 0, err
+	}
+
+	chapterID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.bumpLastChapter(ctx, mangaID, number, now); err != nil {
+		return 0, err
+	}
+
+	return chapterID, nil
+}
+
+// UpdateChapter rewrites a chapter's number, title and content, and raises
+// the manga's last chapter when the new number is past it.
+func (r *Repository) UpdateChapter(ctx context.Context, chapterID, mangaID int64, number int, title string, contentText string) error {
+	now := time.Now()
 	if _, err := r.db.ExecContext(ctx, `
+UPDATE chapters
+SET number = ?, title = ?, content_text = ?, updated_at = ?
+WHERE id = ?
+`, number, title, contentText, now, chapterID); err != nil {
+		if dberr.IsDuplicateKey(err) {
+			return ErrDuplicateChapter
+		}
+		return err
+	}
+	return r.bumpLastChapter(ctx, mangaID, number, now)
+}
+
+// GetMangaTitle returns the title of a manga that has not been deleted, or
+// false when there is none.
+func (r *Repository) GetMangaTitle(ctx context.Context, mangaID int64) (string, bool, error) {
+	var title string
+	err := r.db.QueryRowContext(ctx, `SELECT title FROM mangas WHERE id = ? AND deleted_at IS NULL`, mangaID).Scan(&title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return title, true, nil
+}
+
+func (r *Repository) bumpLastChapter(ctx context.Context, mangaID int64, number int, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
 UPDATE mangas
 SET last_chapter = ?, last_chapter_at = ?
 WHERE id = ? AND (last_chapter IS NULL OR last_chapter < ?)
-`, number, now, mangaID, number); err != nil {
-		return 0, err
+`, number, at, mangaID, number)
+	return err
+}
+
+// GetAdjacentChapters returns the chapters immediately before and after
+// chapterNumber in the manga. Gaps in numbering are skipped; either result is
+// nil at the boundaries.
+func (r *Repository) GetAdjacentChapters(ctx context.Context, mangaID int64, chapterNumber int) (*pkgchapter.ChapterSummary, *pkgchapter.ChapterSummary, error) {
+	prev, err := r.findNeighbour(ctx, `
+        SELECT id, manga_id, number, title, updated_at
+        FROM chapters
+        WHERE manga_id = ? AND number < ?
+        ORDER BY number DESC
+        LIMIT 1
+    `, mangaID, chapterNumber)
+	if err != nil {
+		return nil, nil, err
 	}
+	next, err := r.findNeighbour(ctx, `
+        SELECT id, manga_id, number, title, updated_at
+        FROM chapters
+        WHERE manga_id = ? AND number > ?
+        ORDER BY number ASC
+        LIMIT 1
+    `, mangaID, chapterNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prev, next, nil
+}
 
-	return chapterID, nil
+func (r *Repository) findNeighbour(ctx context.Context, query string, args ...interface{}) (*pkgchapter.ChapterSummary, error) {
+	var (
+		summary   pkgchapter.ChapterSummary
+		title     sql.NullString
+		updatedAt sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&summary.ID, &summary.MangaID, &summary.Number, &title, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	summary.Title = title.String
+	if updatedAt.Valid {
+		t := updatedAt.Time
+		summary.UpdatedAt = &t
+	}
+	return &summary, nil
 }
@@ -0,0 +1,180 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	"github.com/ngocan-dev/mangahub/backend/internal/dberr"
+)
+
+// CreateCollection inserts a named collection for the user. It returns true
+// when the user already has a collection with the same name, ignoring case.
+func (r *Repository) CreateCollection(ctx context.Context, userID int64, name string) (*domainlibrary.Collection, bool, error) {
+	var existing int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM Collections WHERE User_Id = ? AND LOWER(Name) = LOWER(?)
+`, userID, name).Scan(&existing); err != nil {
+		return nil, false, err
+	}
+	if existing > 0 {
+		return nil, true, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+INSERT INTO Collections (User_Id, Name) VALUES (?, ?)
+`, userID, name)
+	if err != nil {
+		if dberr.IsDuplicateKey(err) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, false, err
+	}
+
+	collection, err := r.GetCollection(ctx, userID, id)
+	if err != nil {
+		return nil, false, err
+	}
+	return collection, false, nil
+}
+
+// GetCollection fetches one of the user's collections with its manga IDs.
+// It returns nil when the collection does not exist or belongs to someone else.
+func (r *Repository) GetCollection(ctx context.Context, userID, collectionID int64) (*domainlibrary.Collection, error) {
+	collection := domainlibrary.Collection{MangaIDs: []int64{}}
+	var createdAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+SELECT Collection_Id, Name, Created_At FROM Collections WHERE Collection_Id = ? AND User_Id = ?
+`, collectionID, userID).Scan(&collection.ID, &collection.Name, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		collection.CreatedAt = createdAt.Time
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT Manga_Id FROM Collection_Items WHERE Collection_Id = ? ORDER BY Added_At, Manga_Id
+`, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mangaID int64
+		if err := rows.Scan(&mangaID); err != nil {
+			return nil, err
+		}
+		collection.MangaIDs = append(collection.MangaIDs, mangaID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// ListCollections returns all of the user's collections with their manga IDs
+func (r *Repository) ListCollections(ctx context.Context, userID int64) ([]domainlibrary.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT c.Collection_Id, c.Name, c.Created_At, ci.Manga_Id
+FROM Collections c
+LEFT JOIN Collection_Items ci ON ci.Collection_Id = c.Collection_Id
+WHERE c.User_Id = ?
+ORDER BY c.Name, c.Collection_Id, ci.Added_At, ci.Manga_Id
+`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []domainlibrary.Collection{}
+	for rows.Next() {
+		var id int64
+		var name string
+		var createdAt sql.NullTime
+		var mangaID sql.NullInt64
+		if err := rows.Scan(&id, &name, &createdAt, &mangaID); err != nil {
+			return nil, err
+		}
+		if n := len(collections); n == 0 || collections[n-1].ID != id {
+			collection := domainlibrary.Collection{ID: id, Name: name, MangaIDs: []int64{}}
+			if createdAt.Valid {
+				collection.CreatedAt = createdAt.Time
+			}
+			collections = append(collections, collection)
+		}
+		if mangaID.Valid {
+			last := &collections[len(collections)-1]
+			last.MangaIDs = append(last.MangaIDs, mangaID.Int64)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// AddToCollection adds a manga to a collection; adding it twice is a no-op
+func (r *Repository) AddToCollection(ctx context.Context, collectionID, mangaID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO Collection_Items (Collection_Id, Manga_Id)
+SELECT ?, ?
+WHERE NOT EXISTS (SELECT 1 FROM Collection_Items WHERE Collection_Id = ? AND Manga_Id = ?)
+`, collectionID, mangaID, collectionID, mangaID)
+	if err != nil && dberr.IsDuplicateKey(err) {
+		return nil
+	}
+	return err
+}
+
+// RemoveFromCollection removes a manga from a collection. It returns
+// sql.ErrNoRows when the manga was not in the collection.
+func (r *Repository) RemoveFromCollection(ctx context.Context, collectionID, mangaID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+DELETE FROM Collection_Items WHERE Collection_Id = ? AND Manga_Id = ?
+`, collectionID, mangaID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// GetMangaCollections lists the user's collections that contain the manga
+func (r *Repository) GetMangaCollections(ctx context.Context, userID, mangaID int64) ([]domainlibrary.CollectionSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT c.Collection_Id, c.Name
+FROM Collections c
+JOIN Collection_Items ci ON ci.Collection_Id = c.Collection_Id
+WHERE c.User_Id = ? AND ci.Manga_Id = ?
+ORDER BY c.Name, c.Collection_Id
+`, userID, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collections []domainlibrary.CollectionSummary
+	for rows.Next() {
+		var collection domainlibrary.CollectionSummary
+		if err := rows.Scan(&collection.ID, &collection.Name); err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
@@ -0,0 +1,82 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/dberr"
+)
+
+// ImportRow is a resolved library entry ready to be inserted
+type ImportRow struct {
+	MangaID        int64
+	Status         string
+	CurrentChapter int
+	IsFavorite     bool
+}
+
+// ImportEntries inserts library entries, their initial progress and favorite
+// flags in one transaction. Rows that hit a unique constraint, e.g. because
+// the entry was added concurrently, get an error in the returned slice at
+// their index and the rest carry on; any other error rolls everything back.
+func (r *Repository) ImportEntries(ctx context.Context, userID int64, rows []ImportRow) (rowErrs []error, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	rowErrs = make([]error, len(rows))
+	now := time.Now()
+	for i, row := range rows {
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO user_library (user_id, manga_id, status, current_chapter, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`, userID, row.MangaID, row.Status, row.CurrentChapter, now, now)
+		if err != nil {
+			if dberr.IsDuplicateKey(err) {
+				rowErrs[i], err = err, nil
+				continue
+			}
+			return nil, err
+		}
+
+		var chapterID *int64
+		if row.CurrentChapter > 0 {
+			var chapterIDVal sql.NullInt64
+			if scanErr := tx.QueryRowContext(ctx, `
+SELECT id FROM chapters WHERE manga_id = ? AND number = ? LIMIT 1
+`, row.MangaID, row.CurrentChapter).Scan(&chapterIDVal); scanErr == nil && chapterIDVal.Valid {
+				chapterID = &chapterIDVal.Int64
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO reading_progress (user_id, manga_id, current_chapter_id, last_read_at, progress_percent, current_page)
+SELECT ?, ?, ?, ?, 0, 0
+WHERE NOT EXISTS (SELECT 1 FROM reading_progress WHERE user_id = ? AND manga_id = ?)
+`, userID, row.MangaID, chapterID, now, userID, row.MangaID)
+		if err != nil {
+			return nil, err
+		}
+
+		if row.IsFavorite {
+			_, err = tx.ExecContext(ctx, `
+INSERT INTO favorites (user_id, manga_id)
+SELECT ?, ?
+WHERE NOT EXISTS (SELECT 1 FROM favorites WHERE user_id = ? AND manga_id = ?)
+`, userID, row.MangaID, userID, row.MangaID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rowErrs, nil
+}
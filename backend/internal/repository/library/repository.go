@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -30,11 +31,26 @@ func (r *Repository) CheckLibraryExists(ctx context.Context, userID, mangaID int
 	return count > 0, nil
 }
 
-// AddToLibrary inserts both library entry and initial progress transactionally.
+// AddToLibrary inserts both library entry and initial progress.
 // It returns true when the manga already exists in the user's library.
 func (r *Repository) AddToLibrary(ctx context.Context, userID, mangaID int64, status string, currentChapter int) (bool, error) {
+	return addToLibrary(ctx, r.db, userID, mangaID, status, currentChapter)
+}
+
+// AddToLibraryTx is AddToLibrary inside the caller's transaction
+func (r *Repository) AddToLibraryTx(ctx context.Context, tx *sql.Tx, userID, mangaID int64, status string, currentChapter int) (bool, error) {
+	return addToLibrary(ctx, tx, userID, mangaID, status, currentChapter)
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// addToLibrary runs AddToLibrary on q, which may be a *sql.DB or *sql.Tx
+func addToLibrary(ctx context.Context, q execQuerier, userID, mangaID int64, status string, currentChapter int) (bool, error) {
 	now := time.Now()
-	if _, err := r.db.ExecContext(ctx, `
+	if _, err := q.ExecContext(ctx, `
 INSERT INTO user_library (user_id, manga_id, status, current_chapter, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?)
 `, userID, mangaID, status, currentChapter, now, now); err != nil {
@@ -48,14 +64,14 @@ VALUES (?, ?, ?, ?, ?, ?)
 	var chapterID *int64
 	if currentChapter > 0 {
 		var chapterIDVal sql.NullInt64
-		if err := r.db.QueryRowContext(ctx, `
+		if err := q.QueryRowContext(ctx, `
 SELECT id FROM chapters WHERE manga_id = ? AND number = ? LIMIT 1
 `, mangaID, currentChapter).Scan(&chapterIDVal); err == nil && chapterIDVal.Valid {
 			chapterID = &chapterIDVal.Int64
 		}
 	}
 
-	if _, err := r.db.ExecContext(ctx, `
+	if _, err := q.ExecContext(ctx, `
 INSERT INTO reading_progress (user_id, manga_id, current_chapter_id, last_read_at, progress_percent, current_page)
 VALUES (?, ?, ?, ?, 0, 0)
 ON DUPLICATE KEY UPDATE current_chapter_id = VALUES(current_chapter_id), last_read_at = VALUES(last_read_at)
@@ -140,8 +156,83 @@ WHERE user_id = ? AND manga_id = ?
 	return r.GetLibraryStatus(ctx, userID, mangaID)
 }
 
-// GetLibrary fetches the user's library listing
-func (r *Repository) GetLibrary(ctx context.Context, userID int64) ([]domainlibrary.LibraryEntry, error) {
+// GetLibraryStatuses fetches the user's library status for several manga in
+// one query, keyed by manga ID. Manga outside the library are absent.
+func (r *Repository) GetLibraryStatuses(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]*domainlibrary.LibraryStatus, error) {
+	statuses := make(map[int64]*domainlibrary.LibraryStatus, len(mangaIDs))
+	if len(mangaIDs) == 0 {
+		return statuses, nil
+	}
+
+	placeholders := make([]string, len(mangaIDs))
+	args := make([]interface{}, 0, len(mangaIDs)+1)
+	args = append(args, userID)
+	for i, id := range mangaIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT manga_id, status, current_chapter, created_at, updated_at
+FROM user_library
+WHERE user_id = ? AND manga_id IN (`+strings.Join(placeholders, ", ")+`)
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mangaID int64
+		var status domainlibrary.LibraryStatus
+		var createdAt, updatedAt sql.NullTime
+		if err := rows.Scan(&mangaID, &status.Status, &status.CurrentChapter, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			status.StartedAt = &createdAt.Time
+		}
+		if updatedAt.Valid {
+			status.CompletedAt = &updatedAt.Time
+		}
+		statuses[mangaID] = &status
+	}
+	return statuses, rows.Err()
+}
+
+// librarySortOrders maps library sort keys to ORDER BY clauses; the manga id
+// keeps the order stable across pages
+var librarySortOrders = map[string]string{
+	domainlibrary.SortLastUpdated: "ul.updated_at DESC, ul.manga_id DESC",
+	domainlibrary.SortTitle:       "m.title COLLATE NOCASE ASC, ul.manga_id ASC",
+	domainlibrary.SortRating:      "m.rating_average DESC, m.title COLLATE NOCASE ASC, ul.manga_id ASC",
+}
+
+// GetLibrary fetches a page of the user's library listing, optionally limited
+// to one status, along with the number of matching entries. A zero limit
+// returns every entry. Entries for soft-deleted manga are hidden but kept so
+// they return when the manga is restored.
+func (r *Repository) GetLibrary(ctx context.Context, userID int64, status, sort string, limit, offset int) ([]domainlibrary.LibraryEntry, int, error) {
+	filter := "ul.user_id = ? AND m.deleted_at IS NULL"
+	args := []interface{}{userID}
+	if status != "" {
+		filter += " AND ul.status = ?"
+		args = append(args, status)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*)
+FROM user_library ul
+JOIN mangas m ON m.id = ul.manga_id
+WHERE `+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := librarySortOrders[sort]
+	if !ok {
+		order = librarySortOrders[domainlibrary.SortLastUpdated]
+	}
 	query := `
 SELECT ul.manga_id,
        COALESCE(m.title, '') AS title,
@@ -152,12 +243,16 @@ SELECT ul.manga_id,
        ul.updated_at
 FROM user_library ul
 JOIN mangas m ON m.id = ul.manga_id
-WHERE ul.user_id = ?
-ORDER BY ul.updated_at DESC
-`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+WHERE ` + filter + `
+ORDER BY ` + order
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -166,7 +261,7 @@ ORDER BY ul.updated_at DESC
 		var entry domainlibrary.LibraryEntry
 		var createdAt, updatedAt sql.NullTime
 		if err := rows.Scan(&entry.MangaID, &entry.Title, &entry.CoverImage, &entry.Status, &entry.CurrentChapter, &createdAt, &updatedAt); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		if createdAt.Valid {
 			entry.StartedAt = &createdAt.Time
@@ -178,7 +273,83 @@ ORDER BY ul.updated_at DESC
 		entries = append(entries, entry)
 	}
 	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// CountLibraryByStatus counts the user's visible library entries per status
+func (r *Repository) CountLibraryByStatus(ctx context.Context, userID int64) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT ul.status, COUNT(*)
+FROM user_library ul
+JOIN mangas m ON m.id = ul.manga_id
+WHERE ul.user_id = ? AND m.deleted_at IS NULL
+GROUP BY ul.status
+`, userID)
+	if err != nil {
 		return nil, err
 	}
-	return entries, nil
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// StreamLibraryExport walks the user's library for export and calls fn for
+// each entry as rows are read, so large libraries are never held in memory.
+// The current chapter comes from reading progress when it is known.
+// Soft-deleted manga are left out, as in GetLibrary.
+func (r *Repository) StreamLibraryExport(ctx context.Context, userID int64, fn func(domainlibrary.ExportEntry) error) error {
+	query := `
+SELECT ul.manga_id,
+       COALESCE(m.title, '') AS title,
+       COALESCE(ul.status, '') AS status,
+       COALESCE(c.number, ul.current_chapter, 0) AS current_chapter,
+       CASE WHEN f.manga_id IS NULL THEN 0 ELSE 1 END AS is_favorite,
+       ul.created_at,
+       ul.updated_at,
+       rp.last_read_at
+FROM user_library ul
+JOIN mangas m ON m.id = ul.manga_id
+LEFT JOIN reading_progress rp ON rp.user_id = ul.user_id AND rp.manga_id = ul.manga_id
+LEFT JOIN chapters c ON c.id = rp.current_chapter_id
+LEFT JOIN favorites f ON f.user_id = ul.user_id AND f.manga_id = ul.manga_id
+WHERE ul.user_id = ? AND m.deleted_at IS NULL
+ORDER BY m.title, ul.manga_id
+`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domainlibrary.ExportEntry
+		var createdAt, updatedAt, lastReadAt sql.NullTime
+		if err := rows.Scan(&entry.MangaID, &entry.Title, &entry.Status, &entry.CurrentChapter, &entry.IsFavorite, &createdAt, &updatedAt, &lastReadAt); err != nil {
+			return err
+		}
+		if createdAt.Valid {
+			entry.AddedAt = &createdAt.Time
+		}
+		if updatedAt.Valid {
+			entry.UpdatedAt = &updatedAt.Time
+		}
+		if lastReadAt.Valid {
+			entry.LastReadAt = &lastReadAt.Time
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
@@ -0,0 +1,117 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// Config sets the limits and content policy the validators apply. Chat and
+// reviews have separate limits because a review is legitimately much longer
+// than a chat line.
+type Config struct {
+	// MaxMessageLength caps chat messages, in characters
+	MaxMessageLength int
+	// MaxReviewLength caps review content, in characters
+	MaxReviewLength int
+	// DenyPatterns are the regular expressions DetectSQLInjection rejects;
+	// nil keeps the built-in patterns
+	DenyPatterns []string
+	// AllowPatterns mark text DetectSQLInjection ignores, such as a phrase
+	// the deny patterns would otherwise catch
+	AllowPatterns []string
+}
+
+// policy is a compiled Config
+type policy struct {
+	maxMessageLength int
+	maxReviewLength  int
+	deny             []*regexp.Regexp
+	allow            []*regexp.Regexp
+}
+
+// SQL injection patterns to detect unless Config.DenyPatterns replaces them
+var defaultDenyPatterns = []string{
+	`(?i)(\b(SELECT|INSERT|UPDATE|DELETE|DROP|CREATE|ALTER|EXEC|EXECUTE|UNION|SCRIPT)\b)`,
+	`(?i)(--|/\*|\*/|;|\||&)`,
+	`(?i)(\b(OR|AND)\s+\d+\s*=\s*\d+)`,
+	`(?i)(\b(OR|AND)\s+['"]\w+['"]\s*=\s*['"]\w+['"])`,
+	`(?i)(\b(OR|AND)\s+['"]1['"]\s*=\s*['"]1['"])`,
+}
+
+var current atomic.Pointer[policy]
+
+func init() {
+	if err := Configure(DefaultConfig()); err != nil {
+		panic(err)
+	}
+}
+
+// DefaultConfig returns the policy used until Configure is called
+func DefaultConfig() Config {
+	return Config{
+		MaxMessageLength: MaxMessageLength,
+		MaxReviewLength:  MaxReviewContentLength,
+		DenyPatterns:     append([]string(nil), defaultDenyPatterns...),
+	}
+}
+
+// Configure replaces the validation policy. Zero limits and nil deny
+// patterns keep the defaults; a pattern that does not compile is an error and
+// leaves the current policy in place.
+func Configure(cfg Config) error {
+	defaults := DefaultConfig()
+	if cfg.MaxMessageLength == 0 {
+		cfg.MaxMessageLength = defaults.MaxMessageLength
+	}
+	if cfg.MaxReviewLength == 0 {
+		cfg.MaxReviewLength = defaults.MaxReviewLength
+	}
+	if cfg.DenyPatterns == nil {
+		cfg.DenyPatterns = defaults.DenyPatterns
+	}
+	if cfg.MaxMessageLength < 1 {
+		return fmt.Errorf("security: max message length must be positive, got %d", cfg.MaxMessageLength)
+	}
+	if cfg.MaxReviewLength < MinReviewContentLength {
+		return fmt.Errorf("security: max review length must be at least %d, got %d", MinReviewContentLength, cfg.MaxReviewLength)
+	}
+
+	deny, err := compilePatterns(cfg.DenyPatterns)
+	if err != nil {
+		return err
+	}
+	allow, err := compilePatterns(cfg.AllowPatterns)
+	if err != nil {
+		return err
+	}
+	current.Store(&policy{
+		maxMessageLength: cfg.MaxMessageLength,
+		maxReviewLength:  cfg.MaxReviewLength,
+		deny:             deny,
+		allow:            allow,
+	})
+	return nil
+}
+
+// MessageLengthLimit returns the configured chat message limit
+func MessageLengthLimit() int {
+	return current.Load().maxMessageLength
+}
+
+// ReviewLengthLimit returns the configured review content limit
+func ReviewLengthLimit() int {
+	return current.Load().maxReviewLength
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("security: invalid pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
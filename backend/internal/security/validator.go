@@ -22,7 +22,8 @@ var (
 	ErrContainsSQLInjection = errors.New("input contains potentially dangerous SQL patterns")
 )
 
-// Input limits
+// Input limits; MaxReviewContentLength and MaxMessageLength are defaults that
+// Configure can change
 const (
 	MaxUsernameLength      = 50
 	MinUsernameLength      = 3
@@ -31,22 +32,13 @@ const (
 	MinPasswordLength      = 8
 	MaxReviewContentLength = 5000
 	MinReviewContentLength = 10
-	MaxReviewRating        = 10
+	MaxReviewRating        = 5
 	MinReviewRating        = 1
 	MaxMessageLength       = 1000
 	MaxMangaTitleLength    = 200
 	MaxDescriptionLength   = 10000
 )
 
-// SQL injection patterns to detect
-var sqlInjectionPatterns = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)(\b(SELECT|INSERT|UPDATE|DELETE|DROP|CREATE|ALTER|EXEC|EXECUTE|UNION|SCRIPT)\b)`),
-	regexp.MustCompile(`(?i)(--|/\*|\*/|;|\||&)`),
-	regexp.MustCompile(`(?i)(\b(OR|AND)\s+\d+\s*=\s*\d+)`),
-	regexp.MustCompile(`(?i)(\b(OR|AND)\s+['"]\w+['"]\s*=\s*['"]\w+['"])`),
-	regexp.MustCompile(`(?i)(\b(OR|AND)\s+['"]1['"]\s*=\s*['"]1['"])`),
-}
-
 // XSS patterns to detect
 var xssPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)<\s*script[^>]*>.*?</\s*script\s*>`),
@@ -137,7 +129,11 @@ func SanitizeHTML(input string) string {
 // DetectSQLInjection checks for SQL injection patterns
 // SQL injection attempts are blocked
 func DetectSQLInjection(input string) error {
-	for _, pattern := range sqlInjectionPatterns {
+	p := current.Load()
+	for _, allowed := range p.allow {
+		input = allowed.ReplaceAllString(input, " ")
+	}
+	for _, pattern := range p.deny {
 		if pattern.MatchString(input) {
 			return ErrContainsSQLInjection
 		}
@@ -252,7 +248,7 @@ func ValidatePassword(password string) error {
 // Input length limits are enforced
 // XSS attempts are sanitized
 func ValidateReviewContent(content string) error {
-	if err := ValidateLength(content, MinReviewContentLength, MaxReviewContentLength); err != nil {
+	if err := ValidateLength(content, MinReviewContentLength, ReviewLengthLimit()); err != nil {
 		return err
 	}
 
@@ -270,6 +266,16 @@ func ValidateReviewContent(content string) error {
 	return nil
 }
 
+// ValidateMessage validates a chat message
+// Input length limits are enforced
+// SQL injection attempts are blocked
+func ValidateMessage(content string) error {
+	if err := ValidateLength(content, 1, MessageLengthLimit()); err != nil {
+		return err
+	}
+	return DetectSQLInjection(content)
+}
+
 // SanitizeReviewContent sanitizes review content
 // XSS attempts are sanitized
 func SanitizeReviewContent(content string) string {
@@ -0,0 +1,77 @@
+package security
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func configureForTest(t *testing.T, cfg Config) {
+	t.Helper()
+	if err := Configure(cfg); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := Configure(DefaultConfig()); err != nil {
+			t.Fatalf("restore default config: %v", err)
+		}
+	})
+}
+
+func TestShorterMessageLimitRejectsTextReviewsAccept(t *testing.T) {
+	configureForTest(t, Config{MaxMessageLength: 20, MaxReviewLength: 200})
+
+	content := strings.Repeat("great read ", 5)
+	if err := ValidateMessage(content); !errors.Is(err, ErrInputTooLong) {
+		t.Fatalf("expected chat message to be too long, got %v", err)
+	}
+	if err := ValidateReviewContent(content); err != nil {
+		t.Fatalf("expected review to be accepted, got %v", err)
+	}
+	if err := ValidateMessage("great read"); err != nil {
+		t.Fatalf("expected short message to pass, got %v", err)
+	}
+}
+
+func TestConfiguredPatterns(t *testing.T) {
+	configureForTest(t, Config{
+		DenyPatterns:  []string{`(?i)\bspoiler\b`},
+		AllowPatterns: []string{`(?i)\bno spoiler\b`},
+	})
+
+	if err := DetectSQLInjection("the villain dies, spoiler"); !errors.Is(err, ErrContainsSQLInjection) {
+		t.Fatalf("expected deny pattern to match, got %v", err)
+	}
+	if err := DetectSQLInjection("no spoiler here"); err != nil {
+		t.Fatalf("expected allowed phrase to pass, got %v", err)
+	}
+	// The built-in patterns are replaced, so SQL keywords now pass
+	if err := DetectSQLInjection("select a chapter"); err != nil {
+		t.Fatalf("expected built-in patterns to be replaced, got %v", err)
+	}
+}
+
+func TestDefaultConfigKeepsBuiltInLimits(t *testing.T) {
+	configureForTest(t, Config{})
+
+	if MessageLengthLimit() != MaxMessageLength || ReviewLengthLimit() != MaxReviewContentLength {
+		t.Fatalf("expected default limits, got %d/%d", MessageLengthLimit(), ReviewLengthLimit())
+	}
+	if err := DetectSQLInjection("1 OR 1=1"); !errors.Is(err, ErrContainsSQLInjection) {
+		t.Fatalf("expected built-in patterns, got %v", err)
+	}
+}
+
+func TestConfigureRejectsInvalidPolicy(t *testing.T) {
+	configureForTest(t, DefaultConfig())
+
+	if err := Configure(Config{DenyPatterns: []string{"("}}); err == nil {
+		t.Fatal("expected invalid pattern to be rejected")
+	}
+	if err := Configure(Config{MaxMessageLength: -1}); err == nil {
+		t.Fatal("expected negative limit to be rejected")
+	}
+	if MessageLengthLimit() != MaxMessageLength {
+		t.Fatalf("expected failed configure to keep the policy, got %d", MessageLengthLimit())
+	}
+}
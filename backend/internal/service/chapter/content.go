@@ -0,0 +1,103 @@
+package chapter
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
+)
+
+// Chapter content page sizes, in characters
+const (
+	DefaultContentPageSize = 4000
+	MaxContentPageSize     = 20000
+)
+
+// ErrPageOutOfRange is returned when a content page past the end is requested
+var ErrPageOutOfRange = errors.New("page out of range")
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+
+// ContentPage is one page of a chapter's text
+type ContentPage struct {
+	Content    string `json:"content"`
+	Page       int    `json:"page"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// GetChapterPage returns a chapter along with one page of its content.
+// It returns a nil chapter when the chapter does not exist.
+func (s *Service) GetChapterPage(ctx context.Context, chapterID int64, page, pageSize int) (*pkgchapter.Chapter, *ContentPage, error) {
+	chapter, err := s.repo.GetChapterByID(ctx, chapterID)
+	if err != nil || chapter == nil {
+		return nil, nil, err
+	}
+	contentPage, err := PaginateContent(chapter.ContentText, page, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chapter, contentPage, nil
+}
+
+// PaginateContent splits text into pages of whole paragraphs. Paragraphs are
+// separated by blank lines and packed into a page until the next one would
+// push it past pageSize characters; a paragraph longer than pageSize gets a
+// page of its own rather than being cut. Pages are numbered from 1.
+func PaginateContent(content string, page, pageSize int) (*ContentPage, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultContentPageSize
+	}
+	if pageSize > MaxContentPageSize {
+		pageSize = MaxContentPageSize
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	pages := splitParagraphPages(content, pageSize)
+	if len(pages) == 0 {
+		pages = []string{""}
+	}
+	if page > len(pages) {
+		return nil, ErrPageOutOfRange
+	}
+
+	return &ContentPage{
+		Content:    pages[page-1],
+		Page:       page,
+		TotalPages: len(pages),
+	}, nil
+}
+
+func splitParagraphPages(content string, pageSize int) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var (
+		pages   []string
+		current []string
+		size    int
+	)
+	for _, p := range paragraphBreak.Split(content, -1) {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		n := utf8.RuneCountInString(p)
+		if len(current) > 0 && size+2+n > pageSize {
+			pages = append(pages, strings.Join(current, "\n\n"))
+			current, size = nil, 0
+		}
+		if len(current) > 0 {
+			size += 2
+		}
+		current = append(current, p)
+		size += n
+	}
+	if len(current) > 0 {
+		pages = append(pages, strings.Join(current, "\n\n"))
+	}
+	return pages
+}
@@ -0,0 +1,47 @@
+package chapter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPaginateContentSplitsOnParagraphBoundaries(t *testing.T) {
+	content := "First paragraph.\r\n\r\nSecond one here.\n\n  \n\nThird paragraph is longer than the page size on its own.\n\nFour."
+
+	first, err := PaginateContent(content, 1, 40)
+	if err != nil {
+		t.Fatalf("PaginateContent returned error: %v", err)
+	}
+	if first.TotalPages != 3 {
+		t.Fatalf("expected 3 pages, got %d", first.TotalPages)
+	}
+	if first.Content != "First paragraph.\n\nSecond one here." {
+		t.Fatalf("unexpected first page %q", first.Content)
+	}
+
+	// An oversized paragraph is kept whole on its own page
+	second, _ := PaginateContent(content, 2, 40)
+	if !strings.HasPrefix(second.Content, "Third paragraph") || strings.Contains(second.Content, "Four.") {
+		t.Fatalf("unexpected second page %q", second.Content)
+	}
+
+	third, _ := PaginateContent(content, 3, 40)
+	if third.Content != "Four." {
+		t.Fatalf("unexpected third page %q", third.Content)
+	}
+
+	if _, err := PaginateContent(content, 4, 40); !errors.Is(err, ErrPageOutOfRange) {
+		t.Fatalf("expected ErrPageOutOfRange, got %v", err)
+	}
+}
+
+func TestPaginateContentEmpty(t *testing.T) {
+	page, err := PaginateContent("", 1, 0)
+	if err != nil {
+		t.Fatalf("PaginateContent returned error: %v", err)
+	}
+	if page.TotalPages != 1 || page.Content != "" {
+		t.Fatalf("expected a single empty page, got %+v", page)
+	}
+}
@@ -0,0 +1,136 @@
+package chapter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
+)
+
+// Release feed sizes, in chapters
+const (
+	DefaultFeedSize = 20
+	MaxFeedSize     = 100
+)
+
+// FeedSource describes the manga a release feed is published for
+type FeedSource struct {
+	MangaID     int64
+	Title       string
+	Description string
+	// BaseURL is the public origin links in the feed point at, e.g. https://mangahub.example
+	BaseURL string
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title   string  `xml:"title"`
+	Link    string  `xml:"link"`
+	GUID    rssGUID `xml:"guid"`
+	PubDate string  `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// ReleaseFeed renders an RSS 2.0 document of the latest chapters of a manga,
+// newest first, so feed readers can follow releases.
+func (s *Service) ReleaseFeed(ctx context.Context, source FeedSource, limit int) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultFeedSize
+	}
+	if limit > MaxFeedSize {
+		limit = MaxFeedSize
+	}
+
+	chapters, err := s.repo.GetRecentChapters(ctx, source.MangaID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return RenderFeed(source, chapters)
+}
+
+// RenderFeed builds the RSS document for chapters, which must already be
+// ordered newest first. A chapter's pubDate is its release (creation) time,
+// falling back to its last update.
+func RenderFeed(source FeedSource, chapters []pkgchapter.Chapter) ([]byte, error) {
+	base := strings.TrimRight(source.BaseURL, "/")
+	description := source.Description
+	if description == "" {
+		description = fmt.Sprintf("New chapters of %s", source.Title)
+	}
+
+	channel := rssChannel{
+		Title:       source.Title,
+		Link:        fmt.Sprintf("%s/mangas/%d", base, source.MangaID),
+		Description: description,
+		Self: rssLink{
+			Href: fmt.Sprintf("%s/mangas/%d/feed.xml", base, source.MangaID),
+			Rel:  "self",
+			Type: "application/rss+xml",
+		},
+		Items: make([]rssItem, 0, len(chapters)),
+	}
+
+	for _, ch := range chapters {
+		title := fmt.Sprintf("Chapter %d", ch.Number)
+		if ch.Title != "" {
+			title += ": " + ch.Title
+		}
+		item := rssItem{
+			Title: title,
+			Link:  fmt.Sprintf("%s/chapters/%d", base, ch.ID),
+			GUID:  rssGUID{Value: fmt.Sprintf("mangahub:chapter:%d", ch.ID)},
+		}
+		if published := releaseTime(ch); published != nil {
+			item.PubDate = published.UTC().Format(time.RFC1123Z)
+			if channel.LastBuildDate == "" {
+				channel.LastBuildDate = item.PubDate
+			}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	body, err := xml.MarshalIndent(rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func releaseTime(ch pkgchapter.Chapter) *time.Time {
+	if ch.CreatedAt != nil {
+		return ch.CreatedAt
+	}
+	return ch.UpdatedAt
+}
@@ -0,0 +1,128 @@
+package chapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	repository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+)
+
+func TestReleaseFeed(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	// Chapter 3 was released last even though chapter 4 has a higher number
+	if _, err := db.Exec(`
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        content_text TEXT,
+        created_at DATETIME,
+        updated_at DATETIME
+    );
+    INSERT INTO chapters (id, manga_id, number, title, created_at) VALUES
+        (1, 1, 1, 'Romance Dawn', '2024-01-01 10:00:00'),
+        (2, 1, 2, '', '2024-01-08 10:00:00'),
+        (3, 1, 4, 'Skipped Ahead', '2024-01-15 10:00:00'),
+        (4, 1, 3, 'Late Translation', '2024-01-20 18:30:00'),
+        (5, 2, 1, 'Other manga', '2024-02-01 00:00:00');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	svc := NewService(repository.NewRepository(db))
+
+	body, err := svc.ReleaseFeed(context.Background(), FeedSource{
+		MangaID: 1,
+		Title:   "One Piece",
+		BaseURL: "https://mangahub.example/",
+	}, 0)
+	if err != nil {
+		t.Fatalf("ReleaseFeed failed: %v", err)
+	}
+
+	var feed struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			// Both the RSS link and the atom:link self reference
+			Links []struct {
+				XMLName xml.Name
+				Href    string `xml:"href,attr"`
+				Rel     string `xml:"rel,attr"`
+				Value   string `xml:",chardata"`
+			} `xml:"link"`
+			Items []struct {
+				Title   string `xml:"title"`
+				Link    string `xml:"link"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, body)
+	}
+
+	if feed.Version != "2.0" {
+		t.Fatalf("expected RSS 2.0, got %q", feed.Version)
+	}
+	ch := feed.Channel
+	if ch.Title != "One Piece" || ch.Description == "" || len(ch.Links) != 2 {
+		t.Fatalf("unexpected channel metadata: %+v", ch)
+	}
+	for _, link := range ch.Links {
+		switch link.XMLName.Space {
+		case "":
+			if link.Value != "https://mangahub.example/mangas/1" {
+				t.Fatalf("unexpected channel link %q", link.Value)
+			}
+		case "http://www.w3.org/2005/Atom":
+			if link.Href != "https://mangahub.example/mangas/1/feed.xml" || link.Rel != "self" {
+				t.Fatalf("unexpected self link: %+v", link)
+			}
+		default:
+			t.Fatalf("unexpected link namespace %q", link.XMLName.Space)
+		}
+	}
+
+	want := []struct {
+		title, link string
+		published   time.Time
+	}{
+		{"Chapter 3: Late Translation", "https://mangahub.example/chapters/4", time.Date(2024, 1, 20, 18, 30, 0, 0, time.UTC)},
+		{"Chapter 4: Skipped Ahead", "https://mangahub.example/chapters/3", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{"Chapter 2", "https://mangahub.example/chapters/2", time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC)},
+		{"Chapter 1: Romance Dawn", "https://mangahub.example/chapters/1", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	if len(ch.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(ch.Items))
+	}
+	for i, w := range want {
+		item := ch.Items[i]
+		if item.Title != w.title || item.Link != w.link || item.GUID == "" {
+			t.Fatalf("item %d: unexpected %+v", i, item)
+		}
+		published, err := time.Parse(time.RFC1123Z, item.PubDate)
+		if err != nil {
+			t.Fatalf("item %d: pubDate %q is not RFC 1123: %v", i, item.PubDate, err)
+		}
+		if !published.Equal(w.published) {
+			t.Fatalf("item %d: expected pubDate %v, got %v", i, w.published, published)
+		}
+	}
+	if ch.LastBuildDate != ch.Items[0].PubDate {
+		t.Fatalf("expected lastBuildDate to match the newest chapter, got %q", ch.LastBuildDate)
+	}
+}
@@ -0,0 +1,139 @@
+package chapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	repository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+	pkgchapter "github.com/ngocan-dev/mangahub/backend/pkg/models"
+)
+
+var (
+	ErrMangaNotFound        = errors.New("manga not found")
+	ErrChapterNotFound      = errors.New("chapter not found")
+	ErrChapterNumberTaken   = errors.New("chapter number already exists for this manga")
+	ErrInvalidChapterNumber = errors.New("chapter number must be positive")
+	ErrEmptyChapterContent  = errors.New("chapter content must not be empty")
+)
+
+// ReleaseNotifier tells subscribers about a newly published chapter
+type ReleaseNotifier interface {
+	NotifyChapterRelease(ctx context.Context, mangaID int64, mangaName string, chapter int, chapterID int64) error
+}
+
+// NewChapterRequest is the body of POST /admin/mangas/:id/chapters
+type NewChapterRequest struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	Language string `json:"language"`
+}
+
+// UpdateChapterRequest is the body of PUT /admin/chapters/:id. Omitted
+// fields keep their current value.
+type UpdateChapterRequest struct {
+	Number  *int    `json:"number"`
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+}
+
+// SetReleaseNotifier enables release notifications for published chapters
+func (s *Service) SetReleaseNotifier(n ReleaseNotifier) {
+	s.releaseNotifier = n
+}
+
+// PublishChapter adds a new chapter to a manga, raises the manga's last
+// chapter and notifies subscribers. A failed notification is logged; the
+// chapter stays published.
+func (s *Service) PublishChapter(ctx context.Context, mangaID int64, req NewChapterRequest) (*pkgchapter.Chapter, error) {
+	if req.Number < 1 {
+		return nil, ErrInvalidChapterNumber
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, ErrEmptyChapterContent
+	}
+
+	mangaTitle, ok, err := s.repo.GetMangaTitle(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMangaNotFound
+	}
+	if err := s.ensureNumberFree(ctx, mangaID, req.Number, 0); err != nil {
+		return nil, err
+	}
+
+	chapterID, err := s.repo.InsertChapter(ctx, mangaID, req.Number, strings.TrimSpace(req.Title), req.Content, req.Language)
+	if errors.Is(err, repository.ErrDuplicateChapter) {
+		return nil, ErrChapterNumberTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.releaseNotifier != nil {
+		if err := s.releaseNotifier.NotifyChapterRelease(ctx, mangaID, mangaTitle, req.Number, chapterID); err != nil {
+			log.Printf("chapter: release notification failed manga_id=%d chapter_id=%d: %v", mangaID, chapterID, err)
+		}
+	}
+
+	return s.repo.GetChapterByID(ctx, chapterID)
+}
+
+// UpdateChapter edits a chapter's number, title or content
+func (s *Service) UpdateChapter(ctx context.Context, chapterID int64, req UpdateChapterRequest) (*pkgchapter.Chapter, error) {
+	current, err := s.repo.GetChapterByID(ctx, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrChapterNotFound
+	}
+
+	number, title, content := current.Number, current.Title, current.ContentText
+	if req.Number != nil {
+		if *req.Number < 1 {
+			return nil, ErrInvalidChapterNumber
+		}
+		number = *req.Number
+	}
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if req.Content != nil {
+		if strings.TrimSpace(*req.Content) == "" {
+			return nil, ErrEmptyChapterContent
+		}
+		content = *req.Content
+	}
+	if number != current.Number {
+		if err := s.ensureNumberFree(ctx, current.MangaID, number, chapterID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.UpdateChapter(ctx, chapterID, current.MangaID, number, title, content)
+	if errors.Is(err, repository.ErrDuplicateChapter) {
+		return nil, ErrChapterNumberTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetChapterByID(ctx, chapterID)
+}
+
+// ensureNumberFree checks that no other chapter of the manga, in any
+// language, uses number
+func (s *Service) ensureNumberFree(ctx context.Context, mangaID int64, number int, chapterID int64) error {
+	existing, err := s.repo.ValidateChapter(ctx, mangaID, number)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != chapterID {
+		return ErrChapterNumberTaken
+	}
+	return nil
+}
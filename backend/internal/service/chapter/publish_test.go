@@ -0,0 +1,146 @@
+package chapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	repository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+)
+
+type recordedRelease struct {
+	mangaID   int64
+	mangaName string
+	chapter   int
+	chapterID int64
+}
+
+type fakeReleaseNotifier struct {
+	releases []recordedRelease
+}
+
+func (f *fakeReleaseNotifier) NotifyChapterRelease(_ context.Context, mangaID int64, mangaName string, chapter int, chapterID int64) error {
+	f.releases = append(f.releases, recordedRelease{mangaID, mangaName, chapter, chapterID})
+	return nil
+}
+
+// setupPublishService seeds manga 1 with chapters 1 and 2 and a deleted manga 2
+func setupPublishService(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY,
+        title TEXT NOT NULL,
+        last_chapter INTEGER,
+        last_chapter_at DATETIME,
+        deleted_at DATETIME
+    );
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        language TEXT DEFAULT 'ja',
+        content_text TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (manga_id, number, language)
+    );
+    INSERT INTO mangas (id, title, last_chapter, deleted_at) VALUES
+        (1, 'Hero Saga', 2, NULL),
+        (2, 'Retired', 1, CURRENT_TIMESTAMP);
+    INSERT INTO chapters (manga_id, number, title, content_text) VALUES
+        (1, 1, 'One', 'First.'),
+        (1, 2, 'Two', 'Second.');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(repository.NewRepository(db)), db
+}
+
+func TestPublishChapterNotifiesSubscribers(t *testing.T) {
+	svc, db := setupPublishService(t)
+	notifier := &fakeReleaseNotifier{}
+	svc.SetReleaseNotifier(notifier)
+	ctx := context.Background()
+
+	chapter, err := svc.PublishChapter(ctx, 1, NewChapterRequest{Number: 3, Title: " Three ", Content: "Third."})
+	if err != nil {
+		t.Fatalf("PublishChapter returned error: %v", err)
+	}
+	if chapter.Number != 3 || chapter.Title != "Three" || chapter.ContentText != "Third." {
+		t.Fatalf("unexpected chapter %+v", chapter)
+	}
+
+	if len(notifier.releases) != 1 {
+		t.Fatalf("expected one release notification, got %d", len(notifier.releases))
+	}
+	if got := notifier.releases[0]; got != (recordedRelease{1, "Hero Saga", 3, chapter.ID}) {
+		t.Fatalf("unexpected release notification %+v", got)
+	}
+
+	var lastChapter int
+	if err := db.QueryRow(`SELECT last_chapter FROM mangas WHERE id = 1`).Scan(&lastChapter); err != nil {
+		t.Fatalf("failed to read manga: %v", err)
+	}
+	if lastChapter != 3 {
+		t.Fatalf("expected last chapter 3, got %d", lastChapter)
+	}
+}
+
+func TestPublishChapterRejectsDuplicateNumber(t *testing.T) {
+	svc, _ := setupPublishService(t)
+	notifier := &fakeReleaseNotifier{}
+	svc.SetReleaseNotifier(notifier)
+	ctx := context.Background()
+
+	// Another language still counts as the same chapter number
+	_, err := svc.PublishChapter(ctx, 1, NewChapterRequest{Number: 2, Content: "Again.", Language: "en"})
+	if !errors.Is(err, ErrChapterNumberTaken) {
+		t.Fatalf("expected ErrChapterNumberTaken, got %v", err)
+	}
+	if len(notifier.releases) != 0 {
+		t.Fatalf("expected no notification for a rejected chapter, got %d", len(notifier.releases))
+	}
+
+	if _, err := svc.PublishChapter(ctx, 1, NewChapterRequest{Number: 3, Content: "  "}); !errors.Is(err, ErrEmptyChapterContent) {
+		t.Fatalf("expected ErrEmptyChapterContent, got %v", err)
+	}
+	if _, err := svc.PublishChapter(ctx, 2, NewChapterRequest{Number: 2, Content: "Text."}); !errors.Is(err, ErrMangaNotFound) {
+		t.Fatalf("expected ErrMangaNotFound for a deleted manga, got %v", err)
+	}
+}
+
+func TestUpdateChapter(t *testing.T) {
+	svc, _ := setupPublishService(t)
+	ctx := context.Background()
+
+	taken := 1
+	if _, err := svc.UpdateChapter(ctx, 2, UpdateChapterRequest{Number: &taken}); !errors.Is(err, ErrChapterNumberTaken) {
+		t.Fatalf("expected ErrChapterNumberTaken, got %v", err)
+	}
+
+	content := "Second, revised."
+	chapter, err := svc.UpdateChapter(ctx, 2, UpdateChapterRequest{Content: &content})
+	if err != nil {
+		t.Fatalf("UpdateChapter returned error: %v", err)
+	}
+	if chapter.Number != 2 || chapter.Title != "Two" || chapter.ContentText != content {
+		t.Fatalf("unexpected chapter %+v", chapter)
+	}
+
+	if _, err := svc.UpdateChapter(ctx, 99, UpdateChapterRequest{Content: &content}); !errors.Is(err, ErrChapterNotFound) {
+		t.Fatalf("expected ErrChapterNotFound, got %v", err)
+	}
+}
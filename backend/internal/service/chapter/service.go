@@ -9,7 +9,8 @@ import (
 
 // Service exposes higher-level chapter use cases.
 type Service struct {
-	repo *repository.Repository
+	repo            *repository.Repository
+	releaseNotifier ReleaseNotifier
 }
 
 // NewService constructs a chapter service.
@@ -32,6 +33,25 @@ func (s *Service) GetChapterByID(ctx context.Context, chapterID int64) (*pkgchap
 	return s.repo.GetChapterByID(ctx, chapterID)
 }
 
+// GetAdjacentChapters returns the previous and next chapters of the same
+// manga, ordered by chapter number. It returns nil when the chapter does not exist.
+func (s *Service) GetAdjacentChapters(ctx context.Context, chapterID int64) (*pkgchapter.ChapterNavigation, error) {
+	current, err := s.repo.GetChapterByID(ctx, chapterID)
+	if err != nil || current == nil {
+		return nil, err
+	}
+
+	prev, next, err := s.repo.GetAdjacentChapters(ctx, current.MangaID, current.Number)
+	if err != nil {
+		return nil, err
+	}
+	return &pkgchapter.ChapterNavigation{
+		Current:  current.ChapterSummary,
+		Previous: prev,
+		Next:     next,
+	}, nil
+}
+
 // ValidateChapter ensures a chapter exists and returns its summary when found.
 func (s *Service) ValidateChapter(ctx context.Context, mangaID int64, chapterNumber int) (*pkgchapter.ChapterSummary, error) {
 	return s.repo.ValidateChapter(ctx, mangaID, chapterNumber)
@@ -42,6 +62,16 @@ func (s *Service) GetChapterCount(ctx context.Context, mangaID int64) (int, erro
 	return s.repo.GetChapterCount(ctx, mangaID)
 }
 
+// GetChapterCounts returns the number of chapters of each of several manga.
+func (s *Service) GetChapterCounts(ctx context.Context, mangaIDs []int64) (map[int64]int, error) {
+	return s.repo.GetChapterCounts(ctx, mangaIDs)
+}
+
+// GetMaxChapterNumber returns the highest chapter number of a manga.
+func (s *Service) GetMaxChapterNumber(ctx context.Context, mangaID int64) (int, error) {
+	return s.repo.GetMaxChapterNumber(ctx, mangaID)
+}
+
 // CreateChapter persists a chapter row.
 func (s *Service) CreateChapter(ctx context.Context, mangaID int64, number int, title, contentText, language string) (int64, error) {
 	return s.repo.CreateChapter(ctx, mangaID, number, title, contentText, language)
@@ -0,0 +1,82 @@
+package chapter
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	repository "github.com/ngocan-dev/mangahub/backend/internal/repository/chapter"
+)
+
+func setupNavigationService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	// Manga 1 is missing chapter 3; manga 2 interleaves IDs with it
+	if _, err := db.Exec(`
+    CREATE TABLE chapters (
+        id INTEGER PRIMARY KEY,
+        manga_id INTEGER NOT NULL,
+        number INTEGER NOT NULL,
+        title TEXT,
+        content_text TEXT,
+        created_at DATETIME,
+        updated_at DATETIME
+    );
+    INSERT INTO chapters (id, manga_id, number, title) VALUES
+        (10, 1, 1, 'One'),
+        (11, 1, 2, 'Two'),
+        (12, 2, 3, 'Other manga'),
+        (13, 1, 4, 'Four'),
+        (14, 1, 5, 'Five');
+    `); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(repository.NewRepository(db))
+}
+
+func TestGetAdjacentChapters(t *testing.T) {
+	svc := setupNavigationService(t)
+	ctx := context.Background()
+
+	first, err := svc.GetAdjacentChapters(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetAdjacentChapters returned error: %v", err)
+	}
+	if first.Previous != nil {
+		t.Fatalf("expected no previous chapter for the first chapter, got %+v", first.Previous)
+	}
+	if first.Next == nil || first.Next.ID != 11 {
+		t.Fatalf("expected next chapter 11, got %+v", first.Next)
+	}
+
+	last, err := svc.GetAdjacentChapters(ctx, 14)
+	if err != nil {
+		t.Fatalf("GetAdjacentChapters returned error: %v", err)
+	}
+	if last.Next != nil {
+		t.Fatalf("expected no next chapter for the last chapter, got %+v", last.Next)
+	}
+
+	// Chapter 3 is missing, so 2 and 4 are neighbours; manga 2 is ignored
+	gap, err := svc.GetAdjacentChapters(ctx, 11)
+	if err != nil {
+		t.Fatalf("GetAdjacentChapters returned error: %v", err)
+	}
+	if gap.Previous == nil || gap.Previous.Number != 1 || gap.Next == nil || gap.Next.Number != 4 {
+		t.Fatalf("expected neighbours 1 and 4, got %+v / %+v", gap.Previous, gap.Next)
+	}
+
+	missing, err := svc.GetAdjacentChapters(ctx, 999)
+	if err != nil || missing != nil {
+		t.Fatalf("expected nil for a missing chapter, got %+v, %v", missing, err)
+	}
+}
@@ -0,0 +1,111 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+)
+
+const maxCollectionNameLength = 50
+
+var (
+	ErrInvalidCollectionName = fmt.Errorf("collection name must be between 1 and %d characters", maxCollectionNameLength)
+	ErrCollectionExists      = errors.New("collection with this name already exists")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrMangaNotInCollection  = errors.New("manga not in collection")
+)
+
+// CreateCollection adds a named shelf for the user. Names are unique per
+// user, ignoring case.
+func (s *Service) CreateCollection(ctx context.Context, userID int64, req domainlibrary.CreateCollectionRequest) (*domainlibrary.Collection, error) {
+	name := strings.TrimSpace(req.Name)
+	if length := utf8.RuneCountInString(name); length == 0 || length > maxCollectionNameLength {
+		return nil, ErrInvalidCollectionName
+	}
+
+	collection, duplicate, err := s.repo.CreateCollection(ctx, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if duplicate {
+		return nil, ErrCollectionExists
+	}
+	return collection, nil
+}
+
+// ListCollections returns the user's collections with their manga
+func (s *Service) ListCollections(ctx context.Context, userID int64) (*domainlibrary.ListCollectionsResponse, error) {
+	collections, err := s.repo.ListCollections(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return &domainlibrary.ListCollectionsResponse{Collections: collections}, nil
+}
+
+// AddToCollection puts a manga from the user's library into one of their
+// collections. A manga may belong to any number of collections.
+func (s *Service) AddToCollection(ctx context.Context, userID, collectionID, mangaID int64) (*domainlibrary.Collection, error) {
+	if err := s.ensureCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.CheckLibraryExists(ctx, userID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if !exists {
+		return nil, ErrMangaNotInLibrary
+	}
+
+	if err := s.repo.AddToCollection(ctx, collectionID, mangaID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.getCollection(ctx, userID, collectionID)
+}
+
+// RemoveFromCollection takes a manga out of one of the user's collections.
+// The library entry itself is kept.
+func (s *Service) RemoveFromCollection(ctx context.Context, userID, collectionID, mangaID int64) (*domainlibrary.Collection, error) {
+	if err := s.ensureCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RemoveFromCollection(ctx, collectionID, mangaID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMangaNotInCollection
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return s.getCollection(ctx, userID, collectionID)
+}
+
+// GetMangaCollections lists the user's collections containing the manga
+func (s *Service) GetMangaCollections(ctx context.Context, userID, mangaID int64) ([]domainlibrary.CollectionSummary, error) {
+	collections, err := s.repo.GetMangaCollections(ctx, userID, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return collections, nil
+}
+
+// ensureCollection checks the collection exists and belongs to the user
+func (s *Service) ensureCollection(ctx context.Context, userID, collectionID int64) error {
+	_, err := s.getCollection(ctx, userID, collectionID)
+	return err
+}
+
+func (s *Service) getCollection(ctx context.Context, userID, collectionID int64) (*domainlibrary.Collection, error) {
+	collection, err := s.repo.GetCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if collection == nil {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
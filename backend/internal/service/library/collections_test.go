@@ -0,0 +1,130 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	_ "modernc.org/sqlite"
+)
+
+func setupCollectionTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE user_library (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        current_chapter INTEGER NOT NULL DEFAULT 0,
+        PRIMARY KEY (user_id, manga_id)
+    );
+    CREATE TABLE Collections (
+        Collection_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+        User_Id INTEGER NOT NULL,
+        Name TEXT NOT NULL,
+        Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (User_Id, Name)
+    );
+    CREATE TABLE Collection_Items (
+        Collection_Id INTEGER NOT NULL,
+        Manga_Id INTEGER NOT NULL,
+        Added_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (Collection_Id, Manga_Id)
+    );
+    INSERT INTO user_library (user_id, manga_id, status) VALUES (1, 10, 'reading'), (1, 11, 'completed');
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(libraryrepository.NewRepository(db), nil, nil)
+}
+
+func TestCollectionsGroupLibraryManga(t *testing.T) {
+	svc := setupCollectionTestService(t)
+	ctx := context.Background()
+
+	favorites, err := svc.CreateCollection(ctx, 1, domainlibrary.CreateCollectionRequest{Name: "  Favorites 2024 "})
+	if err != nil {
+		t.Fatalf("CreateCollection returned error: %v", err)
+	}
+	if favorites.Name != "Favorites 2024" {
+		t.Fatalf("expected trimmed name, got %q", favorites.Name)
+	}
+	rereading, err := svc.CreateCollection(ctx, 1, domainlibrary.CreateCollectionRequest{Name: "Re-reading"})
+	if err != nil {
+		t.Fatalf("CreateCollection returned error: %v", err)
+	}
+
+	if _, err := svc.CreateCollection(ctx, 1, domainlibrary.CreateCollectionRequest{Name: "favorites 2024"}); !errors.Is(err, ErrCollectionExists) {
+		t.Fatalf("expected ErrCollectionExists, got %v", err)
+	}
+	if _, err := svc.CreateCollection(ctx, 2, domainlibrary.CreateCollectionRequest{Name: "Favorites 2024"}); err != nil {
+		t.Fatalf("another user should be able to reuse the name, got %v", err)
+	}
+	for _, name := range []string{"   ", strings.Repeat("x", maxCollectionNameLength+1)} {
+		if _, err := svc.CreateCollection(ctx, 1, domainlibrary.CreateCollectionRequest{Name: name}); !errors.Is(err, ErrInvalidCollectionName) {
+			t.Fatalf("expected ErrInvalidCollectionName for %q, got %v", name, err)
+		}
+	}
+
+	// The same manga can sit on several shelves
+	for _, id := range []int64{favorites.ID, rereading.ID} {
+		if _, err := svc.AddToCollection(ctx, 1, id, 10); err != nil {
+			t.Fatalf("AddToCollection returned error: %v", err)
+		}
+	}
+	if _, err := svc.AddToCollection(ctx, 1, favorites.ID, 10); err != nil {
+		t.Fatalf("adding twice should be a no-op, got %v", err)
+	}
+	if _, err := svc.AddToCollection(ctx, 1, favorites.ID, 99); !errors.Is(err, ErrMangaNotInLibrary) {
+		t.Fatalf("expected ErrMangaNotInLibrary, got %v", err)
+	}
+	if _, err := svc.AddToCollection(ctx, 2, favorites.ID, 10); !errors.Is(err, ErrCollectionNotFound) {
+		t.Fatalf("expected another user's collection to be hidden, got %v", err)
+	}
+
+	memberships, err := svc.GetMangaCollections(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("GetMangaCollections returned error: %v", err)
+	}
+	if len(memberships) != 2 {
+		t.Fatalf("expected manga in 2 collections, got %+v", memberships)
+	}
+
+	updated, err := svc.RemoveFromCollection(ctx, 1, rereading.ID, 10)
+	if err != nil {
+		t.Fatalf("RemoveFromCollection returned error: %v", err)
+	}
+	if len(updated.MangaIDs) != 0 {
+		t.Fatalf("expected re-reading shelf to be empty, got %v", updated.MangaIDs)
+	}
+	if _, err := svc.RemoveFromCollection(ctx, 1, rereading.ID, 10); !errors.Is(err, ErrMangaNotInCollection) {
+		t.Fatalf("expected ErrMangaNotInCollection, got %v", err)
+	}
+
+	list, err := svc.ListCollections(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListCollections returned error: %v", err)
+	}
+	if len(list.Collections) != 2 {
+		t.Fatalf("expected 2 collections, got %+v", list.Collections)
+	}
+	if got := list.Collections[0]; got.Name != "Favorites 2024" || len(got.MangaIDs) != 1 || got.MangaIDs[0] != 10 {
+		t.Fatalf("unexpected first collection %+v", got)
+	}
+	if got := list.Collections[1]; got.Name != "Re-reading" || len(got.MangaIDs) != 0 {
+		t.Fatalf("unexpected second collection %+v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package library
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	"github.com/ngocan-dev/mangahub/backend/internal/export"
+)
+
+// Supported library export formats
+const (
+	ExportFormatCSV  = export.FormatCSV
+	ExportFormatJSON = export.FormatJSON
+)
+
+var ErrInvalidExportFormat = export.ErrInvalidFormat
+
+var exportCSVHeader = []string{"manga_id", "title", "status", "current_chapter", "is_favorite", "added_at", "updated_at", "last_read_at"}
+
+// ExportLibrary writes the user's whole library to w as CSV or a JSON array.
+// Entries are written as they are read from the database; output is
+// buffered, so nothing reaches w when the query fails up front.
+func (s *Service) ExportLibrary(ctx context.Context, userID int64, format string, w io.Writer) error {
+	var err error
+	switch format {
+	case ExportFormatCSV:
+		err = s.exportCSV(ctx, userID, w)
+	case ExportFormatJSON:
+		err = s.exportJSON(ctx, userID, w)
+	default:
+		return ErrInvalidExportFormat
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return nil
+}
+
+func (s *Service) exportCSV(ctx context.Context, userID int64, w io.Writer) error {
+	// csv.Writer quotes titles containing commas, quotes or newlines
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.repo.StreamLibraryExport(ctx, userID, func(entry domainlibrary.ExportEntry) error {
+		return cw.Write([]string{
+			strconv.FormatInt(entry.MangaID, 10),
+			entry.Title,
+			entry.Status,
+			strconv.Itoa(entry.CurrentChapter),
+			strconv.FormatBool(entry.IsFavorite),
+			formatExportTime(entry.AddedAt),
+			formatExportTime(entry.UpdatedAt),
+			formatExportTime(entry.LastReadAt),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (s *Service) exportJSON(ctx context.Context, userID int64, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("["); err != nil {
+		return err
+	}
+
+	first := true
+	err := s.repo.StreamLibraryExport(ctx, userID, func(entry domainlibrary.ExportEntry) error {
+		if !first {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = bw.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := bw.WriteString("]\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
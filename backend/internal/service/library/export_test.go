@@ -0,0 +1,105 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	_ "modernc.org/sqlite"
+)
+
+func setupExportTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE mangas (id INTEGER PRIMARY KEY, title TEXT NOT NULL, deleted_at DATETIME);
+    CREATE TABLE chapters (id INTEGER PRIMARY KEY, manga_id INTEGER NOT NULL, number INTEGER NOT NULL);
+    CREATE TABLE user_library (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        current_chapter INTEGER NOT NULL DEFAULT 0,
+        created_at DATETIME,
+        updated_at DATETIME,
+        PRIMARY KEY (user_id, manga_id)
+    );
+    CREATE TABLE reading_progress (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        current_chapter_id INTEGER,
+        last_read_at DATETIME,
+        UNIQUE (user_id, manga_id)
+    );
+    CREATE TABLE favorites (user_id INTEGER NOT NULL, manga_id INTEGER NOT NULL, UNIQUE (user_id, manga_id));
+    INSERT INTO mangas (id, title) VALUES (1, 'Kaguya-sama: Love Is War'), (2, 'The "Apothecary" Diaries, Vol. 1');
+    INSERT INTO chapters (id, manga_id, number) VALUES (100, 2, 42);
+    INSERT INTO user_library (user_id, manga_id, status, current_chapter) VALUES (1, 1, 'completed', 281), (1, 2, 'reading', 40), (2, 1, 'reading', 3);
+    INSERT INTO reading_progress (user_id, manga_id, current_chapter_id) VALUES (1, 2, 100);
+    INSERT INTO favorites (user_id, manga_id) VALUES (1, 2);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(libraryrepository.NewRepository(db), nil, nil)
+}
+
+func TestExportLibraryCSVEscapesTitles(t *testing.T) {
+	svc := setupExportTestService(t)
+
+	var buf bytes.Buffer
+	if err := svc.ExportLibrary(context.Background(), 1, ExportFormatCSV, &buf); err != nil {
+		t.Fatalf("ExportLibrary returned error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+	if got := records[2][1]; got != `The "Apothecary" Diaries, Vol. 1` {
+		t.Fatalf("title did not round-trip, got %q", got)
+	}
+	// Progress chapter wins over the library column, and the favorite flag is set
+	if records[2][3] != "42" || records[2][4] != "true" {
+		t.Fatalf("unexpected row %v", records[2])
+	}
+	if records[1][3] != "281" || records[1][4] != "false" {
+		t.Fatalf("unexpected row %v", records[1])
+	}
+}
+
+func TestExportLibraryJSON(t *testing.T) {
+	svc := setupExportTestService(t)
+
+	var buf bytes.Buffer
+	if err := svc.ExportLibrary(context.Background(), 1, ExportFormatJSON, &buf); err != nil {
+		t.Fatalf("ExportLibrary returned error: %v", err)
+	}
+
+	var entries []domainlibrary.ExportEntry
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	if len(entries) != 2 || entries[0].MangaID != 1 || entries[1].MangaID != 2 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	if err := svc.ExportLibrary(context.Background(), 1, "xml", &buf); !errors.Is(err, ErrInvalidExportFormat) {
+		t.Fatalf("expected ErrInvalidExportFormat, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	internalmanga "github.com/ngocan-dev/mangahub/backend/internal/manga"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+)
+
+// MaxImportEntries caps how many rows a single import may contain
+const MaxImportEntries = 500
+
+var ErrImportTooLarge = fmt.Errorf("import is limited to %d entries", MaxImportEntries)
+
+// SetTitleMatcher enables importing entries by title instead of manga ID
+func (s *Service) SetTitleMatcher(m internalmanga.MatchTitle) {
+	s.titleMatcher = m
+}
+
+// ImportLibrary adds entries exported from another site or from
+// ExportLibrary. Rows are matched by manga ID when title_or_id is numeric,
+// otherwise by fuzzy title. Rows that cannot be matched, are invalid or are
+// already in the library are skipped and reported; the rest are written in
+// one transaction.
+func (s *Service) ImportLibrary(ctx context.Context, userID int64, entries []domainlibrary.ImportEntry) (*domainlibrary.ImportSummary, error) {
+	if len(entries) > MaxImportEntries {
+		return nil, ErrImportTooLarge
+	}
+
+	summary := &domainlibrary.ImportSummary{Errors: []domainlibrary.ImportError{}}
+	skip := func(row int, ref, reason string) {
+		summary.Skipped++
+		summary.Errors = append(summary.Errors, domainlibrary.ImportError{Row: row, TitleOrID: ref, Error: reason})
+	}
+
+	var (
+		rows    []libraryrepository.ImportRow
+		rowNums []int
+		refs    []string
+		seen    = make(map[int64]bool)
+	)
+	for i, entry := range entries {
+		rowNum := i + 1
+		ref := strings.TrimSpace(string(entry.TitleOrID))
+		if ref == "" {
+			skip(rowNum, ref, "title_or_id is required")
+			continue
+		}
+
+		status := strings.ToLower(strings.TrimSpace(entry.Status))
+		if status == "" {
+			status = "reading"
+		}
+		if !validStatuses[status] {
+			skip(rowNum, ref, ErrInvalidStatus.Error())
+			continue
+		}
+
+		mangaID, err := s.resolveImportRef(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if mangaID == 0 {
+			skip(rowNum, ref, ErrMangaNotFound.Error())
+			continue
+		}
+		if seen[mangaID] {
+			skip(rowNum, ref, "duplicate entry in import")
+			continue
+		}
+		seen[mangaID] = true
+
+		exists, err := s.repo.CheckLibraryExists(ctx, userID, mangaID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		if exists {
+			skip(rowNum, ref, "already in library")
+			continue
+		}
+
+		rows = append(rows, libraryrepository.ImportRow{
+			MangaID:        mangaID,
+			Status:         status,
+			CurrentChapter: max(entry.CurrentChapter, 0),
+			IsFavorite:     entry.IsFavorite,
+		})
+		rowNums = append(rowNums, rowNum)
+		refs = append(refs, ref)
+	}
+
+	if len(rows) == 0 {
+		return summary, nil
+	}
+
+	rowErrs, err := s.repo.ImportEntries(ctx, userID, rows)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	for i, rowErr := range rowErrs {
+		if rowErr != nil {
+			skip(rowNums[i], refs[i], "already in library")
+			continue
+		}
+		summary.Imported++
+	}
+	return summary, nil
+}
+
+// resolveImportRef returns the manga ID for an import row, or 0 when no
+// manga matches
+func (s *Service) resolveImportRef(ctx context.Context, ref string) (int64, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		if id <= 0 || s.mangaService == nil {
+			return 0, nil
+		}
+		manga, err := s.mangaService.GetByID(ctx, id)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		}
+		if manga == nil {
+			return 0, nil
+		}
+		return manga.ID, nil
+	}
+
+	if s.titleMatcher == nil {
+		return 0, nil
+	}
+	manga, err := s.titleMatcher.MatchTitle(ctx, ref)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if manga == nil {
+		return 0, nil
+	}
+	return manga.ID, nil
+}
@@ -0,0 +1,138 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	"github.com/ngocan-dev/mangahub/backend/domain/manga"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	_ "modernc.org/sqlite"
+)
+
+func setupImportTestService(t *testing.T) (*Service, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        slug TEXT NOT NULL UNIQUE,
+        title TEXT NOT NULL,
+        alt_title TEXT,
+        cover_url TEXT,
+        author TEXT,
+        artist TEXT,
+        status TEXT NOT NULL DEFAULT 'ongoing',
+        synopsis TEXT,
+        rating_average REAL NOT NULL DEFAULT 0,
+        rating_count INTEGER NOT NULL DEFAULT 0,
+        views INTEGER NOT NULL DEFAULT 0,
+        deleted_at DATETIME
+    );
+    CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE);
+    CREATE TABLE manga_tags (manga_id INTEGER NOT NULL, tag_id INTEGER NOT NULL);
+    CREATE TABLE chapters (id INTEGER PRIMARY KEY, manga_id INTEGER NOT NULL, number INTEGER NOT NULL);
+    CREATE TABLE user_library (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        current_chapter INTEGER NOT NULL DEFAULT 0,
+        created_at DATETIME,
+        updated_at DATETIME,
+        PRIMARY KEY (user_id, manga_id)
+    );
+    CREATE TABLE reading_progress (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        current_chapter_id INTEGER,
+        last_read_at DATETIME,
+        progress_percent REAL,
+        current_page INTEGER,
+        UNIQUE (user_id, manga_id)
+    );
+    CREATE TABLE favorites (user_id INTEGER NOT NULL, manga_id INTEGER NOT NULL, UNIQUE (user_id, manga_id));
+    INSERT INTO mangas (id, slug, title, alt_title) VALUES
+        (1, 'one-piece', 'One Piece', NULL),
+        (2, 'frieren', 'Frieren: Beyond Journey''s End', 'Sousou no Frieren'),
+        (3, 'vagabond', 'Vagabond', NULL);
+    INSERT INTO chapters (id, manga_id, number) VALUES (500, 2, 12);
+    INSERT INTO user_library (user_id, manga_id, status) VALUES (1, 3, 'completed');
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	mangaSvc := manga.NewService(db)
+	svc := NewService(libraryrepository.NewRepository(db), mangaSvc, nil)
+	svc.SetTitleMatcher(mangaSvc)
+	return svc, db
+}
+
+func TestImportLibraryMatchesAndReportsRows(t *testing.T) {
+	svc, db := setupImportTestService(t)
+
+	var entries []domainlibrary.ImportEntry
+	payload := `[
+        {"title_or_id": 1, "status": "completed", "current_chapter": 1100},
+        {"title_or_id": "sousou no frieren", "status": "reading", "current_chapter": 12, "is_favorite": true},
+        {"title_or_id": "Vagabond"},
+        {"title_or_id": "Some Unknown Manhwa"},
+        {"title_or_id": "one piece"},
+        {"title_or_id": 999},
+        {"title_or_id": "Vagabond", "status": "abandoned"}
+    ]`
+	if err := json.Unmarshal([]byte(payload), &entries); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+
+	summary, err := svc.ImportLibrary(context.Background(), 1, entries)
+	if err != nil {
+		t.Fatalf("ImportLibrary returned error: %v", err)
+	}
+	if summary.Imported != 2 || summary.Skipped != 5 {
+		t.Fatalf("expected 2 imported and 5 skipped, got %+v", summary)
+	}
+
+	wantRows := []int{3, 4, 5, 6, 7}
+	for i, e := range summary.Errors {
+		if e.Row != wantRows[i] {
+			t.Fatalf("expected skipped rows %v, got %+v", wantRows, summary.Errors)
+		}
+	}
+
+	var status string
+	var chapterID sql.NullInt64
+	if err := db.QueryRow(`
+        SELECT ul.status, rp.current_chapter_id FROM user_library ul
+        JOIN reading_progress rp ON rp.user_id = ul.user_id AND rp.manga_id = ul.manga_id
+        WHERE ul.user_id = 1 AND ul.manga_id = 2`).Scan(&status, &chapterID); err != nil {
+		t.Fatalf("expected imported entry with progress: %v", err)
+	}
+	if status != "reading" || chapterID.Int64 != 500 {
+		t.Fatalf("unexpected imported entry status=%s chapter_id=%v", status, chapterID)
+	}
+
+	var favorites int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM favorites WHERE user_id = 1 AND manga_id = 2`).Scan(&favorites); err != nil || favorites != 1 {
+		t.Fatalf("expected favorite flag to be imported, got %d, %v", favorites, err)
+	}
+}
+
+func TestImportLibraryRejectsOversizedImports(t *testing.T) {
+	svc, _ := setupImportTestService(t)
+
+	entries := make([]domainlibrary.ImportEntry, MaxImportEntries+1)
+	if _, err := svc.ImportLibrary(context.Background(), 1, entries); !errors.Is(err, ErrImportTooLarge) {
+		t.Fatalf("expected ErrImportTooLarge, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/ngocan-dev/mangahub/backend/domain/history"
 	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
@@ -17,8 +18,18 @@ var (
 	ErrInvalidStatus     = errors.New("invalid status")
 	ErrDatabaseError     = errors.New("database error")
 	ErrMangaNotInLibrary = errors.New("manga not in library")
+	ErrInvalidSort       = errors.New("sort must be one of: last_updated, title, rating")
 )
 
+// Library listing page sizes. Listings are only paged when a page or limit is given.
+const (
+	DefaultLibraryPageSize = 20
+	MaxLibraryPageSize     = 100
+)
+
+// ActionRemoved is the broadcast action sent when an entry leaves the library
+const ActionRemoved = "removed"
+
 var validStatuses = map[string]bool{
 	"plan_to_read": true,
 	"reading":      true,
@@ -34,11 +45,18 @@ type ProgressProvider interface {
 
 //go:generate mockgen -destination=./mocks/mock_progress_provider.go -package=library . ProgressProvider
 
+// Broadcaster notifies a user's other devices about library changes
+type Broadcaster interface {
+	BroadcastLibraryChange(ctx context.Context, userID, mangaID int64, action string) error
+}
+
 // Service coordinates library use cases
 type Service struct {
 	repo         *libraryrepository.Repository
 	mangaService internalmanga.GetByID
 	progressSvc  ProgressProvider
+	broadcaster  Broadcaster
+	titleMatcher internalmanga.MatchTitle
 }
 
 // NewService constructs library service
@@ -46,6 +64,11 @@ func NewService(repo *libraryrepository.Repository, mangaService internalmanga.G
 	return &Service{repo: repo, mangaService: mangaService, progressSvc: progressSvc}
 }
 
+// SetBroadcaster injects optional broadcaster for library change events
+func (s *Service) SetBroadcaster(b Broadcaster) {
+	s.broadcaster = b
+}
+
 // AddToLibrary inserts manga into user's library
 func (s *Service) AddToLibrary(ctx context.Context, userID, mangaID int64, req domainlibrary.AddToLibraryRequest) (*domainlibrary.AddToLibraryResponse, error) {
 	status := req.Status
@@ -106,7 +129,8 @@ func (s *Service) AddToLibrary(ctx context.Context, userID, mangaID int64, req d
 	}, nil
 }
 
-// RemoveFromLibrary deletes a manga from user's library
+// RemoveFromLibrary deletes a manga and its reading progress from user's library
+// and notifies the user's other devices
 func (s *Service) RemoveFromLibrary(ctx context.Context, userID, mangaID int64) error {
 	exists, err := s.repo.CheckLibraryExists(ctx, userID, mangaID)
 	if err != nil {
@@ -117,18 +141,74 @@ func (s *Service) RemoveFromLibrary(ctx context.Context, userID, mangaID int64)
 	}
 
 	if err := s.repo.RemoveFromLibrary(ctx, userID, mangaID); err != nil {
+		// Another request removed the entry between the check and the delete
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMangaNotInLibrary
+		}
 		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
+
+	if s.broadcaster != nil {
+		if err := s.broadcaster.BroadcastLibraryChange(ctx, userID, mangaID, ActionRemoved); err != nil {
+			log.Printf("library: failed to broadcast removal user_id=%d manga_id=%d: %v", userID, mangaID, err)
+		}
+	}
 	return nil
 }
 
-// GetLibrary returns the user's library entries
-func (s *Service) GetLibrary(ctx context.Context, userID int64) (*domainlibrary.GetLibraryResponse, error) {
-	entries, err := s.repo.GetLibrary(ctx, userID)
+// GetLibrary returns the user's library entries filtered to query.Status and
+// ordered by query.Sort (most recently updated first by default), with entry
+// counts for every status
+func (s *Service) GetLibrary(ctx context.Context, userID int64, query domainlibrary.LibraryQuery) (*domainlibrary.GetLibraryResponse, error) {
+	if query.Status != "" && !validStatuses[query.Status] {
+		return nil, ErrInvalidStatus
+	}
+	if query.Sort == "" {
+		query.Sort = domainlibrary.SortLastUpdated
+	}
+	if query.Sort != domainlibrary.SortLastUpdated && query.Sort != domainlibrary.SortTitle && query.Sort != domainlibrary.SortRating {
+		return nil, ErrInvalidSort
+	}
+
+	offset := 0
+	if query.Page > 0 || query.Limit > 0 {
+		if query.Page < 1 {
+			query.Page = 1
+		}
+		if query.Limit < 1 {
+			query.Limit = DefaultLibraryPageSize
+		}
+		if query.Limit > MaxLibraryPageSize {
+			query.Limit = MaxLibraryPageSize
+		}
+		offset = (query.Page - 1) * query.Limit
+	}
+
+	entries, total, err := s.repo.GetLibrary(ctx, userID, query.Status, query.Sort, query.Limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
-	return &domainlibrary.GetLibraryResponse{Entries: entries}, nil
+	counts, err := s.repo.CountLibraryByStatus(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	// Every status gets a badge, even when empty
+	for status := range validStatuses {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+	if entries == nil {
+		entries = []domainlibrary.LibraryEntry{}
+	}
+
+	return &domainlibrary.GetLibraryResponse{
+		Entries:      entries,
+		Total:        total,
+		Page:         query.Page,
+		Limit:        query.Limit,
+		StatusCounts: counts,
+	}, nil
 }
 
 // UpdateLibraryStatus updates a manga's status for the user
@@ -164,6 +244,15 @@ func (s *Service) CheckLibraryExists(ctx context.Context, userID, mangaID int64)
 	return exists, nil
 }
 
+// GetLibraryStatuses returns the user's library status for several manga, keyed by manga ID
+func (s *Service) GetLibraryStatuses(ctx context.Context, userID int64, mangaIDs []int64) (map[int64]*domainlibrary.LibraryStatus, error) {
+	statuses, err := s.repo.GetLibraryStatuses(ctx, userID, mangaIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return statuses, nil
+}
+
 // GetLibraryStatus exposes repository lookup for composition with other domains
 func (s *Service) GetLibraryStatus(ctx context.Context, userID, mangaID int64) (*domainlibrary.LibraryStatus, error) {
 	status, err := s.repo.GetLibraryStatus(ctx, userID, mangaID)
@@ -0,0 +1,184 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	domainlibrary "github.com/ngocan-dev/mangahub/backend/domain/library"
+	libraryrepository "github.com/ngocan-dev/mangahub/backend/internal/repository/library"
+	_ "modernc.org/sqlite"
+)
+
+type recordingBroadcaster struct {
+	actions []string
+}
+
+func (b *recordingBroadcaster) BroadcastLibraryChange(ctx context.Context, userID, mangaID int64, action string) error {
+	b.actions = append(b.actions, action)
+	return nil
+}
+
+func TestRemoveFromLibraryDeletesEntryAndProgress(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	schema := `
+    CREATE TABLE user_library (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        current_chapter INTEGER NOT NULL DEFAULT 0,
+        PRIMARY KEY (user_id, manga_id)
+    );
+    CREATE TABLE reading_progress (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        UNIQUE (user_id, manga_id)
+    );
+    INSERT INTO user_library (user_id, manga_id, status) VALUES (1, 10, 'reading');
+    INSERT INTO reading_progress (user_id, manga_id) VALUES (1, 10);
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	broadcaster := &recordingBroadcaster{}
+	svc := NewService(libraryrepository.NewRepository(db), nil, nil)
+	svc.SetBroadcaster(broadcaster)
+	ctx := context.Background()
+
+	if err := svc.RemoveFromLibrary(ctx, 1, 10); err != nil {
+		t.Fatalf("RemoveFromLibrary returned error: %v", err)
+	}
+
+	var remaining int
+	if err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM user_library) + (SELECT COUNT(*) FROM reading_progress)`).Scan(&remaining); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("expected library entry and progress to be deleted, %d rows remain", remaining)
+	}
+	if len(broadcaster.actions) != 1 || broadcaster.actions[0] != ActionRemoved {
+		t.Fatalf("expected one %q broadcast, got %v", ActionRemoved, broadcaster.actions)
+	}
+
+	if err := svc.RemoveFromLibrary(ctx, 1, 10); !errors.Is(err, ErrMangaNotInLibrary) {
+		t.Fatalf("expected ErrMangaNotInLibrary, got %v", err)
+	}
+}
+
+func setupListingTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+    CREATE TABLE mangas (
+        id INTEGER PRIMARY KEY,
+        title TEXT NOT NULL,
+        cover_url TEXT,
+        rating_average REAL NOT NULL DEFAULT 0,
+        deleted_at DATETIME
+    );
+    CREATE TABLE user_library (
+        user_id INTEGER NOT NULL,
+        manga_id INTEGER NOT NULL,
+        status TEXT NOT NULL,
+        current_chapter INTEGER NOT NULL DEFAULT 0,
+        created_at DATETIME,
+        updated_at DATETIME,
+        PRIMARY KEY (user_id, manga_id)
+    );
+    INSERT INTO mangas (id, title, rating_average, deleted_at) VALUES
+        (1, 'berserk', 4.9, NULL),
+        (2, 'Akira', 4.5, NULL),
+        (3, 'Chainsaw Man', 4.7, NULL),
+        (4, 'Dorohedoro', 4.2, NULL),
+        (5, 'Erased', 4.8, CURRENT_TIMESTAMP);
+    INSERT INTO user_library (user_id, manga_id, status, updated_at) VALUES
+        (1, 1, 'reading', '2024-01-03 00:00:00'),
+        (1, 2, 'completed', '2024-01-01 00:00:00'),
+        (1, 3, 'reading', '2024-01-04 00:00:00'),
+        (1, 4, 'reading', '2024-01-02 00:00:00'),
+        (1, 5, 'reading', '2024-01-05 00:00:00'),
+        (2, 2, 'reading', '2024-01-06 00:00:00');
+    `
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return NewService(libraryrepository.NewRepository(db), nil, nil)
+}
+
+func libraryIDs(entries []domainlibrary.LibraryEntry) []int64 {
+	ids := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.MangaID)
+	}
+	return ids
+}
+
+func TestGetLibraryFiltersByStatus(t *testing.T) {
+	svc := setupListingTestService(t)
+	ctx := context.Background()
+
+	resp, err := svc.GetLibrary(ctx, 1, domainlibrary.LibraryQuery{Status: "reading"})
+	if err != nil {
+		t.Fatalf("GetLibrary returned error: %v", err)
+	}
+	// The soft-deleted manga 5 stays hidden
+	if got := libraryIDs(resp.Entries); !reflect.DeepEqual(got, []int64{3, 1, 4}) || resp.Total != 3 {
+		t.Fatalf("expected reading entries 3, 1, 4, got %v (total %d)", got, resp.Total)
+	}
+	want := map[string]int{"reading": 3, "completed": 1, "plan_to_read": 0, "on_hold": 0, "dropped": 0}
+	if !reflect.DeepEqual(resp.StatusCounts, want) {
+		t.Fatalf("unexpected status counts %v", resp.StatusCounts)
+	}
+
+	page, err := svc.GetLibrary(ctx, 1, domainlibrary.LibraryQuery{Status: "reading", Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("GetLibrary returned error: %v", err)
+	}
+	if got := libraryIDs(page.Entries); !reflect.DeepEqual(got, []int64{4}) || page.Total != 3 {
+		t.Fatalf("expected the second page to hold entry 4, got %v (total %d)", got, page.Total)
+	}
+
+	if _, err := svc.GetLibrary(ctx, 1, domainlibrary.LibraryQuery{Status: "abandoned"}); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestGetLibrarySortOrders(t *testing.T) {
+	svc := setupListingTestService(t)
+
+	cases := map[string][]int64{
+		"":                            {3, 1, 4, 2},
+		domainlibrary.SortLastUpdated: {3, 1, 4, 2},
+		domainlibrary.SortTitle:       {2, 1, 3, 4},
+		domainlibrary.SortRating:      {1, 3, 2, 4},
+	}
+	for sort, want := range cases {
+		resp, err := svc.GetLibrary(context.Background(), 1, domainlibrary.LibraryQuery{Sort: sort})
+		if err != nil {
+			t.Fatalf("sort %q: GetLibrary returned error: %v", sort, err)
+		}
+		if got := libraryIDs(resp.Entries); !reflect.DeepEqual(got, want) {
+			t.Fatalf("sort %q: expected %v, got %v", sort, want, got)
+		}
+	}
+
+	if _, err := svc.GetLibrary(context.Background(), 1, domainlibrary.LibraryQuery{Sort: "popularity"}); !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort, got %v", err)
+	}
+}
@@ -30,6 +30,9 @@ func (b *ServerBroadcaster) BroadcastProgress(ctx context.Context, userID, novel
 			broadcastErr = err
 		}
 	} else {
+		if b.server != nil {
+			b.server.PublishProgress(userID, novelID, chapter, chapterID)
+		}
 		broadcastErr = errors.New("tcp server not configured or not running")
 	}
 
@@ -48,3 +51,12 @@ func (b *ServerBroadcaster) BroadcastProgress(ctx context.Context, userID, novel
 
 	return broadcastErr
 }
+
+// BroadcastLibraryChange notifies the user's connected devices about a library change.
+// Library events are best-effort and are not queued for retry.
+func (b *ServerBroadcaster) BroadcastLibraryChange(ctx context.Context, userID, mangaID int64, action string) error {
+	if b.server == nil || !b.server.IsRunning() {
+		return errors.New("tcp server not configured or not running")
+	}
+	return b.server.BroadcastLibraryChange(ctx, userID, mangaID, action)
+}
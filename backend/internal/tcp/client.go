@@ -3,6 +3,7 @@ package tcp
 import (
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,17 +18,31 @@ type Client struct {
 	LastSeen      time.Time
 	mu            sync.RWMutex
 	authenticated bool
+	sessionID     int64
+
+	// Per-operation deadlines as nanoseconds; the server sets them from its
+	// Deadlines. Atomic so a blocked write does not hold up reads.
+	readTimeout  atomic.Int64
+	writeTimeout atomic.Int64
 }
 
 // NewClient creates a new client instance
 func NewClient(conn net.Conn) *Client {
 	now := time.Now()
-	return &Client{
+	c := &Client{
 		Conn:          conn,
 		ConnectedAt:   now,
 		LastSeen:      now,
 		authenticated: false,
 	}
+	c.setTimeouts(30*time.Second, 5*time.Second)
+	return c
+}
+
+// setTimeouts changes the deadline of each following read and write
+func (c *Client) setTimeouts(read, write time.Duration) {
+	c.readTimeout.Store(int64(read))
+	c.writeTimeout.Store(int64(write))
 }
 
 // IsAuthenticated returns whether the client is authenticated
@@ -49,6 +64,20 @@ func (c *Client) SetAuthenticated(userID int64, username, deviceName, deviceType
 	c.LastSeen = time.Now()
 }
 
+// SetSessionID stores the Sync_Sessions row recorded for this connection
+func (c *Client) SetSessionID(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionID = id
+}
+
+// SessionID returns the Sync_Sessions row for this connection, or 0 if none was recorded
+func (c *Client) SessionID() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionID
+}
+
 // UpdateLastSeen updates the last seen timestamp
 func (c *Client) UpdateLastSeen() {
 	c.mu.Lock()
@@ -71,7 +100,7 @@ func (c *Client) SendMessage(msg *Message) error {
 	data = append(data, '\n')
 
 	// Set write deadline
-	c.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	c.Conn.SetWriteDeadline(time.Now().Add(time.Duration(c.writeTimeout.Load())))
 	_, err = c.Conn.Write(data)
 	if err != nil {
 		// A1: Connection lost - return error so server can remove client
@@ -105,7 +134,7 @@ func (c *Client) Close() error {
 // ReadMessage reads a message from the client connection
 func (c *Client) ReadMessage() (*Message, error) {
 	// Set read deadline
-	c.Conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	c.Conn.SetReadDeadline(time.Now().Add(time.Duration(c.readTimeout.Load())))
 
 	// Read until newline
 	var buffer []byte
@@ -0,0 +1,125 @@
+package tcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrDeviceNotFound is returned when a sync session does not exist or belongs to another user
+var ErrDeviceNotFound = errors.New("device not found")
+
+// Device is an active sync session of a user
+type Device struct {
+	ID         int64     `json:"id"`
+	DeviceName string    `json:"device_name"`
+	DeviceType string    `json:"device_type"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	LastIP     string    `json:"last_ip"`
+	Connected  bool      `json:"connected"`
+}
+
+// ListDevices returns the user's active sync sessions, most recently seen first.
+// Sessions with a live connection report that connection's last activity.
+func (s *Server) ListDevices(ctx context.Context, userID int64) ([]Device, error) {
+	if s.db == nil {
+		return []Device{}, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT Id, COALESCE(Device_Name, ''), COALESCE(Device_Type, ''), Started_At, Last_Seen_At, COALESCE(Last_Ip, '')
+		FROM Sync_Sessions
+		WHERE User_Id = ? AND Status = 'active'
+		ORDER BY Last_Seen_At DESC, Id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	live := make(map[int64]*Client)
+	for _, client := range s.userClients(userID) {
+		if id := client.SessionID(); id != 0 {
+			live[id] = client
+		}
+	}
+
+	devices := []Device{}
+	for rows.Next() {
+		var (
+			device   Device
+			lastSeen sql.NullTime
+		)
+		if err := rows.Scan(&device.ID, &device.DeviceName, &device.DeviceType, &device.StartedAt, &lastSeen, &device.LastIP); err != nil {
+			return nil, err
+		}
+		device.LastSeenAt = lastSeen.Time
+		if client, ok := live[device.ID]; ok {
+			device.Connected = true
+			client.mu.RLock()
+			device.LastSeenAt = client.LastSeen
+			client.mu.RUnlock()
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
+// RevokeDevice closes the user's sync session and disconnects its TCP client if
+// it is still connected. It returns ErrDeviceNotFound if the session is not an
+// active session of the user.
+func (s *Server) RevokeDevice(ctx context.Context, userID, sessionID int64) error {
+	if s.db == nil {
+		return ErrDeviceNotFound
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE Sync_Sessions SET Status = 'closed', Last_Seen_At = ?
+		WHERE Id = ? AND User_Id = ? AND Status = 'active'
+	`, time.Now(), sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrDeviceNotFound
+	}
+
+	for _, client := range s.userClients(userID) {
+		if client.SessionID() != sessionID {
+			continue
+		}
+		if err := client.SendError("device_revoked", "this device has been signed out"); err != nil {
+			log.Printf("Error notifying revoked device (UserID=%d, Session=%d): %v", userID, sessionID, err)
+		}
+		s.removeClient(client)
+		client.Close()
+	}
+	return nil
+}
+
+// DisconnectUser signs out every connected device of the user, e.g. after
+// the account was deleted
+func (s *Server) DisconnectUser(userID int64, reason string) {
+	for _, client := range s.userClients(userID) {
+		if err := client.SendError("signed_out", reason); err != nil {
+			log.Printf("Error notifying disconnected client (UserID=%d): %v", userID, err)
+		}
+		s.removeClient(client)
+		client.Close()
+	}
+}
+
+// userClients returns a snapshot of the user's connected clients
+func (s *Server) userClients(userID int64) []*Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clients := make([]*Client, len(s.clientsByUser[userID]))
+	copy(clients, s.clientsByUser[userID])
+	return clients
+}
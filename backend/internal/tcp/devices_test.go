@@ -0,0 +1,79 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListDevicesReturnsOnlyUsersActiveSessions(t *testing.T) {
+	db := newSyncSessionsDB(t)
+	s := NewServer("127.0.0.1:0", 10, db)
+	connectTestClient(t, s, 7, "laptop")
+	connectTestClient(t, s, 8, "phone")
+
+	if _, err := db.Exec(`INSERT INTO Sync_Sessions (User_Id, Device_Name, Status) VALUES (7, 'old tablet', 'closed')`); err != nil {
+		t.Fatalf("failed to insert closed session: %v", err)
+	}
+
+	devices, err := s.ListDevices(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ListDevices returned error: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("expected 1 active device, got %d", len(devices))
+	}
+	device := devices[0]
+	if device.DeviceName != "laptop" || device.DeviceType != "desktop" || !device.Connected || device.LastIP == "" {
+		t.Fatalf("unexpected device: %+v", device)
+	}
+}
+
+func TestRevokeDeviceDisconnectsClient(t *testing.T) {
+	db := newSyncSessionsDB(t)
+	s := NewServer("127.0.0.1:0", 10, db)
+	peer := connectTestClient(t, s, 7, "laptop")
+
+	devices, err := s.ListDevices(context.Background(), 7)
+	if err != nil || len(devices) != 1 {
+		t.Fatalf("expected one device, got %v (err=%v)", devices, err)
+	}
+	deviceID := devices[0].ID
+
+	if err := s.RevokeDevice(context.Background(), 8, deviceID); !errors.Is(err, ErrDeviceNotFound) {
+		t.Fatalf("expected ErrDeviceNotFound for another user's device, got %v", err)
+	}
+
+	revoked := make(chan error, 1)
+	go func() { revoked <- s.RevokeDevice(context.Background(), 7, deviceID) }()
+
+	msg, err := peer.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read revoke notice: %v", err)
+	}
+	payload, _ := msg.Payload.(map[string]interface{})
+	if msg.Type != MessageTypeError || payload["code"] != "device_revoked" {
+		t.Fatalf("expected device_revoked error, got %s %v", msg.Type, payload)
+	}
+	if err := <-revoked; err != nil {
+		t.Fatalf("RevokeDevice returned error: %v", err)
+	}
+
+	if _, err := peer.ReadMessage(); err == nil {
+		t.Fatalf("expected connection to be closed after revoke")
+	}
+	if s.GetClientCount() != 0 {
+		t.Fatalf("expected revoked client to be removed, got %d clients", s.GetClientCount())
+	}
+
+	var status string
+	if err := db.QueryRow(`SELECT Status FROM Sync_Sessions WHERE Id = ?`, deviceID).Scan(&status); err != nil {
+		t.Fatalf("failed to load sync session: %v", err)
+	}
+	if status != "closed" {
+		t.Fatalf("expected session to be closed, got %q", status)
+	}
+	if err := s.RevokeDevice(context.Background(), 7, deviceID); !errors.Is(err, ErrDeviceNotFound) {
+		t.Fatalf("expected ErrDeviceNotFound for a closed session, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 var (
 	ErrInvalidMessage = errors.New("invalid message format")
 	ErrUnauthorized   = errors.New("authentication failed")
+	ErrServerClosed   = errors.New("tcp: server closed")
 )
 
 // MessageType represents the type of TCP message
@@ -17,8 +18,10 @@ const (
 	MessageTypeAuth      MessageType = "auth"
 	MessageTypeAuthResp  MessageType = "auth_response"
 	MessageTypeProgress  MessageType = "progress"
+	MessageTypeLibrary   MessageType = "library"
 	MessageTypeError     MessageType = "error"
 	MessageTypeHeartbeat MessageType = "heartbeat"
+	MessageTypeShutdown  MessageType = "shutdown"
 )
 
 // Message represents a TCP protocol message
@@ -50,6 +53,12 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// ShutdownNotice tells clients the server is going away and they should reconnect later
+type ShutdownNotice struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
 // ProgressUpdate represents a progress update broadcast
 type ProgressUpdate struct {
 	UserID    int64  `json:"user_id"`
@@ -59,6 +68,14 @@ type ProgressUpdate struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// LibraryUpdate represents a library change broadcast
+type LibraryUpdate struct {
+	UserID    int64  `json:"user_id"`
+	MangaID   int64  `json:"manga_id"`
+	Action    string `json:"action"` // e.g. "removed"
+	Timestamp string `json:"timestamp"`
+}
+
 // ParseMessage parses a JSON message from bytes
 func ParseMessage(data []byte) (*Message, error) {
 	var msg Message
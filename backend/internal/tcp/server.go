@@ -21,10 +21,20 @@ type Server struct {
 	maxClients    int
 	db            *sql.DB
 	clients       map[*Client]bool
-	clientsByUser map[int64][]*Client // Multiple devices per user
+	clientsByUser map[int64][]*Client // Multiple devices per user, oldest first
+	maxPerUser    int                 // Per-user connection cap; 0 means unlimited
+	deadlines     Deadlines
 	mu            sync.RWMutex
-	broadcastCh   chan ProgressUpdate
+	broadcastCh   chan userBroadcast
 	running       atomic.Bool
+
+	// Graceful shutdown state
+	listener     net.Listener
+	shuttingDown atomic.Bool
+	sessionWG    sync.WaitGroup
+
+	subMu       sync.Mutex
+	subscribers map[int64]map[chan ProgressUpdate]struct{}
 }
 
 // Stats describes the current runtime state of the TCP server.
@@ -34,6 +44,34 @@ type Stats struct {
 	MaxClients int
 }
 
+// DefaultMaxConnectionsPerUser is how many devices a user may keep connected
+// at once unless SetMaxConnectionsPerUser says otherwise
+const DefaultMaxConnectionsPerUser = 5
+
+// Deadlines bounds how long the server waits on a client connection
+type Deadlines struct {
+	// Auth is how long a new connection may take to authenticate
+	Auth time.Duration
+	// Read is how long one read may block once authenticated; a silent
+	// client is sent a heartbeat rather than dropped
+	Read time.Duration
+	// Write is how long sending one message may take before the client is
+	// treated as gone
+	Write time.Duration
+}
+
+// MinDeadline is the shortest deadline SetDeadlines accepts
+const MinDeadline = time.Second
+
+// DefaultDeadlines returns the deadlines used unless SetDeadlines says otherwise
+func DefaultDeadlines() Deadlines {
+	return Deadlines{
+		Auth:  30 * time.Second,
+		Read:  30 * time.Second,
+		Write: 10 * time.Second,
+	}
+}
+
 // NewServer creates a new TCP server instance
 // TCP and WebSocket connections remain stable
 func NewServer(address string, maxClients int, db *sql.DB) *Server {
@@ -43,7 +81,10 @@ func NewServer(address string, maxClients int, db *sql.DB) *Server {
 		db:            db,
 		clients:       make(map[*Client]bool),
 		clientsByUser: make(map[int64][]*Client),
-		broadcastCh:   make(chan ProgressUpdate, 1000), // Increased buffer for 50-100 concurrent users
+		maxPerUser:    DefaultMaxConnectionsPerUser,
+		deadlines:     DefaultDeadlines(),
+		broadcastCh:   make(chan userBroadcast, 1000), // Increased buffer for 50-100 concurrent users
+		subscribers:   make(map[int64]map[chan ProgressUpdate]struct{}),
 	}
 }
 
@@ -64,11 +105,26 @@ func (s *Server) Start(ctx context.Context) (err error) {
 	}
 	defer listener.Close()
 
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
 	log.Printf("TCP server listening on %s", s.address)
 
 	// Start broadcast handler
 	go s.handleBroadcasts(ctx)
 
+	// Unblock Accept once the context is cancelled
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			listener.Close()
+		case <-stopped:
+		}
+	}()
+
 	// Accept connections
 	for {
 		select {
@@ -77,14 +133,21 @@ func (s *Server) Start(ctx context.Context) (err error) {
 		default:
 			conn, err := listener.Accept()
 			if err != nil {
+				if s.shuttingDown.Load() {
+					return ErrServerClosed
+				}
+				if errors.Is(err, net.ErrClosed) {
+					return ctx.Err()
+				}
 				log.Printf("Error accepting connection: %v", err)
 				continue
 			}
 
 			// Set connection timeouts for stability
 			// TCP and WebSocket connections remain stable
-			conn.SetReadDeadline(time.Now().Add(30 * time.Second))
-			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			deadlines := s.Deadlines()
+			conn.SetReadDeadline(time.Now().Add(deadlines.Auth))
+			conn.SetWriteDeadline(time.Now().Add(deadlines.Write))
 
 			// A2: Check server capacity
 			s.mu.RLock()
@@ -110,12 +173,19 @@ func (s *Server) handleClient(ctx context.Context, client *Client) {
 	defer func() {
 		s.removeClient(client)
 		client.Close()
+		if err := s.closeSyncSessions(context.Background(), []*Client{client}); err != nil {
+			log.Printf("Error closing sync session: %v", err)
+		}
 	}()
 
 	log.Printf("New client connected from %s", client.Conn.RemoteAddr())
 
+	// Until it authenticates, each read may take the whole auth deadline
+	deadlines := s.Deadlines()
+	client.setTimeouts(deadlines.Auth, deadlines.Write)
+
 	// Step 3: Wait for authentication message
-	authTimeout := time.NewTimer(30 * time.Second)
+	authTimeout := time.NewTimer(deadlines.Auth)
 	defer authTimeout.Stop()
 
 	authChan := make(chan bool, 1)
@@ -170,6 +240,7 @@ func (s *Server) handleClient(ctx context.Context, client *Client) {
 
 	// Step 5: Client is authenticated, handle messages
 	log.Printf("Client authenticated: UserID=%d, Username=%s", client.UserID, client.Username)
+	client.setTimeouts(deadlines.Read, deadlines.Write)
 
 	// Heartbeat ticker
 	heartbeatTicker := time.NewTicker(30 * time.Second)
@@ -190,9 +261,7 @@ func (s *Server) handleClient(ctx context.Context, client *Client) {
 				return
 			}
 		default:
-			// Set read timeout
-			client.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-
+			// ReadMessage blocks for at most the read deadline
 			msg, err := client.ReadMessage()
 			if err != nil {
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
@@ -240,6 +309,9 @@ func (s *Server) handleAuthentication(client *Client, msg *Message) bool {
 		if errors.Is(err, auth.ErrExpiredToken) {
 			// Expired tokens trigger reauthentication
 			client.SendError("token_expired", "your session has expired. please login again")
+		} else if errors.Is(err, auth.ErrTokenRevoked) {
+			// Revoked tokens (logout) are rejected
+			client.SendError("token_revoked", "this token has been revoked. please login again")
 		} else if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrInvalidSigningMethod) {
 			// Invalid tokens are rejected
 			client.SendError("auth_failed", "invalid token")
@@ -278,18 +350,80 @@ func (s *Server) handleAuthentication(client *Client, msg *Message) bool {
 	}
 
 	// Record sync session in database
-	go s.recordSyncSession(client)
+	s.sessionWG.Add(1)
+	go func() {
+		defer s.sessionWG.Done()
+		s.recordSyncSession(client)
+	}()
 
 	return true
 }
 
-// addClient adds a client to the active list
-func (s *Server) addClient(client *Client) {
+// SetDeadlines changes the deadlines of connections accepted from now on.
+// Zero fields keep their current value and anything shorter than
+// MinDeadline is raised to it.
+func (s *Server) SetDeadlines(d Deadlines) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if d.Auth != 0 {
+		s.deadlines.Auth = max(d.Auth, MinDeadline)
+	}
+	if d.Read != 0 {
+		s.deadlines.Read = max(d.Read, MinDeadline)
+	}
+	if d.Write != 0 {
+		s.deadlines.Write = max(d.Write, MinDeadline)
+	}
+}
+
+// Deadlines returns the deadlines applied to new connections
+func (s *Server) Deadlines() Deadlines {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deadlines
+}
+
+// SetMaxConnectionsPerUser caps how many connections one user may hold;
+// 0 removes the cap
+func (s *Server) SetMaxConnectionsPerUser(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	s.maxPerUser = n
+}
+
+// addClient adds a client to the active list. When the user already holds
+// the maximum number of connections, their oldest ones are closed so a
+// single account cannot exhaust the server's client budget.
+func (s *Server) addClient(client *Client) {
+	s.mu.Lock()
 	s.clients[client] = true
-	s.clientsByUser[client.UserID] = append(s.clientsByUser[client.UserID], client)
-	log.Printf("Client registered: UserID=%d, Total clients: %d", client.UserID, len(s.clients))
+	userClients := append(s.clientsByUser[client.UserID], client)
+
+	var evicted []*Client
+	if s.maxPerUser > 0 && len(userClients) > s.maxPerUser {
+		over := len(userClients) - s.maxPerUser
+		evicted = append(evicted, userClients[:over]...)
+		userClients = append([]*Client(nil), userClients[over:]...)
+		for _, old := range evicted {
+			delete(s.clients, old)
+		}
+	}
+	s.clientsByUser[client.UserID] = userClients
+	total := len(s.clients)
+	s.mu.Unlock()
+
+	log.Printf("Client registered: UserID=%d, Total clients: %d", client.UserID, total)
+
+	for _, old := range evicted {
+		log.Printf("Evicting oldest connection of UserID=%d: connection limit reached", client.UserID)
+		if err := old.SendError("connection_limit", "signed in on too many devices; this connection was closed"); err != nil {
+			log.Printf("Error notifying evicted client (UserID=%d): %v", client.UserID, err)
+		}
+		old.Close()
+	}
 }
 
 // removeClient removes a client from the active list
@@ -314,6 +448,13 @@ func (s *Server) removeClient(client *Client) {
 	log.Printf("Client disconnected: UserID=%d, Total clients: %d", client.UserID, len(s.clients))
 }
 
+// userBroadcast is a message queued for every connection of a single user
+type userBroadcast struct {
+	UserID  int64
+	Message *Message
+	Summary string
+}
+
 // BroadcastProgress broadcasts a progress update to all clients
 func (s *Server) BroadcastProgress(ctx context.Context, userID, novelID int64, chapter int, chapterID *int64) error {
 	update := ProgressUpdate{
@@ -323,9 +464,39 @@ func (s *Server) BroadcastProgress(ctx context.Context, userID, novelID int64, c
 		ChapterID: chapterID,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 	}
+	s.publishProgress(update)
+
+	return s.enqueueBroadcast(ctx, userBroadcast{
+		UserID:  userID,
+		Message: &Message{Type: MessageTypeProgress, Payload: update},
+		Summary: fmt.Sprintf("Progress update broadcasted: UserID=%d, NovelID=%d, Chapter=%d", userID, novelID, chapter),
+	})
+}
+
+// BroadcastLibraryChange notifies the user's other devices that a library entry changed
+func (s *Server) BroadcastLibraryChange(ctx context.Context, userID, mangaID int64, action string) error {
+	update := LibraryUpdate{
+		UserID:    userID,
+		MangaID:   mangaID,
+		Action:    action,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return s.enqueueBroadcast(ctx, userBroadcast{
+		UserID:  userID,
+		Message: &Message{Type: MessageTypeLibrary, Payload: update},
+		Summary: fmt.Sprintf("Library update broadcasted: UserID=%d, MangaID=%d, Action=%s", userID, mangaID, action),
+	})
+}
+
+func (s *Server) enqueueBroadcast(ctx context.Context, b userBroadcast) error {
+	if s.shuttingDown.Load() {
+		log.Printf("TCP server shutting down, dropping update")
+		return nil
+	}
 
 	select {
-	case s.broadcastCh <- update:
+	case s.broadcastCh <- b:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -336,7 +507,7 @@ func (s *Server) BroadcastProgress(ctx context.Context, userID, novelID int64, c
 	}
 }
 
-// handleBroadcasts handles broadcasting progress updates
+// handleBroadcasts handles broadcasting progress and library updates
 // Main Success Scenario:
 // 1. System receives progress update from HTTP API
 // 2. TCP server receives broadcast message via channel
@@ -349,52 +520,137 @@ func (s *Server) handleBroadcasts(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case update := <-s.broadcastCh:
-			// Step 3: Identify connections for the specific user
-			s.mu.RLock()
-			userClients, exists := s.clientsByUser[update.UserID]
-			if !exists || len(userClients) == 0 {
-				s.mu.RUnlock()
-				log.Printf("No active connections for user %d", update.UserID)
-				continue
-			}
+			s.deliverBroadcast(update)
+		}
+	}
+}
 
-			// Create a copy of the client list to avoid holding lock during sends
-			clients := make([]*Client, len(userClients))
-			copy(clients, userClients)
-			s.mu.RUnlock()
+// deliverBroadcast sends a queued update to every connection of its user
+func (s *Server) deliverBroadcast(update userBroadcast) {
+	// Step 3: Identify connections for the specific user
+	s.mu.RLock()
+	userClients, exists := s.clientsByUser[update.UserID]
+	if !exists || len(userClients) == 0 {
+		s.mu.RUnlock()
+		log.Printf("No active connections for user %d", update.UserID)
+		return
+	}
 
-			// Step 4: Send JSON progress message to connections
-			msg := &Message{
-				Type:    MessageTypeProgress,
-				Payload: update,
-			}
+	// Create a copy of the client list to avoid holding lock during sends
+	clients := make([]*Client, len(userClients))
+	copy(clients, userClients)
+	s.mu.RUnlock()
+
+	// Step 4: Send JSON message to connections
+	successCount := 0
+	for _, client := range clients {
+		// Check if client is still authenticated
+		if !client.IsAuthenticated() {
+			// A1: Client connection lost - Server removes from active list
+			s.removeClient(client)
+			continue
+		}
 
-			successCount := 0
-			for _, client := range clients {
-				// Check if client is still authenticated
-				if !client.IsAuthenticated() {
-					// A1: Client connection lost - Server removes from active list
-					s.removeClient(client)
-					continue
-				}
+		// Send message to client
+		if err := client.SendMessage(update.Message); err != nil {
+			// A2: Send fails - Server logs error and continues with other clients
+			log.Printf("Error broadcasting to client (UserID=%d, Device=%s): %v",
+				client.UserID, client.DeviceName, err)
+			// A1: Remove failed client from active list
+			s.removeClient(client)
+			continue
+		}
 
-				// Send message to client
-				if err := client.SendMessage(msg); err != nil {
-					// A2: Send fails - Server logs error and continues with other clients
-					log.Printf("Error broadcasting to client (UserID=%d, Device=%s): %v",
-						client.UserID, client.DeviceName, err)
-					// A1: Remove failed client from active list
-					s.removeClient(client)
-					continue
-				}
+		successCount++
+	}
 
-				successCount++
-			}
+	log.Printf("%s, Sent to %d/%d clients", update.Summary, successCount, len(clients))
+}
+
+// Shutdown stops accepting connections, delivers queued broadcasts, sends a
+// shutdown notice to every connected client, closes the connections and marks
+// their sync sessions closed. It returns early with ctx's error if ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if !s.shuttingDown.CompareAndSwap(false, true) {
+		return nil
+	}
 
-			log.Printf("Progress update broadcasted: UserID=%d, NovelID=%d, Chapter=%d, Sent to %d/%d clients",
-				update.UserID, update.NovelID, update.Chapter, successCount, len(clients))
+	// Stop accepting new connections
+	s.mu.RLock()
+	listener := s.listener
+	s.mu.RUnlock()
+	if listener != nil {
+		listener.Close()
+	}
+
+	// Flush updates that were queued before shutdown began
+flush:
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case update := <-s.broadcastCh:
+			s.deliverBroadcast(update)
+		default:
+			break flush
 		}
 	}
+
+	s.mu.RLock()
+	clients := make([]*Client, 0, len(s.clients))
+	for client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.RUnlock()
+
+	notice := &Message{
+		Type: MessageTypeShutdown,
+		Payload: ShutdownNotice{
+			Reason:  "server_shutdown",
+			Message: "server is shutting down, please reconnect later",
+		},
+	}
+	for _, client := range clients {
+		if err := client.SendMessage(notice); err != nil {
+			log.Printf("Error sending shutdown notice to client (UserID=%d): %v", client.UserID, err)
+		}
+		client.Close()
+	}
+
+	// Session rows are inserted asynchronously after authentication
+	recorded := make(chan struct{})
+	go func() {
+		s.sessionWG.Wait()
+		close(recorded)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-recorded:
+	}
+
+	return s.closeSyncSessions(ctx, clients)
+}
+
+// closeSyncSessions marks the sync sessions of the given clients as closed
+func (s *Server) closeSyncSessions(ctx context.Context, clients []*Client) error {
+	if s.db == nil {
+		return nil
+	}
+
+	now := time.Now()
+	for _, client := range clients {
+		sessionID := client.SessionID()
+		if sessionID == 0 {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, `
+			UPDATE Sync_Sessions SET Status = 'closed', Last_Seen_At = ? WHERE Id = ?
+		`, now, sessionID); err != nil {
+			return fmt.Errorf("close sync session %d: %w", sessionID, err)
+		}
+	}
+	return nil
 }
 
 // recordSyncSession records the sync session in the database
@@ -403,13 +659,22 @@ func (s *Server) recordSyncSession(client *Client) {
 		return
 	}
 
-	_, err := s.db.Exec(`
+	client.mu.RLock()
+	userID, deviceName, deviceType := client.UserID, client.DeviceName, client.DeviceType
+	connectedAt, lastSeen := client.ConnectedAt, client.LastSeen
+	client.mu.RUnlock()
+
+	result, err := s.db.Exec(`
 		INSERT INTO Sync_Sessions (User_Id, Device_Name, Device_Type, Status, Started_At, Last_Seen_At, Last_Ip)
 		VALUES (?, ?, ?, 'active', ?, ?, ?)
-	`, client.UserID, client.DeviceName, client.DeviceType, client.ConnectedAt, client.LastSeen, client.Conn.RemoteAddr().String())
+	`, userID, deviceName, deviceType, connectedAt, lastSeen, client.Conn.RemoteAddr().String())
 
 	if err != nil {
 		log.Printf("Error recording sync session: %v", err)
+		return
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		client.SetSessionID(id)
 	}
 }
 
@@ -437,6 +702,14 @@ func (s *Server) Stats() Stats {
 	}
 }
 
+// IsUserOnline reports whether the user has at least one authenticated TCP connection
+func (s *Server) IsUserOnline(userID int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.clientsByUser[userID]) > 0
+}
+
 // sendConnectionError sends an error message for connections that cannot be fully initialized
 func sendConnectionError(conn net.Conn, code, message string) {
 	msg := &Message{
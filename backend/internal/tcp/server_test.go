@@ -0,0 +1,283 @@
+package tcp
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+func revokedTestToken(t *testing.T) string {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file:tcp_revocation?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE Revoked_Tokens (
+		Jti TEXT PRIMARY KEY,
+		User_Id INTEGER NOT NULL,
+		Expires_At DATETIME NOT NULL,
+		Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	t.Cleanup(func() { auth.SetRevocationStore(nil) })
+
+	token, err := auth.GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+	return token
+}
+
+func TestHandleAuthenticationRejectsRevokedToken(t *testing.T) {
+	token := revokedTestToken(t)
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	s := NewServer("127.0.0.1:0", 10, nil)
+	client := NewClient(serverConn)
+
+	result := make(chan bool, 1)
+	go func() {
+		result <- s.handleAuthentication(client, &Message{
+			Type:    MessageTypeAuth,
+			Payload: AuthRequest{Token: token},
+		})
+	}()
+
+	msg, err := NewClient(clientConn).ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	if msg.Type != MessageTypeError {
+		t.Fatalf("expected error message, got %s", msg.Type)
+	}
+	payload, _ := msg.Payload.(map[string]interface{})
+	if payload["code"] != "token_revoked" {
+		t.Fatalf("expected token_revoked code, got %v", payload["code"])
+	}
+	if <-result {
+		t.Fatalf("expected authentication to fail")
+	}
+	if s.GetClientCount() != 0 {
+		t.Fatalf("revoked client must not be registered")
+	}
+}
+
+// newSyncSessionsDB returns an in-memory database with the Sync_Sessions table
+func newSyncSessionsDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE Sync_Sessions (
+		Id INTEGER PRIMARY KEY AUTOINCREMENT,
+		User_Id INTEGER NOT NULL,
+		Device_Name TEXT,
+		Device_Type TEXT,
+		Status TEXT NOT NULL DEFAULT 'active',
+		Started_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		Last_Seen_At DATETIME,
+		Last_Ip TEXT
+	)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+// connectTestClient authenticates a piped connection as userID and returns the
+// peer end, reading the auth response so the server-side write completes
+func connectTestClient(t *testing.T, s *Server, userID int64, deviceName string) *Client {
+	t.Helper()
+
+	token, err := auth.GenerateToken(userID, "user", "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+	peer := NewClient(clientConn)
+
+	authenticated := make(chan bool, 1)
+	go func() {
+		authenticated <- s.handleAuthentication(NewClient(serverConn), &Message{
+			Type:    MessageTypeAuth,
+			Payload: AuthRequest{Token: token, DeviceName: deviceName, DeviceType: "desktop"},
+		})
+	}()
+	if msg, err := peer.ReadMessage(); err != nil || msg.Type != MessageTypeAuthResp {
+		t.Fatalf("expected auth response, got %v (err=%v)", msg, err)
+	}
+	if !<-authenticated {
+		t.Fatalf("expected authentication to succeed")
+	}
+	s.sessionWG.Wait()
+	return peer
+}
+
+func TestShutdownNotifiesClientsAndClosesSessions(t *testing.T) {
+	db := newSyncSessionsDB(t)
+	s := NewServer("127.0.0.1:0", 10, db)
+	peer := connectTestClient(t, s, 7, "laptop")
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- s.Shutdown(ctx)
+	}()
+
+	msg, err := peer.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read shutdown notice: %v", err)
+	}
+	if msg.Type != MessageTypeShutdown {
+		t.Fatalf("expected shutdown message, got %s", msg.Type)
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	var status string
+	if err := db.QueryRow(`SELECT Status FROM Sync_Sessions WHERE User_Id = 7`).Scan(&status); err != nil {
+		t.Fatalf("failed to load sync session: %v", err)
+	}
+	if status != "closed" {
+		t.Fatalf("expected sync session to be closed, got %q", status)
+	}
+
+	if err := s.BroadcastProgress(context.Background(), 7, 1, 2, nil); err != nil {
+		t.Fatalf("BroadcastProgress returned error: %v", err)
+	}
+	if len(s.broadcastCh) != 0 {
+		t.Fatalf("expected broadcasts to be dropped after shutdown")
+	}
+}
+
+func TestAddClientEvictsOldestConnectionOverPerUserCap(t *testing.T) {
+	db := newSyncSessionsDB(t)
+	s := NewServer("127.0.0.1:0", 10, db)
+	s.SetMaxConnectionsPerUser(2)
+
+	phone := connectTestClient(t, s, 7, "phone")
+	connectTestClient(t, s, 7, "tablet")
+	connectTestClient(t, s, 8, "desktop")
+
+	evicted := make(chan *Message, 1)
+	go func() {
+		msg, _ := phone.ReadMessage()
+		evicted <- msg
+	}()
+	connectTestClient(t, s, 7, "laptop")
+
+	select {
+	case msg := <-evicted:
+		if msg == nil || msg.Type != MessageTypeError {
+			t.Fatalf("expected the oldest connection to get an error before closing, got %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("oldest connection was not notified")
+	}
+	if _, err := phone.ReadMessage(); err == nil {
+		t.Fatal("expected the oldest connection to be closed")
+	}
+
+	clients := s.userClients(7)
+	if len(clients) != 2 {
+		t.Fatalf("expected user to stay at the cap of 2 connections, got %d", len(clients))
+	}
+	if clients[0].DeviceName != "tablet" || clients[1].DeviceName != "laptop" {
+		t.Fatalf("expected tablet and laptop to remain, got %s and %s", clients[0].DeviceName, clients[1].DeviceName)
+	}
+	if got := len(s.userClients(8)); got != 1 {
+		t.Fatalf("expected other users to be unaffected, got %d connections", got)
+	}
+	s.mu.RLock()
+	total := len(s.clients)
+	s.mu.RUnlock()
+	if total != 3 {
+		t.Fatalf("expected 3 connections in total, got %d", total)
+	}
+}
+
+// authenticateSlowly runs a connection through handleClient with a client that
+// takes 1.5s to send its token, as on a high-latency mobile link, and reports
+// whether it was authenticated
+func authenticateSlowly(t *testing.T, deadlines Deadlines) bool {
+	t.Helper()
+
+	token, err := auth.GenerateToken(7, "user", "user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	s := NewServer("127.0.0.1:0", 10, nil)
+	s.SetDeadlines(deadlines)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serverConn, clientConn := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.handleClient(ctx, NewClient(serverConn))
+	}()
+	defer func() {
+		clientConn.Close()
+		<-done
+	}()
+
+	peer := NewClient(clientConn)
+	time.Sleep(1500 * time.Millisecond)
+	if err := peer.SendMessage(&Message{Type: MessageTypeAuth, Payload: AuthRequest{Token: token}}); err != nil {
+		return false
+	}
+	msg, err := peer.ReadMessage()
+	return err == nil && msg.Type == MessageTypeAuthResp
+}
+
+func TestLongerDeadlinesKeepSlowClientConnected(t *testing.T) {
+	if authenticateSlowly(t, Deadlines{Auth: MinDeadline, Read: MinDeadline, Write: MinDeadline}) {
+		t.Fatal("expected a 1s auth deadline to drop the slow client")
+	}
+	if !authenticateSlowly(t, Deadlines{Auth: 3 * time.Second, Read: 3 * time.Second, Write: 3 * time.Second}) {
+		t.Fatal("expected a 3s auth deadline to keep the slow client")
+	}
+}
+
+func TestSetDeadlinesEnforcesMinimum(t *testing.T) {
+	s := NewServer("127.0.0.1:0", 10, nil)
+	s.SetDeadlines(Deadlines{Auth: time.Millisecond, Write: time.Minute})
+
+	got := s.Deadlines()
+	want := Deadlines{Auth: MinDeadline, Read: DefaultDeadlines().Read, Write: time.Minute}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
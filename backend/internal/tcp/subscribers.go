@@ -0,0 +1,76 @@
+package tcp
+
+import (
+	"log"
+	"time"
+)
+
+// subscriberBuffer is how many updates a slow subscriber may fall behind
+// before further updates are dropped for it
+const subscriberBuffer = 32
+
+// SubscribeProgress registers an in-process listener for the user's progress
+// updates, alongside the user's TCP connections. The returned cancel func
+// unsubscribes and closes the channel; it is safe to call more than once.
+func (s *Server) SubscribeProgress(userID int64) (<-chan ProgressUpdate, func()) {
+	ch := make(chan ProgressUpdate, subscriberBuffer)
+
+	s.subMu.Lock()
+	subs, ok := s.subscribers[userID]
+	if !ok {
+		subs = make(map[chan ProgressUpdate]struct{})
+		s.subscribers[userID] = subs
+	}
+	subs[ch] = struct{}{}
+	s.subMu.Unlock()
+
+	cancel := func() {
+		s.subMu.Lock()
+		defer s.subMu.Unlock()
+		subs, ok := s.subscribers[userID]
+		if !ok {
+			return
+		}
+		if _, ok := subs[ch]; !ok {
+			return
+		}
+		delete(subs, ch)
+		if len(subs) == 0 {
+			delete(s.subscribers, userID)
+		}
+		close(ch)
+	}
+	return ch, cancel
+}
+
+// SubscriberCount returns the number of progress subscribers for a user
+func (s *Server) SubscriberCount(userID int64) int {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return len(s.subscribers[userID])
+}
+
+// PublishProgress delivers a progress update to in-process subscribers only.
+// It is used when the TCP listener is down but subscribers are still attached.
+func (s *Server) PublishProgress(userID, novelID int64, chapter int, chapterID *int64) {
+	s.publishProgress(ProgressUpdate{
+		UserID:    userID,
+		NovelID:   novelID,
+		Chapter:   chapter,
+		ChapterID: chapterID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// publishProgress never blocks: updates for a full subscriber are dropped
+func (s *Server) publishProgress(update ProgressUpdate) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for ch := range s.subscribers[update.UserID] {
+		select {
+		case ch <- update:
+		default:
+			log.Printf("Progress subscriber for user %d is full, dropping update", update.UserID)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package testdb builds the production schema for tests that must run
+// against the real tables rather than a hand-written subset.
+package testdb
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	dbpkg "github.com/ngocan-dev/mangahub/backend/db"
+)
+
+// backendDir is the module root, found from this file so callers in any
+// package get the same paths
+func backendDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// Migrated returns a SQLite database in a temporary file with every
+// migration applied. The migrations directory starts at 010; the earlier
+// migrations only survive in the bundled development database, so a copy of
+// it provides the base tables.
+func Migrated(t testing.TB) *sql.DB {
+	t.Helper()
+
+	root := backendDir()
+	base, err := os.ReadFile(filepath.Join(root, "data", "mangahub.db"))
+	if err != nil {
+		t.Fatalf("failed to read the base database: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "mangahub.db")
+	if err := os.WriteFile(path, base, 0o600); err != nil {
+		t.Fatalf("failed to copy the base database: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := dbpkg.RunMigrations(db, filepath.Join(root, "db", "migrations")); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	return db
+}
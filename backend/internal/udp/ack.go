@@ -0,0 +1,134 @@
+package udp
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+)
+
+const (
+	// maxDeliveryAttempts bounds how often an unacknowledged notification is sent
+	maxDeliveryAttempts = 3
+
+	// defaultAckTimeout is how long to wait for the first ack before resending;
+	// each further attempt doubles the wait
+	defaultAckTimeout = 2 * time.Second
+)
+
+// pendingDelivery is a notification sent to a client that has not been acknowledged yet
+type pendingDelivery struct {
+	clientKey string
+	addr      *net.UDPAddr
+	packet    *Packet
+	attempts  int
+	nextRetry time.Time
+}
+
+// trackDelivery assigns the next sequence number to a per-client copy of packet
+// and records it as unacknowledged. The caller sends the returned packet.
+func (s *Server) trackDelivery(client *Client, packet *Packet) *Packet {
+	p := *packet
+	p.Seq = s.nextSeq.Add(1)
+
+	s.pendingMu.Lock()
+	s.pending[p.Seq] = &pendingDelivery{
+		clientKey: client.GetKey(),
+		addr:      client.Address,
+		packet:    &p,
+		attempts:  1,
+		nextRetry: time.Now().Add(s.ackTimeout),
+	}
+	s.pendingMu.Unlock()
+
+	return &p
+}
+
+// handleAck clears a pending delivery once the client confirms it
+func (s *Server) handleAck(packet *Packet, addr *net.UDPAddr) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	delivery, ok := s.pending[packet.Seq]
+	if !ok {
+		return
+	}
+	// Only the client the notification was sent to may acknowledge it
+	if delivery.addr.String() != addr.String() {
+		log.Printf("Ignoring ack for seq %d from unexpected address %s", packet.Seq, addr.String())
+		return
+	}
+	delete(s.pending, packet.Seq)
+}
+
+// dropPending forgets unacknowledged deliveries for a removed client
+func (s *Server) dropPending(clientKey string) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	for seq, delivery := range s.pending {
+		if delivery.clientKey == clientKey {
+			delete(s.pending, seq)
+		}
+	}
+}
+
+// unackedCounts returns the total number of unacknowledged deliveries and a per-client breakdown
+func (s *Server) unackedCounts() (int, map[string]int) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	byClient := make(map[string]int)
+	for _, delivery := range s.pending {
+		byClient[delivery.clientKey]++
+	}
+	return len(s.pending), byClient
+}
+
+// retryUnacked resends unacknowledged notifications with exponential backoff,
+// giving up after maxDeliveryAttempts
+func (s *Server) retryUnacked(ctx context.Context) {
+	interval := s.ackTimeout / 4
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.resendDue(time.Now())
+		}
+	}
+}
+
+// resendDue resends every pending delivery whose retry time has passed
+func (s *Server) resendDue(now time.Time) {
+	var due []*pendingDelivery
+
+	s.pendingMu.Lock()
+	for seq, delivery := range s.pending {
+		if now.Before(delivery.nextRetry) {
+			continue
+		}
+		if delivery.attempts >= maxDeliveryAttempts {
+			log.Printf("Notification seq %d to %s unacknowledged after %d attempts, giving up",
+				seq, delivery.addr.String(), delivery.attempts)
+			delete(s.pending, seq)
+			continue
+		}
+		delivery.attempts++
+		delivery.nextRetry = now.Add(s.ackTimeout << (delivery.attempts - 1))
+		due = append(due, delivery)
+	}
+	s.pendingMu.Unlock()
+
+	for _, delivery := range due {
+		if err := s.sendPacket(delivery.addr, delivery.packet); err != nil {
+			log.Printf("Error resending notification seq %d to %s: %v", delivery.packet.Seq, delivery.addr.String(), err)
+		}
+	}
+}
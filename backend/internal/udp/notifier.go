@@ -5,21 +5,54 @@ import (
 	"log"
 	"net"
 	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
 )
 
+// ChapterReleaseRecorder keeps a durable record of chapter release
+// notifications so offline users can review them later
+type ChapterReleaseRecorder interface {
+	RecordChapterRelease(ctx context.Context, novelID int64, novelName string, chapter int, chapterID int64, liveUserIDs []int64) (int, error)
+}
+
 // Notifier handles sending notifications to registered clients
 type Notifier struct {
-	server *Server
+	server   *Server
+	recorder ChapterReleaseRecorder
 }
 
-// NewNotifier creates a new notifier instance
+// NewNotifier creates a new notifier instance. server may be nil when the UDP
+// server is disabled; notifications are then only recorded.
 func NewNotifier(server *Server) *Notifier {
 	return &Notifier{server: server}
 }
 
-// NotifyChapterRelease sends a chapter release notification to subscribed clients
+// SetRecorder configures where notifications are persisted
+func (n *Notifier) SetRecorder(recorder ChapterReleaseRecorder) {
+	n.recorder = recorder
+}
+
+// record persists the notification; failures are logged so the live push still happens
+func (n *Notifier) record(ctx context.Context, novelID int64, novelName string, chapter int, chapterID int64, clients []*Client) {
+	if n.recorder == nil {
+		return
+	}
+	userIDs := make([]int64, 0, len(clients))
+	for _, c := range clients {
+		userIDs = append(userIDs, c.UserID)
+	}
+	recorded, err := n.recorder.RecordChapterRelease(ctx, novelID, novelName, chapter, chapterID, userIDs)
+	if err != nil {
+		log.Printf("udp.Notifier: failed to record notification NovelID=%d Chapter=%d: %v", novelID, chapter, err)
+		return
+	}
+	log.Printf("udp.Notifier: recorded notification NovelID=%d Chapter=%d for %d users", novelID, chapter, recorded)
+}
+
+// NotifyChapterRelease records a chapter release notification and sends it to subscribed clients
 func (n *Notifier) NotifyChapterRelease(ctx context.Context, novelID int64, novelName string, chapter int, chapterID int64) error {
 	if n.server == nil {
+		n.record(ctx, novelID, novelName, chapter, chapterID, nil)
 		return nil
 	}
 
@@ -62,6 +95,8 @@ func (n *Notifier) NotifyChapterRelease(ctx context.Context, novelID int64, nove
 
 	n.server.mu.RUnlock()
 
+	n.record(ctx, novelID, novelName, chapter, chapterID, clients)
+
 	// Step 3: Broadcast message to all registered clients
 	// Main Success Scenario:
 	// 1. Administrator triggers notification for specific manga
@@ -75,8 +110,12 @@ func (n *Notifier) NotifyChapterRelease(ctx context.Context, novelID int64, nove
 	for _, client := range clients {
 		client.UpdateLastSeen()
 
+		// Each client gets its own sequence number and is resent the
+		// notification until it acknowledges it
+		packet := n.server.trackDelivery(client, notification)
+
 		// A2: Network error - Server logs error and retries
-		err := n.sendWithRetry(ctx, client.Address, notification, 3)
+		err := n.sendWithRetry(ctx, client.Address, packet, 3)
 		if err != nil {
 			// A1: Client unreachable - Server continues with other clients
 			log.Printf("Failed to send notification to %s (UserID=%d) after retries: %v",
@@ -94,6 +133,40 @@ func (n *Notifier) NotifyChapterRelease(ctx context.Context, novelID int64, nove
 	return nil
 }
 
+// GoalCompleted pushes a goal completion to the user's registered clients.
+// The durable record is kept by the notification service, not the recorder.
+func (n *Notifier) GoalCompleted(ctx context.Context, goal *history.ReadingGoal) error {
+	if n.server == nil {
+		return nil
+	}
+
+	notification := &Packet{
+		Type: PacketTypeGoalCompleted,
+		Payload: GoalCompletedPacket{
+			GoalID:      goal.GoalID,
+			GoalType:    goal.GoalType,
+			PeriodType:  goal.PeriodType,
+			TargetValue: goal.TargetValue,
+			Message:     goal.CompletionMessage(),
+			Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	n.server.mu.RLock()
+	clients := append([]*Client(nil), n.server.clientsByUser[goal.UserID]...)
+	n.server.mu.RUnlock()
+
+	var lastErr error
+	for _, client := range clients {
+		packet := n.server.trackDelivery(client, notification)
+		if err := n.sendWithRetry(ctx, client.Address, packet, 3); err != nil {
+			log.Printf("Failed to send goal completion to %s (UserID=%d): %v", client.Address.String(), client.UserID, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
 // sendWithRetry sends a packet with retry logic
 // A2: Network error - Server logs error and retries
 func (n *Notifier) sendWithRetry(ctx context.Context, addr *net.UDPAddr, packet *Packet, maxRetries int) error {
@@ -13,16 +13,21 @@ var (
 type PacketType string
 
 const (
-	PacketTypeRegister     PacketType = "register"
-	PacketTypeConfirm      PacketType = "confirm"
-	PacketTypeUnregister   PacketType = "unregister"
-	PacketTypeNotification PacketType = "notification"
-	PacketTypeError        PacketType = "error"
+	PacketTypeRegister      PacketType = "register"
+	PacketTypeConfirm       PacketType = "confirm"
+	PacketTypeUnregister    PacketType = "unregister"
+	PacketTypeNotification  PacketType = "notification"
+	PacketTypeAck           PacketType = "ack"
+	PacketTypeError         PacketType = "error"
+	PacketTypeShutdown      PacketType = "shutdown"
+	PacketTypeGoalCompleted PacketType = "goal_completed"
 )
 
 // Packet represents a UDP protocol packet
+// Notifications carry a Seq that the client echoes back in an ack packet.
 type Packet struct {
 	Type    PacketType  `json:"type"`
+	Seq     uint64      `json:"seq,omitempty"`
 	Payload interface{} `json:"payload,omitempty"`
 	Error   string      `json:"error,omitempty"`
 }
@@ -54,6 +59,22 @@ type NotificationPacket struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// GoalCompletedPacket tells a user they completed a reading goal
+type GoalCompletedPacket struct {
+	GoalID      int64  `json:"goal_id"`
+	GoalType    string `json:"goal_type"`
+	PeriodType  string `json:"period_type"`
+	TargetValue int    `json:"target_value"`
+	Message     string `json:"message"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// ShutdownPacket tells clients the server is going away and they should re-register later
+type ShutdownPacket struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
 // ParsePacket parses a JSON packet from bytes
 func ParsePacket(data []byte) (*Packet, error) {
 	var packet Packet
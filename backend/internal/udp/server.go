@@ -23,19 +23,52 @@ type Server struct {
 	clientsByUser  map[int64][]*Client // Clients grouped by user
 	clientsByNovel map[int64][]*Client // Clients grouped by novel subscription
 	maxClients     int
+	deadlines      Deadlines
 	mu             sync.RWMutex
 	running        atomic.Bool
+	shuttingDown   atomic.Bool
+
+	// Delivery acknowledgement tracking
+	nextSeq    atomic.Uint64
+	pending    map[uint64]*pendingDelivery
+	pendingMu  sync.Mutex
+	ackTimeout time.Duration
 }
 
 // Stats describes current runtime state of the UDP server.
 type Stats struct {
-	Running    bool
-	Clients    int
-	MaxClients int
+	Running         bool
+	Clients         int
+	MaxClients      int
+	Unacked         int
+	UnackedByClient map[string]int
 }
 
 const defaultMaxClients = 1000
 
+// Deadlines bounds how long the server waits on the socket and on clients
+type Deadlines struct {
+	// Read is how long the receive loop blocks before checking for shutdown
+	Read time.Duration
+	// StaleAfter is how long a registered client may stay silent before it
+	// is dropped
+	StaleAfter time.Duration
+}
+
+// Shortest deadlines SetDeadlines accepts
+const (
+	MinReadDeadline = 100 * time.Millisecond
+	MinStaleAfter   = time.Minute
+)
+
+// DefaultDeadlines returns the deadlines used unless SetDeadlines says otherwise
+func DefaultDeadlines() Deadlines {
+	return Deadlines{
+		Read:       time.Second,
+		StaleAfter: 30 * time.Minute,
+	}
+}
+
 // NewServer creates a new UDP server instance
 func NewServer(address string, db *sql.DB) *Server {
 	return &Server{
@@ -45,6 +78,9 @@ func NewServer(address string, db *sql.DB) *Server {
 		clientsByUser:  make(map[int64][]*Client),
 		clientsByNovel: make(map[int64][]*Client),
 		maxClients:     defaultMaxClients,
+		deadlines:      DefaultDeadlines(),
+		pending:        make(map[uint64]*pendingDelivery),
+		ackTimeout:     defaultAckTimeout,
 	}
 }
 
@@ -69,6 +105,9 @@ func (s *Server) Start(ctx context.Context) error {
 	// Start cleanup goroutine for stale clients
 	go s.cleanupStaleClients(ctx)
 
+	// Resend notifications that were not acknowledged
+	go s.retryUnacked(ctx)
+
 	// Main receive loop
 	buffer := make([]byte, 4096)
 	for {
@@ -77,10 +116,13 @@ func (s *Server) Start(ctx context.Context) error {
 			return ctx.Err()
 		default:
 			// Set read deadline
-			conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+			conn.SetReadDeadline(time.Now().Add(s.Deadlines().Read))
 
 			n, clientAddr, err := conn.ReadFromUDP(buffer)
 			if err != nil {
+				if s.shuttingDown.Load() {
+					return nil
+				}
 				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 					// Timeout - continue loop
 					continue
@@ -103,12 +145,18 @@ func (s *Server) IsRunning() bool {
 // Stats returns current runtime metrics for the UDP server.
 func (s *Server) Stats() Stats {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
+	clients := len(s.clients)
+	maxClients := s.maxClients
+	s.mu.RUnlock()
+
+	unacked, byClient := s.unackedCounts()
 
 	return Stats{
-		Running:    s.running.Load(),
-		Clients:    len(s.clients),
-		MaxClients: s.maxClients,
+		Running:         s.running.Load(),
+		Clients:         clients,
+		MaxClients:      maxClients,
+		Unacked:         unacked,
+		UnackedByClient: byClient,
 	}
 }
 
@@ -132,6 +180,8 @@ func (s *Server) handlePacket(ctx context.Context, data []byte, addr *net.UDPAdd
 		s.handleRegister(ctx, packet, addr)
 	case PacketTypeUnregister:
 		s.handleUnregister(ctx, packet, addr)
+	case PacketTypeAck:
+		s.handleAck(packet, addr)
 	default:
 		log.Printf("Unknown packet type: %s from %s", packet.Type, addr.String())
 		s.sendError(addr, "unknown_type", "unknown packet type")
@@ -170,6 +220,11 @@ func (s *Server) handleRegister(ctx context.Context, packet *Packet, addr *net.U
 		return
 	}
 
+	if s.shuttingDown.Load() {
+		s.sendError(addr, "server_shutdown", "server is shutting down, please try again later")
+		return
+	}
+
 	// Step 3: Add client to notification list
 	clientKey := fmt.Sprintf("%s:%d", addr.String(), userID)
 
@@ -192,13 +247,13 @@ func (s *Server) handleRegister(ctx context.Context, packet *Packet, addr *net.U
 			return
 		}
 
-		// Create new client
-		novelIDs := req.NovelIDs
-		if req.AllNovels {
+		// Create new client, including subscriptions made over HTTP
+		novelIDs, allNovels := s.mergeStoredSubscriptions(ctx, userID, req.NovelIDs, req.AllNovels)
+		if allNovels {
 			novelIDs = []int64{} // Empty means all
 		}
 
-		client := NewClient(addr, userID, novelIDs, req.AllNovels, req.DeviceID)
+		client := NewClient(addr, userID, novelIDs, allNovels, req.DeviceID)
 		if err := s.addClient(client); err != nil {
 			s.sendError(addr, "server_capacity", err.Error())
 			return
@@ -258,6 +313,47 @@ func (s *Server) handleUnregister(ctx context.Context, packet *Packet, addr *net
 	log.Printf("Client unregistered: UserID=%d, Address=%s", userID, addr.String())
 }
 
+// Shutdown stops accepting registrations, resends notifications still waiting
+// for an ack one last time, sends a shutdown packet to every registered client
+// and closes the socket.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if !s.shuttingDown.CompareAndSwap(false, true) {
+		return nil
+	}
+	if s.conn == nil {
+		return nil
+	}
+
+	// Flush unacknowledged notifications before saying goodbye
+	s.resendDue(time.Now().Add(24 * time.Hour))
+
+	s.mu.RLock()
+	clients := make([]*Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.RUnlock()
+
+	packet := &Packet{
+		Type: PacketTypeShutdown,
+		Payload: ShutdownPacket{
+			Reason:  "server_shutdown",
+			Message: "server is shutting down, please re-register later",
+		},
+	}
+	for _, client := range clients {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.sendPacket(client.Address, packet); err != nil {
+			log.Printf("Error sending shutdown packet to %s: %v", client.Address.String(), err)
+		}
+		s.removeClient(client.GetKey())
+	}
+
+	return s.conn.Close()
+}
+
 // addClient adds a client to the notification list
 func (s *Server) addClient(client *Client) error {
 	s.mu.Lock()
@@ -330,6 +426,7 @@ func (s *Server) removeClient(clientKey string) {
 	}
 
 	delete(s.clients, clientKey)
+	s.dropPending(clientKey)
 
 	// Remove from user's client list
 	if clients, ok := s.clientsByUser[client.UserID]; ok {
@@ -359,6 +456,26 @@ func (s *Server) removeClient(clientKey string) {
 	}
 }
 
+// SetDeadlines changes the server's deadlines. Zero fields keep their
+// current value and values below the minimums are raised to them.
+func (s *Server) SetDeadlines(d Deadlines) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.Read != 0 {
+		s.deadlines.Read = max(d.Read, MinReadDeadline)
+	}
+	if d.StaleAfter != 0 {
+		s.deadlines.StaleAfter = max(d.StaleAfter, MinStaleAfter)
+	}
+}
+
+// Deadlines returns the deadlines in effect
+func (s *Server) Deadlines() Deadlines {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deadlines
+}
+
 // SetMaxClients updates the maximum allowed concurrent clients (<=0 disables the limit)
 func (s *Server) SetMaxClients(max int) {
 	s.mu.Lock()
@@ -400,7 +517,8 @@ func (s *Server) sendError(addr *net.UDPAddr, code, message string) {
 
 // cleanupStaleClients removes clients that haven't been seen recently
 func (s *Server) cleanupStaleClients(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Minute)
+	// Sweep at least as often as clients can go stale
+	ticker := time.NewTicker(min(5*time.Minute, s.Deadlines().StaleAfter))
 	defer ticker.Stop()
 
 	for {
@@ -410,7 +528,7 @@ func (s *Server) cleanupStaleClients(ctx context.Context) {
 		case <-ticker.C:
 			s.mu.Lock()
 			now := time.Now()
-			staleThreshold := 30 * time.Minute
+			staleThreshold := s.deadlines.StaleAfter
 
 			var toRemove []string
 			for key, client := range s.clients {
@@ -433,6 +551,52 @@ func (s *Server) cleanupStaleClients(ctx context.Context) {
 	}
 }
 
+// mergeStoredSubscriptions adds the user's active subscriptions from the
+// database to the ones sent in the register packet
+func (s *Server) mergeStoredSubscriptions(ctx context.Context, userID int64, novelIDs []int64, allNovels bool) ([]int64, bool) {
+	if s.db == nil || allNovels {
+		return novelIDs, allNovels
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT manga_id FROM notification_subscriptions
+		WHERE user_id = ? AND is_active = 1
+	`, userID)
+	if err != nil {
+		log.Printf("Error loading subscriptions: %v", err)
+		return novelIDs, allNovels
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool, len(novelIDs))
+	merged := make([]int64, 0, len(novelIDs))
+	for _, id := range novelIDs {
+		if !seen[id] {
+			seen[id] = true
+			merged = append(merged, id)
+		}
+	}
+	for rows.Next() {
+		var mangaID sql.NullInt64
+		if err := rows.Scan(&mangaID); err != nil {
+			log.Printf("Error loading subscriptions: %v", err)
+			return novelIDs, allNovels
+		}
+		if !mangaID.Valid {
+			return nil, true
+		}
+		if !seen[mangaID.Int64] {
+			seen[mangaID.Int64] = true
+			merged = append(merged, mangaID.Int64)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error loading subscriptions: %v", err)
+		return novelIDs, allNovels
+	}
+	return merged, false
+}
+
 // recordSubscription records the subscription in the database
 func (s *Server) recordSubscription(ctx context.Context, client *Client) {
 	if s.db == nil {
@@ -446,24 +610,31 @@ func (s *Server) recordSubscription(ctx context.Context, client *Client) {
 	client.mu.RUnlock()
 
 	if allNovels {
-		// Subscribe to all novels - insert a single record with NULL novel_id
+		// Subscribe to all novels - a single record with NULL manga_id
 		_, err := s.db.ExecContext(ctx, `
-			INSERT OR REPLACE INTO Notification_Subscriptions (User_Id, Novel_Id, Is_Active, Created_At)
-			VALUES (?, NULL, 1, ?)
-		`, userID, time.Now())
+			INSERT INTO notification_subscriptions (user_id, manga_id, is_active)
+			SELECT ?, NULL, 1
+			WHERE NOT EXISTS (
+				SELECT 1 FROM notification_subscriptions WHERE user_id = ? AND manga_id IS NULL
+			)
+		`, userID, userID)
 		if err != nil {
 			log.Printf("Error recording subscription: %v", err)
 		}
-	} else {
-		// Subscribe to specific novels
-		for _, novelID := range novelIDs {
-			_, err := s.db.ExecContext(ctx, `
-				INSERT OR REPLACE INTO Notification_Subscriptions (User_Id, Novel_Id, Is_Active, Created_At)
-				VALUES (?, ?, 1, ?)
-			`, userID, novelID, time.Now())
-			if err != nil {
-				log.Printf("Error recording subscription: %v", err)
-			}
+		return
+	}
+
+	// Subscribe to specific novels
+	for _, novelID := range novelIDs {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO notification_subscriptions (user_id, manga_id, is_active)
+			SELECT ?, ?, 1
+			WHERE NOT EXISTS (
+				SELECT 1 FROM notification_subscriptions WHERE user_id = ? AND manga_id = ?
+			)
+		`, userID, novelID, userID, novelID)
+		if err != nil {
+			log.Printf("Error recording subscription: %v", err)
 		}
 	}
 }
@@ -166,3 +166,75 @@ func TestHandleRegisterRespectsCapacity(t *testing.T) {
 		t.Fatalf("expected server_capacity code, got %#v", payload["code"])
 	}
 }
+
+func TestNotificationResentUntilAcknowledged(t *testing.T) {
+	serverConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("failed to create server UDP conn: %v", err)
+	}
+	defer serverConn.Close()
+
+	clientConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("failed to create client UDP conn: %v", err)
+	}
+	defer clientConn.Close()
+
+	server := NewServer(serverConn.LocalAddr().String(), nil)
+	server.conn = serverConn
+	server.ackTimeout = 50 * time.Millisecond
+
+	clientAddr := clientConn.LocalAddr().(*net.UDPAddr)
+	if err := server.addClient(NewClient(clientAddr, 7, []int64{99}, false, "")); err != nil {
+		t.Fatalf("failed to add client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.retryUnacked(ctx)
+
+	if err := NewNotifier(server).NotifyChapterRelease(ctx, 99, "Test Manga", 12, 1200); err != nil {
+		t.Fatalf("NotifyChapterRelease returned error: %v", err)
+	}
+
+	readNotification := func() *Packet {
+		t.Helper()
+		buf := make([]byte, 1024)
+		if err := clientConn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatalf("failed to set read deadline: %v", err)
+		}
+		n, _, err := clientConn.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatalf("failed to read notification: %v", err)
+		}
+		packet, err := ParsePacket(buf[:n])
+		if err != nil {
+			t.Fatalf("failed to parse notification: %v", err)
+		}
+		if packet.Type != PacketTypeNotification || packet.Seq == 0 {
+			t.Fatalf("expected sequenced notification, got %s seq=%d", packet.Type, packet.Seq)
+		}
+		return packet
+	}
+
+	// Simulate the first delivery being lost: read it but never acknowledge it
+	first := readNotification()
+	if stats := server.Stats(); stats.Unacked != 1 {
+		t.Fatalf("expected 1 unacked notification, got %d", stats.Unacked)
+	}
+
+	retry := readNotification()
+	if retry.Seq != first.Seq {
+		t.Fatalf("expected retry to reuse seq %d, got %d", first.Seq, retry.Seq)
+	}
+
+	ack, err := SerializePacket(&Packet{Type: PacketTypeAck, Seq: retry.Seq})
+	if err != nil {
+		t.Fatalf("failed to serialize ack: %v", err)
+	}
+	server.handlePacket(ctx, ack, clientAddr)
+
+	if stats := server.Stats(); stats.Unacked != 0 {
+		t.Fatalf("expected no unacked notifications after ack, got %d", stats.Unacked)
+	}
+}
@@ -0,0 +1,26 @@
+// Package version holds the build metadata of the server binaries. The
+// values are overridden at build time, e.g.
+//
+//	go build -ldflags "-X github.com/ngocan-dev/mangahub/backend/internal/version.Version=1.4.0 \
+//	    -X github.com/ngocan-dev/mangahub/backend/internal/version.GitCommit=$(git rev-parse --short HEAD) \
+//	    -X github.com/ngocan-dev/mangahub/backend/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+// Set via -ldflags; unset values identify a development build
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info is the build metadata served by GET /version
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get returns the build metadata of the running binary
+func Get() Info {
+	return Info{Version: Version, GitCommit: GitCommit, BuildTime: BuildTime}
+}
@@ -1,7 +1,9 @@
 package websocket
 
 import (
+	"errors"
 	"log"
+	"net"
 	"sync"
 	"time"
 
@@ -31,6 +33,19 @@ type Client struct {
 	Username string
 	RoomID   int64
 	mu       sync.RWMutex
+
+	// lastPong is when the peer last answered a ping; guarded by mu
+	lastPong time.Time
+
+	// seq orders the user's connections for eviction; guarded by hub.mu
+	seq uint64
+
+	// Send throttle, see allowSend
+	limitMu       sync.Mutex
+	sendTokens    int
+	sendRefillAt  time.Time
+	sendStrikes   int
+	sendMuteUntil time.Time
 }
 
 // NewClient creates a new WebSocket client
@@ -39,6 +54,8 @@ func NewClient(hub *Hub, conn *websocket.Conn) *Client {
 		hub:  hub,
 		conn: conn,
 		send: make(chan []byte, 256),
+		// The connection counts as alive until the first ping is due
+		lastPong: time.Now(),
 	}
 }
 
@@ -78,6 +95,20 @@ func (c *Client) GetRoomID() int64 {
 	return c.RoomID
 }
 
+// LastPong returns when the client last answered a ping, or when it
+// connected if it has not been pinged yet
+func (c *Client) LastPong() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastPong
+}
+
+func (c *Client) markPong() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastPong = time.Now()
+}
+
 // ReadPump pumps messages from the WebSocket connection to the hub
 func (c *Client) ReadPump() {
 	defer func() {
@@ -85,17 +116,24 @@ func (c *Client) ReadPump() {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	// A peer that misses a pong hits the read deadline, which ends this loop
+	// and unregisters the client like any other disconnect
+	_, timeout := c.hub.heartbeat()
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.markPong()
+		c.conn.SetReadDeadline(time.Now().Add(timeout))
 		return nil
 	})
 
 	for {
 		_, message, err := c.conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				log.Printf("WebSocket client missed pong: UserID=%d, last pong %s ago", c.GetUserID(), time.Since(c.LastPong()).Round(time.Millisecond))
+			} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
@@ -116,7 +154,8 @@ func (c *Client) ReadPump() {
 
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump() {
-	ticker := time.NewTicker(pingPeriod)
+	interval, _ := c.hub.heartbeat()
+	ticker := time.NewTicker(interval)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -158,6 +197,17 @@ func (c *Client) WritePump() {
 	}
 }
 
+// closeWithReason tells the peer why it is being disconnected and closes the
+// connection; the read pump then unregisters the client as usual
+func (c *Client) closeWithReason(reason string) {
+	if c.conn == nil {
+		return
+	}
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+	_ = c.conn.Close()
+}
+
 // SendMessage sends a message to the client
 func (c *Client) SendMessage(msg *Message) error {
 	data, err := SerializeMessage(msg)
@@ -21,6 +21,9 @@ type DirectChatHub struct {
 	db         *sql.DB
 	friendRepo *friend.Repository
 
+	// upgrader rejects cross-origin upgrades with 403; see SetAllowedOrigins
+	upgrader websocket.Upgrader
+
 	mu       sync.RWMutex
 	presence map[int64]map[int64]*websocket.Conn
 	chats    map[int64]map[int64]*websocket.Conn
@@ -36,23 +39,24 @@ type DirectMessage struct {
 	Timestamp int64  `json:"timestamp"`
 }
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// Nếu bạn cần strict origin: check ở đây.
-		return true
-	},
-}
-
 // NewDirectChatHub constructs a new hub.
 func NewDirectChatHub(db *sql.DB) *DirectChatHub {
 	return &DirectChatHub{
 		db:         db,
 		friendRepo: friend.NewRepository(db),
-		presence:   make(map[int64]map[int64]*websocket.Conn),
-		chats:      make(map[int64]map[int64]*websocket.Conn),
+		// Until SetAllowedOrigins is called only same-origin pages may connect
+		upgrader: websocket.Upgrader{},
+		presence: make(map[int64]map[int64]*websocket.Conn),
+		chats:    make(map[int64]map[int64]*websocket.Conn),
 	}
 }
 
+// SetAllowedOrigins limits which browser origins may open connections,
+// normally the CORS allowlist.
+func (h *DirectChatHub) SetAllowedOrigins(origins []string) {
+	h.upgrader.CheckOrigin = CheckOrigin(origins)
+}
+
 // -----------------------
 // Presence (online users)
 // -----------------------
@@ -68,6 +72,26 @@ func (h *DirectChatHub) GetOnlineUserIDs() []int64 {
 	return ids
 }
 
+// IsUserOnline reports whether the user has an open presence connection.
+func (h *DirectChatHub) IsUserOnline(userID int64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.presence[userID]) > 0
+}
+
+// ConnectionCount returns the number of open presence connections.
+func (h *DirectChatHub) ConnectionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, conns := range h.presence {
+		count += len(conns)
+	}
+	return count
+}
+
 func (h *DirectChatHub) addPresenceConn(userID int64, conn *websocket.Conn) int64 {
 	connID := atomic.AddInt64(&h.connSeq, 1)
 
@@ -136,7 +160,7 @@ func (h *DirectChatHub) broadcastPresence() {
 			"online_user_ids": visible,
 		}
 		for _, conn := range conns {
-			_ = conn.WriteJSON(msg)
+			_ = writeJSON(conn, msg)
 		}
 	}
 
@@ -192,12 +216,22 @@ func (h *DirectChatHub) getChatConn(userID, friendID int64) *websocket.Conn {
 func (h *DirectChatHub) HandleWS(w http.ResponseWriter, r *http.Request, userID int64) {
 	friendIDStr := r.URL.Query().Get("friend_id")
 
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("ws upgrade error: %v", err)
 		return
 	}
 
+	// Peers must answer pings within pongWait or the read loop ends
+	conn.SetReadLimit(maxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go keepAlive(conn, stopPing)
+
 	// Always register presence for this WS connection
 	pConnID := h.addPresenceConn(userID, conn)
 	h.broadcastPresence()
@@ -249,13 +283,8 @@ func (h *DirectChatHub) HandleWS(w http.ResponseWriter, r *http.Request, userID
 // -----------------------
 
 func (h *DirectChatHub) presenceReadLoop(userID, connID int64, conn *websocket.Conn) {
-	// keepalive / detect disconnect
+	// Presence clients only send control frames
 	conn.SetReadLimit(1024)
-	_ = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	conn.SetPongHandler(func(string) error {
-		_ = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		return nil
-	})
 
 	for {
 		// ReadMessage để bắt close frame / ping/pong
@@ -276,6 +305,12 @@ func (h *DirectChatHub) chatReadLoop(userID, friendID int64, conn *websocket.Con
 			return
 		}
 
+		// A block placed mid-conversation ends the chat
+		if h.isBlocked(context.Background(), userID, friendID) {
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "not friends"))
+			return
+		}
+
 		msg := DirectMessage{
 			From:      userID,
 			To:        friendID,
@@ -294,11 +329,34 @@ func (h *DirectChatHub) chatReadLoop(userID, friendID int64, conn *websocket.Con
 func (h *DirectChatHub) dispatchMessage(msg DirectMessage) {
 	// Send to friend if connected
 	if friendConn := h.getChatConn(msg.To, msg.From); friendConn != nil {
-		_ = friendConn.WriteJSON(msg)
+		_ = writeJSON(friendConn, msg)
 	}
 	// Echo back to sender
 	if senderConn := h.getChatConn(msg.From, msg.To); senderConn != nil {
-		_ = senderConn.WriteJSON(msg)
+		_ = writeJSON(senderConn, msg)
+	}
+}
+
+// writeJSON sends v, giving up after writeWait so a stalled peer cannot block
+// the sender
+func writeJSON(conn *websocket.Conn, v any) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(v)
+}
+
+// keepAlive pings the peer every pingPeriod until stop is closed
+func keepAlive(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
 	}
 }
 
@@ -307,6 +365,9 @@ func (h *DirectChatHub) dispatchMessage(msg DirectMessage) {
 // -----------------------
 
 func (h *DirectChatHub) areFriends(ctx context.Context, userID, friendID int64) bool {
+	if h.isBlocked(ctx, userID, friendID) {
+		return false
+	}
 	ok, err := h.friendRepo.AreFriends(ctx, userID, friendID)
 	if err != nil {
 		log.Printf("[chat] friend check failed user_id=%d friend_id=%d err=%v", userID, friendID, err)
@@ -314,3 +375,30 @@ func (h *DirectChatHub) areFriends(ctx context.Context, userID, friendID int64)
 	}
 	return ok
 }
+
+func (h *DirectChatHub) isBlocked(ctx context.Context, userID, friendID int64) bool {
+	blocked, err := h.friendRepo.IsBlocked(ctx, userID, friendID)
+	if err != nil {
+		log.Printf("[chat] block check failed user_id=%d friend_id=%d err=%v", userID, friendID, err)
+		return true
+	}
+	return blocked
+}
+
+// DisconnectUser closes every connection of the user with the given reason.
+// The read loops then unregister them and friends receive a presence:update
+// without the user.
+func (h *DirectChatHub) DisconnectUser(userID int64, reason string) {
+	h.mu.RLock()
+	conns := make([]*websocket.Conn, 0, len(h.presence[userID]))
+	for _, conn := range h.presence[userID] {
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason)
+	for _, conn := range conns {
+		_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+		_ = conn.Close()
+	}
+}
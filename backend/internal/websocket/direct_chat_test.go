@@ -0,0 +1,66 @@
+package websocket
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	_ "modernc.org/sqlite"
+)
+
+func TestDisconnectUserClosesPresenceConnections(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hub := NewDirectChatHub(db)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+		hub.HandleWS(w, r, userID)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?user_id=2", nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !hub.IsUserOnline(2) {
+		if time.Now().After(deadline) {
+			t.Fatal("presence connection was never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	hub.DisconnectUser(2, "account deleted")
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue // presence updates
+		}
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) || closeErr.Text != "account deleted" {
+			t.Fatalf("expected a close frame with the reason, got %v", err)
+		}
+		break
+	}
+
+	for hub.IsUserOnline(2) {
+		if time.Now().After(deadline) {
+			t.Fatal("disconnected user is still online")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
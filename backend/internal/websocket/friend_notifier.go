@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+)
+
+// Friend events pushed on presence connections, next to presence:update
+const (
+	friendRequestEvent  = "friend:request"
+	friendAcceptedEvent = "friend:accepted"
+)
+
+// DirectChatHub implements friend.Notifier by pushing to presence connections
+var _ friend.Notifier = (*DirectChatHub)(nil)
+
+// FriendRequestSent tells the target's open presence connections about a new
+// friend request. Offline users see the request the next time they list them.
+func (h *DirectChatHub) FriendRequestSent(ctx context.Context, request *friend.FriendRequest, requesterUsername string) error {
+	return h.pushToUser(request.ToUserID, map[string]any{
+		"type":          friendRequestEvent,
+		"request_id":    request.ID,
+		"from_user_id":  request.FromUserID,
+		"from_username": requesterUsername,
+		"timestamp":     time.Now().Unix(),
+	})
+}
+
+// FriendRequestAccepted tells the requester that the request was accepted and
+// refreshes presence so the new friends see each other online.
+func (h *DirectChatHub) FriendRequestAccepted(ctx context.Context, request *friend.FriendRequest, accepterUsername string) error {
+	err := h.pushToUser(request.FromUserID, map[string]any{
+		"type":       friendAcceptedEvent,
+		"request_id": request.ID,
+		"user_id":    request.ToUserID,
+		"username":   accepterUsername,
+		"timestamp":  time.Now().Unix(),
+	})
+	h.broadcastPresence()
+	return err
+}
+
+// pushToUser writes v to every presence connection of the user and returns
+// the last write error
+func (h *DirectChatHub) pushToUser(userID int64, v any) error {
+	h.mu.RLock()
+	conns := make([]*websocket.Conn, 0, len(h.presence[userID]))
+	for _, conn := range h.presence[userID] {
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+
+	var lastErr error
+	for _, conn := range conns {
+		if err := writeJSON(conn, v); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+)
+
+func TestFriendRequestPushedToTargetPresenceConnection(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hub := NewDirectChatHub(db)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+		hub.HandleWS(w, r, userID)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?user_id=2", nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !hub.IsUserOnline(2) {
+		if time.Now().After(deadline) {
+			t.Fatal("presence connection was never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	request := &friend.FriendRequest{ID: 9, FromUserID: 1, ToUserID: 2, Status: "pending"}
+	if err := hub.FriendRequestSent(context.Background(), request, "alice"); err != nil {
+		t.Fatalf("FriendRequestSent returned error: %v", err)
+	}
+	// Nobody else is connected, so this push is simply dropped
+	if err := hub.FriendRequestSent(context.Background(), &friend.FriendRequest{ID: 10, FromUserID: 2, ToUserID: 3}, "bob"); err != nil {
+		t.Fatalf("FriendRequestSent to an offline user returned error: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var event map[string]interface{}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("expected a friend request event: %v", err)
+		}
+		if event["type"] != friendRequestEvent {
+			continue // presence updates
+		}
+		if event["request_id"] != float64(9) || event["from_user_id"] != float64(1) || event["from_username"] != "alice" {
+			t.Fatalf("unexpected friend request event %v", event)
+		}
+		return
+	}
+}
@@ -0,0 +1,28 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/history"
+)
+
+// goalCompletedEvent is pushed on presence connections when a goal completes
+const goalCompletedEvent = "goal:completed"
+
+// DirectChatHub implements history.GoalNotifier by pushing to presence connections
+var _ history.GoalNotifier = (*DirectChatHub)(nil)
+
+// GoalCompleted tells the user's open presence connections about a completed
+// goal. Offline users find it in their notifications.
+func (h *DirectChatHub) GoalCompleted(ctx context.Context, goal *history.ReadingGoal) error {
+	return h.pushToUser(goal.UserID, map[string]any{
+		"type":         goalCompletedEvent,
+		"goal_id":      goal.GoalID,
+		"goal_type":    goal.GoalType,
+		"period_type":  goal.PeriodType,
+		"target_value": goal.TargetValue,
+		"message":      goal.CompletionMessage(),
+		"timestamp":    time.Now().Unix(),
+	})
+}
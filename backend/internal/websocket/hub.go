@@ -10,6 +10,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+	"github.com/ngocan-dev/mangahub/backend/domain/room"
 	"github.com/ngocan-dev/mangahub/backend/internal/auth"
 	"github.com/ngocan-dev/mangahub/backend/internal/security"
 )
@@ -40,11 +43,52 @@ type Hub struct {
 	// Database connection
 	db *sql.DB
 
+	// Friendship and block lookups for direct messages
+	friends FriendChecker
+	blocks  BlockChecker
+
+	// Connected clients by user, used for presence
+	presence map[int64]map[*Client]bool
+
+	// upgrader rejects cross-origin upgrades with 403; see SetAllowedOrigins
+	upgrader websocket.Upgrader
+
+	// Per-user connection cap (0 means unlimited) and the counter ordering
+	// connections so the oldest can be evicted
+	maxPerUser int
+	connSeq    uint64
+
+	// Friend lookup used to notify friends about presence changes
+	friendLister FriendLister
+
+	// Membership lookup for private rooms
+	roomAccess RoomAccessChecker
+
+	// Heartbeat timing, see SetHeartbeat
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+
 	// Mutex for thread-safe operations
 	mu sync.RWMutex
 }
 
+// FriendChecker reports whether two users are friends
+type FriendChecker interface {
+	AreFriends(ctx context.Context, userID, friendID int64) (bool, error)
+}
+
+// BlockChecker reports whether either user has blocked the other
+type BlockChecker interface {
+	IsBlocked(ctx context.Context, userA, userB int64) (bool, error)
+}
+
+// RoomAccessChecker reports whether a user may join a room
+type RoomAccessChecker interface {
+	CanJoin(ctx context.Context, roomID, userID int64) (bool, error)
+}
+
 // HubStatus provides runtime metrics for the WebSocket hub.
+// It is the /status payload the API server's status check decodes.
 type HubStatus struct {
 	Running bool   `json:"running"`
 	Clients int    `json:"clients"`
@@ -52,20 +96,50 @@ type HubStatus struct {
 	Uptime  string `json:"uptime"`
 }
 
+// HubStats counts the hub's live connections and occupied rooms
+type HubStats struct {
+	Clients int
+	Rooms   int
+}
+
 // NewHub creates a new hub instance
 // TCP and WebSocket connections remain stable
 func NewHub(db *sql.DB) *Hub {
+	friendRepo := friend.NewRepository(db)
 	return &Hub{
-		rooms:      make(map[int64]map[*Client]bool),
-		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte, 1000), // Increased buffer for 50-100 concurrent users
-		register:   make(chan *Client, 100), // Buffered channels to prevent blocking
-		unregister: make(chan *Client, 100),
-		db:         db,
-		startedAt:  time.Now(),
+		rooms:        make(map[int64]map[*Client]bool),
+		clients:      make(map[*Client]bool),
+		broadcast:    make(chan []byte, 1000), // Increased buffer for 50-100 concurrent users
+		register:     make(chan *Client, 100), // Buffered channels to prevent blocking
+		unregister:   make(chan *Client, 100),
+		db:           db,
+		friends:      friendRepo,
+		blocks:       friendRepo,
+		presence:     make(map[int64]map[*Client]bool),
+		maxPerUser:   DefaultMaxConnectionsPerUser,
+		friendLister: friendRepo,
+		roomAccess:   room.NewRepository(db),
+		pingInterval: DefaultPingInterval,
+		pongTimeout:  DefaultPongTimeout,
+		startedAt:    time.Now(),
 	}
 }
 
+// SetFriendChecker overrides the friendship lookup used for direct messages
+func (h *Hub) SetFriendChecker(checker FriendChecker) {
+	h.friends = checker
+}
+
+// SetBlockChecker overrides the block lookup used for direct messages
+func (h *Hub) SetBlockChecker(checker BlockChecker) {
+	h.blocks = checker
+}
+
+// SetRoomAccessChecker overrides the membership lookup used for private rooms
+func (h *Hub) SetRoomAccessChecker(checker RoomAccessChecker) {
+	h.roomAccess = checker
+}
+
 // Run starts the hub
 func (h *Hub) Run(ctx context.Context) {
 	h.running.Store(true)
@@ -102,6 +176,18 @@ func (h *Hub) handleMessage(client *Client, msg *Message) {
 		h.handleReconnect(client, msg)
 	case MessageTypeMessage:
 		h.handleChatMessage(client, msg)
+	case MessageTypeEdit:
+		h.handleEditMessage(client, msg)
+	case MessageTypeDelete:
+		h.handleDeleteMessage(client, msg)
+	case MessageTypeDirect:
+		h.handleDirectMessage(client, msg)
+	case MessageTypeLoadMore:
+		h.handleLoadMore(client, msg)
+	case MessageTypeSearch:
+		h.handleSearch(client, msg)
+	case MessageTypeRead:
+		h.handleRead(client, msg)
 	case MessageTypeLeave:
 		h.handleLeave(client)
 	default:
@@ -147,6 +233,9 @@ func (h *Hub) handleJoin(client *Client, msg *Message) {
 		if errors.Is(err, auth.ErrExpiredToken) {
 			// Expired tokens trigger reauthentication
 			client.SendError("token_expired", "your session has expired. please login again")
+		} else if errors.Is(err, auth.ErrTokenRevoked) {
+			// Revoked tokens (logout) are rejected
+			client.SendError("token_revoked", "this token has been revoked. please login again")
 		} else if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrInvalidSigningMethod) {
 			// Invalid tokens are rejected
 			client.SendError("auth_failed", "invalid token")
@@ -170,13 +259,18 @@ func (h *Hub) handleJoin(client *Client, msg *Message) {
 		client.SendError("room_not_found", "room not found")
 		return
 	}
+	if !h.authorizeRoom(client, roomID, claims.UserID) {
+		return
+	}
 
 	// Set user and room
 	client.SetUser(claims.UserID, claims.Username)
 	client.SetRoom(roomID)
 
 	// Step 4: Add to active connections
-	h.addClient(client, roomID)
+	if h.addClient(client, roomID) {
+		h.notifyFriendsPresence(claims.UserID, claims.Username, true)
+	}
 
 	// Step 6: Send recent chat history
 	history, err := h.getChatHistory(context.Background(), roomID, 50)
@@ -188,6 +282,12 @@ func (h *Hub) handleJoin(client *Client, msg *Message) {
 		client.SendMessage(historyMsg)
 	}
 
+	// Deliver direct messages received while offline
+	h.deliverPendingDirectMessages(client)
+
+	// Let the new participant know how far everyone has read
+	h.sendReadReceipts(client, roomID)
+
 	// Send join confirmation
 	joinResp := &Message{
 		Type: MessageTypeJoined,
@@ -220,6 +320,25 @@ func (h *Hub) handleJoin(client *Client, msg *Message) {
 	log.Printf("User joined: UserID=%d, Username=%s, RoomID=%d", claims.UserID, claims.Username, roomID)
 }
 
+// authorizeRoom keeps non-members out of private rooms.
+// It reports the problem to the client and returns false when access is denied.
+func (h *Hub) authorizeRoom(client *Client, roomID, userID int64) bool {
+	if h.roomAccess == nil {
+		return true
+	}
+	allowed, err := h.roomAccess.CanJoin(context.Background(), roomID, userID)
+	if err != nil {
+		log.Printf("Error checking room access: UserID=%d, RoomID=%d, err=%v", userID, roomID, err)
+		client.SendError("database_error", "failed to verify room access")
+		return false
+	}
+	if !allowed {
+		client.SendError("room_forbidden", "you are not a member of this room")
+		return false
+	}
+	return true
+}
+
 // handleReconnect allows a client to resume a session and retrieve missed messages
 func (h *Hub) handleReconnect(client *Client, msg *Message) {
 	// Parse reconnect request
@@ -255,10 +374,15 @@ func (h *Hub) handleReconnect(client *Client, msg *Message) {
 		client.SendError("room_not_found", "room not found")
 		return
 	}
+	if !h.authorizeRoom(client, roomID, claims.UserID) {
+		return
+	}
 
 	client.SetUser(claims.UserID, claims.Username)
 	client.SetRoom(roomID)
-	h.addClient(client, roomID)
+	if h.addClient(client, roomID) {
+		h.notifyFriendsPresence(claims.UserID, claims.Username, true)
+	}
 
 	limit := req.Limit
 	if limit <= 0 {
@@ -273,6 +397,9 @@ func (h *Hub) handleReconnect(client *Client, msg *Message) {
 		client.SendMessage(historyMsg)
 	}
 
+	h.deliverPendingDirectMessages(client)
+	h.sendReadReceipts(client, roomID)
+
 	reconnectResp := &Message{
 		Type: MessageTypeReconnected,
 		Payload: ReconnectResponse{
@@ -325,6 +452,10 @@ func (h *Hub) handleChatMessage(client *Client, msg *Message) {
 		return
 	}
 
+	if !client.throttleSend() {
+		return
+	}
+
 	// Parse message payload
 	payloadBytes, err := json.Marshal(msg.Payload)
 	if err != nil {
@@ -340,33 +471,11 @@ func (h *Hub) handleChatMessage(client *Client, msg *Message) {
 		return
 	}
 
-	// A1: Message too long - Server returns error to sender
-	// Input length limits are enforced
-	// XSS attempts are sanitized
-	if err := security.ValidateLength(chatMsg.Content, 1, security.MaxMessageLength); err != nil {
-		if errors.Is(err, security.ErrInputTooShort) {
-			client.SendError("empty_message", "message cannot be empty")
-			return
-		}
-		if errors.Is(err, security.ErrInputTooLong) {
-			client.SendError("message_too_long", "message exceeds maximum length")
-			return
-		}
-		client.SendError("invalid_message", "invalid message format")
-		return
-	}
-
-	// Check for SQL injection and XSS
-	// SQL injection attempts are blocked
-	if err := security.DetectSQLInjection(chatMsg.Content); err != nil {
-		client.SendError("invalid_message", "message contains invalid content")
+	sanitizedContent, ok := validateMessageContent(client, chatMsg.Content)
+	if !ok {
 		return
 	}
 
-	// Sanitize message content to prevent XSS
-	// XSS attempts are sanitized
-	sanitizedContent := security.SanitizeString(chatMsg.Content)
-
 	// Save message to database (use sanitized content)
 	messageID, err := h.saveMessage(context.Background(), roomID, userID, sanitizedContent)
 	if err != nil {
@@ -397,6 +506,32 @@ func (h *Hub) handleChatMessage(client *Client, msg *Message) {
 		userID, username, roomID, messageID)
 }
 
+// validateMessageContent enforces length limits and sanitizes chat content.
+// It reports the problem to the client and returns false when the content is rejected.
+func validateMessageContent(client *Client, content string) (string, bool) {
+	// A1: Message too long - Server returns error to sender
+	// Input length limits are enforced
+	// XSS attempts are sanitized
+	// SQL injection attempts are blocked
+	if err := security.ValidateMessage(content); err != nil {
+		switch {
+		case errors.Is(err, security.ErrInputTooShort):
+			client.SendError("empty_message", "message cannot be empty")
+		case errors.Is(err, security.ErrInputTooLong):
+			client.SendError("message_too_long", "message exceeds maximum length")
+		case errors.Is(err, security.ErrContainsSQLInjection):
+			client.SendError("invalid_message", "message contains invalid content")
+		default:
+			client.SendError("invalid_message", "invalid message format")
+		}
+		return "", false
+	}
+
+	// Sanitize message content to prevent XSS
+	// XSS attempts are sanitized
+	return security.SanitizeString(content), true
+}
+
 // handleLeave handles explicit client leave request (user sends leave message)
 func (h *Hub) handleLeave(client *Client) {
 	userID := client.GetUserID()
@@ -420,25 +555,42 @@ func (h *Hub) handleLeave(client *Client) {
 		h.broadcastUserList(roomID)
 
 		// Remove client after broadcasting
-		h.removeClient(client)
+		if h.removeClient(client) {
+			h.notifyFriendsPresence(userID, username, false)
+		}
 
 		log.Printf("User left: UserID=%d, Username=%s, RoomID=%d", userID, username, roomID)
 	}
 }
 
-// addClient adds a client to the hub
-func (h *Hub) addClient(client *Client, roomID int64) {
+// addClient adds a client to the hub.
+// It reports whether this is the user's first connection. When the user now
+// holds more than the per-user cap, their oldest connections are closed.
+func (h *Hub) addClient(client *Client, roomID int64) bool {
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	// Add to all clients
 	h.clients[client] = true
+	if client.seq == 0 {
+		h.connSeq++
+		client.seq = h.connSeq
+	}
 
 	// Add to room
 	if h.rooms[roomID] == nil {
 		h.rooms[roomID] = make(map[*Client]bool)
 	}
 	h.rooms[roomID][client] = true
+
+	first := h.trackPresence(client)
+	evicted := h.evictOverLimit(client.GetUserID())
+	h.mu.Unlock()
+
+	for _, old := range evicted {
+		log.Printf("Evicting oldest connection of UserID=%d: connection limit reached", old.GetUserID())
+		old.closeWithReason("connection limit reached")
+	}
+	return first
 }
 
 // handleClientDisconnect handles client disconnection
@@ -472,14 +624,17 @@ func (h *Hub) handleClientDisconnect(client *Client) {
 	}
 
 	// Step 2: Remove connection from active list
-	h.removeClient(client)
+	if h.removeClient(client) {
+		h.notifyFriendsPresence(userID, username, false)
+	}
 
 	// Step 5: Connection resources are cleaned up (handled by defer in ReadPump)
 	log.Printf("Client disconnected: UserID=%d, Username=%s, RoomID=%d", userID, username, roomID)
 }
 
-// removeClient removes a client from the hub (internal, called after broadcasting)
-func (h *Hub) removeClient(client *Client) {
+// removeClient removes a client from the hub (internal, called after broadcasting).
+// It reports whether the user has no connections left.
+func (h *Hub) removeClient(client *Client) bool {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -494,19 +649,31 @@ func (h *Hub) removeClient(client *Client) {
 			delete(h.rooms, roomID)
 		}
 	}
+
+	return h.untrackPresence(client)
 }
 
-// Status returns live metrics about the hub without leaking internal maps.
-func (h *Hub) Status() HubStatus {
+// Stats returns the number of connected clients across all rooms and the
+// number of rooms with at least one client.
+func (h *Hub) Stats() HubStats {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	return HubStats{
+		Clients: len(h.clients),
+		Rooms:   len(h.rooms),
+	}
+}
+
+// Status returns live metrics about the hub without leaking internal maps.
+func (h *Hub) Status() HubStatus {
+	stats := h.Stats()
 	uptime := time.Since(h.startedAt).Round(time.Second)
 
 	return HubStatus{
 		Running: h.running.Load(),
-		Clients: len(h.clients),
-		Rooms:   len(h.rooms),
+		Clients: stats.Clients,
+		Rooms:   stats.Rooms,
 		Uptime:  uptime.String(),
 	}
 }
@@ -697,10 +864,11 @@ func (h *Hub) getChatHistory(ctx context.Context, roomID int64, limit int) (*His
 			cm.User_Id,
 			u.Username,
 			cm.Content,
-			cm.Created_At
+			cm.Created_At,
+			cm.Edited_At
 		FROM Chat_Messages cm
 		JOIN Users u ON cm.User_Id = u.UserId
-		WHERE cm.Room_Id = ?
+		WHERE cm.Room_Id = ? AND cm.Deleted_At IS NULL
 		ORDER BY cm.Created_At DESC
 		LIMIT ?
 	`, roomID, limit)
@@ -713,12 +881,16 @@ func (h *Hub) getChatHistory(ctx context.Context, roomID int64, limit int) (*His
 	for rows.Next() {
 		var msg ChatMessage
 		var createdAt time.Time
-		err := rows.Scan(&msg.MessageID, &msg.UserID, &msg.Username, &msg.Content, &createdAt)
+		var editedAt sql.NullTime
+		err := rows.Scan(&msg.MessageID, &msg.UserID, &msg.Username, &msg.Content, &createdAt, &editedAt)
 		if err != nil {
 			continue
 		}
 		msg.RoomID = roomID
 		msg.Timestamp = FormatTimestamp(createdAt)
+		if editedAt.Valid {
+			msg.EditedAt = FormatTimestamp(editedAt.Time)
+		}
 		messages = append(messages, msg)
 	}
 
@@ -746,10 +918,11 @@ cm.Message_Id,
 cm.User_Id,
 u.Username,
 cm.Content,
-cm.Created_At
+cm.Created_At,
+cm.Edited_At
 FROM Chat_Messages cm
 JOIN Users u ON cm.User_Id = u.UserId
-WHERE cm.Room_Id = ? AND cm.Message_Id > ?
+WHERE cm.Room_Id = ? AND cm.Message_Id > ? AND cm.Deleted_At IS NULL
 ORDER BY cm.Created_At ASC
 LIMIT ?
 `, roomID, lastMessageID, limit)
@@ -762,11 +935,15 @@ LIMIT ?
 	for rows.Next() {
 		var msg ChatMessage
 		var createdAt time.Time
-		if err := rows.Scan(&msg.MessageID, &msg.UserID, &msg.Username, &msg.Content, &createdAt); err != nil {
+		var editedAt sql.NullTime
+		if err := rows.Scan(&msg.MessageID, &msg.UserID, &msg.Username, &msg.Content, &createdAt, &editedAt); err != nil {
 			continue
 		}
 		msg.RoomID = roomID
 		msg.Timestamp = FormatTimestamp(createdAt)
+		if editedAt.Valid {
+			msg.EditedAt = FormatTimestamp(editedAt.Time)
+		}
 		messages = append(messages, msg)
 	}
 
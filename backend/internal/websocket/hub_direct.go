@@ -0,0 +1,222 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// handleDirectMessage routes a private message to every connected device of the recipient.
+// Messages are persisted first so recipients who are offline receive them on their next join.
+func (h *Hub) handleDirectMessage(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	if !client.throttleSend() {
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req DirectMessageRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil {
+		client.SendError("invalid_request", "invalid direct message payload")
+		return
+	}
+	if req.ToUserID <= 0 {
+		client.SendError("invalid_recipient", "to_user_id is required")
+		return
+	}
+	if req.ToUserID == userID {
+		client.SendError("invalid_recipient", "cannot message yourself")
+		return
+	}
+
+	content, ok := validateMessageContent(client, req.Content)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if h.blocks != nil {
+		blocked, err := h.blocks.IsBlocked(ctx, userID, req.ToUserID)
+		if err != nil {
+			log.Printf("Error checking block: UserID=%d, ToUserID=%d, err=%v", userID, req.ToUserID, err)
+			client.SendError("database_error", "failed to verify friendship")
+			return
+		}
+		if blocked {
+			// Dropped without revealing the block to either side
+			client.SendError("not_friends", "you can only message friends")
+			return
+		}
+	}
+
+	areFriends, err := h.friends.AreFriends(ctx, userID, req.ToUserID)
+	if err != nil {
+		log.Printf("Error checking friendship: UserID=%d, ToUserID=%d, err=%v", userID, req.ToUserID, err)
+		client.SendError("database_error", "failed to verify friendship")
+		return
+	}
+	if !areFriends {
+		client.SendError("not_friends", "you can only message friends")
+		return
+	}
+
+	now := time.Now()
+	messageID, err := h.saveDirectMessage(ctx, userID, req.ToUserID, content, now)
+	if err != nil {
+		log.Printf("Error saving direct message to database: %v", err)
+		client.SendError("database_error", "failed to save message")
+		return
+	}
+
+	directMsg := &Message{
+		Type: MessageTypeDirect,
+		Payload: DirectChatMessage{
+			MessageID:    messageID,
+			FromUserID:   userID,
+			FromUsername: client.GetUsername(),
+			ToUserID:     req.ToUserID,
+			Content:      content,
+			Timestamp:    FormatTimestamp(now),
+		},
+	}
+
+	delivered := h.sendToUser(req.ToUserID, directMsg)
+	if delivered > 0 {
+		if err := h.markDirectMessagesDelivered(ctx, []int64{messageID}); err != nil {
+			log.Printf("Error marking direct message delivered: MessageID=%d, err=%v", messageID, err)
+		}
+	}
+
+	// Echo to all of the sender's devices so their conversations stay in sync
+	h.sendToUser(userID, directMsg)
+
+	log.Printf("Direct message sent: FromUserID=%d, ToUserID=%d, MessageID=%d, Delivered to %d clients",
+		userID, req.ToUserID, messageID, delivered)
+}
+
+// sendToUser queues a message on every connected client of a user and returns how many accepted it
+func (h *Hub) sendToUser(userID int64, msg *Message) int {
+	data, err := SerializeMessage(msg)
+	if err != nil {
+		log.Printf("Error serializing message: %v", err)
+		return 0
+	}
+
+	h.mu.RLock()
+	clients := make([]*Client, 0)
+	for client := range h.clients {
+		if client.GetUserID() == userID {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	sent := 0
+	for _, client := range clients {
+		select {
+		case client.send <- data:
+			sent++
+		default:
+			// Client send buffer full
+			log.Printf("Client send buffer full, dropping message")
+		}
+	}
+	return sent
+}
+
+// deliverPendingDirectMessages sends direct messages that arrived while the user was offline
+func (h *Hub) deliverPendingDirectMessages(client *Client) {
+	ctx := context.Background()
+	userID := client.GetUserID()
+
+	pending, err := h.getUndeliveredDirectMessages(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading pending direct messages: UserID=%d, err=%v", userID, err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	client.SendMessage(&Message{
+		Type:    MessageTypeDirectHistory,
+		Payload: DirectHistoryResponse{Messages: pending},
+	})
+
+	ids := make([]int64, 0, len(pending))
+	for _, m := range pending {
+		ids = append(ids, m.MessageID)
+	}
+	if err := h.markDirectMessagesDelivered(ctx, ids); err != nil {
+		log.Printf("Error marking direct messages delivered: UserID=%d, err=%v", userID, err)
+	}
+}
+
+// saveDirectMessage saves a direct message to the database
+func (h *Hub) saveDirectMessage(ctx context.Context, fromUserID, toUserID int64, content string, createdAt time.Time) (int64, error) {
+	result, err := h.db.ExecContext(ctx, `
+		INSERT INTO Direct_Messages (Sender_Id, Recipient_Id, Content, Created_At)
+		VALUES (?, ?, ?, ?)
+	`, fromUserID, toUserID, content, createdAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// getUndeliveredDirectMessages returns a user's undelivered direct messages in chronological order
+func (h *Hub) getUndeliveredDirectMessages(ctx context.Context, userID int64) ([]DirectChatMessage, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT
+			dm.Message_Id,
+			dm.Sender_Id,
+			COALESCE(u.username, ''),
+			dm.Recipient_Id,
+			dm.Content,
+			dm.Created_At
+		FROM Direct_Messages dm
+		LEFT JOIN users u ON u.id = dm.Sender_Id
+		WHERE dm.Recipient_Id = ? AND dm.Delivered_At IS NULL
+		ORDER BY dm.Message_Id ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]DirectChatMessage, 0)
+	for rows.Next() {
+		var msg DirectChatMessage
+		var createdAt time.Time
+		if err := rows.Scan(&msg.MessageID, &msg.FromUserID, &msg.FromUsername, &msg.ToUserID, &msg.Content, &createdAt); err != nil {
+			return nil, err
+		}
+		msg.Timestamp = FormatTimestamp(createdAt)
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// markDirectMessagesDelivered records delivery time for the given messages
+func (h *Hub) markDirectMessagesDelivered(ctx context.Context, messageIDs []int64) error {
+	now := time.Now().UTC()
+	for _, id := range messageIDs {
+		if _, err := h.db.ExecContext(ctx, `
+			UPDATE Direct_Messages SET Delivered_At = ? WHERE Message_Id = ? AND Delivered_At IS NULL
+		`, now, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
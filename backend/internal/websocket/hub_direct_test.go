@@ -0,0 +1,173 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+type staticFriends map[[2]int64]bool
+
+func (f staticFriends) AreFriends(ctx context.Context, userID, friendID int64) (bool, error) {
+	return f[[2]int64{userID, friendID}] || f[[2]int64{friendID, userID}], nil
+}
+
+type staticBlocks map[[2]int64]bool
+
+func (b staticBlocks) IsBlocked(ctx context.Context, userA, userB int64) (bool, error) {
+	return b[[2]int64{userA, userB}] || b[[2]int64{userB, userA}], nil
+}
+
+func setupDirectHub(t *testing.T, friends staticFriends) (*Hub, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT NOT NULL);
+		CREATE TABLE Direct_Messages (
+			Message_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+			Sender_Id INTEGER NOT NULL,
+			Recipient_Id INTEGER NOT NULL,
+			Content TEXT NOT NULL,
+			Created_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			Delivered_At DATETIME
+		);
+		INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+	`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	hub := NewHub(db)
+	hub.SetFriendChecker(friends)
+	hub.SetBlockChecker(staticBlocks{})
+	return hub, db
+}
+
+func connectedClient(hub *Hub, userID int64, username string) *Client {
+	client := NewClient(hub, nil)
+	client.SetUser(userID, username)
+	hub.addClient(client, 1)
+	return client
+}
+
+func readMessage(t *testing.T, client *Client) (MessageType, map[string]interface{}) {
+	t.Helper()
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    MessageType            `json:"type"`
+			Payload map[string]interface{} `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+		return msg.Type, msg.Payload
+	default:
+		t.Fatalf("expected a message for user %d", client.GetUserID())
+		return "", nil
+	}
+}
+
+func TestDirectMessageRejectedBetweenNonFriends(t *testing.T) {
+	hub, db := setupDirectHub(t, staticFriends{})
+	alice := connectedClient(hub, 1, "alice")
+	carol := connectedClient(hub, 3, "carol")
+
+	hub.handleDirectMessage(alice, &Message{Type: MessageTypeDirect, Payload: DirectMessageRequest{ToUserID: 3, Content: "hi"}})
+
+	msgType, payload := readMessage(t, alice)
+	if msgType != MessageTypeError || payload["code"] != "not_friends" {
+		t.Fatalf("expected not_friends error, got %s %v", msgType, payload)
+	}
+	if len(carol.send) != 0 {
+		t.Fatalf("non-friend must not receive the message")
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM Direct_Messages`).Scan(&count); err != nil {
+		t.Fatalf("failed to count messages: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("rejected message must not be persisted, found %d", count)
+	}
+}
+
+func TestDirectMessageDroppedWhenBlocked(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{{1, 2}: true})
+	hub.SetBlockChecker(staticBlocks{{2, 1}: true})
+	alice := connectedClient(hub, 1, "alice")
+	bob := connectedClient(hub, 2, "bob")
+
+	hub.handleDirectMessage(alice, &Message{Type: MessageTypeDirect, Payload: DirectMessageRequest{ToUserID: 2, Content: "hello?"}})
+
+	msgType, payload := readMessage(t, alice)
+	if msgType != MessageTypeError || payload["code"] != "not_friends" {
+		t.Fatalf("expected not_friends error, got %s %v", msgType, payload)
+	}
+	if len(bob.send) != 0 {
+		t.Fatalf("blocked sender's message must not be delivered")
+	}
+}
+
+func TestDirectMessageFansOutToAllRecipientDevices(t *testing.T) {
+	hub, db := setupDirectHub(t, staticFriends{{1, 2}: true})
+	alice := connectedClient(hub, 1, "alice")
+	bobPhone := connectedClient(hub, 2, "bob")
+	bobLaptop := connectedClient(hub, 2, "bob")
+	carol := connectedClient(hub, 3, "carol")
+
+	hub.handleDirectMessage(alice, &Message{Type: MessageTypeDirect, Payload: DirectMessageRequest{ToUserID: 2, Content: "new chapter is out"}})
+
+	for _, client := range []*Client{bobPhone, bobLaptop, alice} {
+		msgType, payload := readMessage(t, client)
+		if msgType != MessageTypeDirect || payload["from_user_id"] != float64(1) || payload["to_user_id"] != float64(2) {
+			t.Fatalf("expected direct message, got %s %v", msgType, payload)
+		}
+	}
+	if len(carol.send) != 0 {
+		t.Fatalf("unrelated user must not receive the message")
+	}
+
+	var undelivered int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM Direct_Messages WHERE Delivered_At IS NULL`).Scan(&undelivered); err != nil {
+		t.Fatalf("failed to count messages: %v", err)
+	}
+	if undelivered != 0 {
+		t.Fatalf("expected message to be marked delivered")
+	}
+}
+
+func TestDirectMessageQueuedForOfflineRecipient(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{{1, 2}: true})
+	alice := connectedClient(hub, 1, "alice")
+
+	hub.handleDirectMessage(alice, &Message{Type: MessageTypeDirect, Payload: DirectMessageRequest{ToUserID: 2, Content: "are you there?"}})
+	readMessage(t, alice)
+
+	bob := connectedClient(hub, 2, "bob")
+	hub.deliverPendingDirectMessages(bob)
+
+	msgType, payload := readMessage(t, bob)
+	if msgType != MessageTypeDirectHistory {
+		t.Fatalf("expected direct_history, got %s", msgType)
+	}
+	messages, _ := payload["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 pending message, got %v", payload)
+	}
+
+	// Pending messages are delivered only once
+	hub.deliverPendingDirectMessages(bob)
+	if len(bob.send) != 0 {
+		t.Fatalf("expected no redelivery of pending messages")
+	}
+}
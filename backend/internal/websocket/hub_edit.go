@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+)
+
+// messageEditWindow is how long after sending an author may still edit a message
+const messageEditWindow = 15 * time.Minute
+
+// storedMessage is the ownership data needed to edit or delete a chat message
+type storedMessage struct {
+	RoomID    int64
+	UserID    int64
+	CreatedAt time.Time
+}
+
+// handleEditMessage lets the author replace the content of a recent message
+// and tells the room to re-render it
+func (h *Hub) handleEditMessage(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req EditMessageRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || req.MessageID <= 0 {
+		client.SendError("invalid_request", "invalid edit payload")
+		return
+	}
+
+	content, ok := validateMessageContent(client, req.Content)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	stored, ok := h.loadOwnedMessage(ctx, client, req.MessageID)
+	if !ok {
+		return
+	}
+	if time.Since(stored.CreatedAt) > messageEditWindow {
+		client.SendError("edit_window_expired", "messages can only be edited within 15 minutes of sending")
+		return
+	}
+
+	editedAt := time.Now()
+	if _, err := h.db.ExecContext(ctx, `
+		UPDATE Chat_Messages SET Content = ?, Edited_At = ? WHERE Message_Id = ?
+	`, content, editedAt, req.MessageID); err != nil {
+		log.Printf("Error editing message: MessageID=%d, err=%v", req.MessageID, err)
+		client.SendError("database_error", "failed to edit message")
+		return
+	}
+
+	h.broadcastToRoom(stored.RoomID, &Message{
+		Type: MessageTypeEdited,
+		Payload: MessageEditedNotification{
+			MessageID: req.MessageID,
+			RoomID:    stored.RoomID,
+			UserID:    userID,
+			Content:   content,
+			EditedAt:  FormatTimestamp(editedAt),
+		},
+	})
+
+	log.Printf("Message edited: UserID=%d, RoomID=%d, MessageID=%d", userID, stored.RoomID, req.MessageID)
+}
+
+// handleDeleteMessage soft-deletes one of the author's messages and tells the room to remove it
+func (h *Hub) handleDeleteMessage(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req DeleteMessageRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || req.MessageID <= 0 {
+		client.SendError("invalid_request", "invalid delete payload")
+		return
+	}
+
+	ctx := context.Background()
+	stored, ok := h.loadOwnedMessage(ctx, client, req.MessageID)
+	if !ok {
+		return
+	}
+
+	deletedAt := time.Now()
+	if _, err := h.db.ExecContext(ctx, `
+		UPDATE Chat_Messages SET Deleted_At = ? WHERE Message_Id = ?
+	`, deletedAt, req.MessageID); err != nil {
+		log.Printf("Error deleting message: MessageID=%d, err=%v", req.MessageID, err)
+		client.SendError("database_error", "failed to delete message")
+		return
+	}
+
+	h.broadcastToRoom(stored.RoomID, &Message{
+		Type: MessageTypeDeleted,
+		Payload: MessageDeletedNotification{
+			MessageID: req.MessageID,
+			RoomID:    stored.RoomID,
+			UserID:    userID,
+			DeletedAt: FormatTimestamp(deletedAt),
+		},
+	})
+
+	log.Printf("Message deleted: UserID=%d, RoomID=%d, MessageID=%d", userID, stored.RoomID, req.MessageID)
+}
+
+// loadOwnedMessage fetches a live message and verifies the client wrote it.
+// It reports the problem to the client and returns false otherwise.
+func (h *Hub) loadOwnedMessage(ctx context.Context, client *Client, messageID int64) (*storedMessage, bool) {
+	var stored storedMessage
+	err := h.db.QueryRowContext(ctx, `
+		SELECT Room_Id, User_Id, Created_At
+		FROM Chat_Messages
+		WHERE Message_Id = ? AND Deleted_At IS NULL
+	`, messageID).Scan(&stored.RoomID, &stored.UserID, &stored.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		client.SendError("message_not_found", "message not found")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error loading message: MessageID=%d, err=%v", messageID, err)
+		client.SendError("database_error", "failed to load message")
+		return nil, false
+	}
+
+	if stored.UserID != client.GetUserID() {
+		client.SendError("forbidden", "you can only change your own messages")
+		return nil, false
+	}
+	return &stored, true
+}
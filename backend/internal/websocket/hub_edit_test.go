@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupEditHub(t *testing.T) (*Hub, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		CREATE TABLE Chat_Messages (
+			Message_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+			Room_Id INTEGER NOT NULL,
+			User_Id INTEGER NOT NULL,
+			Content TEXT NOT NULL,
+			Created_At DATETIME NOT NULL,
+			Edited_At DATETIME,
+			Deleted_At DATETIME
+		);
+	`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO Chat_Messages (Room_Id, User_Id, Content, Created_At) VALUES (1, 1, 'fresh', ?), (1, 1, 'stale', ?)`,
+		time.Now(), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to seed messages: %v", err)
+	}
+
+	return NewHub(db), db
+}
+
+func TestEditMessageRejectsOtherAuthors(t *testing.T) {
+	hub, _ := setupEditHub(t)
+	bob := connectedClient(hub, 2, "bob")
+
+	hub.handleEditMessage(bob, &Message{Type: MessageTypeEdit, Payload: EditMessageRequest{MessageID: 1, Content: "hijacked"}})
+
+	msgType, payload := readMessage(t, bob)
+	if msgType != MessageTypeError || payload["code"] != "forbidden" {
+		t.Fatalf("expected forbidden error, got %s %v", msgType, payload)
+	}
+}
+
+func TestEditMessageRejectsAfterWindow(t *testing.T) {
+	hub, _ := setupEditHub(t)
+	alice := connectedClient(hub, 1, "alice")
+
+	hub.handleEditMessage(alice, &Message{Type: MessageTypeEdit, Payload: EditMessageRequest{MessageID: 2, Content: "too late"}})
+
+	msgType, payload := readMessage(t, alice)
+	if msgType != MessageTypeError || payload["code"] != "edit_window_expired" {
+		t.Fatalf("expected edit_window_expired error, got %s %v", msgType, payload)
+	}
+}
+
+func TestEditAndDeleteBroadcastToRoom(t *testing.T) {
+	hub, db := setupEditHub(t)
+	alice := connectedClient(hub, 1, "alice")
+	bob := connectedClient(hub, 2, "bob")
+
+	hub.handleEditMessage(alice, &Message{Type: MessageTypeEdit, Payload: EditMessageRequest{MessageID: 1, Content: "fixed typo"}})
+	if msgType, payload := readMessage(t, bob); msgType != MessageTypeEdited || payload["message_id"] != float64(1) {
+		t.Fatalf("expected edited notification, got %s %v", msgType, payload)
+	}
+	readMessage(t, alice)
+
+	hub.handleDeleteMessage(alice, &Message{Type: MessageTypeDelete, Payload: DeleteMessageRequest{MessageID: 1}})
+	if msgType, payload := readMessage(t, bob); msgType != MessageTypeDeleted || payload["message_id"] != float64(1) {
+		t.Fatalf("expected deleted notification, got %s %v", msgType, payload)
+	}
+
+	var deleted sql.NullTime
+	if err := db.QueryRow(`SELECT Deleted_At FROM Chat_Messages WHERE Message_Id = 1`).Scan(&deleted); err != nil {
+		t.Fatalf("failed to load message: %v", err)
+	}
+	if !deleted.Valid {
+		t.Fatalf("expected message to be soft-deleted")
+	}
+}
@@ -0,0 +1,34 @@
+package websocket
+
+import "time"
+
+// Heartbeat timing used unless SetHeartbeat says otherwise
+const (
+	DefaultPingInterval = pingPeriod
+	DefaultPongTimeout  = pongWait
+)
+
+// SetHeartbeat changes how often clients are pinged and how long the hub
+// waits for a pong before dropping the connection. A timeout of 0 keeps
+// DefaultPongTimeout; an interval that is 0 or not shorter than the timeout
+// is derived from the timeout. It applies to connections opened afterwards.
+func (h *Hub) SetHeartbeat(interval, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultPongTimeout
+	}
+	if interval <= 0 || interval >= timeout {
+		interval = (timeout * 9) / 10
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pingInterval = interval
+	h.pongTimeout = timeout
+}
+
+// heartbeat returns the ping interval and pong timeout for new connections
+func (h *Hub) heartbeat() (time.Duration, time.Duration) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.pingInterval, h.pongTimeout
+}
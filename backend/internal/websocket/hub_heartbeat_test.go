@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newHeartbeatServer serves connections that join room 1 as the given user
+// straight away, the state handleJoin leaves a client in
+func newHeartbeatServer(t *testing.T, hub *Hub, joined chan<- *Client) string {
+	t.Helper()
+	var nextUser int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := hub.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		nextUser++
+		client := NewClient(hub, conn)
+		client.SetUser(nextUser, r.URL.Query().Get("name"))
+		client.SetRoom(1)
+		hub.addClient(client, 1)
+		go client.WritePump()
+		go client.ReadPump()
+		joined <- client
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// dialHeartbeatClient connects and keeps reading so control frames are
+// handled; when answerPings is false incoming pings are silently dropped
+func dialHeartbeatClient(t *testing.T, url string, answerPings bool) {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if !answerPings {
+		conn.SetPingHandler(func(string) error { return nil })
+	}
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestClientIgnoringPingsIsDroppedAfterPongTimeout(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{})
+	hub.SetHeartbeat(20*time.Millisecond, 150*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	joined := make(chan *Client, 2)
+	url := newHeartbeatServer(t, hub, joined)
+
+	dialHeartbeatClient(t, url+"?name=alice", true)
+	alice := <-joined
+	dialHeartbeatClient(t, url+"?name=bob", false)
+	bob := <-joined
+	connectedAt := bob.LastPong()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hub.mu.RLock()
+		gone := !hub.clients[bob] && !hub.rooms[1][bob]
+		hub.mu.RUnlock()
+		if gone {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the unresponsive client to be removed after the pong timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	if !hub.clients[alice] || !hub.rooms[1][alice] {
+		t.Fatal("expected the client answering pings to stay connected")
+	}
+	if !alice.LastPong().After(connectedAt) {
+		t.Fatal("expected pongs to advance the responsive client's last pong time")
+	}
+	if !bob.LastPong().Equal(connectedAt) {
+		t.Fatal("expected no pong to be recorded for the unresponsive client")
+	}
+}
@@ -0,0 +1,129 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const (
+	// defaultHistoryPageSize is used when a load_more request has no limit
+	defaultHistoryPageSize = 50
+	// maxHistoryPageSize caps how many messages a single load_more returns
+	maxHistoryPageSize = 100
+)
+
+// handleLoadMore returns the page of room messages older than the given message ID
+// so clients can scroll back through history
+func (h *Hub) handleLoadMore(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	roomID := client.GetRoomID()
+	if roomID == 0 {
+		client.SendError("not_in_room", "user not in a room")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req LoadMoreRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil {
+		client.SendError("invalid_request", "invalid load_more payload")
+		return
+	}
+	if req.BeforeMessageID <= 0 {
+		client.SendError("invalid_request", "before_message_id is required")
+		return
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+
+	page, err := h.getChatHistoryBefore(context.Background(), roomID, req.BeforeMessageID, limit)
+	if err != nil {
+		log.Printf("Error loading older messages: RoomID=%d, BeforeMessageID=%d, err=%v", roomID, req.BeforeMessageID, err)
+		client.SendError("database_error", "failed to load messages")
+		return
+	}
+
+	client.SendMessage(&Message{
+		Type:    MessageTypeHistoryPage,
+		Payload: page,
+	})
+}
+
+// getChatHistoryBefore fetches up to limit messages older than beforeMessageID,
+// returned oldest first
+func (h *Hub) getChatHistoryBefore(ctx context.Context, roomID, beforeMessageID int64, limit int) (*HistoryPageResponse, error) {
+	// Fetch one extra row to learn whether an older page exists
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT
+			cm.Message_Id,
+			cm.User_Id,
+			u.Username,
+			cm.Content,
+			cm.Created_At,
+			cm.Edited_At
+		FROM Chat_Messages cm
+		JOIN Users u ON cm.User_Id = u.UserId
+		WHERE cm.Room_Id = ? AND cm.Message_Id < ? AND cm.Deleted_At IS NULL
+		ORDER BY cm.Message_Id DESC
+		LIMIT ?
+	`, roomID, beforeMessageID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]ChatMessage, 0, limit)
+	for rows.Next() {
+		var msg ChatMessage
+		var createdAt time.Time
+		var editedAt sql.NullTime
+		if err := rows.Scan(&msg.MessageID, &msg.UserID, &msg.Username, &msg.Content, &createdAt, &editedAt); err != nil {
+			return nil, err
+		}
+		msg.RoomID = roomID
+		msg.Timestamp = FormatTimestamp(createdAt)
+		if editedAt.Valid {
+			msg.EditedAt = FormatTimestamp(editedAt.Time)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	// Reverse to get chronological order
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return &HistoryPageResponse{
+		RoomID:          roomID,
+		Messages:        messages,
+		BeforeMessageID: beforeMessageID,
+		Limit:           limit,
+		HasMore:         hasMore,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package websocket
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupHistoryHub(t *testing.T, messages int) *Hub {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		CREATE TABLE Users (UserId INTEGER PRIMARY KEY, Username TEXT NOT NULL);
+		INSERT INTO Users (UserId, Username) VALUES (1, 'alice');
+		CREATE TABLE Chat_Messages (
+			Message_Id INTEGER PRIMARY KEY AUTOINCREMENT,
+			Room_Id INTEGER NOT NULL,
+			User_Id INTEGER NOT NULL,
+			Content TEXT NOT NULL,
+			Created_At DATETIME NOT NULL,
+			Edited_At DATETIME,
+			Deleted_At DATETIME
+		);
+	`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < messages; i++ {
+		if _, err := db.Exec(`INSERT INTO Chat_Messages (Room_Id, User_Id, Content, Created_At) VALUES (1, 1, ?, ?)`,
+			"message", start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("failed to seed messages: %v", err)
+		}
+	}
+	// A message in another room must never leak into the page
+	if _, err := db.Exec(`INSERT INTO Chat_Messages (Room_Id, User_Id, Content, Created_At) VALUES (2, 1, 'elsewhere', ?)`, start); err != nil {
+		t.Fatalf("failed to seed messages: %v", err)
+	}
+
+	return NewHub(db)
+}
+
+func loadMore(t *testing.T, hub *Hub, client *Client, req LoadMoreRequest) HistoryPageResponse {
+	t.Helper()
+	hub.handleLoadMore(client, &Message{Type: MessageTypeLoadMore, Payload: req})
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    MessageType         `json:"type"`
+			Payload HistoryPageResponse `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+		if msg.Type != MessageTypeHistoryPage {
+			t.Fatalf("expected history_page, got %s", msg.Type)
+		}
+		return msg.Payload
+	default:
+		t.Fatalf("expected a history page")
+		return HistoryPageResponse{}
+	}
+}
+
+func TestLoadMorePagesBackwardsWithoutOverlap(t *testing.T) {
+	hub := setupHistoryHub(t, 25)
+	alice := connectedClient(hub, 1, "alice")
+	alice.SetRoom(1)
+
+	first := loadMore(t, hub, alice, LoadMoreRequest{BeforeMessageID: 21, Limit: 10})
+	second := loadMore(t, hub, alice, LoadMoreRequest{BeforeMessageID: first.Messages[0].MessageID, Limit: 10})
+
+	assertWindow := func(name string, page HistoryPageResponse, from, to int64) {
+		t.Helper()
+		if int64(len(page.Messages)) != to-from+1 {
+			t.Fatalf("%s: expected %d messages, got %d", name, to-from+1, len(page.Messages))
+		}
+		for i, m := range page.Messages {
+			if m.MessageID != from+int64(i) || m.RoomID != 1 {
+				t.Fatalf("%s: expected message %d at position %d, got %+v", name, from+int64(i), i, m)
+			}
+		}
+	}
+	assertWindow("first page", first, 11, 20)
+	assertWindow("second page", second, 1, 10)
+	if !first.HasMore || second.HasMore {
+		t.Fatalf("expected has_more to be true then false, got %v and %v", first.HasMore, second.HasMore)
+	}
+}
+
+func TestLoadMoreCapsLimit(t *testing.T) {
+	hub := setupHistoryHub(t, 120)
+	alice := connectedClient(hub, 1, "alice")
+	alice.SetRoom(1)
+
+	page := loadMore(t, hub, alice, LoadMoreRequest{BeforeMessageID: 121, Limit: 500})
+	if page.Limit != maxHistoryPageSize || len(page.Messages) != maxHistoryPageSize {
+		t.Fatalf("expected the page to be capped at %d, got limit=%d messages=%d", maxHistoryPageSize, page.Limit, len(page.Messages))
+	}
+	if page.Messages[len(page.Messages)-1].MessageID != 120 {
+		t.Fatalf("expected the newest message before the cursor last, got %d", page.Messages[len(page.Messages)-1].MessageID)
+	}
+}
@@ -0,0 +1,48 @@
+package websocket
+
+import "sort"
+
+// DefaultMaxConnectionsPerUser is how many connections a user may keep open
+// at once unless SetMaxConnectionsPerUser says otherwise
+const DefaultMaxConnectionsPerUser = 5
+
+// SetMaxConnectionsPerUser caps how many connections one user may hold;
+// 0 removes the cap
+func (h *Hub) SetMaxConnectionsPerUser(n int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	h.maxPerUser = n
+}
+
+// evictOverLimit removes the user's oldest connections beyond the cap from
+// the hub and returns them so the caller can close them once h.mu is
+// released. Callers must hold h.mu.
+func (h *Hub) evictOverLimit(userID int64) []*Client {
+	conns := h.presence[userID]
+	if h.maxPerUser <= 0 || len(conns) <= h.maxPerUser {
+		return nil
+	}
+
+	ordered := make([]*Client, 0, len(conns))
+	for c := range conns {
+		ordered = append(ordered, c)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].seq < ordered[j].seq })
+
+	evicted := ordered[:len(ordered)-h.maxPerUser]
+	for _, c := range evicted {
+		delete(h.clients, c)
+		roomID := c.GetRoomID()
+		if room, ok := h.rooms[roomID]; ok {
+			delete(room, c)
+			if len(room) == 0 {
+				delete(h.rooms, roomID)
+			}
+		}
+		h.untrackPresence(c)
+	}
+	return evicted
+}
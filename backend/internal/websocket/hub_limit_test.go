@@ -0,0 +1,55 @@
+package websocket
+
+import "testing"
+
+// roomClient joins user to room 1 the way handleJoin does
+func roomClient(hub *Hub, userID int64, username string) *Client {
+	client := NewClient(hub, nil)
+	client.SetUser(userID, username)
+	client.SetRoom(1)
+	hub.addClient(client, 1)
+	return client
+}
+
+func TestAddClientEvictsOldestConnectionOverPerUserCap(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{})
+	hub.SetMaxConnectionsPerUser(2)
+
+	oldest := roomClient(hub, 1, "alice")
+	second := roomClient(hub, 1, "alice")
+	other := roomClient(hub, 2, "bob")
+	newest := roomClient(hub, 1, "alice")
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	if hub.clients[oldest] || hub.presence[1][oldest] || hub.rooms[1][oldest] {
+		t.Fatal("expected the oldest connection to be evicted")
+	}
+	for _, c := range []*Client{second, newest} {
+		if !hub.clients[c] || !hub.presence[1][c] {
+			t.Fatal("expected the newer connections to remain")
+		}
+	}
+	if len(hub.presence[1]) != 2 {
+		t.Fatalf("expected user to stay at the cap of 2 connections, got %d", len(hub.presence[1]))
+	}
+	if !hub.presence[2][other] {
+		t.Fatal("expected other users to be unaffected")
+	}
+	if len(hub.clients) != 3 {
+		t.Fatalf("expected 3 connections in total, got %d", len(hub.clients))
+	}
+}
+
+func TestAddClientWithoutCapKeepsAllConnections(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{})
+	hub.SetMaxConnectionsPerUser(0)
+
+	for i := 0; i < DefaultMaxConnectionsPerUser+2; i++ {
+		roomClient(hub, 1, "alice")
+	}
+	if got := len(hub.presence[1]); got != DefaultMaxConnectionsPerUser+2 {
+		t.Fatalf("expected every connection to be kept, got %d", got)
+	}
+}
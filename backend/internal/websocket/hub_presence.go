@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+)
+
+// FriendLister lists a user's accepted friends
+type FriendLister interface {
+	ListFriends(ctx context.Context, userID int64) ([]friend.UserSummary, error)
+}
+
+// SetFriendLister overrides the friend lookup used for presence notifications
+func (h *Hub) SetFriendLister(lister FriendLister) {
+	h.friendLister = lister
+}
+
+// IsUserOnline reports whether the user has at least one authenticated connection
+func (h *Hub) IsUserOnline(userID int64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.presence[userID]) > 0
+}
+
+// trackPresence records an authenticated client and reports whether it is the
+// user's first connection. Callers must hold h.mu.
+func (h *Hub) trackPresence(client *Client) bool {
+	userID := client.GetUserID()
+	if userID == 0 {
+		return false
+	}
+	conns := h.presence[userID]
+	if conns == nil {
+		conns = make(map[*Client]bool)
+		h.presence[userID] = conns
+	}
+	if conns[client] {
+		return false
+	}
+	conns[client] = true
+	return len(conns) == 1
+}
+
+// untrackPresence forgets a client and reports whether it was the user's last
+// connection. Callers must hold h.mu.
+func (h *Hub) untrackPresence(client *Client) bool {
+	userID := client.GetUserID()
+	conns, ok := h.presence[userID]
+	if !ok || !conns[client] {
+		return false
+	}
+	delete(conns, client)
+	if len(conns) > 0 {
+		return false
+	}
+	delete(h.presence, userID)
+	return true
+}
+
+// notifyFriendsPresence tells the user's connected friends that the user came
+// online or went offline
+func (h *Hub) notifyFriendsPresence(userID int64, username string, online bool) {
+	if h.friendLister == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	friends, err := h.friendLister.ListFriends(ctx, userID)
+	cancel()
+	if err != nil {
+		log.Printf("Error listing friends for presence: UserID=%d, err=%v", userID, err)
+		return
+	}
+
+	data, err := SerializeMessage(&Message{
+		Type: MessageTypePresence,
+		Payload: PresenceNotification{
+			UserID:    userID,
+			Username:  username,
+			Online:    online,
+			Timestamp: FormatTimestamp(time.Now()),
+		},
+	})
+	if err != nil {
+		log.Printf("Error serializing message: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	clients := make([]*Client, 0)
+	for _, f := range friends {
+		for client := range h.presence[f.ID] {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		select {
+		case client.send <- data:
+		default:
+			// Client send buffer full
+			log.Printf("Client send buffer full, dropping message")
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ngocan-dev/mangahub/backend/domain/friend"
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+type staticFriendLists map[int64][]int64
+
+func (f staticFriendLists) ListFriends(ctx context.Context, userID int64) ([]friend.UserSummary, error) {
+	friends := make([]friend.UserSummary, 0, len(f[userID]))
+	for _, id := range f[userID] {
+		friends = append(friends, friend.UserSummary{ID: id})
+	}
+	return friends, nil
+}
+
+// presenceMessages drains a client's queue and returns the presence notifications in it
+func presenceMessages(t *testing.T, client *Client) []PresenceNotification {
+	t.Helper()
+	var out []PresenceNotification
+	for {
+		select {
+		case data := <-client.send:
+			var msg struct {
+				Type    MessageType     `json:"type"`
+				Payload json.RawMessage `json:"payload"`
+			}
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("failed to decode message: %v", err)
+			}
+			if msg.Type != MessageTypePresence {
+				continue
+			}
+			var p PresenceNotification
+			if err := json.Unmarshal(msg.Payload, &p); err != nil {
+				t.Fatalf("failed to decode presence payload: %v", err)
+			}
+			out = append(out, p)
+		default:
+			return out
+		}
+	}
+}
+
+func TestFriendJoiningBroadcastsPresence(t *testing.T) {
+	hub, db := setupDirectHub(t, staticFriends{})
+	if _, err := db.Exec(`
+		CREATE TABLE Chat_Rooms (Room_Id INTEGER PRIMARY KEY, Room_Name TEXT, Room_Code TEXT);
+		CREATE TABLE Chat_Room_Members (Room_Id INTEGER NOT NULL, User_Id INTEGER NOT NULL, Role TEXT NOT NULL DEFAULT 'member', PRIMARY KEY (Room_Id, User_Id));
+		INSERT INTO Chat_Rooms (Room_Id, Room_Name, Room_Code) VALUES (1, 'general', 'general');
+	`); err != nil {
+		t.Fatalf("failed to create rooms: %v", err)
+	}
+	hub.SetFriendLister(staticFriendLists{1: {2}, 2: {1}})
+
+	alice := connectedClient(hub, 1, "alice")
+	carol := connectedClient(hub, 3, "carol")
+
+	token, err := auth.GenerateToken(2, "bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	bob := NewClient(hub, nil)
+	hub.handleJoin(bob, &Message{Type: MessageTypeJoin, Payload: JoinRequest{Token: token, RoomID: 1}})
+
+	if !hub.IsUserOnline(2) {
+		t.Fatalf("expected bob to be online after joining")
+	}
+	got := presenceMessages(t, alice)
+	if len(got) != 1 || got[0].UserID != 2 || !got[0].Online || got[0].Username != "bob" {
+		t.Fatalf("expected alice to be told bob came online, got %+v", got)
+	}
+	if got := presenceMessages(t, carol); len(got) != 0 {
+		t.Fatalf("non-friends must not receive presence updates, got %+v", got)
+	}
+
+	// A second device does not announce the user again
+	second := NewClient(hub, nil)
+	hub.handleJoin(second, &Message{Type: MessageTypeJoin, Payload: JoinRequest{Token: token, RoomID: 1}})
+	if got := presenceMessages(t, alice); len(got) != 0 {
+		t.Fatalf("expected no presence update for a second connection, got %+v", got)
+	}
+
+	hub.handleClientDisconnect(second)
+	if got := presenceMessages(t, alice); len(got) != 0 {
+		t.Fatalf("expected bob to stay online while a connection remains, got %+v", got)
+	}
+	hub.handleClientDisconnect(bob)
+	if hub.IsUserOnline(2) {
+		t.Fatalf("expected bob to be offline after disconnecting")
+	}
+	got = presenceMessages(t, alice)
+	if len(got) != 1 || got[0].UserID != 2 || got[0].Online {
+		t.Fatalf("expected alice to be told bob went offline, got %+v", got)
+	}
+}
@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+)
+
+// handleRead moves the sender's read marker forward to the given message and
+// tells the room so senders can see who has caught up. Only the latest read
+// message per user is stored, never a row per message, which keeps the cost
+// flat no matter how large the room gets.
+func (h *Hub) handleRead(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	roomID := client.GetRoomID()
+	if roomID == 0 {
+		client.SendError("not_in_room", "user not in a room")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req ReadRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || req.MessageID <= 0 {
+		client.SendError("invalid_request", "invalid read payload")
+		return
+	}
+
+	ctx := context.Background()
+	var messageRoomID int64
+	err = h.db.QueryRowContext(ctx, `SELECT Room_Id FROM Chat_Messages WHERE Message_Id = ?`, req.MessageID).Scan(&messageRoomID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && messageRoomID != roomID) {
+		client.SendError("message_not_found", "message not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading message for read marker: MessageID=%d, err=%v", req.MessageID, err)
+		client.SendError("database_error", "failed to mark message as read")
+		return
+	}
+
+	readAt := time.Now()
+	advanced, err := h.saveReadMarker(ctx, roomID, userID, req.MessageID, readAt)
+	if err != nil {
+		log.Printf("Error saving read marker: UserID=%d, RoomID=%d, MessageID=%d, err=%v", userID, roomID, req.MessageID, err)
+		client.SendError("database_error", "failed to mark message as read")
+		return
+	}
+	// Reading an older message never moves the marker back, so there is nothing to announce
+	if !advanced {
+		return
+	}
+
+	h.broadcastToRoom(roomID, &Message{
+		Type: MessageTypeRead,
+		Payload: ReadReceipt{
+			RoomID:            roomID,
+			UserID:            userID,
+			Username:          client.GetUsername(),
+			LastReadMessageID: req.MessageID,
+			Timestamp:         FormatTimestamp(readAt),
+		},
+	})
+}
+
+// sendReadReceipts delivers every participant's last-read marker for the room
+func (h *Hub) sendReadReceipts(client *Client, roomID int64) {
+	receipts, err := h.getReadReceipts(context.Background(), roomID)
+	if err != nil {
+		log.Printf("Error loading read receipts: RoomID=%d, err=%v", roomID, err)
+		return
+	}
+	client.SendMessage(&Message{
+		Type: MessageTypeReadReceipts,
+		Payload: ReadReceiptsResponse{
+			RoomID:   roomID,
+			Receipts: receipts,
+		},
+	})
+}
+
+// saveReadMarker stores messageID as the user's last read message in the room.
+// It reports false when the stored marker was already at or past messageID.
+func (h *Hub) saveReadMarker(ctx context.Context, roomID, userID, messageID int64, readAt time.Time) (bool, error) {
+	result, err := h.db.ExecContext(ctx, `
+		INSERT INTO Message_Reads (Room_Id, User_Id, Last_Read_Message_Id, Updated_At)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (Room_Id, User_Id) DO UPDATE SET
+			Last_Read_Message_Id = excluded.Last_Read_Message_Id,
+			Updated_At = excluded.Updated_At
+		WHERE excluded.Last_Read_Message_Id > Message_Reads.Last_Read_Message_Id
+	`, roomID, userID, messageID, readAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// getReadReceipts lists the read markers of everyone who has read in the room
+func (h *Hub) getReadReceipts(ctx context.Context, roomID int64) ([]ReadReceipt, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT mr.User_Id, u.Username, mr.Last_Read_Message_Id, mr.Updated_At
+		FROM Message_Reads mr
+		JOIN Users u ON mr.User_Id = u.UserId
+		WHERE mr.Room_Id = ?
+		ORDER BY mr.Last_Read_Message_Id DESC, mr.User_Id
+	`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]ReadReceipt, 0)
+	for rows.Next() {
+		receipt := ReadReceipt{RoomID: roomID}
+		var updatedAt time.Time
+		if err := rows.Scan(&receipt.UserID, &receipt.Username, &receipt.LastReadMessageID, &updatedAt); err != nil {
+			return nil, err
+		}
+		receipt.Timestamp = FormatTimestamp(updatedAt)
+		receipts = append(receipts, receipt)
+	}
+	return receipts, rows.Err()
+}
@@ -0,0 +1,127 @@
+package websocket
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func setupReadHub(t *testing.T) *Hub {
+	t.Helper()
+	hub := setupHistoryHub(t, 5)
+
+	migration, err := os.ReadFile("../../db/migrations/029_message_reads.sql")
+	if err != nil {
+		t.Fatalf("failed to read migration: %v", err)
+	}
+	if _, err := hub.db.Exec(string(migration)); err != nil {
+		t.Fatalf("failed to apply migration: %v", err)
+	}
+	if _, err := hub.db.Exec(`INSERT INTO Users (UserId, Username) VALUES (2, 'bob')`); err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	return hub
+}
+
+func markRead(hub *Hub, client *Client, messageID int64) {
+	hub.handleRead(client, &Message{Type: MessageTypeRead, Payload: ReadRequest{MessageID: messageID}})
+}
+
+func storedReadMarker(t *testing.T, hub *Hub, userID int64) int64 {
+	t.Helper()
+	var messageID int64
+	if err := hub.db.QueryRow(`SELECT Last_Read_Message_Id FROM Message_Reads WHERE Room_Id = 1 AND User_Id = ?`, userID).Scan(&messageID); err != nil {
+		t.Fatalf("failed to load read marker: %v", err)
+	}
+	return messageID
+}
+
+func TestReadUpdatesMarkerAndNotifiesRoom(t *testing.T) {
+	hub := setupReadHub(t)
+	alice := connectedClient(hub, 1, "alice")
+	alice.SetRoom(1)
+	bob := connectedClient(hub, 2, "bob")
+	bob.SetRoom(1)
+
+	markRead(hub, bob, 3)
+
+	if got := storedReadMarker(t, hub, 2); got != 3 {
+		t.Fatalf("expected read marker 3, got %d", got)
+	}
+	for _, client := range []*Client{alice, bob} {
+		msgType, payload := readMessage(t, client)
+		if msgType != MessageTypeRead {
+			t.Fatalf("expected read notification, got %s", msgType)
+		}
+		if payload["user_id"].(float64) != 2 || payload["last_read_message_id"].(float64) != 3 {
+			t.Fatalf("unexpected read receipt: %v", payload)
+		}
+	}
+
+	// Reading an older message keeps the marker where it is and stays quiet
+	markRead(hub, bob, 2)
+	if got := storedReadMarker(t, hub, 2); got != 3 {
+		t.Fatalf("expected read marker to stay at 3, got %d", got)
+	}
+	select {
+	case data := <-alice.send:
+		t.Fatalf("expected no notification for an older message, got %s", data)
+	default:
+	}
+
+	markRead(hub, bob, 5)
+	if got := storedReadMarker(t, hub, 2); got != 5 {
+		t.Fatalf("expected read marker 5, got %d", got)
+	}
+}
+
+func TestReadRejectsMessageFromAnotherRoom(t *testing.T) {
+	hub := setupReadHub(t)
+	bob := connectedClient(hub, 2, "bob")
+	bob.SetRoom(1)
+
+	// Message 6 was posted in room 2
+	markRead(hub, bob, 6)
+
+	msgType, payload := readMessage(t, bob)
+	if msgType != MessageTypeError {
+		t.Fatalf("expected an error, got %s", msgType)
+	}
+	if payload["code"] != "message_not_found" {
+		t.Fatalf("expected message_not_found, got %v", payload)
+	}
+}
+
+func TestReadReceiptsDeliveredOnJoin(t *testing.T) {
+	hub := setupReadHub(t)
+	bob := connectedClient(hub, 2, "bob")
+	bob.SetRoom(1)
+	markRead(hub, bob, 4)
+	readMessage(t, bob)
+
+	alice := connectedClient(hub, 1, "alice")
+	hub.sendReadReceipts(alice, 1)
+
+	var resp struct {
+		Type    MessageType          `json:"type"`
+		Payload ReadReceiptsResponse `json:"payload"`
+	}
+	select {
+	case data := <-alice.send:
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+	default:
+		t.Fatal("expected read receipts")
+	}
+	if resp.Type != MessageTypeReadReceipts {
+		t.Fatalf("expected read_receipts, got %s", resp.Type)
+	}
+	if len(resp.Payload.Receipts) != 1 {
+		t.Fatalf("expected one receipt, got %+v", resp.Payload.Receipts)
+	}
+	receipt := resp.Payload.Receipts[0]
+	if receipt.UserID != 2 || receipt.Username != "bob" || receipt.LastReadMessageID != 4 {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+}
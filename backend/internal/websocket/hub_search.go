@@ -0,0 +1,139 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultSearchLimit is used when a search request has no limit
+	defaultSearchLimit = 20
+	// maxSearchLimit caps how many matches a single search returns
+	maxSearchLimit = 50
+	// maxSearchQueryLength bounds the search text in characters
+	maxSearchQueryLength = 100
+)
+
+// likeEscaper makes LIKE wildcards in a search query match literally. '!'
+// is the escape character because a backslash means different things in
+// SQLite and MySQL string literals.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// handleSearch finds messages in the sender's room containing the query and
+// returns them only to the sender
+func (h *Hub) handleSearch(client *Client, msg *Message) {
+	userID := client.GetUserID()
+	if userID == 0 {
+		client.SendError("not_authenticated", "user not authenticated")
+		return
+	}
+
+	roomID := client.GetRoomID()
+	if roomID == 0 {
+		client.SendError("not_in_room", "user not in a room")
+		return
+	}
+
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		client.SendError("invalid_request", "invalid message format")
+		return
+	}
+
+	var req SearchRequest
+	if err := json.Unmarshal(payloadBytes, &req); err != nil {
+		client.SendError("invalid_request", "invalid search payload")
+		return
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		client.SendError("invalid_request", "query is required")
+		return
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		client.SendError("invalid_request", "query is too long")
+		return
+	}
+
+	// Membership of private rooms can be revoked after the client joined
+	if !h.authorizeRoom(client, roomID, userID) {
+		return
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	results, err := h.searchMessages(context.Background(), roomID, query, limit)
+	if err != nil {
+		log.Printf("Error searching messages: RoomID=%d, err=%v", roomID, err)
+		client.SendError("database_error", "failed to search messages")
+		return
+	}
+
+	client.SendMessage(&Message{
+		Type: MessageTypeSearchResults,
+		Payload: &SearchResponse{
+			RoomID:  roomID,
+			Query:   query,
+			Results: results,
+			Limit:   limit,
+		},
+	})
+}
+
+// searchMessages returns up to limit undeleted messages of the room whose
+// content contains query, newest first
+func (h *Hub) searchMessages(ctx context.Context, roomID int64, query string, limit int) ([]SearchResult, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT
+			cm.Message_Id,
+			cm.User_Id,
+			u.Username,
+			cm.Content,
+			cm.Created_At,
+			cm.Edited_At,
+			(SELECT MAX(p.Message_Id) FROM Chat_Messages p
+				WHERE p.Room_Id = cm.Room_Id AND p.Message_Id < cm.Message_Id AND p.Deleted_At IS NULL),
+			(SELECT MIN(n.Message_Id) FROM Chat_Messages n
+				WHERE n.Room_Id = cm.Room_Id AND n.Message_Id > cm.Message_Id AND n.Deleted_At IS NULL)
+		FROM Chat_Messages cm
+		JOIN Users u ON cm.User_Id = u.UserId
+		WHERE cm.Room_Id = ? AND cm.Deleted_At IS NULL AND cm.Content LIKE ? ESCAPE '!'
+		ORDER BY cm.Message_Id DESC
+		LIMIT ?
+	`, roomID, "%"+likeEscaper.Replace(query)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]SearchResult, 0, limit)
+	for rows.Next() {
+		var result SearchResult
+		var createdAt time.Time
+		var editedAt sql.NullTime
+		var previousID, nextID sql.NullInt64
+		if err := rows.Scan(&result.MessageID, &result.UserID, &result.Username, &result.Content, &createdAt, &editedAt, &previousID, &nextID); err != nil {
+			return nil, err
+		}
+		result.RoomID = roomID
+		result.Timestamp = FormatTimestamp(createdAt)
+		if editedAt.Valid {
+			result.EditedAt = FormatTimestamp(editedAt.Time)
+		}
+		result.PreviousMessageID = previousID.Int64
+		result.NextMessageID = nextID.Int64
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func setupSearchHub(t *testing.T, messages int) *Hub {
+	t.Helper()
+	hub := setupHistoryHub(t, messages)
+	if _, err := hub.db.Exec(`CREATE TABLE Chat_Room_Members (Room_Id INTEGER NOT NULL, User_Id INTEGER NOT NULL, Role TEXT NOT NULL DEFAULT 'member', PRIMARY KEY (Room_Id, User_Id))`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return hub
+}
+
+func search(t *testing.T, hub *Hub, client *Client, req SearchRequest) (MessageType, json.RawMessage) {
+	t.Helper()
+	hub.handleSearch(client, &Message{Type: MessageTypeSearch, Payload: req})
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    MessageType     `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+		return msg.Type, msg.Payload
+	default:
+		t.Fatalf("expected a reply to the search")
+		return "", nil
+	}
+}
+
+func TestSearchReturnsOnlyMatchingMessagesFromClientRoom(t *testing.T) {
+	// Message 1 is the other room's seed message
+	hub := setupSearchHub(t, 0)
+	start := time.Now().Add(-time.Hour)
+	for i, row := range []struct {
+		room    int64
+		content string
+		deleted bool
+	}{
+		{1, "anyone read One Piece?", false},        // 2
+		{1, "good morning", false},                  // 3
+		{1, "one piece chapter 1100 is out", false}, // 4
+		{2, "one piece in another room", false},     // 5
+		{1, "one piece spoiler", true},              // 6
+		{1, "100% agree", false},                    // 7
+	} {
+		var deletedAt interface{}
+		if row.deleted {
+			deletedAt = start
+		}
+		if _, err := hub.db.Exec(`INSERT INTO Chat_Messages (Room_Id, User_Id, Content, Created_At, Deleted_At) VALUES (?, 1, ?, ?, ?)`,
+			row.room, row.content, start.Add(time.Duration(i)*time.Second), deletedAt); err != nil {
+			t.Fatalf("failed to seed messages: %v", err)
+		}
+	}
+
+	alice := connectedClient(hub, 1, "alice")
+	alice.SetRoom(1)
+	bob := connectedClient(hub, 2, "bob")
+	bob.SetRoom(1)
+
+	msgType, payload := search(t, hub, alice, SearchRequest{Query: "  ONE piece "})
+	if msgType != MessageTypeSearchResults {
+		t.Fatalf("expected search_results, got %s", msgType)
+	}
+	var resp SearchResponse
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		t.Fatalf("failed to decode results: %v", err)
+	}
+	if resp.RoomID != 1 || len(resp.Results) != 2 {
+		t.Fatalf("expected 2 matches in room 1, got %+v", resp)
+	}
+	if resp.Results[0].MessageID != 4 || resp.Results[1].MessageID != 2 {
+		t.Fatalf("expected messages 4 and 2 newest first, got %+v", resp.Results)
+	}
+	// Context skips the deleted message and never crosses into another room
+	if got := resp.Results[0]; got.PreviousMessageID != 3 || got.NextMessageID != 7 {
+		t.Fatalf("unexpected context around message 4: %+v", got)
+	}
+	if got := resp.Results[1]; got.PreviousMessageID != 0 || got.NextMessageID != 3 {
+		t.Fatalf("unexpected context around message 2: %+v", got)
+	}
+
+	// Wildcards in the query match literally
+	_, payload = search(t, hub, alice, SearchRequest{Query: "100%"})
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		t.Fatalf("failed to decode results: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].MessageID != 7 {
+		t.Fatalf("expected only the message containing 100%%, got %+v", resp.Results)
+	}
+
+	select {
+	case data := <-bob.send:
+		t.Fatalf("search results must only go to the requester, bob got %s", data)
+	default:
+	}
+}
+
+func TestSearchRequiresRoomMembershipAndQuery(t *testing.T) {
+	hub := setupSearchHub(t, 1)
+	lobby := connectedClient(hub, 1, "alice")
+
+	if msgType, _ := search(t, hub, lobby, SearchRequest{Query: "message"}); msgType != MessageTypeError {
+		t.Fatalf("expected an error outside a room, got %s", msgType)
+	}
+	lobby.SetRoom(1)
+	if msgType, _ := search(t, hub, lobby, SearchRequest{Query: "   "}); msgType != MessageTypeError {
+		t.Fatalf("expected an error for a blank query, got %s", msgType)
+	}
+
+	// Room 1 turns private and alice is no longer a member
+	if _, err := hub.db.Exec(`INSERT INTO Chat_Room_Members (Room_Id, User_Id) VALUES (1, 2)`); err != nil {
+		t.Fatalf("failed to seed members: %v", err)
+	}
+	msgType, payload := search(t, hub, lobby, SearchRequest{Query: "message"})
+	var errPayload map[string]string
+	if err := json.Unmarshal(payload, &errPayload); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	if msgType != MessageTypeError || errPayload["code"] != "room_forbidden" {
+		t.Fatalf("expected room_forbidden for a non-member, got %s %v", msgType, errPayload)
+	}
+}
@@ -0,0 +1,30 @@
+package websocket
+
+import (
+	"log"
+	"net/http"
+)
+
+// SetAllowedOrigins limits which browser origins may open room connections,
+// normally the CORS allowlist. Until it is called only same-origin pages may
+// connect.
+func (h *Hub) SetAllowedOrigins(origins []string) {
+	h.upgrader.CheckOrigin = CheckOrigin(origins)
+}
+
+// ServeWS upgrades a request to a room chat connection. The client
+// authenticates and picks a room with its first join message; until then it
+// is not part of any room.
+func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	conn, err := hub.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ws upgrade error: %v", err)
+		return
+	}
+
+	client := NewClient(hub, conn)
+	hub.register <- client
+
+	go client.WritePump()
+	go client.ReadPump()
+}
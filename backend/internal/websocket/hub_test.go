@@ -0,0 +1,125 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ngocan-dev/mangahub/backend/internal/auth"
+)
+
+func TestHandleJoinRejectsRevokedToken(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:ws_revocation?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE Revoked_Tokens (
+		Jti TEXT PRIMARY KEY,
+		User_Id INTEGER NOT NULL,
+		Expires_At DATETIME NOT NULL,
+		Revoked_At DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	auth.SetRevocationStore(auth.NewRevocationStore(db, nil))
+	defer auth.SetRevocationStore(nil)
+
+	token, err := auth.GenerateToken(1, "alice", "alice@example.com")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if err := auth.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+
+	hub := NewHub(db)
+	client := NewClient(hub, nil)
+	hub.handleJoin(client, &Message{Type: MessageTypeJoin, Payload: JoinRequest{Token: token}})
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Type    MessageType       `json:"type"`
+			Payload map[string]string `json:"payload"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("failed to decode message: %v", err)
+		}
+		if msg.Type != MessageTypeError || msg.Payload["code"] != "token_revoked" {
+			t.Fatalf("expected token_revoked error, got %s %v", msg.Type, msg.Payload)
+		}
+	default:
+		t.Fatalf("expected an error message to be sent")
+	}
+
+	if client.GetUserID() != 0 {
+		t.Fatalf("revoked client must not be authenticated")
+	}
+}
+
+func TestHandleJoinRejectsNonMembersOfPrivateRoom(t *testing.T) {
+	hub, db := setupDirectHub(t, staticFriends{})
+	if _, err := db.Exec(`
+		CREATE TABLE Chat_Rooms (Room_Id INTEGER PRIMARY KEY, Room_Name TEXT, Room_Code TEXT);
+		CREATE TABLE Chat_Room_Members (Room_Id INTEGER NOT NULL, User_Id INTEGER NOT NULL, Role TEXT NOT NULL DEFAULT 'member', PRIMARY KEY (Room_Id, User_Id));
+		INSERT INTO Chat_Rooms (Room_Id, Room_Name, Room_Code) VALUES (1, 'General Chat', 'general'), (2, 'Book club', 'a1b2c3');
+		INSERT INTO Chat_Room_Members (Room_Id, User_Id, Role) VALUES (2, 1, 'owner');
+	`); err != nil {
+		t.Fatalf("failed to create rooms: %v", err)
+	}
+
+	join := func(userID int64, username string, req JoinRequest) *Client {
+		token, err := auth.GenerateToken(userID, username, username+"@example.com")
+		if err != nil {
+			t.Fatalf("GenerateToken returned error: %v", err)
+		}
+		req.Token = token
+		client := NewClient(hub, nil)
+		hub.handleJoin(client, &Message{Type: MessageTypeJoin, Payload: req})
+		return client
+	}
+
+	for _, req := range []JoinRequest{{RoomID: 2}, {RoomCode: "a1b2c3"}} {
+		bob := join(2, "bob", req)
+		msgType, payload := readMessage(t, bob)
+		if msgType != MessageTypeError || payload["code"] != "room_forbidden" {
+			t.Fatalf("expected room_forbidden for %+v, got %s %v", req, msgType, payload)
+		}
+		if bob.GetRoomID() != 0 || hub.IsUserOnline(2) {
+			t.Fatalf("non-member must not be added to the private room")
+		}
+	}
+
+	if alice := join(1, "alice", JoinRequest{RoomID: 2}); alice.GetRoomID() != 2 {
+		t.Fatalf("expected the owner to join the private room, got room %d", alice.GetRoomID())
+	}
+	if bob := join(2, "bob", JoinRequest{RoomCode: "general"}); bob.GetRoomID() != 1 {
+		t.Fatalf("expected anyone to join the general room, got room %d", bob.GetRoomID())
+	}
+}
+
+func TestHubStatsCountsClientsAcrossRooms(t *testing.T) {
+	hub, _ := setupDirectHub(t, staticFriends{})
+
+	roomClient(hub, 1, "alice")
+	roomClient(hub, 2, "bob")
+	carol := NewClient(hub, nil)
+	carol.SetUser(3, "carol")
+	carol.SetRoom(2)
+	hub.addClient(carol, 2)
+
+	stats := hub.Stats()
+	if stats.Clients != 3 || stats.Rooms != 2 {
+		t.Fatalf("expected 3 clients in 2 rooms, got %+v", stats)
+	}
+}
@@ -0,0 +1,66 @@
+package websocket
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	_ "modernc.org/sqlite"
+)
+
+func newOriginTestServer(t *testing.T, allowed []string) string {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hub := NewDirectChatHub(db)
+	hub.SetAllowedOrigins(allowed)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.HandleWS(w, r, 1)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dialWithOrigin(url, origin string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func TestDirectChatHubAllowedOriginUpgrades(t *testing.T) {
+	url := newOriginTestServer(t, []string{"https://app.example.com"})
+
+	for _, origin := range []string{"https://app.example.com", ""} {
+		conn, resp, err := dialWithOrigin(url, origin)
+		if err != nil {
+			t.Fatalf("origin %q: expected upgrade, got %v", origin, err)
+		}
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("origin %q: expected 101, got %d", origin, resp.StatusCode)
+		}
+		conn.Close()
+	}
+}
+
+func TestDirectChatHubRejectsDisallowedOrigin(t *testing.T) {
+	url := newOriginTestServer(t, []string{"https://app.example.com"})
+
+	conn, resp, err := dialWithOrigin(url, "https://evil.example.com")
+	if err == nil {
+		conn.Close()
+		t.Fatal("expected the upgrade to be rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 before upgrade, got %v (err %v)", resp, err)
+	}
+}
@@ -14,17 +14,30 @@ var (
 type MessageType string
 
 const (
-	MessageTypeJoin        MessageType = "join"
-	MessageTypeJoined      MessageType = "joined"
-	MessageTypeReconnect   MessageType = "reconnect"
-	MessageTypeReconnected MessageType = "reconnected"
-	MessageTypeLeave       MessageType = "leave"
-	MessageTypeLeft        MessageType = "left"
-	MessageTypeMessage     MessageType = "message"
-	MessageTypeHistory     MessageType = "history"
-	MessageTypeError       MessageType = "error"
-	MessageTypeUserList    MessageType = "user_list"
-	MessageTypeHeartbeat   MessageType = "heartbeat"
+	MessageTypeJoin          MessageType = "join"
+	MessageTypeJoined        MessageType = "joined"
+	MessageTypeReconnect     MessageType = "reconnect"
+	MessageTypeReconnected   MessageType = "reconnected"
+	MessageTypeLeave         MessageType = "leave"
+	MessageTypeLeft          MessageType = "left"
+	MessageTypeMessage       MessageType = "message"
+	MessageTypeEdit          MessageType = "edit"
+	MessageTypeEdited        MessageType = "edited"
+	MessageTypeDelete        MessageType = "delete"
+	MessageTypeDeleted       MessageType = "deleted"
+	MessageTypeDirect        MessageType = "direct"
+	MessageTypeDirectHistory MessageType = "direct_history"
+	MessageTypeHistory       MessageType = "history"
+	MessageTypeLoadMore      MessageType = "load_more"
+	MessageTypeHistoryPage   MessageType = "history_page"
+	MessageTypeSearch        MessageType = "search"
+	MessageTypeSearchResults MessageType = "search_results"
+	MessageTypeError         MessageType = "error"
+	MessageTypeUserList      MessageType = "user_list"
+	MessageTypeHeartbeat     MessageType = "heartbeat"
+	MessageTypePresence      MessageType = "presence"
+	MessageTypeRead          MessageType = "read"
+	MessageTypeReadReceipts  MessageType = "read_receipts"
 )
 
 // Message represents a WebSocket message
@@ -88,6 +101,76 @@ type ChatMessage struct {
 	Content   string `json:"content"`
 	RoomID    int64  `json:"room_id"`
 	Timestamp string `json:"timestamp"`
+	EditedAt  string `json:"edited_at,omitempty"`
+}
+
+// EditMessageRequest asks to replace the content of one of the sender's messages
+type EditMessageRequest struct {
+	MessageID int64  `json:"message_id"`
+	Content   string `json:"content"`
+}
+
+// DeleteMessageRequest asks to delete one of the sender's messages
+type DeleteMessageRequest struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// MessageEditedNotification tells room members to re-render an edited message
+type MessageEditedNotification struct {
+	MessageID int64  `json:"message_id"`
+	RoomID    int64  `json:"room_id"`
+	UserID    int64  `json:"user_id"`
+	Content   string `json:"content"`
+	EditedAt  string `json:"edited_at"`
+}
+
+// MessageDeletedNotification tells room members to remove a deleted message
+type MessageDeletedNotification struct {
+	MessageID int64  `json:"message_id"`
+	RoomID    int64  `json:"room_id"`
+	UserID    int64  `json:"user_id"`
+	DeletedAt string `json:"deleted_at"`
+}
+
+// ReadRequest marks every room message up to MessageID as read by the sender
+type ReadRequest struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// ReadReceipt is the latest message a user has read in a room
+type ReadReceipt struct {
+	RoomID            int64  `json:"room_id"`
+	UserID            int64  `json:"user_id"`
+	Username          string `json:"username"`
+	LastReadMessageID int64  `json:"last_read_message_id"`
+	Timestamp         string `json:"timestamp"`
+}
+
+// ReadReceiptsResponse carries the read markers of a room's participants
+type ReadReceiptsResponse struct {
+	RoomID   int64         `json:"room_id"`
+	Receipts []ReadReceipt `json:"receipts"`
+}
+
+// DirectMessageRequest is the payload of a direct message sent by a client
+type DirectMessageRequest struct {
+	ToUserID int64  `json:"to_user_id"`
+	Content  string `json:"content"`
+}
+
+// DirectChatMessage represents a private message between two friends
+type DirectChatMessage struct {
+	MessageID    int64  `json:"message_id"`
+	FromUserID   int64  `json:"from_user_id"`
+	FromUsername string `json:"from_username"`
+	ToUserID     int64  `json:"to_user_id"`
+	Content      string `json:"content"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// DirectHistoryResponse carries direct messages received while the user was offline
+type DirectHistoryResponse struct {
+	Messages []DirectChatMessage `json:"messages"`
 }
 
 // HistoryResponse represents chat history
@@ -97,6 +180,43 @@ type HistoryResponse struct {
 	Limit    int           `json:"limit"`
 }
 
+// LoadMoreRequest asks for the page of room messages older than BeforeMessageID
+type LoadMoreRequest struct {
+	BeforeMessageID int64 `json:"before_message_id"`
+	Limit           int   `json:"limit,omitempty"`
+}
+
+// HistoryPageResponse carries an older page of room messages in chronological order
+type HistoryPageResponse struct {
+	RoomID          int64         `json:"room_id"`
+	Messages        []ChatMessage `json:"messages"`
+	BeforeMessageID int64         `json:"before_message_id"`
+	Limit           int           `json:"limit"`
+	HasMore         bool          `json:"has_more"`
+}
+
+// SearchRequest asks for the messages in the sender's room containing Query
+type SearchRequest struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// SearchResult is a matching message with the IDs of the messages around it,
+// which clients pass to load_more to show the match in context
+type SearchResult struct {
+	ChatMessage
+	PreviousMessageID int64 `json:"previous_message_id,omitempty"`
+	NextMessageID     int64 `json:"next_message_id,omitempty"`
+}
+
+// SearchResponse carries a room's matching messages, newest first
+type SearchResponse struct {
+	RoomID  int64          `json:"room_id"`
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+	Limit   int            `json:"limit"`
+}
+
 // UserListResponse represents list of active users
 type UserListResponse struct {
 	RoomID int64      `json:"room_id"`
@@ -109,6 +229,14 @@ type UserInfo struct {
 	Username string `json:"username"`
 }
 
+// PresenceNotification tells a user that a friend came online or went offline
+type PresenceNotification struct {
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username"`
+	Online    bool   `json:"online"`
+	Timestamp string `json:"timestamp"`
+}
+
 // ParseMessage parses a JSON message from bytes
 func ParseMessage(data []byte) (*Message, error) {
 	var msg Message
@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// sendRateLimit messages are allowed per sendRateWindow for each client
+	sendRateLimit  = 5
+	sendRateWindow = 5 * time.Second
+
+	// A client rejected muteAfterStrikes times within one window is muted for muteDuration
+	muteAfterStrikes = 3
+	muteDuration     = 30 * time.Second
+)
+
+// allowSend counts one outgoing chat message against the client's token bucket.
+// The bucket refills gradually and fully once a whole window has passed. When the
+// message is rejected it returns the error code and how long the client should wait.
+func (c *Client) allowSend(now time.Time) (bool, string, time.Duration) {
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+
+	if now.Before(c.sendMuteUntil) {
+		return false, "muted", c.sendMuteUntil.Sub(now)
+	}
+
+	elapsed := now.Sub(c.sendRefillAt)
+	if elapsed >= sendRateWindow {
+		c.sendTokens = sendRateLimit
+		c.sendStrikes = 0
+		c.sendRefillAt = now
+	} else if refill := int(int64(sendRateLimit) * int64(elapsed) / int64(sendRateWindow)); refill > 0 {
+		c.sendTokens = min(c.sendTokens+refill, sendRateLimit)
+		c.sendRefillAt = now
+	}
+
+	if c.sendTokens > 0 {
+		c.sendTokens--
+		return true, "", 0
+	}
+
+	c.sendStrikes++
+	if c.sendStrikes >= muteAfterStrikes {
+		c.sendStrikes = 0
+		c.sendMuteUntil = now.Add(muteDuration)
+		return false, "muted", muteDuration
+	}
+	perToken := sendRateWindow / sendRateLimit
+	return false, "rate_limited", max(perToken-now.Sub(c.sendRefillAt), 0)
+}
+
+// throttleSend reports whether the client may send another message and tells
+// the client why when it may not
+func (c *Client) throttleSend() bool {
+	ok, code, retryAfter := c.allowSend(time.Now())
+	if ok {
+		return true
+	}
+	wait := retryAfter.Round(time.Second)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	if code == "muted" {
+		log.Printf("Client muted for flooding: UserID=%d, RoomID=%d", c.GetUserID(), c.GetRoomID())
+		c.SendError(code, fmt.Sprintf("you are sending messages too fast and have been muted for %s", wait))
+	} else {
+		c.SendError(code, fmt.Sprintf("too many messages, try again in %s", wait))
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSixthRapidMessageIsRateLimited(t *testing.T) {
+	hub := setupHistoryHub(t, 0)
+	alice := connectedClient(hub, 1, "alice")
+	alice.SetRoom(1)
+
+	for i := 0; i < sendRateLimit; i++ {
+		hub.handleChatMessage(alice, &Message{Type: MessageTypeMessage, Payload: map[string]string{"content": "hello"}})
+		if msgType, payload := readMessage(t, alice); msgType != MessageTypeMessage {
+			t.Fatalf("message %d: expected broadcast, got %s %v", i+1, msgType, payload)
+		}
+	}
+
+	hub.handleChatMessage(alice, &Message{Type: MessageTypeMessage, Payload: map[string]string{"content": "spam"}})
+	msgType, payload := readMessage(t, alice)
+	if msgType != MessageTypeError || payload["code"] != "rate_limited" {
+		t.Fatalf("expected rate_limited error, got %s %v", msgType, payload)
+	}
+
+	var saved int
+	if err := hub.db.QueryRow(`SELECT COUNT(*) FROM Chat_Messages WHERE Content = 'spam'`).Scan(&saved); err != nil {
+		t.Fatalf("failed to count messages: %v", err)
+	}
+	if saved != 0 {
+		t.Fatalf("rate limited message must not be saved")
+	}
+}
+
+func TestSendLimitResetsAfterWindow(t *testing.T) {
+	client := NewClient(nil, nil)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < sendRateLimit; i++ {
+		if ok, _, _ := client.allowSend(start); !ok {
+			t.Fatalf("message %d should be allowed", i+1)
+		}
+	}
+	if ok, code, _ := client.allowSend(start); ok || code != "rate_limited" {
+		t.Fatalf("expected the 6th message to be rate limited, got ok=%v code=%q", ok, code)
+	}
+
+	later := start.Add(sendRateWindow)
+	for i := 0; i < sendRateLimit; i++ {
+		if ok, _, _ := client.allowSend(later); !ok {
+			t.Fatalf("message %d after the window should be allowed", i+1)
+		}
+	}
+}
+
+func TestRepeatedViolationsMuteClient(t *testing.T) {
+	client := NewClient(nil, nil)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < sendRateLimit; i++ {
+		client.allowSend(start)
+	}
+	var code string
+	for i := 0; i < muteAfterStrikes; i++ {
+		_, code, _ = client.allowSend(start)
+	}
+	if code != "muted" {
+		t.Fatalf("expected the client to be muted after %d violations, got %q", muteAfterStrikes, code)
+	}
+
+	// Muting outlasts the rate window
+	if ok, code, _ := client.allowSend(start.Add(sendRateWindow)); ok || code != "muted" {
+		t.Fatalf("expected the client to stay muted, got ok=%v code=%q", ok, code)
+	}
+	if ok, _, _ := client.allowSend(start.Add(muteDuration)); !ok {
+		t.Fatalf("expected the mute to expire")
+	}
+}
@@ -17,3 +17,11 @@ type Chapter struct {
 	ContentText string     `json:"content_text"`
 	CreatedAt   *time.Time `json:"created_at,omitempty"`
 }
+
+// ChapterNavigation links a chapter to its neighbours within the same manga.
+// Previous and Next are nil at the first and last chapter.
+type ChapterNavigation struct {
+	Current  ChapterSummary  `json:"current"`
+	Previous *ChapterSummary `json:"previous"`
+	Next     *ChapterSummary `json:"next"`
+}
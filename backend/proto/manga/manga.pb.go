@@ -17,13 +17,18 @@ func NewMangaServiceClient(cc grpc.ClientConnInterface) MangaServiceClient {
 	return &mangaServiceClient{cc}
 }
 
+// callOptions selects the JSON codec ahead of any caller options
+func callOptions(opts []grpc.CallOption) []grpc.CallOption {
+	return append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+}
+
 type mangaServiceClient struct {
 	cc grpc.ClientConnInterface
 }
 
 func (c *mangaServiceClient) GetManga(ctx context.Context, in *GetMangaRequest, opts ...grpc.CallOption) (*GetMangaResponse, error) {
 	out := new(GetMangaResponse)
-	err := c.cc.Invoke(ctx, "/manga.MangaService/GetManga", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manga.MangaService/GetManga", in, out, callOptions(opts)...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +37,7 @@ func (c *mangaServiceClient) GetManga(ctx context.Context, in *GetMangaRequest,
 
 func (c *mangaServiceClient) SearchManga(ctx context.Context, in *SearchMangaRequest, opts ...grpc.CallOption) (*SearchMangaResponse, error) {
 	out := new(SearchMangaResponse)
-	err := c.cc.Invoke(ctx, "/manga.MangaService/SearchManga", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manga.MangaService/SearchManga", in, out, callOptions(opts)...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,18 +46,52 @@ func (c *mangaServiceClient) SearchManga(ctx context.Context, in *SearchMangaReq
 
 func (c *mangaServiceClient) UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*UpdateProgressResponse, error) {
 	out := new(UpdateProgressResponse)
-	err := c.cc.Invoke(ctx, "/manga.MangaService/UpdateProgress", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/manga.MangaService/UpdateProgress", in, out, callOptions(opts)...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *mangaServiceClient) StreamProgress(ctx context.Context, in *StreamProgressRequest, opts ...grpc.CallOption) (MangaService_StreamProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MangaService_ServiceDesc.Streams[0], "/manga.MangaService/StreamProgress", callOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mangaServiceStreamProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// MangaService_StreamProgressClient receives progress updates from the server
+type MangaService_StreamProgressClient interface {
+	Recv() (*ProgressUpdate, error)
+	grpc.ClientStream
+}
+
+type mangaServiceStreamProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *mangaServiceStreamProgressClient) Recv() (*ProgressUpdate, error) {
+	m := new(ProgressUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MangaServiceClient is the client API for MangaService service
 type MangaServiceClient interface {
 	GetManga(ctx context.Context, in *GetMangaRequest, opts ...grpc.CallOption) (*GetMangaResponse, error)
 	SearchManga(ctx context.Context, in *SearchMangaRequest, opts ...grpc.CallOption) (*SearchMangaResponse, error)
 	UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*UpdateProgressResponse, error)
+	StreamProgress(ctx context.Context, in *StreamProgressRequest, opts ...grpc.CallOption) (MangaService_StreamProgressClient, error)
 }
 
 // MangaServiceServer is the server API for MangaService service
@@ -60,6 +99,7 @@ type MangaServiceServer interface {
 	GetManga(context.Context, *GetMangaRequest) (*GetMangaResponse, error)
 	SearchManga(context.Context, *SearchMangaRequest) (*SearchMangaResponse, error)
 	UpdateProgress(context.Context, *UpdateProgressRequest) (*UpdateProgressResponse, error)
+	StreamProgress(*StreamProgressRequest, MangaService_StreamProgressServer) error
 	mustEmbedUnimplementedMangaServiceServer()
 }
 
@@ -78,6 +118,10 @@ func (UnimplementedMangaServiceServer) UpdateProgress(context.Context, *UpdatePr
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateProgress not implemented")
 }
 
+func (UnimplementedMangaServiceServer) StreamProgress(*StreamProgressRequest, MangaService_StreamProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamProgress not implemented")
+}
+
 func (UnimplementedMangaServiceServer) mustEmbedUnimplementedMangaServiceServer() {}
 
 // RegisterMangaServiceServer registers the MangaServiceServer with the gRPC server
@@ -103,7 +147,13 @@ var MangaService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _MangaService_UpdateProgress_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamProgress",
+			Handler:       _MangaService_StreamProgress_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "manga.proto",
 }
 
@@ -161,6 +211,28 @@ func _MangaService_UpdateProgress_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MangaService_StreamProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MangaServiceServer).StreamProgress(m, &mangaServiceStreamProgressServer{stream})
+}
+
+// MangaService_StreamProgressServer sends progress updates to a client
+type MangaService_StreamProgressServer interface {
+	Send(*ProgressUpdate) error
+	grpc.ServerStream
+}
+
+type mangaServiceStreamProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *mangaServiceStreamProgressServer) Send(m *ProgressUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // GetMangaRequest is the request message
 type GetMangaRequest struct {
 	MangaId int64 `protobuf:"varint,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
@@ -218,3 +290,17 @@ type Manga struct {
 	CreatedAt    string   `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt    string   `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 }
+
+// StreamProgressRequest is the request message for StreamProgress
+type StreamProgressRequest struct {
+	MangaId int64 `protobuf:"varint,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+}
+
+// ProgressUpdate is a progress change pushed by StreamProgress
+type ProgressUpdate struct {
+	UserId         int64  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId        int64  `protobuf:"varint,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	CurrentChapter int32  `protobuf:"varint,3,opt,name=current_chapter,json=currentChapter,proto3" json:"current_chapter,omitempty"`
+	ChapterId      int64  `protobuf:"varint,4,opt,name=chapter_id,json=chapterId,proto3" json:"chapter_id,omitempty"`
+	Timestamp      string `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
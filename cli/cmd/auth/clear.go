@@ -33,11 +33,11 @@ var clearCmd = &cobra.Command{
 			return nil
 		}
 
-		cmd.Println("Clearing authentication data...\n")
+		cmd.Print("Clearing authentication data...\n\n")
 		cmd.Println("✓ Authentication token removed")
 		cmd.Println("✓ User session cleared")
 		cmd.Println("✓ Sync connections terminated")
-		cmd.Println("✓ Cache cleared\n")
+		cmd.Print("✓ Cache cleared\n\n")
 		cmd.Println("You are now logged out. To continue using MangaHub:")
 		cmd.Println("mangahub auth login --username <your-username>")
 		cmd.Println("Or register a new account:")
@@ -3,6 +3,7 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"syscall"
@@ -70,6 +71,7 @@ func runAuthLogin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	cfg.Data.RefreshToken = resp.RefreshToken
 	if err := cfg.UpdateSession(resp.Token, resp.ExpiresAt, resp.User.Username, resp.User.Permissions, resp.User.Settings.Autosync, resp.User.Settings.Notifications); err != nil {
 		return err
 	}
@@ -85,13 +87,13 @@ func runAuthLogin(cmd *cobra.Command, args []string) error {
 
 func handleLoginError(cmd *cobra.Command, err error, username, email string) {
 	if apiErr, ok := err.(*api.Error); ok {
-		switch apiErr.Code {
-		case "invalid_credentials":
+		switch {
+		case apiErr.Status == http.StatusUnauthorized || apiErr.Code == "invalid_credentials":
 			cmd.Println("❌ 1. Invalid credentials")
 			cmd.Println("✗ Login failed: Invalid credentials")
 			cmd.Println("Check your username and password")
 			os.Exit(1)
-		case "account_not_found":
+		case apiErr.Status == http.StatusNotFound || apiErr.Code == "account_not_found":
 			cmd.Println("❌ 2. Account not found")
 			cmd.Println("✗ Login failed: Account not found")
 			if username != "" {
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ngocan-dev/mangahub_/cli/internal/config"
+)
+
+func TestLoginStoresTokenAndLogoutClearsIt(t *testing.T) {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"user_id":1,"exp":1893456000}`))
+	token := "header." + claims + ".signature"
+	var loggedOut bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["email"] != "alice" || body["password"] != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid credentials"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"token":         token,
+				"refresh_token": "refresh-1",
+				"user":          map[string]any{"id": 1, "username": "alice"},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/logout":
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			loggedOut = true
+			_, _ = w.Write([]byte(`{"message":"logged out"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if _, err := config.LoadWithOptions(config.LoadOptions{Path: path, APIEndpoint: srv.URL}); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	login := NewLoginCommand("login", "", "")
+	login.SetArgs([]string{"--username", "alice", "--password", "secret"})
+	login.SetOut(&bytes.Buffer{})
+	if err := login.Execute(); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	stored, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if stored.Data.Token != token || stored.Data.RefreshToken != "refresh-1" || stored.Data.Auth.Username != "alice" {
+		t.Fatalf("expected session to be persisted, got token=%q refresh=%q user=%q", stored.Data.Token, stored.Data.RefreshToken, stored.Data.Auth.Username)
+	}
+	if stored.Data.ExpiresAt != "2030-01-01T00:00:00Z" {
+		t.Fatalf("expected expiry from token claims, got %q", stored.Data.ExpiresAt)
+	}
+
+	logout := NewLogoutCommand("logout", "", "", "")
+	logout.SetArgs([]string{})
+	logout.SetOut(&bytes.Buffer{})
+	if err := logout.Execute(); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if !loggedOut {
+		t.Fatalf("expected logout to be sent to the server")
+	}
+
+	stored, err = config.Load(path)
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if stored.Data.Token != "" || stored.Data.RefreshToken != "" {
+		t.Fatalf("expected session to be cleared, got token=%q refresh=%q", stored.Data.Token, stored.Data.RefreshToken)
+	}
+}
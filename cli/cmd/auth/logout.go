@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 
+	"github.com/ngocan-dev/mangahub_/cli/internal/api"
 	"github.com/ngocan-dev/mangahub_/cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -43,6 +44,14 @@ func runAuthLogout(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
+	// Revoke the session server-side; local credentials are cleared regardless
+	if cfg.Data.Token != "" {
+		client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
+		if err := client.Logout(cmd.Context(), cfg.Data.RefreshToken); err != nil && config.Runtime().Verbose {
+			cmd.Printf("Server-side logout failed: %v\n", err)
+		}
+	}
+
 	if err := cfg.ClearSession(); err != nil {
 		return err
 	}
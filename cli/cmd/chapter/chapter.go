@@ -2,9 +2,14 @@ package chapter
 
 import "github.com/spf13/cobra"
 
-// ChapterCmd groups chapter-related commands.
+// ChapterCmd groups chapter-related commands. Run with a manga ID and chapter
+// number it opens that chapter for reading.
 var ChapterCmd = &cobra.Command{
-	Use:   "chapter",
+	Use:   "chapter <manga-id> <chapter>",
 	Short: "Browse and read chapters",
 	Long:  "List and read chapter content from MangaHub.",
+	Example: `mangahub chapter 42 3
+mangahub chapter list 42`,
+	Args: cobra.ExactArgs(2),
+	RunE: runOpen,
 }
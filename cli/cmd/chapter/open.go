@@ -0,0 +1,135 @@
+package chapter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ngocan-dev/mangahub_/cli/internal/api"
+	"github.com/ngocan-dev/mangahub_/cli/internal/config"
+	"github.com/ngocan-dev/mangahub_/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const defaultPageLines = 30
+
+var pageLines int
+
+func init() {
+	output.AddFlag(ChapterCmd)
+	ChapterCmd.Flags().IntVar(&pageLines, "page-lines", defaultPageLines, "Lines shown per page when reading in a terminal (0 disables paging)")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	format, err := output.GetFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	mangaID, err := strconv.ParseInt(strings.TrimSpace(args[0]), 10, 64)
+	if err != nil || mangaID <= 0 {
+		return errors.New("manga-id must be a valid number")
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(args[1]))
+	if err != nil || number <= 0 {
+		return errors.New("chapter must be a valid number")
+	}
+
+	cfg := config.ManagerInstance()
+	if cfg == nil {
+		return errors.New("configuration not loaded")
+	}
+
+	client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
+	chapters, err := client.ListChapters(cmd.Context(), mangaID)
+	if err != nil {
+		return err
+	}
+
+	var chapterID int64
+	for _, ch := range chapters {
+		if ch.Number == number {
+			chapterID = ch.ID
+			break
+		}
+	}
+	if chapterID == 0 {
+		return fmt.Errorf("chapter %d not found for manga %d (see 'mangahub chapter list %d')", number, mangaID, mangaID)
+	}
+
+	chapter, err := client.GetChapter(cmd.Context(), chapterID)
+	if err != nil {
+		return err
+	}
+
+	if format == output.FormatJSON {
+		output.PrintJSON(cmd, chapter)
+		return nil
+	}
+
+	if config.Runtime().Quiet {
+		cmd.Println(chapter.Content)
+		return nil
+	}
+
+	cmd.Printf("Chapter %d of %d", chapter.Number, len(chapters))
+	if title := strings.TrimSpace(chapter.Title); title != "" {
+		cmd.Printf(": %s", title)
+	}
+	cmd.Println()
+	cmd.Println()
+
+	lines := pageLines
+	if !isTerminal(cmd.OutOrStdout()) {
+		lines = 0
+	}
+	return pageContent(cmd.OutOrStdout(), cmd.InOrStdin(), chapter.Content, lines)
+}
+
+// pageContent writes content a page of lines at a time, waiting for Enter
+// between pages. Entering q stops reading. lines <= 0 writes everything at once.
+func pageContent(out io.Writer, in io.Reader, content string, lines int) error {
+	all := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if lines <= 0 || len(all) <= lines {
+		_, err := fmt.Fprintln(out, strings.Join(all, "\n"))
+		return err
+	}
+
+	reader := bufio.NewReader(in)
+	for start := 0; start < len(all); start += lines {
+		end := start + lines
+		if end > len(all) {
+			end = len(all)
+		}
+		if _, err := fmt.Fprintln(out, strings.Join(all[start:end], "\n")); err != nil {
+			return err
+		}
+		if end == len(all) {
+			break
+		}
+
+		fmt.Fprintf(out, "-- %d%% -- Enter for more, q to quit ", end*100/len(all))
+		answer, err := reader.ReadString('\n')
+		fmt.Fprintln(out)
+		if strings.EqualFold(strings.TrimSpace(answer), "q") || err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -19,111 +19,139 @@ var MangaCmd = &cobra.Command{
 	Long:  "Search and retrieve manga information from MangaHub services.",
 }
 
-// searchCmd performs manga searches against the backend service.
-var searchCmd = &cobra.Command{
-	Use:     "search <query>",
-	Short:   "Search for manga titles",
-	Long:    "Search MangaHub for manga titles using keywords.",
-	Example: "mangahub manga search \"one piece\"",
-	Args:    cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		format, err := output.GetFormat(cmd)
-		if err != nil {
-			return err
-		}
-
-		query := args[0]
+// NewSearchCommand builds the catalog search command. It is mounted both as
+// `manga search` and as the top-level `search`.
+func NewSearchCommand(use, example string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     use,
+		Short:   "Search for manga titles",
+		Long:    "Search the MangaHub catalog by keyword with optional genre, status, author, rating, year and chapter filters.",
+		Example: example,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runSearch,
+	}
+	cmd.Flags().String("genre", "", "Filter by genre (comma-separated)")
+	cmd.Flags().String("status", "", "Filter by status (ongoing|completed|hiatus|canceled)")
+	cmd.Flags().String("author", "", "Filter by author")
+	cmd.Flags().Float64("min-rating", 0, "Only include manga rated at least this value")
+	cmd.Flags().Float64("max-rating", 0, "Only include manga rated at most this value")
+	cmd.Flags().Int("year-from", 0, "Filter by starting publication year")
+	cmd.Flags().Int("year-to", 0, "Filter by ending publication year")
+	cmd.Flags().Int("min-chapters", 0, "Filter by minimum chapter count")
+	cmd.Flags().String("sort", "", "Sort by (relevance|rating|date_updated)")
+	cmd.Flags().String("sort-by", "", "Sort by (relevance|rating|date_updated)")
+	_ = cmd.Flags().MarkDeprecated("sort-by", "use --sort instead")
+	cmd.Flags().String("order", "", "Sort order (asc|desc)")
+	cmd.Flags().Int("page", 1, "Results page to show")
+	cmd.Flags().Int("limit", 20, "Results per page")
+	output.AddFlag(cmd)
+	return cmd
+}
 
-		cfg := config.ManagerInstance()
-		if cfg == nil {
-			return errors.New("configuration not loaded")
-		}
+func runSearch(cmd *cobra.Command, args []string) error {
+	format, err := output.GetFormat(cmd)
+	if err != nil {
+		return err
+	}
 
-		filters, err := parseSearchFilters(cmd)
-		if err != nil {
-			return err
-		}
+	cfg := config.ManagerInstance()
+	if cfg == nil {
+		return errors.New("configuration not loaded")
+	}
 
-		client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
-		resp, err := client.SearchManga(cmd.Context(), query, filters)
-		if err != nil {
-			return err
-		}
+	req, err := buildSearchRequest(cmd, args[0])
+	if err != nil {
+		return err
+	}
 
-		results := resp.Results
-		if format == output.FormatJSON {
-			output.PrintJSON(cmd, map[string]any{
-				"results": results,
-				"total":   resp.Total,
-				"page":    resp.Page,
-				"limit":   resp.Limit,
-				"pages":   resp.Pages,
-			})
-			return nil
-		}
+	client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
+	resp, err := client.SearchManga(cmd.Context(), req)
+	if err != nil {
+		return err
+	}
 
-		if config.Runtime().Verbose {
-			output.PrintJSON(cmd, results)
-			return nil
-		}
+	results := resp.Results
+	if format == output.FormatJSON {
+		output.PrintJSON(cmd, map[string]any{
+			"results": results,
+			"total":   resp.Total,
+			"page":    resp.Page,
+			"limit":   resp.Limit,
+			"pages":   resp.Pages,
+		})
+		return nil
+	}
 
-		if config.Runtime().Quiet {
-			for _, res := range results {
-				cmd.Println(res.ID)
-			}
-			return nil
-		}
+	if config.Runtime().Verbose {
+		output.PrintJSON(cmd, results)
+		return nil
+	}
 
-		cmd.Printf("Searching for \"%s\"...\n", query)
-		if len(results) == 0 {
-			cmd.Println("No manga found matching your search criteria.")
-			cmd.Println("\nSuggestions:")
-			cmd.Println("- Check spelling and try again")
-			cmd.Println("- Use broader search terms")
-			cmd.Println("- Browse by genre: mangahub manga list --genre action")
-			return nil
+	if config.Runtime().Quiet {
+		for _, res := range results {
+			cmd.Println(res.ID)
 		}
+		return nil
+	}
 
-		if resp.Total > 0 {
-			cmd.Printf("Found %d results (page %d/%d):\n", resp.Total, resp.Page, resp.Pages)
-		} else {
-			cmd.Printf("Found %d results:\n", len(results))
-		}
-		table := buildMangaTable(fromSearchResults(results))
-		cmd.Print(table)
-		cmd.Println("\nUse 'mangahub manga info <id>' to view details")
-		cmd.Println("Use 'mangahub library add --manga-id <id>' to add to your library")
+	cmd.Printf("Searching for \"%s\"...\n", req.Query)
+	if len(results) == 0 {
+		cmd.Println("No manga found matching your search criteria.")
+		cmd.Println("\nSuggestions:")
+		cmd.Println("- Check spelling and try again")
+		cmd.Println("- Use broader search terms")
+		cmd.Println("- Browse by genre: mangahub manga list --genre action")
 		return nil
-	},
+	}
+
+	cmd.Print(renderSearchResults(resp))
+	if resp.Page < resp.Pages {
+		cmd.Printf("\nMore results: rerun with --page %d\n", resp.Page+1)
+	}
+	cmd.Println("\nUse 'mangahub manga info <id>' to view details")
+	cmd.Println("Use 'mangahub library add --manga-id <id>' to add to your library")
+	return nil
 }
 
 func init() {
-	MangaCmd.AddCommand(searchCmd)
-	searchCmd.Flags().String("genre", "", "Filter by genre (comma-separated)")
-	searchCmd.Flags().String("status", "", "Filter by status (ongoing|completed|hiatus|canceled)")
-	searchCmd.Flags().String("author", "", "Filter by author")
-	searchCmd.Flags().Int("year-from", 0, "Filter by starting publication year")
-	searchCmd.Flags().Int("year-to", 0, "Filter by ending publication year")
-	searchCmd.Flags().Int("min-chapters", 0, "Filter by minimum chapter count")
-	searchCmd.Flags().String("sort-by", "", "Sort by field (title|chapters|year|popularity)")
-	searchCmd.Flags().String("order", "", "Sort order (asc|desc)")
-	searchCmd.Flags().Int("limit", 0, "Limit number of results")
-	output.AddFlag(searchCmd)
+	MangaCmd.AddCommand(NewSearchCommand("search <query>", "mangahub manga search \"one piece\""))
 }
 
-func parseSearchFilters(cmd *cobra.Command) (api.MangaSearchFilters, error) {
-	status, _ := cmd.Flags().GetString("status")
-	sortBy, _ := cmd.Flags().GetString("sort-by")
-	order, _ := cmd.Flags().GetString("order")
+// buildSearchRequest validates the search flags and maps them onto the API request.
+func buildSearchRequest(cmd *cobra.Command, query string) (api.SearchRequest, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return api.SearchRequest{}, errors.New("query cannot be empty")
+	}
+
 	genre, _ := cmd.Flags().GetString("genre")
+	status, _ := cmd.Flags().GetString("status")
 	author, _ := cmd.Flags().GetString("author")
-	yearFrom, _ := cmd.Flags().GetInt("year-from")
-	yearTo, _ := cmd.Flags().GetInt("year-to")
 	minChapters, _ := cmd.Flags().GetInt("min-chapters")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	order, _ := cmd.Flags().GetString("order")
+	page, _ := cmd.Flags().GetInt("page")
 	limit, _ := cmd.Flags().GetInt("limit")
+	// --sort-by is the old spelling of --sort; --sort wins when both are given
+	if !cmd.Flags().Changed("sort") {
+		sortBy, _ = cmd.Flags().GetString("sort-by")
+	}
+
+	req := api.SearchRequest{Query: query, Author: strings.TrimSpace(author), MinChapters: minChapters, Page: page, Limit: limit}
+	if minChapters < 0 {
+		return api.SearchRequest{}, errors.New("--min-chapters cannot be negative")
+	}
+	if page < 1 {
+		return api.SearchRequest{}, errors.New("--page must be at least 1")
+	}
+	if limit < 1 || limit > 100 {
+		return api.SearchRequest{}, errors.New("--limit must be between 1 and 100")
+	}
 
-	if yearFrom > 0 && yearTo > 0 && yearFrom > yearTo {
-		return api.MangaSearchFilters{}, errors.New("--year-from cannot be greater than --year-to")
+	for _, g := range strings.Split(genre, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			req.Genres = append(req.Genres, g)
+		}
 	}
 
 	if status != "" {
@@ -131,17 +159,19 @@ func parseSearchFilters(cmd *cobra.Command) (api.MangaSearchFilters, error) {
 		switch status {
 		case "ongoing", "completed", "hiatus", "canceled":
 		default:
-			return api.MangaSearchFilters{}, errors.New("--status must be one of: ongoing, completed, hiatus, canceled")
+			return api.SearchRequest{}, errors.New("--status must be one of: ongoing, completed, hiatus, canceled")
 		}
+		req.Status = status
 	}
 
 	if sortBy != "" {
 		sortBy = strings.ToLower(sortBy)
 		switch sortBy {
-		case "title", "chapters", "year", "popularity":
+		case "relevance", "rating", "date_updated":
 		default:
-			return api.MangaSearchFilters{}, errors.New("--sort-by must be one of: title, chapters, year, popularity")
+			return api.SearchRequest{}, errors.New("--sort must be one of: relevance, rating, date_updated")
 		}
+		req.SortBy = sortBy
 	}
 
 	if order != "" {
@@ -149,40 +179,64 @@ func parseSearchFilters(cmd *cobra.Command) (api.MangaSearchFilters, error) {
 		switch order {
 		case "asc", "desc":
 		default:
-			return api.MangaSearchFilters{}, errors.New("--order must be one of: asc, desc")
+			return api.SearchRequest{}, errors.New("--order must be one of: asc, desc")
 		}
+		req.Order = order
 	}
 
-	return api.MangaSearchFilters{
-		Genre:       strings.TrimSpace(genre),
-		Status:      status,
-		Author:      strings.TrimSpace(author),
-		YearFrom:    yearFrom,
-		YearTo:      yearTo,
-		MinChapters: minChapters,
-		SortBy:      sortBy,
-		Order:       order,
-		Limit:       limit,
-	}, nil
-}
+	if cmd.Flags().Changed("min-rating") {
+		v, _ := cmd.Flags().GetFloat64("min-rating")
+		req.MinRating = &v
+	}
+	if cmd.Flags().Changed("max-rating") {
+		v, _ := cmd.Flags().GetFloat64("max-rating")
+		req.MaxRating = &v
+	}
+	if req.MinRating != nil && req.MaxRating != nil && *req.MinRating > *req.MaxRating {
+		return api.SearchRequest{}, errors.New("--min-rating cannot be greater than --max-rating")
+	}
 
-func buildMangaTable(rows [][]string) string {
-	t := utils.Table{Headers: []string{"ID", "Title", "Author", "Status", "Genres"}, Rows: rows}
-	return t.Render()
+	if cmd.Flags().Changed("year-from") {
+		v, _ := cmd.Flags().GetInt("year-from")
+		req.YearFrom = &v
+	}
+	if cmd.Flags().Changed("year-to") {
+		v, _ := cmd.Flags().GetInt("year-to")
+		req.YearTo = &v
+	}
+	if req.YearFrom != nil && req.YearTo != nil && *req.YearFrom > *req.YearTo {
+		return api.SearchRequest{}, errors.New("--year-from cannot be greater than --year-to")
+	}
+
+	return req, nil
 }
 
-func fromSearchResults(results []api.MangaSearchResult) [][]string {
-	var rows [][]string
-	for _, res := range results {
-		rows = append(rows, []string{
+// renderSearchResults formats a search page as a table with a result summary.
+func renderSearchResults(resp *api.MangaSearchResponse) string {
+	var b strings.Builder
+	if resp.Total > 0 {
+		fmt.Fprintf(&b, "Found %d results (page %d/%d):\n", resp.Total, resp.Page, resp.Pages)
+	} else {
+		fmt.Fprintf(&b, "Found %d results:\n", len(resp.Results))
+	}
+
+	t := utils.Table{Headers: []string{"ID", "Title", "Author", "Rating", "Status"}}
+	for _, res := range resp.Results {
+		t.AddRow(
 			fmt.Sprintf("%d", res.ID),
 			formatTitleCell(res.Title, res.Name),
 			formatAuthorCell(res.Author),
+			fmt.Sprintf("%.1f", res.RatingPoint),
 			formatStatus(res.Status),
-			formatGenres(res.Genre),
-		})
+		)
 	}
-	return rows
+	b.WriteString(t.Render())
+	return b.String()
+}
+
+func buildMangaTable(rows [][]string) string {
+	t := utils.Table{Headers: []string{"ID", "Title", "Author", "Status", "Genres"}, Rows: rows}
+	return t.Render()
 }
 
 func formatTitleCell(title, name string) string {
@@ -220,11 +274,3 @@ func formatStatus(status string) string {
 		return status
 	}
 }
-
-func formatGenres(genre string) string {
-	genre = strings.TrimSpace(genre)
-	if genre == "" {
-		return "-"
-	}
-	return genre
-}
@@ -0,0 +1,124 @@
+package manga
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ngocan-dev/mangahub_/cli/internal/api"
+)
+
+func TestBuildSearchRequestFromFlags(t *testing.T) {
+	cmd := NewSearchCommand("search <query>", "")
+	if err := cmd.ParseFlags([]string{
+		"--genre", "action, fantasy",
+		"--status", "Ongoing",
+		"--min-rating", "4.5",
+		"--sort", "rating",
+		"--page", "2",
+		"--limit", "10",
+	}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	req, err := buildSearchRequest(cmd, " one piece ")
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	if req.Query != "one piece" || req.Status != "ongoing" || req.SortBy != "rating" || req.Page != 2 || req.Limit != 10 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if len(req.Genres) != 2 || req.Genres[0] != "action" || req.Genres[1] != "fantasy" {
+		t.Fatalf("unexpected genres %v", req.Genres)
+	}
+	if req.MinRating == nil || *req.MinRating != 4.5 || req.MaxRating != nil || req.YearFrom != nil {
+		t.Fatalf("expected only min rating to be set, got %+v", req)
+	}
+
+	q := req.Values()
+	if q.Get("genres") != "action,fantasy" || q.Get("min_rating") != "4.5" || q.Get("sort_by") != "rating" || q.Has("max_rating") {
+		t.Fatalf("unexpected query %s", q.Encode())
+	}
+}
+
+func TestBuildSearchRequestRejectsInvalidFlags(t *testing.T) {
+	cmd := NewSearchCommand("search <query>", "")
+	if err := cmd.ParseFlags([]string{"--sort", "chapters"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if _, err := buildSearchRequest(cmd, "naruto"); err == nil {
+		t.Fatalf("expected an error for an unsupported sort")
+	}
+}
+
+func TestBuildSearchRequestKeepsLegacyFlags(t *testing.T) {
+	cmd := NewSearchCommand("search <query>", "")
+	if err := cmd.ParseFlags([]string{
+		"--author", " Oda ",
+		"--min-chapters", "100",
+		"--sort-by", "Rating",
+		"--order", "DESC",
+	}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	req, err := buildSearchRequest(cmd, "pirates")
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	if req.Author != "Oda" || req.MinChapters != 100 || req.SortBy != "rating" || req.Order != "desc" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	q := req.Values()
+	if q.Get("author") != "Oda" || q.Get("min_chapters") != "100" || q.Get("sort_by") != "rating" || q.Get("order") != "desc" {
+		t.Fatalf("unexpected query %s", q.Encode())
+	}
+
+	// --sort takes precedence over its deprecated alias
+	cmd = NewSearchCommand("search <query>", "")
+	if err := cmd.ParseFlags([]string{"--sort-by", "rating", "--sort", "date_updated"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if req, err := buildSearchRequest(cmd, "pirates"); err != nil || req.SortBy != "date_updated" {
+		t.Fatalf("expected --sort to win, got %+v (err %v)", req, err)
+	}
+
+	for _, args := range [][]string{{"--order", "sideways"}, {"--min-chapters", "-1"}, {"--sort-by", "chapters"}} {
+		cmd = NewSearchCommand("search <query>", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		if _, err := buildSearchRequest(cmd, "pirates"); err == nil {
+			t.Fatalf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestMangaSearchFiltersRequest(t *testing.T) {
+	req := api.MangaSearchFilters{Genre: "action, drama", Author: "Oda", YearFrom: 1997, MinChapters: 50, Order: "asc", Limit: 5}.Request("one piece")
+	if req.Query != "one piece" || req.Author != "Oda" || req.MinChapters != 50 || req.Order != "asc" || req.Limit != 5 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if len(req.Genres) != 2 || req.Genres[1] != "drama" {
+		t.Fatalf("unexpected genres %v", req.Genres)
+	}
+	if req.YearFrom == nil || *req.YearFrom != 1997 || req.YearTo != nil {
+		t.Fatalf("expected only year_from to be set, got %+v", req)
+	}
+}
+
+func TestRenderSearchResults(t *testing.T) {
+	out := renderSearchResults(&api.MangaSearchResponse{
+		Results: []api.MangaSearchResult{
+			{ID: 3, Title: "Berserk", Author: "Miura", Status: "completed", RatingPoint: 4.82},
+		},
+		Total: 21,
+		Page:  1,
+		Pages: 3,
+	})
+
+	for _, want := range []string{"Found 21 results (page 1/3)", "Rating", "Berserk", "Miura", "4.8", "Completed"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -35,7 +35,7 @@ var searchCmd = &cobra.Command{
 		}
 
 		client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
-		resp, err := client.SearchManga(cmd.Context(), keyword, api.MangaSearchFilters{})
+		resp, err := client.SearchManga(cmd.Context(), api.SearchRequest{Query: keyword})
 		if err != nil {
 			return err
 		}
@@ -24,10 +24,12 @@ var updateCmd = &cobra.Command{
 	Long:  "Update reading progress for a specific manga and chapter.",
 	Example: strings.Join([]string{
 		"mangahub progress update --manga-id <id> --chapter <number>",
+		"mangahub progress update --manga-id <id> --chapter <number> --force",
 	}, "\n"),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		mangaID, _ := cmd.Flags().GetString("manga-id")
 		chapter, _ := cmd.Flags().GetInt("chapter")
+		force, _ := cmd.Flags().GetBool("force")
 
 		if mangaID == "" {
 			return fmt.Errorf("--manga-id is required")
@@ -45,7 +47,7 @@ var updateCmd = &cobra.Command{
 		}
 
 		client := api.NewClient(cfg.Data.BaseURL, cfg.Data.Token)
-		resp, err := client.UpdateProgress(cmd.Context(), mangaID, chapter)
+		resp, err := client.UpdateProgress(cmd.Context(), mangaID, chapter, force)
 		if err != nil {
 			return err
 		}
@@ -56,7 +58,12 @@ var updateCmd = &cobra.Command{
 		}
 
 		cmd.Println("Updating reading progress...")
-		cmd.Println("✓ Progress updated successfully.")
+		if resp.Conflict {
+			cmd.Println("! A higher chapter from another device was kept.")
+			cmd.Println("  Use --force to move progress backwards.")
+		} else {
+			cmd.Println("✓ Progress updated successfully.")
+		}
 		cmd.Println("")
 		cmd.Printf("Manga ID: %s\n", mangaID)
 		if resp.UserProgress != nil {
@@ -98,6 +105,7 @@ func init() {
 	ProgressCmd.AddCommand(updateCmd)
 	updateCmd.Flags().String("manga-id", "", "Manga identifier")
 	updateCmd.Flags().Int("chapter", 0, "Chapter number")
+	updateCmd.Flags().Bool("force", false, "Allow moving progress backwards")
 	updateCmd.MarkFlagRequired("manga-id")
 	updateCmd.MarkFlagRequired("chapter")
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/ngocan-dev/mangahub_/cli/cmd/system"
 	"github.com/ngocan-dev/mangahub_/cli/cmd/update"
 	"github.com/ngocan-dev/mangahub_/cli/cmd/user"
+	"github.com/ngocan-dev/mangahub_/cli/internal/api"
 	"github.com/ngocan-dev/mangahub_/cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -49,7 +50,7 @@ var rootCmd = &cobra.Command{
 	Long: "MangaHub CLI – manage your manga library, reading progress, and servers.\n\n" +
 		"Usage:\n  mangahub [command]\n\n" +
 		"Top-level commands include:\n" +
-		"  auth, manga, library, progress, server, stats, export, backup, db, chat, config, profile, logs, sync\n\n" +
+		"  auth, manga, search, library, progress, server, stats, export, backup, db, chat, config, profile, logs, sync\n\n" +
 		"Use \"mangahub [command] help\" for more information about a command.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
@@ -76,13 +77,17 @@ func init() {
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		config.SetRuntimeOptions(verbose, quiet)
-		_, err := config.LoadWithOptions(config.LoadOptions{
+		mgr, err := config.LoadWithOptions(config.LoadOptions{
 			Path:        cfgFile,
 			APIEndpoint: apiOverride,
 			GRPCAddress: grpcOverride,
 			TCPAddress:  tcpOverride,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		api.SetTokenStore(mgr)
+		return nil
 	}
 
 	rootCmd.AddCommand(server.ServerCmd)
@@ -96,6 +101,7 @@ func init() {
 	rootCmd.AddCommand(backup.BackupCmd)
 	rootCmd.AddCommand(db.DBCmd)
 	rootCmd.AddCommand(manga.MangaCmd)
+	rootCmd.AddCommand(manga.NewSearchCommand("search <query>", "mangahub search \"one piece\" --genre action --min-rating 4 --sort rating"))
 	rootCmd.AddCommand(novel.NovelCmd)
 	rootCmd.AddCommand(chapter.ChapterCmd)
 	rootCmd.AddCommand(library.LibraryCmd)
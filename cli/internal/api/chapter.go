@@ -22,12 +22,41 @@ type Chapter struct {
 	Content string `json:"content"`
 }
 
+// chapterPayload mirrors the server's GET /chapters/:id response.
+type chapterPayload struct {
+	ID            int64  `json:"id"`
+	MangaID       int64  `json:"manga_id"`
+	ChapterNumber int    `json:"chapter_number"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+}
+
 // GetChapter retrieves a chapter by its identifier.
 func (c *Client) GetChapter(ctx context.Context, chapterID int64) (*Chapter, error) {
-	var resp Chapter
+	var resp chapterPayload
 	endpoint := fmt.Sprintf("/chapters/%d", chapterID)
 	if err := c.doRequest(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
 		return nil, err
 	}
-	return &resp, nil
+	return &Chapter{
+		ChapterSummary: ChapterSummary{
+			ID:      resp.ID,
+			MangaID: resp.MangaID,
+			Number:  resp.ChapterNumber,
+			Title:   resp.Title,
+		},
+		Content: resp.ContentText,
+	}, nil
+}
+
+// ListChapters retrieves the chapter list of a manga in reading order.
+func (c *Client) ListChapters(ctx context.Context, mangaID int64) ([]ChapterSummary, error) {
+	var resp struct {
+		Chapters []ChapterSummary `json:"chapters"`
+	}
+	endpoint := fmt.Sprintf("/mangas/%d", mangaID)
+	if err := c.doRequest(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Chapters, nil
 }
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetChapterAndListChapters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/mangas/7":
+			_, _ = w.Write([]byte(`{"id":7,"title":"Test","chapters":[{"id":70,"manga_id":7,"number":1,"title":"Start"},{"id":71,"manga_id":7,"number":2,"title":"Next"}]}`))
+		case "/chapters/71":
+			_, _ = w.Write([]byte(`{"id":71,"manga_id":7,"chapter_number":2,"title":"Next","content_text":"line one\nline two","created_at":""}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "")
+	ctx := context.Background()
+
+	chapters, err := client.ListChapters(ctx, 7)
+	if err != nil {
+		t.Fatalf("list chapters: %v", err)
+	}
+	if len(chapters) != 2 || chapters[1].ID != 71 || chapters[1].Number != 2 {
+		t.Fatalf("unexpected chapters %+v", chapters)
+	}
+
+	chapter, err := client.GetChapter(ctx, 71)
+	if err != nil {
+		t.Fatalf("get chapter: %v", err)
+	}
+	if chapter.Number != 2 || chapter.MangaID != 7 || chapter.Title != "Next" || chapter.Content != "line one\nline two" {
+		t.Fatalf("unexpected chapter %+v", chapter)
+	}
+
+	if _, err := client.GetChapter(ctx, 99); err == nil {
+		t.Fatalf("expected an error for a missing chapter")
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,10 +12,24 @@ import (
 	"time"
 )
 
+// TokenStore provides the stored refresh token and persists renewed credentials.
+type TokenStore interface {
+	RefreshToken() string
+	UpdateTokens(token, refreshToken string) error
+}
+
+var defaultTokenStore TokenStore
+
+// SetTokenStore registers the store used by new clients to renew expired access tokens.
+func SetTokenStore(store TokenStore) {
+	defaultTokenStore = store
+}
+
 // Client is a reusable HTTP API client for MangaHub.
 type Client struct {
 	baseURL    string
 	token      string
+	store      TokenStore
 	httpClient *http.Client
 }
 
@@ -23,6 +38,7 @@ func NewClient(baseURL, token string) *Client {
 	return &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
 		token:      token,
+		store:      defaultTokenStore,
 		httpClient: &http.Client{Timeout: 15 * time.Second},
 	}
 }
@@ -40,16 +56,45 @@ func (c *Client) Register(ctx context.Context, username, email, password string)
 }
 
 // Login authenticates a user using either username or email and returns session details.
+// The server accepts either identifier in its email field.
 func (c *Client) Login(ctx context.Context, username, email, password string) (*LoginResponse, error) {
+	identifier := email
+	if identifier == "" {
+		identifier = username
+	}
 	payload := map[string]any{
-		"username": username,
-		"email":    email,
+		"email":    identifier,
 		"password": password,
 	}
 
 	var resp LoginResponse
-	err := c.doRequest(ctx, http.MethodPost, "/auth/login", payload, &resp)
-	return &resp, err
+	if err := c.doRequest(ctx, http.MethodPost, "/login", payload, &resp); err != nil {
+		return &resp, err
+	}
+	if resp.ExpiresAt == "" {
+		resp.ExpiresAt = tokenExpiry(resp.Token)
+	}
+	return &resp, nil
+}
+
+// tokenExpiry reads the exp claim of a JWT as RFC3339, or "" when it has none.
+// The signature is not verified; the server remains the authority.
+func tokenExpiry(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.Exp == 0 {
+		return ""
+	}
+	return time.Unix(claims.Exp, 0).UTC().Format(time.RFC3339)
 }
 
 // ChangePassword updates the user's password using the provided current and new password.
@@ -64,11 +109,31 @@ func (c *Client) ChangePassword(ctx context.Context, currentPassword, newPasswor
 	return resp, err
 }
 
+// Refresh exchanges a refresh token for a new access token.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*RefreshResponse, error) {
+	payload := map[string]string{
+		"refresh_token": refreshToken,
+	}
+
+	var resp RefreshResponse
+	err := c.doRequest(ctx, http.MethodPost, "/auth/refresh", payload, &resp)
+	return &resp, err
+}
+
+// Logout revokes the current access token and, if provided, the refresh token on the server.
+func (c *Client) Logout(ctx context.Context, refreshToken string) error {
+	payload := map[string]string{
+		"refresh_token": refreshToken,
+	}
+	return c.doRequest(ctx, http.MethodPost, "/logout", payload, nil)
+}
+
 // LoginResponse represents the authentication response payload.
 type LoginResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt string `json:"expires_at"`
-	User      struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    string `json:"expires_at"`
+	User         struct {
 		Username    string   `json:"username"`
 		Permissions []string `json:"permissions"`
 		Settings    struct {
@@ -78,33 +143,46 @@ type LoginResponse struct {
 	} `json:"user"`
 }
 
+// RefreshResponse represents the token refresh response payload.
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 func (c *Client) doRequest(ctx context.Context, method, path string, body any, target any) error {
-	var reader io.Reader
+	var payload []byte
 	if body != nil {
 		buf, err := json.Marshal(body)
 		if err != nil {
 			return err
 		}
-		reader = bytes.NewBuffer(buf)
+		payload = buf
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
-	if err != nil {
-		return err
-	}
-	c.applyHeaders(req)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(ctx, method, path, payload)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
 	if err := checkStatus(res); err != nil {
-		return err
+		if !c.shouldRefresh(path, err) {
+			return err
+		}
+		if refreshErr := c.refreshSession(ctx); refreshErr != nil {
+			return err
+		}
+
+		// Retry once with the renewed access token
+		res, err = c.send(ctx, method, path, payload)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if err := checkStatus(res); err != nil {
+			return err
+		}
 	}
 
 	if target != nil {
@@ -115,6 +193,51 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body any, t
 	return nil
 }
 
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	c.applyHeaders(req)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// shouldRefresh reports whether a failed request was rejected only because the access token expired.
+func (c *Client) shouldRefresh(path string, err error) bool {
+	if c.store == nil || c.store.RefreshToken() == "" || path == "/auth/refresh" {
+		return false
+	}
+	apiErr, ok := err.(*Error)
+	return ok && apiErr.Status == http.StatusUnauthorized && apiErr.Reason == "TOKEN_EXPIRED"
+}
+
+// refreshSession renews the access token and persists the new credentials.
+func (c *Client) refreshSession(ctx context.Context) error {
+	resp, err := c.Refresh(ctx, c.store.RefreshToken())
+	if err != nil {
+		return err
+	}
+	if resp.Token == "" {
+		return fmt.Errorf("refresh returned no token")
+	}
+
+	c.token = resp.Token
+	refreshToken := resp.RefreshToken
+	if refreshToken == "" {
+		refreshToken = c.store.RefreshToken()
+	}
+	return c.store.UpdateTokens(resp.Token, refreshToken)
+}
+
 func (c *Client) applyHeaders(req *http.Request) {
 	if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
@@ -130,11 +253,12 @@ func checkStatus(res *http.Response) error {
 	var apiErr struct {
 		Error   string `json:"error"`
 		Message string `json:"message"`
+		Code    string `json:"code"`
 	}
 	_ = json.Unmarshal(data, &apiErr)
 
 	if apiErr.Error != "" {
-		return &Error{Code: apiErr.Error, Message: apiErr.Message, Status: res.StatusCode}
+		return &Error{Code: apiErr.Error, Message: apiErr.Message, Reason: apiErr.Code, Status: res.StatusCode}
 	}
 
 	return fmt.Errorf("api error: %s", strings.TrimSpace(string(data)))
@@ -144,7 +268,9 @@ func checkStatus(res *http.Response) error {
 type Error struct {
 	Code    string
 	Message string
-	Status  int
+	// Reason carries the machine-readable code, e.g. TOKEN_EXPIRED.
+	Reason string
+	Status int
 }
 
 // Error implements the error interface.
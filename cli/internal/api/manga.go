@@ -11,6 +11,69 @@ import (
 	"time"
 )
 
+// SearchRequest mirrors the server's manga.SearchRequest query parameters,
+// plus the author, min_chapters and order filters older clients send.
+type SearchRequest struct {
+	Query       string
+	Genres      []string
+	Status      string
+	Author      string
+	MinRating   *float64
+	MaxRating   *float64
+	YearFrom    *int
+	YearTo      *int
+	MinChapters int
+	Page        int
+	Limit       int
+	SortBy      string
+	Order       string
+}
+
+// Values encodes the request as GET /mangas/search query parameters.
+func (r SearchRequest) Values() url.Values {
+	q := url.Values{}
+	if r.Query != "" {
+		q.Set("q", r.Query)
+	}
+	if len(r.Genres) > 0 {
+		q.Set("genres", strings.Join(r.Genres, ","))
+	}
+	if r.Status != "" {
+		q.Set("status", r.Status)
+	}
+	if r.Author != "" {
+		q.Set("author", r.Author)
+	}
+	if r.MinRating != nil {
+		q.Set("min_rating", strconv.FormatFloat(*r.MinRating, 'f', -1, 64))
+	}
+	if r.MaxRating != nil {
+		q.Set("max_rating", strconv.FormatFloat(*r.MaxRating, 'f', -1, 64))
+	}
+	if r.YearFrom != nil {
+		q.Set("year_from", strconv.Itoa(*r.YearFrom))
+	}
+	if r.YearTo != nil {
+		q.Set("year_to", strconv.Itoa(*r.YearTo))
+	}
+	if r.MinChapters > 0 {
+		q.Set("min_chapters", strconv.Itoa(r.MinChapters))
+	}
+	if r.Page > 0 {
+		q.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.Limit > 0 {
+		q.Set("limit", strconv.Itoa(r.Limit))
+	}
+	if r.SortBy != "" {
+		q.Set("sort_by", r.SortBy)
+	}
+	if r.Order != "" {
+		q.Set("order", r.Order)
+	}
+	return q
+}
+
 // MangaSearchFilters captures optional search parameters.
 type MangaSearchFilters struct {
 	Genre       string
@@ -24,6 +87,34 @@ type MangaSearchFilters struct {
 	Limit       int
 }
 
+// Request converts the filters into a SearchRequest for query. Genre may
+// hold several comma-separated genres; zero years are left unset.
+func (f MangaSearchFilters) Request(query string) SearchRequest {
+	req := SearchRequest{
+		Query:       query,
+		Status:      f.Status,
+		Author:      f.Author,
+		MinChapters: f.MinChapters,
+		SortBy:      f.SortBy,
+		Order:       f.Order,
+		Limit:       f.Limit,
+	}
+	for _, g := range strings.Split(f.Genre, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			req.Genres = append(req.Genres, g)
+		}
+	}
+	if f.YearFrom > 0 {
+		yearFrom := f.YearFrom
+		req.YearFrom = &yearFrom
+	}
+	if f.YearTo > 0 {
+		yearTo := f.YearTo
+		req.YearTo = &yearTo
+	}
+	return req
+}
+
 // MangaSearchResult represents a manga search item.
 type MangaSearchResult struct {
 	ID          int64   `json:"id"`
@@ -96,51 +187,18 @@ type MangaListItem struct {
 	Chapters  int      `json:"chapters"`
 }
 
-// SearchManga searches manga titles with filters.
-func (c *Client) SearchManga(ctx context.Context, query string, filters MangaSearchFilters) (*MangaSearchResponse, error) {
-	u, _ := url.Parse(c.baseURL + "/manga/search")
-	q := u.Query()
-	q.Set("q", query)
-	q.Set("genres", filters.Genre)
-	q.Set("status", filters.Status)
-	q.Set("author", filters.Author)
-	if filters.YearFrom > 0 {
-		q.Set("year_from", strconv.Itoa(filters.YearFrom))
-	}
-	if filters.YearTo > 0 {
-		q.Set("year_to", strconv.Itoa(filters.YearTo))
-	}
-	if filters.MinChapters > 0 {
-		q.Set("min_chapters", strconv.Itoa(filters.MinChapters))
-	}
-	q.Set("sort_by", filters.SortBy)
-	q.Set("order", filters.Order)
-	if filters.Limit > 0 {
-		q.Set("limit", strconv.Itoa(filters.Limit))
-	}
-	u.RawQuery = q.Encode()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	c.applyHeaders(req)
-
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if err := checkStatus(res); err != nil {
-		return nil, err
+// SearchManga searches the catalog via GET /mangas/search.
+func (c *Client) SearchManga(ctx context.Context, req SearchRequest) (*MangaSearchResponse, error) {
+	endpoint := "/mangas/search"
+	if q := req.Values().Encode(); q != "" {
+		endpoint += "?" + q
 	}
 
-	var response MangaSearchResponse
-	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+	var resp MangaSearchResponse
+	if err := c.doRequest(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
 		return nil, err
 	}
-	return &response, nil
+	return &resp, nil
 }
 
 // GetMangaInfo retrieves detailed manga information.
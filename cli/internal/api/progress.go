@@ -42,13 +42,18 @@ type UpdateProgressResponse struct {
 	Message      string        `json:"message"`
 	UserProgress *UserProgress `json:"user_progress"`
 	Broadcasted  bool          `json:"broadcasted"`
+	Conflict     bool          `json:"conflict,omitempty"`
 }
 
 // UpdateProgress updates progress for a specific manga via the backend API.
-func (c *Client) UpdateProgress(ctx context.Context, mangaID string, chapter int) (*UpdateProgressResponse, error) {
+// The server keeps the highest chapter unless force is set.
+func (c *Client) UpdateProgress(ctx context.Context, mangaID string, chapter int, force bool) (*UpdateProgressResponse, error) {
 	payload := map[string]any{
 		"current_chapter": chapter,
 	}
+	if force {
+		payload["force"] = true
+	}
 	endpoint := fmt.Sprintf("/manga/%s/progress", url.PathEscape(mangaID))
 	var resp UpdateProgressResponse
 	if err := c.doRequest(ctx, http.MethodPut, endpoint, payload, &resp); err != nil {
@@ -32,6 +32,9 @@ type ResourceStatus struct {
 }
 
 type ServerStatus struct {
+	Version   string          `json:"version"`
+	GitCommit string          `json:"git_commit"`
+	BuildTime string          `json:"build_time"`
 	Overall   string          `json:"overall"`
 	Services  []ServiceStatus `json:"services"`
 	Database  DatabaseStatus  `json:"database"`
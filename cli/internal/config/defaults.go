@@ -72,10 +72,11 @@ type Config struct {
 	Notifications NotificationsConfig `json:"notifications"`
 	Auth          AuthSection         `json:"auth"`
 
-	Token       string         `json:"token"`
-	ExpiresAt   string         `json:"expires_at"`
-	Permissions []string       `json:"permissions"`
-	Settings    LegacySettings `json:"settings"`
+	Token        string         `json:"token"`
+	RefreshToken string         `json:"refresh_token"`
+	ExpiresAt    string         `json:"expires_at"`
+	Permissions  []string       `json:"permissions"`
+	Settings     LegacySettings `json:"settings"`
 
 	BaseURL     string `json:"base_url"`
 	GRPCAddress string `json:"grpc_address"`
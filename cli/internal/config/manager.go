@@ -230,6 +230,21 @@ func (m *Manager) UpdateToken(token string) error {
 	return m.Save()
 }
 
+// RefreshToken returns the stored refresh token for the active profile.
+func (m *Manager) RefreshToken() string {
+	if m == nil {
+		return ""
+	}
+	return m.Data.RefreshToken
+}
+
+// UpdateTokens saves a renewed access and refresh token pair.
+func (m *Manager) UpdateTokens(token, refreshToken string) error {
+	m.Data.Token = token
+	m.Data.RefreshToken = refreshToken
+	return m.Save()
+}
+
 // UpdateSession saves a full authentication session to the configuration file.
 func (m *Manager) UpdateSession(token, expiresAt, username string, permissions []string, autosync, notifications bool) error {
 	m.Data.Token = token
@@ -245,6 +260,7 @@ func (m *Manager) UpdateSession(token, expiresAt, username string, permissions [
 // ClearSession removes authentication-related data from the configuration.
 func (m *Manager) ClearSession() error {
 	m.Data.Token = ""
+	m.Data.RefreshToken = ""
 	m.Data.ExpiresAt = ""
 	m.Data.Auth.Username = ""
 	m.Data.Permissions = nil
@@ -53,6 +53,10 @@ func PrintServerStatusTable(cmd *cobra.Command, st *api.ServerStatus) {
 	cmd.Println()
 
 	cmd.Printf("Overall System Health: %s\n", FormatOverall(st.Overall))
+	// Servers predating /version leave the build fields empty
+	if st.Version != "" {
+		cmd.Printf("Server Version: %s (commit %s, built %s)\n", st.Version, st.GitCommit, st.BuildTime)
+	}
 	cmd.Println()
 
 	if len(st.Issues) > 0 {
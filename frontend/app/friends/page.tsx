@@ -267,6 +267,10 @@ export default function FriendsPage() {
             .map((id) => Number(id))
             .filter((id) => Number.isFinite(id));
           setRawOnlineUserIds(new Set(normalizedIds));
+        } else if (payload.type === "friend:request") {
+          void loadPendingRequests();
+        } else if (payload.type === "friend:accepted") {
+          void loadFriends();
         }
       } catch (err) {
         console.error("Failed to parse presence payload", err);
@@ -311,7 +315,7 @@ export default function FriendsPage() {
       if (reconnectTimeout) clearTimeout(reconnectTimeout);
       socket?.close();
     };
-  }, [token]);
+  }, [token, loadFriends, loadPendingRequests]);
 
   // Filter online ids to friends only to avoid leaking presence
   useEffect(() => {
@@ -25,7 +25,7 @@ export default function MangaDetailPage({ params }: PageProps) {
   const [loading, setLoading] = useState<boolean>(true);
   const [error, setError] = useState<string | null>(null);
   const [reviewComment, setReviewComment] = useState<string>("");
-  const [reviewRating, setReviewRating] = useState<number>(8);
+  const [reviewRating, setReviewRating] = useState<number>(4);
 
   const sortedChapters = useMemo(() => {
     if (!manga?.chapters?.length) return [];
@@ -239,7 +239,7 @@ export default function MangaDetailPage({ params }: PageProps) {
               reviews.reviews.map((review) => (
                 <div key={review.review_id} className="rounded-lg bg-slate-800 p-3">
                   <div className="flex items-center justify-between text-xs text-slate-400">
-                    <span>Rating: {review.rating}/10</span>
+                    <span>Rating: {review.rating}/5</span>
                     <span>{review.username}</span>
                   </div>
                   <p className="mt-1 text-slate-200">{review.content}</p>
@@ -252,13 +252,13 @@ export default function MangaDetailPage({ params }: PageProps) {
           <div className="space-y-2 rounded-lg border border-slate-800 bg-slate-950 p-3">
             <p className="text-sm font-medium text-white">Add a review</p>
             <label className="text-xs text-slate-400" htmlFor="rating">
-              Rating (1-10)
+              Rating (1-5)
             </label>
             <input
               id="rating"
               type="number"
               min={1}
-              max={10}
+              max={5}
               value={reviewRating}
               onChange={(e) => setReviewRating(Number(e.target.value))}
             />
@@ -65,6 +65,17 @@ export interface LibraryEntry {
 
 export interface GetLibraryResponse {
   entries: LibraryEntry[];
+  total: number;
+  page?: number;
+  limit?: number;
+  status_counts: Record<string, number>;
+}
+
+export interface LibraryQuery {
+  status?: string;
+  sort?: "last_updated" | "title" | "rating";
+  page?: number;
+  limit?: number;
 }
 
 export interface ChapterDetail {
@@ -163,8 +174,8 @@ export async function createReview(id: number, payload: CreateReviewRequest): Pr
   return data;
 }
 
-export async function getLibrary(): Promise<GetLibraryResponse> {
-  const { data } = await http.get<GetLibraryResponse>("/library");
+export async function getLibrary(params: LibraryQuery = {}): Promise<GetLibraryResponse> {
+  const { data } = await http.get<GetLibraryResponse>("/library", { params });
   return data;
 }
 